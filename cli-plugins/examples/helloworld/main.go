@@ -45,9 +45,12 @@ func main() {
 		}
 
 		var (
-			who, context string
-			debug        bool
+			who, context, greeting string
+			debug                  bool
 		)
+		// This is a global option of the plugin, which can be set before
+		// its name: "docker --greeting Howdy helloworld".
+		plugin.PersistentFlags().StringVar(&greeting, "greeting", "Hello", "Greeting of the plugin")
 		cmd := &cobra.Command{
 			Use:   "helloworld",
 			Short: "A basic Hello World plugin for tests",
@@ -71,7 +74,7 @@ func main() {
 					who = "World"
 				}
 
-				fmt.Fprintf(dockerCli.Out(), "Hello %s!\n", who)
+				fmt.Fprintf(dockerCli.Out(), "%s %s!\n", greeting, who)
 				dockerCli.ConfigFile().SetPluginConfig("helloworld", "lastwho", who)
 				return dockerCli.ConfigFile().Save()
 			},
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +27,11 @@ const (
 	// is, one which failed it's candidate test) and contains the
 	// reason for the failure.
 	CommandAnnotationPluginInvalid = "com.docker.cli.plugin-invalid"
+
+	// CommandAnnotationPluginName is added to the stub commands added
+	// by AddPluginCommandStubs for the subcommands of builtin command
+	// groups provided by plugins, and contains the name of the plugin.
+	CommandAnnotationPluginName = "com.docker.cli.plugin.name"
 )
 
 // AddPluginCommandStubs adds a stub cobra.Commands for each valid and invalid
@@ -56,5 +62,27 @@ func AddPluginCommandStubs(dockerCli command.Cli, cmd *cobra.Command) error {
 			Annotations: annotations,
 		})
 	}
+
+	subcommands, errs := resolvePluginSubcommands(plugins, cmd)
+	for _, err := range errs {
+		logrus.Debug(err)
+	}
+	for _, s := range subcommands {
+		group, _, err := cmd.Find(s.Path[:len(s.Path)-1])
+		if err != nil {
+			continue
+		}
+		group.AddCommand(&cobra.Command{
+			Use:   s.Name(),
+			Short: s.Plugin.ShortDescription,
+			Run:   func(_ *cobra.Command, _ []string) {},
+			Annotations: map[string]string{
+				CommandAnnotationPlugin:        "true",
+				CommandAnnotationPluginVendor:  s.Plugin.Vendor,
+				CommandAnnotationPluginVersion: s.Plugin.Version,
+				CommandAnnotationPluginName:    s.Plugin.Name,
+			},
+		})
+	}
 	return nil
 }
@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"encoding/json"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CommandsFlagName is the name of the flag of the metadata subcommand which
+// adds the description of the commands of the plugin to its metadata.
+const CommandsFlagName = "commands"
+
+// CommandMetadata is the description of a command of a plugin, and of its
+// subcommands, such as to generate the documentation and the completion of
+// the plugin.
+type CommandMetadata struct {
+	// Name is the name of the command.
+	Name string
+	// Path is the path of the command, such as "docker helloworld goodbye".
+	Path       string
+	Use        string
+	Aliases    []string `json:",omitempty"`
+	Short      string   `json:",omitempty"`
+	Long       string   `json:",omitempty"`
+	Example    string   `json:",omitempty"`
+	Deprecated string   `json:",omitempty"`
+	// Flags are the flags of the command, including the persistent flags
+	// it defines, but not those it inherits from its parents.
+	Flags []FlagMetadata `json:",omitempty"`
+	// Commands are the subcommands of the command, which are not hidden.
+	Commands []CommandMetadata `json:",omitempty"`
+}
+
+// FlagMetadata is the description of a flag of a command of a plugin.
+type FlagMetadata struct {
+	// Name is the name of the flag, without the leading "--".
+	Name      string
+	Shorthand string `json:",omitempty"`
+	// Type is the type of the value of the flag, such as "string", or
+	// "bool".
+	Type       string `json:",omitempty"`
+	Default    string `json:",omitempty"`
+	Usage      string `json:",omitempty"`
+	Deprecated string `json:",omitempty"`
+	// Persistent is true if the flag is inherited by the subcommands.
+	Persistent bool `json:",omitempty"`
+}
+
+// NewCommandMetadata returns the description of a command, and of its
+// subcommands which are not hidden. The hidden flags are skipped.
+func NewCommandMetadata(cmd *cobra.Command) CommandMetadata {
+	meta := CommandMetadata{
+		Name:       cmd.Name(),
+		Path:       cmd.CommandPath(),
+		Use:        cmd.Use,
+		Aliases:    cmd.Aliases,
+		Short:      cmd.Short,
+		Long:       cmd.Long,
+		Example:    cmd.Example,
+		Deprecated: cmd.Deprecated,
+	}
+	persistent := cmd.PersistentFlags()
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		meta.Flags = append(meta.Flags, FlagMetadata{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Deprecated: f.Deprecated,
+			Persistent: persistent.Lookup(f.Name) != nil,
+		})
+	})
+	for _, sub := range cmd.Commands() {
+		if sub.Hidden || sub.Name() == "help" {
+			continue
+		}
+		meta.Commands = append(meta.Commands, NewCommandMetadata(sub))
+	}
+	return meta
+}
+
+// CommandMetadata returns the description of the commands of the plugin, by
+// running its metadata subcommand with the --commands flag.
+func (p *Plugin) CommandMetadata() (*CommandMetadata, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	out, err := exec.Command(p.Path, MetadataSubcommandName, "--"+CommandsFlagName).Output()
+	if err != nil {
+		return nil, wrapAsPluginError(err, "failed to fetch the description of the commands")
+	}
+	var meta Metadata
+	if err := json.Unmarshal(out, &meta); err != nil {
+		return nil, wrapAsPluginError(err, "invalid metadata")
+	}
+	if meta.Commands == nil {
+		return nil, NewPluginError("plugin %q does not describe its commands", p.Name)
+	}
+	return meta.Commands, nil
+}
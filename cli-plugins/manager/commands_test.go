@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+)
+
+func TestNewCommandMetadata(t *testing.T) {
+	root := &cobra.Command{Use: "helloworld", Short: "A basic Hello World plugin for tests"}
+	root.PersistentFlags().Bool("debug", false, "Enable debug")
+	root.Flags().StringP("who", "w", "World", "Who are we addressing?")
+	root.Flags().String("secret", "", "")
+	root.Flags().MarkHidden("secret")
+
+	goodbye := &cobra.Command{Use: "goodbye [NAME]", Aliases: []string{"bye"}, Short: "Say Goodbye instead of Hello", Run: func(*cobra.Command, []string) {}}
+	goodbye.Flags().Int("times", 1, "Number of goodbyes")
+	hidden := &cobra.Command{Use: "internal", Hidden: true, Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(goodbye, hidden)
+
+	assert.DeepEqual(t, NewCommandMetadata(root), CommandMetadata{
+		Name:  "helloworld",
+		Path:  "helloworld",
+		Use:   "helloworld",
+		Short: "A basic Hello World plugin for tests",
+		Flags: []FlagMetadata{
+			{Name: "debug", Type: "bool", Default: "false", Usage: "Enable debug", Persistent: true},
+			{Name: "who", Shorthand: "w", Type: "string", Default: "World", Usage: "Who are we addressing?"},
+		},
+		Commands: []CommandMetadata{
+			{
+				Name:    "goodbye",
+				Path:    "helloworld goodbye",
+				Use:     "goodbye [NAME]",
+				Aliases: []string{"bye"},
+				Short:   "Say Goodbye instead of Hello",
+				Flags: []FlagMetadata{
+					{Name: "times", Type: "int", Default: "1", Usage: "Number of goodbyes"},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewGlobalFlags returns the global options of the metadata of a plugin for
+// the flags of a flag set. The shorthands of the flags are not supported by
+// the CLI.
+func NewGlobalFlags(flags *pflag.FlagSet) []GlobalFlag {
+	var globalFlags []GlobalFlag
+	flags.VisitAll(func(f *pflag.Flag) {
+		globalFlags = append(globalFlags, GlobalFlag{
+			Name:  f.Name,
+			Bool:  f.NoOptDefVal != "",
+			Usage: f.Usage,
+		})
+	})
+	return globalFlags
+}
+
+// ListPluginGlobalFlags returns the global options added by the valid
+// plugins, by plugin name. The values of the flags are discarded, as the
+// options are parsed by the plugins.
+func ListPluginGlobalFlags(dockerCli command.Cli, rootcmd *cobra.Command) (map[string]*pflag.FlagSet, error) {
+	plugins, err := ListPlugins(dockerCli, rootcmd)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]*pflag.FlagSet{}
+	for _, p := range plugins {
+		if p.Err != nil || len(p.GlobalFlags) == 0 {
+			continue
+		}
+		flags := pflag.NewFlagSet(p.Name, pflag.ContinueOnError)
+		for _, f := range p.GlobalFlags {
+			if f.Name == "" || flags.Lookup(f.Name) != nil {
+				continue
+			}
+			if f.Bool {
+				flags.Bool(f.Name, false, f.Usage)
+			} else {
+				flags.String(f.Name, "", f.Usage)
+			}
+			flags.Lookup(f.Name).Hidden = true
+		}
+		res[p.Name] = flags
+	}
+	return res, nil
+}
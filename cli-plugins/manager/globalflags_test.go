@@ -0,0 +1,19 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+)
+
+func TestNewGlobalFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("plugin", pflag.ContinueOnError)
+	flags.String("greeting", "Hello", "Greeting of the plugin")
+	flags.Bool("verbose", false, "Print more output")
+
+	assert.DeepEqual(t, NewGlobalFlags(flags), []GlobalFlag{
+		{Name: "greeting", Usage: "Greeting of the plugin"},
+		{Name: "verbose", Bool: true, Usage: "Print more output"},
+	})
+}
@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"encoding/json"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+)
+
+// InvocationContextEnvvar is the name of an envvar which is set to the
+// InvocationContext of the CLI, as JSON, when executing a plugin.
+const InvocationContextEnvvar = "DOCKER_CLI_PLUGIN_INVOCATION_CONTEXT"
+
+// InvocationContext describes the configuration of the CLI which invoked a
+// plugin, such that the plugin does not need to parse the global options
+// itself.
+type InvocationContext struct {
+	// Context is the name of the current context.
+	Context string
+	// ConfigDir is the directory of the configuration files.
+	ConfigDir string
+	// APIVersion is the API version the client is configured with.
+	APIVersion string `json:",omitempty"`
+	// Endpoint is the Docker endpoint of the current context.
+	Endpoint InvocationEndpoint
+	// GlobalArgs are the global options the CLI was invoked with, that is
+	// the args up to, but not including, the name of the plugin.
+	GlobalArgs []string
+	// Subcommand is the path of the subcommand of a builtin command group
+	// the plugin was invoked as, such as "image sign", if any. It is empty
+	// if the plugin was invoked as its own command.
+	Subcommand string `json:",omitempty"`
+}
+
+// InvocationEndpoint describes the Docker endpoint of the CLI which invoked a
+// plugin.
+type InvocationEndpoint struct {
+	Host          string `json:",omitempty"`
+	TLS           bool
+	SkipTLSVerify bool
+}
+
+// NewInvocationContext returns the InvocationContext of the plugins invoked
+// by the CLI with the given global options. The CLI must be initialized.
+func NewInvocationContext(dockerCli command.Cli, globalArgs []string) InvocationContext {
+	endpoint := dockerCli.DockerEndpoint()
+	return InvocationContext{
+		Context:    dockerCli.CurrentContext(),
+		ConfigDir:  config.Dir(),
+		APIVersion: dockerCli.DefaultVersion(),
+		Endpoint: InvocationEndpoint{
+			Host:          endpoint.Host,
+			TLS:           endpoint.TLSData != nil,
+			SkipTLSVerify: endpoint.SkipTLSVerify,
+		},
+		GlobalArgs: globalArgs,
+	}
+}
+
+// Encode returns the value of InvocationContextEnvvar for the
+// InvocationContext.
+func (c InvocationContext) Encode() (string, error) {
+	b, err := json.Marshal(c)
+	return string(b), err
+}
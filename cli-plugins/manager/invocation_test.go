@@ -0,0 +1,25 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewInvocationContext(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetCurrentContext("remote")
+	cli.SetDockerEndpoint(docker.Endpoint{EndpointMeta: docker.EndpointMeta{Host: "tcp://remote:2376", SkipTLSVerify: true}})
+
+	invocation := NewInvocationContext(cli, []string{"--context", "remote"})
+	assert.Check(t, is.Equal("remote", invocation.Context))
+	assert.Check(t, is.DeepEqual(InvocationEndpoint{Host: "tcp://remote:2376", SkipTLSVerify: true}, invocation.Endpoint))
+	assert.Check(t, is.DeepEqual([]string{"--context", "remote"}, invocation.GlobalArgs))
+
+	value, err := invocation.Encode()
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(value, `"Endpoint":{"Host":"tcp://remote:2376","TLS":false,"SkipTLSVerify":true},"GlobalArgs":["--context","remote"]`))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +39,11 @@ func IsNotFound(err error) bool {
 func getPluginDirs(dockerCli command.Cli) ([]string, error) {
 	var pluginDirs []string
 
+	// The per-project plugins have precedence, so that they can pin the
+	// versions of the plugins of a project
+	if dir, trusted, _ := getProjectPluginDir(dockerCli); trusted {
+		pluginDirs = append(pluginDirs, dir)
+	}
 	if cfg := dockerCli.ConfigFile(); cfg != nil {
 		pluginDirs = append(pluginDirs, cfg.CLIPluginsExtraDirs...)
 	}
@@ -48,7 +54,7 @@ func getPluginDirs(dockerCli command.Cli) ([]string, error) {
 
 	pluginDirs = append(pluginDirs, pluginDir)
 	pluginDirs = append(pluginDirs, defaultSystemPluginDirs...)
-	return pluginDirs, nil
+	return filterPluginDirs(pluginDirs, getPolicy(dockerCli)), nil
 }
 
 func addPluginCandidatesFromDir(res map[string][]string, d string) error {
@@ -111,11 +117,18 @@ func ListPlugins(dockerCli command.Cli, rootcmd *cobra.Command) ([]Plugin, error
 		return nil, err
 	}
 
+	policy := getPolicy(dockerCli)
 	var plugins []Plugin
-	for _, paths := range candidates {
+	for name, paths := range candidates {
 		if len(paths) == 0 {
 			continue
 		}
+		// Plugins which do not conform to the policy are not run, not
+		// even to get their metadata.
+		if err := checkPolicy(policy, name, paths[0]); err != nil {
+			logrus.Debug(err)
+			continue
+		}
 		c := &candidate{paths[0]}
 		p, err := newPlugin(c, rootcmd)
 		if err != nil {
@@ -142,6 +155,7 @@ func PluginRunCommand(dockerCli command.Cli, name string, rootcmd *cobra.Command
 		return nil, errPluginNotFound(name)
 	}
 	exename := addExeSuffix(NamePrefix + name)
+	trustProjectPluginDir(dockerCli, name)
 	pluginDirs, err := getPluginDirs(dockerCli)
 	if err != nil {
 		return nil, err
@@ -158,6 +172,10 @@ func PluginRunCommand(dockerCli command.Cli, name string, rootcmd *cobra.Command
 			continue
 		}
 
+		if err := checkPolicy(getPolicy(dockerCli), name, path); err != nil {
+			return nil, err
+		}
+
 		c := &candidate{path: path}
 		plugin, err := newPlugin(c, rootcmd)
 		if err != nil {
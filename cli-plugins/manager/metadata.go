@@ -22,4 +22,22 @@ type Metadata struct {
 	ShortDescription string `json:",omitempty"`
 	// URL is a pointer to the plugin's homepage.
 	URL string `json:",omitempty"`
+	// Subcommands are the subcommands the plugin adds to builtin command
+	// groups, as command paths such as "image sign".
+	Subcommands []string `json:",omitempty"`
+	// GlobalFlags are the global options the plugin adds to those of the
+	// CLI, which can be set before the name of the plugin.
+	GlobalFlags []GlobalFlag `json:",omitempty"`
+	// Commands is the description of the commands of the plugin. It is
+	// only set with the --commands flag of the metadata subcommand.
+	Commands *CommandMetadata `json:",omitempty"`
+}
+
+// GlobalFlag is a global option added by a plugin.
+type GlobalFlag struct {
+	// Name is the name of the option, without the leading "--".
+	Name string
+	// Bool is true if the option has no value, such as "--verbose".
+	Bool  bool   `json:",omitempty"`
+	Usage string `json:",omitempty"`
 }
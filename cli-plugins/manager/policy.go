@@ -0,0 +1,94 @@
+package manager
+
+import (
+	_ "crypto/sha256" // required by digest.Parse
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// getPolicy returns the CLI plugins policy of the configuration file, or nil
+// if there is no policy or the policy is ignored.
+func getPolicy(dockerCli command.Cli) *configfile.CLIPluginsPolicy {
+	if dockerCli.ClientInfo().IgnoreCLIPluginsPolicy {
+		return nil
+	}
+	if cfg := dockerCli.ConfigFile(); cfg != nil {
+		return cfg.CLIPluginsPolicy
+	}
+	return nil
+}
+
+// filterPluginDirs returns the plugin directories allowed by the policy.
+func filterPluginDirs(dirs []string, policy *configfile.CLIPluginsPolicy) []string {
+	if policy == nil || len(policy.Dirs) == 0 {
+		return dirs
+	}
+	allowed := map[string]bool{}
+	for _, d := range policy.Dirs {
+		allowed[filepath.Clean(d)] = true
+	}
+	var res []string
+	for _, d := range dirs {
+		if allowed[filepath.Clean(d)] {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// checkPolicy returns an error if the plugin with the given name and path
+// does not conform to the policy. The plugin directories are checked by
+// filterPluginDirs.
+func checkPolicy(policy *configfile.CLIPluginsPolicy, name, path string) error {
+	if policy == nil {
+		return nil
+	}
+	for _, n := range policy.Denied {
+		if n == name {
+			return errors.Errorf("plugin %q is denied by the CLI plugins policy", name)
+		}
+	}
+	if len(policy.Allowed) > 0 {
+		allowed := false
+		for _, n := range policy.Allowed {
+			if n == name {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return errors.Errorf("plugin %q is not allowed by the CLI plugins policy", name)
+		}
+	}
+	if expected, ok := policy.Digests[name]; ok {
+		if err := verifyDigest(path, expected); err != nil {
+			return errors.Wrapf(err, "plugin %q does not conform to the CLI plugins policy", name)
+		}
+	}
+	return nil
+}
+
+func verifyDigest(path, expected string) error {
+	dgst, err := digest.Parse(expected)
+	if err != nil {
+		return errors.Wrap(err, "invalid digest")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	actual, err := dgst.Algorithm().FromReader(f)
+	if err != nil {
+		return err
+	}
+	if actual != dgst {
+		return errors.Errorf("binary %s has digest %s, expected %s", path, actual, dgst)
+	}
+	return nil
+}
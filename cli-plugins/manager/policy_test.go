@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestFilterPluginDirs(t *testing.T) {
+	dirs := []string{"/home/user/.docker/cli-plugins", "/usr/local/lib/docker/cli-plugins", "/usr/lib/docker/cli-plugins"}
+	assert.Check(t, is.DeepEqual(dirs, filterPluginDirs(dirs, nil)))
+	assert.Check(t, is.DeepEqual(dirs, filterPluginDirs(dirs, &configfile.CLIPluginsPolicy{})))
+
+	policy := &configfile.CLIPluginsPolicy{Dirs: []string{"/usr/lib/docker/cli-plugins/", "/opt/docker/cli-plugins"}}
+	assert.Check(t, is.DeepEqual([]string{"/usr/lib/docker/cli-plugins"}, filterPluginDirs(dirs, policy)))
+}
+
+func TestCheckPolicy(t *testing.T) {
+	dir := fs.NewDir(t, "policy", fs.WithFile("docker-signer", "#!/bin/sh\n"))
+	defer dir.Remove()
+	path := dir.Join("docker-signer")
+	const validDigest = "sha256:a8076d3d28d21e02012b20eaf7dbf75409a6277134439025f282e368e3305abf"
+
+	testCases := []struct {
+		doc         string
+		policy      *configfile.CLIPluginsPolicy
+		expectedErr string
+	}{
+		{doc: "no policy"},
+		{doc: "allowed", policy: &configfile.CLIPluginsPolicy{Allowed: []string{"other", "signer"}}},
+		{
+			doc:         "not allowed",
+			policy:      &configfile.CLIPluginsPolicy{Allowed: []string{"other"}},
+			expectedErr: `plugin "signer" is not allowed by the CLI plugins policy`,
+		},
+		{
+			doc:         "denied",
+			policy:      &configfile.CLIPluginsPolicy{Allowed: []string{"signer"}, Denied: []string{"signer"}},
+			expectedErr: `plugin "signer" is denied by the CLI plugins policy`,
+		},
+		{doc: "valid digest", policy: &configfile.CLIPluginsPolicy{Digests: map[string]string{"signer": validDigest}}},
+		{doc: "digest of other plugin", policy: &configfile.CLIPluginsPolicy{Digests: map[string]string{"other": "sha256:0"}}},
+		{
+			doc:         "invalid digest",
+			policy:      &configfile.CLIPluginsPolicy{Digests: map[string]string{"signer": "sha256:0"}},
+			expectedErr: `plugin "signer" does not conform to the CLI plugins policy: invalid digest: invalid checksum digest length`,
+		},
+		{
+			doc:         "digest mismatch",
+			policy:      &configfile.CLIPluginsPolicy{Digests: map[string]string{"signer": "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}},
+			expectedErr: "plugin \"signer\" does not conform to the CLI plugins policy: binary " + path + " has digest " + validDigest + ", expected sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+	}
+	for _, tc := range testCases {
+		err := checkPolicy(tc.policy, "signer", path)
+		if tc.expectedErr == "" {
+			assert.Check(t, err, tc.doc)
+			continue
+		}
+		assert.Check(t, is.Error(err, tc.expectedErr), tc.doc)
+	}
+}
+
+func TestGetPolicy(t *testing.T) {
+	policy := &configfile.CLIPluginsPolicy{Allowed: []string{"signer"}}
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(&configfile.ConfigFile{CLIPluginsPolicy: policy})
+	assert.Check(t, is.Equal(policy, getPolicy(cli)))
+
+	cli.SetClientInfo(func() command.ClientInfo { return command.ClientInfo{IgnoreCLIPluginsPolicy: true} })
+	assert.Check(t, getPolicy(cli) == nil)
+}
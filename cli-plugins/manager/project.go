@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/homedir"
+	"github.com/sirupsen/logrus"
+)
+
+// projectPluginDir is the per-project plugin directory, relative to the
+// directory of a project
+var projectPluginDir = filepath.Join(".docker", "cli-plugins")
+
+// findProjectPluginDir returns the per-project plugin directory nearest to
+// dir, walking up to the root directory, or an empty string if there is none.
+// The plugin directory of the user, such as "~/.docker/cli-plugins", is not a
+// per-project plugin directory.
+func findProjectPluginDir(dir string) string {
+	userDirs := map[string]bool{
+		filepath.Join(homedir.Get(), projectPluginDir): true,
+	}
+	if d, err := config.Path("cli-plugins"); err == nil {
+		userDirs[d] = true
+	}
+	for {
+		d := filepath.Join(dir, projectPluginDir)
+		if fi, err := os.Stat(d); err == nil && fi.IsDir() && !userDirs[d] {
+			return d
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// getProjectPluginDir returns the per-project plugin directory of the current
+// directory, if any, whether it is trusted, and whether its trust has been
+// recorded in the configuration file.
+func getProjectPluginDir(dockerCli command.Cli) (dir string, trusted, recorded bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", false, false
+	}
+	dir = findProjectPluginDir(cwd)
+	if dir == "" {
+		return "", false, false
+	}
+	if cfg := dockerCli.ConfigFile(); cfg != nil {
+		trusted, recorded = cfg.CLIPluginsProjectDirs[dir]
+	}
+	return dir, trusted, recorded
+}
+
+// trustProjectPluginDir prompts whether to trust the per-project plugin
+// directory of the current directory, if it has the named plugin and its
+// trust has not been recorded yet, and records the answer in the
+// configuration file. The user is only prompted if the input is a terminal.
+func trustProjectPluginDir(dockerCli command.Cli, name string) {
+	dir, _, recorded := getProjectPluginDir(dockerCli)
+	if dir == "" || recorded {
+		return
+	}
+	if _, err := os.Stat(filepath.Join(dir, addExeSuffix(NamePrefix+name))); err != nil {
+		return
+	}
+	if dockerCli.ConfigFile() == nil || !dockerCli.In().IsTerminal() {
+		logrus.Debugf("ignoring the CLI plugins of the per-project plugin directory %s, which is not trusted", dir)
+		return
+	}
+
+	message := fmt.Sprintf("The project directory %s has CLI plugins, which run with your privileges. Do you trust them?", dir)
+	trusted := command.PromptForConfirmation(dockerCli.In(), dockerCli.Err(), message)
+	cfg := dockerCli.ConfigFile()
+	if cfg.CLIPluginsProjectDirs == nil {
+		cfg.CLIPluginsProjectDirs = map[string]bool{}
+	}
+	cfg.CLIPluginsProjectDirs[dir] = trusted
+	if err := cfg.Save(); err != nil {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: failed to record the trust of the per-project plugin directory %s: %v\n", dir, err)
+	}
+}
@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+	"gotest.tools/skip"
+)
+
+// newProjectDir returns a project directory with a per-project plugin
+// directory with the plugin, and the path of the directory, with its symbolic
+// links resolved as by os.Getwd
+func newProjectDir(t *testing.T) (*fs.Dir, string) {
+	t.Helper()
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithDir(".docker",
+			fs.WithDir("cli-plugins", fs.WithFile(addExeSuffix("docker-plugin"), "")),
+		),
+		fs.WithDir("src", fs.WithDir("cmd")),
+	)
+	path, err := filepath.EvalSymlinks(dir.Path())
+	assert.NilError(t, err)
+	return dir, path
+}
+
+// chdir changes the current directory, and returns a function restoring it
+func chdir(t *testing.T, dir string) func() {
+	t.Helper()
+	cwd, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(dir))
+	return func() {
+		assert.NilError(t, os.Chdir(cwd))
+	}
+}
+
+func TestFindProjectPluginDir(t *testing.T) {
+	dir, path := newProjectDir(t)
+	defer dir.Remove()
+
+	expected := filepath.Join(path, ".docker", "cli-plugins")
+	assert.Check(t, is.Equal(findProjectPluginDir(path), expected))
+	assert.Check(t, is.Equal(findProjectPluginDir(filepath.Join(path, "src", "cmd")), expected))
+	assert.Check(t, is.Equal(findProjectPluginDir(filepath.Dir(path)), ""))
+}
+
+func TestGetPluginDirsProject(t *testing.T) {
+	dir, path := newProjectDir(t)
+	defer dir.Remove()
+	defer chdir(t, filepath.Join(path, "src"))()
+	projectDir := filepath.Join(path, ".docker", "cli-plugins")
+
+	cli := test.NewFakeCli(nil)
+	pluginDirs, err := getPluginDirs(cli)
+	assert.NilError(t, err)
+	assert.Check(t, pluginDirs[0] != projectDir)
+
+	cli.SetConfigFile(&configfile.ConfigFile{
+		CLIPluginsProjectDirs: map[string]bool{projectDir: false},
+	})
+	pluginDirs, err = getPluginDirs(cli)
+	assert.NilError(t, err)
+	assert.Check(t, pluginDirs[0] != projectDir)
+
+	cli.SetConfigFile(&configfile.ConfigFile{
+		CLIPluginsProjectDirs: map[string]bool{projectDir: true},
+		CLIPluginsExtraDirs:   []string{"foo"},
+	})
+	pluginDirs, err = getPluginDirs(cli)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pluginDirs[:2], []string{projectDir, "foo"}))
+}
+
+func TestTrustProjectPluginDir(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "the prompt reads the standard input of the process on Windows")
+	dir, path := newProjectDir(t)
+	defer dir.Remove()
+	defer chdir(t, path)()
+	projectDir := filepath.Join(path, ".docker", "cli-plugins")
+
+	testCases := []struct {
+		name     string
+		input    string
+		terminal bool
+		plugin   string
+		expected map[string]bool
+	}{
+		{
+			name:     "trusted",
+			input:    "y\n",
+			terminal: true,
+			plugin:   "plugin",
+			expected: map[string]bool{projectDir: true},
+		},
+		{
+			name:     "not-trusted",
+			input:    "n\n",
+			terminal: true,
+			plugin:   "plugin",
+			expected: map[string]bool{projectDir: false},
+		},
+		{
+			name:   "not-a-terminal",
+			input:  "y\n",
+			plugin: "plugin",
+		},
+		{
+			name:     "other-plugin",
+			input:    "y\n",
+			terminal: true,
+			plugin:   "other",
+		},
+	}
+	for _, tc := range testCases {
+		configDir := fs.NewDir(t, "config")
+		defer configDir.Remove()
+		cli := test.NewFakeCli(nil)
+		cli.SetConfigFile(configfile.New(configDir.Join("config.json")))
+		cli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader(tc.input))))
+		cli.In().SetIsTerminal(tc.terminal)
+
+		trustProjectPluginDir(cli, tc.plugin)
+		assert.Check(t, is.DeepEqual(cli.ConfigFile().CLIPluginsProjectDirs, tc.expected), tc.name)
+		if tc.expected != nil {
+			assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Do you trust them? [y/N]"), tc.name)
+			saved, err := ioutil.ReadFile(configDir.Join("config.json"))
+			assert.NilError(t, err)
+			assert.Check(t, is.Contains(string(saved), "cliPluginsProjectDirs"), tc.name)
+		}
+	}
+}
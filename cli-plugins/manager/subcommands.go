@@ -0,0 +1,137 @@
+package manager
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// PluginSubcommand is a subcommand added by a plugin to a builtin command
+// group, such as "image sign".
+type PluginSubcommand struct {
+	// Path is the path of the subcommand, without the name of the root
+	// command
+	Path   []string
+	Plugin Plugin
+}
+
+// Name returns the name of the subcommand
+func (s PluginSubcommand) Name() string {
+	return s.Path[len(s.Path)-1]
+}
+
+// InvocationContext returns the InvocationContext of the plugin invoked as the
+// subcommand, so that a plugin adding several subcommands knows which one was
+// invoked
+func (s PluginSubcommand) InvocationContext(dockerCli command.Cli, globalArgs []string) InvocationContext {
+	invocation := NewInvocationContext(dockerCli, globalArgs)
+	invocation.Subcommand = strings.Join(s.Path, " ")
+	return invocation
+}
+
+// ListPluginSubcommands returns the subcommands added by the plugins to the
+// builtin command groups. The subcommands which cannot be added are logged,
+// and ignored.
+func ListPluginSubcommands(dockerCli command.Cli, rootcmd *cobra.Command) ([]PluginSubcommand, error) {
+	plugins, err := ListPlugins(dockerCli, rootcmd)
+	if err != nil {
+		return nil, err
+	}
+	subcommands, errs := resolvePluginSubcommands(plugins, rootcmd)
+	for _, err := range errs {
+		logrus.Debug(err)
+	}
+	return subcommands, nil
+}
+
+// FindPluginSubcommand returns the plugin providing the subcommand of a
+// builtin command group with the given path, such as ["image", "sign"]. The
+// error returned satisfies the IsNotFound() predicate if no plugin provides
+// the subcommand.
+func FindPluginSubcommand(dockerCli command.Cli, rootcmd *cobra.Command, path []string) (PluginSubcommand, error) {
+	subcommands, err := ListPluginSubcommands(dockerCli, rootcmd)
+	if err != nil {
+		return PluginSubcommand{}, err
+	}
+	for _, s := range subcommands {
+		if strings.Join(s.Path, " ") == strings.Join(path, " ") {
+			return s, nil
+		}
+	}
+	return PluginSubcommand{}, errPluginNotFound(strings.Join(path, " "))
+}
+
+// resolvePluginSubcommands returns the subcommands added by the valid plugins,
+// and the errors for the subcommands which cannot be added:
+//   - subcommands can only be added to builtin commands having subcommands
+//   - builtin subcommands, and their aliases, take precedence over the
+//     subcommands of plugins
+//   - if several plugins add the same subcommand, the plugin whose name sorts
+//     first takes precedence
+func resolvePluginSubcommands(plugins []Plugin, rootcmd *cobra.Command) ([]PluginSubcommand, []error) {
+	sorted := append([]Plugin{}, plugins...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var (
+		subcommands []PluginSubcommand
+		errs        []error
+		owners      = map[string]string{}
+	)
+	for _, p := range sorted {
+		if p.Err != nil {
+			continue
+		}
+		for _, s := range p.Subcommands {
+			path := strings.Fields(s)
+			if err := validateSubcommand(path, rootcmd); err != nil {
+				errs = append(errs, errors.Wrapf(err, "plugin %q cannot add subcommand %q", p.Name, s))
+				continue
+			}
+			key := strings.Join(path, " ")
+			if owner, ok := owners[key]; ok {
+				errs = append(errs, errors.Errorf("plugin %q cannot add subcommand %q: already added by plugin %q", p.Name, key, owner))
+				continue
+			}
+			owners[key] = p.Name
+			subcommands = append(subcommands, PluginSubcommand{Path: path, Plugin: p})
+		}
+	}
+	return subcommands, errs
+}
+
+func validateSubcommand(path []string, rootcmd *cobra.Command) error {
+	if len(path) < 2 {
+		return errors.New("subcommands must be added to a command group, such as \"image sign\"")
+	}
+	name := path[len(path)-1]
+	if !pluginNameRe.MatchString(name) {
+		return errors.Errorf("subcommand name %q did not match %q", name, pluginNameRe.String())
+	}
+	group := rootcmd
+	for _, n := range path[:len(path)-1] {
+		var next *cobra.Command
+		for _, c := range group.Commands() {
+			if c.Name() == n && c.Annotations[CommandAnnotationPlugin] != "true" {
+				next = c
+				break
+			}
+		}
+		if next == nil || !next.HasSubCommands() {
+			return errors.Errorf("%q is not a builtin command group", strings.Join(path[:len(path)-1], " "))
+		}
+		group = next
+	}
+	for _, c := range group.Commands() {
+		if c.Annotations[CommandAnnotationPlugin] == "true" {
+			continue
+		}
+		if c.Name() == name || c.HasAlias(name) {
+			return errors.Errorf("it duplicates builtin command %q", c.CommandPath())
+		}
+	}
+	return nil
+}
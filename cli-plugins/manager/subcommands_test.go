@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newSubcommandsTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	image := &cobra.Command{Use: "image"}
+	image.AddCommand(&cobra.Command{Use: "ls", Aliases: []string{"list"}, Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(image, &cobra.Command{Use: "run", Run: func(*cobra.Command, []string) {}})
+	return root
+}
+
+func TestValidateSubcommand(t *testing.T) {
+	root := newSubcommandsTestRoot()
+	testCases := []struct {
+		path        []string
+		expectedErr string
+	}{
+		{path: []string{"image", "sign"}},
+		{path: []string{"sign"}, expectedErr: `subcommands must be added to a command group, such as "image sign"`},
+		{path: []string{"image", "Sign"}, expectedErr: `subcommand name "Sign" did not match "^[a-z][a-z0-9]*$"`},
+		{path: []string{"image", "ls"}, expectedErr: `it duplicates builtin command "docker image ls"`},
+		{path: []string{"image", "list"}, expectedErr: `it duplicates builtin command "docker image ls"`},
+		{path: []string{"run", "sign"}, expectedErr: `"run" is not a builtin command group`},
+		{path: []string{"volume", "sign"}, expectedErr: `"volume" is not a builtin command group`},
+	}
+	for _, tc := range testCases {
+		err := validateSubcommand(tc.path, root)
+		if tc.expectedErr == "" {
+			assert.Check(t, err, tc.path)
+			continue
+		}
+		assert.Check(t, is.Error(err, tc.expectedErr), tc.path)
+	}
+}
+
+func TestResolvePluginSubcommands(t *testing.T) {
+	root := newSubcommandsTestRoot()
+	plugins := []Plugin{
+		{Name: "zsign", Metadata: Metadata{Subcommands: []string{"image sign"}}},
+		{Name: "asign", Metadata: Metadata{Subcommands: []string{"image  sign", "image ls"}}},
+		{Name: "broken", Metadata: Metadata{Subcommands: []string{"image broken"}}, Err: errors.New("invalid")},
+	}
+	subcommands, errs := resolvePluginSubcommands(plugins, root)
+	assert.Assert(t, is.Len(subcommands, 1))
+	assert.Check(t, is.DeepEqual([]string{"image", "sign"}, subcommands[0].Path))
+	assert.Check(t, is.Equal("sign", subcommands[0].Name()))
+	assert.Check(t, is.Equal("asign", subcommands[0].Plugin.Name))
+
+	assert.Assert(t, is.Len(errs, 2))
+	assert.Check(t, is.Error(errs[0], `plugin "asign" cannot add subcommand "image ls": it duplicates builtin command "docker image ls"`))
+	assert.Check(t, is.Error(errs[1], `plugin "zsign" cannot add subcommand "image sign": already added by plugin "asign"`))
+}
+
+func TestPluginSubcommandInvocationContext(t *testing.T) {
+	root := newSubcommandsTestRoot()
+	plugins := []Plugin{
+		{Name: "signer", Metadata: Metadata{Subcommands: []string{"image sign", "image verify"}}},
+	}
+	subcommands, errs := resolvePluginSubcommands(plugins, root)
+	assert.Assert(t, is.Len(errs, 0))
+	assert.Assert(t, is.Len(subcommands, 2))
+
+	cli := test.NewFakeCli(nil)
+	for i, expected := range []string{"image sign", "image verify"} {
+		invocation := subcommands[i].InvocationContext(cli, []string{"--debug"})
+		assert.Check(t, is.Equal("signer", subcommands[i].Plugin.Name))
+		assert.Check(t, is.Equal(expected, invocation.Subcommand))
+		assert.Check(t, is.DeepEqual([]string{"--debug"}, invocation.GlobalArgs))
+		value, err := invocation.Encode()
+		assert.NilError(t, err)
+		assert.Check(t, is.Contains(value, `"Subcommand":"`+expected+`"`))
+	}
+
+	// The subcommand is not set when the plugin is invoked as its own command
+	value, err := NewInvocationContext(cli, nil).Encode()
+	assert.NilError(t, err)
+	assert.Check(t, !strings.Contains(value, "Subcommand"))
+}
@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// suggestionsMinimumDistance is the maximum Levenshtein distance between an
+// unknown command and the commands which are suggested, as for cobra
+// commands
+const suggestionsMinimumDistance = 2
+
+// wellKnownPlugins are the URLs of the installation instructions of the CLI
+// plugins which are suggested to be installed when their command is used
+var wellKnownPlugins = map[string]string{
+	"buildx":  "https://github.com/docker/buildx#installing",
+	"compose": "https://docs.docker.com/compose/install/",
+}
+
+// WellKnownPlugin returns the URL of the installation instructions of the CLI
+// plugin, if it is a well-known plugin, such as "compose" or "buildx".
+func WellKnownPlugin(name string) (string, bool) {
+	url, ok := wellKnownPlugins[name]
+	return url, ok
+}
+
+// SuggestCommands returns the sorted names of the builtin commands and of the
+// installed plugins which are close to the name of an unknown command. The
+// plugins are not run to get their metadata.
+func SuggestCommands(dockerCli command.Cli, rootcmd *cobra.Command, name string) []string {
+	if rootcmd.SuggestionsMinimumDistance <= 0 {
+		rootcmd.SuggestionsMinimumDistance = suggestionsMinimumDistance
+	}
+	seen := map[string]bool{}
+	for _, s := range rootcmd.SuggestionsFor(name) {
+		seen[s] = true
+	}
+
+	if pluginNames, err := listPluginNames(dockerCli); err != nil {
+		logrus.Debug(err)
+	} else {
+		for _, p := range pluginNames {
+			if isSuggested(name, p) {
+				seen[p] = true
+			}
+		}
+	}
+
+	suggestions := make([]string, 0, len(seen))
+	for s := range seen {
+		suggestions = append(suggestions, s)
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// listPluginNames returns the names of the plugin candidates which conform to
+// the policy of the configuration file
+func listPluginNames(dockerCli command.Cli) ([]string, error) {
+	pluginDirs, err := getPluginDirs(dockerCli)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := listPluginCandidates(pluginDirs)
+	if err != nil {
+		return nil, err
+	}
+	policy := getPolicy(dockerCli)
+	var names []string
+	for name, paths := range candidates {
+		if len(paths) == 0 || checkPolicy(policy, name, paths[0]) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// isSuggested returns whether the name is suggested for the typed name, if it
+// is a prefix of the name, or close to it
+func isSuggested(typed, name string) bool {
+	typed, name = strings.ToLower(typed), strings.ToLower(name)
+	return strings.HasPrefix(name, typed) || levenshtein(typed, name) <= suggestionsMinimumDistance
+}
+
+// levenshtein returns the Levenshtein distance between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
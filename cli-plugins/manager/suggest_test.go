@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestSuggestCommands(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("docker-buildx", ""),
+		fs.WithFile("docker-scan", ""),
+		fs.WithFile("docker-compose", ""),
+	)
+	defer dir.Remove()
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(&configfile.ConfigFile{CLIPluginsExtraDirs: []string{dir.Path()}})
+
+	root := &cobra.Command{Use: "docker"}
+	for _, name := range []string{"build", "builder", "container", "scale"} {
+		root.AddCommand(&cobra.Command{Use: name, Run: func(*cobra.Command, []string) {}})
+	}
+	root.AddCommand(&cobra.Command{Use: "hidden", Hidden: true, Run: func(*cobra.Command, []string) {}})
+
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{name: "biuld", expected: []string{"build"}},
+		{name: "buidx", expected: []string{"build", "buildx"}},
+		{name: "bui", expected: []string{"build", "builder", "buildx"}},
+		{name: "contaner", expected: []string{"container"}},
+		{name: "SCAN", expected: []string{"scale", "scan"}},
+		{name: "hiden", expected: []string{}},
+		{name: "nothinglikeit", expected: []string{}},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.DeepEqual(tc.expected, SuggestCommands(cli, root, tc.name)), tc.name)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Check(t, is.Equal(0, levenshtein("build", "build")))
+	assert.Check(t, is.Equal(2, levenshtein("biuld", "build")))
+	assert.Check(t, is.Equal(1, levenshtein("contaner", "container")))
+	assert.Check(t, is.Equal(5, levenshtein("", "build")))
+}
+
+func TestWellKnownPlugin(t *testing.T) {
+	url, ok := WellKnownPlugin("compose")
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal("https://docs.docker.com/compose/install/", url))
+	_, ok = WellKnownPlugin("unknown")
+	assert.Check(t, !ok)
+}
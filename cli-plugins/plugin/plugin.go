@@ -1,16 +1,22 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/connhelper"
+	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/tracing"
 	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func runPlugin(dockerCli *command.DockerCli, plugin *cobra.Command, meta manager.Metadata) error {
@@ -19,20 +25,31 @@ func runPlugin(dockerCli *command.DockerCli, plugin *cobra.Command, meta manager
 	// Doing this here avoids also calling it for the metadata
 	// command which needlessly initializes the client and tries
 	// to connect to the daemon.
-	plugin.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
-		return tcmd.Initialize(withPluginClientConn(plugin.Name()))
+	var span *tracing.Span
+	plugin.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if err := tcmd.Initialize(withPluginClientConn(plugin.Name())); err != nil {
+			return err
+		}
+		span = dockerCli.Tracer().StartSpan(cmd.CommandPath())
+		return nil
 	}
 
 	cmd, _, err := tcmd.HandleGlobalFlags()
 	if err != nil {
 		return err
 	}
-	return cmd.Execute()
+	parsedGlobalOptions = tcmd.Options()
+	err = cmd.Execute()
+	span.End(err)
+	dockerCli.Tracer().Flush()
+	return err
 }
 
 // Run is the top-level entry point to the CLI plugin framework. It should be called from your plugin's `main()` function.
 func Run(makeCmd func(command.Cli) *cobra.Command, meta manager.Metadata) {
-	dockerCli, err := command.NewDockerCli()
+	ctx, cancel := command.NotifyContext(context.Background())
+	defer cancel()
+	dockerCli, err := command.NewDockerCli(command.WithRootContext(ctx))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -53,8 +70,47 @@ func Run(makeCmd func(command.Cli) *cobra.Command, meta manager.Metadata) {
 			os.Exit(sterr.StatusCode)
 		}
 		fmt.Fprintln(dockerCli.Err(), err)
-		os.Exit(1)
+		// The exit code of the kind of the error, such as not found, is
+		// propagated to the CLI
+		os.Exit(cli.NewStatusError(err).StatusCode)
+	}
+}
+
+// persistentFlags are the global options added by the plugin
+var persistentFlags = pflag.NewFlagSet("plugin", pflag.ContinueOnError)
+
+// parsedGlobalOptions are the global options the plugin was invoked with
+var parsedGlobalOptions *cliflags.ClientOptions
+
+// PersistentFlags returns the flag set of the global options added by the
+// plugin, which can be set before the name of the plugin, such as
+// "docker --verbose myplugin". The flags must be added before calling Run,
+// and must not have shorthands. They are listed in the metadata of the
+// plugin, so that the CLI accepts them.
+func PersistentFlags() *pflag.FlagSet {
+	return persistentFlags
+}
+
+// ParsedGlobalOptions returns the global options the plugin was invoked
+// with, such as the log level, the TLS options, and the context, as parsed by
+// Run. It returns nil before the global options are parsed. The options are
+// complete once the plugin command is initialized, that is when it runs.
+func ParsedGlobalOptions() *cliflags.ClientOptions {
+	return parsedGlobalOptions
+}
+
+// GetInvocationContext returns the context the plugin was invoked with by the
+// docker CLI, or nil if the CLI did not pass it.
+func GetInvocationContext() (*manager.InvocationContext, error) {
+	value, ok := os.LookupEnv(manager.InvocationContextEnvvar)
+	if !ok {
+		return nil, nil
+	}
+	var invocation manager.InvocationContext
+	if err := json.Unmarshal([]byte(value), &invocation); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s environment variable", manager.InvocationContextEnvvar)
 	}
+	return &invocation, nil
 }
 
 func withPluginClientConn(name string) command.InitializeOpt {
@@ -65,18 +121,29 @@ func withPluginClientConn(name string) command.InitializeOpt {
 		}
 		var flags []string
 
-		// Accumulate all the global arguments, that is those
-		// up to (but not including) the plugin's name. This
-		// ensures that `docker system dial-stdio` is
-		// evaluating the same set of `--config`, `--tls*` etc
-		// global options as the plugin was called with, which
-		// in turn is the same as what the original docker
-		// invocation was passed.
-		for _, a := range os.Args[1:] {
-			if a == name {
-				break
+		// Use all the global arguments, that is those up to (but
+		// not including) the plugin's name. This ensures that
+		// `docker system dial-stdio` is evaluating the same set
+		// of `--config`, `--tls*` etc global options as the
+		// plugin was called with, which in turn is the same as
+		// what the original docker invocation was passed.
+		invocation, err := GetInvocationContext()
+		if err != nil {
+			return nil, err
+		}
+		if invocation != nil {
+			flags = append(flags, removeFlags(invocation.GlobalArgs, persistentFlags)...)
+		} else {
+			// The CLI predates the invocation context,
+			// accumulate the global arguments from our own
+			// arguments.
+			for _, a := range os.Args[1:] {
+				if a == name {
+					break
+				}
+				flags = append(flags, a)
 			}
-			flags = append(flags, a)
+			flags = removeFlags(flags, persistentFlags)
 		}
 		flags = append(flags, "system", "dial-stdio")
 
@@ -102,6 +169,7 @@ func newPluginCommand(dockerCli *command.DockerCli, plugin *cobra.Command, meta
 		DisableFlagsInUseLine: true,
 	}
 	opts, flags := cli.SetupPluginRootCommand(cmd)
+	flags.AddFlagSet(persistentFlags)
 
 	cmd.SetOutput(dockerCli.Out())
 
@@ -119,15 +187,51 @@ func newMetadataSubcommand(plugin *cobra.Command, meta manager.Metadata) *cobra.
 	if meta.ShortDescription == "" {
 		meta.ShortDescription = plugin.Short
 	}
+	if len(meta.GlobalFlags) == 0 {
+		meta.GlobalFlags = manager.NewGlobalFlags(persistentFlags)
+	}
+	var commands bool
 	cmd := &cobra.Command{
 		Use:    manager.MetadataSubcommandName,
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if commands {
+				// The commands are described once the plugin has
+				// added all of them
+				c := manager.NewCommandMetadata(plugin)
+				meta.Commands = &c
+			}
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetEscapeHTML(false)
 			enc.SetIndent("", "     ")
 			return enc.Encode(meta)
 		},
 	}
+	cmd.Flags().BoolVar(&commands, manager.CommandsFlagName, false, "Describe the commands and the flags of the plugin")
 	return cmd
 }
+
+// removeFlags removes the flags of a flag set, and their values, from args,
+// such as the global options of the plugin from the global options passed to
+// "docker system dial-stdio"
+func removeFlags(args []string, flags *pflag.FlagSet) []string {
+	var res []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" || !strings.HasPrefix(a, "--") {
+			res = append(res, a)
+			continue
+		}
+		name := strings.SplitN(strings.TrimPrefix(a, "--"), "=", 2)[0]
+		f := flags.Lookup(name)
+		if f == nil {
+			res = append(res, a)
+			continue
+		}
+		if !strings.Contains(a, "=") && f.NoOptDefVal == "" {
+			// Skip the value of the flag
+			i++
+		}
+	}
+	return res
+}
@@ -0,0 +1,17 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+)
+
+func TestRemoveFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("plugin", pflag.ContinueOnError)
+	flags.String("greeting", "", "")
+	flags.Bool("verbose", false, "")
+
+	args := []string{"-H", "tcp://host", "--greeting", "Howdy", "--verbose", "--config=/config", "--greeting=Hi", "-D"}
+	assert.DeepEqual(t, removeFlags(args, flags), []string{"-H", "tcp://host", "--config=/config", "-D"})
+}
@@ -0,0 +1,118 @@
+// Package audit records the commands executed by the CLI in an audit log,
+// which is a local file or the syslog daemon.
+//
+// The audit log is disabled unless it is enabled in the configuration file.
+// All the methods of a nil *Logger are no-ops, so that callers do not have to
+// check whether the audit log is enabled.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/redact"
+	"github.com/pkg/errors"
+)
+
+// syslogTag is the tag of the records sent to the syslog daemon
+const syslogTag = "docker-cli"
+
+// Record is the record of a command in the audit log
+type Record struct {
+	Time time.Time `json:"time"`
+	User string    `json:"user"`
+	// Context is the name of the context the command was executed against.
+	Context string `json:"context"`
+	// Command is the command, such as "docker container ls".
+	Command string `json:"command"`
+	// Args are the arguments of the command line, without the values of
+	// the sensitive flags.
+	Args     []string `json:"args"`
+	ExitCode int      `json:"exitCode"`
+}
+
+// Logger appends the records of the commands to the audit log
+type Logger struct {
+	file   string
+	syslog bool
+}
+
+// New returns a logger of the audit log of the configuration, or nil if the
+// audit log is not enabled
+func New(config *configfile.AuditLogConfig) *Logger {
+	if config == nil || (config.File == "" && !config.Syslog) {
+		return nil
+	}
+	return &Logger{file: config.File, syslog: config.Syslog}
+}
+
+// Log appends a record to the audit log. Its time and user are set if they
+// are not, and the values of the sensitive flags of its arguments are
+// redacted.
+func (l *Logger) Log(record Record) error {
+	if l == nil {
+		return nil
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	if record.User == "" {
+		record.User = currentUser()
+	}
+	record.Args = RedactArgs(record.Args)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(record); err != nil {
+		return err
+	}
+
+	if l.file != "" {
+		if err := appendFile(l.file, buf.Bytes()); err != nil {
+			return errors.Wrap(err, "failed to write the audit log")
+		}
+	}
+	if l.syslog {
+		if err := writeSyslog(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))); err != nil {
+			return errors.Wrap(err, "failed to send the audit log to syslog")
+		}
+	}
+	return nil
+}
+
+// appendFile appends data to a file with a single write, so that the records
+// of concurrent commands are not interleaved
+func appendFile(filename string, data []byte) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RedactArgs returns the arguments of a command line, in which the values of
+// the sensitive flags, such as "--password", are replaced with "<redacted>",
+// as well as the values of the sensitive environment variables of the --env
+// flags
+func RedactArgs(args []string) []string {
+	return redact.Args(args)
+}
+
+// currentUser returns the name of the user executing the CLI
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestNew(t *testing.T) {
+	assert.Check(t, is.Nil(New(nil)))
+	assert.Check(t, is.Nil(New(&configfile.AuditLogConfig{})))
+	assert.Check(t, New(&configfile.AuditLogConfig{File: "audit.log"}) != nil)
+	assert.Check(t, New(&configfile.AuditLogConfig{Syslog: true}) != nil)
+
+	var l *Logger
+	assert.NilError(t, l.Log(Record{Command: "docker ps"}))
+}
+
+func TestLogFile(t *testing.T) {
+	dir := fs.NewDir(t, "audit")
+	defer dir.Remove()
+	filename := filepath.Join(dir.Path(), "audit.log")
+	l := New(&configfile.AuditLogConfig{File: filename})
+
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.NilError(t, l.Log(Record{
+		Time:    now,
+		User:    "jdoe",
+		Context: "default",
+		Command: "docker login",
+		Args:    []string{"login", "--username", "jdoe", "--password", "secret", "registry.example.com"},
+	}))
+	assert.NilError(t, l.Log(Record{
+		Context:  "prod",
+		Command:  "docker compose",
+		Args:     []string{"compose", "up"},
+		ExitCode: 2,
+	}))
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	assert.Assert(t, is.Len(lines, 2))
+	assert.Check(t, is.Equal(lines[0], `{"time":"2019-06-01T12:00:00Z","user":"jdoe","context":"default","command":"docker login","args":["login","--username","jdoe","--password","<redacted>","registry.example.com"],"exitCode":0}`))
+
+	var record Record
+	assert.NilError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Check(t, !record.Time.IsZero())
+	assert.Check(t, record.User != "")
+	assert.Check(t, is.Equal(record.Command, "docker compose"))
+	assert.Check(t, is.Equal(record.ExitCode, 2))
+}
+
+func TestLogFileError(t *testing.T) {
+	dir := fs.NewDir(t, "audit")
+	defer dir.Remove()
+	l := New(&configfile.AuditLogConfig{File: filepath.Join(dir.Path(), "missing", "audit.log")})
+	assert.Check(t, is.ErrorContains(l.Log(Record{Command: "docker ps"}), "failed to write the audit log"))
+}
+
+func TestRedactArgs(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"ps", "-a"},
+			expected: []string{"ps", "-a"},
+		},
+		{
+			args:     []string{"login", "--password", "secret", "--username", "jdoe"},
+			expected: []string{"login", "--password", "<redacted>", "--username", "jdoe"},
+		},
+		{
+			args:     []string{"login", "-u", "jdoe", "-p", "secret"},
+			expected: []string{"login", "-u", "jdoe", "-p", "<redacted>"},
+		},
+		{
+			args:     []string{"run", "-e", "DB_PASSWORD=secret", "-p", "8080:80", "nginx"},
+			expected: []string{"run", "-e", "DB_PASSWORD=<redacted>", "-p", "8080:80", "nginx"},
+		},
+		{
+			args:     []string{"swarm", "join", "--token=SWMTKN-1-abc", "10.0.0.1:2377"},
+			expected: []string{"swarm", "join", "--token=<redacted>", "10.0.0.1:2377"},
+		},
+		{
+			args:     []string{"run", "busybox", "--", "echo", "--password", "secret"},
+			expected: []string{"run", "busybox", "--", "echo", "--password", "secret"},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.DeepEqual(RedactArgs(tc.args), tc.expected))
+	}
+}
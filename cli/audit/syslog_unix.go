@@ -0,0 +1,17 @@
+// +build !windows
+
+package audit
+
+import (
+	"log/syslog"
+)
+
+// writeSyslog sends a record to the local syslog daemon
+func writeSyslog(data []byte) error {
+	w, err := syslog.New(syslog.LOG_NOTICE|syslog.LOG_USER, syslogTag)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	return w.Notice(string(data))
+}
@@ -0,0 +1,10 @@
+package audit
+
+import (
+	"github.com/pkg/errors"
+)
+
+// writeSyslog fails, as there is no syslog daemon on Windows
+func writeSyslog(data []byte) error {
+	return errors.New("syslog is not supported on Windows")
+}
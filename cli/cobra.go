@@ -9,6 +9,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	cliconfig "github.com/docker/cli/cli/config"
 	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/i18n"
 	"github.com/docker/docker/pkg/term"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -22,6 +23,7 @@ func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *p
 	flags := rootCmd.Flags()
 
 	flags.StringVar(&opts.ConfigDir, "config", cliconfig.Dir(), "Location of client config files")
+	flags.BoolVar(&opts.IgnoreCLIPluginsPolicy, "ignore-cli-plugins-policy", false, "Run the CLI plugins which do not conform to the policy of the configuration file")
 	opts.Common.InstallFlags(flags)
 
 	cobra.AddTemplateFunc("add", func(a, b int) int { return a + b })
@@ -36,6 +38,7 @@ func setupCommonRootCommand(rootCmd *cobra.Command) (*cliflags.ClientOptions, *p
 	cobra.AddTemplateFunc("invalidPluginReason", invalidPluginReason)
 	cobra.AddTemplateFunc("isPlugin", isPlugin)
 	cobra.AddTemplateFunc("decoratedName", decoratedName)
+	cobra.AddTemplateFunc("t", i18n.T)
 
 	rootCmd.SetUsageTemplate(usageTemplate)
 	rootCmd.SetHelpTemplate(helpTemplate)
@@ -82,7 +85,9 @@ func FlagErrorFunc(cmd *cobra.Command, err error) error {
 	}
 	return StatusError{
 		Status:     fmt.Sprintf("%s\nSee '%s --help'.%s", err, cmd.CommandPath(), usage),
-		StatusCode: 125,
+		StatusCode: command.ExitCodeUsage,
+		Code:       command.ErrorCodeUsage,
+		WrappedErr: err,
 	}
 }
 
@@ -95,11 +100,16 @@ type TopLevelCommand struct {
 	opts      *cliflags.ClientOptions
 	flags     *pflag.FlagSet
 	args      []string
+	// commandArgs are the args following the global flags
+	commandArgs []string
+	// tolerateCLIPluginsGlobalFlags is whether the global options added by
+	// the CLI plugins are accepted
+	tolerateCLIPluginsGlobalFlags bool
 }
 
 // NewTopLevelCommand returns a new TopLevelCommand object
 func NewTopLevelCommand(cmd *cobra.Command, dockerCli *command.DockerCli, opts *cliflags.ClientOptions, flags *pflag.FlagSet) *TopLevelCommand {
-	return &TopLevelCommand{cmd, dockerCli, opts, flags, os.Args[1:]}
+	return &TopLevelCommand{cmd: cmd, dockerCli: dockerCli, opts: opts, flags: flags, args: os.Args[1:]}
 }
 
 // SetArgs sets the args (default os.Args[:1] used to invoke the command
@@ -139,6 +149,11 @@ func (tcmd *TopLevelCommand) HandleGlobalFlags() (*cobra.Command, []string, erro
 	// We need the single parse to see both sets of flags.
 	flags.AddFlagSet(cmd.Flags())
 	flags.AddFlagSet(cmd.PersistentFlags())
+	var pluginFlags map[string]*pflag.FlagSet
+	if tcmd.tolerateCLIPluginsGlobalFlags {
+		pluginFlags = tcmd.pluginGlobalFlags(flags)
+		addPluginGlobalFlags(flags, pluginFlags)
+	}
 	// Now parse the global flags, up to (but not including) the
 	// first command. The result will be that all the remaining
 	// arguments are in `flags.Args()`.
@@ -149,10 +164,39 @@ func (tcmd *TopLevelCommand) HandleGlobalFlags() (*cobra.Command, []string, erro
 		}
 		return nil, nil, cmd.FlagErrorFunc()(cmd, err)
 	}
+	if err := checkPluginGlobalFlags(flags, pluginFlags); err != nil {
+		if err := tcmd.Initialize(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, cmd.FlagErrorFunc()(cmd, err)
+	}
 
+	tcmd.commandArgs = flags.Args()
 	return cmd, flags.Args(), nil
 }
 
+// SetCommandArgs replaces the args following the global flags, as returned
+// by HandleGlobalFlags, such as to expand an alias. It returns the new args,
+// including the global flags.
+func (tcmd *TopLevelCommand) SetCommandArgs(args []string) []string {
+	fullArgs := append(tcmd.GlobalArgs(), args...)
+	tcmd.SetArgs(fullArgs)
+	tcmd.commandArgs = args
+	return fullArgs
+}
+
+// GlobalArgs returns the args preceding the command, that is the global flags,
+// as parsed by HandleGlobalFlags.
+func (tcmd *TopLevelCommand) GlobalArgs() []string {
+	return append([]string{}, tcmd.args[:len(tcmd.args)-len(tcmd.commandArgs)]...)
+}
+
+// Options returns the global options of the command, as parsed by
+// HandleGlobalFlags. Their defaults are set by Initialize.
+func (tcmd *TopLevelCommand) Options() *cliflags.ClientOptions {
+	return tcmd.opts
+}
+
 // Initialize finalises global option parsing and initializes the docker client.
 func (tcmd *TopLevelCommand) Initialize(ops ...command.InitializeOpt) error {
 	tcmd.opts.Common.SetDefaultOptions(tcmd.flags)
@@ -284,7 +328,7 @@ func invalidPluginReason(cmd *cobra.Command) string {
 	return cmd.Annotations[pluginmanager.CommandAnnotationPluginInvalid]
 }
 
-var usageTemplate = `Usage:
+var usageTemplate = `{{t "Usage:"}}
 
 {{- if not .HasSubCommands}}	{{.UseLine}}{{end}}
 {{- if .HasSubCommands}}	{{ .CommandPath}}{{- if .HasAvailableFlags}} [OPTIONS]{{end}} COMMAND{{end}}
@@ -293,25 +337,25 @@ var usageTemplate = `Usage:
 
 {{- if gt .Aliases 0}}
 
-Aliases:
+{{t "Aliases:"}}
   {{.NameAndAliases}}
 
 {{- end}}
 {{- if .HasExample}}
 
-Examples:
+{{t "Examples:"}}
 {{ .Example }}
 
 {{- end}}
 {{- if .HasAvailableFlags}}
 
-Options:
+{{t "Options:"}}
 {{ wrappedFlagUsages . | trimRightSpace}}
 
 {{- end}}
 {{- if hasManagementSubCommands . }}
 
-Management Commands:
+{{t "Management Commands:"}}
 
 {{- range managementSubCommands . }}
   {{rpad (decoratedName .) (add .NamePadding 1)}}{{.Short}}{{ if isPlugin .}} {{vendorAndVersion .}}{{ end}}
@@ -320,7 +364,7 @@ Management Commands:
 {{- end}}
 {{- if hasSubCommands .}}
 
-Commands:
+{{t "Commands:"}}
 
 {{- range operationSubCommands . }}
   {{rpad .Name .NamePadding }} {{.Short}}
@@ -329,7 +373,7 @@ Commands:
 
 {{- if hasInvalidPlugins . }}
 
-Invalid Plugins:
+{{t "Invalid Plugins:"}}
 
 {{- range invalidPlugins . }}
   {{rpad .Name .NamePadding }} {{invalidPluginReason .}}
@@ -339,7 +383,7 @@ Invalid Plugins:
 
 {{- if .HasSubCommands }}
 
-Run '{{.CommandPath}} COMMAND --help' for more information on a command.
+{{printf (t "Run '%s COMMAND --help' for more information on a command.") .CommandPath}}
 {{- end}}
 `
 
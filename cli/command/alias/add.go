@@ -0,0 +1,65 @@
+package alias
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type addOptions struct {
+	name    string
+	command string
+	force   bool
+}
+
+func newAddCommand(dockerCli command.Cli) *cobra.Command {
+	var opts addOptions
+	cmd := &cobra.Command{
+		Use:   "add [OPTIONS] NAME COMMAND",
+		Short: "Add a command alias",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			opts.command = args[1]
+			return runAdd(dockerCli, cmd.Root(), opts)
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Replace the alias if it already exists")
+	return cmd
+}
+
+func runAdd(dockerCli command.Cli, root *cobra.Command, opts addOptions) error {
+	if err := validateAliasName(opts.name); err != nil {
+		return err
+	}
+	if isCommand(root, opts.name) {
+		return errors.Errorf("%q is a docker command and cannot be used as an alias", opts.name)
+	}
+	cfg := dockerCli.ConfigFile()
+	if _, exists := cfg.Aliases[opts.name]; exists && !opts.force {
+		return errors.Errorf("alias %q already exists, use --force to replace it", opts.name)
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]string{}
+	}
+	cfg.Aliases[opts.name] = opts.command
+	// Check that the alias does not expand to itself before it is saved
+	if _, err := Expand(cfg.Aliases, []string{opts.name}, func(name string) bool { return isCommand(root, name) }); err != nil {
+		delete(cfg.Aliases, opts.name)
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), opts.name)
+	return nil
+}
+
+// isCommand returns whether name is the name of a top-level docker command
+func isCommand(root *cobra.Command, name string) bool {
+	_, _, err := root.Find([]string{name})
+	return err == nil
+}
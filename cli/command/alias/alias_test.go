@@ -0,0 +1,103 @@
+package alias
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func newTestCli(t *testing.T, dir *fs.Dir) *test.FakeCli {
+	t.Helper()
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(configfile.New(filepath.Join(dir.Path(), "config.json")))
+	return cli
+}
+
+func newTestRoot(cli *test.FakeCli) *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	root.AddCommand(&cobra.Command{Use: "ps", Run: func(*cobra.Command, []string) {}}, NewAliasCommand(cli))
+	return root
+}
+
+func TestAddListRemove(t *testing.T) {
+	dir := fs.NewDir(t, "alias")
+	defer dir.Remove()
+	cli := newTestCli(t, dir)
+	root := newTestRoot(cli)
+
+	for _, args := range [][]string{
+		{"alias", "add", "dps", "ps --format '{{.Names}}'"},
+		{"alias", "add", "it", "run -it --rm"},
+		{"alias", "add", "--force", "it", "run -it --rm --init"},
+	} {
+		root.SetArgs(args)
+		assert.NilError(t, root.Execute())
+	}
+	assert.Check(t, is.DeepEqual(map[string]string{
+		"dps": "ps --format '{{.Names}}'",
+		"it":  "run -it --rm --init",
+	}, cli.ConfigFile().Aliases))
+
+	cli.OutBuffer().Reset()
+	root.SetArgs([]string{"alias", "ls"})
+	assert.NilError(t, root.Execute())
+	assert.Check(t, is.Equal(`NAME   COMMAND
+dps    ps --format '{{.Names}}'
+it     run -it --rm --init
+`, cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	root.SetArgs([]string{"alias", "rm", "dps", "unknown"})
+	assert.Check(t, is.Error(root.Execute(), "unknown: no such alias"))
+	assert.Check(t, is.Equal("dps\n", cli.OutBuffer().String()))
+	assert.Check(t, is.DeepEqual(map[string]string{"it": "run -it --rm --init"}, cli.ConfigFile().Aliases))
+
+	// The aliases are saved in the configuration file
+	b, err := ioutil.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	saved := configfile.New(cli.ConfigFile().Filename)
+	assert.NilError(t, saved.LoadFromReader(bytes.NewReader(b)))
+	assert.Check(t, is.DeepEqual(map[string]string{"it": "run -it --rm --init"}, saved.Aliases))
+}
+
+func TestAddErrors(t *testing.T) {
+	dir := fs.NewDir(t, "alias")
+	defer dir.Remove()
+	cli := newTestCli(t, dir)
+	cli.ConfigFile().Aliases = map[string]string{"a": "b", "it": "run -it"}
+
+	testCases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{"ps", "ps -a"},
+			expectedErr: `"ps" is a docker command and cannot be used as an alias`,
+		},
+		{
+			args:        []string{"it", "run"},
+			expectedErr: `alias "it" already exists, use --force to replace it`,
+		},
+		{
+			args:        []string{"b", "a x"},
+			expectedErr: "alias loop detected: b -> a -> b",
+		},
+		{
+			args:        []string{"-x", "ps"},
+			expectedErr: `alias name "-x" is invalid, names are validated against regexp "^[a-zA-Z0-9][a-zA-Z0-9_.+-]*$"`,
+		},
+	}
+	for _, tc := range testCases {
+		err := runAdd(cli, newTestRoot(cli), addOptions{name: tc.args[0], command: tc.args[1]})
+		assert.Check(t, is.Error(err, tc.expectedErr))
+	}
+	assert.Check(t, is.DeepEqual(map[string]string{"a": "b", "it": "run -it"}, cli.ConfigFile().Aliases))
+}
@@ -0,0 +1,51 @@
+package alias
+
+import (
+	"regexp"
+	"strings"
+
+	shellwords "github.com/mattn/go-shellwords"
+	"github.com/pkg/errors"
+)
+
+const restrictedNamePattern = "^[a-zA-Z0-9][a-zA-Z0-9_.+-]*$"
+
+var restrictedNameRegEx = regexp.MustCompile(restrictedNamePattern)
+
+func validateAliasName(name string) error {
+	if !restrictedNameRegEx.MatchString(name) {
+		return errors.Errorf("alias name %q is invalid, names are validated against regexp %q", name, restrictedNamePattern)
+	}
+	return nil
+}
+
+// Expand replaces the first argument of args, if it is an alias, with the
+// command it stands for, until the first argument is not an alias. Built-in
+// commands, as reported by isCommand, cannot be aliased. An error is returned
+// if an alias expands to itself, directly or through other aliases.
+func Expand(aliases map[string]string, args []string, isCommand func(name string) bool) ([]string, error) {
+	seen := map[string]bool{}
+	var chain []string
+	for len(args) > 0 {
+		name := args[0]
+		value, ok := aliases[name]
+		if !ok || isCommand(name) {
+			return args, nil
+		}
+		chain = append(chain, name)
+		if seen[name] {
+			return nil, errors.Errorf("alias loop detected: %s", strings.Join(chain, " -> "))
+		}
+		seen[name] = true
+
+		words, err := shellwords.Parse(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid alias %q", name)
+		}
+		if len(words) == 0 {
+			return nil, errors.Errorf("invalid alias %q: empty command", name)
+		}
+		args = append(words, args[1:]...)
+	}
+	return args, nil
+}
@@ -0,0 +1,74 @@
+package alias
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestExpand(t *testing.T) {
+	aliases := map[string]string{
+		"dps":   `ps --format 'table {{.Names}}\t{{.Status}}'`,
+		"it":    "run -it --rm",
+		"sh":    "it alpine sh",
+		"ps":    "ps -a",
+		"loop":  "loop2 x",
+		"loop2": "loop",
+		"self":  "self -a",
+		"bad":   `ps "unterminated`,
+		"none":  "",
+	}
+	isCommand := func(name string) bool {
+		return name == "ps" || name == "run"
+	}
+
+	testCases := []struct {
+		args        []string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			args:     []string{"dps", "-q"},
+			expected: []string{"ps", "--format", `table {{.Names}}\t{{.Status}}`, "-q"},
+		},
+		{
+			args:     []string{"sh", "-c", "true"},
+			expected: []string{"run", "-it", "--rm", "alpine", "sh", "-c", "true"},
+		},
+		{
+			// built-in commands cannot be aliased
+			args:     []string{"ps"},
+			expected: []string{"ps"},
+		},
+		{
+			args:     []string{"unknown"},
+			expected: []string{"unknown"},
+		},
+		{
+			args:        []string{"loop"},
+			expectedErr: "alias loop detected: loop -> loop2 -> loop",
+		},
+		{
+			args:        []string{"self"},
+			expectedErr: "alias loop detected: self -> self",
+		},
+		{
+			args:        []string{"bad"},
+			expectedErr: `invalid alias "bad": invalid command line string`,
+		},
+		{
+			args:        []string{"none"},
+			expectedErr: `invalid alias "none": empty command`,
+		},
+	}
+	for _, tc := range testCases {
+		actual, err := Expand(aliases, tc.args, isCommand)
+		if tc.expectedErr != "" {
+			assert.Check(t, is.Error(err, tc.expectedErr))
+			continue
+		}
+		assert.Check(t, err)
+		assert.Check(t, is.DeepEqual(tc.expected, actual))
+	}
+}
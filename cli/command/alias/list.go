@@ -0,0 +1,52 @@
+package alias
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet bool
+}
+
+func newListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts listOptions
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List command aliases",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, opts)
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.quiet, "quiet", "q", false, "Only show alias names")
+	return cmd
+}
+
+func runList(dockerCli command.Cli, opts listOptions) error {
+	aliases := dockerCli.ConfigFile().Aliases
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if opts.quiet {
+		for _, name := range names {
+			fmt.Fprintln(dockerCli.Out(), name)
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCOMMAND")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, aliases[name])
+	}
+	return w.Flush()
+}
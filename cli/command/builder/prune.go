@@ -64,7 +64,7 @@ func runPrune(dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint6
 	if options.all {
 		warning = allCacheWarning
 	}
-	if !options.force && !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), warning) {
+	if !command.ConfirmPrune(dockerCli, options.force, warning, "builder") {
 		return 0, "", nil
 	}
 
@@ -12,6 +12,10 @@ type fakeClient struct {
 	checkpointCreateFunc func(container string, options types.CheckpointCreateOptions) error
 	checkpointDeleteFunc func(container string, options types.CheckpointDeleteOptions) error
 	checkpointListFunc   func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error)
+	containerInspectFunc func(container string) (types.ContainerJSON, error)
+	containerStartFunc   func(container string, options types.ContainerStartOptions) error
+	infoFunc             func() (types.Info, error)
+	daemonHost           string
 }
 
 func (cli *fakeClient) CheckpointCreate(ctx context.Context, container string, options types.CheckpointCreateOptions) error {
@@ -34,3 +38,30 @@ func (cli *fakeClient) CheckpointList(ctx context.Context, container string, opt
 	}
 	return []types.Checkpoint{}, nil
 }
+
+func (cli *fakeClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	if cli.containerInspectFunc != nil {
+		return cli.containerInspectFunc(container)
+	}
+	return types.ContainerJSON{}, nil
+}
+
+func (cli *fakeClient) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	if cli.containerStartFunc != nil {
+		return cli.containerStartFunc(container, options)
+	}
+	return nil
+}
+
+// Info returns the info of a daemon supporting checkpoints, unless infoFunc
+// is set
+func (cli *fakeClient) Info(ctx context.Context) (types.Info, error) {
+	if cli.infoFunc != nil {
+		return cli.infoFunc()
+	}
+	return types.Info{OSType: "linux", ExperimentalBuild: true}, nil
+}
+
+func (cli *fakeClient) DaemonHost() string {
+	return cli.daemonHost
+}
@@ -39,7 +39,11 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runCreate(dockerCli command.Cli, opts createOptions) error {
+	ctx := context.Background()
 	client := dockerCli.Client()
+	if err := ValidateDaemonSupport(ctx, client); err != nil {
+		return err
+	}
 
 	checkpointOpts := types.CheckpointCreateOptions{
 		CheckpointID:  opts.checkpoint,
@@ -47,9 +51,11 @@ func runCreate(dockerCli command.Cli, opts createOptions) error {
 		Exit:          !opts.leaveRunning,
 	}
 
-	err := client.CheckpointCreate(context.Background(), opts.container, checkpointOpts)
+	done := startProgress(dockerCli, fmt.Sprintf("Checkpointing container %s", opts.container))
+	err := client.CheckpointCreate(ctx, opts.container, checkpointOpts)
+	done(err)
 	if err != nil {
-		return err
+		return wrapCRIUError(err)
 	}
 
 	fmt.Fprintf(dockerCli.Out(), "%s\n", opts.checkpoint)
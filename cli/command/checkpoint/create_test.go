@@ -33,6 +33,13 @@ func TestCheckpointCreateErrors(t *testing.T) {
 			},
 			expectedError: "error creating checkpoint for container foo",
 		},
+		{
+			args: []string{"foo", "bar"},
+			checkpointCreateFunc: func(container string, options types.CheckpointCreateOptions) error {
+				return errors.Errorf(`exec: "criu": executable file not found in $PATH`)
+			},
+			expectedError: "CRIU failed on the host of the daemon",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -46,6 +53,35 @@ func TestCheckpointCreateErrors(t *testing.T) {
 	}
 }
 
+func TestCheckpointCreateUnsupportedDaemon(t *testing.T) {
+	testCases := []struct {
+		info          types.Info
+		expectedError string
+	}{
+		{
+			info:          types.Info{OSType: "windows", ExperimentalBuild: true},
+			expectedError: "checkpoints are not supported by windows daemons",
+		},
+		{
+			info:          types.Info{OSType: "linux"},
+			expectedError: "checkpoints are not supported by the daemon: enable its experimental features, and install CRIU on its host",
+		},
+	}
+	for _, tc := range testCases {
+		info := tc.info
+		cli := test.NewFakeCli(&fakeClient{
+			infoFunc: func() (types.Info, error) { return info, nil },
+			checkpointCreateFunc: func(container string, options types.CheckpointCreateOptions) error {
+				return errors.New("unexpected checkpoint")
+			},
+		})
+		cmd := newCreateCommand(cli)
+		cmd.SetArgs([]string{"foo", "bar"})
+		cmd.SetOutput(ioutil.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
+
 func TestCheckpointCreateWithOptions(t *testing.T) {
 	var containerID, checkpointID, checkpointDir string
 	var exit bool
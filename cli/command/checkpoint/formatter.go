@@ -1,16 +1,26 @@
 package checkpoint
 
 import (
+	"time"
+
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/docker/api/types"
+	units "github.com/docker/go-units"
 )
 
 const (
-	defaultCheckpointFormat = "table {{.Name}}"
+	defaultCheckpointFormat = "table {{.Name}}\t{{.CreatedSince}}\t{{.Size}}"
 
 	checkpointNameHeader = "CHECKPOINT NAME"
 )
 
+// Details are the details of a checkpoint which are not returned by the API,
+// but read from the checkpoint directory on the host of the daemon
+type Details struct {
+	Size    int64
+	Created time.Time
+}
+
 // NewFormat returns a format for use with a checkpoint Context
 func NewFormat(source string) formatter.Format {
 	switch source {
@@ -20,11 +30,13 @@ func NewFormat(source string) formatter.Format {
 	return formatter.Format(source)
 }
 
-// FormatWrite writes formatted checkpoints using the Context
-func FormatWrite(ctx formatter.Context, checkpoints []types.Checkpoint) error {
+// FormatWrite writes formatted checkpoints using the Context, with their
+// details, if known
+func FormatWrite(ctx formatter.Context, checkpoints []types.Checkpoint, details map[string]Details) error {
 	render := func(format func(subContext formatter.SubContext) error) error {
 		for _, checkpoint := range checkpoints {
-			if err := format(&checkpointContext{c: checkpoint}); err != nil {
+			d, known := details[checkpoint.Name]
+			if err := format(&checkpointContext{c: checkpoint, d: d, known: known}); err != nil {
 				return err
 			}
 		}
@@ -35,13 +47,18 @@ func FormatWrite(ctx formatter.Context, checkpoints []types.Checkpoint) error {
 
 type checkpointContext struct {
 	formatter.HeaderContext
-	c types.Checkpoint
+	c     types.Checkpoint
+	d     Details
+	known bool
 }
 
 func newCheckpointContext() *checkpointContext {
 	cpCtx := checkpointContext{}
 	cpCtx.Header = formatter.SubHeaderContext{
-		"Name": checkpointNameHeader,
+		"Name":         checkpointNameHeader,
+		"CreatedSince": formatter.CreatedSinceHeader,
+		"CreatedAt":    formatter.CreatedAtHeader,
+		"Size":         formatter.SizeHeader,
 	}
 	return &cpCtx
 }
@@ -53,3 +70,24 @@ func (c *checkpointContext) MarshalJSON() ([]byte, error) {
 func (c *checkpointContext) Name() string {
 	return c.c.Name
 }
+
+func (c *checkpointContext) CreatedSince() string {
+	if !c.known {
+		return "N/A"
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(c.d.Created)) + " ago"
+}
+
+func (c *checkpointContext) CreatedAt() string {
+	if !c.known {
+		return "N/A"
+	}
+	return c.d.Created.String()
+}
+
+func (c *checkpointContext) Size() string {
+	if !c.known {
+		return "N/A"
+	}
+	return units.HumanSizeWithPrecision(float64(c.d.Size), 3)
+}
@@ -3,6 +3,7 @@ package checkpoint
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/docker/api/types"
@@ -16,10 +17,25 @@ func TestCheckpointContextFormatWrite(t *testing.T) {
 	}{
 		{
 			formatter.Context{Format: NewFormat(defaultCheckpointFormat)},
+			`CHECKPOINT NAME     CREATED             SIZE
+checkpoint-1        N/A                 N/A
+checkpoint-2        2 hours ago         1.5MB
+checkpoint-3        N/A                 N/A
+`,
+		},
+		{
+			formatter.Context{Format: NewFormat("table {{.Name}}")},
 			`CHECKPOINT NAME
 checkpoint-1
 checkpoint-2
 checkpoint-3
+`,
+		},
+		{
+			formatter.Context{Format: NewFormat("{{.Name}} {{.Size}}")},
+			`checkpoint-1 N/A
+checkpoint-2 1.5MB
+checkpoint-3 N/A
 `,
 		},
 		{
@@ -43,10 +59,13 @@ checkpoint-3:
 		{Name: "checkpoint-2"},
 		{Name: "checkpoint-3"},
 	}
+	details := map[string]Details{
+		"checkpoint-2": {Size: 1500000, Created: time.Now().Add(-2 * time.Hour)},
+	}
 	for _, testcase := range cases {
 		out := bytes.NewBufferString("")
 		testcase.context.Output = out
-		err := FormatWrite(testcase.context, checkpoints)
+		err := FormatWrite(testcase.context, checkpoints, details)
 		assert.NilError(t, err)
 		assert.Equal(t, out.String(), testcase.expected)
 	}
@@ -2,16 +2,21 @@ package checkpoint
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/docker/api/types"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 type listOptions struct {
 	checkpointDir string
+	format        string
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
@@ -29,26 +34,86 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.checkpointDir, "checkpoint-dir", "", "", "Use a custom checkpoint storage directory")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print checkpoints using a Go template")
 
 	return cmd
 
 }
 
 func runList(dockerCli command.Cli, container string, opts listOptions) error {
+	ctx := context.Background()
 	client := dockerCli.Client()
 
 	listOpts := types.CheckpointListOptions{
 		CheckpointDir: opts.checkpointDir,
 	}
 
-	checkpoints, err := client.CheckpointList(context.Background(), container, listOpts)
+	checkpoints, err := client.CheckpointList(ctx, container, listOpts)
 	if err != nil {
-		return err
+		return wrapCRIUError(err)
 	}
 
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
 	cpCtx := formatter.Context{
 		Output: dockerCli.Out(),
-		Format: NewFormat(formatter.TableFormatKey),
+		Format: NewFormat(format),
+	}
+	return FormatWrite(cpCtx, checkpoints, readDetails(ctx, dockerCli, container, opts.checkpointDir, checkpoints))
+}
+
+// readDetails returns the details of the checkpoints which can be read from
+// their directories, if the daemon runs on the same host. The checkpoints are
+// stored in the "checkpoints" directory of the container, unless a custom
+// checkpoint directory is used.
+func readDetails(ctx context.Context, dockerCli command.Cli, container, checkpointDir string, checkpoints []types.Checkpoint) map[string]Details {
+	client := dockerCli.Client()
+	if len(checkpoints) == 0 || !strings.HasPrefix(client.DaemonHost(), "unix://") {
+		return nil
+	}
+	if checkpointDir == "" {
+		c, err := client.ContainerInspect(ctx, container)
+		if err != nil {
+			logrus.Debug(err)
+			return nil
+		}
+		info, err := client.Info(ctx)
+		if err != nil {
+			logrus.Debug(err)
+			return nil
+		}
+		checkpointDir = filepath.Join(info.DockerRootDir, "containers", c.ID, "checkpoints")
+	}
+	details := map[string]Details{}
+	for _, c := range checkpoints {
+		d, err := readCheckpointDir(filepath.Join(checkpointDir, c.Name))
+		if err != nil {
+			logrus.Debug(err)
+			continue
+		}
+		details[c.Name] = d
+	}
+	return details
+}
+
+// readCheckpointDir returns the total size of the files of the directory of
+// a checkpoint, and the time it was created
+func readCheckpointDir(dir string) (Details, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return Details{}, err
 	}
-	return FormatWrite(cpCtx, checkpoints)
+	d := Details{Created: fi.ModTime()}
+	err = filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			d.Size += fi.Size()
+		}
+		return nil
+	})
+	return d, err
 }
@@ -2,6 +2,7 @@ package checkpoint
 
 import (
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/internal/test"
@@ -9,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
 	"gotest.tools/golden"
 )
 
@@ -65,3 +67,43 @@ func TestCheckpointListWithOptions(t *testing.T) {
 	assert.Check(t, is.Equal("/dir/foo", checkpointDir))
 	golden.Assert(t, cli.OutBuffer().String(), "checkpoint-list-with-options.golden")
 }
+
+func TestCheckpointListFormatWithDetails(t *testing.T) {
+	dir := fs.NewDir(t, "checkpoints",
+		fs.WithDir("checkpoint-foo", fs.WithFile("core.img", strings.Repeat("x", 1000)), fs.WithDir("criu", fs.WithFile("dump.log", strings.Repeat("x", 500)))),
+	)
+	defer dir.Remove()
+	cli := test.NewFakeCli(&fakeClient{
+		daemonHost: "unix:///var/run/docker.sock",
+		checkpointListFunc: func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error) {
+			return []types.Checkpoint{{Name: "checkpoint-foo"}, {Name: "checkpoint-bar"}}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{"--checkpoint-dir", dir.Path(), "--format", "{{.Name}} {{.Size}}", "container-foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("checkpoint-foo 1.5kB\ncheckpoint-bar N/A\n", cli.OutBuffer().String()))
+}
+
+func TestCheckpointListDefaultDir(t *testing.T) {
+	root := fs.NewDir(t, "docker",
+		fs.WithDir("containers", fs.WithDir("abc123", fs.WithDir("checkpoints", fs.WithDir("checkpoint-foo", fs.WithFile("core.img", "xx"))))),
+	)
+	defer root.Remove()
+	cli := test.NewFakeCli(&fakeClient{
+		daemonHost: "unix:///var/run/docker.sock",
+		checkpointListFunc: func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error) {
+			return []types.Checkpoint{{Name: "checkpoint-foo"}}, nil
+		},
+		containerInspectFunc: func(container string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: "abc123"}}, nil
+		},
+		infoFunc: func() (types.Info, error) {
+			return types.Info{DockerRootDir: root.Path()}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Name}} {{.Size}}", "container-foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("checkpoint-foo 2B\n", cli.OutBuffer().String()))
+}
@@ -0,0 +1,53 @@
+package checkpoint
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+)
+
+// progressInterval is the interval between the updates of the time elapsed
+// while checkpointing or restoring a container
+var progressInterval = time.Second
+
+// startProgress prints the action, such as "Checkpointing container web",
+// and the time elapsed, to the standard error if it is a terminal, until the
+// returned function is called with the result of the action.
+func startProgress(dockerCli command.Cli, action string) func(err error) {
+	if !streams.NewOut(dockerCli.Err()).IsTerminal() {
+		return func(error) {}
+	}
+	return newProgress(dockerCli.Err(), action, time.Now)
+}
+
+func newProgress(out io.Writer, action string, now func() time.Time) func(err error) {
+	start := now()
+	fmt.Fprintf(out, "%s...", action)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fmt.Fprintf(out, "\r%s... %s", action, now().Sub(start).Round(time.Second))
+			}
+		}
+	}()
+	return func(err error) {
+		close(done)
+		<-stopped
+		status := "done"
+		if err != nil {
+			status = "failed"
+		}
+		fmt.Fprintf(out, "\r%s... %s (%s)\n", action, status, now().Sub(start).Round(100*time.Millisecond))
+	}
+}
@@ -0,0 +1,53 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+// RestoreOptions are the options to restore a container from a checkpoint
+type RestoreOptions struct {
+	Container     string
+	Checkpoint    string
+	CheckpointDir string
+}
+
+// RunRestore starts a container from one of its checkpoints, after checking
+// that the daemon supports checkpoints and that the checkpoint exists
+func RunRestore(ctx context.Context, dockerCli command.Cli, opts RestoreOptions) error {
+	client := dockerCli.Client()
+	if err := ValidateDaemonSupport(ctx, client); err != nil {
+		return err
+	}
+
+	checkpoints, err := client.CheckpointList(ctx, opts.Container, types.CheckpointListOptions{
+		CheckpointDir: opts.CheckpointDir,
+	})
+	if err != nil {
+		return wrapCRIUError(err)
+	}
+	if !hasCheckpoint(checkpoints, opts.Checkpoint) {
+		return errors.Errorf("container %s has no checkpoint %s, see 'docker checkpoint ls %s'", opts.Container, opts.Checkpoint, opts.Container)
+	}
+
+	done := startProgress(dockerCli, fmt.Sprintf("Restoring container %s from checkpoint %s", opts.Container, opts.Checkpoint))
+	err = client.ContainerStart(ctx, opts.Container, types.ContainerStartOptions{
+		CheckpointID:  opts.Checkpoint,
+		CheckpointDir: opts.CheckpointDir,
+	})
+	done(err)
+	return wrapCRIUError(err)
+}
+
+func hasCheckpoint(checkpoints []types.Checkpoint, name string) bool {
+	for _, c := range checkpoints {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
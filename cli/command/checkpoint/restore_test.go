@@ -0,0 +1,123 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRunRestore(t *testing.T) {
+	var started types.ContainerStartOptions
+	cli := test.NewFakeCli(&fakeClient{
+		checkpointListFunc: func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error) {
+			assert.Check(t, is.Equal("/dir/foo", options.CheckpointDir))
+			return []types.Checkpoint{{Name: "checkpoint-foo"}}, nil
+		},
+		containerStartFunc: func(container string, options types.ContainerStartOptions) error {
+			assert.Check(t, is.Equal("container-foo", container))
+			started = options
+			return nil
+		},
+	})
+	assert.NilError(t, RunRestore(context.Background(), cli, RestoreOptions{
+		Container:     "container-foo",
+		Checkpoint:    "checkpoint-foo",
+		CheckpointDir: "/dir/foo",
+	}))
+	assert.Check(t, is.DeepEqual(types.ContainerStartOptions{CheckpointID: "checkpoint-foo", CheckpointDir: "/dir/foo"}, started))
+}
+
+func TestRunRestoreErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		client        *fakeClient
+		expectedError string
+	}{
+		{
+			name: "unsupported daemon",
+			client: &fakeClient{
+				infoFunc: func() (types.Info, error) { return types.Info{OSType: "linux"}, nil },
+			},
+			expectedError: "checkpoints are not supported by the daemon",
+		},
+		{
+			name:          "missing checkpoint",
+			client:        &fakeClient{},
+			expectedError: "container container-foo has no checkpoint checkpoint-foo, see 'docker checkpoint ls container-foo'",
+		},
+		{
+			name: "criu error",
+			client: &fakeClient{
+				checkpointListFunc: func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error) {
+					return []types.Checkpoint{{Name: "checkpoint-foo"}}, nil
+				},
+				containerStartFunc: func(container string, options types.ContainerStartOptions) error {
+					return errors.New("criu failed: type NOTIFY errno 0")
+				},
+			},
+			expectedError: "CRIU failed on the host of the daemon, check that it is installed and supports the kernel of the host: criu failed: type NOTIFY errno 0",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(tc.client)
+		err := RunRestore(context.Background(), cli, RestoreOptions{Container: "container-foo", Checkpoint: "checkpoint-foo"})
+		assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.name)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	defer func(interval time.Duration) { progressInterval = interval }(progressInterval)
+	progressInterval = time.Millisecond
+
+	var (
+		mu      sync.Mutex
+		elapsed time.Duration
+	)
+	setElapsed := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		elapsed = d
+	}
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return time.Unix(0, 0).Add(elapsed)
+	}
+	out := &syncBuffer{}
+	done := newProgress(out, "Checkpointing container web", now)
+	setElapsed(2 * time.Second)
+	for !strings.Contains(out.String(), " 2s") {
+		time.Sleep(time.Millisecond)
+	}
+	setElapsed(2500 * time.Millisecond)
+	done(nil)
+	assert.Check(t, strings.HasPrefix(out.String(), "Checkpointing container web..."), out.String())
+	assert.Check(t, strings.Contains(out.String(), "\rCheckpointing container web... 2s"), out.String())
+	assert.Check(t, strings.HasSuffix(out.String(), "\rCheckpointing container web... done (2.5s)\n"), out.String())
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,35 @@
+package checkpoint
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// ValidateDaemonSupport returns an error if the daemon cannot checkpoint and
+// restore containers. Checkpoints are only supported by Linux daemons with
+// experimental features enabled, which use CRIU on their host.
+func ValidateDaemonSupport(ctx context.Context, apiClient client.APIClient) error {
+	info, err := apiClient.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if info.OSType != "" && info.OSType != "linux" {
+		return errors.Errorf("checkpoints are not supported by %s daemons", info.OSType)
+	}
+	if !info.ExperimentalBuild {
+		return errors.New("checkpoints are not supported by the daemon: enable its experimental features, and install CRIU on its host")
+	}
+	return nil
+}
+
+// wrapCRIUError explains the errors of CRIU returned by the daemon, such as
+// when CRIU is not installed on its host
+func wrapCRIUError(err error) error {
+	if err == nil || !strings.Contains(strings.ToLower(err.Error()), "criu") {
+		return err
+	}
+	return errors.Wrap(err, "CRIU failed on the host of the daemon, check that it is installed and supports the kernel of the host")
+}
@@ -17,13 +17,16 @@ import (
 	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/cli/cli/debug"
 	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/i18n"
 	manifeststore "github.com/docker/cli/cli/manifest/store"
 	registryclient "github.com/docker/cli/cli/registry/client"
 	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/cli/tracing"
 	"github.com/docker/cli/cli/trust"
 	"github.com/docker/cli/cli/version"
 	"github.com/docker/cli/internal/containerizedengine"
 	dopts "github.com/docker/cli/opts"
+	"github.com/docker/cli/templates"
 	clitypes "github.com/docker/cli/types"
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
@@ -65,6 +68,10 @@ type Cli interface {
 	CurrentContext() string
 	StackOrchestrator(flagValue string) (Orchestrator, error)
 	DockerEndpoint() docker.Endpoint
+	SelectedContextClients() []ContextAPIClient
+	ColorPolicy() streams.ColorPolicy
+	NoTrunc() bool
+	Context() context.Context
 }
 
 // DockerCli is an instance the docker command line client.
@@ -82,7 +89,12 @@ type DockerCli struct {
 	contextStore          store.Store
 	currentContext        string
 	dockerEndpoint        docker.Endpoint
+	selectedContexts      []ContextAPIClient
+	colorPolicy           streams.ColorPolicy
+	noTrunc               bool
 	contextStoreConfig    store.Config
+	tracer                *tracing.Tracer
+	rootContext           context.Context
 }
 
 // DefaultVersion returns api.defaultVersion or DOCKER_API_VERSION if specified.
@@ -95,6 +107,21 @@ func (cli *DockerCli) Client() client.APIClient {
 	return cli.client
 }
 
+// Tracer returns the tracer recording the spans of the command, or nil if
+// tracing is not enabled in the configuration file
+func (cli *DockerCli) Tracer() *tracing.Tracer {
+	return cli.tracer
+}
+
+// Context returns the root context of the CLI, which is cancelled when the
+// command is cancelled, such as by pressing CTRL-C. It is never nil.
+func (cli *DockerCli) Context() context.Context {
+	if cli.rootContext == nil {
+		return context.Background()
+	}
+	return cli.rootContext
+}
+
 // Out returns the writer used for stdout
 func (cli *DockerCli) Out() *streams.Out {
 	return cli.out
@@ -175,6 +202,32 @@ func (cli *DockerCli) RegistryClient(allowInsecure bool) registryclient.Registry
 	return registryclient.NewRegistryClient(resolver, UserAgent(), allowInsecure)
 }
 
+type initializationEndpointError struct {
+	error
+}
+
+func (e initializationEndpointError) Cause() error {
+	return e.error
+}
+
+// endpointError marks err as a failure to resolve the endpoint of the daemon,
+// or to create the API client to connect to it
+func endpointError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return initializationEndpointError{err}
+}
+
+// IsEndpointError returns whether err is a failure of Initialize to resolve
+// the endpoint of the daemon, or to create the API client to connect to it,
+// rather than another failure to initialize the CLI, such as an invalid
+// setting of the configuration file.
+func IsEndpointError(err error) bool {
+	_, ok := err.(initializationEndpointError)
+	return ok
+}
+
 // InitializeOpt is the type of the functional options passed to DockerCli.Initialize
 type InitializeOpt func(dockerCli *DockerCli) error
 
@@ -183,7 +236,7 @@ func WithInitializeClient(makeClient func(dockerCli *DockerCli) (client.APIClien
 	return func(dockerCli *DockerCli) error {
 		var err error
 		dockerCli.client, err = makeClient(dockerCli)
-		return err
+		return endpointError(err)
 	}
 }
 
@@ -208,16 +261,48 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...Initialize
 	}
 
 	cli.configFile = cliconfig.LoadDefaultConfigFile(cli.err)
+	templates.SetNamedTemplates(cli.configFile.Templates)
+	if cli.colorPolicy.Mode, err = streams.ParseColorMode(opts.Common.Color); err != nil {
+		return err
+	}
+	if cli.colorPolicy.Theme, err = streams.ParseTheme(cli.configFile.Theme); err != nil {
+		return err
+	}
+	cli.noTrunc = opts.Common.NoTrunc
+	catalog, err := i18n.Load(i18n.Language(cli.configFile.Language), i18n.DirLoader(filepath.Join(cliconfig.Dir(), "locales")))
+	if err != nil {
+		PrintWarning(cli, "Error loading the message catalog: %v", err)
+	}
+	i18n.SetCatalog(catalog)
+	if cli.tracer == nil && cli.configFile.Tracing != nil {
+		cli.tracer = tracing.New(cli.configFile.Tracing.Endpoint, cli.configFile.Tracing.Headers)
+		cli.tracer.SetResourceAttribute("service.version", version.Version)
+	}
 
 	if cli.client == nil {
 		cli.contextStore = store.New(cliconfig.ContextStoreDir(), cli.contextStoreConfig)
-		cli.currentContext, err = resolveContextName(opts.Common, cli.configFile, cli.contextStore)
+		commonOpts := opts.Common
+		var selected []string
+		if isContextSelector(cli.contextStore, commonOpts.Context) {
+			if len(commonOpts.Hosts) > 0 {
+				return errors.New("Conflicting options: either specify --host or --context, not both")
+			}
+			selected, err = selectContexts(cli.contextStore, commonOpts.Context)
+			if err != nil {
+				return endpointError(err)
+			}
+			// The first selected context is the current context
+			selectedOpts := *commonOpts
+			selectedOpts.Context = selected[0]
+			commonOpts = &selectedOpts
+		}
+		cli.currentContext, err = resolveContextName(commonOpts, cli.configFile, cli.contextStore)
 		if err != nil {
-			return err
+			return endpointError(err)
 		}
-		endpoint, err := resolveDockerEndpoint(cli.contextStore, cli.currentContext, opts.Common)
+		endpoint, err := resolveDockerEndpoint(cli.contextStore, cli.currentContext, commonOpts)
 		if err != nil {
-			return errors.Wrap(err, "unable to resolve docker endpoint")
+			return endpointError(errors.Wrap(err, "unable to resolve docker endpoint"))
 		}
 		cli.dockerEndpoint = endpoint
 
@@ -230,9 +315,24 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...Initialize
 			}
 			cli.client, err = getClientWithPassword(passRetriever, newClient)
 		}
+		if err != nil {
+			return endpointError(err)
+		}
+		var retries *int
+		if opts.Common.RetriesSet {
+			retries = &opts.Common.Retries
+		}
+		retryPolicy, err := newRetryPolicy(cli.configFile.Retries, retries)
 		if err != nil {
 			return err
 		}
+		retryAPIClient(cli.client, retryPolicy)
+		cancelAPIClient(cli.client, cli.rootContext)
+		traceAPIClient(cli.client, cli.tracer)
+		cli.client = newOfflineAPIClient(cli, cli.client, opts.Common.Offline, filepath.Join(cliconfig.Dir(), "offline-cache"))
+		if selected != nil {
+			cli.selectedContexts = newContextAPIClients(cli, selected)
+		}
 	}
 	var experimentalValue string
 	// Environment variable always overrides configuration
@@ -244,8 +344,9 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...Initialize
 		return errors.Wrap(err, "Experimental field")
 	}
 	cli.clientInfo = ClientInfo{
-		DefaultVersion:  cli.client.ClientVersion(),
-		HasExperimental: hasExperimental,
+		DefaultVersion:         cli.client.ClientVersion(),
+		HasExperimental:        hasExperimental,
+		IgnoreCLIPluginsPolicy: opts.IgnoreCLIPluginsPolicy,
 	}
 	cli.initializeFromClient()
 	return nil
@@ -279,6 +380,45 @@ func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigF
 	return client.NewClientWithOpts(clientOpts...)
 }
 
+// cancelAPIClient cancels the requests in progress of the API client when the
+// root context of the CLI is cancelled, even if they are made with another
+// context. Requests are only cancelled for plain connections to the daemon, as
+// the hijacked connections of the client (used by attach and exec) cannot be
+// established over TLS or SSH once the transport of the client is wrapped; the
+// commands use the root context for them instead.
+func cancelAPIClient(apiClient client.APIClient, ctx context.Context) {
+	c, ok := apiClient.(*client.Client)
+	if ctx == nil || !ok || !canWrapTransport(c) {
+		return
+	}
+	httpClient := c.HTTPClient()
+	httpClient.Transport = &cancelTransport{ctx: ctx, base: httpClient.Transport}
+}
+
+// traceAPIClient records a span for each request made by the API client.
+// Requests are only traced for plain connections to the daemon, as the hijacked
+// connections of the client (used by attach and exec) cannot be established
+// over TLS or SSH once the transport of the client is wrapped.
+func traceAPIClient(apiClient client.APIClient, tracer *tracing.Tracer) {
+	c, ok := apiClient.(*client.Client)
+	if tracer == nil || !ok {
+		return
+	}
+	httpClient := c.HTTPClient()
+	if !canWrapTransport(c) {
+		return
+	}
+	// Each attempt of a request that is retried is recorded as a span.
+	transport := &httpClient.Transport
+	if cancel, ok := (*transport).(*cancelTransport); ok {
+		transport = &cancel.base
+	}
+	if retry, ok := (*transport).(*retryTransport); ok {
+		transport = &retry.base
+	}
+	*transport = tracing.NewTransport(tracer, *transport)
+}
+
 func resolveDockerEndpoint(s store.Store, contextName string, opts *cliflags.CommonOptions) (docker.Endpoint, error) {
 	if contextName != "" {
 		ctxMeta, err := s.GetContextMetadata(contextName)
@@ -345,6 +485,7 @@ func (cli *DockerCli) initializeFromClient() {
 		HasExperimental: ping.Experimental,
 		OSType:          ping.OSType,
 		BuildkitVersion: ping.BuilderVersion,
+		APIVersion:      ping.APIVersion,
 	}
 	cli.client.NegotiateAPIVersionPing(ping)
 }
@@ -383,6 +524,25 @@ func (cli *DockerCli) CurrentContext() string {
 	return cli.currentContext
 }
 
+// ColorPolicy returns the policy of the colored output of the CLI, set with
+// the --color flag and the theme of the configuration file
+func (cli *DockerCli) ColorPolicy() streams.ColorPolicy {
+	return cli.colorPolicy
+}
+
+// NoTrunc returns whether the global --no-trunc flag is set, which disables
+// the truncation of the output of all the commands
+func (cli *DockerCli) NoTrunc() bool {
+	return cli.noTrunc
+}
+
+// SelectedContextClients returns the API clients of the contexts selected
+// with several names, or glob patterns, of the --context flag, or nil if a
+// single context is used. The client of the current context is the first one.
+func (cli *DockerCli) SelectedContextClients() []ContextAPIClient {
+	return cli.selectedContexts
+}
+
 // StackOrchestrator resolves which stack orchestrator is in use
 func (cli *DockerCli) StackOrchestrator(flagValue string) (Orchestrator, error) {
 	var ctxOrchestrator string
@@ -404,7 +564,7 @@ func (cli *DockerCli) StackOrchestrator(flagValue string) (Orchestrator, error)
 		ctxRaw, err := contextstore.GetContextMetadata(currentContext)
 		if store.IsErrContextDoesNotExist(err) {
 			// case where the currentContext has been removed (CLI behavior is to fallback to using DOCKER_HOST based resolution)
-			return GetStackOrchestrator(flagValue, "", configFile.StackOrchestrator, cli.Err())
+			return getStackOrchestrator(flagValue, "", configFile.StackOrchestrator, cli.printWarning)
 		}
 		if err != nil {
 			return "", err
@@ -416,7 +576,11 @@ func (cli *DockerCli) StackOrchestrator(flagValue string) (Orchestrator, error)
 		ctxOrchestrator = string(ctxMeta.StackOrchestrator)
 	}
 
-	return GetStackOrchestrator(flagValue, ctxOrchestrator, configFile.StackOrchestrator, cli.Err())
+	return getStackOrchestrator(flagValue, ctxOrchestrator, configFile.StackOrchestrator, cli.printWarning)
+}
+
+func (cli *DockerCli) printWarning(format string, args ...interface{}) {
+	PrintWarning(cli, format, args...)
 }
 
 // DockerEndpoint returns the current docker endpoint
@@ -440,12 +604,18 @@ type ServerInfo struct {
 	HasExperimental bool
 	OSType          string
 	BuildkitVersion types.BuilderVersion
+	// APIVersion is the highest API version supported by the daemon, which
+	// may be higher than the API version used by the client
+	APIVersion string
 }
 
 // ClientInfo stores details about the supported features of the client
 type ClientInfo struct {
 	HasExperimental bool
 	DefaultVersion  string
+	// IgnoreCLIPluginsPolicy is set when the CLI plugins policy of the
+	// configuration file is overridden
+	IgnoreCLIPluginsPolicy bool
 }
 
 // NewDockerCli returns a DockerCli instance with all operators applied on it.
@@ -504,10 +674,14 @@ func UserAgent() string {
 // - if DOCKER_CONTEXT is set, use this value
 // - if Config file has a globally set "CurrentContext", use this value
 // - fallbacks to default HOST, uses TLS config from flags/env vars
+// The "default" context of --context or DOCKER_CONTEXT is the default HOST.
 func resolveContextName(opts *cliflags.CommonOptions, config *configfile.ConfigFile, contextstore store.Store) (string, error) {
 	if opts.Context != "" && len(opts.Hosts) > 0 {
 		return "", errors.New("Conflicting options: either specify --host or --context, not both")
 	}
+	if opts.Context == DefaultContextName {
+		return "", nil
+	}
 	if opts.Context != "" {
 		return opts.Context, nil
 	}
@@ -518,6 +692,9 @@ func resolveContextName(opts *cliflags.CommonOptions, config *configfile.ConfigF
 		return "", nil
 	}
 	if ctxName, ok := os.LookupEnv("DOCKER_CONTEXT"); ok {
+		if ctxName == DefaultContextName {
+			return "", nil
+		}
 		return ctxName, nil
 	}
 	if config != nil && config.CurrentContext != "" {
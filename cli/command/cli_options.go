@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -62,6 +63,15 @@ func WithErrorStream(err io.Writer) DockerCliOption {
 	}
 }
 
+// WithRootContext sets the root context of a cli, such as the context returned
+// by NotifyContext.
+func WithRootContext(ctx context.Context) DockerCliOption {
+	return func(cli *DockerCli) error {
+		cli.rootContext = ctx
+		return nil
+	}
+}
+
 // WithContentTrustFromEnv enables content trust on a cli from environment variable DOCKER_CONTENT_TRUST value.
 func WithContentTrustFromEnv() DockerCliOption {
 	return func(cli *DockerCli) error {
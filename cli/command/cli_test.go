@@ -112,7 +112,7 @@ func TestInitializeFromClient(t *testing.T) {
 			pingFunc: func() (types.Ping, error) {
 				return types.Ping{Experimental: true, OSType: "linux", APIVersion: "v1.30"}, nil
 			},
-			expectedServer: ServerInfo{HasExperimental: true, OSType: "linux"},
+			expectedServer: ServerInfo{HasExperimental: true, OSType: "linux", APIVersion: "v1.30"},
 			negotiated:     true,
 		},
 		{
@@ -295,3 +295,18 @@ func TestNewDockerCliAndOperators(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, string(errStream), "error")
 }
+
+func TestResolveDefaultContextName(t *testing.T) {
+	defer env.Patch(t, "DOCKER_HOST", "")()
+	os.Unsetenv("DOCKER_HOST")
+	config := &configfile.ConfigFile{CurrentContext: "other"}
+
+	name, err := resolveContextName(&flags.CommonOptions{Context: DefaultContextName}, config, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("", name))
+
+	defer env.Patch(t, "DOCKER_CONTEXT", DefaultContextName)()
+	name, err = resolveContextName(&flags.CommonOptions{}, config, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("", name))
+}
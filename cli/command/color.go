@@ -0,0 +1,22 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli/i18n"
+	"github.com/docker/cli/cli/streams"
+)
+
+// PrintWarning writes the warning to the error stream of the CLI, prefixed
+// with "WARNING: ", and colored with the color of warnings. The format is
+// translated by the message catalog.
+func PrintWarning(dockerCli Cli, format string, args ...interface{}) {
+	msg := i18n.T("WARNING: ") + i18n.Sprintf(format, args...)
+	fmt.Fprintln(dockerCli.Err(), dockerCli.ColorPolicy().Colorize(dockerCli.Err(), streams.ColorWarning, msg))
+}
+
+// PrintError writes the error to the error stream of the CLI, colored with the
+// color of errors.
+func PrintError(dockerCli Cli, err error) {
+	fmt.Fprintln(dockerCli.Err(), dockerCli.ColorPolicy().Colorize(dockerCli.Err(), streams.ColorError, err.Error()))
+}
@@ -5,9 +5,12 @@ import (
 	"runtime"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/alias"
 	"github.com/docker/cli/cli/command/builder"
 	"github.com/docker/cli/cli/command/checkpoint"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/config"
+	"github.com/docker/cli/cli/command/configfile"
 	"github.com/docker/cli/cli/command/container"
 	"github.com/docker/cli/cli/command/context"
 	"github.com/docker/cli/cli/command/engine"
@@ -16,6 +19,7 @@ import (
 	"github.com/docker/cli/cli/command/network"
 	"github.com/docker/cli/cli/command/node"
 	"github.com/docker/cli/cli/command/plugin"
+	"github.com/docker/cli/cli/command/preset"
 	"github.com/docker/cli/cli/command/registry"
 	"github.com/docker/cli/cli/command/secret"
 	"github.com/docker/cli/cli/command/service"
@@ -30,12 +34,22 @@ import (
 // AddCommands adds all the commands from cli/command to the root command
 func AddCommands(cmd *cobra.Command, dockerCli command.Cli) {
 	cmd.AddCommand(
+		// alias
+		alias.NewAliasCommand(dockerCli),
+
 		// checkpoint
 		checkpoint.NewCheckpointCommand(dockerCli),
 
+		// completion
+		completion.NewCompletionCommand(dockerCli),
+		completion.NewCompleteCommand(dockerCli),
+
 		// config
 		config.NewConfigCommand(dockerCli),
 
+		// config-file
+		configfile.NewConfigFileCommand(dockerCli),
+
 		// container
 		container.NewContainerCommand(dockerCli),
 		container.NewRunCommand(dockerCli),
@@ -59,10 +73,14 @@ func AddCommands(cmd *cobra.Command, dockerCli command.Cli) {
 		// plugin
 		plugin.NewPluginCommand(dockerCli),
 
+		// preset
+		preset.NewPresetCommand(dockerCli),
+
 		// registry
 		registry.NewLoginCommand(dockerCli),
 		registry.NewLogoutCommand(dockerCli),
 		registry.NewSearchCommand(dockerCli),
+		registry.NewRegistryCommand(dockerCli),
 
 		// secret
 		secret.NewSecretCommand(dockerCli),
@@ -73,6 +91,7 @@ func AddCommands(cmd *cobra.Command, dockerCli command.Cli) {
 		// system
 		system.NewSystemCommand(dockerCli),
 		system.NewVersionCommand(dockerCli),
+		system.NewDoctorCommand(dockerCli),
 
 		// stack
 		stack.NewStackCommand(dockerCli),
@@ -0,0 +1,46 @@
+package completion
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+type fakeClient struct {
+	client.Client
+	containerListFunc func(options types.ContainerListOptions) ([]types.Container, error)
+	imageListFunc     func(options types.ImageListOptions) ([]types.ImageSummary, error)
+	networkListFunc   func(options types.NetworkListOptions) ([]types.NetworkResource, error)
+	volumeListFunc    func(filter filters.Args) (volumetypes.VolumeListOKBody, error)
+}
+
+func (c *fakeClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	if c.containerListFunc != nil {
+		return c.containerListFunc(options)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	if c.imageListFunc != nil {
+		return c.imageListFunc(options)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	if c.networkListFunc != nil {
+		return c.networkListFunc(options)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+	if c.volumeListFunc != nil {
+		return c.volumeListFunc(filter)
+	}
+	return volumetypes.VolumeListOKBody{}, nil
+}
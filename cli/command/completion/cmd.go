@@ -0,0 +1,62 @@
+package completion
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// CompleteCommandName is the name of the hidden command which the completion
+// scripts run to complete the command line
+const CompleteCommandName = "__complete"
+
+// NewCompletionCommand returns the completion cli subcommand
+func NewCompletionCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Short: "Generate the completion script of a shell",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	for _, shell := range []struct {
+		name   string
+		script string
+	}{
+		{name: "bash", script: bashScript},
+		{name: "zsh", script: zshScript},
+		{name: "fish", script: fishScript},
+		{name: "powershell", script: powershellScript},
+	} {
+		script := shell.script
+		cmd.AddCommand(&cobra.Command{
+			Use:   shell.name,
+			Short: fmt.Sprintf("Generate the completion script of %s", shell.name),
+			Args:  cli.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				_, err := fmt.Fprint(dockerCli.Out(), script)
+				return err
+			},
+		})
+	}
+	return cmd
+}
+
+// NewCompleteCommand returns the hidden command which writes the candidates
+// to complete the last argument of a command line, such as
+// "docker __complete container stop web", which is run by the completion
+// scripts
+func NewCompleteCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:                CompleteCommandName + " [ARG...] WORD",
+		Short:              "Complete a command line",
+		Hidden:             true,
+		DisableFlagParsing: true,
+		Args:               cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			complete(dockerCli, cmd.Root(), args[:len(args)-1], args[len(args)-1], dockerCli.Out())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,293 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// argValues are the kinds of values of the arguments of the commands, by
+// path. If first is set, only the first argument has the kind of values.
+var argValues = map[string]struct {
+	kind  valueKind
+	first bool
+}{
+	"attach":             {kind: runningContainers, first: true},
+	"checkpoint create":  {kind: runningContainers, first: true},
+	"checkpoint ls":      {kind: containers, first: true},
+	"checkpoint rm":      {kind: containers, first: true},
+	"commit":             {kind: containers, first: true},
+	"container attach":   {kind: runningContainers, first: true},
+	"container commit":   {kind: containers, first: true},
+	"container create":   {kind: images, first: true},
+	"container diff":     {kind: containers, first: true},
+	"container exec":     {kind: runningContainers, first: true},
+	"container export":   {kind: containers, first: true},
+	"container inspect":  {kind: containers},
+	"container kill":     {kind: runningContainers},
+	"container logs":     {kind: containers, first: true},
+	"container pause":    {kind: runningContainers},
+	"container port":     {kind: containers, first: true},
+	"container recreate": {kind: containers, first: true},
+	"container rename":   {kind: containers, first: true},
+	"container restart":  {kind: containers},
+	"container rm":       {kind: containers},
+	"container run":      {kind: images, first: true},
+	"container start":    {kind: containers},
+	"container stats":    {kind: runningContainers},
+	"container stop":     {kind: runningContainers},
+	"container top":      {kind: runningContainers, first: true},
+	"container unpause":  {kind: containers},
+	"container update":   {kind: containers},
+	"container wait":     {kind: containers},
+	"context export":     {kind: contexts, first: true},
+	"context inspect":    {kind: contexts},
+	"context rm":         {kind: contexts},
+	"context update":     {kind: contexts, first: true},
+	"context use":        {kind: contexts, first: true},
+	"create":             {kind: images, first: true},
+	"diff":               {kind: containers, first: true},
+	"exec":               {kind: runningContainers, first: true},
+	"export":             {kind: containers, first: true},
+	"history":            {kind: images, first: true},
+	"image history":      {kind: images, first: true},
+	"image inspect":      {kind: images},
+	"image push":         {kind: images, first: true},
+	"image rm":           {kind: images},
+	"image save":         {kind: images},
+	"image tag":          {kind: images, first: true},
+	"inspect":            {kind: containers},
+	"kill":               {kind: runningContainers},
+	"logs":               {kind: containers, first: true},
+	"network connect":    {kind: networks, first: true},
+	"network disconnect": {kind: networks, first: true},
+	"network inspect":    {kind: networks},
+	"network rm":         {kind: networks},
+	"pause":              {kind: runningContainers},
+	"port":               {kind: containers, first: true},
+	"push":               {kind: images, first: true},
+	"rename":             {kind: containers, first: true},
+	"restart":            {kind: containers},
+	"rm":                 {kind: containers},
+	"rmi":                {kind: images},
+	"run":                {kind: images, first: true},
+	"save":               {kind: images},
+	"start":              {kind: containers},
+	"stats":              {kind: runningContainers},
+	"stop":               {kind: runningContainers},
+	"system inspect":     {kind: containers},
+	"tag":                {kind: images, first: true},
+	"top":                {kind: runningContainers, first: true},
+	"unpause":            {kind: containers},
+	"update":             {kind: containers},
+	"volume inspect":     {kind: volumes},
+	"volume rm":          {kind: volumes},
+	"wait":               {kind: containers},
+}
+
+// flagValues are the kinds of values of the flags, by name
+var flagValues = map[string]valueKind{
+	"context":      contexts,
+	"network":      networks,
+	"volumes-from": containers,
+}
+
+// complete writes the candidates to complete the word toComplete of the
+// command line of the arguments, one per line, with their description after a
+// tab, if any
+func complete(dockerCli command.Cli, root *cobra.Command, args []string, toComplete string, out io.Writer) {
+	cmd, cmdArgs, err := root.Find(args)
+	if err != nil {
+		return
+	}
+	if cmd == root && !strings.HasPrefix(toComplete, "-") {
+		// The plugins are only run to get their metadata when the commands
+		// are completed
+		if err := pluginmanager.AddPluginCommandStubs(dockerCli, root); err == nil {
+			cmd, cmdArgs, _ = root.Find(args)
+		}
+	}
+
+	var candidates []candidate
+	if flagName, _, ok := splitFlagValue(toComplete); ok {
+		if f := lookupFlag(cmd, flagName); f != nil {
+			for _, c := range flagCandidates(dockerCli, f) {
+				c.value = flagName + "=" + c.value
+				candidates = append(candidates, c)
+			}
+		}
+	} else if f := pendingFlag(cmd, cmdArgs); f != nil {
+		candidates = flagCandidates(dockerCli, f)
+	} else if strings.HasPrefix(toComplete, "-") {
+		candidates = flagNames(cmd)
+	} else if cmd.HasAvailableSubCommands() {
+		candidates = subcommandNames(cmd)
+	} else {
+		candidates = argCandidates(dockerCli, cmd, cmdArgs)
+	}
+
+	for _, c := range candidates {
+		if !strings.HasPrefix(c.value, toComplete) {
+			continue
+		}
+		if c.description != "" {
+			fmt.Fprintf(out, "%s\t%s\n", c.value, c.description)
+		} else {
+			fmt.Fprintln(out, c.value)
+		}
+	}
+}
+
+type candidate struct {
+	value       string
+	description string
+}
+
+func valueCandidates(values []string) []candidate {
+	candidates := make([]candidate, 0, len(values))
+	for _, v := range values {
+		candidates = append(candidates, candidate{value: v})
+	}
+	return candidates
+}
+
+// splitFlagValue splits a word such as "--network=host" into the name of the
+// flag and its value
+func splitFlagValue(word string) (string, string, bool) {
+	if !strings.HasPrefix(word, "--") {
+		return "", "", false
+	}
+	i := strings.Index(word, "=")
+	if i < 0 {
+		return "", "", false
+	}
+	return word[:i], word[i+1:], true
+}
+
+// lookupFlag returns the flag of the command or of its parents of a word
+// such as "--network" or "-c"
+func lookupFlag(cmd *cobra.Command, word string) *pflag.Flag {
+	var f *pflag.Flag
+	switch {
+	case strings.HasPrefix(word, "--"):
+		name := strings.TrimPrefix(word, "--")
+		for c := cmd; c != nil && f == nil; c = c.Parent() {
+			f = c.Flags().Lookup(name)
+			if f == nil {
+				f = c.PersistentFlags().Lookup(name)
+			}
+		}
+	case strings.HasPrefix(word, "-") && len(word) == 2:
+		shorthand := word[1:]
+		for c := cmd; c != nil && f == nil; c = c.Parent() {
+			f = c.Flags().ShorthandLookup(shorthand)
+			if f == nil {
+				f = c.PersistentFlags().ShorthandLookup(shorthand)
+			}
+		}
+	}
+	return f
+}
+
+// pendingFlag returns the flag of the last argument, if it requires a value,
+// such as "--network", which is the value to complete
+func pendingFlag(cmd *cobra.Command, args []string) *pflag.Flag {
+	if len(args) == 0 {
+		return nil
+	}
+	f := lookupFlag(cmd, args[len(args)-1])
+	if f == nil || f.NoOptDefVal != "" {
+		return nil
+	}
+	return f
+}
+
+func flagCandidates(dockerCli command.Cli, f *pflag.Flag) []candidate {
+	kind, ok := flagValues[f.Name]
+	if !ok {
+		return nil
+	}
+	return valueCandidates(listValues(dockerCli, kind))
+}
+
+// flagNames returns the flags of the command, and the persistent flags of its
+// parents, which are not hidden
+func flagNames(cmd *cobra.Command) []candidate {
+	var candidates []candidate
+	seen := map[string]bool{}
+	add := func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" || seen[f.Name] {
+			return
+		}
+		seen[f.Name] = true
+		candidates = append(candidates, candidate{value: "--" + f.Name, description: f.Usage})
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			candidates = append(candidates, candidate{value: "-" + f.Shorthand, description: f.Usage})
+		}
+	}
+	cmd.Flags().VisitAll(add)
+	for c := cmd; c != nil; c = c.Parent() {
+		c.PersistentFlags().VisitAll(add)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].value < candidates[j].value })
+	return candidates
+}
+
+func subcommandNames(cmd *cobra.Command) []candidate {
+	var candidates []candidate
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+		if _, invalid := c.Annotations[pluginmanager.CommandAnnotationPluginInvalid]; invalid {
+			continue
+		}
+		candidates = append(candidates, candidate{value: c.Name(), description: c.Short})
+	}
+	return candidates
+}
+
+// argCandidates returns the candidates of the next argument of the command,
+// after the arguments which are already on the command line
+func argCandidates(dockerCli command.Cli, cmd *cobra.Command, args []string) []candidate {
+	if len(cmd.ValidArgs) > 0 {
+		return valueCandidates(cmd.ValidArgs)
+	}
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	values, ok := argValues[path]
+	if !ok || values.kind == "" {
+		return nil
+	}
+	if values.first && len(positionalArgs(cmd, args)) > 0 {
+		return nil
+	}
+	return valueCandidates(listValues(dockerCli, values.kind))
+}
+
+// positionalArgs returns the arguments which are not flags, or their values
+func positionalArgs(cmd *cobra.Command, args []string) []string {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(positional, args[i+1:]...)
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		if f := lookupFlag(cmd, arg); f != nil && f.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return positional
+}
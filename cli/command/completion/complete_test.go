@@ -0,0 +1,211 @@
+package completion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func newTestRootCommand(dockerCli command.Cli) *cobra.Command {
+	run := func(*cobra.Command, []string) {}
+	root := &cobra.Command{Use: "docker"}
+	root.PersistentFlags().StringP("context", "c", "", "Name of the context to use")
+	root.Flags().BoolP("debug", "D", false, "Enable debug mode")
+
+	containerCmd := &cobra.Command{Use: "container", Short: "Manage containers"}
+	stop := &cobra.Command{Use: "stop", Short: "Stop one or more running containers", Run: run}
+	stop.Flags().IntP("time", "t", 10, "Seconds to wait for stop before killing it")
+	start := &cobra.Command{Use: "start", Short: "Start one or more stopped containers", Run: run}
+	containerCmd.AddCommand(stop, start)
+
+	runCmd := &cobra.Command{Use: "run", Short: "Run a command in a new container", Run: run}
+	runCmd.Flags().String("network", "default", "Connect a container to a network")
+	runCmd.Flags().Bool("rm", false, "Automatically remove the container when it exits")
+	runCmd.Flags().String("secret-flag", "", "Hidden flag")
+	runCmd.Flags().MarkHidden("secret-flag")
+
+	volumeCmd := &cobra.Command{Use: "volume", Short: "Manage volumes"}
+	volumeCmd.AddCommand(&cobra.Command{Use: "rm", Short: "Remove one or more volumes", Run: run})
+
+	root.AddCommand(containerCmd, runCmd, volumeCmd, NewCompleteCommand(dockerCli))
+	return root
+}
+
+// newTestCli returns a CLI whose configuration directory, where the values
+// are cached, is a temporary directory, with the "production" context
+func newTestCli(t *testing.T, apiClient *fakeClient) (*test.FakeCli, func()) {
+	dir := fs.NewDir(t, "completion")
+	configDir := config.Dir()
+	config.SetDir(dir.Path())
+	cleanup := func() {
+		config.SetDir(configDir)
+		dir.Remove()
+	}
+
+	contextStore := store.New(dir.Join("contexts"), store.NewConfig(func() interface{} { return &command.DockerContext{} }))
+	assert.NilError(t, contextStore.CreateOrUpdateContext(store.ContextMetadata{Name: "production", Metadata: command.DockerContext{}}))
+	cli := test.NewFakeCli(apiClient)
+	cli.SetContextStore(contextStore)
+	cli.SetCurrentContext("default")
+	return cli, cleanup
+}
+
+func runComplete(dockerCli command.Cli, args ...string) string {
+	out := new(bytes.Buffer)
+	complete(dockerCli, newTestRootCommand(dockerCli), args[:len(args)-1], args[len(args)-1], out)
+	return out.String()
+}
+
+func TestComplete(t *testing.T) {
+	cli, cleanup := newTestCli(t, &fakeClient{
+		containerListFunc: func(options types.ContainerListOptions) ([]types.Container, error) {
+			if !options.All {
+				return []types.Container{{Names: []string{"/web"}}}, nil
+			}
+			return []types.Container{{Names: []string{"/web"}}, {Names: []string{"/db", "/web/db"}}}, nil
+		},
+		imageListFunc: func(options types.ImageListOptions) ([]types.ImageSummary, error) {
+			return []types.ImageSummary{{RepoTags: []string{"nginx:latest", "nginx:1.17"}}, {RepoTags: []string{"<none>:<none>"}}}, nil
+		},
+		networkListFunc: func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+			return []types.NetworkResource{{Name: "bridge"}, {Name: "host"}}, nil
+		},
+		volumeListFunc: func(filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+			return volumetypes.VolumeListOKBody{Volumes: []*types.Volume{{Name: "data"}}}, nil
+		},
+	})
+	defer cleanup()
+
+	testCases := []struct {
+		doc      string
+		args     []string
+		expected string
+	}{
+		{
+			doc:      "subcommands",
+			args:     []string{""},
+			expected: "container\tManage containers\nrun\tRun a command in a new container\nvolume\tManage volumes\n",
+		},
+		{
+			doc:      "subcommands with prefix",
+			args:     []string{"container", "st"},
+			expected: "start\tStart one or more stopped containers\nstop\tStop one or more running containers\n",
+		},
+		{
+			doc:      "flags",
+			args:     []string{"run", "--"},
+			expected: "--context\tName of the context to use\n--network\tConnect a container to a network\n--rm\tAutomatically remove the container when it exits\n",
+		},
+		{
+			doc:      "shorthand flags",
+			args:     []string{"container", "stop", "-"},
+			expected: "--context\tName of the context to use\n--time\tSeconds to wait for stop before killing it\n-c\tName of the context to use\n-t\tSeconds to wait for stop before killing it\n",
+		},
+		{
+			doc:      "running containers",
+			args:     []string{"container", "stop", ""},
+			expected: "web\n",
+		},
+		{
+			doc:      "all containers",
+			args:     []string{"container", "start", "web", ""},
+			expected: "db\nweb\n",
+		},
+		{
+			doc:      "images",
+			args:     []string{"run", "--rm", "ng"},
+			expected: "nginx:1.17\nnginx:latest\n",
+		},
+		{
+			doc:  "command of run",
+			args: []string{"run", "--network", "host", "nginx", ""},
+		},
+		{
+			doc:      "value of flag",
+			args:     []string{"run", "--network", ""},
+			expected: "bridge\nhost\n",
+		},
+		{
+			doc:      "value of flag after equal sign",
+			args:     []string{"run", "--network=h"},
+			expected: "--network=host\n",
+		},
+		{
+			doc:      "contexts",
+			args:     []string{"-c", ""},
+			expected: "default\nproduction\n",
+		},
+		{
+			doc:      "volumes",
+			args:     []string{"volume", "rm", ""},
+			expected: "data\n",
+		},
+		{
+			doc:  "value of flag without completion",
+			args: []string{"container", "stop", "--time", ""},
+		},
+		{
+			doc:  "unknown command",
+			args: []string{"unknown", ""},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			assert.Check(t, is.Equal(tc.expected, runComplete(cli, tc.args...)))
+		})
+	}
+}
+
+func TestCompleteCache(t *testing.T) {
+	apiClient := &fakeClient{
+		volumeListFunc: func(filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+			return volumetypes.VolumeListOKBody{Volumes: []*types.Volume{{Name: "data"}}}, nil
+		},
+	}
+	cli, cleanup := newTestCli(t, apiClient)
+	defer cleanup()
+	assert.Check(t, is.Equal("data\n", runComplete(cli, "volume", "rm", "")))
+
+	apiClient.volumeListFunc = func(filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+		return volumetypes.VolumeListOKBody{}, errors.New("the daemon is not called")
+	}
+	assert.Check(t, is.Equal("data\n", runComplete(cli, "volume", "rm", "")))
+
+	defer func(ttl time.Duration) { cacheTTL = ttl }(cacheTTL)
+	cacheTTL = 0
+	assert.Check(t, is.Equal("", runComplete(cli, "volume", "rm", "")))
+}
+
+func TestCompleteCommand(t *testing.T) {
+	cli, cleanup := newTestCli(t, &fakeClient{})
+	defer cleanup()
+	cmd := newTestRootCommand(cli)
+	cmd.SetArgs([]string{CompleteCommandName, "container", "--context", "production", "sto"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("stop\tStop one or more running containers\n", cli.OutBuffer().String()))
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		cli := test.NewFakeCli(&fakeClient{})
+		cmd := NewCompletionCommand(cli)
+		cmd.SetArgs([]string{shell})
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, strings.Contains(cli.OutBuffer().String(), `generated by "docker completion `+shell+`"`))
+		assert.Check(t, strings.Contains(cli.OutBuffer().String(), "docker __complete"))
+	}
+}
@@ -0,0 +1,115 @@
+package completion
+
+// The completion scripts run "docker __complete" with the words of the
+// command line before the cursor, and the word to complete, which may be
+// empty. The candidates are written one per line, with their description
+// after a tab, if any. The shells complete file names if there are no
+// candidates.
+
+const bashScript = `# bash completion for docker, generated by "docker completion bash"
+
+__docker_complete() {
+	local cur words cword
+	if declare -F _get_comp_words_by_ref >/dev/null; then
+		_get_comp_words_by_ref -n =: cur words cword
+	else
+		cur=${COMP_WORDS[COMP_CWORD]}
+		words=("${COMP_WORDS[@]}")
+		cword=$COMP_CWORD
+	fi
+
+	local IFS=$'\n'
+	local candidates
+	candidates=($(command docker __complete "${words[@]:1:cword-1}" "$cur" 2>/dev/null | cut -f1))
+	COMPREPLY=("${candidates[@]}")
+
+	# Strip the part of the candidates which bash considers to be previous
+	# words, such as "--network=" of "--network=host", or "nginx:" of
+	# "nginx:latest"
+	if [[ $cur == *=* && $COMP_WORDBREAKS == *=* ]]; then
+		local prefix=${cur%"${cur##*=}"}
+		COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+	fi
+	if declare -F __ltrim_colon_completions >/dev/null; then
+		__ltrim_colon_completions "$cur"
+	fi
+}
+
+complete -o default -F __docker_complete docker
+`
+
+const zshScript = `#compdef docker
+# zsh completion for docker, generated by "docker completion zsh"
+
+_docker() {
+	local -a candidates
+	local line value
+	for line in "${(@f)$(command docker __complete "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)}"; do
+		[[ -z $line ]] && continue
+		value=${line%%$'\t'*}
+		if [[ $line == *$'\t'* ]]; then
+			candidates+=("${value//:/\\:}:${line#*$'\t'}")
+		else
+			candidates+=("${value//:/\\:}")
+		fi
+	done
+	if (( ${#candidates} )); then
+		_describe -t docker 'docker' candidates
+	else
+		_files
+	fi
+}
+
+if [ "$funcstack[1]" = "_docker" ]; then
+	_docker "$@"
+else
+	compdef _docker docker
+fi
+`
+
+const fishScript = `# fish completion for docker, generated by "docker completion fish"
+
+function __docker_complete
+	set -l args (commandline -opc)
+	set -e args[1]
+	set -l candidates (command docker __complete $args (commandline -ct) 2>/dev/null)
+	if test (count $candidates) -eq 0
+		__fish_complete_path (commandline -ct)
+	else
+		printf '%s\n' $candidates
+	end
+end
+
+complete -c docker -e
+complete -c docker -f -a '(__docker_complete)'
+`
+
+const powershellScript = `# powershell completion for docker, generated by "docker completion powershell"
+
+Register-ArgumentCompleter -Native -CommandName docker -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+
+	$words = @($commandAst.CommandElements |
+		Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+		Select-Object -Skip 1 |
+		ForEach-Object { "'" + ($_.ToString() -replace "'", "''") + "'" })
+	$word = "'" + ($wordToComplete -replace "'", "''") + "'"
+	if ($wordToComplete -eq '') {
+		# Empty arguments are only passed to native commands if quoted
+		$word = '""'
+	}
+
+	$PSNativeCommandArgumentPassing = 'Legacy'
+	$candidates = Invoke-Expression "docker __complete $($words -join ' ') $word 2>` + "`" + `$null"
+	if (-not $candidates) {
+		return
+	}
+	foreach ($line in $candidates) {
+		$value, $description = $line -split "` + "`" + `t", 2
+		if (-not $description) {
+			$description = $value
+		}
+		[System.Management.Automation.CompletionResult]::new($value, $value, 'ParameterValue', $description)
+	}
+}
+`
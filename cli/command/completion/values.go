@@ -0,0 +1,158 @@
+package completion
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+)
+
+// valueKind is a kind of values which are completed, such as the names of
+// the containers
+type valueKind string
+
+const (
+	containers        valueKind = "containers"
+	runningContainers valueKind = "running-containers"
+	images            valueKind = "images"
+	contexts          valueKind = "contexts"
+	networks          valueKind = "networks"
+	volumes           valueKind = "volumes"
+)
+
+var (
+	// apiTimeout is the timeout of the API calls which list the values to
+	// complete, so that completing never blocks the shell for long
+	apiTimeout = 2 * time.Second
+	// cacheTTL is the duration during which the values listed with the API
+	// are cached, as completing a command line usually takes several tabs
+	cacheTTL = 10 * time.Second
+)
+
+// listValues returns the values of the kind, from the cache if they were
+// listed recently. The values of the API are not completed on errors.
+func listValues(dockerCli command.Cli, kind valueKind) []string {
+	if kind == contexts {
+		values, err := listContexts(dockerCli)
+		if err != nil {
+			logrus.Debug(err)
+		}
+		return values
+	}
+
+	cacheFile := filepath.Join(config.Dir(), "completion", dockerCli.CurrentContext(), string(kind))
+	if values, ok := readCache(cacheFile); ok {
+		return values
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+	values, err := listAPIValues(ctx, dockerCli, kind)
+	if err != nil {
+		logrus.Debug(err)
+		return nil
+	}
+	sort.Strings(values)
+	writeCache(cacheFile, values)
+	return values
+}
+
+func listContexts(dockerCli command.Cli) ([]string, error) {
+	contextMap, err := dockerCli.ContextStore().ListContexts()
+	if err != nil {
+		return nil, err
+	}
+	values := []string{"default"}
+	for _, c := range contextMap {
+		values = append(values, c.Name)
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+func listAPIValues(ctx context.Context, dockerCli command.Cli, kind valueKind) ([]string, error) {
+	var values []string
+	switch kind {
+	case containers, runningContainers:
+		options := types.ContainerListOptions{All: kind == containers}
+		list, err := dockerCli.Client().ContainerList(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range list {
+			for _, name := range c.Names {
+				// Only the names of the containers, not of their links,
+				// such as "/web/db"
+				if name = strings.TrimPrefix(name, "/"); !strings.Contains(name, "/") {
+					values = append(values, name)
+				}
+			}
+		}
+	case images:
+		list, err := dockerCli.Client().ImageList(ctx, types.ImageListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range list {
+			for _, tag := range img.RepoTags {
+				if tag != "<none>:<none>" {
+					values = append(values, tag)
+				}
+			}
+		}
+	case networks:
+		list, err := dockerCli.Client().NetworkList(ctx, types.NetworkListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range list {
+			values = append(values, n.Name)
+		}
+	case volumes:
+		list, err := dockerCli.Client().VolumeList(ctx, filters.NewArgs())
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range list.Volumes {
+			values = append(values, v.Name)
+		}
+	}
+	return values, nil
+}
+
+func readCache(filename string) ([]string, bool) {
+	fi, err := os.Stat(filename)
+	if err != nil || time.Since(fi.ModTime()) > cacheTTL {
+		return nil, false
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+	if len(content) == 0 {
+		return []string{}, true
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), true
+}
+
+func writeCache(filename string, values []string) {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		logrus.Debug(err)
+		return
+	}
+	content := ""
+	if len(values) > 0 {
+		content = strings.Join(values, "\n") + "\n"
+	}
+	if err := ioutil.WriteFile(filename, []byte(content), 0600); err != nil {
+		logrus.Debug(err)
+	}
+}
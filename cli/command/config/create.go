@@ -8,6 +8,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/secretprovider"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/pkg/system"
@@ -20,6 +21,7 @@ type CreateOptions struct {
 	Name           string
 	TemplateDriver string
 	File           string
+	Source         secretprovider.Source
 	Labels         opts.ListOpts
 }
 
@@ -29,12 +31,21 @@ func newConfigCreateCommand(dockerCli command.Cli) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "create [OPTIONS] CONFIG file|-",
-		Short: "Create a config from a file or STDIN",
-		Args:  cli.ExactArgs(2),
+		Use:   "create [OPTIONS] CONFIG [file|-]",
+		Short: "Create a config from a file, STDIN, or a secret provider",
+		Args: func(cmd *cobra.Command, args []string) error {
+			// The content of the config is not a file when it is read from
+			// a secret provider
+			if createOpts.Source.IsSet() {
+				return cli.ExactArgs(1)(cmd, args)
+			}
+			return cli.ExactArgs(2)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			createOpts.Name = args[0]
-			createOpts.File = args[1]
+			if len(args) == 2 {
+				createOpts.File = args[1]
+			}
 			return RunConfigCreate(dockerCli, createOpts)
 		},
 	}
@@ -42,6 +53,7 @@ func newConfigCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.VarP(&createOpts.Labels, "label", "l", "Config labels")
 	flags.StringVar(&createOpts.TemplateDriver, "template-driver", "", "Template driver")
 	flags.SetAnnotation("driver", "version", []string{"1.37"})
+	secretprovider.AddFlags(flags, &createOpts.Source, "config")
 
 	return cmd
 }
@@ -51,19 +63,9 @@ func RunConfigCreate(dockerCli command.Cli, options CreateOptions) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 
-	var in io.Reader = dockerCli.In()
-	if options.File != "-" {
-		file, err := system.OpenSequential(options.File)
-		if err != nil {
-			return err
-		}
-		in = file
-		defer file.Close()
-	}
-
-	configData, err := ioutil.ReadAll(in)
+	configData, err := readConfigData(dockerCli.In(), options)
 	if err != nil {
-		return errors.Errorf("Error reading content from %q: %v", options.File, err)
+		return err
 	}
 
 	spec := swarm.ConfigSpec{
@@ -86,3 +88,26 @@ func RunConfigCreate(dockerCli command.Cli, options CreateOptions) error {
 	fmt.Fprintln(dockerCli.Out(), r.ID)
 	return nil
 }
+
+// readConfigData returns the content of the config, from its secret provider,
+// or from its file or STDIN
+func readConfigData(in io.Reader, options CreateOptions) ([]byte, error) {
+	if options.Source.IsSet() {
+		return options.Source.Get()
+	}
+
+	if options.File != "-" {
+		file, err := system.OpenSequential(options.File)
+		if err != nil {
+			return nil, err
+		}
+		in = file
+		defer file.Close()
+	}
+
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, errors.Errorf("Error reading content from %q: %v", options.File, err)
+	}
+	return data, nil
+}
@@ -31,6 +31,10 @@ func TestConfigCreateErrors(t *testing.T) {
 		{args: []string{"too", "many", "arguments"},
 			expectedError: "requires exactly 2 arguments",
 		},
+		{
+			args:          []string{"--from-vault", "secret/data/app", "name", "file"},
+			expectedError: "requires exactly 1 argument",
+		},
 		{
 			args: []string{"name", filepath.Join("testdata", configDataFile)},
 			configCreateFunc: func(configSpec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
@@ -61,7 +61,7 @@ func RunConfigInspect(dockerCli command.Cli, opts InspectOptions) error {
 	}
 
 	if err := InspectFormatWrite(configCtx, opts.Names, getRef); err != nil {
-		return cli.StatusError{StatusCode: 1, Status: err.Error()}
+		return cli.NewStatusError(err)
 	}
 	return nil
 
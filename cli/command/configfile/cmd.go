@@ -0,0 +1,21 @@
+package configfile
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigFileCommand returns the config-file cli subcommand
+func NewConfigFileCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config-file",
+		Short: "Manage the configuration file of the CLI",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newShowCommand(dockerCli),
+	)
+	return cmd
+}
@@ -0,0 +1,56 @@
+package configfile
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/redact"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type showOptions struct {
+	resolved bool
+}
+
+func newShowCommand(dockerCli command.Cli) *cobra.Command {
+	var options showOptions
+
+	cmd := &cobra.Command{
+		Use:   "show [OPTIONS]",
+		Short: "Show the configuration file",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShow(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.resolved, "resolved", false, "Show the configuration with the properties of the files it includes")
+	return cmd
+}
+
+func runShow(dockerCli command.Cli, options showOptions) error {
+	filename := dockerCli.ConfigFile().Filename
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return errors.Errorf("there is no configuration file at %s", filename)
+	}
+	content, resolved, err := config.ResolveFile(filename)
+	if err != nil {
+		return err
+	}
+	if options.resolved {
+		content = resolved
+	}
+	// The credentials, such as the passwords, and the secrets of the plugins
+	// and the proxies are not shown
+	redact.Value(content)
+
+	enc := json.NewEncoder(dockerCli.Out())
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	return enc.Encode(content)
+}
@@ -0,0 +1,56 @@
+package configfile
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestShow(t *testing.T) {
+	dir := fs.NewDir(t, "config-test",
+		fs.WithFile("base.json", `{"psFormat": "{{.ID}}", "auths": {"example.com": {"auth": "dXNlcjpwYXNz"}}}`),
+		fs.WithFile("config.json", `{"include": "base.json", "detachKeys": "ctrl-e,e"}`),
+	)
+	defer dir.Remove()
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(configfile.New(dir.Join("config.json")))
+
+	cmd := newShowCommand(cli)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`{
+	"detachKeys": "ctrl-e,e",
+	"include": "base.json"
+}
+`, cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	cmd = newShowCommand(cli)
+	cmd.SetArgs([]string{"--resolved"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`{
+	"auths": {
+		"example.com": {
+			"auth": "<redacted>"
+		}
+	},
+	"detachKeys": "ctrl-e,e",
+	"psFormat": "{{.ID}}"
+}
+`, cli.OutBuffer().String()))
+}
+
+func TestShowNoFile(t *testing.T) {
+	dir := fs.NewDir(t, "config-test")
+	defer dir.Remove()
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(configfile.New(dir.Join("config.json")))
+
+	cmd := newShowCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "there is no configuration file at "+dir.Join("config.json")))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
@@ -21,6 +22,8 @@ type attachOptions struct {
 	noStdin    bool
 	proxy      bool
 	detachKeys string
+	record     string
+	transport  string
 
 	container string
 }
@@ -61,11 +64,13 @@ func NewAttachCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.noStdin, "no-stdin", false, "Do not attach STDIN")
 	flags.BoolVar(&opts.proxy, "sig-proxy", true, "Proxy all received signals to the process")
 	flags.StringVar(&opts.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.StringVar(&opts.record, "record", "", "Record the session to a file, in the asciicast v2 format")
+	addAttachTransportFlag(flags, &opts.transport)
 	return cmd
 }
 
 func runAttach(dockerCli command.Cli, opts *attachOptions) error {
-	ctx := context.Background()
+	ctx := dockerCli.Context()
 	client := dockerCli.Client()
 
 	// request channel to wait for client
@@ -100,9 +105,10 @@ func runAttach(dockerCli command.Cli, opts *attachOptions) error {
 	if opts.proxy && !c.Config.Tty {
 		sigc := ForwardAllSignals(ctx, dockerCli, opts.container)
 		defer signal.StopCatch(sigc)
+		defer command.IgnoreCancelSignals()()
 	}
 
-	resp, errAttach := client.ContainerAttach(ctx, opts.container, options)
+	resp, raw, errAttach := containerAttach(ctx, dockerCli, opts.transport, opts.container, options)
 	if errAttach != nil && errAttach != httputil.ErrPersistEOF {
 		// ContainerAttach returns an ErrPersistEOF (connection closed)
 		// means server met an error and put it in Hijacked connection
@@ -128,6 +134,16 @@ func runAttach(dockerCli command.Cli, opts *attachOptions) error {
 		resizeTTY(ctx, dockerCli, opts.container)
 	}
 
+	var recorder *streams.Recorder
+	if opts.record != "" {
+		var closeRecording func()
+		recorder, closeRecording, err = newSessionRecorder(dockerCli, opts.record)
+		if err != nil {
+			return err
+		}
+		defer closeRecording()
+	}
+
 	streamer := hijackedIOStreamer{
 		streams:      dockerCli,
 		inputStream:  in,
@@ -136,6 +152,8 @@ func runAttach(dockerCli command.Cli, opts *attachOptions) error {
 		resp:         resp,
 		tty:          c.Config.Tty,
 		detachKeys:   options.DetachKeys,
+		raw:          raw,
+		recorder:     recorder,
 	}
 
 	if err := streamer.stream(ctx); err != nil {
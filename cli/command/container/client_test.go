@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -29,6 +30,12 @@ type fakeClient struct {
 	containerListFunc       func(types.ContainerListOptions) ([]types.Container, error)
 	containerExportFunc     func(string) (io.ReadCloser, error)
 	containerExecResizeFunc func(id string, options types.ResizeOptions) error
+	imageInspectFunc        func(image string) (types.ImageInspect, []byte, error)
+	checkpointListFunc      func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error)
+	containerStopFunc       func(container string, timeout *time.Duration) error
+	containerRenameFunc     func(container, newContainerName string) error
+	containerRemoveFunc     func(container string, options types.ContainerRemoveOptions) error
+	networkConnectFunc      func(network, container string, config *network.EndpointSettings) error
 	Version                 string
 }
 
@@ -39,6 +46,8 @@ func (f *fakeClient) ContainerList(_ context.Context, options types.ContainerLis
 	return []types.Container{}, nil
 }
 
+func (f *fakeClient) NegotiateAPIVersion(ctx context.Context) {}
+
 func (f *fakeClient) ContainerInspect(_ context.Context, containerID string) (types.ContainerJSON, error) {
 	if f.inspectFunc != nil {
 		return f.inspectFunc(containerID)
@@ -123,6 +132,13 @@ func (f *fakeClient) ContainerWait(_ context.Context, container string, _ contai
 	return nil, nil
 }
 
+func (f *fakeClient) CheckpointList(_ context.Context, container string, options types.CheckpointListOptions) ([]types.Checkpoint, error) {
+	if f.checkpointListFunc != nil {
+		return f.checkpointListFunc(container, options)
+	}
+	return nil, nil
+}
+
 func (f *fakeClient) ContainerStart(_ context.Context, container string, options types.ContainerStartOptions) error {
 	if f.containerStartFunc != nil {
 		return f.containerStartFunc(container, options)
@@ -143,3 +159,38 @@ func (f *fakeClient) ContainerExecResize(_ context.Context, id string, options t
 	}
 	return nil
 }
+
+func (f *fakeClient) ImageInspectWithRaw(_ context.Context, image string) (types.ImageInspect, []byte, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(image)
+	}
+	return types.ImageInspect{}, nil, nil
+}
+
+func (f *fakeClient) ContainerStop(_ context.Context, container string, timeout *time.Duration) error {
+	if f.containerStopFunc != nil {
+		return f.containerStopFunc(container, timeout)
+	}
+	return nil
+}
+
+func (f *fakeClient) ContainerRename(_ context.Context, container, newContainerName string) error {
+	if f.containerRenameFunc != nil {
+		return f.containerRenameFunc(container, newContainerName)
+	}
+	return nil
+}
+
+func (f *fakeClient) ContainerRemove(_ context.Context, container string, options types.ContainerRemoveOptions) error {
+	if f.containerRemoveFunc != nil {
+		return f.containerRemoveFunc(container, options)
+	}
+	return nil
+}
+
+func (f *fakeClient) NetworkConnect(_ context.Context, network, container string, config *network.EndpointSettings) error {
+	if f.networkConnectFunc != nil {
+		return f.networkConnectFunc(network, container, config)
+	}
+	return nil
+}
@@ -26,14 +26,17 @@ func NewContainerCommand(dockerCli command.Cli) *cobra.Command {
 		NewLogsCommand(dockerCli),
 		NewPauseCommand(dockerCli),
 		NewPortCommand(dockerCli),
+		NewRecreateCommand(dockerCli),
 		NewRenameCommand(dockerCli),
 		NewRestartCommand(dockerCli),
+		NewRestoreCommand(dockerCli),
 		NewRmCommand(dockerCli),
 		NewRunCommand(dockerCli),
 		NewStartCommand(dockerCli),
 		NewStatsCommand(dockerCli),
 		NewStopCommand(dockerCli),
 		NewTopCommand(dockerCli),
+		NewToComposeCommand(dockerCli),
 		NewUnpauseCommand(dockerCli),
 		NewUpdateCommand(dockerCli),
 		NewWaitCommand(dockerCli),
@@ -26,6 +26,8 @@ type createOptions struct {
 	name      string
 	platform  string
 	untrusted bool
+	dryRun    dryRunOptions
+	presets   []string
 }
 
 // NewCreateCommand creates a new cobra.Command for `docker create`
@@ -50,6 +52,8 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetInterspersed(false)
 
 	flags.StringVar(&opts.name, "name", "", "Assign a name to the container")
+	addDryRunFlags(flags, &opts.dryRun)
+	addPresetFlag(flags, &opts.presets)
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -62,6 +66,14 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, options *createOptions, copts *containerOptions) error {
+	if err := checkPresetsExpanded(options.presets); err != nil {
+		return err
+	}
+	if options.dryRun.enabled {
+		if err := options.dryRun.validate(); err != nil {
+			return err
+		}
+	}
 	proxyConfig := dockerCli.ConfigFile().ParseProxyConfig(dockerCli.Client().DaemonHost(), opts.ConvertKVStringsToMapWithNil(copts.env.GetAll()))
 	newEnv := []string{}
 	for k, v := range proxyConfig {
@@ -81,6 +93,9 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, options *createOptio
 		reportError(dockerCli.Err(), "create", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
 	}
+	if options.dryRun.enabled {
+		return printCreateRequest(dockerCli.Out(), options.dryRun.format, containerConfig, options)
+	}
 	response, err := createContainer(context.Background(), dockerCli, containerConfig, options)
 	if err != nil {
 		return err
@@ -89,6 +104,22 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, options *createOptio
 	return nil
 }
 
+// addPresetFlag adds the --preset flag, which is replaced with the flags of
+// its preset by the docker command, before the flags are parsed
+func addPresetFlag(flags *pflag.FlagSet, presets *[]string) {
+	flags.StringArrayVar(presets, opts.PresetFlag, nil, "Apply the flags of a preset of the configuration file")
+}
+
+// checkPresetsExpanded returns an error if the --preset flag was not
+// replaced with the flags of its preset, such as when the flags are not parsed
+// by the docker command
+func checkPresetsExpanded(presets []string) error {
+	if len(presets) > 0 {
+		return errors.Errorf("preset %q was not expanded: presets are only supported by the docker command", presets[0])
+	}
+	return nil
+}
+
 func pullImage(ctx context.Context, dockerCli command.Cli, image string, platform string, out io.Writer) error {
 	ref, err := reference.ParseNormalizedNamed(image)
 	if err != nil {
@@ -181,8 +212,8 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig
 	networkingConfig := containerConfig.NetworkingConfig
 	stderr := dockerCli.Err()
 
-	warnOnOomKillDisable(*hostConfig, stderr)
-	warnOnLocalhostDNS(*hostConfig, stderr)
+	warnOnOomKillDisable(dockerCli, *hostConfig)
+	warnOnLocalhostDNS(dockerCli, *hostConfig)
 
 	var (
 		trustedRef reference.Canonical
@@ -241,24 +272,24 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerConfig
 	}
 
 	for _, warning := range response.Warnings {
-		fmt.Fprintf(stderr, "WARNING: %s\n", warning)
+		command.PrintWarning(dockerCli, "%s", warning)
 	}
 	err = containerIDFile.Write(response.ID)
 	return &response, err
 }
 
-func warnOnOomKillDisable(hostConfig container.HostConfig, stderr io.Writer) {
+func warnOnOomKillDisable(dockerCli command.Cli, hostConfig container.HostConfig) {
 	if hostConfig.OomKillDisable != nil && *hostConfig.OomKillDisable && hostConfig.Memory == 0 {
-		fmt.Fprintln(stderr, "WARNING: Disabling the OOM killer on containers without setting a '-m/--memory' limit may be dangerous.")
+		command.PrintWarning(dockerCli, "Disabling the OOM killer on containers without setting a '-m/--memory' limit may be dangerous.")
 	}
 }
 
 // check the DNS settings passed via --dns against localhost regexp to warn if
 // they are trying to set a DNS to a localhost address
-func warnOnLocalhostDNS(hostConfig container.HostConfig, stderr io.Writer) {
+func warnOnLocalhostDNS(dockerCli command.Cli, hostConfig container.HostConfig) {
 	for _, dnsIP := range hostConfig.DNS {
 		if isLocalhost(dnsIP) {
-			fmt.Fprintf(stderr, "WARNING: Localhost DNS setting (--dns=%s) may fail in containers.\n", dnsIP)
+			command.PrintWarning(dockerCli, "Localhost DNS setting (--dns=%s) may fail in containers.", dnsIP)
 			return
 		}
 	}
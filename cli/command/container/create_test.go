@@ -2,6 +2,7 @@ package container
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -274,6 +275,50 @@ func TestCreateContainerWithProxyConfig(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestCreateContainerDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error) {
+			t.Fatal("the daemon should not be called with --dry-run")
+			return container.ContainerCreateCreatedBody{}, nil
+		},
+	})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		Proxies: map[string]configfile.ProxyConfig{"default": {HTTPProxy: "httpProxy"}},
+	})
+	cmd := NewCreateCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--dry-run", "--name", "web", "-e", "FOO=bar", "-v", "/data", "--memory", "64m", "busybox", "top"})
+	assert.NilError(t, cmd.Execute())
+
+	var req createRequest
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &req))
+	assert.Check(t, is.Equal("web", req.Name))
+	assert.Check(t, is.Equal("busybox", req.Config.Image))
+	assert.Check(t, is.DeepEqual([]string{"top"}, []string(req.Config.Cmd)))
+	sort.Strings(req.Config.Env)
+	assert.Check(t, is.DeepEqual([]string{"FOO=bar", "HTTP_PROXY=httpProxy", "http_proxy=httpProxy"}, req.Config.Env))
+	assert.Check(t, is.DeepEqual(map[string]struct{}{"/data": {}}, req.Config.Volumes))
+	assert.Check(t, is.Equal(int64(64*1024*1024), req.HostConfig.Memory))
+}
+
+func TestCreateContainerDryRunYAML(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := NewCreateCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--dry-run", "--dry-run-format", "yaml", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "  Image: busybox\n"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "HostConfig:\n"))
+}
+
+func TestCreateContainerDryRunInvalidFormat(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := NewCreateCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--dry-run", "--dry-run-format", "toml", "busybox"})
+	assert.ErrorContains(t, cmd.Execute(), `invalid --dry-run-format "toml"`)
+}
+
 type fakeNotFound struct{}
 
 func (f fakeNotFound) NotFound() bool { return true }
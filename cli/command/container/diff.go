@@ -43,5 +43,8 @@ func runDiff(dockerCli command.Cli, opts *diffOptions) error {
 		Output: dockerCli.Out(),
 		Format: NewDiffFormat("{{.Type}} {{.Path}}"),
 	}
-	return DiffFormatWrite(diffCtx, changes)
+	colorize := func(role, s string) string {
+		return dockerCli.ColorPolicy().Colorize(dockerCli.Out(), role, s)
+	}
+	return diffFormatWrite(diffCtx, changes, colorize)
 }
@@ -0,0 +1,75 @@
+package container
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	networktypes "github.com/docker/docker/api/types/network"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	dryRunFormatJSON = "json"
+	dryRunFormatYAML = "yaml"
+)
+
+// dryRunOptions are the options for printing the create request instead of
+// creating the container.
+type dryRunOptions struct {
+	enabled bool
+	format  string
+}
+
+func addDryRunFlags(flags *pflag.FlagSet, opts *dryRunOptions) {
+	flags.BoolVar(&opts.enabled, "dry-run", false, "Print the resolved create request without creating the container")
+	flags.StringVar(&opts.format, "dry-run-format", dryRunFormatJSON, "Format of the --dry-run output (json, yaml)")
+}
+
+func (opts *dryRunOptions) validate() error {
+	switch opts.format {
+	case dryRunFormatJSON, dryRunFormatYAML:
+		return nil
+	default:
+		return errors.Errorf("invalid --dry-run-format %q: must be one of json or yaml", opts.format)
+	}
+}
+
+// createRequest is the request that would be sent to the daemon to create
+// a container. Field names match the container create API.
+type createRequest struct {
+	Name             string `json:",omitempty"`
+	Platform         string `json:",omitempty"`
+	Config           *container.Config
+	HostConfig       *container.HostConfig
+	NetworkingConfig *networktypes.NetworkingConfig
+}
+
+// printCreateRequest prints the create request for the given configuration
+// in the requested format, without contacting the daemon.
+func printCreateRequest(out io.Writer, format string, containerConfig *containerConfig, opts *createOptions) error {
+	req := createRequest{
+		Name:             opts.name,
+		Platform:         opts.platform,
+		Config:           containerConfig.Config,
+		HostConfig:       containerConfig.HostConfig,
+		NetworkingConfig: containerConfig.NetworkingConfig,
+	}
+	var (
+		b   []byte
+		err error
+	)
+	if format == dryRunFormatYAML {
+		b, err = yaml.Marshal(req)
+	} else {
+		b, err = json.MarshalIndent(req, "", "    ")
+		b = append(b, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	apiclient "github.com/docker/docker/client"
@@ -27,6 +28,7 @@ type execOptions struct {
 	workdir     string
 	container   string
 	command     []string
+	record      string
 }
 
 func newExecOptions() execOptions {
@@ -61,13 +63,14 @@ func NewExecCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("env", "version", []string{"1.25"})
 	flags.StringVarP(&options.workdir, "workdir", "w", "", "Working directory inside the container")
 	flags.SetAnnotation("workdir", "version", []string{"1.35"})
+	flags.StringVar(&options.record, "record", "", "Record the session to a file, in the asciicast v2 format")
 
 	return cmd
 }
 
 func runExec(dockerCli command.Cli, options execOptions) error {
 	execConfig := parseExec(options, dockerCli.ConfigFile())
-	ctx := context.Background()
+	ctx := dockerCli.Context()
 	client := dockerCli.Client()
 
 	// We need to check the tty _before_ we do the ContainerExecCreate, because
@@ -77,6 +80,9 @@ func runExec(dockerCli command.Cli, options execOptions) error {
 	if _, err := client.ContainerInspect(ctx, options.container); err != nil {
 		return err
 	}
+	if execConfig.Detach && options.record != "" {
+		return errors.New("Conflicting options: --record and -d")
+	}
 	if !execConfig.Detach {
 		if err := dockerCli.In().CheckTty(execConfig.AttachStdin, execConfig.Tty); err != nil {
 			return err
@@ -100,10 +106,10 @@ func runExec(dockerCli command.Cli, options execOptions) error {
 		}
 		return client.ContainerExecStart(ctx, execID, execStartCheck)
 	}
-	return interactiveExec(ctx, dockerCli, execConfig, execID)
+	return interactiveExec(ctx, dockerCli, execConfig, execID, options.record)
 }
 
-func interactiveExec(ctx context.Context, dockerCli command.Cli, execConfig *types.ExecConfig, execID string) error {
+func interactiveExec(ctx context.Context, dockerCli command.Cli, execConfig *types.ExecConfig, execID string, record string) error {
 	// Interactive exec requested.
 	var (
 		out, stderr io.Writer
@@ -134,6 +140,16 @@ func interactiveExec(ctx context.Context, dockerCli command.Cli, execConfig *typ
 	}
 	defer resp.Close()
 
+	var recorder *streams.Recorder
+	if record != "" {
+		var closeRecording func()
+		recorder, closeRecording, err = newSessionRecorder(dockerCli, record)
+		if err != nil {
+			return err
+		}
+		defer closeRecording()
+	}
+
 	errCh := make(chan error, 1)
 
 	go func() {
@@ -147,6 +163,7 @@ func interactiveExec(ctx context.Context, dockerCli command.Cli, execConfig *typ
 				resp:         resp,
 				tty:          execConfig.Tty,
 				detachKeys:   execConfig.DetachKeys,
+				recorder:     recorder,
 			}
 
 			return streamer.stream(ctx)
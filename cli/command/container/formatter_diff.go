@@ -2,6 +2,7 @@ package container
 
 import (
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/archive"
 )
@@ -24,10 +25,16 @@ func NewDiffFormat(source string) formatter.Format {
 
 // DiffFormatWrite writes formatted diff using the Context
 func DiffFormatWrite(ctx formatter.Context, changes []container.ContainerChangeResponseItem) error {
+	return diffFormatWrite(ctx, changes, nil)
+}
+
+// diffFormatWrite writes formatted diff using the Context, with the type of
+// each change colored by colorize, if not nil
+func diffFormatWrite(ctx formatter.Context, changes []container.ContainerChangeResponseItem, colorize func(role, s string) string) error {
 
 	render := func(format func(subContext formatter.SubContext) error) error {
 		for _, change := range changes {
-			if err := format(&diffContext{c: change}); err != nil {
+			if err := format(&diffContext{c: change, colorize: colorize}); err != nil {
 				return err
 			}
 		}
@@ -38,7 +45,8 @@ func DiffFormatWrite(ctx formatter.Context, changes []container.ContainerChangeR
 
 type diffContext struct {
 	formatter.HeaderContext
-	c container.ContainerChangeResponseItem
+	c        container.ContainerChangeResponseItem
+	colorize func(role, s string) string
 }
 
 func newDiffContext() *diffContext {
@@ -55,14 +63,17 @@ func (d *diffContext) MarshalJSON() ([]byte, error) {
 }
 
 func (d *diffContext) Type() string {
-	var kind string
+	var kind, role string
 	switch d.c.Kind {
 	case archive.ChangeModify:
-		kind = "C"
+		kind, role = "C", streams.ColorChanged
 	case archive.ChangeAdd:
-		kind = "A"
+		kind, role = "A", streams.ColorAdded
 	case archive.ChangeDelete:
-		kind = "D"
+		kind, role = "D", streams.ColorRemoved
+	}
+	if d.colorize != nil {
+		return d.colorize(role, kind)
 	}
 	return kind
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/archive"
 	"gotest.tools/assert"
@@ -59,3 +60,20 @@ D: /usr/app/old_app.js
 		}
 	}
 }
+
+func TestDiffContextFormatWriteColors(t *testing.T) {
+	diffs := []container.ContainerChangeResponseItem{
+		{Kind: archive.ChangeModify, Path: "/var/log/app.log"},
+		{Kind: archive.ChangeAdd, Path: "/usr/app/app.js"},
+		{Kind: archive.ChangeDelete, Path: "/usr/app/old_app.js"},
+	}
+	out := bytes.NewBufferString("")
+	colors := streams.ColorPolicy{Mode: streams.ColorAlways}
+	colorize := func(role, s string) string {
+		return colors.Colorize(out, role, s)
+	}
+	err := diffFormatWrite(formatter.Context{Format: NewDiffFormat("{{.Type}} {{.Path}}"), Output: out}, diffs, colorize)
+	assert.NilError(t, err)
+	expected := "\x1b[33mC\x1b[0m /var/log/app.log\n\x1b[32mA\x1b[0m /usr/app/app.js\n\x1b[31mD\x1b[0m /usr/app/old_app.js\n"
+	assert.Check(t, is.Equal(expected, out.String()))
+}
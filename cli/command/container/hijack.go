@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/stdcopy"
@@ -31,6 +32,14 @@ type hijackedIOStreamer struct {
 
 	tty        bool
 	detachKeys string
+
+	// raw is set if the output of the connection is not multiplexed, as with
+	// the WebSocket transport, in which case the output and error streams of
+	// the container are both copied to the output stream
+	raw bool
+
+	// recorder records the session, if set
+	recorder *streams.Recorder
 }
 
 // stream handles setting up the IO and then begins streaming stdin/stdout
@@ -45,6 +54,10 @@ func (h *hijackedIOStreamer) stream(ctx context.Context) error {
 
 	defer restoreInput()
 
+	if h.recorder != nil {
+		h.recordStreams()
+	}
+
 	outputDone := h.beginOutputStream(restoreInput)
 	inputDone, detached := h.beginInputStream(restoreInput)
 
@@ -108,6 +121,20 @@ func (h *hijackedIOStreamer) setupInput() (restore func(), err error) {
 	return restore, nil
 }
 
+// recordStreams records the input and output streams with the recorder. The
+// input is recorded after the detach key sequence is handled.
+func (h *hijackedIOStreamer) recordStreams() {
+	if h.inputStream != nil {
+		h.inputStream = h.recorder.RecordInput(h.inputStream)
+	}
+	if h.outputStream != nil {
+		h.outputStream = h.recorder.RecordOutput(h.outputStream)
+	}
+	if h.errorStream != nil {
+		h.errorStream = h.recorder.RecordOutput(h.errorStream)
+	}
+}
+
 func (h *hijackedIOStreamer) beginOutputStream(restoreInput func()) <-chan error {
 	if h.outputStream == nil && h.errorStream == nil {
 		// There is no need to copy output.
@@ -125,6 +152,12 @@ func (h *hijackedIOStreamer) beginOutputStream(restoreInput func()) <-chan error
 			// once the connection ends so any following print
 			// messages will be in normal type.
 			restoreInput()
+		} else if h.raw {
+			out := h.outputStream
+			if out == nil {
+				out = h.errorStream
+			}
+			_, err = io.Copy(out, h.resp.Reader)
 		} else {
 			_, err = stdcopy.StdCopy(h.outputStream, h.errorStream, h.resp.Reader)
 		}
@@ -35,6 +35,7 @@ func NewPsCommand(dockerCli command.Cli) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runPs(dockerCli, &options)
 		},
+		Annotations: map[string]string{"multiContext": ""},
 	}
 
 	flags := cmd.Flags()
@@ -116,10 +117,8 @@ func runPs(dockerCli command.Cli, options *psOptions) error {
 	if err != nil {
 		return err
 	}
-
-	containers, err := dockerCli.Client().ContainerList(ctx, *listOptions)
-	if err != nil {
-		return err
+	if listOptions.All {
+		ctx = command.WithOfflineCache(ctx)
 	}
 
 	format := options.format
@@ -131,10 +130,39 @@ func runPs(dockerCli command.Cli, options *psOptions) error {
 		}
 	}
 
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	containerCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: formatter.NewContainerFormat(format, options.quiet, listOptions.Size),
-		Trunc:  !options.noTrunc,
+		Trunc:  truncation.Trunc(options.noTrunc),
+		Widths: truncation.Widths("ps"),
+	}
+
+	if clients := dockerCli.SelectedContextClients(); len(clients) > 0 {
+		return runPsContexts(ctx, clients, containerCtx, *listOptions)
+	}
+
+	containers, err := dockerCli.Client().ContainerList(ctx, *listOptions)
+	if err != nil {
+		return err
 	}
 	return formatter.ContainerWrite(containerCtx, containers)
 }
+
+// runPsContexts lists the containers of the daemons of several contexts
+// concurrently. The containers of the contexts whose daemon can be reached
+// are written before the errors of the others are returned.
+func runPsContexts(ctx context.Context, clients []command.ContextAPIClient, containerCtx formatter.Context, listOptions types.ContainerListOptions) error {
+	results := make([]formatter.ContextContainers, len(clients))
+	listErr := command.RunInContexts(ctx, clients, func(ctx context.Context, i int, c command.ContextAPIClient) error {
+		containers, err := c.Client.ContainerList(ctx, listOptions)
+		results[i] = formatter.ContextContainers{Context: c.Name, Containers: containers}
+		return err
+	})
+
+	containerCtx.Format = formatter.WithContextColumn(containerCtx.Format)
+	if err := formatter.ContainerWriteContexts(containerCtx, results); err != nil {
+		return err
+	}
+	return listErr
+}
@@ -5,12 +5,14 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	// Import builders to get the builder function as package function
 	. "github.com/docker/cli/internal/test/builders"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/golden"
 )
 
@@ -87,6 +89,32 @@ func TestContainerListNoTrunc(t *testing.T) {
 	golden.Assert(t, cli.OutBuffer().String(), "container-list-without-format-no-trunc.golden")
 }
 
+func TestContainerListTruncation(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(_ types.ContainerListOptions) ([]types.Container, error) {
+			c := Container("c1")
+			c.Command = "sh -c 'sleep 3600 && echo done'"
+			return []types.Container{*c}, nil
+		},
+	})
+	cli.SetConfigFile(&configfile.ConfigFile{
+		Truncation: &configfile.TruncationConfig{
+			ColumnWidths: map[string]map[string]int{"ps": {"Command": 10}},
+		},
+	})
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.ID}} {{.Command}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("container_id \"sh -c 'sl…\"\n", cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	cli.SetNoTrunc(true)
+	cmd = newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.ID}} {{.Command}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("container_id \"sh -c 'sleep 3600 && echo done'\"\n", cli.OutBuffer().String()))
+}
+
 // Test for GitHub issue docker/docker#21772
 func TestContainerListNamesMultipleTime(t *testing.T) {
 	cli := test.NewFakeCli(&fakeClient{
@@ -162,3 +190,28 @@ func TestContainerListWithFormat(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 	golden.Assert(t, cli.OutBuffer().String(), "container-list-with-format.golden")
 }
+
+func TestContainerListContexts(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetSelectedContextClients([]command.ContextAPIClient{
+		{Name: "default", Current: true, Client: &fakeClient{
+			containerListFunc: func(_ types.ContainerListOptions) ([]types.Container, error) {
+				return []types.Container{*Container("c1"), *Container("c2")}, nil
+			},
+		}},
+		{Name: "prod", Client: &fakeClient{
+			containerListFunc: func(_ types.ContainerListOptions) ([]types.Container, error) {
+				return []types.Container{*Container("c3")}, nil
+			},
+		}},
+		{Name: "staging", Client: &fakeClient{
+			containerListFunc: func(_ types.ContainerListOptions) ([]types.Container, error) {
+				return nil, fmt.Errorf("Cannot connect to the Docker daemon")
+			},
+		}},
+	})
+	cmd := newListCommand(cli)
+	cmd.Flags().Set("format", "table {{.Names}}\t{{.Image}}")
+	assert.Error(t, cmd.Execute(), "context staging: Cannot connect to the Docker daemon")
+	golden.Assert(t, cli.OutBuffer().String(), "container-list-contexts.golden")
+}
@@ -3,19 +3,30 @@ package container
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/go-connections/nat"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+// portPollInterval is the interval between two inspections of the container,
+// or two lists of the containers, while waiting for a port mapping
+var portPollInterval = 500 * time.Millisecond
+
 type portOptions struct {
 	container string
 
-	port string
+	port        string
+	hostPort    string
+	wait        bool
+	waitTimeout time.Duration
 }
 
 // NewPortCommand creates a new cobra.Command for `docker port`
@@ -23,10 +34,18 @@ func NewPortCommand(dockerCli command.Cli) *cobra.Command {
 	var opts portOptions
 
 	cmd := &cobra.Command{
-		Use:   "port CONTAINER [PRIVATE_PORT[/PROTO]]",
+		Use:   "port [OPTIONS] CONTAINER [PRIVATE_PORT[/PROTO]]",
 		Short: "List port mappings or a specific mapping for the container",
-		Args:  cli.RequiresRangeArgs(1, 2),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.hostPort != "" {
+				return cli.NoArgs(cmd, args)
+			}
+			return cli.RequiresRangeArgs(1, 2)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.hostPort != "" {
+				return runPortReverse(dockerCli, &opts)
+			}
 			opts.container = args[0]
 			if len(args) > 1 {
 				opts.port = args[1]
@@ -34,45 +53,167 @@ func NewPortCommand(dockerCli command.Cli) *cobra.Command {
 			return runPort(dockerCli, &opts)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.hostPort, "host-port", "", "Find the running containers publishing a host port (HOST_PORT[/PROTO])")
+	flags.BoolVar(&opts.wait, "wait", false, "Wait until the port mapping exists")
+	flags.DurationVar(&opts.waitTimeout, "wait-timeout", 0, "Maximum time to wait for the port mapping (0 to wait forever)")
 	return cmd
 }
 
 func runPort(dockerCli command.Cli, opts *portOptions) error {
-	ctx := context.Background()
+	ctx, cancel := portContext(dockerCli, opts)
+	defer cancel()
 
-	c, err := dockerCli.Client().ContainerInspect(ctx, opts.container)
-	if err != nil {
-		return err
+	var (
+		newP     nat.Port
+		natPort  string
+		matching bool
+	)
+	if opts.port != "" {
+		port, proto := splitPortProto(opts.port)
+		natPort = port + "/" + proto
+		var err error
+		newP, err = nat.NewPort(proto, port)
+		if err != nil {
+			return err
+		}
+		matching = true
 	}
 
-	if opts.port != "" {
-		port := opts.port
-		proto := "tcp"
-		parts := strings.SplitN(port, "/", 2)
+	for {
+		c, err := dockerCli.Client().ContainerInspect(ctx, opts.container)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return portTimeoutError(natPort, "container "+opts.container, opts.waitTimeout)
+			}
+			return err
+		}
 
-		if len(parts) == 2 && len(parts[1]) != 0 {
-			port = parts[0]
-			proto = parts[1]
+		if matching {
+			if frontends, exists := c.NetworkSettings.Ports[newP]; exists && frontends != nil {
+				for _, frontend := range frontends {
+					fmt.Fprintf(dockerCli.Out(), "%s:%s\n", frontend.HostIP, frontend.HostPort)
+				}
+				return nil
+			}
+			if !opts.wait {
+				return errors.Errorf("Error: No public port '%s' published for %s", natPort, opts.container)
+			}
+		} else if published := hasPublishedPorts(c.NetworkSettings.Ports); published || !opts.wait {
+			for from, frontends := range c.NetworkSettings.Ports {
+				for _, frontend := range frontends {
+					fmt.Fprintf(dockerCli.Out(), "%s -> %s:%s\n", from, frontend.HostIP, frontend.HostPort)
+				}
+			}
+			return nil
 		}
-		natPort := port + "/" + proto
-		newP, err := nat.NewPort(proto, port)
+
+		// The ports of a created container are published once it starts
+		if c.State != nil && !c.State.Running && !c.State.Restarting && c.State.Status != "created" {
+			return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("container %s exited with status %d before publishing the port", opts.container, c.State.ExitCode)}
+		}
+		if err := waitPortPoll(ctx, natPort, "container "+opts.container, opts.waitTimeout); err != nil {
+			return err
+		}
+	}
+}
+
+// runPortReverse prints the running containers publishing the host port of
+// the options, and their private port.
+func runPortReverse(dockerCli command.Cli, opts *portOptions) error {
+	ctx, cancel := portContext(dockerCli, opts)
+	defer cancel()
+
+	port, proto := splitPortProto(opts.hostPort)
+	hostPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return errors.Errorf("invalid host port: %s", opts.hostPort)
+	}
+	natPort := port + "/" + proto
+
+	for {
+		containers, err := dockerCli.Client().ContainerList(ctx, types.ContainerListOptions{})
 		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return portTimeoutError(natPort, "a running container", opts.waitTimeout)
+			}
 			return err
 		}
-		if frontends, exists := c.NetworkSettings.Ports[newP]; exists && frontends != nil {
-			for _, frontend := range frontends {
-				fmt.Fprintf(dockerCli.Out(), "%s:%s\n", frontend.HostIP, frontend.HostPort)
+
+		var mappings []string
+		for _, c := range containers {
+			name := c.ID
+			if len(c.Names) > 0 {
+				name = strings.TrimPrefix(c.Names[0], "/")
+			}
+			for _, p := range c.Ports {
+				if uint64(p.PublicPort) == hostPort && p.Type == proto {
+					mappings = append(mappings, fmt.Sprintf("%s %d/%s -> %s:%d", name, p.PrivatePort, p.Type, p.IP, p.PublicPort))
+				}
+			}
+		}
+		if len(mappings) > 0 {
+			sort.Strings(mappings)
+			for _, m := range mappings {
+				fmt.Fprintln(dockerCli.Out(), m)
 			}
 			return nil
 		}
-		return errors.Errorf("Error: No public port '%s' published for %s", natPort, opts.container)
+		if !opts.wait {
+			return errors.Errorf("Error: No running container publishes the host port '%s'", natPort)
+		}
+		if err := waitPortPoll(ctx, natPort, "a running container", opts.waitTimeout); err != nil {
+			return err
+		}
 	}
+}
 
-	for from, frontends := range c.NetworkSettings.Ports {
-		for _, frontend := range frontends {
-			fmt.Fprintf(dockerCli.Out(), "%s -> %s:%s\n", from, frontend.HostIP, frontend.HostPort)
+// portContext returns the context of the requests of the command, with the
+// timeout of --wait-timeout when waiting.
+func portContext(dockerCli command.Cli, opts *portOptions) (context.Context, context.CancelFunc) {
+	if opts.wait && opts.waitTimeout > 0 {
+		return context.WithTimeout(dockerCli.Context(), opts.waitTimeout)
+	}
+	return context.WithCancel(dockerCli.Context())
+}
+
+// splitPortProto splits a PORT[/PROTO] argument. The protocol is tcp if it is
+// not specified.
+func splitPortProto(arg string) (port, proto string) {
+	port, proto = arg, "tcp"
+	parts := strings.SplitN(arg, "/", 2)
+	if len(parts) == 2 && len(parts[1]) != 0 {
+		port = parts[0]
+		proto = parts[1]
+	}
+	return port, proto
+}
+
+func hasPublishedPorts(ports nat.PortMap) bool {
+	for _, frontends := range ports {
+		if len(frontends) > 0 {
+			return true
 		}
 	}
+	return false
+}
+
+func waitPortPoll(ctx context.Context, natPort, publisher string, timeout time.Duration) error {
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return portTimeoutError(natPort, publisher, timeout)
+		}
+		return ctx.Err()
+	case <-time.After(portPollInterval):
+		return nil
+	}
+}
 
-	return nil
+func portTimeoutError(natPort, publisher string, timeout time.Duration) error {
+	if natPort == "" {
+		return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("no port is published by %s after %s", publisher, timeout)}
+	}
+	return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("port %s is not published by %s after %s", natPort, publisher, timeout)}
 }
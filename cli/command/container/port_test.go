@@ -0,0 +1,151 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func portContainer(state types.ContainerState, ports nat.PortMap) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{State: &state},
+		NetworkSettings:   &types.NetworkSettings{NetworkSettingsBase: types.NetworkSettingsBase{Ports: ports}},
+	}
+}
+
+func TestPortWait(t *testing.T) {
+	defer func(interval time.Duration) { portPollInterval = interval }(portPollInterval)
+	portPollInterval = time.Millisecond
+
+	var inspections int
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			inspections++
+			if inspections < 3 {
+				return portContainer(types.ContainerState{Status: "created"}, nil), nil
+			}
+			return portContainer(types.ContainerState{Running: true}, nat.PortMap{
+				"80/tcp": {{HostIP: "0.0.0.0", HostPort: "32768"}},
+			}), nil
+		},
+	})
+	cmd := NewPortCommand(cli)
+	cmd.SetArgs([]string{"--wait", "web", "80"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(3, inspections))
+	assert.Check(t, is.Equal("0.0.0.0:32768\n", cli.OutBuffer().String()))
+}
+
+func TestPortWaitErrors(t *testing.T) {
+	defer func(interval time.Duration) { portPollInterval = interval }(portPollInterval)
+	portPollInterval = time.Millisecond
+
+	testCases := []struct {
+		doc           string
+		args          []string
+		state         types.ContainerState
+		expectedError string
+	}{
+		{
+			doc:           "not waiting",
+			args:          []string{"web", "80"},
+			state:         types.ContainerState{Running: true},
+			expectedError: "Error: No public port '80/tcp' published for web",
+		},
+		{
+			doc:           "exited",
+			args:          []string{"--wait", "web", "80"},
+			state:         types.ContainerState{Status: "exited", ExitCode: 2},
+			expectedError: "container web exited with status 2 before publishing the port",
+		},
+		{
+			doc:           "timeout",
+			args:          []string{"--wait", "--wait-timeout", "10ms", "web", "80/udp"},
+			state:         types.ContainerState{Running: true},
+			expectedError: "port 80/udp is not published by container web after 10ms",
+		},
+		{
+			doc:           "timeout of any port",
+			args:          []string{"--wait", "--wait-timeout", "10ms", "web"},
+			state:         types.ContainerState{Running: true},
+			expectedError: "no port is published by container web after 10ms",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				inspectFunc: func(string) (types.ContainerJSON, error) {
+					return portContainer(tc.state, nil), nil
+				},
+			})
+			cmd := NewPortCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOutput(ioutil.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestPortHostPort(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(types.ContainerListOptions) ([]types.Container, error) {
+			return []types.Container{
+				{ID: "id1", Names: []string{"/web"}, Ports: []types.Port{
+					{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+					{IP: "0.0.0.0", PrivatePort: 53, PublicPort: 8080, Type: "udp"},
+				}},
+				{ID: "id2", Names: []string{"/api"}, Ports: []types.Port{
+					{IP: "127.0.0.1", PrivatePort: 8000, PublicPort: 8080, Type: "tcp"},
+				}},
+				{ID: "id3", Names: []string{"/db"}, Ports: []types.Port{
+					{PrivatePort: 5432, Type: "tcp"},
+				}},
+			}, nil
+		},
+	})
+	cmd := NewPortCommand(cli)
+	cmd.SetArgs([]string{"--host-port", "8080"})
+	assert.NilError(t, cmd.Execute())
+	expected := `api 8000/tcp -> 127.0.0.1:8080
+web 80/tcp -> 0.0.0.0:8080
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestPortHostPortErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--host-port", "8080", "web"},
+			expectedError: "accepts no arguments",
+		},
+		{
+			args:          []string{"--host-port", "http"},
+			expectedError: "invalid host port: http",
+		},
+		{
+			args:          []string{"--host-port", "8080/udp"},
+			expectedError: "Error: No running container publishes the host port '8080/udp'",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{
+			containerListFunc: func(types.ContainerListOptions) ([]types.Container, error) {
+				return []types.Container{{ID: "id1", Ports: []types.Port{{PrivatePort: 80, PublicPort: 8080, Type: "tcp"}}}}, nil
+			},
+		})
+		cmd := NewPortCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
@@ -0,0 +1,51 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/pkg/errors"
+)
+
+// newSessionRecorder returns a recorder recording the session of the command
+// to the given file, in the asciicast v2 format, and a function closing the
+// file. Errors writing the recording are printed as a warning when the file
+// is closed, without interrupting the session.
+func newSessionRecorder(dockerCli command.Cli, path string) (*streams.Recorder, func(), error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to record the session")
+	}
+	height, width := dockerCli.Out().GetTtySize()
+	if height == 0 || width == 0 {
+		height, width = 24, 80
+	}
+	header := streams.RecordHeader{
+		Width:   width,
+		Height:  height,
+		Command: strings.Join(os.Args, " "),
+		Env:     map[string]string{},
+	}
+	for _, name := range []string{"SHELL", "TERM"} {
+		if v := os.Getenv(name); v != "" {
+			header.Env[name] = v
+		}
+	}
+	recorder, err := streams.NewRecorder(f, header)
+	if err != nil {
+		f.Close()
+		return nil, nil, errors.Wrap(err, "unable to record the session")
+	}
+	return recorder, func() {
+		err := recorder.Err()
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			fmt.Fprintf(dockerCli.Err(), "Warning: the recording of the session to %s is incomplete: %v\n", path, err)
+		}
+	}, nil
+}
@@ -0,0 +1,599 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	networktypes "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type recreateOptions struct {
+	container   string
+	image       string
+	envAdd      opts.ListOpts
+	envRm       []string
+	mountAdd    opts.MountOpt
+	mountRm     []string
+	publishAdd  opts.ListOpts
+	publishRm   []string
+	time        int
+	timeChanged bool
+	dryRun      bool
+}
+
+// NewRecreateCommand creates a new cobra.Command for `docker container recreate`
+func NewRecreateCommand(dockerCli command.Cli) *cobra.Command {
+	options := recreateOptions{
+		envAdd:     opts.NewListOpts(opts.ValidateEnv),
+		publishAdd: opts.NewListOpts(nil),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "recreate [OPTIONS] CONTAINER",
+		Short: "Recreate a container with a modified configuration",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.container = args[0]
+			options.timeChanged = cmd.Flags().Changed("time")
+			return runRecreate(dockerCli, &options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.image, "image", "", "Image of the new container")
+	flags.Var(&options.envAdd, "env-add", "Add or update an environment variable")
+	flags.StringSliceVar(&options.envRm, "env-rm", nil, "Remove an environment variable")
+	flags.Var(&options.mountAdd, "mount-add", "Add or update a mount")
+	flags.StringSliceVar(&options.mountRm, "mount-rm", nil, "Remove a mount by its target path")
+	flags.Var(&options.publishAdd, "publish-add", "Add or update a published port")
+	flags.StringSliceVar(&options.publishRm, "publish-rm", nil, "Remove a published port by its container port")
+	flags.IntVarP(&options.time, "time", "t", 10, "Seconds to wait for the container to stop before killing it")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Print the changes to the configuration without recreating the container")
+	return cmd
+}
+
+func runRecreate(dockerCli command.Cli, options *recreateOptions) error {
+	ctx := context.Background()
+	apiClient := dockerCli.Client()
+
+	c, err := apiClient.ContainerInspect(ctx, options.container)
+	if err != nil {
+		return err
+	}
+	if c.Config == nil || c.HostConfig == nil {
+		return errors.Errorf("cannot recreate container %s: its configuration is unknown", options.container)
+	}
+
+	current := recreateRequest(c)
+	// The settings inherited from the image are not kept when the image is
+	// replaced, so that the container uses the settings of the new image
+	var imageConfig *container.Config
+	if options.image != "" && options.image != c.Config.Image {
+		image, _, err := apiClient.ImageInspectWithRaw(ctx, c.Image)
+		switch {
+		case err == nil:
+			imageConfig = image.Config
+		case !client.IsErrNotFound(err):
+			return err
+		}
+	}
+	req, err := modifyRecreateRequest(current, imageConfig, options)
+	if err != nil {
+		return err
+	}
+
+	if options.dryRun {
+		return printRecreateChanges(dockerCli.Out(), current, req, dockerCli.ColorPolicy())
+	}
+
+	var timeout *time.Duration
+	if options.timeChanged {
+		timeoutValue := time.Duration(options.time) * time.Second
+		timeout = &timeoutValue
+	}
+	if err := recreateContainer(ctx, dockerCli, c, req, timeout); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), req.Name)
+	return nil
+}
+
+// recreateRequest returns the request to create a container with the same
+// configuration as an existing container. The networks which the container
+// is connected to are all listed in the networking config, and the anonymous
+// volumes of the container are mounted by name, so that they are kept.
+func recreateRequest(c types.ContainerJSON) createRequest {
+	config := *c.Config
+	// The hostname defaults to the short ID of the container
+	if config.Hostname != "" && strings.HasPrefix(c.ID, config.Hostname) {
+		config.Hostname = ""
+	}
+
+	hostConfig := *c.HostConfig
+	name := strings.TrimPrefix(c.Name, "/")
+	hostConfig.Links = nil
+	// The links of the container are listed as "/db:/web/db"
+	for _, link := range c.HostConfig.Links {
+		parts := strings.SplitN(link, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		hostConfig.Links = append(hostConfig.Links, strings.TrimPrefix(parts[0], "/")+":"+path.Base(parts[1]))
+	}
+	hostConfig.Binds, hostConfig.Mounts = keepAnonymousVolumes(c.Mounts, c.HostConfig)
+
+	networking := &networktypes.NetworkingConfig{}
+	if c.NetworkSettings != nil && len(c.NetworkSettings.Networks) > 0 {
+		shortID := stringid.TruncateID(c.ID)
+		networking.EndpointsConfig = map[string]*networktypes.EndpointSettings{}
+		for network, settings := range c.NetworkSettings.Networks {
+			endpoint := &networktypes.EndpointSettings{}
+			if settings != nil {
+				endpoint.IPAMConfig = settings.IPAMConfig
+				endpoint.Links = settings.Links
+				endpoint.DriverOpts = settings.DriverOpts
+				for _, alias := range settings.Aliases {
+					// The daemon adds the short ID of the container as an alias
+					if alias != shortID {
+						endpoint.Aliases = append(endpoint.Aliases, alias)
+					}
+				}
+			}
+			networking.EndpointsConfig[network] = endpoint
+		}
+	}
+
+	return createRequest{
+		Name:             name,
+		Config:           &config,
+		HostConfig:       &hostConfig,
+		NetworkingConfig: networking,
+	}
+}
+
+// keepAnonymousVolumes returns the binds and mounts of the host config, with
+// the anonymous volumes of the container mounted by name
+func keepAnonymousVolumes(mounts []types.MountPoint, hostConfig *container.HostConfig) ([]string, []mount.Mount) {
+	named := map[string]bool{}
+	for _, m := range hostConfig.Mounts {
+		if m.Type != mount.TypeVolume || m.Source != "" {
+			named[m.Target] = true
+		}
+	}
+	for _, bind := range hostConfig.Binds {
+		if source, target := splitBind(bind); source != "" {
+			named[target] = true
+		}
+	}
+
+	anonymous := map[string]types.MountPoint{}
+	for _, m := range mounts {
+		if m.Type == mount.TypeVolume && m.Name != "" && !named[m.Destination] {
+			anonymous[m.Destination] = m
+		}
+	}
+
+	binds := []string{}
+	for _, bind := range hostConfig.Binds {
+		if _, target := splitBind(bind); anonymous[target].Name == "" {
+			binds = append(binds, bind)
+		}
+	}
+	var volumeMounts []mount.Mount
+	for _, m := range hostConfig.Mounts {
+		if anonymous[m.Target].Name == "" {
+			volumeMounts = append(volumeMounts, m)
+		}
+	}
+	for _, target := range sortedMountTargets(anonymous) {
+		m := anonymous[target]
+		bind := m.Name + ":" + m.Destination
+		if !m.RW {
+			bind += ":ro"
+		}
+		binds = append(binds, bind)
+	}
+	if len(binds) == 0 {
+		binds = nil
+	}
+	return binds, volumeMounts
+}
+
+func sortedMountTargets(m map[string]types.MountPoint) []string {
+	targets := make([]string, 0, len(m))
+	for target := range m {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// splitBind returns the source and the target of a bind, such as
+// "data:/data:ro". The source of an anonymous volume, such as "/data", is
+// empty.
+func splitBind(bind string) (string, string) {
+	parts := strings.Split(bind, ":")
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return parts[0], parts[1]
+}
+
+// modifyRecreateRequest returns a copy of the request, modified with the
+// options. The settings of the configuration which are equal to the settings
+// of the image configuration, if any, are removed.
+func modifyRecreateRequest(current createRequest, imageConfig *container.Config, options *recreateOptions) (createRequest, error) {
+	var req createRequest
+	b, err := json.Marshal(current)
+	if err != nil {
+		return req, err
+	}
+	if err := json.Unmarshal(b, &req); err != nil {
+		return req, err
+	}
+	config, hostConfig := req.Config, req.HostConfig
+
+	if options.image != "" {
+		config.Image = options.image
+	}
+	if imageConfig != nil {
+		removeImageSettings(config, imageConfig)
+	}
+
+	for _, env := range options.envAdd.GetAll() {
+		config.Env = append(removeEnv(config.Env, strings.SplitN(env, "=", 2)[0]), env)
+	}
+	for _, name := range options.envRm {
+		config.Env = removeEnv(config.Env, name)
+	}
+
+	for _, m := range options.mountAdd.Value() {
+		removeMount(hostConfig, m.Target)
+		hostConfig.Mounts = append(hostConfig.Mounts, m)
+	}
+	for _, target := range options.mountRm {
+		removeMount(hostConfig, target)
+	}
+
+	if publish := options.publishAdd.GetAll(); len(publish) > 0 {
+		exposed, bindings, err := nat.ParsePortSpecs(publish)
+		if err != nil {
+			return req, err
+		}
+		if config.ExposedPorts == nil {
+			config.ExposedPorts = nat.PortSet{}
+		}
+		for port := range exposed {
+			config.ExposedPorts[port] = struct{}{}
+		}
+		if hostConfig.PortBindings == nil {
+			hostConfig.PortBindings = nat.PortMap{}
+		}
+		for port, portBindings := range bindings {
+			hostConfig.PortBindings[port] = portBindings
+		}
+	}
+	for _, spec := range options.publishRm {
+		proto, port := nat.SplitProtoPort(spec)
+		p, err := nat.NewPort(proto, port)
+		if err != nil {
+			return req, err
+		}
+		if _, ok := hostConfig.PortBindings[p]; !ok {
+			return req, errors.Errorf("port %s is not published", p)
+		}
+		delete(hostConfig.PortBindings, p)
+		if len(hostConfig.PortBindings) == 0 {
+			hostConfig.PortBindings = nil
+		}
+	}
+	return req, nil
+}
+
+// removeImageSettings removes the settings of the configuration which are
+// inherited from the image configuration
+func removeImageSettings(config, imageConfig *container.Config) {
+	inherited := map[string]bool{}
+	for _, env := range imageConfig.Env {
+		inherited[env] = true
+	}
+	var env []string
+	for _, e := range config.Env {
+		if !inherited[e] {
+			env = append(env, e)
+		}
+	}
+	config.Env = env
+	config.Labels = mapDifference(config.Labels, imageConfig.Labels)
+	if stringSliceEqual(config.Entrypoint, imageConfig.Entrypoint) {
+		config.Entrypoint = nil
+		if stringSliceEqual(config.Cmd, imageConfig.Cmd) {
+			config.Cmd = nil
+		}
+	}
+	if config.User == imageConfig.User {
+		config.User = ""
+	}
+	if config.WorkingDir == imageConfig.WorkingDir {
+		config.WorkingDir = ""
+	}
+	if config.StopSignal == imageConfig.StopSignal {
+		config.StopSignal = ""
+	}
+	if healthcheckEqual(config.Healthcheck, imageConfig.Healthcheck) {
+		config.Healthcheck = nil
+	}
+	for port := range imageConfig.ExposedPorts {
+		delete(config.ExposedPorts, port)
+	}
+	if len(config.ExposedPorts) == 0 {
+		config.ExposedPorts = nil
+	}
+	for volume := range imageConfig.Volumes {
+		delete(config.Volumes, volume)
+	}
+	if len(config.Volumes) == 0 {
+		config.Volumes = nil
+	}
+}
+
+func removeEnv(env []string, name string) []string {
+	var result []string
+	for _, e := range env {
+		if strings.SplitN(e, "=", 2)[0] != name {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// removeMount removes the binds, mounts and tmpfs mounts of the target
+func removeMount(hostConfig *container.HostConfig, target string) {
+	var binds []string
+	for _, bind := range hostConfig.Binds {
+		if _, t := splitBind(bind); t != target {
+			binds = append(binds, bind)
+		}
+	}
+	hostConfig.Binds = binds
+	var mounts []mount.Mount
+	for _, m := range hostConfig.Mounts {
+		if m.Target != target {
+			mounts = append(mounts, m)
+		}
+	}
+	hostConfig.Mounts = mounts
+	delete(hostConfig.Tmpfs, target)
+}
+
+// recreateContainer replaces the container with a new container created
+// with the request. The container is stopped and renamed, and restored if
+// the new container cannot be created, connected to its networks, or
+// started. The new container is started if the container was running.
+func recreateContainer(ctx context.Context, dockerCli command.Cli, c types.ContainerJSON, req createRequest, timeout *time.Duration) error {
+	apiClient := dockerCli.Client()
+	running := c.State != nil && c.State.Running
+	if running && c.HostConfig.AutoRemove {
+		return errors.Errorf("cannot recreate container %s: it is removed when it stops", req.Name)
+	}
+
+	if running {
+		if err := apiClient.ContainerStop(ctx, c.ID, timeout); err != nil {
+			return err
+		}
+	}
+	if err := apiClient.ContainerRename(ctx, c.ID, req.Name+"_"+stringid.TruncateID(c.ID)); err != nil {
+		return restoreContainer(ctx, apiClient, c.ID, "", running, err)
+	}
+
+	createNetworking, connectNetworks := splitNetworks(req.HostConfig.NetworkMode, req.NetworkingConfig)
+	response, err := apiClient.ContainerCreate(ctx, req.Config, req.HostConfig, createNetworking, req.Name)
+	if err != nil && client.IsErrNotFound(err) && req.Config.Image != "" {
+		if err := pullImage(ctx, dockerCli, req.Config.Image, "", dockerCli.Err()); err != nil {
+			return restoreContainer(ctx, apiClient, c.ID, req.Name, running, err)
+		}
+		response, err = apiClient.ContainerCreate(ctx, req.Config, req.HostConfig, createNetworking, req.Name)
+	}
+	if err != nil {
+		return restoreContainer(ctx, apiClient, c.ID, req.Name, running, err)
+	}
+	for _, warning := range response.Warnings {
+		command.PrintWarning(dockerCli, "%s", warning)
+	}
+
+	err = func() error {
+		for _, network := range sortedNetworks(connectNetworks) {
+			if err := apiClient.NetworkConnect(ctx, network, response.ID, connectNetworks[network]); err != nil {
+				return errors.Wrapf(err, "failed to connect the new container to network %s", network)
+			}
+		}
+		if running {
+			return apiClient.ContainerStart(ctx, response.ID, types.ContainerStartOptions{})
+		}
+		return nil
+	}()
+	if err != nil {
+		if rmErr := apiClient.ContainerRemove(ctx, response.ID, types.ContainerRemoveOptions{Force: true}); rmErr != nil {
+			return errors.Wrapf(err, "failed to remove the new container %s: %v", response.ID, rmErr)
+		}
+		return restoreContainer(ctx, apiClient, c.ID, req.Name, running, err)
+	}
+
+	// The volumes of the container are used by the new container
+	if err := apiClient.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{}); err != nil {
+		command.PrintWarning(dockerCli, "failed to remove the previous container %s: %v", stringid.TruncateID(c.ID), err)
+	}
+	return nil
+}
+
+// restoreContainer renames the container back to its name, if it was
+// renamed, and starts it if it was running, after the recreation failed
+func restoreContainer(ctx context.Context, apiClient client.APIClient, id, name string, running bool, cause error) error {
+	if name != "" {
+		if err := apiClient.ContainerRename(ctx, id, name); err != nil {
+			return errors.Wrapf(cause, "failed to restore the name of container %s: %v", stringid.TruncateID(id), err)
+		}
+	}
+	if running {
+		if err := apiClient.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+			return errors.Wrapf(cause, "failed to restart container %s: %v", stringid.TruncateID(id), err)
+		}
+	}
+	return cause
+}
+
+// splitNetworks returns the networking config to create a container with its
+// network mode, and the other networks to connect the container to, as only
+// one network can be set when creating a container
+func splitNetworks(mode container.NetworkMode, networking *networktypes.NetworkingConfig) (*networktypes.NetworkingConfig, map[string]*networktypes.EndpointSettings) {
+	createNetworking := &networktypes.NetworkingConfig{}
+	connect := map[string]*networktypes.EndpointSettings{}
+	if networking == nil {
+		return createNetworking, connect
+	}
+	for network, endpoint := range networking.EndpointsConfig {
+		switch {
+		case network == string(mode) && mode.IsUserDefined():
+			createNetworking.EndpointsConfig = map[string]*networktypes.EndpointSettings{network: endpoint}
+		case network == string(mode), mode.IsDefault() && network == "bridge":
+			// The container is connected to the network of its mode
+		default:
+			connect[network] = endpoint
+		}
+	}
+	return createNetworking, connect
+}
+
+func sortedNetworks(networks map[string]*networktypes.EndpointSettings) []string {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printRecreateChanges prints the changes to the fields of the create request
+// of the container, such as "~ Config.Image: "nginx:1.16" => "nginx:1.17""
+func printRecreateChanges(out io.Writer, current, req createRequest, colors streams.ColorPolicy) error {
+	oldFields, err := flattenFields(current)
+	if err != nil {
+		return err
+	}
+	newFields, err := flattenFields(req)
+	if err != nil {
+		return err
+	}
+	type change struct {
+		role string
+		line string
+	}
+	var changes []change
+	for _, field := range sortedFieldPaths(oldFields, newFields) {
+		oldValue, inOld := oldFields[field]
+		newValue, inNew := newFields[field]
+		switch {
+		case !inOld:
+			changes = append(changes, change{role: streams.ColorAdded, line: fmt.Sprintf("+ %s: %s", field, newValue)})
+		case !inNew:
+			changes = append(changes, change{role: streams.ColorRemoved, line: fmt.Sprintf("- %s: %s", field, oldValue)})
+		case oldValue != newValue:
+			changes = append(changes, change{role: streams.ColorChanged, line: fmt.Sprintf("~ %s: %s => %s", field, oldValue, newValue)})
+		}
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintf(out, "No changes to container %s\n", req.Name)
+		return err
+	}
+	for _, c := range changes {
+		fmt.Fprintln(out, colors.Colorize(out, c.role, c.line))
+	}
+	plural := "s"
+	if len(changes) == 1 {
+		plural = ""
+	}
+	_, err = fmt.Fprintf(out, "\nPlan for container %s: %d change%s\n", req.Name, len(changes), plural)
+	return err
+}
+
+func sortedFieldPaths(fields ...map[string]string) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, f := range fields {
+		for p := range f {
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// flattenFields returns the JSON-encoded values of the non-empty fields of a
+// value, indexed by their path (such as "Config.Image")
+func flattenFields(value interface{}) (map[string]string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	var flatten func(path string, v interface{})
+	flatten = func(path string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			// Sets, such as the exposed ports, are objects of empty objects
+			if len(v) == 0 && path != "" {
+				fields[path] = "{}"
+			}
+			for key, value := range v {
+				if path != "" {
+					key = path + "." + key
+				}
+				flatten(key, value)
+			}
+		case []interface{}:
+			for i, value := range v {
+				flatten(fmt.Sprintf("%s[%d]", path, i), value)
+			}
+		case nil:
+		case string:
+			if v != "" {
+				fields[path] = fmt.Sprintf("%q", v)
+			}
+		case bool:
+			if v {
+				fields[path] = "true"
+			}
+		case float64:
+			if v != 0 {
+				fields[path] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+	flatten("", v)
+	return fields, nil
+}
@@ -0,0 +1,246 @@
+package container
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+const recreateTestID = "0123456789ab0123456789ab0123456789ab0123456789ab0123456789abcdef"
+
+func recreateTestContainer() types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    recreateTestID,
+			Name:  "/web",
+			Image: "sha256:abc",
+			State: &types.ContainerState{Running: true},
+			HostConfig: &container.HostConfig{
+				Binds:       []string{"data:/data", "/cache"},
+				Links:       []string{"/db:/web/database"},
+				NetworkMode: "front",
+				PortBindings: nat.PortMap{
+					"80/tcp": {{HostPort: "8080"}},
+				},
+			},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "data", Destination: "/data", RW: true},
+			{Type: mount.TypeVolume, Name: "anon1", Destination: "/cache", RW: true},
+			{Type: mount.TypeVolume, Name: "anon2", Destination: "/var/lib/app", RW: false},
+			{Type: mount.TypeBind, Source: "/etc/app", Destination: "/etc/app"},
+		},
+		Config: &container.Config{
+			Hostname:     "0123456789ab",
+			Image:        "nginx:1.16",
+			Env:          []string{"PATH=/usr/bin", "NGINX_VERSION=1.16", "MODE=production"},
+			Cmd:          []string{"nginx", "-g", "daemon off;"},
+			ExposedPorts: nat.PortSet{"80/tcp": {}},
+			Labels:       map[string]string{"maintainer": "nginx", "app": "web"},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"front": {Aliases: []string{"0123456789ab", "www"}, EndpointID: "e1"},
+				"back":  {IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: "10.0.0.5"}, EndpointID: "e2"},
+			},
+		},
+	}
+}
+
+func TestRecreateRequest(t *testing.T) {
+	req := recreateRequest(recreateTestContainer())
+	assert.Check(t, is.Equal("web", req.Name))
+	assert.Check(t, is.Equal("", req.Config.Hostname))
+	assert.Check(t, is.DeepEqual([]string{"db:database"}, req.HostConfig.Links))
+	// The anonymous volumes are mounted by name
+	assert.Check(t, is.DeepEqual([]string{"data:/data", "anon1:/cache", "anon2:/var/lib/app:ro"}, req.HostConfig.Binds))
+	assert.Check(t, is.DeepEqual(map[string]*network.EndpointSettings{
+		"front": {Aliases: []string{"www"}},
+		"back":  {IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: "10.0.0.5"}},
+	}, req.NetworkingConfig.EndpointsConfig))
+}
+
+func TestModifyRecreateRequest(t *testing.T) {
+	c := recreateTestContainer()
+	current := recreateRequest(c)
+	options := &recreateOptions{
+		image:      "nginx:1.17",
+		envAdd:     opts.NewListOpts(opts.ValidateEnv),
+		envRm:      []string{"UNKNOWN"},
+		mountRm:    []string{"/data"},
+		publishAdd: opts.NewListOpts(nil),
+		publishRm:  []string{"80"},
+	}
+	assert.NilError(t, options.envAdd.Set("MODE=staging"))
+	assert.NilError(t, options.envAdd.Set("DEBUG=1"))
+	assert.NilError(t, options.mountAdd.Set("type=volume,source=cache,target=/cache"))
+	assert.NilError(t, options.publishAdd.Set("8443:443"))
+
+	imageConfig := &container.Config{
+		Env:          []string{"PATH=/usr/bin", "NGINX_VERSION=1.16"},
+		Cmd:          []string{"nginx", "-g", "daemon off;"},
+		ExposedPorts: nat.PortSet{"80/tcp": {}},
+		Labels:       map[string]string{"maintainer": "nginx"},
+	}
+	req, err := modifyRecreateRequest(current, imageConfig, options)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("nginx:1.17", req.Config.Image))
+	assert.Check(t, is.DeepEqual([]string{"MODE=staging", "DEBUG=1"}, req.Config.Env))
+	assert.Check(t, is.Nil(req.Config.Cmd))
+	assert.Check(t, is.DeepEqual(map[string]string{"app": "web"}, req.Config.Labels))
+	assert.Check(t, is.DeepEqual(nat.PortSet{"443/tcp": {}}, req.Config.ExposedPorts))
+	assert.Check(t, is.DeepEqual(nat.PortMap{"443/tcp": {{HostPort: "8443"}}}, req.HostConfig.PortBindings))
+	assert.Check(t, is.DeepEqual([]string{"anon2:/var/lib/app:ro"}, req.HostConfig.Binds))
+	assert.Check(t, is.DeepEqual([]mount.Mount{{Type: mount.TypeVolume, Source: "cache", Target: "/cache"}}, req.HostConfig.Mounts))
+
+	// The current request is not modified
+	assert.Check(t, is.Equal("nginx:1.16", current.Config.Image))
+	assert.Check(t, is.Len(current.HostConfig.Binds, 3))
+
+	options.publishRm = []string{"9000"}
+	_, err = modifyRecreateRequest(current, nil, options)
+	assert.Check(t, is.Error(err, "port 9000/tcp is not published"))
+}
+
+func TestRunRecreateDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			return recreateTestContainer(), nil
+		},
+		imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{}, nil, errors.New("unexpected image inspect")
+		},
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error) {
+			return container.ContainerCreateCreatedBody{}, errors.New("unexpected create")
+		},
+	})
+	cmd := NewRecreateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "--env-add", "MODE=staging", "--publish-rm", "80/tcp", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`~ Config.Env[2]: "MODE=production" => "MODE=staging"
+- HostConfig.PortBindings.80/tcp[0].HostPort: "8080"
+
+Plan for container web: 2 changes
+`, cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	cmd = NewRecreateCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("No changes to container web\n", cli.OutBuffer().String()))
+}
+
+func TestRunRecreate(t *testing.T) {
+	var calls []string
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			return recreateTestContainer(), nil
+		},
+		containerStopFunc: func(container string, timeout *time.Duration) error {
+			calls = append(calls, fmt.Sprintf("stop %s %v", container[:12], *timeout))
+			return nil
+		},
+		containerRenameFunc: func(container, name string) error {
+			calls = append(calls, fmt.Sprintf("rename %s %s", container[:12], name))
+			return nil
+		},
+		createContainerFunc: func(config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, name string) (container.ContainerCreateCreatedBody, error) {
+			var networks []string
+			for nw := range networkingConfig.EndpointsConfig {
+				networks = append(networks, nw)
+			}
+			calls = append(calls, fmt.Sprintf("create %s %s %v", name, config.Image, networks))
+			return container.ContainerCreateCreatedBody{ID: "new"}, nil
+		},
+		networkConnectFunc: func(nw, container string, config *network.EndpointSettings) error {
+			calls = append(calls, fmt.Sprintf("connect %s %s %s", nw, container, config.IPAMConfig.IPv4Address))
+			return nil
+		},
+		containerStartFunc: func(container string, options types.ContainerStartOptions) error {
+			calls = append(calls, "start "+container)
+			return nil
+		},
+		containerRemoveFunc: func(container string, options types.ContainerRemoveOptions) error {
+			calls = append(calls, fmt.Sprintf("remove %s %v", container[:12], options.RemoveVolumes))
+			return nil
+		},
+	})
+	cmd := NewRecreateCommand(cli)
+	cmd.SetArgs([]string{"--image", "nginx:1.17", "-t", "3", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual([]string{
+		"stop 0123456789ab 3s",
+		"rename 0123456789ab web_0123456789ab",
+		"create web nginx:1.17 [front]",
+		"connect back new 10.0.0.5",
+		"start new",
+		"remove 0123456789ab false",
+	}, calls))
+	assert.Check(t, is.Equal("web\n", cli.OutBuffer().String()))
+}
+
+func TestRunRecreateRestoresContainer(t *testing.T) {
+	var calls []string
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			return recreateTestContainer(), nil
+		},
+		containerRenameFunc: func(container, name string) error {
+			calls = append(calls, "rename "+name)
+			return nil
+		},
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error) {
+			return container.ContainerCreateCreatedBody{ID: "new"}, nil
+		},
+		networkConnectFunc: func(nw, container string, config *network.EndpointSettings) error {
+			return errors.New("network back not found")
+		},
+		containerStartFunc: func(container string, options types.ContainerStartOptions) error {
+			calls = append(calls, "start "+container[:3])
+			return nil
+		},
+		containerRemoveFunc: func(container string, options types.ContainerRemoveOptions) error {
+			calls = append(calls, fmt.Sprintf("remove %s %v", container, options.Force))
+			return nil
+		},
+	})
+	cmd := NewRecreateCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "failed to connect the new container to network back: network back not found"))
+	assert.Check(t, is.DeepEqual([]string{
+		"rename web_0123456789ab",
+		"remove new true",
+		"rename web",
+		"start 012",
+	}, calls))
+}
+
+func TestRunRecreateAutoRemove(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			c := recreateTestContainer()
+			c.HostConfig.AutoRemove = true
+			return c, nil
+		},
+		containerStopFunc: func(string, *time.Duration) error {
+			return errors.New("unexpected stop")
+		},
+	})
+	cmd := NewRecreateCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "cannot recreate container web: it is removed when it stops"))
+}
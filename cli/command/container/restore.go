@@ -0,0 +1,43 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/checkpoint"
+	"github.com/spf13/cobra"
+)
+
+// NewRestoreCommand creates a new cobra.Command for `docker container restore`
+func NewRestoreCommand(dockerCli command.Cli) *cobra.Command {
+	var opts checkpoint.RestoreOptions
+
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] CONTAINER CHECKPOINT",
+		Short: "Restore a stopped container from a checkpoint",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Container = args[0]
+			opts.Checkpoint = args[1]
+			return runRestore(dockerCli, opts)
+		},
+		Annotations: map[string]string{
+			"ostype":  "linux",
+			"version": "1.25",
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.CheckpointDir, "checkpoint-dir", "", "Use a custom checkpoint storage directory")
+	return cmd
+}
+
+func runRestore(dockerCli command.Cli, opts checkpoint.RestoreOptions) error {
+	if err := checkpoint.RunRestore(context.Background(), dockerCli, opts); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), opts.Container)
+	return nil
+}
@@ -0,0 +1,45 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRunRestore(t *testing.T) {
+	var started types.ContainerStartOptions
+	cli := test.NewFakeCli(&fakeClient{
+		infoFunc: func() (types.Info, error) {
+			return types.Info{OSType: "linux", ExperimentalBuild: true}, nil
+		},
+		checkpointListFunc: func(container string, options types.CheckpointListOptions) ([]types.Checkpoint, error) {
+			return []types.Checkpoint{{Name: "checkpoint-foo"}}, nil
+		},
+		containerStartFunc: func(container string, options types.ContainerStartOptions) error {
+			started = options
+			return nil
+		},
+	})
+	cmd := NewRestoreCommand(cli)
+	cmd.SetArgs([]string{"--checkpoint-dir", "/dir/foo", "container-foo", "checkpoint-foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(types.ContainerStartOptions{CheckpointID: "checkpoint-foo", CheckpointDir: "/dir/foo"}, started))
+	assert.Check(t, is.Equal("container-foo\n", cli.OutBuffer().String()))
+}
+
+func TestRunRestoreErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := NewRestoreCommand(cli)
+	cmd.SetArgs([]string{"container-foo"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "requires exactly 2 arguments"))
+
+	cmd = NewRestoreCommand(cli)
+	cmd.SetArgs([]string{"container-foo", "checkpoint-foo"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "checkpoints are not supported by the daemon"))
+}
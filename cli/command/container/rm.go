@@ -13,9 +13,10 @@ import (
 )
 
 type rmOptions struct {
-	rmVolumes bool
-	rmLink    bool
-	force     bool
+	rmVolumes      bool
+	rmLink         bool
+	force          bool
+	forceProtected bool
 
 	containers []string
 }
@@ -38,6 +39,7 @@ func NewRmCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.rmVolumes, "volumes", "v", false, "Remove the volumes associated with the container")
 	flags.BoolVarP(&opts.rmLink, "link", "l", false, "Remove the specified link")
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force the removal of a running container (uses SIGKILL)")
+	command.AddForceProtectedFlag(flags, &opts.forceProtected)
 	return cmd
 }
 
@@ -56,6 +58,13 @@ func runRm(dockerCli command.Cli, opts *rmOptions) error {
 		if container == "" {
 			return errors.New("Container name cannot be empty")
 		}
+		if !opts.forceProtected {
+			if c, err := dockerCli.Client().ContainerInspect(ctx, container); err == nil && c.Config != nil {
+				if err := command.CheckProtected("container", container, c.Config.Labels, false); err != nil {
+					return err
+				}
+			}
+		}
 		return dockerCli.Client().ContainerRemove(ctx, container, options)
 	})
 
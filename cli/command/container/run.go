@@ -12,6 +12,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -25,9 +26,12 @@ import (
 
 type runOptions struct {
 	createOptions
-	detach     bool
-	sigProxy   bool
-	detachKeys string
+	detach      bool
+	sigProxy    bool
+	detachKeys  string
+	record      string
+	transport   string
+	waitHealthy waitHealthyOptions
 }
 
 // NewRunCommand create a new `docker run` command
@@ -56,6 +60,11 @@ func NewRunCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.sigProxy, "sig-proxy", true, "Proxy received signals to the process")
 	flags.StringVar(&opts.name, "name", "", "Assign a name to the container")
 	flags.StringVar(&opts.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.StringVar(&opts.record, "record", "", "Record the session to a file, in the asciicast v2 format")
+	addAttachTransportFlag(flags, &opts.transport)
+	addDryRunFlags(flags, &opts.dryRun)
+	addWaitHealthyFlags(flags, &opts.waitHealthy)
+	addPresetFlag(flags, &opts.presets)
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -68,6 +77,14 @@ func NewRunCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runRun(dockerCli command.Cli, flags *pflag.FlagSet, ropts *runOptions, copts *containerOptions) error {
+	if err := checkPresetsExpanded(ropts.presets); err != nil {
+		return err
+	}
+	if ropts.dryRun.enabled {
+		if err := ropts.dryRun.validate(); err != nil {
+			return err
+		}
+	}
 	proxyConfig := dockerCli.ConfigFile().ParseProxyConfig(dockerCli.Client().DaemonHost(), opts.ConvertKVStringsToMapWithNil(copts.env.GetAll()))
 	newEnv := []string{}
 	for k, v := range proxyConfig {
@@ -101,18 +118,32 @@ func runContainer(dockerCli command.Cli, opts *runOptions, copts *containerOptio
 	config.ArgsEscaped = false
 
 	if !opts.detach {
-		if err := dockerCli.In().CheckTty(config.AttachStdin, config.Tty); err != nil {
-			return err
+		if opts.waitHealthy.enabled {
+			return errors.New("Conflicting options: --wait-healthy requires -d")
+		}
+		// The terminal is not used when only printing the create request
+		if !opts.dryRun.enabled {
+			if err := dockerCli.In().CheckTty(config.AttachStdin, config.Tty); err != nil {
+				return err
+			}
 		}
 	} else {
 		if copts.attach.Len() != 0 {
 			return errors.New("Conflicting options: -a and -d")
 		}
+		if opts.record != "" {
+			return errors.New("Conflicting options: --record and -d")
+		}
 
 		config.AttachStdin = false
 		config.AttachStdout = false
 		config.AttachStderr = false
 		config.StdinOnce = false
+
+		// Interrupting the wait must not stop the container
+		if opts.waitHealthy.enabled {
+			opts.sigProxy = false
+		}
 	}
 
 	// Disable sigProxy when in TTY mode
@@ -127,7 +158,11 @@ func runContainer(dockerCli command.Cli, opts *runOptions, copts *containerOptio
 		hostConfig.ConsoleSize[0], hostConfig.ConsoleSize[1] = dockerCli.Out().GetTtySize()
 	}
 
-	ctx, cancelFun := context.WithCancel(context.Background())
+	if opts.dryRun.enabled {
+		return printCreateRequest(stdout, opts.dryRun.format, containerConfig, &opts.createOptions)
+	}
+
+	ctx, cancelFun := context.WithCancel(dockerCli.Context())
 	defer cancelFun()
 
 	createResponse, err := createContainer(ctx, dockerCli, containerConfig, &opts.createOptions)
@@ -138,6 +173,7 @@ func runContainer(dockerCli command.Cli, opts *runOptions, copts *containerOptio
 	if opts.sigProxy {
 		sigc := ForwardAllSignals(ctx, dockerCli, createResponse.ID)
 		defer signal.StopCatch(sigc)
+		defer command.IgnoreCancelSignals()()
 	}
 
 	var (
@@ -158,7 +194,17 @@ func runContainer(dockerCli command.Cli, opts *runOptions, copts *containerOptio
 			dockerCli.ConfigFile().DetachKeys = opts.detachKeys
 		}
 
-		close, err := attachContainer(ctx, dockerCli, &errCh, config, createResponse.ID)
+		var recorder *streams.Recorder
+		if opts.record != "" {
+			var closeRecording func()
+			recorder, closeRecording, err = newSessionRecorder(dockerCli, opts.record)
+			if err != nil {
+				return err
+			}
+			defer closeRecording()
+		}
+
+		close, err := attachContainer(ctx, dockerCli, &errCh, config, createResponse.ID, opts.transport, recorder)
 
 		if err != nil {
 			return err
@@ -208,6 +254,9 @@ func runContainer(dockerCli command.Cli, opts *runOptions, copts *containerOptio
 	if !config.AttachStdout && !config.AttachStderr {
 		// Detached mode
 		<-waitDisplayID
+		if opts.waitHealthy.enabled {
+			return waitHealthy(ctx, dockerCli, createResponse.ID, opts.waitHealthy)
+		}
 		return nil
 	}
 
@@ -224,6 +273,8 @@ func attachContainer(
 	errCh *chan error,
 	config *container.Config,
 	containerID string,
+	transport string,
+	recorder *streams.Recorder,
 ) (func(), error) {
 	stdout, stderr := dockerCli.Out(), dockerCli.Err()
 	var (
@@ -252,7 +303,7 @@ func attachContainer(
 		DetachKeys: dockerCli.ConfigFile().DetachKeys,
 	}
 
-	resp, errAttach := dockerCli.Client().ContainerAttach(ctx, containerID, options)
+	resp, raw, errAttach := containerAttach(ctx, dockerCli, transport, containerID, options)
 	if errAttach != nil && errAttach != httputil.ErrPersistEOF {
 		// ContainerAttach returns an ErrPersistEOF (connection closed)
 		// means server met an error and put it in Hijacked connection
@@ -273,6 +324,8 @@ func attachContainer(
 				resp:         resp,
 				tty:          config.Tty,
 				detachKeys:   options.DetachKeys,
+				raw:          raw,
+				recorder:     recorder,
 			}
 
 			if errHijack := streamer.stream(ctx); errHijack != nil {
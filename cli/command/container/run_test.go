@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/notary"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"gotest.tools/assert"
@@ -27,6 +29,120 @@ func TestRunLabel(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 }
 
+func TestRunDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error) {
+			t.Fatal("the daemon should not be called with --dry-run")
+			return container.ContainerCreateCreatedBody{}, nil
+		},
+		Version: "1.36",
+	})
+	cmd := NewRunCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "-it", "--rm", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	output := cli.OutBuffer().String()
+	assert.Check(t, is.Contains(output, `"Tty": true`))
+	assert.Check(t, is.Contains(output, `"AutoRemove": true`))
+	assert.Check(t, is.Contains(output, `"Image": "busybox"`))
+}
+
+func TestRunWaitHealthy(t *testing.T) {
+	defer func(interval time.Duration) { healthPollInterval = interval }(healthPollInterval)
+	healthPollInterval = time.Millisecond
+
+	start := time.Now()
+	var inspections int
+	cli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error) {
+			return container.ContainerCreateCreatedBody{ID: "id"}, nil
+		},
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			inspections++
+			health := &types.Health{Status: types.Starting}
+			for i := 0; i < inspections; i++ {
+				health.Log = append(health.Log, &types.HealthcheckResult{Start: start.Add(time.Duration(i) * time.Second), ExitCode: 1, Output: "not ready\n"})
+			}
+			if inspections == 3 {
+				health.Status = types.Healthy
+				health.Log[2].ExitCode = 0
+				health.Log[2].Output = "ready"
+			}
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+				State: &types.ContainerState{Running: true, Health: health},
+			}}, nil
+		},
+		Version: "1.36",
+	})
+	cmd := NewRunCommand(cli)
+	cmd.SetArgs([]string{"--detach", "--wait-healthy", "--wait-healthy-verbose", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("id\n", cli.OutBuffer().String()))
+	expected := `health probe exited with code 1: not ready
+health probe exited with code 1: not ready
+health probe exited with code 0: ready
+`
+	assert.Check(t, is.Equal(expected, cli.ErrBuffer().String()))
+}
+
+func TestRunWaitHealthyErrors(t *testing.T) {
+	defer func(interval time.Duration) { healthPollInterval = interval }(healthPollInterval)
+	healthPollInterval = time.Millisecond
+
+	testCases := []struct {
+		doc           string
+		args          []string
+		state         types.ContainerState
+		expectedError string
+	}{
+		{
+			doc:           "not detached",
+			args:          []string{"--wait-healthy", "busybox"},
+			expectedError: "Conflicting options: --wait-healthy requires -d",
+		},
+		{
+			doc:           "no healthcheck",
+			state:         types.ContainerState{Running: true},
+			expectedError: "container id has no healthcheck",
+		},
+		{
+			doc:           "unhealthy",
+			state:         types.ContainerState{Running: true, Health: &types.Health{Status: types.Unhealthy}},
+			expectedError: "container id is unhealthy",
+		},
+		{
+			doc:           "exited",
+			state:         types.ContainerState{ExitCode: 3, Health: &types.Health{Status: types.Starting}},
+			expectedError: "container id exited with status 3 before being healthy",
+		},
+		{
+			doc:           "timeout",
+			args:          []string{"-d", "--wait-healthy", "--wait-healthy-timeout", "10ms", "busybox"},
+			state:         types.ContainerState{Running: true, Health: &types.Health{Status: types.Starting}},
+			expectedError: "container id is not healthy after 10ms",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		cli := test.NewFakeCli(&fakeClient{
+			createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, string) (container.ContainerCreateCreatedBody, error) {
+				return container.ContainerCreateCreatedBody{ID: "id"}, nil
+			},
+			inspectFunc: func(string) (types.ContainerJSON, error) {
+				return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &tc.state}}, nil
+			},
+			Version: "1.36",
+		})
+		cmd := NewRunCommand(cli)
+		cmd.SetOutput(ioutil.Discard)
+		args := tc.args
+		if args == nil {
+			args = []string{"-d", "--wait-healthy", "busybox"}
+		}
+		cmd.SetArgs(args)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError), tc.doc)
+	}
+}
+
 func TestRunCommandWithContentTrustErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
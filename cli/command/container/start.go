@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/checkpoint"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/pkg/term"
@@ -22,6 +23,7 @@ type startOptions struct {
 	detachKeys    string
 	checkpoint    string
 	checkpointDir string
+	transport     string
 
 	containers []string
 }
@@ -44,19 +46,20 @@ func NewStartCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.attach, "attach", "a", false, "Attach STDOUT/STDERR and forward signals")
 	flags.BoolVarP(&opts.openStdin, "interactive", "i", false, "Attach container's STDIN")
 	flags.StringVar(&opts.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	addAttachTransportFlag(flags, &opts.transport)
 
 	flags.StringVar(&opts.checkpoint, "checkpoint", "", "Restore from this checkpoint")
-	flags.SetAnnotation("checkpoint", "experimental", nil)
 	flags.SetAnnotation("checkpoint", "ostype", []string{"linux"})
+	flags.SetAnnotation("checkpoint", "version", []string{"1.25"})
 	flags.StringVar(&opts.checkpointDir, "checkpoint-dir", "", "Use a custom checkpoint storage directory")
-	flags.SetAnnotation("checkpoint-dir", "experimental", nil)
 	flags.SetAnnotation("checkpoint-dir", "ostype", []string{"linux"})
+	flags.SetAnnotation("checkpoint-dir", "version", []string{"1.25"})
 	return cmd
 }
 
 // nolint: gocyclo
 func runStart(dockerCli command.Cli, opts *startOptions) error {
-	ctx, cancelFun := context.WithCancel(context.Background())
+	ctx, cancelFun := context.WithCancel(dockerCli.Context())
 	defer cancelFun()
 
 	if opts.attach || opts.openStdin {
@@ -77,6 +80,7 @@ func runStart(dockerCli command.Cli, opts *startOptions) error {
 		if !c.Config.Tty {
 			sigc := ForwardAllSignals(ctx, dockerCli, c.ID)
 			defer signal.StopCatch(sigc)
+			defer command.IgnoreCancelSignals()()
 		}
 
 		if opts.detachKeys != "" {
@@ -97,7 +101,7 @@ func runStart(dockerCli command.Cli, opts *startOptions) error {
 			in = dockerCli.In()
 		}
 
-		resp, errAttach := dockerCli.Client().ContainerAttach(ctx, c.ID, options)
+		resp, raw, errAttach := containerAttach(ctx, dockerCli, opts.transport, c.ID, options)
 		if errAttach != nil && errAttach != httputil.ErrPersistEOF {
 			// ContainerAttach return an ErrPersistEOF (connection closed)
 			// means server met an error and already put it in Hijacked connection,
@@ -118,6 +122,7 @@ func runStart(dockerCli command.Cli, opts *startOptions) error {
 					resp:         resp,
 					tty:          c.Config.Tty,
 					detachKeys:   options.DetachKeys,
+					raw:          raw,
 				}
 
 				errHijack := streamer.stream(ctx)
@@ -168,12 +173,11 @@ func runStart(dockerCli command.Cli, opts *startOptions) error {
 		if len(opts.containers) > 1 {
 			return errors.New("you cannot restore multiple containers at once")
 		}
-		container := opts.containers[0]
-		startOptions := types.ContainerStartOptions{
-			CheckpointID:  opts.checkpoint,
+		return checkpoint.RunRestore(ctx, dockerCli, checkpoint.RestoreOptions{
+			Container:     opts.containers[0],
+			Checkpoint:    opts.checkpoint,
 			CheckpointDir: opts.checkpointDir,
-		}
-		return dockerCli.Client().ContainerStart(ctx, container, startOptions)
+		})
 
 	} else {
 		// We're not going to attach to anything.
@@ -200,6 +200,7 @@ func runStats(dockerCli command.Cli, opts *statsOptions) error {
 		Output: dockerCli.Out(),
 		Format: NewStatsFormat(format, daemonOSType),
 	}
+	trunc := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation).Trunc(opts.noTrunc)
 	cleanScreen := func() {
 		if !opts.noStream {
 			fmt.Fprint(dockerCli.Out(), "\033[2J")
@@ -216,7 +217,7 @@ func runStats(dockerCli command.Cli, opts *statsOptions) error {
 			ccstats = append(ccstats, c.GetStatistics())
 		}
 		cStats.mu.Unlock()
-		if err = statsFormatWrite(statsCtx, ccstats, daemonOSType, !opts.noTrunc); err != nil {
+		if err = statsFormatWrite(statsCtx, ccstats, daemonOSType, trunc); err != nil {
 			break
 		}
 		if len(cStats.cs) == 0 && !showAll {
@@ -0,0 +1,518 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/cli/compose/schema"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// composeFileVersion is the version of the compose files generated by
+// `docker container to-compose`
+const composeFileVersion = "3.8"
+
+// defaultShmSize is the size of /dev/shm used by the daemon if no size is
+// specified when creating a container.
+const defaultShmSize = 64 * 1024 * 1024
+
+type toComposeOptions struct {
+	containers []string
+}
+
+// NewToComposeCommand creates a new cobra.Command for `docker container to-compose`
+func NewToComposeCommand(dockerCli command.Cli) *cobra.Command {
+	var opts toComposeOptions
+
+	cmd := &cobra.Command{
+		Use:   "to-compose CONTAINER [CONTAINER...]",
+		Short: "Generate a Compose file from one or more containers",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.containers = args
+			return runToCompose(dockerCli, &opts)
+		},
+	}
+	return cmd
+}
+
+func runToCompose(dockerCli command.Cli, opts *toComposeOptions) error {
+	ctx := context.Background()
+	config := composetypes.Config{
+		Version:  composeFileVersion,
+		Networks: map[string]composetypes.NetworkConfig{},
+		Volumes:  map[string]composetypes.VolumeConfig{},
+	}
+	for _, name := range opts.containers {
+		c, err := dockerCli.Client().ContainerInspect(ctx, name)
+		if err != nil {
+			return err
+		}
+		// The image configuration is used to omit the settings that the
+		// container inherits from its image. If the image is no longer
+		// present, all settings are included.
+		var imageConfig *container.Config
+		image, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, c.Image)
+		switch {
+		case err == nil:
+			imageConfig = image.Config
+		case !client.IsErrNotFound(err):
+			return err
+		}
+		service, err := containerToService(c, imageConfig)
+		if err != nil {
+			return errors.Wrapf(err, "cannot convert container %s", name)
+		}
+		for _, s := range config.Services {
+			if s.Name == service.Name {
+				return errors.Errorf("duplicate service name %q", service.Name)
+			}
+		}
+
+		for _, v := range service.Volumes {
+			if v.Type == string(mount.TypeVolume) && v.Source != "" {
+				config.Volumes[v.Source] = composetypes.VolumeConfig{External: composetypes.External{External: true}}
+			}
+		}
+		for nw := range service.Networks {
+			config.Networks[nw] = composetypes.NetworkConfig{External: composetypes.External{External: true}}
+		}
+		escapeInterpolation(reflect.ValueOf(&service).Elem())
+		config.Services = append(config.Services, service)
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	// Make sure that the generated file can be used with the compose loader.
+	parsed, err := loader.ParseYAML(out)
+	if err != nil {
+		return err
+	}
+	if err := schema.Validate(parsed, composeFileVersion); err != nil {
+		return errors.Wrap(err, "failed to generate a valid Compose file")
+	}
+	_, err = dockerCli.Out().Write(out)
+	return err
+}
+
+// containerToService converts the configuration of a container to a service
+// definition. Settings that are equal to the image's configuration (if
+// known), or to the daemon's defaults, are omitted.
+func containerToService(c types.ContainerJSON, imageConfig *container.Config) (composetypes.ServiceConfig, error) {
+	if imageConfig == nil {
+		imageConfig = &container.Config{}
+	}
+	cfg := c.Config
+	if cfg == nil {
+		cfg = &container.Config{}
+	}
+	hostConfig := c.HostConfig
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+	name := strings.TrimPrefix(c.Name, "/")
+
+	service := composetypes.ServiceConfig{
+		Name:          name,
+		ContainerName: name,
+		Image:         cfg.Image,
+		Labels:        composetypes.Labels(mapDifference(cfg.Labels, imageConfig.Labels)),
+		Environment:   envDifference(cfg.Env, imageConfig.Env),
+		Tty:           cfg.Tty,
+		StdinOpen:     cfg.OpenStdin,
+		Privileged:    hostConfig.Privileged,
+		ReadOnly:      hostConfig.ReadonlyRootfs,
+		CapAdd:        hostConfig.CapAdd,
+		CapDrop:       hostConfig.CapDrop,
+		DNS:           composetypes.StringList(hostConfig.DNS),
+		DNSSearch:     composetypes.StringList(hostConfig.DNSSearch),
+		ExtraHosts:    composetypes.HostsList(hostConfig.ExtraHosts),
+		SecurityOpt:   hostConfig.SecurityOpt,
+		Init:          hostConfig.Init,
+		CgroupParent:  hostConfig.CgroupParent,
+	}
+	if !hostConfig.Isolation.IsDefault() {
+		service.Isolation = string(hostConfig.Isolation)
+	}
+	if !stringSliceEqual(cfg.Entrypoint, imageConfig.Entrypoint) {
+		service.Entrypoint = composetypes.ShellCommand(cfg.Entrypoint)
+	}
+	if !stringSliceEqual(cfg.Cmd, imageConfig.Cmd) || service.Entrypoint != nil {
+		service.Command = composetypes.ShellCommand(cfg.Cmd)
+	}
+	if cfg.User != imageConfig.User {
+		service.User = cfg.User
+	}
+	if cfg.WorkingDir != imageConfig.WorkingDir {
+		service.WorkingDir = cfg.WorkingDir
+	}
+	if cfg.StopSignal != imageConfig.StopSignal {
+		service.StopSignal = cfg.StopSignal
+	}
+	if cfg.StopTimeout != nil {
+		d := composetypes.Duration(time.Duration(*cfg.StopTimeout) * time.Second)
+		service.StopGracePeriod = &d
+	}
+	// The hostname defaults to the short ID of the container
+	if cfg.Hostname != "" && !strings.HasPrefix(c.ID, cfg.Hostname) {
+		service.Hostname = cfg.Hostname
+	}
+	service.DomainName = cfg.Domainname
+	service.MacAddress = cfg.MacAddress
+	if cfg.Healthcheck != nil && !healthcheckEqual(cfg.Healthcheck, imageConfig.Healthcheck) {
+		service.HealthCheck = convertHealthcheck(cfg.Healthcheck)
+	}
+
+	service.Restart = convertRestartPolicy(hostConfig.RestartPolicy)
+	ports, expose, err := convertPorts(cfg, hostConfig, imageConfig)
+	if err != nil {
+		return composetypes.ServiceConfig{}, err
+	}
+	service.Ports, service.Expose = ports, expose
+	service.Volumes = convertMounts(c.Mounts, hostConfig)
+	for path := range hostConfig.Tmpfs {
+		service.Tmpfs = append(service.Tmpfs, path)
+	}
+	sort.Strings(service.Tmpfs)
+	for _, d := range hostConfig.Devices {
+		device := d.PathOnHost + ":" + d.PathInContainer
+		if d.CgroupPermissions != "" && d.CgroupPermissions != "rwm" {
+			device += ":" + d.CgroupPermissions
+		}
+		service.Devices = append(service.Devices, device)
+	}
+	for _, key := range sortedKeys(hostConfig.Sysctls) {
+		service.Sysctls = append(service.Sysctls, key+"="+hostConfig.Sysctls[key])
+	}
+	for _, u := range hostConfig.Ulimits {
+		if service.Ulimits == nil {
+			service.Ulimits = map[string]*composetypes.UlimitsConfig{}
+		}
+		if u.Soft == u.Hard {
+			service.Ulimits[u.Name] = &composetypes.UlimitsConfig{Single: int(u.Soft)}
+		} else {
+			service.Ulimits[u.Name] = &composetypes.UlimitsConfig{Soft: int(u.Soft), Hard: int(u.Hard)}
+		}
+	}
+	if hostConfig.ShmSize != 0 && hostConfig.ShmSize != defaultShmSize {
+		service.ShmSize = strconv.FormatInt(hostConfig.ShmSize, 10)
+	}
+	if mode := string(hostConfig.PidMode); mode != "" {
+		service.Pid = mode
+	}
+	if mode := string(hostConfig.IpcMode); mode != "" && mode != "private" && mode != "shareable" {
+		service.Ipc = mode
+	}
+	if mode := string(hostConfig.UsernsMode); mode != "" {
+		service.UserNSMode = mode
+	}
+	// The json-file driver without options is treated as the default, as
+	// the daemon's default logging driver is not known here.
+	if lc := hostConfig.LogConfig; lc.Type != "" && (lc.Type != "json-file" || len(lc.Config) > 0) {
+		service.Logging = &composetypes.LoggingConfig{Driver: lc.Type, Options: lc.Config}
+	}
+	if hostConfig.NanoCPUs != 0 || hostConfig.Memory != 0 {
+		limits := &composetypes.Resource{MemoryBytes: composetypes.UnitBytes(hostConfig.Memory)}
+		if hostConfig.NanoCPUs != 0 {
+			limits.NanoCPUs = strconv.FormatFloat(float64(hostConfig.NanoCPUs)/1e9, 'f', -1, 64)
+		}
+		service.Deploy.Resources.Limits = limits
+	}
+
+	convertNetworks(&service, c)
+	return service, nil
+}
+
+func convertRestartPolicy(policy container.RestartPolicy) string {
+	switch {
+	case policy.Name == "" || policy.Name == "no":
+		return ""
+	case policy.IsOnFailure() && policy.MaximumRetryCount > 0:
+		return fmt.Sprintf("on-failure:%d", policy.MaximumRetryCount)
+	}
+	return policy.Name
+}
+
+func convertHealthcheck(hc *container.HealthConfig) *composetypes.HealthCheckConfig {
+	if len(hc.Test) == 1 && hc.Test[0] == "NONE" {
+		return &composetypes.HealthCheckConfig{Disable: true}
+	}
+	durationPtr := func(d time.Duration) *composetypes.Duration {
+		if d == 0 {
+			return nil
+		}
+		cd := composetypes.Duration(d)
+		return &cd
+	}
+	healthcheck := &composetypes.HealthCheckConfig{
+		Test:        composetypes.HealthCheckTest(hc.Test),
+		Interval:    durationPtr(hc.Interval),
+		Timeout:     durationPtr(hc.Timeout),
+		StartPeriod: durationPtr(hc.StartPeriod),
+	}
+	if hc.Retries != 0 {
+		retries := uint64(hc.Retries)
+		healthcheck.Retries = &retries
+	}
+	return healthcheck
+}
+
+// convertPorts returns the published ports of the container, and the ports
+// that are exposed by the container but not by its image. The ports published
+// on a specific host IP are rejected, as the ports of a Compose file are
+// published on all interfaces.
+func convertPorts(cfg *container.Config, hostConfig *container.HostConfig, imageConfig *container.Config) ([]composetypes.ServicePortConfig, composetypes.StringOrNumberList, error) {
+	var (
+		ports  []composetypes.ServicePortConfig
+		expose composetypes.StringOrNumberList
+	)
+	for port, bindings := range hostConfig.PortBindings {
+		for _, binding := range bindings {
+			if binding.HostIP != "" && binding.HostIP != "0.0.0.0" && binding.HostIP != "::" {
+				return nil, nil, errors.Errorf("port %s is published on %s, but the ports of a Compose file are published on all interfaces", port, binding.HostIP)
+			}
+			published, _ := strconv.ParseUint(binding.HostPort, 10, 32)
+			ports = append(ports, composetypes.ServicePortConfig{
+				Target:    uint32(port.Int()),
+				Published: uint32(published),
+				Protocol:  port.Proto(),
+			})
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Target != ports[j].Target {
+			return ports[i].Target < ports[j].Target
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Published < ports[j].Published
+	})
+	for port := range cfg.ExposedPorts {
+		if _, ok := imageConfig.ExposedPorts[port]; ok {
+			continue
+		}
+		if _, ok := hostConfig.PortBindings[port]; ok {
+			continue
+		}
+		expose = append(expose, string(port))
+	}
+	sort.Strings(expose)
+	return ports, expose, nil
+}
+
+// convertMounts converts the mounts of a container to service volumes.
+// Volumes that were not created from a bind or mount option of the
+// container (such as the anonymous volumes of the image) are converted to
+// anonymous volumes.
+func convertMounts(mounts []types.MountPoint, hostConfig *container.HostConfig) []composetypes.ServiceVolumeConfig {
+	named := map[string]bool{}
+	for _, m := range hostConfig.Mounts {
+		if m.Type == mount.TypeVolume && m.Source != "" {
+			named[m.Target] = true
+		}
+	}
+	for _, bind := range hostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) >= 2 && !strings.ContainsAny(parts[0], `/\`) {
+			named[parts[1]] = true
+		}
+	}
+
+	var volumes []composetypes.ServiceVolumeConfig
+	for _, m := range mounts {
+		v := composetypes.ServiceVolumeConfig{
+			Type:     string(m.Type),
+			Target:   m.Destination,
+			ReadOnly: !m.RW,
+		}
+		switch m.Type {
+		case mount.TypeBind:
+			v.Source = m.Source
+			if m.Propagation != "" && m.Propagation != mount.PropagationRPrivate {
+				v.Bind = &composetypes.ServiceVolumeBind{Propagation: string(m.Propagation)}
+			}
+		case mount.TypeVolume:
+			if named[m.Destination] {
+				v.Source = m.Name
+			}
+		case mount.TypeTmpfs:
+			// tmpfs mounts are listed in the container's HostConfig
+			continue
+		default:
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Target < volumes[j].Target
+	})
+	return volumes
+}
+
+// convertNetworks sets the network mode of the service, or the networks it
+// is connected to if the container uses user-defined networks.
+func convertNetworks(service *composetypes.ServiceConfig, c types.ContainerJSON) {
+	mode := container.NetworkMode("default")
+	if c.HostConfig != nil && c.HostConfig.NetworkMode != "" {
+		mode = c.HostConfig.NetworkMode
+	}
+	if !mode.IsUserDefined() {
+		if !mode.IsDefault() && !mode.IsBridge() {
+			service.NetworkMode = string(mode)
+		}
+		return
+	}
+	if c.NetworkSettings == nil {
+		return
+	}
+	shortID := c.ID
+	if len(shortID) > 12 {
+		shortID = shortID[:12]
+	}
+	for name, settings := range c.NetworkSettings.Networks {
+		if service.Networks == nil {
+			service.Networks = map[string]*composetypes.ServiceNetworkConfig{}
+		}
+		var nw *composetypes.ServiceNetworkConfig
+		if settings != nil {
+			var aliases []string
+			for _, alias := range settings.Aliases {
+				// The daemon adds the short ID of the container as an alias
+				if alias != shortID && alias != service.Name {
+					aliases = append(aliases, alias)
+				}
+			}
+			var ipv4 string
+			if settings.IPAMConfig != nil {
+				ipv4 = settings.IPAMConfig.IPv4Address
+			}
+			if len(aliases) > 0 || ipv4 != "" {
+				nw = &composetypes.ServiceNetworkConfig{Aliases: aliases, Ipv4Address: ipv4}
+			}
+		}
+		service.Networks[name] = nw
+	}
+}
+
+// envDifference returns the environment variables that are not set, or set
+// to a different value, in the image.
+func envDifference(env, imageEnv []string) composetypes.MappingWithEquals {
+	inherited := map[string]bool{}
+	for _, e := range imageEnv {
+		inherited[e] = true
+	}
+	var result composetypes.MappingWithEquals
+	for _, e := range env {
+		if inherited[e] {
+			continue
+		}
+		if result == nil {
+			result = composetypes.MappingWithEquals{}
+		}
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 1 {
+			result[kv[0]] = nil
+			continue
+		}
+		value := kv[1]
+		result[kv[0]] = &value
+	}
+	return result
+}
+
+// escapeInterpolation escapes the "$" of the strings of v as "$$", so that the
+// compose loader does not interpolate them as variables. The keys of the maps,
+// which are not interpolated, and the fields which are not written to the
+// Compose file are unchanged.
+func escapeInterpolation(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(strings.Replace(v.String(), "$", "$$", -1))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			escapeInterpolation(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Tag.Get("yaml") == "-" {
+				continue
+			}
+			escapeInterpolation(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			escapeInterpolation(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			escapeInterpolation(value)
+			v.SetMapIndex(key, value)
+		}
+	}
+}
+
+func mapDifference(m, base map[string]string) map[string]string {
+	var result map[string]string
+	for k, v := range m {
+		if bv, ok := base[k]; ok && bv == v {
+			continue
+		}
+		if result == nil {
+			result = map[string]string{}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func healthcheckEqual(a, b *container.HealthConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return stringSliceEqual(a.Test, b.Test) && a.Interval == b.Interval && a.Timeout == b.Timeout &&
+		a.StartPeriod == b.StartPeriod && a.Retries == b.Retries
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
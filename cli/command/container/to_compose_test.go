@@ -0,0 +1,190 @@
+package container
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/loader"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func TestToCompose(t *testing.T) {
+	stopTimeout := 30
+	containers := map[string]types.ContainerJSON{
+		"web": {
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "3f4ab2c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2",
+				Name:  "/web",
+				Image: "sha256:nginx",
+				HostConfig: &container.HostConfig{
+					NetworkMode:   "frontend",
+					RestartPolicy: container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3},
+					PortBindings: nat.PortMap{
+						"80/tcp":  {{HostPort: "8080"}},
+						"443/tcp": {{HostIP: "0.0.0.0", HostPort: "8443"}},
+					},
+					Binds:   []string{"webdata:/usr/share/nginx/html:ro", "/etc/web:/etc/nginx/conf.d"},
+					CapAdd:  []string{"NET_ADMIN"},
+					ShmSize: 64 * 1024 * 1024,
+					Resources: container.Resources{
+						NanoCPUs: 1500000000,
+						Memory:   512 * 1024 * 1024,
+						Ulimits:  []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}},
+					},
+					LogConfig: container.LogConfig{Type: "json-file"},
+				},
+			},
+			Mounts: []types.MountPoint{
+				{Type: mount.TypeVolume, Name: "webdata", Destination: "/usr/share/nginx/html"},
+				{Type: mount.TypeBind, Source: "/etc/web", Destination: "/etc/nginx/conf.d", RW: true, Propagation: mount.PropagationRPrivate},
+				{Type: mount.TypeVolume, Name: "8d7c6b5a4f3e", Destination: "/var/cache/nginx", RW: true},
+			},
+			Config: &container.Config{
+				Hostname:    "3f4ab2c4d5e6",
+				Image:       "nginx:alpine",
+				Env:         []string{"PATH=/usr/local/bin:/usr/bin", "NGINX_VERSION=1.17", "MODE=production"},
+				Cmd:         []string{"nginx", "-g", "daemon off;"},
+				Labels:      map[string]string{"maintainer": "nginx", "tier": "frontend"},
+				StopTimeout: &stopTimeout,
+			},
+			NetworkSettings: &types.NetworkSettings{
+				Networks: map[string]*network.EndpointSettings{
+					"frontend": {Aliases: []string{"3f4ab2c4d5e6", "www"}},
+				},
+			},
+		},
+		"db": {
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b",
+				Name:  "/db",
+				Image: "sha256:postgres",
+				HostConfig: &container.HostConfig{
+					NetworkMode: "host",
+				},
+			},
+			Config: &container.Config{
+				Hostname: "database",
+				Image:    "postgres:11",
+				Env:      []string{"POSTGRES_PASSWORD"},
+				Healthcheck: &container.HealthConfig{
+					Test:     []string{"CMD-SHELL", "pg_isready"},
+					Interval: 10000000000,
+					Retries:  5,
+				},
+			},
+		},
+	}
+	images := map[string]types.ImageInspect{
+		"sha256:nginx": {
+			Config: &container.Config{
+				Env:    []string{"PATH=/usr/local/bin:/usr/bin", "NGINX_VERSION=1.17"},
+				Cmd:    []string{"nginx", "-g", "daemon off;"},
+				Labels: map[string]string{"maintainer": "nginx"},
+			},
+		},
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return containers[name], nil
+		},
+		imageInspectFunc: func(image string) (types.ImageInspect, []byte, error) {
+			if i, ok := images[image]; ok {
+				return i, nil, nil
+			}
+			return types.ImageInspect{}, nil, errdefsNotFound{errors.New("no such image")}
+		},
+	})
+	cmd := NewToComposeCommand(cli)
+	cmd.SetArgs([]string{"web", "db"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "container-to-compose.golden")
+}
+
+func TestToComposeDuplicateName(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: "abc", Name: "/web"},
+				Config:            &container.Config{Image: "nginx"},
+			}, nil
+		},
+	})
+	cmd := NewToComposeCommand(cli)
+	cmd.SetArgs([]string{"web", "abc"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), `duplicate service name "web"`)
+}
+
+func TestToComposeHostIP(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:   "abc",
+					Name: "/web",
+					HostConfig: &container.HostConfig{
+						PortBindings: nat.PortMap{"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}}},
+					},
+				},
+				Config: &container.Config{Image: "nginx"},
+			}, nil
+		},
+	})
+	cmd := NewToComposeCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), "cannot convert container web: port 80/tcp is published on 127.0.0.1, but the ports of a Compose file are published on all interfaces")
+	assert.Equal(t, cli.OutBuffer().String(), "")
+}
+
+func TestToComposeEscapesInterpolation(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: "abc", Name: "/web"},
+				Config: &container.Config{
+					Image:      "nginx",
+					Env:        []string{"PASS=p$ecret", "PRICE=$$5"},
+					Entrypoint: []string{"sh", "-c"},
+					Cmd:        []string{"echo $HOME ${USER}"},
+					Labels:     map[string]string{"cost": "$5"},
+				},
+			}, nil
+		},
+	})
+	cmd := NewToComposeCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	assert.NilError(t, cmd.Execute())
+
+	parsed, err := loader.ParseYAML(cli.OutBuffer().Bytes())
+	assert.NilError(t, err)
+	config, err := loader.Load(composetypes.ConfigDetails{
+		ConfigFiles: []composetypes.ConfigFile{{Filename: "docker-compose.yml", Config: parsed}},
+		Environment: map[string]string{"HOME": "/root", "USER": "root"},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(config.Services, 1))
+	service := config.Services[0]
+	pass, price := "p$ecret", "$$5"
+	assert.Check(t, is.DeepEqual(service.Environment, composetypes.MappingWithEquals{"PASS": &pass, "PRICE": &price}))
+	assert.Check(t, is.DeepEqual(service.Entrypoint, composetypes.ShellCommand{"sh", "-c"}))
+	assert.Check(t, is.DeepEqual(service.Command, composetypes.ShellCommand{"echo $HOME ${USER}"}))
+	assert.Check(t, is.DeepEqual(service.Labels, composetypes.Labels{"cost": "$5"}))
+}
+
+type errdefsNotFound struct {
+	error
+}
+
+func (errdefsNotFound) NotFound() bool { return true }
@@ -2,17 +2,48 @@ package container
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/templates"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+// Statuses of the containers in the summary of docker wait
+const (
+	waitStatusExited  = "exited"
+	waitStatusRunning = "running"
+	waitStatusError   = "error"
+)
+
 type waitOptions struct {
 	containers []string
+	any        bool
+	all        bool
+	timeout    time.Duration
+	format     string
+}
+
+// waitSummary is the summary of docker wait, printed with --format
+type waitSummary struct {
+	Containers []waitResult `json:"containers"`
+	// TimedOut is set if the timeout expired before the containers exited
+	TimedOut bool `json:"timedOut"`
+}
+
+// waitResult is the result of the wait for a container. Its exit code is nil
+// if the container did not exit, either because the timeout expired, or
+// because another container exited first with --any.
+type waitResult struct {
+	Container string `json:"container"`
+	Status    string `json:"status"`
+	ExitCode  *int64 `json:"exitCode"`
+	Error     string `json:"error,omitempty"`
 }
 
 // NewWaitCommand creates a new cobra.Command for `docker wait`
@@ -20,7 +51,7 @@ func NewWaitCommand(dockerCli command.Cli) *cobra.Command {
 	var opts waitOptions
 
 	cmd := &cobra.Command{
-		Use:   "wait CONTAINER [CONTAINER...]",
+		Use:   "wait [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Block until one or more containers stop, then print their exit codes",
 		Args:  cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -29,25 +60,118 @@ func NewWaitCommand(dockerCli command.Cli) *cobra.Command {
 		},
 	}
 
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.any, "any", false, "Return as soon as one of the containers stops")
+	flags.BoolVar(&opts.all, "all", false, "Return once all the containers stop (default)")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Maximum time to wait for the containers (0 to wait forever)")
+	flags.StringVarP(&opts.format, "format", "f", "", `Print a summary of the exit codes using the given Go template, or "json"`)
 	return cmd
 }
 
 func runWait(dockerCli command.Cli, opts *waitOptions) error {
-	ctx := context.Background()
+	if opts.any && opts.all {
+		return errors.New("--any and --all are mutually exclusive")
+	}
+
+	ctx, cancel := context.WithCancel(dockerCli.Context())
+	if opts.timeout > 0 {
+		ctx, cancel = context.WithTimeout(dockerCli.Context(), opts.timeout)
+	}
+	defer cancel()
 
-	var errs []string
-	for _, container := range opts.containers {
-		resultC, errC := dockerCli.Client().ContainerWait(ctx, container, "")
+	type response struct {
+		index  int
+		result waitResult
+	}
+	// The containers are waited for concurrently, so that a container which
+	// exits, and is removed, before the previous ones is not missed
+	responses := make(chan response, len(opts.containers))
+	summary := waitSummary{Containers: make([]waitResult, len(opts.containers))}
+	for i, container := range opts.containers {
+		summary.Containers[i] = waitResult{Container: container, Status: waitStatusRunning}
+		go func(i int, container string) {
+			resultC, errC := dockerCli.Client().ContainerWait(ctx, container, "")
+			result := waitResult{Container: container}
+			select {
+			case body := <-resultC:
+				result.Status = waitStatusExited
+				result.ExitCode = &body.StatusCode
+				if body.Error != nil {
+					result.Error = body.Error.Message
+				}
+			case err := <-errC:
+				result.Status = waitStatusError
+				result.Error = err.Error()
+			case <-ctx.Done():
+				return
+			}
+			responses <- response{index: i, result: result}
+		}(i, container)
+	}
 
+	var exited bool
+	for pending := len(opts.containers); pending > 0 && !(opts.any && exited); pending-- {
 		select {
-		case result := <-resultC:
-			fmt.Fprintf(dockerCli.Out(), "%d\n", result.StatusCode)
-		case err := <-errC:
-			errs = append(errs, err.Error())
+		case r := <-responses:
+			summary.Containers[r.index] = r.result
+			exited = exited || r.result.Status == waitStatusExited
+		case <-ctx.Done():
+			if ctx.Err() != context.DeadlineExceeded {
+				return ctx.Err()
+			}
+			summary.TimedOut = true
+			pending = 0
 		}
 	}
+	cancel()
+
+	if err := printWaitSummary(dockerCli, summary, opts.format); err != nil {
+		return err
+	}
+
+	var errs, running []string
+	for _, result := range summary.Containers {
+		switch result.Status {
+		case waitStatusError:
+			errs = append(errs, result.Error)
+		case waitStatusRunning:
+			running = append(running, result.Container)
+		}
+	}
+	if summary.TimedOut {
+		errs = append(errs, fmt.Sprintf("timeout waiting for %s after %s", strings.Join(running, ", "), opts.timeout))
+		return cli.StatusError{StatusCode: 1, Status: strings.Join(errs, "\n")}
+	}
 	if len(errs) > 0 {
 		return errors.New(strings.Join(errs, "\n"))
 	}
 	return nil
 }
+
+// printWaitSummary prints the exit codes of the containers which exited, in
+// the order of the arguments, or the summary with a format
+func printWaitSummary(dockerCli command.Cli, summary waitSummary, format string) error {
+	switch format {
+	case "":
+		for _, result := range summary.Containers {
+			if result.ExitCode != nil {
+				fmt.Fprintf(dockerCli.Out(), "%d\n", *result.ExitCode)
+			}
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(summary)
+	default:
+		tmpl, err := templates.Parse(format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "Template parsing error: " + err.Error()}
+		}
+		if err := tmpl.Execute(dockerCli.Out(), summary); err != nil {
+			return err
+		}
+		fmt.Fprintln(dockerCli.Out())
+		return nil
+	}
+}
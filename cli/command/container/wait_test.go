@@ -0,0 +1,102 @@
+package container
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// newWaitClient returns a client whose containers exit with their exit code
+// after their delay, or never if they have none
+func newWaitClient(exitCodes map[string]int64, delays map[string]time.Duration) *fakeClient {
+	return &fakeClient{
+		waitFunc: func(name string) (<-chan container.ContainerWaitOKBody, <-chan error) {
+			resultC := make(chan container.ContainerWaitOKBody, 1)
+			errC := make(chan error, 1)
+			code, ok := exitCodes[name]
+			switch {
+			case name == "missing":
+				errC <- errors.New("Error: No such container: missing")
+			case ok:
+				go func() {
+					time.Sleep(delays[name])
+					resultC <- container.ContainerWaitOKBody{StatusCode: code}
+				}()
+			}
+			return resultC, errC
+		},
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	cli := test.NewFakeCli(newWaitClient(
+		map[string]int64{"first": 1, "second": 2},
+		map[string]time.Duration{"first": 20 * time.Millisecond},
+	))
+	cmd := NewWaitCommand(cli)
+	cmd.SetArgs([]string{"first", "second"})
+	assert.NilError(t, cmd.Execute())
+	// The exit codes are printed in the order of the arguments
+	assert.Check(t, is.Equal("1\n2\n", cli.OutBuffer().String()))
+}
+
+func TestWaitAny(t *testing.T) {
+	cli := test.NewFakeCli(newWaitClient(map[string]int64{"second": 3}, nil))
+	cmd := NewWaitCommand(cli)
+	cmd.SetArgs([]string{"--any", "--format", "json", "first", "second"})
+	assert.NilError(t, cmd.Execute())
+
+	var summary waitSummary
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &summary))
+	code := int64(3)
+	assert.Check(t, is.DeepEqual(summary, waitSummary{Containers: []waitResult{
+		{Container: "first", Status: waitStatusRunning},
+		{Container: "second", Status: waitStatusExited, ExitCode: &code},
+	}}))
+}
+
+func TestWaitTimeout(t *testing.T) {
+	cli := test.NewFakeCli(newWaitClient(map[string]int64{"first": 0}, nil))
+	cmd := NewWaitCommand(cli)
+	cmd.SetArgs([]string{"--timeout", "10ms", "--format", "{{.TimedOut}}{{range .Containers}} {{.Status}}{{end}}", "first", "second", "third"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "timeout waiting for second, third after 10ms")
+	assert.Check(t, is.Equal("true exited running running\n", cli.OutBuffer().String()))
+}
+
+func TestWaitErrors(t *testing.T) {
+	testCases := []struct {
+		args           []string
+		expectedError  string
+		expectedOutput string
+	}{
+		{
+			args:           []string{"first", "missing"},
+			expectedError:  "Error: No such container: missing",
+			expectedOutput: "4\n",
+		},
+		{
+			args:          []string{"--any", "--all", "first"},
+			expectedError: "--any and --all are mutually exclusive",
+		},
+		{
+			args:          []string{"--format", "{{.Unknown", "first"},
+			expectedError: "Template parsing error",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(newWaitClient(map[string]int64{"first": 4}, nil))
+		cmd := NewWaitCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		assert.Check(t, is.Equal(tc.expectedOutput, cli.OutBuffer().String()))
+	}
+}
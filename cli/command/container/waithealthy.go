@@ -0,0 +1,95 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// healthPollInterval is the interval between two inspections of the health
+// of a container
+var healthPollInterval = 500 * time.Millisecond
+
+// waitHealthyOptions are the options for waiting until a container is healthy
+// after starting it.
+type waitHealthyOptions struct {
+	enabled bool
+	timeout time.Duration
+	verbose bool
+}
+
+func addWaitHealthyFlags(flags *pflag.FlagSet, opts *waitHealthyOptions) {
+	flags.BoolVar(&opts.enabled, "wait-healthy", false, "Wait until the healthcheck of the container reports healthy (requires --detach)")
+	flags.DurationVar(&opts.timeout, "wait-healthy-timeout", 0, "Maximum time to wait for the container to be healthy (0 to wait forever)")
+	flags.BoolVar(&opts.verbose, "wait-healthy-verbose", false, "Print the output of the health probes while waiting for the container to be healthy")
+}
+
+// waitHealthy waits until the healthcheck of the container reports healthy.
+// It returns a StatusError if the container is unhealthy, exits, or is not
+// healthy before the timeout.
+func waitHealthy(ctx context.Context, dockerCli command.Cli, containerID string, opts waitHealthyOptions) error {
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	var lastProbe time.Time
+	for {
+		c, err := dockerCli.Client().ContainerInspect(ctx, containerID)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return healthTimeoutError(containerID, opts.timeout)
+			}
+			return err
+		}
+		if c.State == nil {
+			return errors.Errorf("unable to inspect the state of container %s", containerID)
+		}
+		health := c.State.Health
+		if health == nil || health.Status == types.NoHealthcheck {
+			return errors.Errorf("container %s has no healthcheck", containerID)
+		}
+		if opts.verbose {
+			for _, probe := range health.Log {
+				if probe != nil && probe.Start.After(lastProbe) {
+					printHealthProbe(dockerCli, probe)
+					lastProbe = probe.Start
+				}
+			}
+		}
+
+		switch {
+		case health.Status == types.Healthy:
+			return nil
+		case health.Status == types.Unhealthy:
+			return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("container %s is unhealthy", containerID)}
+		case !c.State.Running && !c.State.Restarting:
+			return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("container %s exited with status %d before being healthy", containerID, c.State.ExitCode)}
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return healthTimeoutError(containerID, opts.timeout)
+			}
+			return ctx.Err()
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+func healthTimeoutError(containerID string, timeout time.Duration) error {
+	return cli.StatusError{StatusCode: 1, Status: fmt.Sprintf("container %s is not healthy after %s", containerID, timeout)}
+}
+
+func printHealthProbe(dockerCli command.Cli, probe *types.HealthcheckResult) {
+	fmt.Fprintf(dockerCli.Err(), "health probe exited with code %d: %s\n", probe.ExitCode, strings.TrimSpace(probe.Output))
+}
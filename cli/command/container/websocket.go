@@ -0,0 +1,393 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+// Transports of the attached streams of the containers
+const (
+	// attachTransportAuto uses the WebSocket transport if the daemon is
+	// reached through an HTTP proxy, or if the hijacked connection cannot
+	// be upgraded, and the hijacked connection otherwise.
+	attachTransportAuto = "auto"
+	// attachTransportHijack hijacks the HTTP connection to the daemon, as
+	// with "docker exec".
+	attachTransportHijack = "hijack"
+	// attachTransportWebSocket uses the WebSocket endpoint of the daemon,
+	// which goes through the HTTP proxies and load balancers, but does not
+	// separate the output and error streams of the containers.
+	attachTransportWebSocket = "websocket"
+)
+
+// webSocketGUID is the GUID of the handshake of the WebSocket protocol
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes of the frames of the WebSocket protocol
+const (
+	wsContinuationFrame = 0x0
+	wsTextFrame         = 0x1
+	wsBinaryFrame       = 0x2
+	wsCloseFrame        = 0x8
+	wsPingFrame         = 0x9
+	wsPongFrame         = 0xa
+)
+
+func addAttachTransportFlag(flags *pflag.FlagSet, transport *string) {
+	flags.StringVar(transport, "attach-transport", "", `Transport of the attached streams ("auto", "hijack", "websocket")`)
+}
+
+// resolveAttachTransport returns the transport of the attached streams of the
+// --attach-transport flag, or of the configuration file, or
+// attachTransportAuto.
+func resolveAttachTransport(dockerCli command.Cli, transport string) (string, error) {
+	if transport == "" {
+		transport = dockerCli.ConfigFile().AttachTransport
+	}
+	switch transport {
+	case "":
+		return attachTransportAuto, nil
+	case attachTransportAuto, attachTransportHijack, attachTransportWebSocket:
+		return transport, nil
+	}
+	return "", errors.Errorf("invalid attach transport %q: must be %q, %q, or %q", transport, attachTransportAuto, attachTransportHijack, attachTransportWebSocket)
+}
+
+// containerAttach attaches to the streams of a container with a transport. It
+// returns whether the output of the connection is raw, rather than
+// multiplexed, as with the WebSocket transport.
+func containerAttach(ctx context.Context, dockerCli command.Cli, transport string, container string, options types.ContainerAttachOptions) (resp types.HijackedResponse, raw bool, err error) {
+	transport, err = resolveAttachTransport(dockerCli, transport)
+	if err != nil {
+		return types.HijackedResponse{}, false, err
+	}
+	apiClient := dockerCli.Client()
+	if transport == attachTransportAuto && isProxiedDaemon(apiClient) {
+		logrus.Debug("[websocket] the daemon is reached through an HTTP proxy")
+		transport = attachTransportWebSocket
+	}
+	if transport == attachTransportWebSocket {
+		resp, err := attachWebSocket(ctx, apiClient, container, options)
+		return resp, true, err
+	}
+
+	resp, err = apiClient.ContainerAttach(ctx, container, options)
+	if transport == attachTransportAuto && err != nil && strings.Contains(err.Error(), "unable to upgrade to tcp") {
+		// A proxy, or a load balancer, in front of the daemon does not
+		// support the upgrade of the connection
+		logrus.Debugf("[websocket] falling back to the WebSocket transport: %v", err)
+		resp, err := attachWebSocket(ctx, apiClient, container, options)
+		return resp, true, err
+	}
+	return resp, false, err
+}
+
+// isProxiedDaemon returns whether the daemon is reached through an HTTP proxy,
+// which the hijacked connections, dialed directly, bypass.
+func isProxiedDaemon(apiClient client.APIClient) bool {
+	if !strings.HasPrefix(apiClient.DaemonHost(), "tcp://") {
+		return false
+	}
+	u, err := daemonURL(apiClient, "/_ping", nil)
+	if err != nil {
+		return false
+	}
+	proxy, err := http.ProxyFromEnvironment(&http.Request{URL: u})
+	return err == nil && proxy != nil
+}
+
+// daemonURL returns the URL of an endpoint of the API of the daemon
+func daemonURL(apiClient client.APIClient, path string, query url.Values) (*url.URL, error) {
+	host, err := client.ParseHostURL(apiClient.DaemonHost())
+	if err != nil {
+		return nil, err
+	}
+	u := &url.URL{Scheme: "http", Host: host.Host, RawQuery: query.Encode()}
+	switch host.Scheme {
+	case "unix", "npipe":
+		// The address of the socket is dialed by the transport of the client
+		u.Host = "docker"
+	}
+	// The transports of the client are only wrapped, such as to retry the
+	// requests, if they do not use TLS
+	if transport, ok := apiClient.HTTPClient().Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		u.Scheme = "https"
+	}
+	u.Path = host.Path
+	if v := apiClient.ClientVersion(); v != "" {
+		u.Path += "/v" + strings.TrimPrefix(v, "v")
+	}
+	u.Path += path
+	return u, nil
+}
+
+// attachWebSocket attaches to the streams of a container with the WebSocket
+// endpoint of the daemon. The handshake is sent with the HTTP client of the
+// API client, so that the connection goes through the HTTP proxies of the
+// environment.
+func attachWebSocket(ctx context.Context, apiClient client.APIClient, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error) {
+	query := url.Values{}
+	for name, set := range map[string]bool{"stream": options.Stream, "stdin": options.Stdin, "stdout": options.Stdout, "stderr": options.Stderr, "logs": options.Logs} {
+		if set {
+			query.Set(name, "1")
+		}
+	}
+	if options.DetachKeys != "" {
+		query.Set("detachKeys", options.DetachKeys)
+	}
+	u, err := daemonURL(apiClient, "/containers/"+container+"/attach/ws", query)
+	if err != nil {
+		return types.HijackedResponse{}, err
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return types.HijackedResponse{}, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return types.HijackedResponse{}, err
+	}
+	// The API client may be wrapped, such as by the offline cache of the CLI
+	if c, ok := apiClient.(interface{ CustomHTTPHeaders() map[string]string }); ok {
+		for k, v := range c.CustomHTTPHeaders() {
+			req.Header.Set(k, v)
+		}
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Origin", u.Scheme+"://"+u.Host)
+
+	resp, err := apiClient.HTTPClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return types.HijackedResponse{}, errors.Wrap(err, "cannot connect to the WebSocket endpoint of the Docker daemon")
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		if resp.StatusCode == http.StatusNotFound && len(body) == 0 {
+			return types.HijackedResponse{}, errors.New("the daemon has no WebSocket endpoint to attach to the containers")
+		}
+		return types.HijackedResponse{}, errors.Errorf("unable to upgrade to websocket, received %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return types.HijackedResponse{}, errors.New("unable to upgrade to websocket: the connection is not writable")
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != webSocketAccept(key) {
+		rwc.Close()
+		return types.HijackedResponse{}, errors.New("unable to upgrade to websocket: invalid Sec-WebSocket-Accept header")
+	}
+
+	conn := &wsConn{rwc: rwc, r: bufio.NewReader(rwc)}
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(conn)}, nil
+}
+
+// webSocketAccept returns the Sec-WebSocket-Accept header of the response to
+// the handshake with a Sec-WebSocket-Key header
+func webSocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// wsConn is a connection of the WebSocket protocol, whose reads and writes
+// are the payloads of its data frames. The writes are sent as masked binary
+// frames, as required for the clients.
+type wsConn struct {
+	rwc io.ReadWriteCloser
+	r   *bufio.Reader
+
+	// remaining is the size of the payload of the current data frame which
+	// is not read yet, and mask its masking key, if it is masked
+	remaining uint64
+	mask      []byte
+	offset    uint64
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+func (c *wsConn) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		opcode, payload, err := c.readFrameHeader()
+		if err != nil {
+			return 0, err
+		}
+		switch opcode {
+		case wsContinuationFrame, wsTextFrame, wsBinaryFrame:
+			c.remaining = payload
+		case wsCloseFrame:
+			c.discard(payload)
+			return 0, io.EOF
+		case wsPingFrame:
+			body, err := c.readControlPayload(payload)
+			if err != nil {
+				return 0, err
+			}
+			if err := c.writeFrame(wsPongFrame, body); err != nil {
+				return 0, err
+			}
+		default:
+			if err := c.discard(payload); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if uint64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.unmask(p[:n])
+	c.remaining -= uint64(n)
+	if err == io.EOF && c.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// readFrameHeader reads the header of the next frame, and returns its opcode
+// and the size of its payload
+func (c *wsConn) readFrameHeader() (opcode byte, size uint64, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return 0, 0, err
+	}
+	opcode = header[0] & 0x0f
+	size = uint64(header[1] & 0x7f)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, 0, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return 0, 0, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	c.mask, c.offset = nil, 0
+	if header[1]&0x80 != 0 {
+		c.mask = make([]byte, 4)
+		if _, err := io.ReadFull(c.r, c.mask); err != nil {
+			return 0, 0, err
+		}
+	}
+	return opcode, size, nil
+}
+
+func (c *wsConn) readControlPayload(size uint64) ([]byte, error) {
+	if size > 125 {
+		return nil, errors.New("invalid WebSocket control frame")
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return nil, err
+	}
+	c.unmask(payload)
+	return payload, nil
+}
+
+func (c *wsConn) discard(size uint64) error {
+	_, err := io.CopyN(ioutil.Discard, c.r, int64(size))
+	return err
+}
+
+func (c *wsConn) unmask(p []byte) {
+	if c.mask == nil {
+		return
+	}
+	for i := range p {
+		p[i] ^= c.mask[(c.offset+uint64(i))%4]
+	}
+	c.offset += uint64(len(p))
+}
+
+func (c *wsConn) Write(p []byte) (int, error) {
+	if err := c.writeFrame(wsBinaryFrame, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeFrame writes a masked frame with a payload
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch size := len(payload); {
+	case size < 126:
+		frame = append(frame, 0x80|byte(size))
+	case size <= 0xffff:
+		frame = append(frame, 0x80|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(size))
+	default:
+		frame = append(frame, 0x80|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(size))
+	}
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return err
+	}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.rwc.Write(frame)
+	return err
+}
+
+// Close sends a close frame, and closes the connection
+func (c *wsConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		// Normal closure
+		c.writeFrame(wsCloseFrame, []byte{0x03, 0xe8})
+		err = c.rwc.Close()
+	})
+	return err
+}
+
+// wsAddr is the address of both ends of a wsConn, which are not known
+type wsAddr struct{}
+
+func (wsAddr) Network() string { return "websocket" }
+
+func (wsAddr) String() string { return "websocket" }
+
+func (c *wsConn) LocalAddr() net.Addr { return wsAddr{} }
+
+func (c *wsConn) RemoteAddr() net.Addr { return wsAddr{} }
+
+func (c *wsConn) SetDeadline(time.Time) error { return nil }
+
+func (c *wsConn) SetReadDeadline(time.Time) error { return nil }
+
+func (c *wsConn) SetWriteDeadline(time.Time) error { return nil }
@@ -0,0 +1,132 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// newWebSocketDaemon returns a daemon which refuses to hijack the connections
+// of /attach, as a proxy would, and whose /attach/ws endpoint greets the
+// client, echoes the first frame it receives, and closes the connection
+func newWebSocketDaemon(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.40/containers/web/attach":
+			w.WriteHeader(http.StatusBadRequest)
+		case "/v1.40/containers/web/attach/ws":
+			assert.Check(t, is.Equal(r.URL.Query().Get("stdin"), "1"))
+			assert.Check(t, is.Equal(r.Header.Get("Upgrade"), "websocket"))
+			assert.Check(t, is.Equal(r.Header.Get("X-Proxy-Auth"), "token"))
+			conn, rw, err := w.(http.Hijacker).Hijack()
+			assert.NilError(t, err)
+			defer conn.Close()
+			rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+			rw.WriteString("Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+			rw.WriteString("\x89\x00")
+			rw.WriteString("\x81\x05hello")
+			assert.NilError(t, rw.Flush())
+
+			// The client answers the ping, and then sends its frame
+			server := &wsConn{rwc: conn, r: rw.Reader}
+			opcode, size, err := server.readFrameHeader()
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(opcode, byte(wsPongFrame)))
+			assert.Check(t, is.Equal(size, uint64(0)))
+			buf := make([]byte, 4)
+			n, err := server.Read(buf)
+			assert.NilError(t, err)
+			rw.WriteString("\x82\x04" + string(buf[:n]))
+			rw.WriteString("\x88\x02\x03\xe8")
+			assert.NilError(t, rw.Flush())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// wrappedAPIClient wraps the API client, as the offline cache of the CLI does
+type wrappedAPIClient struct {
+	*client.Client
+}
+
+func TestContainerAttachWebSocketFallback(t *testing.T) {
+	server := newWebSocketDaemon(t)
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(
+		client.WithHost("tcp://"+server.Listener.Addr().String()),
+		client.WithVersion("1.40"),
+		client.WithHTTPHeaders(map[string]string{"X-Proxy-Auth": "token"}),
+	)
+	assert.NilError(t, err)
+	cli := test.NewFakeCli(&wrappedAPIClient{Client: apiClient})
+
+	resp, raw, err := containerAttach(context.Background(), cli, "", "web", types.ContainerAttachOptions{Stream: true, Stdin: true, Stdout: true})
+	assert.NilError(t, err)
+	defer resp.Close()
+	assert.Check(t, raw)
+
+	buf := make([]byte, 5)
+	_, err = resp.Reader.Read(buf)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(buf), "hello"))
+
+	_, err = resp.Conn.Write([]byte("ping"))
+	assert.NilError(t, err)
+	rest, err := ioutil.ReadAll(resp.Reader)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(rest), "ping"))
+}
+
+func TestContainerAttachHijackTransport(t *testing.T) {
+	server := newWebSocketDaemon(t)
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.40"))
+	assert.NilError(t, err)
+	cli := test.NewFakeCli(apiClient)
+
+	_, _, err = containerAttach(context.Background(), cli, attachTransportHijack, "web", types.ContainerAttachOptions{Stream: true, Stdin: true})
+	assert.ErrorContains(t, err, "unable to upgrade to tcp, received 400")
+}
+
+func TestWsConnRead(t *testing.T) {
+	// A masked text frame "hel", continued by an unmasked frame "lo"
+	frames := "\x01\x83\x01\x02\x03\x04" + string([]byte{'h' ^ 1, 'e' ^ 2, 'l' ^ 3}) + "\x80\x02lo"
+	conn := &wsConn{r: bufio.NewReader(strings.NewReader(frames))}
+	out, err := ioutil.ReadAll(conn)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), "hello"))
+}
+
+func TestResolveAttachTransport(t *testing.T) {
+	testCases := []struct {
+		flag, config, expected, expectedError string
+	}{
+		{expected: attachTransportAuto},
+		{config: attachTransportWebSocket, expected: attachTransportWebSocket},
+		{flag: attachTransportHijack, config: attachTransportWebSocket, expected: attachTransportHijack},
+		{flag: "ssh", expectedError: `invalid attach transport "ssh"`},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetConfigFile(&configfile.ConfigFile{AttachTransport: tc.config})
+		transport, err := resolveAttachTransport(cli, tc.flag)
+		if tc.expectedError != "" {
+			assert.Check(t, is.ErrorContains(err, tc.expectedError))
+			continue
+		}
+		assert.Check(t, err)
+		assert.Check(t, is.Equal(transport, tc.expected))
+	}
+}
@@ -22,6 +22,7 @@ func NewContextCommand(dockerCli command.Cli) *cobra.Command {
 		newCreateCommand(dockerCli),
 		newListCommand(dockerCli),
 		newUseCommand(dockerCli),
+		newEnvCommand(dockerCli),
 		newExportCommand(dockerCli),
 		newImportCommand(dockerCli),
 		newRemoveCommand(dockerCli),
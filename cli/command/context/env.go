@@ -0,0 +1,140 @@
+package context
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// EnvOptions are the options of the commands which print the environment
+// of a context
+type EnvOptions struct {
+	// Shell is the shell to print the commands for. It defaults to the shell
+	// of the SHELL environment variable, or to PowerShell on Windows.
+	Shell string
+	// Unset prints the commands to stop using a context instead
+	Unset bool
+}
+
+// shells are the commands to set and unset environment variables, by shell
+var shells = map[string]struct {
+	set   func(w io.Writer, name, value string)
+	unset func(w io.Writer, name string)
+}{
+	"bash":       {set: setPosix, unset: unsetPosix},
+	"zsh":        {set: setPosix, unset: unsetPosix},
+	"sh":         {set: setPosix, unset: unsetPosix},
+	"fish":       {set: setFish, unset: unsetFish},
+	"powershell": {set: setPowerShell, unset: unsetPowerShell},
+	"cmd":        {set: setCmd, unset: unsetCmd},
+}
+
+func setPosix(w io.Writer, name, value string) {
+	fmt.Fprintf(w, "export %s=%q\n", name, value)
+}
+
+func unsetPosix(w io.Writer, name string) {
+	fmt.Fprintf(w, "unset %s\n", name)
+}
+
+func setFish(w io.Writer, name, value string) {
+	fmt.Fprintf(w, "set -gx %s %q;\n", name, value)
+}
+
+func unsetFish(w io.Writer, name string) {
+	fmt.Fprintf(w, "set -e %s;\n", name)
+}
+
+func setPowerShell(w io.Writer, name, value string) {
+	fmt.Fprintf(w, "$Env:%s = %q\n", name, value)
+}
+
+func unsetPowerShell(w io.Writer, name string) {
+	fmt.Fprintf(w, "Remove-Item Env:\\%s -ErrorAction SilentlyContinue\n", name)
+}
+
+func setCmd(w io.Writer, name, value string) {
+	fmt.Fprintf(w, "SET %s=%s\n", name, value)
+}
+
+func unsetCmd(w io.Writer, name string) {
+	fmt.Fprintf(w, "SET %s=\n", name)
+}
+
+func newEnvCommand(dockerCli command.Cli) *cobra.Command {
+	var opts EnvOptions
+	cmd := &cobra.Command{
+		Use:   "env [OPTIONS] [CONTEXT]",
+		Short: "Print the commands to use a context in the current shell",
+		Long: `Print the commands to use a context in the current shell, without changing the current context of other shells, such as:
+
+    eval "$(docker context env CONTEXT)"`,
+		Args: cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+			return RunEnv(dockerCli, name, opts)
+		},
+	}
+	addEnvFlags(cmd.Flags(), &opts)
+	cmd.Flags().BoolVarP(&opts.Unset, "unset", "u", false, "Print the commands to stop using a context in the current shell")
+	return cmd
+}
+
+func addEnvFlags(flags *pflag.FlagSet, opts *EnvOptions) {
+	flags.StringVar(&opts.Shell, "shell", "", "Shell to print the commands for (bash, cmd, fish, powershell, sh, zsh)")
+}
+
+// RunEnv prints the commands to use a Docker context in the current shell, or
+// to stop using it.
+func RunEnv(dockerCli command.Cli, name string, opts EnvOptions) error {
+	shellName := opts.Shell
+	if shellName == "" {
+		shellName = defaultShell()
+	}
+	shell, ok := shells[shellName]
+	if !ok {
+		return errors.Errorf("unsupported shell %q: use --shell", shellName)
+	}
+
+	switch {
+	case opts.Unset && name != "":
+		return errors.New("--unset does not take a context name")
+	case opts.Unset:
+		shell.unset(dockerCli.Out(), "DOCKER_CONTEXT")
+		return nil
+	case name == "":
+		return errors.New("requires a context name, or --unset")
+	}
+	if err := validateContextName(name); err != nil && name != command.DefaultContextName {
+		return err
+	}
+	if _, err := dockerCli.ContextStore().GetContextMetadata(name); err != nil && name != command.DefaultContextName {
+		return err
+	}
+	// DOCKER_HOST takes precedence over DOCKER_CONTEXT
+	shell.unset(dockerCli.Out(), "DOCKER_HOST")
+	shell.set(dockerCli.Out(), "DOCKER_CONTEXT", name)
+	return nil
+}
+
+func defaultShell() string {
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	shell := filepath.Base(os.Getenv("SHELL"))
+	if _, ok := shells[shell]; !ok {
+		return "sh"
+	}
+	return shell
+}
@@ -0,0 +1,74 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/context/store"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestEnv(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+	assert.NilError(t, cli.ContextStore().CreateOrUpdateContext(store.ContextMetadata{Name: "test"}))
+
+	testCases := []struct {
+		shell    string
+		unset    bool
+		expected string
+	}{
+		{shell: "bash", expected: "unset DOCKER_HOST\nexport DOCKER_CONTEXT=\"test\"\n"},
+		{shell: "bash", unset: true, expected: "unset DOCKER_CONTEXT\n"},
+		{shell: "fish", expected: "set -e DOCKER_HOST;\nset -gx DOCKER_CONTEXT \"test\";\n"},
+		{shell: "powershell", expected: "Remove-Item Env:\\DOCKER_HOST -ErrorAction SilentlyContinue\n$Env:DOCKER_CONTEXT = \"test\"\n"},
+		{shell: "cmd", unset: true, expected: "SET DOCKER_CONTEXT=\n"},
+	}
+	for _, tc := range testCases {
+		cli.OutBuffer().Reset()
+		args := []string{"--shell", tc.shell}
+		if tc.unset {
+			args = append(args, "--unset")
+		} else {
+			args = append(args, "test")
+		}
+		cmd := newEnvCommand(cli)
+		cmd.SetArgs(args)
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.Equal(tc.expected, cli.OutBuffer().String()), tc.shell)
+	}
+}
+
+func TestEnvErrors(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"--shell", "bash"}, expectedError: "requires a context name, or --unset"},
+		{args: []string{"--shell", "bash", "--unset", "test"}, expectedError: "--unset does not take a context name"},
+		{args: []string{"--shell", "tcsh", "test"}, expectedError: `unsupported shell "tcsh": use --shell`},
+		{args: []string{"--shell", "bash", "test"}, expectedError: `context "test" does not exist`},
+	}
+	for _, tc := range testCases {
+		cmd := newEnvCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestUsePrintEnv(t *testing.T) {
+	cli, cleanup := makeFakeCli(t)
+	defer cleanup()
+
+	cmd := newUseCommand(cli)
+	cmd.SetArgs([]string{"--print-env", "--shell", "zsh", "default"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("unset DOCKER_HOST\nexport DOCKER_CONTEXT=\"default\"\n", cli.OutBuffer().String()))
+	// The current context is not changed
+	assert.Check(t, is.Equal("", cli.ErrBuffer().String()))
+}
@@ -8,15 +8,25 @@ import (
 )
 
 func newUseCommand(dockerCli command.Cli) *cobra.Command {
+	var (
+		printEnv bool
+		envOpts  EnvOptions
+	)
 	cmd := &cobra.Command{
-		Use:   "use CONTEXT",
+		Use:   "use [OPTIONS] CONTEXT",
 		Short: "Set the current docker context",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
+			if printEnv {
+				return RunEnv(dockerCli, name, envOpts)
+			}
 			return RunUse(dockerCli, name)
 		},
 	}
+	flags := cmd.Flags()
+	flags.BoolVar(&printEnv, "print-env", false, "Print the commands to use the context in the current shell only, instead of setting the current context")
+	addEnvFlags(flags, &envOpts)
 	return cmd
 }
 
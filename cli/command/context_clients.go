@@ -0,0 +1,136 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// DefaultContextName is the name of the context connecting to the daemon set
+// with the DOCKER_HOST environment variable, or to the default daemon
+const DefaultContextName = "default"
+
+// ContextAPIClient is an API client connected to the daemon of a context
+type ContextAPIClient struct {
+	Name    string
+	Current bool
+	Client  client.APIClient
+	// Err is the error creating the client, if any
+	Err error
+}
+
+// NewContextAPIClients returns an API client for the default context and for
+// each context of the store with a Docker endpoint, sorted by name. The client
+// of the current context is the client of the CLI. A failure to create the
+// client of a context is returned in the Err field of the context, so that the
+// other contexts can still be used.
+func NewContextAPIClients(dockerCli Cli) ([]ContextAPIClient, error) {
+	names, err := dockerContextNames(dockerCli.ContextStore())
+	if err != nil {
+		return nil, err
+	}
+	return newContextAPIClients(dockerCli, names), nil
+}
+
+// CloseContextAPIClients closes the clients created by NewContextAPIClients
+// for the contexts other than the current context, whose client is the client
+// of the CLI.
+func CloseContextAPIClients(clients []ContextAPIClient) {
+	for _, c := range clients {
+		if !c.Current && c.Client != nil {
+			c.Client.Close()
+		}
+	}
+}
+
+// RunInContexts runs fn concurrently with the client of each context, once the
+// API version is negotiated with its daemon. The contexts whose client cannot
+// be created, or for which fn fails, are reported in the returned error, once
+// fn is done for every other context.
+func RunInContexts(ctx context.Context, clients []ContextAPIClient, fn func(ctx context.Context, i int, c ContextAPIClient) error) error {
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		if c.Err != nil {
+			errs[i] = c.Err
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c ContextAPIClient) {
+			defer wg.Done()
+			if !c.Current {
+				c.Client.NegotiateAPIVersion(ctx)
+			}
+			errs[i] = fn(ctx, i, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	var msgs []string
+	for i, err := range errs {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("context %s: %s", clients[i].Name, err))
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "\n"))
+	}
+	return nil
+}
+
+// dockerContextNames returns the name of the default context and of each
+// context of the store with a Docker endpoint, sorted
+func dockerContextNames(s store.Store) ([]string, error) {
+	names := []string{DefaultContextName}
+	if s != nil {
+		contexts, err := s.ListContexts()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range contexts {
+			if _, ok := c.Endpoints[docker.DockerEndpoint]; ok && c.Name != DefaultContextName {
+				names = append(names, c.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func newContextAPIClients(dockerCli Cli, names []string) []ContextAPIClient {
+	current := dockerCli.CurrentContext()
+	if current == "" {
+		current = DefaultContextName
+	}
+	var clients []ContextAPIClient
+	for _, name := range names {
+		c := ContextAPIClient{Name: name, Current: name == current}
+		if c.Current {
+			c.Client = dockerCli.Client()
+		} else {
+			c.Client, c.Err = newContextAPIClient(dockerCli, name)
+		}
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+func newContextAPIClient(dockerCli Cli, name string) (client.APIClient, error) {
+	contextName := name
+	if name == DefaultContextName {
+		contextName = ""
+	}
+	endpoint, err := resolveDockerEndpoint(dockerCli.ContextStore(), contextName, &cliflags.CommonOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to resolve docker endpoint")
+	}
+	return newAPIClientFromEndpoint(endpoint, dockerCli.ConfigFile())
+}
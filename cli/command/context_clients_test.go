@@ -0,0 +1,30 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type closeCountingClient struct {
+	fakeClient
+	closed int
+}
+
+func (c *closeCountingClient) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestCloseContextAPIClients(t *testing.T) {
+	current, other := &closeCountingClient{}, &closeCountingClient{}
+	CloseContextAPIClients([]ContextAPIClient{
+		{Name: "default", Current: true, Client: current},
+		{Name: "remote", Client: other},
+		{Name: "invalid", Err: errors.New("context not found")},
+	})
+	assert.Check(t, is.Equal(current.closed, 0))
+	assert.Check(t, is.Equal(other.closed, 1))
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/context/store"
+	"github.com/pkg/errors"
+)
+
+// AllContextsSelector is the value of the --context flag selecting the default
+// context and every context with a Docker endpoint
+const AllContextsSelector = "all"
+
+// isContextSelector returns whether the value of the --context flag selects
+// several contexts: "all" (unless a context has this name), or a
+// comma-separated list of names and glob patterns. These characters cannot be
+// part of the name of a context.
+func isContextSelector(s store.Store, value string) bool {
+	if strings.ContainsAny(value, ",*?[") {
+		return true
+	}
+	if value != AllContextsSelector {
+		return false
+	}
+	_, err := s.GetContextMetadata(value)
+	return store.IsErrContextDoesNotExist(err)
+}
+
+// selectContexts returns the sorted names of the contexts with a Docker
+// endpoint matched by the selector of the --context flag. A name which is not
+// a glob pattern must be the name of an existing context.
+func selectContexts(s store.Store, selector string) ([]string, error) {
+	names, err := dockerContextNames(s)
+	if err != nil {
+		return nil, err
+	}
+	if selector == AllContextsSelector {
+		return names, nil
+	}
+
+	selected := map[string]bool{}
+	for _, pattern := range strings.Split(selector, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if !strings.ContainsAny(pattern, "*?[") {
+			if pattern != DefaultContextName {
+				if _, err := s.GetContextMetadata(pattern); err != nil {
+					return nil, err
+				}
+			}
+			selected[pattern] = true
+			continue
+		}
+		for _, name := range names {
+			matched, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid context pattern %q", pattern)
+			}
+			if matched {
+				selected[name] = true
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.Errorf("no context matches %q", selector)
+	}
+
+	var result []string
+	for name := range selected {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func newSelectorTestStore(t *testing.T, dir string, names ...string) store.Store {
+	s := store.New(dir, defaultContextStoreConfig())
+	for _, name := range names {
+		assert.NilError(t, s.CreateOrUpdateContext(store.ContextMetadata{
+			Name:      name,
+			Metadata:  DockerContext{},
+			Endpoints: map[string]interface{}{docker.DockerEndpoint: docker.EndpointMeta{Host: "tcp://" + name + ":2376"}},
+		}))
+	}
+	return s
+}
+
+func TestSelectContexts(t *testing.T) {
+	dir := fs.NewDir(t, "context-selector")
+	defer dir.Remove()
+	s := newSelectorTestStore(t, dir.Path(), "prod-1", "prod-2", "staging")
+
+	testCases := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "all", expected: []string{"default", "prod-1", "prod-2", "staging"}},
+		{selector: "prod-*", expected: []string{"prod-1", "prod-2"}},
+		{selector: "staging,default", expected: []string{"default", "staging"}},
+		{selector: "prod-?, prod-1 ,staging", expected: []string{"prod-1", "prod-2", "staging"}},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, isContextSelector(s, tc.selector), tc.selector)
+		names, err := selectContexts(s, tc.selector)
+		assert.NilError(t, err, tc.selector)
+		assert.Check(t, is.DeepEqual(tc.expected, names), tc.selector)
+	}
+
+	assert.Check(t, !isContextSelector(s, "staging"))
+
+	_, err := selectContexts(s, "test-*")
+	assert.Check(t, is.Error(err, `no context matches "test-*"`))
+	_, err = selectContexts(s, "staging,missing")
+	assert.Check(t, store.IsErrContextDoesNotExist(err))
+}
+
+func TestSelectContextsNamedAll(t *testing.T) {
+	dir := fs.NewDir(t, "context-selector")
+	defer dir.Remove()
+	s := newSelectorTestStore(t, dir.Path(), "all", "other")
+
+	assert.Check(t, !isContextSelector(s, "all"))
+	assert.Check(t, isContextSelector(s, "*"))
+}
@@ -0,0 +1,148 @@
+package debug
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/client"
+)
+
+// dialTimeout is the maximum time spent checking that a TCP address is
+// reachable
+const dialTimeout = 3 * time.Second
+
+// inputs are the settings of the connection to the daemon being diagnosed
+type inputs struct {
+	// host is the address of the daemon, empty if the CLI failed to
+	// resolve it
+	host string
+	tls  bool
+	// context is the context in use, and configContext and envContext the
+	// contexts set in the configuration file and the DOCKER_CONTEXT
+	// environment variable
+	context       string
+	configContext string
+	envContext    string
+	// dockerHost is the value of the DOCKER_HOST environment variable, nil
+	// if it is not set
+	dockerHost *string
+	store      store.Store
+}
+
+func diagnose(in inputs) []Finding {
+	var findings []Finding
+	findings = append(findings, checkDockerHost(in)...)
+	findings = append(findings, checkContext(in)...)
+
+	host := in.host
+	if host == "" && in.dockerHost != nil {
+		host = *in.dockerHost
+	}
+	if host == "" {
+		host = client.DefaultDockerHost
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return findings
+	}
+	switch u.Scheme {
+	case "unix":
+		findings = append(findings, checkSocket(u.Path)...)
+	case "tcp":
+		findings = append(findings, checkTCP(u.Host, in.tls)...)
+	case "ssh":
+		findings = append(findings, checkSSH()...)
+	}
+	return findings
+}
+
+func checkDockerHost(in inputs) []Finding {
+	if in.dockerHost == nil {
+		return nil
+	}
+	dockerHost := *in.dockerHost
+	if strings.TrimSpace(dockerHost) == "" {
+		return []Finding{{
+			Problem: "The DOCKER_HOST environment variable is set, but empty.",
+			Remedy:  "Unset DOCKER_HOST to use the current context, or set it to the address of the daemon.",
+		}}
+	}
+	var findings []Finding
+	if !strings.HasPrefix(dockerHost, "ssh://") {
+		if _, err := opts.ParseHost(false, dockerHost); err != nil {
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("The DOCKER_HOST environment variable (%q) is not a valid daemon address: %v.", dockerHost, err),
+				Remedy:  "Set DOCKER_HOST to an address such as unix:///var/run/docker.sock, tcp://host:2376 or ssh://user@host.",
+			})
+		}
+	}
+	// DOCKER_HOST takes precedence over the contexts, unless the context
+	// is set with the --context flag
+	if in.context == "" {
+		for _, name := range []string{in.envContext, in.configContext} {
+			if name != "" && name != "default" {
+				findings = append(findings, Finding{
+					Problem: fmt.Sprintf("The DOCKER_HOST environment variable overrides the current context (%q).", name),
+					Remedy:  "Unset DOCKER_HOST to connect to the daemon of the context.",
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func checkContext(in inputs) []Finding {
+	name := in.context
+	if name == "" && in.dockerHost == nil {
+		name = in.envContext
+		if name == "" {
+			name = in.configContext
+		}
+	}
+	if name == "" || name == "default" || in.store == nil {
+		return nil
+	}
+	if _, err := in.store.GetContextMetadata(name); store.IsErrContextDoesNotExist(err) {
+		return []Finding{{
+			Problem: fmt.Sprintf("The context %q does not exist.", name),
+			Remedy:  `List the contexts with "docker context ls", and select one with "docker context use".`,
+		}}
+	}
+	return nil
+}
+
+func checkTCP(addr string, tls bool) []Finding {
+	var findings []Finding
+	if _, port, err := net.SplitHostPort(addr); err == nil && port == "2376" && !tls {
+		findings = append(findings, Finding{
+			Problem: fmt.Sprintf("Port 2376 of %s is the port of daemons using TLS, but TLS is not enabled.", addr),
+			Remedy:  "Enable TLS with the --tlsverify flag, or by setting DOCKER_TLS_VERIFY=1.",
+		})
+	}
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return append(findings, Finding{
+			Problem: fmt.Sprintf("The daemon cannot be reached at %s: %v.", addr, err),
+			Remedy:  "Check that the daemon is configured to listen on this address, and that no firewall blocks the connection.",
+		})
+	}
+	conn.Close()
+	return findings
+}
+
+func checkSSH() []Finding {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return []Finding{{
+			Problem: "The ssh command, needed to connect to the daemon over SSH, is not installed.",
+			Remedy:  "Install an SSH client, and make sure the ssh command is in the PATH.",
+		}}
+	}
+	return nil
+}
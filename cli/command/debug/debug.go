@@ -0,0 +1,91 @@
+// Package debug diagnoses the failures to connect to the Docker daemon, and
+// suggests how to fix them.
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// Finding is a problem found by the diagnostics, and how to fix it
+type Finding struct {
+	Problem string `json:"problem"`
+	Remedy  string `json:"remedy,omitempty"`
+}
+
+type initializationError struct {
+	error
+}
+
+func (e initializationError) Cause() error {
+	return e.error
+}
+
+// InitializationError marks err as a failure to initialize the CLI, which
+// may be caused by the configuration of the connection to the daemon.
+func InitializationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return initializationError{err}
+}
+
+// IsConnectionFailure returns whether err is a failure to connect to the
+// daemon, or to resolve its endpoint and initialize the API client of the CLI.
+// The other failures to initialize the CLI, such as an invalid --color flag,
+// are not.
+func IsConnectionFailure(err error) bool {
+	if initErr, ok := err.(initializationError); ok {
+		return command.IsEndpointError(initErr.error)
+	}
+	if client.IsErrConnectionFailed(err) {
+		return true
+	}
+	// Some commands only return the message of the error
+	msg := err.Error()
+	if sterr, ok := errors.Cause(err).(cli.StatusError); ok {
+		msg = sterr.Status
+	}
+	return strings.Contains(msg, "Cannot connect to the Docker daemon")
+}
+
+// DiagnoseConnection checks the configuration of the connection to the
+// daemon, and returns the problems found.
+func DiagnoseConnection(dockerCli command.Cli) []Finding {
+	in := inputs{
+		host:    dockerCli.DockerEndpoint().Host,
+		context: dockerCli.CurrentContext(),
+		tls:     dockerCli.DockerEndpoint().TLSData != nil,
+		store:   dockerCli.ContextStore(),
+	}
+	if dockerHost, ok := os.LookupEnv("DOCKER_HOST"); ok {
+		in.dockerHost = &dockerHost
+	}
+	in.envContext = os.Getenv("DOCKER_CONTEXT")
+	if configFile := dockerCli.ConfigFile(); configFile != nil {
+		in.configContext = configFile.CurrentContext
+	}
+	return diagnose(in)
+}
+
+// PrintFindings prints the findings of the diagnostics, if any
+func PrintFindings(w io.Writer, findings []Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Diagnostics:")
+	for _, f := range findings {
+		fmt.Fprintf(w, " - %s\n", f.Problem)
+		if f.Remedy != "" {
+			fmt.Fprintf(w, "   %s\n", f.Remedy)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package debug
+
+import (
+	"bytes"
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+	"gotest.tools/skip"
+)
+
+func TestIsConnectionFailure(t *testing.T) {
+	connErr := client.ErrorConnectionFailed("unix:///var/run/docker.sock")
+	assert.Check(t, IsConnectionFailure(connErr))
+	assert.Check(t, IsConnectionFailure(errors.Wrap(connErr, "failed to list containers")))
+	assert.Check(t, IsConnectionFailure(cli.StatusError{Status: connErr.Error(), StatusCode: 125}))
+	assert.Check(t, !IsConnectionFailure(errors.New("No such container: foo")))
+}
+
+func TestIsConnectionFailureInitialization(t *testing.T) {
+	dir := fs.NewDir(t, "config")
+	defer dir.Remove()
+
+	// Failures to create the API client are connection failures
+	dockerCli, err := command.NewDockerCli()
+	assert.NilError(t, err)
+	opts := flags.NewClientOptions()
+	opts.ConfigDir = dir.Path()
+	err = dockerCli.Initialize(opts, command.WithInitializeClient(func(*command.DockerCli) (client.APIClient, error) {
+		return nil, errors.New("unable to parse docker host")
+	}))
+	assert.Check(t, IsConnectionFailure(InitializationError(err)))
+
+	// Other failures to initialize the CLI are not
+	dockerCli, err = command.NewDockerCli()
+	assert.NilError(t, err)
+	opts.Common.Color = "rainbow"
+	err = dockerCli.Initialize(opts)
+	assert.Check(t, is.ErrorContains(err, "rainbow"))
+	assert.Check(t, !IsConnectionFailure(InitializationError(err)))
+}
+
+func TestCheckDockerHost(t *testing.T) {
+	empty, invalid, valid := "", "foo://bar", "tcp://localhost:2375"
+	testCases := []struct {
+		doc      string
+		in       inputs
+		expected []string
+	}{
+		{doc: "not set", in: inputs{}},
+		{doc: "valid", in: inputs{dockerHost: &valid}},
+		{
+			doc:      "empty",
+			in:       inputs{dockerHost: &empty},
+			expected: []string{"The DOCKER_HOST environment variable is set, but empty."},
+		},
+		{
+			doc:      "invalid",
+			in:       inputs{dockerHost: &invalid},
+			expected: []string{`The DOCKER_HOST environment variable ("foo://bar") is not a valid daemon address: Invalid bind address format: foo://bar.`},
+		},
+		{
+			doc:      "overrides context",
+			in:       inputs{dockerHost: &valid, configContext: "remote"},
+			expected: []string{`The DOCKER_HOST environment variable overrides the current context ("remote").`},
+		},
+		{doc: "context flag", in: inputs{dockerHost: &valid, context: "remote", configContext: "remote"}},
+	}
+	for _, tc := range testCases {
+		var problems []string
+		for _, f := range checkDockerHost(tc.in) {
+			problems = append(problems, f.Problem)
+		}
+		assert.Check(t, is.DeepEqual(tc.expected, problems), tc.doc)
+	}
+}
+
+func TestCheckSocketPath(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "no unix sockets on Windows")
+	dir := fs.NewDir(t, "debug-socket", fs.WithFile("file", ""))
+	defer dir.Remove()
+	rootless := filepath.Join(dir.Path(), "docker.sock")
+	l, err := net.Listen("unix", rootless)
+	assert.NilError(t, err)
+	defer l.Close()
+
+	missing := filepath.Join(dir.Path(), "missing.sock")
+	findings := checkSocketPath(missing, []string{missing, rootless})
+	assert.Assert(t, is.Len(findings, 2))
+	assert.Check(t, is.Equal("The socket of the daemon ("+missing+") does not exist.", findings[0].Problem))
+	assert.Check(t, is.Equal("A daemon socket exists at "+rootless+".", findings[1].Problem))
+
+	findings = checkSocketPath(dir.Join("file"), nil)
+	assert.Assert(t, is.Len(findings, 1))
+	assert.Check(t, is.Equal(dir.Join("file")+" is not a socket.", findings[0].Problem))
+
+	assert.Check(t, is.Len(checkSocketPath(rootless, nil), 0))
+}
+
+func TestCheckTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	addr := l.Addr().String()
+	assert.Check(t, is.Len(checkTCP(addr, false), 0))
+
+	l.Close()
+	findings := checkTCP(addr, false)
+	assert.Assert(t, is.Len(findings, 1))
+	assert.Check(t, is.Contains(findings[0].Problem, "The daemon cannot be reached at "+addr))
+}
+
+func TestPrintFindings(t *testing.T) {
+	out := new(bytes.Buffer)
+	PrintFindings(out, nil)
+	assert.Check(t, is.Equal("", out.String()))
+
+	PrintFindings(out, []Finding{
+		{Problem: "The socket of the daemon (/var/run/docker.sock) does not exist.", Remedy: "Start the Docker daemon."},
+		{Problem: "Something else."},
+	})
+	expected := `
+Diagnostics:
+ - The socket of the daemon (/var/run/docker.sock) does not exist.
+   Start the Docker daemon.
+ - Something else.
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+}
@@ -0,0 +1,74 @@
+// +build !windows
+
+package debug
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+func checkSocket(path string) []Finding {
+	return checkSocketPath(path, daemonSockets())
+}
+
+// daemonSockets returns the usual paths of the sockets of the daemon, either
+// running as root or rootless.
+func daemonSockets() []string {
+	sockets := []string{"/var/run/docker.sock"}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		sockets = append(sockets, filepath.Join(dir, "docker.sock"))
+	}
+	return append(sockets, filepath.Join("/run/user", strconv.Itoa(os.Getuid()), "docker.sock"))
+}
+
+func checkSocketPath(path string, sockets []string) []Finding {
+	fi, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		findings := []Finding{{
+			Problem: fmt.Sprintf("The socket of the daemon (%s) does not exist.", path),
+			Remedy:  `Start the Docker daemon, for example with "sudo systemctl start docker".`,
+		}}
+		seen := map[string]bool{path: true}
+		for _, s := range sockets {
+			if seen[s] || !isSocket(s) {
+				continue
+			}
+			seen[s] = true
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("A daemon socket exists at %s.", s),
+				Remedy:  fmt.Sprintf(`To use it, set DOCKER_HOST=unix://%s, or create a context with "docker context create --docker host=unix://%s NAME".`, s, s),
+			})
+		}
+		return findings
+	case os.IsPermission(err):
+		return []Finding{permissionFinding(path)}
+	case err != nil:
+		return []Finding{{Problem: fmt.Sprintf("The socket of the daemon (%s) cannot be accessed: %v.", path, err)}}
+	case fi.Mode()&os.ModeSocket == 0:
+		return []Finding{{
+			Problem: fmt.Sprintf("%s is not a socket.", path),
+			Remedy:  "Check the address of the daemon, and restart the daemon to re-create its socket.",
+		}}
+	}
+	if err := unix.Access(path, unix.R_OK|unix.W_OK); err == unix.EACCES {
+		return []Finding{permissionFinding(path)}
+	}
+	return nil
+}
+
+func permissionFinding(path string) Finding {
+	return Finding{
+		Problem: fmt.Sprintf("Permission denied to connect to the socket of the daemon (%s).", path),
+		Remedy:  `Add your user to the docker group with "sudo usermod -aG docker $USER" and log in again, or use a rootless daemon.`,
+	}
+}
+
+func isSocket(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode()&os.ModeSocket != 0
+}
@@ -6,10 +6,12 @@ import (
 	"testing"
 
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	registryclient "github.com/docker/cli/cli/registry/client"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
+	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 	"github.com/opencontainers/go-digest"
 	"gotest.tools/assert"
@@ -56,6 +58,21 @@ func (c testRegistryClient) PutManifest(ctx context.Context, ref reference.Named
 func (c testRegistryClient) GetTags(ctx context.Context, ref reference.Named) ([]string, error) {
 	return c.tags, nil
 }
+func (c testRegistryClient) GetManifestContent(ctx context.Context, ref reference.Named) (registryclient.ManifestContent, error) {
+	return registryclient.ManifestContent{}, nil
+}
+func (c testRegistryClient) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	return nil, nil
+}
+func (c testRegistryClient) PutManifestContent(ctx context.Context, ref reference.Named, content registryclient.ManifestContent) (bool, error) {
+	return false, nil
+}
+func (c testRegistryClient) DeleteManifest(ctx context.Context, ref reference.Named) error {
+	return nil
+}
+func (c testRegistryClient) SearchRepositories(ctx context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (registryclient.SearchPage, error) {
+	return registryclient.SearchPage{}, nil
+}
 
 func TestCheckForUpdatesNoCurrentVersion(t *testing.T) {
 	isRoot = func() bool { return true }
@@ -0,0 +1,100 @@
+package command
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// Exit codes of the CLI for the kinds of errors. The commands running a
+// process, such as "docker run" and "docker exec", exit with the exit code of
+// the process instead.
+const (
+	// ExitCodeError is the exit code of the errors of no specific kind.
+	ExitCodeError = 1
+	// ExitCodeNotFound is the exit code of the errors of objects which do
+	// not exist, such as a container.
+	ExitCodeNotFound = 66
+	// ExitCodeConnectionFailure is the exit code of the failures to connect
+	// to the daemon.
+	ExitCodeConnectionFailure = 69
+	// ExitCodeConflict is the exit code of the errors of objects which
+	// conflict with existing objects, or with their state, such as removing
+	// a running container.
+	ExitCodeConflict = 73
+	// ExitCodeUsage is the exit code of the invalid usages of the commands,
+	// such as an unknown flag or an invalid parameter.
+	ExitCodeUsage = 125
+	// ExitCodeCancelled is the exit code of the cancelled commands, such as
+	// by pressing CTRL-C.
+	ExitCodeCancelled = 130
+)
+
+// Machine-readable codes of the kinds of errors, such as in the Code of a
+// cli.StatusError. The errors of no specific kind have no code.
+const (
+	ErrorCodeNotFound          = "not-found"
+	ErrorCodeConnectionFailure = "connection-failure"
+	ErrorCodeConflict          = "conflict"
+	ErrorCodeUsage             = "usage"
+	ErrorCodeCancelled         = "cancelled"
+)
+
+var exitCodes = map[string]int{
+	ErrorCodeNotFound:          ExitCodeNotFound,
+	ErrorCodeConnectionFailure: ExitCodeConnectionFailure,
+	ErrorCodeConflict:          ExitCodeConflict,
+	ErrorCodeUsage:             ExitCodeUsage,
+	ErrorCodeCancelled:         ExitCodeCancelled,
+}
+
+// ClassifyError returns the machine-readable code of the kind of an error,
+// such as ErrorCodeNotFound for the errors of the API of objects which do not
+// exist, and its exit code. The errors of no specific kind have an empty code,
+// and ExitCodeError.
+func ClassifyError(err error) (code string, exitCode int) {
+	code = errorCode(err)
+	if code == "" {
+		return "", ExitCodeError
+	}
+	return code, exitCodes[code]
+}
+
+func errorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case isConnectionFailure(err):
+		return ErrorCodeConnectionFailure
+	case errors.Cause(err) == context.Canceled, errdefs.IsCancelled(err):
+		return ErrorCodeCancelled
+	case client.IsErrNotFound(errors.Cause(err)), errdefs.IsNotFound(err):
+		return ErrorCodeNotFound
+	case errdefs.IsConflict(err), errdefs.IsAlreadyExists(err):
+		return ErrorCodeConflict
+	case errdefs.IsInvalidParameter(err):
+		return ErrorCodeUsage
+	}
+	return ""
+}
+
+// ErrorCodeForExitCode returns the machine-readable code of the kind of error
+// of an exit code, such as ErrorCodeNotFound for ExitCodeNotFound, or an empty
+// string if the exit code is not the exit code of a kind of error.
+func ErrorCodeForExitCode(exitCode int) string {
+	for code, c := range exitCodes {
+		if c == exitCode {
+			return code
+		}
+	}
+	return ""
+}
+
+// isConnectionFailure returns whether err is a failure to connect to the
+// daemon. Some commands only return the message of the error.
+func isConnectionFailure(err error) bool {
+	return client.IsErrConnectionFailed(err) || strings.Contains(err.Error(), "Cannot connect to the Docker daemon")
+}
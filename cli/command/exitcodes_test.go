@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name             string
+		err              error
+		expectedCode     string
+		expectedExitCode int
+	}{
+		{
+			name:             "generic",
+			err:              errors.New("something went wrong"),
+			expectedExitCode: ExitCodeError,
+		},
+		{
+			name:             "not-found",
+			err:              errors.Wrap(errdefs.NotFound(errors.New("No such container: foo")), "error inspecting"),
+			expectedCode:     ErrorCodeNotFound,
+			expectedExitCode: ExitCodeNotFound,
+		},
+		{
+			name:             "connection-failure",
+			err:              errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"),
+			expectedCode:     ErrorCodeConnectionFailure,
+			expectedExitCode: ExitCodeConnectionFailure,
+		},
+		{
+			name:             "conflict",
+			err:              errdefs.Conflict(errors.New("You cannot remove a running container")),
+			expectedCode:     ErrorCodeConflict,
+			expectedExitCode: ExitCodeConflict,
+		},
+		{
+			name:             "already-exists",
+			err:              errdefs.AlreadyExists(errors.New("network foo already exists")),
+			expectedCode:     ErrorCodeConflict,
+			expectedExitCode: ExitCodeConflict,
+		},
+		{
+			name:             "invalid-parameter",
+			err:              errdefs.InvalidParameter(errors.New("invalid port")),
+			expectedCode:     ErrorCodeUsage,
+			expectedExitCode: ExitCodeUsage,
+		},
+		{
+			name:             "cancelled",
+			err:              errors.Wrap(context.Canceled, "error waiting"),
+			expectedCode:     ErrorCodeCancelled,
+			expectedExitCode: ExitCodeCancelled,
+		},
+	}
+	for _, tc := range testCases {
+		code, exitCode := ClassifyError(tc.err)
+		assert.Check(t, is.Equal(code, tc.expectedCode), tc.name)
+		assert.Check(t, is.Equal(exitCode, tc.expectedExitCode), tc.name)
+		if code != "" {
+			assert.Check(t, is.Equal(ErrorCodeForExitCode(exitCode), code), tc.name)
+		}
+	}
+	assert.Check(t, is.Equal(ErrorCodeForExitCode(2), ""))
+}
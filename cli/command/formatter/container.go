@@ -16,6 +16,7 @@ import (
 const (
 	defaultContainerTableFormat = "table {{.ID}}\t{{.Image}}\t{{.Command}}\t{{.RunningFor}}\t{{.Status}}\t{{.Ports}}\t{{.Names}}"
 
+	contextHeader    = "CONTEXT"
 	namesHeader      = "NAMES"
 	commandHeader    = "COMMAND"
 	runningForHeader = "CREATED"
@@ -61,7 +62,7 @@ ports: {{- pad .Ports 1 0}}
 func ContainerWrite(ctx Context, containers []types.Container) error {
 	render := func(format func(subContext SubContext) error) error {
 		for _, container := range containers {
-			err := format(&containerContext{trunc: ctx.Trunc, c: container})
+			err := format(&containerContext{trunc: ctx.Trunc, widths: ctx.Widths, c: container})
 			if err != nil {
 				return err
 			}
@@ -71,10 +72,53 @@ func ContainerWrite(ctx Context, containers []types.Container) error {
 	return ctx.Write(newContainerContext(), render)
 }
 
+// ContextContainers are the containers of the daemon of a context
+type ContextContainers struct {
+	Context    string
+	Containers []types.Container
+}
+
+// ContainerWriteContexts renders the context for the lists of containers of
+// several contexts, with the name of the context of each container
+func ContainerWriteContexts(ctx Context, contexts []ContextContainers) error {
+	render := func(format func(subContext SubContext) error) error {
+		for _, c := range contexts {
+			for _, container := range c.Containers {
+				err := format(&contextContainerContext{
+					containerContext: &containerContext{trunc: ctx.Trunc, widths: ctx.Widths, c: container},
+					context:          c.Context,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	containerCtx := newContainerContext()
+	containerCtx.Header.(SubHeaderContext)["Context"] = contextHeader
+	return ctx.Write(containerCtx, render)
+}
+
+// contextContainerContext is a container of the daemon of a context
+type contextContainerContext struct {
+	*containerContext
+	context string
+}
+
+func (c *contextContainerContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *contextContainerContext) Context() string {
+	return c.context
+}
+
 type containerContext struct {
 	HeaderContext
-	trunc bool
-	c     types.Container
+	trunc  bool
+	widths map[string]int
+	c      types.Container
 }
 
 func newContainerContext() *containerContext {
@@ -151,7 +195,7 @@ func (c *containerContext) Image() string {
 func (c *containerContext) Command() string {
 	command := c.c.Command
 	if c.trunc {
-		command = Ellipsis(command, 20)
+		command = TruncateColumn(command, c.widths, "Command", 20)
 	}
 	return strconv.Quote(command)
 }
@@ -213,7 +257,7 @@ func (c *containerContext) Mounts() string {
 			name = m.Name
 		}
 		if c.trunc {
-			name = Ellipsis(name, 15)
+			name = TruncateColumn(name, c.widths, "Mounts", 15)
 		}
 		mounts = append(mounts, name)
 	}
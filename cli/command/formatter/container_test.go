@@ -656,3 +656,38 @@ func TestDisplayablePorts(t *testing.T) {
 		assert.Check(t, is.Equal(port.expected, actual))
 	}
 }
+
+func TestContainerContextWriteContexts(t *testing.T) {
+	contexts := []ContextContainers{
+		{Context: "default", Containers: []types.Container{{ID: "containerID1", Names: []string{"/foobar_baz"}, Image: "ubuntu"}}},
+		{Context: "prod", Containers: []types.Container{{ID: "containerID2", Names: []string{"/foobar_bar"}, Image: "busybox"}}},
+	}
+	testCases := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: WithContextColumn("table {{.Names}}\t{{.Image}}"),
+			expected: `CONTEXT             NAMES               IMAGE
+default             foobar_baz          ubuntu
+prod                foobar_bar          busybox
+`,
+		},
+		{
+			format: WithContextColumn("table {{.Image}}\t{{.Context}}"),
+			expected: `IMAGE               CONTEXT
+ubuntu              default
+busybox             prod
+`,
+		},
+		{
+			format:   WithContextColumn(`{{json .Context}} {{.Names}}`),
+			expected: "\"default\" foobar_baz\n\"prod\" foobar_bar\n",
+		},
+	}
+	for _, tc := range testCases {
+		out := bytes.NewBufferString("")
+		assert.NilError(t, ContainerWriteContexts(Context{Format: tc.format, Output: out}, contexts))
+		assert.Check(t, is.Equal(tc.expected, out.String()))
+	}
+}
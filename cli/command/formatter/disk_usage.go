@@ -3,8 +3,11 @@ package formatter
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
@@ -15,6 +18,7 @@ const (
 	defaultDiskUsageImageTableFormat      = "table {{.Repository}}\t{{.Tag}}\t{{.ID}}\t{{.CreatedSince}}\t{{.VirtualSize}}\t{{.SharedSize}}\t{{.UniqueSize}}\t{{.Containers}}"
 	defaultDiskUsageContainerTableFormat  = "table {{.ID}}\t{{.Image}}\t{{.Command}}\t{{.LocalVolumes}}\t{{.Size}}\t{{.RunningFor}}\t{{.Status}}\t{{.Names}}"
 	defaultDiskUsageVolumeTableFormat     = "table {{.Name}}\t{{.Links}}\t{{.Size}}"
+	defaultDiskUsageLayerTableFormat      = "table {{.Image}}\t{{.CreatedBy}}\t{{.Size}}\t{{.SharedBy}}"
 	defaultDiskUsageBuildCacheTableFormat = "table {{.ID}}\t{{.CacheType}}\t{{.Size}}\t{{.CreatedSince}}\t{{.LastUsedSince}}\t{{.UsageCount}}\t{{.Shared}}"
 	defaultDiskUsageTableFormat           = "table {{.Type}}\t{{.TotalCount}}\t{{.Active}}\t{{.Size}}\t{{.Reclaimable}}"
 
@@ -25,6 +29,23 @@ const (
 	containersHeader  = "CONTAINERS"
 	sharedSizeHeader  = "SHARED SIZE"
 	uniqueSizeHeader  = "UNIQUE SIZE"
+	createdByHeader   = "CREATED BY"
+	sharedByHeader    = "SHARED BY"
+)
+
+// Object types that can be selected in DiskUsageContext.Types
+const (
+	DiskUsageTypeImages     = "images"
+	DiskUsageTypeContainers = "containers"
+	DiskUsageTypeVolumes    = "volumes"
+	DiskUsageTypeBuildCache = "build-cache"
+)
+
+// Sort orders supported by DiskUsageContext.SortBy
+const (
+	DiskUsageSortBySize    = "size"
+	DiskUsageSortByName    = "name"
+	DiskUsageSortByCreated = "created"
 )
 
 // DiskUsageContext contains disk usage specific information required by the formatter, encapsulate a Context struct.
@@ -37,6 +58,39 @@ type DiskUsageContext struct {
 	Volumes     []*types.Volume
 	BuildCache  []*types.BuildCache
 	BuilderSize int64
+	// Types restricts the output to the given object types. All object
+	// types are written if empty.
+	Types []string
+	// SortBy sets the order in which objects are written in verbose mode.
+	// Objects are written in the order returned by the daemon if empty.
+	SortBy string
+	// ImageLayers are the layer chains of the images, by image ID, which
+	// are written after the images in verbose mode.
+	ImageLayers map[string][]ImageLayer
+}
+
+// ImageLayer is a layer of the layer chain of an image
+type ImageLayer struct {
+	CreatedBy string
+	Created   int64
+	Size      int64
+	// SharedBy is the number of images whose layer chain includes the
+	// layer, and the layers below it
+	SharedBy int
+}
+
+// Includes returns whether the objects of the given type are written, as
+// selected by Types
+func (ctx *DiskUsageContext) Includes(objectType string) bool {
+	if len(ctx.Types) == 0 {
+		return true
+	}
+	for _, t := range ctx.Types {
+		if t == objectType {
+			return true
+		}
+	}
+	return false
 }
 
 func (ctx *DiskUsageContext) startSubsection(format string) (*template.Template, error) {
@@ -92,33 +146,39 @@ func (ctx *DiskUsageContext) Write() (err error) {
 		return err
 	}
 
-	err = ctx.contextFormat(tmpl, &diskUsageImagesContext{
-		totalSize: ctx.LayersSize,
-		images:    ctx.Images,
-	})
-	if err != nil {
-		return err
+	if ctx.Includes(DiskUsageTypeImages) {
+		err = ctx.contextFormat(tmpl, &diskUsageImagesContext{
+			totalSize: ctx.LayersSize,
+			images:    ctx.Images,
+		})
+		if err != nil {
+			return err
+		}
 	}
-	err = ctx.contextFormat(tmpl, &diskUsageContainersContext{
-		containers: ctx.Containers,
-	})
-	if err != nil {
-		return err
+	if ctx.Includes(DiskUsageTypeContainers) {
+		err = ctx.contextFormat(tmpl, &diskUsageContainersContext{
+			containers: ctx.Containers,
+		})
+		if err != nil {
+			return err
+		}
 	}
-
-	err = ctx.contextFormat(tmpl, &diskUsageVolumesContext{
-		volumes: ctx.Volumes,
-	})
-	if err != nil {
-		return err
+	if ctx.Includes(DiskUsageTypeVolumes) {
+		err = ctx.contextFormat(tmpl, &diskUsageVolumesContext{
+			volumes: ctx.Volumes,
+		})
+		if err != nil {
+			return err
+		}
 	}
-
-	err = ctx.contextFormat(tmpl, &diskUsageBuilderContext{
-		builderSize: ctx.BuilderSize,
-		buildCache:  ctx.BuildCache,
-	})
-	if err != nil {
-		return err
+	if ctx.Includes(DiskUsageTypeBuildCache) {
+		err = ctx.contextFormat(tmpl, &diskUsageBuilderContext{
+			builderSize: ctx.BuilderSize,
+			buildCache:  ctx.BuildCache,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	diskUsageContainersCtx := diskUsageContainersContext{containers: []*types.Container{}}
@@ -136,6 +196,7 @@ func (ctx *DiskUsageContext) Write() (err error) {
 
 type diskUsageContext struct {
 	Images     []*imageContext
+	Layers     []*imageLayerContext `json:",omitempty"`
 	Containers []*containerContext
 	Volumes    []*volumeContext
 	BuildCache []*buildCacheContext
@@ -149,9 +210,17 @@ func (ctx *DiskUsageContext) verboseWrite() error {
 		BuildCache: make([]*buildCacheContext, 0, len(ctx.BuildCache)),
 	}
 	trunc := ctx.Format.IsTable()
+	if ctx.SortBy == "" {
+		buildCacheSort(ctx.BuildCache)
+	} else {
+		ctx.sortObjects()
+	}
 
 	// First images
 	for _, i := range ctx.Images {
+		if !ctx.Includes(DiskUsageTypeImages) {
+			break
+		}
 		repo := "<none>"
 		tag := "<none>"
 		if len(i.RepoTags) > 0 && !isDangling(*i) {
@@ -166,16 +235,23 @@ func (ctx *DiskUsageContext) verboseWrite() error {
 			}
 		}
 
-		duc.Images = append(duc.Images, &imageContext{
+		imgCtx := &imageContext{
 			repo:  repo,
 			tag:   tag,
 			trunc: trunc,
 			i:     *i,
-		})
+		}
+		duc.Images = append(duc.Images, imgCtx)
+		for _, l := range ctx.ImageLayers[i.ID] {
+			duc.Layers = append(duc.Layers, &imageLayerContext{image: imgCtx, trunc: trunc, l: l})
+		}
 	}
 
 	// Now containers
 	for _, c := range ctx.Containers {
+		if !ctx.Includes(DiskUsageTypeContainers) {
+			break
+		}
 		// Don't display the virtual size
 		c.SizeRootFs = 0
 		duc.Containers = append(duc.Containers, &containerContext{trunc: trunc, c: *c})
@@ -183,12 +259,17 @@ func (ctx *DiskUsageContext) verboseWrite() error {
 
 	// And volumes
 	for _, v := range ctx.Volumes {
+		if !ctx.Includes(DiskUsageTypeVolumes) {
+			break
+		}
 		duc.Volumes = append(duc.Volumes, &volumeContext{v: *v})
 	}
 
 	// And build cache
-	buildCacheSort(ctx.BuildCache)
 	for _, v := range ctx.BuildCache {
+		if !ctx.Includes(DiskUsageTypeBuildCache) {
+			break
+		}
 		duc.BuildCache = append(duc.BuildCache, &buildCacheContext{v: v, trunc: trunc})
 	}
 
@@ -205,57 +286,183 @@ func (ctx *DiskUsageContext) verboseWrite() error {
 }
 
 func (ctx *DiskUsageContext) verboseWriteTable(duc *diskUsageContext) error {
-	tmpl, err := ctx.startSubsection(defaultDiskUsageImageTableFormat)
-	if err != nil {
-		return err
+	var separator string
+	startSection := func(format, title string) (*template.Template, error) {
+		tmpl, err := ctx.startSubsection(format)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(ctx.Output, "%s%s\n\n", separator, title)
+		separator = "\n"
+		return tmpl, nil
 	}
-	ctx.Output.Write([]byte("Images space usage:\n\n"))
-	for _, img := range duc.Images {
-		if err := ctx.contextFormat(tmpl, img); err != nil {
+
+	if ctx.Includes(DiskUsageTypeImages) {
+		tmpl, err := startSection(defaultDiskUsageImageTableFormat, "Images space usage:")
+		if err != nil {
 			return err
 		}
+		for _, img := range duc.Images {
+			if err := ctx.contextFormat(tmpl, img); err != nil {
+				return err
+			}
+		}
+		ctx.postFormat(tmpl, newImageContext())
 	}
-	ctx.postFormat(tmpl, newImageContext())
 
-	tmpl, err = ctx.startSubsection(defaultDiskUsageContainerTableFormat)
-	if err != nil {
-		return err
-	}
-	ctx.Output.Write([]byte("\nContainers space usage:\n\n"))
-	for _, c := range duc.Containers {
-		if err := ctx.contextFormat(tmpl, c); err != nil {
+	if ctx.Includes(DiskUsageTypeImages) && len(duc.Layers) > 0 {
+		tmpl, err := startSection(defaultDiskUsageLayerTableFormat, "Image layers space usage:")
+		if err != nil {
 			return err
 		}
+		for _, l := range duc.Layers {
+			if err := ctx.contextFormat(tmpl, l); err != nil {
+				return err
+			}
+		}
+		ctx.postFormat(tmpl, newImageLayerContext())
 	}
-	ctx.postFormat(tmpl, newContainerContext())
 
-	tmpl, err = ctx.startSubsection(defaultDiskUsageVolumeTableFormat)
-	if err != nil {
-		return err
-	}
-	ctx.Output.Write([]byte("\nLocal Volumes space usage:\n\n"))
-	for _, v := range duc.Volumes {
-		if err := ctx.contextFormat(tmpl, v); err != nil {
+	if ctx.Includes(DiskUsageTypeContainers) {
+		tmpl, err := startSection(defaultDiskUsageContainerTableFormat, "Containers space usage:")
+		if err != nil {
 			return err
 		}
+		for _, c := range duc.Containers {
+			if err := ctx.contextFormat(tmpl, c); err != nil {
+				return err
+			}
+		}
+		ctx.postFormat(tmpl, newContainerContext())
 	}
-	ctx.postFormat(tmpl, newVolumeContext())
 
-	tmpl, err = ctx.startSubsection(defaultDiskUsageBuildCacheTableFormat)
-	if err != nil {
-		return err
+	if ctx.Includes(DiskUsageTypeVolumes) {
+		tmpl, err := startSection(defaultDiskUsageVolumeTableFormat, "Local Volumes space usage:")
+		if err != nil {
+			return err
+		}
+		for _, v := range duc.Volumes {
+			if err := ctx.contextFormat(tmpl, v); err != nil {
+				return err
+			}
+		}
+		ctx.postFormat(tmpl, newVolumeContext())
 	}
-	fmt.Fprintf(ctx.Output, "\nBuild cache usage: %s\n\n", units.HumanSize(float64(ctx.BuilderSize)))
-	for _, v := range duc.BuildCache {
-		if err := ctx.contextFormat(tmpl, v); err != nil {
+
+	if ctx.Includes(DiskUsageTypeBuildCache) {
+		tmpl, err := startSection(defaultDiskUsageBuildCacheTableFormat, "Build cache usage: "+units.HumanSize(float64(ctx.BuilderSize)))
+		if err != nil {
 			return err
 		}
+		for _, v := range duc.BuildCache {
+			if err := ctx.contextFormat(tmpl, v); err != nil {
+				return err
+			}
+		}
+		ctx.postFormat(tmpl, newBuildCacheContext())
 	}
-	ctx.postFormat(tmpl, newBuildCacheContext())
 
 	return nil
 }
 
+// sortObjects sorts the objects of each type according to ctx.SortBy. Sizes
+// and creation times are sorted in descending order, so that the largest and
+// most recent objects come first.
+func (ctx *DiskUsageContext) sortObjects() {
+	switch ctx.SortBy {
+	case DiskUsageSortBySize:
+		sort.SliceStable(ctx.Images, func(i, j int) bool { return ctx.Images[i].Size > ctx.Images[j].Size })
+		sort.SliceStable(ctx.Containers, func(i, j int) bool { return ctx.Containers[i].SizeRw > ctx.Containers[j].SizeRw })
+		sort.SliceStable(ctx.Volumes, func(i, j int) bool { return volumeSize(ctx.Volumes[i]) > volumeSize(ctx.Volumes[j]) })
+		sort.SliceStable(ctx.BuildCache, func(i, j int) bool { return ctx.BuildCache[i].Size > ctx.BuildCache[j].Size })
+	case DiskUsageSortByName:
+		sort.SliceStable(ctx.Images, func(i, j int) bool {
+			return firstOrEmpty(ctx.Images[i].RepoTags) < firstOrEmpty(ctx.Images[j].RepoTags)
+		})
+		sort.SliceStable(ctx.Containers, func(i, j int) bool {
+			return firstOrEmpty(ctx.Containers[i].Names) < firstOrEmpty(ctx.Containers[j].Names)
+		})
+		sort.SliceStable(ctx.Volumes, func(i, j int) bool { return ctx.Volumes[i].Name < ctx.Volumes[j].Name })
+		sort.SliceStable(ctx.BuildCache, func(i, j int) bool { return ctx.BuildCache[i].ID < ctx.BuildCache[j].ID })
+	case DiskUsageSortByCreated:
+		sort.SliceStable(ctx.Images, func(i, j int) bool { return ctx.Images[i].Created > ctx.Images[j].Created })
+		sort.SliceStable(ctx.Containers, func(i, j int) bool { return ctx.Containers[i].Created > ctx.Containers[j].Created })
+		// Volume creation times are RFC 3339 formatted, so they sort lexically
+		sort.SliceStable(ctx.Volumes, func(i, j int) bool { return ctx.Volumes[i].CreatedAt > ctx.Volumes[j].CreatedAt })
+		sort.SliceStable(ctx.BuildCache, func(i, j int) bool {
+			return ctx.BuildCache[i].CreatedAt.After(ctx.BuildCache[j].CreatedAt)
+		})
+	}
+}
+
+// volumeSize returns the size of a volume, or -1 if the daemon did not
+// compute its usage data
+func volumeSize(v *types.Volume) int64 {
+	if v.UsageData == nil {
+		return -1
+	}
+	return v.UsageData.Size
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+type imageLayerContext struct {
+	HeaderContext
+	image *imageContext
+	trunc bool
+	l     ImageLayer
+}
+
+func newImageLayerContext() *imageLayerContext {
+	layerCtx := imageLayerContext{}
+	layerCtx.Header = SubHeaderContext{
+		"Image":        ImageHeader,
+		"CreatedBy":    createdByHeader,
+		"CreatedSince": CreatedSinceHeader,
+		"Size":         SizeHeader,
+		"SharedBy":     sharedByHeader,
+	}
+	return &layerCtx
+}
+
+func (c *imageLayerContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+// Image returns the first tag of the image of the layer, or its ID if it has
+// none
+func (c *imageLayerContext) Image() string {
+	if c.image.repo == "<none>" {
+		return c.image.ID()
+	}
+	return c.image.repo + ":" + c.image.tag
+}
+
+func (c *imageLayerContext) CreatedBy() string {
+	createdBy := strings.Replace(c.l.CreatedBy, "\t", " ", -1)
+	if c.trunc {
+		return Ellipsis(createdBy, 45)
+	}
+	return createdBy
+}
+
+func (c *imageLayerContext) CreatedSince() string {
+	return units.HumanDuration(time.Now().UTC().Sub(time.Unix(c.l.Created, 0))) + " ago"
+}
+
+func (c *imageLayerContext) Size() string {
+	return units.HumanSizeWithPrecision(float64(c.l.Size), 3)
+}
+
+func (c *imageLayerContext) SharedBy() string {
+	return strconv.Itoa(c.l.SharedBy)
+}
+
 type diskUsageImagesContext struct {
 	HeaderContext
 	totalSize int64
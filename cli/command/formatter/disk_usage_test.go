@@ -3,7 +3,9 @@ package formatter
 import (
 	"bytes"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	"gotest.tools/golden"
@@ -117,3 +119,96 @@ Build Cache         0                   0                   0B
 		}
 	}
 }
+
+func TestDiskUsageContextWriteTypes(t *testing.T) {
+	ctx := DiskUsageContext{
+		Context: Context{Format: NewDiskUsageFormat("table", false)},
+		Types:   []string{DiskUsageTypeVolumes, DiskUsageTypeImages},
+	}
+	out := bytes.NewBufferString("")
+	ctx.Output = out
+	assert.NilError(t, ctx.Write())
+	expected := `TYPE                TOTAL               ACTIVE              SIZE                RECLAIMABLE
+Images              0                   0                   0B                  0B
+Local Volumes       0                   0                   0B                  0B
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+
+	ctx = DiskUsageContext{
+		Context: Context{Format: NewDiskUsageFormat("table", true)},
+		Verbose: true,
+		Types:   []string{DiskUsageTypeVolumes},
+	}
+	out = bytes.NewBufferString("")
+	ctx.Output = out
+	assert.NilError(t, ctx.Write())
+	expected = `Local Volumes space usage:
+
+VOLUME NAME         LINKS               SIZE
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+}
+
+func TestDiskUsageContextWriteSortBy(t *testing.T) {
+	volumes := func() []*types.Volume {
+		return []*types.Volume{
+			{Name: "small", CreatedAt: "2019-01-02T00:00:00Z", UsageData: &types.VolumeUsageData{Size: 10}},
+			{Name: "large", CreatedAt: "2019-01-01T00:00:00Z", UsageData: &types.VolumeUsageData{Size: 2048}},
+			{Name: "medium", CreatedAt: "2019-01-03T00:00:00Z", UsageData: &types.VolumeUsageData{Size: 100}},
+			// The usage data of the volumes is optional
+			{Name: "unknown"},
+		}
+	}
+	cases := []struct {
+		sortBy   string
+		expected string
+	}{
+		{sortBy: "", expected: "small\nlarge\nmedium\nunknown\n"},
+		{sortBy: DiskUsageSortBySize, expected: "large\nmedium\nsmall\nunknown\n"},
+		{sortBy: DiskUsageSortByName, expected: "large\nmedium\nsmall\nunknown\n"},
+		{sortBy: DiskUsageSortByCreated, expected: "medium\nsmall\nlarge\nunknown\n"},
+	}
+	for _, tc := range cases {
+		out := bytes.NewBufferString("")
+		ctx := DiskUsageContext{
+			Context: Context{
+				Format: NewDiskUsageFormat("{{range .Volumes}}{{.Name}}\n{{end}}", true),
+				Output: out,
+			},
+			Verbose: true,
+			Volumes: volumes(),
+			SortBy:  tc.sortBy,
+		}
+		assert.NilError(t, ctx.Write())
+		assert.Check(t, is.Equal(tc.expected, out.String()), tc.sortBy)
+	}
+}
+
+func TestDiskUsageContextWriteLayers(t *testing.T) {
+	out := bytes.NewBufferString("")
+	ctx := DiskUsageContext{
+		Context: Context{Format: NewDiskUsageFormat("table", true), Output: out},
+		Verbose: true,
+		Types:   []string{DiskUsageTypeImages},
+		Images: []*types.ImageSummary{
+			{ID: "sha256:b6a3b6b0d44a56d41b12a2e5eb3c9b4ee8d4bd49cd05a9e151d2ef56c927245e", Created: time.Now().Add(-time.Hour).Unix()},
+		},
+		ImageLayers: map[string][]ImageLayer{
+			"sha256:b6a3b6b0d44a56d41b12a2e5eb3c9b4ee8d4bd49cd05a9e151d2ef56c927245e": {
+				{CreatedBy: "/bin/sh -c #(nop) ADD file:a5ce8d0b2efaa2fd0cb6ee2e68c6d1fb1be2ffa635dc0f2846a5aa756e7e2f1b in /", Size: 5000000, SharedBy: 2},
+			},
+		},
+	}
+	assert.NilError(t, ctx.Write())
+	expected := `Images space usage:
+
+REPOSITORY          TAG                 IMAGE ID            CREATED             SIZE                SHARED SIZE         UNIQUE SIZE         CONTAINERS
+<none>              <none>              b6a3b6b0d44a        About an hour ago   0B                  0B                  0B                  0
+
+Image layers space usage:
+
+IMAGE               CREATED BY                                      SIZE                SHARED BY
+b6a3b6b0d44a        /bin/sh -c #(nop) ADD file:a5ce8d0b2efaa2fd0…   5MB                 2
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+}
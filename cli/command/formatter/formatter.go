@@ -33,6 +33,16 @@ func (f Format) Contains(sub string) bool {
 	return strings.Contains(string(f), sub)
 }
 
+// WithContextColumn returns the table-type format with a first column of the
+// name of the context of each row, unless the format already has one. Other
+// formats are returned unchanged.
+func WithContextColumn(format Format) Format {
+	if !format.IsTable() || format.Contains("{{.Context}}") {
+		return format
+	}
+	return Format(TableFormatKey + " {{.Context}}\t" + strings.TrimLeft(string(format[len(TableFormatKey):]), " "))
+}
+
 // Context contains information required by the formatter to print the output as desired.
 type Context struct {
 	// Output is the output stream to which the formatted string is written.
@@ -41,6 +51,10 @@ type Context struct {
 	Format Format
 	// Trunc when set to true will truncate the output of certain fields such as Container ID.
 	Trunc bool
+	// Widths are the maximum display widths of the truncated columns, by
+	// lowercase field name, which override their default widths. See
+	// TruncateColumn.
+	Widths map[string]int
 
 	// internal element
 	finalFormat string
@@ -49,6 +63,11 @@ type Context struct {
 }
 
 func (c *Context) preFormat() {
+	// The formats referencing a named template which does not exist are
+	// parsed as is, so that parseFormat returns the error
+	if format, err := templates.Expand(string(c.Format)); err == nil {
+		c.Format = Format(format)
+	}
 	c.finalFormat = string(c.Format)
 
 	// TODO: handle this in the Format type
@@ -69,9 +88,15 @@ func (c *Context) parseFormat() (*template.Template, error) {
 	return tmpl, err
 }
 
+// NewTableWriter returns a writer aligning the columns of a table-type
+// format, separated by tabs
+func NewTableWriter(output io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(output, 20, 1, 3, ' ', 0)
+}
+
 func (c *Context) postFormat(tmpl *template.Template, subContext SubContext) {
 	if c.Format.IsTable() {
-		t := tabwriter.NewWriter(c.Output, 20, 1, 3, ' ', 0)
+		t := NewTableWriter(c.Output)
 		buffer := bytes.NewBufferString("")
 		tmpl.Funcs(templates.HeaderFunctions).Execute(buffer, subContext.FullHeader())
 		buffer.WriteTo(t)
@@ -79,6 +79,46 @@ func ImageWrite(ctx ImageContext, images []types.ImageSummary) error {
 	return ctx.Write(newImageContext(), render)
 }
 
+// ContextImages are the images of the daemon of a context
+type ContextImages struct {
+	Context string
+	Images  []types.ImageSummary
+}
+
+// ImageWriteContexts writes the formatter images of several contexts, with the
+// name of the context of each image
+func ImageWriteContexts(ctx ImageContext, contexts []ContextImages) error {
+	render := func(format func(subContext SubContext) error) error {
+		for _, c := range contexts {
+			name := c.Context
+			err := imageFormat(ctx, c.Images, func(subContext SubContext) error {
+				return format(&contextImageContext{imageContext: subContext.(*imageContext), context: name})
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	imageCtx := newImageContext()
+	imageCtx.Header.(SubHeaderContext)["Context"] = contextHeader
+	return ctx.Write(imageCtx, render)
+}
+
+// contextImageContext is an image of the daemon of a context
+type contextImageContext struct {
+	*imageContext
+	context string
+}
+
+func (c *contextImageContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *contextImageContext) Context() string {
+	return c.context
+}
+
 // needDigest determines whether the image digest should be ignored or not when writing image context
 func needDigest(ctx ImageContext) bool {
 	return ctx.Digest || ctx.Format.Contains("{{.Digest}}")
@@ -0,0 +1,66 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+// Truncation is the policy of the truncation of the output of the commands,
+// such as the IDs and the COMMAND column of "docker ps". It is set with the
+// global --no-trunc flag, and the "truncation" property of the configuration
+// file.
+type Truncation struct {
+	// Disabled disables the truncation of the output of all the commands.
+	Disabled bool
+	// ColumnWidths are the maximum display widths of the truncated columns,
+	// by command, such as "ps", and by lowercase field name, such as
+	// "command".
+	ColumnWidths map[string]map[string]int
+}
+
+// NewTruncation returns the truncation policy of the global --no-trunc flag
+// and of the configuration file
+func NewTruncation(noTrunc bool, config *configfile.TruncationConfig) Truncation {
+	t := Truncation{Disabled: noTrunc}
+	if config == nil {
+		return t
+	}
+	t.Disabled = t.Disabled || config.NoTrunc
+	for command, widths := range config.ColumnWidths {
+		if t.ColumnWidths == nil {
+			t.ColumnWidths = map[string]map[string]int{}
+		}
+		t.ColumnWidths[command] = map[string]int{}
+		for column, width := range widths {
+			t.ColumnWidths[command][strings.ToLower(column)] = width
+		}
+	}
+	return t
+}
+
+// Trunc returns whether the output of a command is truncated, given its
+// --no-trunc flag
+func (t Truncation) Trunc(noTrunc bool) bool {
+	return !noTrunc && !t.Disabled
+}
+
+// Widths returns the maximum display widths of the truncated columns of a
+// command, such as "ps", by lowercase field name
+func (t Truncation) Widths(command string) map[string]int {
+	return t.ColumnWidths[command]
+}
+
+// TruncateColumn truncates the value of a column, such as "Command", to its
+// width in widths, or to defaultWidth if widths does not have the column, and
+// appends an ellipsis (…). The value is not truncated if the width is 0.
+func TruncateColumn(s string, widths map[string]int, column string, defaultWidth int) string {
+	width, ok := widths[strings.ToLower(column)]
+	if !ok {
+		width = defaultWidth
+	}
+	if width <= 0 {
+		return s
+	}
+	return Ellipsis(s, width)
+}
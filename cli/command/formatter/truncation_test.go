@@ -0,0 +1,35 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewTruncation(t *testing.T) {
+	truncation := NewTruncation(false, nil)
+	assert.Check(t, truncation.Trunc(false))
+	assert.Check(t, !truncation.Trunc(true))
+	assert.Check(t, is.Nil(truncation.Widths("ps")))
+
+	truncation = NewTruncation(true, nil)
+	assert.Check(t, !truncation.Trunc(false))
+
+	truncation = NewTruncation(false, &configfile.TruncationConfig{
+		NoTrunc:      true,
+		ColumnWidths: map[string]map[string]int{"ps": {"Command": 40}},
+	})
+	assert.Check(t, !truncation.Trunc(false))
+	assert.Check(t, is.DeepEqual(map[string]int{"command": 40}, truncation.Widths("ps")))
+	assert.Check(t, is.Nil(truncation.Widths("history")))
+}
+
+func TestTruncateColumn(t *testing.T) {
+	widths := map[string]int{"command": 5, "mounts": 0}
+	assert.Check(t, is.Equal("echo…", TruncateColumn("echo hello", widths, "Command", 20)))
+	assert.Check(t, is.Equal("/var/lib/data", TruncateColumn("/var/lib/data", widths, "Mounts", 5)))
+	assert.Check(t, is.Equal("a desc…", TruncateColumn("a description", widths, "Description", 7)))
+	assert.Check(t, is.Equal("short", TruncateColumn("short", nil, "Description", 7)))
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/templates"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/stringid"
 	"gotest.tools/assert"
@@ -140,6 +141,29 @@ foobar_bar
 	}
 }
 
+func TestVolumeContextWriteNamedTemplate(t *testing.T) {
+	templates.SetNamedTemplates(map[string]string{
+		"volumes": `table {{template "name" .}}\t{{.Driver}}`,
+		"name":    `{{upper .Name}}`,
+	})
+	defer templates.SetNamedTemplates(nil)
+
+	volumes := []*types.Volume{
+		{Name: "foobar_baz", Driver: "foo"},
+		{Name: "foobar_bar", Driver: "bar"},
+	}
+	out := bytes.NewBufferString("")
+	assert.NilError(t, VolumeWrite(Context{Format: NewVolumeFormat("template:volumes", false), Output: out}, volumes))
+	expected := `VOLUME NAME         DRIVER
+FOOBAR_BAZ          foo
+FOOBAR_BAR          bar
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+
+	err := VolumeWrite(Context{Format: NewVolumeFormat("template:unknown", false), Output: out}, volumes)
+	assert.Check(t, is.ErrorContains(err, `template "unknown" is not defined`))
+}
+
 func TestVolumeContextWriteJSON(t *testing.T) {
 	volumes := []*types.Volume{
 		{Driver: "foo", Name: "foobar_baz"},
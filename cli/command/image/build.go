@@ -14,15 +14,18 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/cli/cli/compression"
 	"github.com/docker/cli/opts"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -63,6 +66,7 @@ type buildOptions struct {
 	pull           bool
 	cacheFrom      []string
 	compress       bool
+	showContext    bool
 	securityOpt    []string
 	networkMode    string
 	squash         bool
@@ -135,8 +139,10 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the build output and print image ID on success")
 	flags.BoolVar(&options.pull, "pull", false, "Always attempt to pull a newer version of the image")
 	flags.StringSliceVar(&options.cacheFrom, "cache-from", []string{}, "Images to consider as cache sources")
-	flags.BoolVar(&options.compress, "compress", false, "Compress the build context using gzip")
+	flags.BoolVar(&options.compress, "compress", false, "Compress the build context using zstd if supported, or gzip")
 	flags.SetAnnotation("compress", "no-buildkit", nil)
+	flags.BoolVar(&options.showContext, "show-context", false, "List the files of the build context, after applying .dockerignore, instead of building")
+	flags.SetAnnotation("show-context", "no-buildkit", nil)
 
 	flags.StringSliceVar(&options.securityOpt, "security-opt", []string{}, "Security options")
 	flags.StringVar(&options.networkMode, "network", "default", "Set the networking mode for the RUN instructions during build")
@@ -186,6 +192,43 @@ func (out *lastProgressOutput) WriteProgress(prog progress.Progress) error {
 	return out.output.WriteProgress(prog)
 }
 
+// contextCompression returns the compression of the build context: zstd if
+// the daemon supports it and the zstd command is available, or gzip. The
+// daemon decompresses zstd build contexts from API version 1.41, whichever
+// API version the client uses.
+func contextCompression(dockerCli command.Cli) compression.Compression {
+	apiVersion := dockerCli.ServerInfo().APIVersion
+	if apiVersion != "" && versions.GreaterThanOrEqualTo(apiVersion, "1.41") && compression.Supported(compression.Zstd) == nil {
+		return compression.Zstd
+	}
+	return compression.Gzip
+}
+
+// showContext prints the files of the build context, with their sizes and
+// the total size of the context.
+func showContext(out io.Writer, buildCtx io.Reader) error {
+	entries, err := build.ListContext(buildCtx)
+	if err != nil {
+		return err
+	}
+	var total int64
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SIZE\tPATH")
+	for _, entry := range entries {
+		path := entry.Path
+		if entry.Linkname != "" {
+			path += " -> " + entry.Linkname
+		}
+		fmt.Fprintf(w, "%s\t%s\n", units.HumanSizeWithPrecision(float64(entry.Size), 3), path)
+		total += entry.Size
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Total: %s in %d files\n", units.HumanSizeWithPrecision(float64(total), 3), len(entries))
+	return err
+}
+
 // nolint: gocyclo
 func runBuild(dockerCli command.Cli, options buildOptions) error {
 	buildkitEnabled, err := command.BuildKitEnabled(dockerCli.ServerInfo())
@@ -193,6 +236,9 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 		return err
 	}
 	if buildkitEnabled {
+		if options.showContext {
+			return errors.New("--show-context is not supported with BuildKit")
+		}
 		return runBuildBuildKit(dockerCli, options)
 	}
 
@@ -205,11 +251,15 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 		progBuff      io.Writer
 		buildBuff     io.Writer
 		remote        string
+		contextSize   int64
 	)
 
 	if options.compress && options.stream {
 		return errors.New("--compress conflicts with --stream options")
 	}
+	if options.showContext && options.stream {
+		return errors.New("--show-context conflicts with --stream options")
+	}
 
 	if options.dockerfileFromStdin() {
 		if options.contextFromStdin() {
@@ -281,6 +331,11 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 		relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
 
 		excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, options.dockerfileFromStdin())
+		if !options.showContext {
+			// The size is only used to estimate the time remaining, the
+			// context can be sent without it.
+			contextSize, _ = build.EstimateContextSize(contextDir, excludes)
+		}
 		buildCtx, err = archive.TarWithOptions(contextDir, &archive.TarOptions{
 			ExcludePatterns: excludes,
 			ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
@@ -298,6 +353,11 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 		}
 	}
 
+	if options.showContext {
+		defer buildCtx.Close()
+		return showContext(dockerCli.Out(), buildCtx)
+	}
+
 	// if streaming and Dockerfile was not from stdin then read from file
 	// to the same reader that is usually stdin
 	if options.stream && dockerfileCtx == nil {
@@ -308,7 +368,7 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 		defer dockerfileCtx.Close()
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(dockerCli.Context())
 	defer cancel()
 
 	var resolvedTags []*resolvedTag
@@ -331,17 +391,24 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 		}
 	}
 
-	if options.compress {
-		buildCtx, err = build.Compress(buildCtx)
-		if err != nil {
-			return err
-		}
-	}
-
 	// Setup an upload progress bar
 	progressOutput := streamformatter.NewProgressOutput(progBuff)
+	transferOutput := build.NewTransferProgressOutput(progBuff)
 	if !dockerCli.Out().IsTerminal() {
 		progressOutput = &lastProgressOutput{output: progressOutput}
+		transferOutput = &lastProgressOutput{output: transferOutput}
+	}
+
+	if buildCtx != nil && !options.stream {
+		// The progress is the one of the archive before compression, whose
+		// size is estimated.
+		buildCtx = progress.NewProgressReader(buildCtx, transferOutput, contextSize, "", "Sending build context to Docker daemon")
+		if options.compress {
+			buildCtx, err = build.CompressWith(buildCtx, contextCompression(dockerCli))
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// if up to this point nothing has set the context then we must have another
@@ -357,7 +424,7 @@ func runBuild(dockerCli command.Cli, options buildOptions) error {
 
 	var body io.Reader
 	if buildCtx != nil && !options.stream {
-		body = progress.NewProgressReader(buildCtx, progressOutput, 0, "", "Sending build context to Docker daemon")
+		body = buildCtx
 	}
 
 	// add context stream to the session
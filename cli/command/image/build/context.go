@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/cli/cli/compression"
 	"github.com/docker/docker/builder/remotecontext/git"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/fileutils"
@@ -402,24 +403,96 @@ func AddDockerfileToBuildContext(dockerfileCtx io.ReadCloser, buildCtx io.ReadCl
 
 // Compress the build context for sending to the API
 func Compress(buildCtx io.ReadCloser) (io.ReadCloser, error) {
+	return CompressWith(buildCtx, compression.Gzip)
+}
+
+// CompressWith compresses the build context with the given compression for
+// sending to the API. The build context is read ahead of the compression, so
+// that reading the files of the context, compressing them, and sending them
+// run concurrently.
+func CompressWith(buildCtx io.ReadCloser, c compression.Compression) (io.ReadCloser, error) {
+	if err := compression.Supported(c); err != nil {
+		return nil, err
+	}
 	pipeReader, pipeWriter := io.Pipe()
+	input := newReadAheadReader(buildCtx, readAheadChunks, readAheadChunkSize)
 
 	go func() {
-		compressWriter, err := archive.CompressStream(pipeWriter, archive.Gzip)
+		defer input.Close()
+		compressWriter, err := compression.CompressStream(pipeWriter, c)
 		if err != nil {
 			pipeWriter.CloseWithError(err)
+			return
 		}
-		defer buildCtx.Close()
 
-		if _, err := pools.Copy(compressWriter, buildCtx); err != nil {
+		if _, err := pools.Copy(compressWriter, input); err != nil {
+			compressWriter.Close()
+			pipeWriter.CloseWithError(
+				errors.Wrap(err, "failed to compress context"))
+			return
+		}
+		if err := compressWriter.Close(); err != nil {
 			pipeWriter.CloseWithError(
 				errors.Wrap(err, "failed to compress context"))
-			compressWriter.Close()
 			return
 		}
-		compressWriter.Close()
 		pipeWriter.Close()
 	}()
 
 	return pipeReader, nil
 }
+
+// EstimateContextSize returns the estimated size of the archive of the
+// context directory, without the excluded files. The files are excluded as
+// archive.TarWithOptions excludes them, including the exceptions of the
+// exclusion patterns, such as "!node_modules/keep".
+func EstimateContextSize(srcPath string, excludes []string) (int64, error) {
+	contextRoot, err := getContextRoot(srcPath)
+	if err != nil {
+		return 0, err
+	}
+	pm, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return 0, err
+	}
+	// The archive ends with two empty blocks
+	size := int64(2 * archiveHeaderSize)
+	err = filepath.Walk(contextRoot, func(filePath string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relFilePath, err := filepath.Rel(contextRoot, filePath)
+		if err != nil {
+			return err
+		}
+		if relFilePath == "." {
+			return nil
+		}
+		if skip, err := pm.Matches(relFilePath); err != nil {
+			return err
+		} else if skip {
+			if !f.IsDir() {
+				return nil
+			}
+			// The directory is walked if an exception may match the files
+			// under it
+			if !pm.Exclusions() {
+				return filepath.SkipDir
+			}
+			dirSlash := relFilePath + string(filepath.Separator)
+			for _, pat := range pm.Patterns() {
+				if pat.Exclusion() && strings.HasPrefix(pat.String()+string(filepath.Separator), dirSlash) {
+					return nil
+				}
+			}
+			return filepath.SkipDir
+		}
+		// Each file has a header, and its contents padded to a block
+		size += archiveHeaderSize
+		if f.Mode().IsRegular() {
+			size += (f.Size() + archiveHeaderSize - 1) / archiveHeaderSize * archiveHeaderSize
+		}
+		return nil
+	})
+	return size, err
+}
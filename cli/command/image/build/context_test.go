@@ -297,3 +297,84 @@ func TestIsArchive(t *testing.T) {
 		assert.Check(t, is.Equal(testcase.expected, IsArchive(testcase.header)), testcase.doc)
 	}
 }
+
+func TestListContext(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-test")
+	defer cleanup()
+	createTestTempFile(t, contextDir, DefaultDockerfileName, dockerfileContents, 0777)
+	createTestTempFile(t, contextDir, ".dockerignore", "logs\n", 0777)
+	assert.NilError(t, os.Mkdir(filepath.Join(contextDir, "logs"), 0777))
+	createTestTempFile(t, filepath.Join(contextDir, "logs"), "build.log", "ignored", 0777)
+	assert.NilError(t, os.Mkdir(filepath.Join(contextDir, "src"), 0777))
+	createTestTempFile(t, filepath.Join(contextDir, "src"), "main.go", "package main", 0777)
+
+	excludes, err := ReadDockerignore(contextDir)
+	assert.NilError(t, err)
+	buildCtx, err := archive.TarWithOptions(contextDir, &archive.TarOptions{ExcludePatterns: excludes})
+	assert.NilError(t, err)
+	defer buildCtx.Close()
+
+	entries, err := ListContext(buildCtx)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]ContextEntry{
+		{Path: ".dockerignore", Size: 5},
+		{Path: DefaultDockerfileName, Size: int64(len(dockerfileContents))},
+		{Path: "src/main.go", Size: 12},
+	}, entries))
+}
+
+func TestEstimateContextSize(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-test")
+	defer cleanup()
+	createTestTempFile(t, contextDir, DefaultDockerfileName, dockerfileContents, 0777)
+	createTestTempFile(t, contextDir, ".dockerignore", "*.log\n", 0777)
+	createTestTempFile(t, contextDir, "build.log", strings.Repeat("ignored", 1000), 0777)
+	assert.NilError(t, os.Mkdir(filepath.Join(contextDir, "src"), 0777))
+	createTestTempFile(t, filepath.Join(contextDir, "src"), "main.go", strings.Repeat("x", 1000), 0777)
+
+	excludes, err := ReadDockerignore(contextDir)
+	assert.NilError(t, err)
+	size, err := EstimateContextSize(contextDir, excludes)
+	assert.NilError(t, err)
+	// The ".dockerignore", "Dockerfile", "src", and "src/main.go" headers,
+	// and the contents of the files
+	assert.Check(t, is.Equal(int64(2*512+4*512+512+512+1024), size))
+}
+
+func TestEstimateContextSizeWithExceptions(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-test")
+	defer cleanup()
+	createTestTempFile(t, contextDir, DefaultDockerfileName, dockerfileContents, 0777)
+	assert.NilError(t, os.MkdirAll(filepath.Join(contextDir, "node_modules", "lib"), 0777))
+	createTestTempFile(t, filepath.Join(contextDir, "node_modules"), "keep", strings.Repeat("x", 1000), 0777)
+	createTestTempFile(t, filepath.Join(contextDir, "node_modules", "lib"), "index.js", strings.Repeat("ignored", 1000), 0777)
+
+	excludes := []string{"node_modules", "!node_modules/keep"}
+	size, err := EstimateContextSize(contextDir, excludes)
+	assert.NilError(t, err)
+
+	// The estimate matches the size of the archive which is sent
+	tarStream, err := archive.TarWithOptions(contextDir, &archive.TarOptions{ExcludePatterns: excludes})
+	assert.NilError(t, err)
+	defer tarStream.Close()
+	archived, err := io.Copy(ioutil.Discard, tarStream)
+	assert.NilError(t, err)
+	assert.Check(t, size <= archived && archived-size < 10*512, "estimated %d, archived %d", size, archived)
+
+	// The "Dockerfile" and "node_modules/keep" headers, and the contents of
+	// the files
+	assert.Check(t, is.Equal(int64(2*512+2*512+512+1024), size))
+}
+
+func TestCompressWith(t *testing.T) {
+	contents := strings.Repeat("build context", 200000)
+	compressed, err := CompressWith(ioutil.NopCloser(strings.NewReader(contents)), "gzip")
+	assert.NilError(t, err)
+	defer compressed.Close()
+
+	decompressed, err := archive.DecompressStream(compressed)
+	assert.NilError(t, err)
+	b, err := ioutil.ReadAll(decompressed)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(contents, string(b)))
+}
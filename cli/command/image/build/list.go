@@ -0,0 +1,39 @@
+package build
+
+import (
+	"archive/tar"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ContextEntry is a file of a build context
+type ContextEntry struct {
+	Path     string
+	Size     int64
+	Linkname string
+}
+
+// ListContext returns the files of the build context archive, in the order of
+// the archive, without its directories.
+func ListContext(buildCtx io.Reader) ([]ContextEntry, error) {
+	var entries []ContextEntry
+	tr := tar.NewReader(buildCtx)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the build context")
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		entries = append(entries, ContextEntry{
+			Path:     hdr.Name,
+			Size:     hdr.Size,
+			Linkname: hdr.Linkname,
+		})
+	}
+}
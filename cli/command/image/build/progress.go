@@ -0,0 +1,66 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/progress"
+	units "github.com/docker/go-units"
+)
+
+// transferProgressOutput is a progress.Output printing the progress of the
+// transfer of a build context, with its rate and, when the total size of the
+// context is known, the estimated time remaining.
+type transferProgressOutput struct {
+	mu    sync.Mutex
+	out   io.Writer
+	now   func() time.Time
+	start time.Time
+}
+
+// NewTransferProgressOutput returns a progress.Output printing the progress of
+// the transfer of a build context to out, with its rate and estimated time
+// remaining.
+func NewTransferProgressOutput(out io.Writer) progress.Output {
+	return &transferProgressOutput{out: out, now: time.Now}
+}
+
+// WriteProgress prints the progress on a single line, which is replaced by
+// the next progress.
+func (o *transferProgressOutput) WriteProgress(p progress.Progress) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := o.now()
+	if o.start.IsZero() {
+		o.start = now
+	}
+	line := p.Action + " " + formatTransfer(p.Current, p.Total, now.Sub(o.start), p.LastUpdate)
+	endl := "\r"
+	if p.LastUpdate {
+		endl = "\n"
+	}
+	_, err := fmt.Fprint(o.out, line+endl)
+	return err
+}
+
+// formatTransfer formats the size transferred, the total size when it is
+// known, the rate of the transfer, and the estimated time remaining.
+func formatTransfer(current, total int64, elapsed time.Duration, done bool) string {
+	parts := []string{fmt.Sprintf("%8v", units.HumanSize(float64(current)))}
+	if total > 0 && current <= total && !done {
+		parts[0] += "/" + units.HumanSize(float64(total))
+	}
+	if elapsed < time.Second/10 {
+		return parts[0]
+	}
+	rate := float64(current) / elapsed.Seconds()
+	parts = append(parts, units.HumanSize(rate)+"/s")
+	if !done && total > 0 && current < total && rate > 0 {
+		remaining := time.Duration(float64(total-current) / rate * float64(time.Second))
+		parts = append(parts, "ETA "+remaining.Round(time.Second).String())
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,27 @@
+package build
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestFormatTransfer(t *testing.T) {
+	testCases := []struct {
+		current, total int64
+		elapsed        time.Duration
+		done           bool
+		expected       string
+	}{
+		{current: 1000, expected: "     1kB"},
+		{current: 1000, total: 4000, expected: "     1kB/4kB"},
+		{current: 2000000, elapsed: 2 * time.Second, expected: "     2MB  1MB/s"},
+		{current: 2000000, total: 8000000, elapsed: 2 * time.Second, expected: "     2MB/8MB  1MB/s  ETA 6s"},
+		{current: 8000000, total: 8000000, elapsed: 8 * time.Second, done: true, expected: "     8MB  1MB/s"},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Equal(tc.expected, formatTransfer(tc.current, tc.total, tc.elapsed, tc.done)))
+	}
+}
@@ -0,0 +1,82 @@
+package build
+
+import (
+	"io"
+	"sync"
+)
+
+const (
+	// readAheadChunks is the number of chunks of the build context read
+	// ahead of its compression
+	readAheadChunks = 8
+	// readAheadChunkSize is the size of the chunks of the build context read
+	// ahead of its compression
+	readAheadChunkSize = 1024 * 1024
+)
+
+type readAheadChunk struct {
+	data []byte
+	err  error
+}
+
+// readAheadReader reads chunks of a reader in a goroutine, ahead of the reads
+// of its consumer, using at most a fixed number of chunks of memory.
+type readAheadReader struct {
+	in      io.ReadCloser
+	chunks  chan readAheadChunk
+	done    chan struct{}
+	current []byte
+	err     error
+	once    sync.Once
+}
+
+func newReadAheadReader(in io.ReadCloser, chunks, chunkSize int) io.ReadCloser {
+	r := &readAheadReader{
+		in:     in,
+		chunks: make(chan readAheadChunk, chunks),
+		done:   make(chan struct{}),
+	}
+	go r.readAhead(chunkSize)
+	return r
+}
+
+func (r *readAheadReader) readAhead(chunkSize int) {
+	defer close(r.chunks)
+	for {
+		buf := make([]byte, chunkSize)
+		n, err := io.ReadFull(r.in, buf)
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		select {
+		case r.chunks <- readAheadChunk{data: buf[:n], err: err}:
+		case <-r.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (r *readAheadReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		chunk, ok := <-r.chunks
+		if !ok {
+			return 0, io.EOF
+		}
+		r.current, r.err = chunk.data, chunk.err
+	}
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
+
+// Close stops reading ahead, and closes the reader.
+func (r *readAheadReader) Close() error {
+	r.once.Do(func() { close(r.done) })
+	return r.in.Close()
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/session/secrets/secretsprovider"
 	"github.com/moby/buildkit/session/sshforward/sshprovider"
-	"github.com/moby/buildkit/util/appcontext"
 	"github.com/moby/buildkit/util/progress/progressui"
 	"github.com/pkg/errors"
 	fsutiltypes "github.com/tonistiigi/fsutil/types"
@@ -41,7 +40,7 @@ var errDockerfileConflict = errors.New("ambiguous Dockerfile source: both stdin
 
 //nolint: gocyclo
 func runBuildBuildKit(dockerCli command.Cli, options buildOptions) error {
-	ctx := appcontext.Context()
+	ctx := dockerCli.Context()
 
 	s, err := trySession(dockerCli, options.context, false)
 	if err != nil {
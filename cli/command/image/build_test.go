@@ -9,10 +9,12 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
 	"testing"
 
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
@@ -20,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/moby/buildkit/session/secrets/secretsprovider"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/fs"
 	"gotest.tools/skip"
 )
@@ -59,6 +62,73 @@ func TestRunBuildDockerfileFromStdinWithCompress(t *testing.T) {
 	assert.Equal(t, archive.Gzip, archive.DetectCompression(header))
 }
 
+func TestRunBuildWithZstdCompress(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd is not installed")
+	}
+	var header []byte
+	fakeImageBuild := func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+		b, err := ioutil.ReadAll(context)
+		assert.NilError(t, err)
+		header = b[:4]
+		return types.ImageBuildResponse{Body: ioutil.NopCloser(new(bytes.Buffer))}, nil
+	}
+
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM alpine:3.6\n"),
+		fs.WithFile("foo", "some content"))
+	defer dir.Remove()
+
+	for _, tc := range []struct {
+		apiVersion string
+		expected   []byte
+	}{
+		{apiVersion: "1.41", expected: []byte{0x28, 0xb5, 0x2f, 0xfd}},
+		{apiVersion: "1.40", expected: []byte{0x1f, 0x8b, 0x08, 0x00}},
+	} {
+		cli := test.NewFakeCli(&fakeClient{imageBuildFunc: fakeImageBuild})
+		// The daemon may support a higher API version than the client
+		cli.SetServerInfo(command.ServerInfo{APIVersion: tc.apiVersion})
+
+		options := newBuildOptions()
+		options.compress = true
+		options.context = dir.Path()
+		options.untrusted = true
+		assert.NilError(t, runBuild(cli, options))
+		assert.Check(t, is.DeepEqual(header, tc.expected), tc.apiVersion)
+	}
+}
+
+func TestRunBuildShowContext(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageBuildFunc: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			t.Fatal("the image must not be built")
+			return types.ImageBuildResponse{}, nil
+		},
+	})
+
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM alpine:3.6\n"),
+		fs.WithFile(".dockerignore", "*.log\n"),
+		fs.WithFile("build.log", "ignored"),
+		fs.WithFile("foo", "some content"))
+	defer dir.Remove()
+
+	options := newBuildOptions()
+	options.context = dir.Path()
+	options.showContext = true
+	options.untrusted = true
+	assert.NilError(t, runBuild(cli, options))
+
+	expected := `SIZE   PATH
+6B     .dockerignore
+16B    Dockerfile
+12B    foo
+Total: 34B in 3 files
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
 func TestRunBuildResetsUidAndGidInContext(t *testing.T) {
 	skip.If(t, os.Getuid() != 0, "root is required to chown files")
 	fakeBuild := newFakeBuild()
@@ -94,6 +94,8 @@ func (cli *fakeClient) ImageList(ctx context.Context, options types.ImageListOpt
 	return []types.ImageSummary{{}}, nil
 }
 
+func (cli *fakeClient) NegotiateAPIVersion(ctx context.Context) {}
+
 func (cli *fakeClient) ImageInspectWithRaw(_ context.Context, image string) (types.ImageInspect, []byte, error) {
 	if cli.imageInspectFunc != nil {
 		return cli.imageInspectFunc(image)
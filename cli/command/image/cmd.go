@@ -17,11 +17,13 @@ func NewImageCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		NewBuildCommand(dockerCli),
+		newDiffCommand(dockerCli),
 		NewHistoryCommand(dockerCli),
 		NewImportCommand(dockerCli),
 		NewLoadCommand(dockerCli),
 		NewPullCommand(dockerCli),
 		NewPushCommand(dockerCli),
+		newRetagCommand(dockerCli),
 		NewSaveCommand(dockerCli),
 		NewTagCommand(dockerCli),
 		newListCommand(dockerCli),
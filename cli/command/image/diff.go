@@ -0,0 +1,474 @@
+package image
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Changes of docker image diff
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+const (
+	// whiteoutPrefix is the prefix of the files of a layer removing a file of
+	// the layers below it
+	whiteoutPrefix = ".wh."
+	// whiteoutOpaqueDir is the file of a layer removing the content of its
+	// directory in the layers below it
+	whiteoutOpaqueDir = whiteoutPrefix + whiteoutPrefix + ".opq"
+)
+
+type diffOptions struct {
+	images  [2]string
+	format  string
+	noFiles bool
+}
+
+// imageDiff is the report of docker image diff
+type imageDiff struct {
+	Images [2]diffImage `json:"images"`
+	Layers diffLayers   `json:"layers"`
+	Config []diffChange `json:"config"`
+	Files  []diffChange `json:"files"`
+}
+
+// diffImage is an image compared by docker image diff
+type diffImage struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+// diffLayers are the differences of the layer chains of the images. The
+// layers are shared up to the first layer which differs.
+type diffLayers struct {
+	Shared  int      `json:"shared"`
+	Removed []string `json:"removed"`
+	Added   []string `json:"added"`
+}
+
+// diffChange is a difference of the configuration, or of the files, of the
+// images. A and B are the values in the first and second image, such as the
+// sizes of the files.
+type diffChange struct {
+	Kind   string `json:"kind"`
+	Change string `json:"change"`
+	Name   string `json:"name"`
+	A      string `json:"a,omitempty"`
+	B      string `json:"b,omitempty"`
+}
+
+// newDiffCommand creates a new `docker image diff` command
+func newDiffCommand(dockerCli command.Cli) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] IMAGE IMAGE",
+		Short: "Show the differences between two images",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.images = [2]string{args[0], args[1]}
+			return runDiff(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", `Format the output using the given Go template, or "json"`)
+	flags.BoolVar(&opts.noFiles, "no-files", false, "Do not compare the files of the layers of the images")
+
+	return cmd
+}
+
+func runDiff(dockerCli command.Cli, opts diffOptions) error {
+	ctx := dockerCli.Context()
+
+	var inspects [2]types.ImageInspect
+	var report imageDiff
+	for i, name := range opts.images {
+		inspect, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, name)
+		if err != nil {
+			return err
+		}
+		inspects[i] = inspect
+		report.Images[i] = diffImage{Name: name, ID: inspect.ID, Size: inspect.Size}
+	}
+	report.Layers = compareLayers(inspects[0].RootFS.Layers, inspects[1].RootFS.Layers)
+	report.Config = compareConfigs(inspects[0], inspects[1])
+
+	if !opts.noFiles && inspects[0].ID != inspects[1].ID {
+		files, err := imageFiles(ctx, dockerCli, inspects)
+		if err != nil {
+			return err
+		}
+		report.Files = compareFiles(files[0], files[1])
+	}
+
+	return printImageDiff(dockerCli, report, opts.format)
+}
+
+func compareLayers(a, b []string) diffLayers {
+	var layers diffLayers
+	for layers.Shared < len(a) && layers.Shared < len(b) && a[layers.Shared] == b[layers.Shared] {
+		layers.Shared++
+	}
+	layers.Removed = append([]string{}, a[layers.Shared:]...)
+	layers.Added = append([]string{}, b[layers.Shared:]...)
+	return layers
+}
+
+func compareConfigs(a, b types.ImageInspect) []diffChange {
+	var changes []diffChange
+	configA, configB := imageConfig(a), imageConfig(b)
+	changes = append(changes, compareValues("env", envMap(configA.Env), envMap(configB.Env))...)
+	changes = append(changes, compareValues("label", configA.Labels, configB.Labels)...)
+	for _, v := range []struct {
+		kind string
+		a, b string
+	}{
+		{"entrypoint", jsonArray(configA.Entrypoint), jsonArray(configB.Entrypoint)},
+		{"cmd", jsonArray(configA.Cmd), jsonArray(configB.Cmd)},
+		{"workdir", configA.WorkingDir, configB.WorkingDir},
+		{"user", configA.User, configB.User},
+	} {
+		if v.a != v.b {
+			changes = append(changes, diffChange{Kind: v.kind, Change: diffChanged, A: v.a, B: v.b})
+		}
+	}
+	return changes
+}
+
+func imageConfig(inspect types.ImageInspect) *container.Config {
+	if inspect.Config == nil {
+		return &container.Config{}
+	}
+	return inspect.Config
+}
+
+// compareValues returns the differences of the values of two maps, sorted by
+// key
+func compareValues(kind string, a, b map[string]string) []diffChange {
+	keys := map[string]struct{}{}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []diffChange
+	for _, k := range sorted {
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inA:
+			changes = append(changes, diffChange{Kind: kind, Change: diffAdded, Name: k, B: vb})
+		case !inB:
+			changes = append(changes, diffChange{Kind: kind, Change: diffRemoved, Name: k, A: va})
+		case va != vb:
+			changes = append(changes, diffChange{Kind: kind, Change: diffChanged, Name: k, A: va, B: vb})
+		}
+	}
+	return changes
+}
+
+func envMap(env []string) map[string]string {
+	m := map[string]string{}
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 1 {
+			kv = append(kv, "")
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m
+}
+
+func jsonArray(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
+// imageFile is a file of the filesystem of an image
+type imageFile struct {
+	typeflag byte
+	mode     int64
+	size     int64
+	linkname string
+	digest   string
+}
+
+// imageFiles returns the files of the filesystems of the images, by path. The
+// layers of the images are streamed from an archive of both images, as
+// written by "docker save", in which the layers they share are only once.
+func imageFiles(ctx context.Context, dockerCli command.Cli, inspects [2]types.ImageInspect) ([2]map[string]imageFile, error) {
+	var files [2]map[string]imageFile
+	archive, err := dockerCli.Client().ImageSave(ctx, []string{inspects[0].ID, inspects[1].ID})
+	if err != nil {
+		return files, err
+	}
+	defer archive.Close()
+
+	// The manifest.json file of the archive, which lists the layers of the
+	// images, is usually its last file, so the files of all the layers are
+	// read first
+	var manifests []archiveManifest
+	layers := map[string][]layerFile{}
+	links := map[string]string{}
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, err
+		}
+		name := path.Clean(hdr.Name)
+		switch {
+		case name == archiveManifestFile:
+			if err := json.NewDecoder(tr).Decode(&manifests); err != nil {
+				return files, errors.Wrapf(err, "invalid %s file", archiveManifestFile)
+			}
+		case path.Base(name) != "layer.tar":
+		case hdr.Typeflag == tar.TypeSymlink:
+			// The layers of the archive which are the same as another
+			// layer are a link to it
+			links[name] = path.Join(path.Dir(name), hdr.Linkname)
+		case hdr.Typeflag == tar.TypeReg:
+			layer, err := readLayer(tar.NewReader(tr))
+			if err != nil {
+				return files, errors.Wrapf(err, "invalid layer %s", name)
+			}
+			layers[name] = layer
+		}
+	}
+
+	for i, inspect := range inspects {
+		configName := strings.TrimPrefix(inspect.ID, "sha256:") + ".json"
+		var manifest *archiveManifest
+		for j := range manifests {
+			if path.Clean(manifests[j].Config) == configName {
+				manifest = &manifests[j]
+			}
+		}
+		if manifest == nil {
+			return files, errors.Errorf("invalid archive: missing image %s", inspect.ID)
+		}
+		files[i] = map[string]imageFile{}
+		for _, l := range manifest.Layers {
+			name := path.Clean(l)
+			if target, ok := links[name]; ok {
+				name = target
+			}
+			layer, ok := layers[name]
+			if !ok {
+				return files, errors.Errorf("invalid archive: missing file %s", l)
+			}
+			applyLayer(files[i], layer)
+		}
+	}
+	return files, nil
+}
+
+// layerFile is a file of a layer
+type layerFile struct {
+	path string
+	imageFile
+}
+
+// readLayer returns the files of a layer, in the order of the layer
+func readLayer(tr *tar.Reader) ([]layerFile, error) {
+	var layer []layerFile
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return layer, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		p := path.Clean("/" + hdr.Name)
+		if p == "/" {
+			continue
+		}
+		f := layerFile{path: p, imageFile: imageFile{
+			typeflag: hdr.Typeflag,
+			mode:     hdr.Mode,
+			size:     hdr.Size,
+			linkname: hdr.Linkname,
+		}}
+		if hdr.Typeflag == tar.TypeReg {
+			h := sha256.New()
+			if _, err := io.Copy(h, tr); err != nil {
+				return nil, err
+			}
+			f.digest = hex.EncodeToString(h.Sum(nil))
+		}
+		layer = append(layer, f)
+	}
+}
+
+// applyLayer applies the files of a layer to the files of the layers below
+// it. The whiteout files of the layer remove the files of the layers below
+// it, but not the files of the layer.
+func applyLayer(files map[string]imageFile, layer []layerFile) {
+	for _, f := range layer {
+		dir, base := path.Split(f.path)
+		switch {
+		case base == whiteoutOpaqueDir:
+			removeFiles(files, path.Clean(dir), false)
+		case strings.HasPrefix(base, whiteoutPrefix):
+			removeFiles(files, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)), true)
+		}
+	}
+	for _, f := range layer {
+		if !strings.HasPrefix(path.Base(f.path), whiteoutPrefix) {
+			files[f.path] = f.imageFile
+		}
+	}
+}
+
+// removeFiles removes the files in the directory p, and p itself if self is
+// set.
+func removeFiles(files map[string]imageFile, p string, self bool) {
+	if self {
+		delete(files, p)
+	}
+	prefix := strings.TrimSuffix(p, "/") + "/"
+	for name := range files {
+		if strings.HasPrefix(name, prefix) {
+			delete(files, name)
+		}
+	}
+}
+
+// compareFiles returns the differences of the files of the images, sorted by
+// path. The directories of both images are not compared, nor are the
+// modification times of the files, which change on every build.
+func compareFiles(a, b map[string]imageFile) []diffChange {
+	var paths []string
+	for p := range a {
+		paths = append(paths, p)
+	}
+	for p := range b {
+		if _, ok := a[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []diffChange
+	for _, p := range paths {
+		fa, inA := a[p]
+		fb, inB := b[p]
+		switch {
+		case !inA:
+			changes = append(changes, diffChange{Kind: "file", Change: diffAdded, Name: p, B: fileSize(fb)})
+		case !inB:
+			changes = append(changes, diffChange{Kind: "file", Change: diffRemoved, Name: p, A: fileSize(fa)})
+		case fa.typeflag == tar.TypeDir && fb.typeflag == tar.TypeDir:
+		case fa != fb:
+			changes = append(changes, diffChange{Kind: "file", Change: diffChanged, Name: p, A: fileSize(fa), B: fileSize(fb)})
+		}
+	}
+	return changes
+}
+
+func fileSize(f imageFile) string {
+	if f.typeflag != tar.TypeReg {
+		return ""
+	}
+	return units.HumanSizeWithPrecision(float64(f.size), 3)
+}
+
+func printImageDiff(dockerCli command.Cli, report imageDiff, format string) error {
+	switch format {
+	case "":
+		w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "IMAGE\tID\tSIZE")
+		for _, img := range report.Images {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", img.Name, stringid.TruncateID(img.ID), units.HumanSizeWithPrecision(float64(img.Size), 3))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(dockerCli.Out(), "\nSize: %s\n", sizeDelta(report.Images[1].Size-report.Images[0].Size))
+		fmt.Fprintf(dockerCli.Out(), "Layers: %d shared, %d removed, %d added\n", report.Layers.Shared, len(report.Layers.Removed), len(report.Layers.Added))
+
+		var changes []diffChange
+		for _, l := range report.Layers.Removed {
+			changes = append(changes, diffChange{Kind: "layer", Change: diffRemoved, Name: l})
+		}
+		for _, l := range report.Layers.Added {
+			changes = append(changes, diffChange{Kind: "layer", Change: diffAdded, Name: l})
+		}
+		changes = append(changes, report.Config...)
+		changes = append(changes, report.Files...)
+		if len(changes) == 0 {
+			return nil
+		}
+		fmt.Fprintln(dockerCli.Out())
+		w = tabwriter.NewWriter(dockerCli.Out(), 0, 4, 3, ' ', 0)
+		fmt.Fprintln(w, "KIND\tCHANGE\tNAME\tA\tB")
+		for _, c := range changes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Kind, c.Change, c.Name, c.A, c.B)
+		}
+		return w.Flush()
+	case "json":
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(report)
+	default:
+		tmpl, err := templates.Parse(format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "Template parsing error: " + err.Error()}
+		}
+		if err := tmpl.Execute(dockerCli.Out(), report); err != nil {
+			return err
+		}
+		fmt.Fprintln(dockerCli.Out())
+		return nil
+	}
+}
+
+// sizeDelta returns the difference of the sizes of the images, such as
+// "+1.5MB"
+func sizeDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + units.HumanSizeWithPrecision(float64(delta), 3)
+	case delta < 0:
+		return "-" + units.HumanSizeWithPrecision(float64(-delta), 3)
+	}
+	return "no change"
+}
@@ -0,0 +1,166 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+type testFile struct {
+	name, content string
+	typeflag      byte
+}
+
+func newTestLayer(t *testing.T, files ...testFile) string {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, f := range files {
+		typeflag := f.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: typeflag}))
+		_, err := tw.Write([]byte(f.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.String()
+}
+
+// newTestDiffArchive returns an archive of two images, as written by
+// "docker save", which share their first layer
+func newTestDiffArchive(t *testing.T) []byte {
+	t.Helper()
+	manifest, err := json.Marshal([]archiveManifest{
+		{Config: "aaaa.json", Layers: []string{"base/layer.tar", "a/layer.tar"}},
+		{Config: "bbbb.json", Layers: []string{"base/layer.tar", "b/layer.tar", "dup/layer.tar"}},
+	})
+	assert.NilError(t, err)
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, f := range []testFile{
+		{name: "base/layer.tar", content: newTestLayer(t,
+			testFile{name: "bin/", typeflag: tar.TypeDir},
+			testFile{name: "bin/sh", content: "sh"},
+			testFile{name: "etc/", typeflag: tar.TypeDir},
+			testFile{name: "etc/hosts", content: "localhost"},
+			testFile{name: "tmp/", typeflag: tar.TypeDir},
+			testFile{name: "tmp/cache", content: "cache"},
+			testFile{name: "var/", typeflag: tar.TypeDir},
+			testFile{name: "var/lib/", typeflag: tar.TypeDir},
+			testFile{name: "var/lib/db", content: "db"},
+		)},
+		{name: "a/layer.tar", content: newTestLayer(t,
+			testFile{name: "etc/motd", content: "hello"},
+		)},
+		{name: "b/layer.tar", content: newTestLayer(t,
+			testFile{name: "etc/hosts", content: "localhost example.com"},
+			testFile{name: "tmp/.wh.cache"},
+			testFile{name: "var/lib/.wh..wh..opq"},
+			testFile{name: "var/lib/db2", content: "db"},
+		)},
+		{name: "dup/layer.tar", content: "../a/layer.tar", typeflag: tar.TypeSymlink},
+		{name: "aaaa.json", content: "{}"},
+		{name: "bbbb.json", content: "{}"},
+		{name: "manifest.json", content: string(manifest)},
+	} {
+		hdr := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}
+		if f.typeflag == tar.TypeSymlink {
+			hdr = &tar.Header{Name: f.name, Mode: 0777, Linkname: f.content, Typeflag: tar.TypeSymlink}
+		}
+		assert.NilError(t, tw.WriteHeader(hdr))
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(f.content))
+			assert.NilError(t, err)
+		}
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func newTestDiffClient(t *testing.T) *fakeClient {
+	images := map[string]types.ImageInspect{
+		"app:1": {
+			ID:     "sha256:aaaa",
+			Size:   1000,
+			RootFS: types.RootFS{Layers: []string{"sha256:base", "sha256:a"}},
+			Config: &container.Config{
+				Env:        []string{"PATH=/bin", "DEBUG=1"},
+				Labels:     map[string]string{"version": "1"},
+				Entrypoint: []string{"/bin/sh"},
+			},
+		},
+		"app:2": {
+			ID:     "sha256:bbbb",
+			Size:   1500,
+			RootFS: types.RootFS{Layers: []string{"sha256:base", "sha256:b", "sha256:a"}},
+			Config: &container.Config{
+				Env:        []string{"PATH=/usr/bin:/bin"},
+				Labels:     map[string]string{"version": "2", "maintainer": "me"},
+				Entrypoint: []string{"/bin/sh"},
+				User:       "app",
+			},
+		},
+	}
+	return &fakeClient{
+		imageInspectFunc: func(image string) (types.ImageInspect, []byte, error) {
+			return images[image], nil, nil
+		},
+		imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			assert.Check(t, is.DeepEqual(images, []string{"sha256:aaaa", "sha256:bbbb"}))
+			return ioutil.NopCloser(bytes.NewReader(newTestDiffArchive(t))), nil
+		},
+	}
+}
+
+func TestNewDiffCommand(t *testing.T) {
+	cli := test.NewFakeCli(newTestDiffClient(t))
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"app:1", "app:2"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "diff-command-success.table.golden")
+}
+
+func TestNewDiffCommandJSON(t *testing.T) {
+	cli := test.NewFakeCli(newTestDiffClient(t))
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"--format", "json", "app:1", "app:2"})
+	assert.NilError(t, cmd.Execute())
+
+	var report imageDiff
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &report))
+	assert.Check(t, is.Equal(report.Layers.Shared, 1))
+	assert.Check(t, is.DeepEqual(report.Layers.Removed, []string{"sha256:a"}))
+	assert.Check(t, is.DeepEqual(report.Layers.Added, []string{"sha256:b", "sha256:a"}))
+	assert.Check(t, is.DeepEqual(report.Files, []diffChange{
+		{Kind: "file", Change: diffChanged, Name: "/etc/hosts", A: "9B", B: "21B"},
+		{Kind: "file", Change: diffRemoved, Name: "/tmp/cache", A: "5B"},
+		{Kind: "file", Change: diffRemoved, Name: "/var/lib/db", A: "2B"},
+		{Kind: "file", Change: diffAdded, Name: "/var/lib/db2", B: "2B"},
+	}))
+}
+
+func TestNewDiffCommandNoFiles(t *testing.T) {
+	client := newTestDiffClient(t)
+	client.imageSaveFunc = func(images []string) (io.ReadCloser, error) {
+		t.Fatal("the images are saved with --no-files")
+		return nil, nil
+	}
+	cli := test.NewFakeCli(client)
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"--no-files", "--format", "{{len .Config}} {{len .Files}}", "app:1", "app:2"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "5 0\n"))
+}
@@ -41,7 +41,7 @@ func NewHistoryFormat(source string, quiet bool, human bool) formatter.Format {
 func HistoryWrite(ctx formatter.Context, human bool, histories []image.HistoryResponseItem) error {
 	render := func(format func(subContext formatter.SubContext) error) error {
 		for _, history := range histories {
-			historyCtx := &historyContext{trunc: ctx.Trunc, h: history, human: human}
+			historyCtx := &historyContext{trunc: ctx.Trunc, widths: ctx.Widths, h: history, human: human}
 			if err := format(historyCtx); err != nil {
 				return err
 			}
@@ -62,9 +62,10 @@ func HistoryWrite(ctx formatter.Context, human bool, histories []image.HistoryRe
 
 type historyContext struct {
 	formatter.HeaderContext
-	trunc bool
-	human bool
-	h     image.HistoryResponseItem
+	trunc  bool
+	widths map[string]int
+	human  bool
+	h      image.HistoryResponseItem
 }
 
 func (c *historyContext) MarshalJSON() ([]byte, error) {
@@ -93,7 +94,7 @@ func (c *historyContext) CreatedSince() string {
 func (c *historyContext) CreatedBy() string {
 	createdBy := strings.Replace(c.h.CreatedBy, "\t", " ", -1)
 	if c.trunc {
-		return formatter.Ellipsis(createdBy, 45)
+		return formatter.TruncateColumn(createdBy, c.widths, "CreatedBy", 45)
 	}
 	return createdBy
 }
@@ -0,0 +1,75 @@
+package image
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/pkg/stringid"
+)
+
+const (
+	defaultRetagTableFormat = "table {{.Source}}\t{{.Target}}\t{{.ID}}"
+
+	retagSourceHeader = "SOURCE"
+	retagTargetHeader = "TARGET"
+	retagIDHeader     = "IMAGE ID"
+)
+
+// retagEntry is a tag of an image matching the rule of docker image retag,
+// and the tag it is mapped to
+type retagEntry struct {
+	Source string
+	Target string
+	ID     string
+}
+
+// NewRetagFormat returns a format for rendering a retagContext
+func NewRetagFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultRetagTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// RetagWrite writes the tags of docker image retag
+func RetagWrite(ctx formatter.Context, entries []retagEntry) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, entry := range entries {
+			if err := format(&retagContext{trunc: ctx.Trunc, e: entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	retagCtx := &retagContext{}
+	retagCtx.Header = formatter.SubHeaderContext{
+		"Source": retagSourceHeader,
+		"Target": retagTargetHeader,
+		"ID":     retagIDHeader,
+	}
+	return ctx.Write(retagCtx, render)
+}
+
+type retagContext struct {
+	formatter.HeaderContext
+	trunc bool
+	e     retagEntry
+}
+
+func (c *retagContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *retagContext) Source() string {
+	return c.e.Source
+}
+
+func (c *retagContext) Target() string {
+	return c.e.Target
+}
+
+func (c *retagContext) ID() string {
+	if c.trunc {
+		return stringid.TruncateID(c.e.ID)
+	}
+	return c.e.ID
+}
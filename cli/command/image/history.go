@@ -55,10 +55,12 @@ func runHistory(dockerCli command.Cli, opts historyOptions) error {
 		format = formatter.TableFormatKey
 	}
 
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	historyCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewHistoryFormat(format, opts.quiet, opts.human),
-		Trunc:  !opts.noTrunc,
+		Trunc:  truncation.Trunc(opts.noTrunc),
+		Widths: truncation.Widths("history"),
 	}
 	return HistoryWrite(historyCtx, opts.human, history)
 }
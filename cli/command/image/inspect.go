@@ -35,7 +35,7 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 
 func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	client := dockerCli.Client()
-	ctx := context.Background()
+	ctx := command.WithOfflineCache(context.Background())
 
 	getRefFunc := func(ref string) (interface{}, []byte, error) {
 		return client.ImageInspectWithRaw(ctx, ref)
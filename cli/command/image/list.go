@@ -36,6 +36,7 @@ func NewImagesCommand(dockerCli command.Cli) *cobra.Command {
 			}
 			return runImages(dockerCli, options)
 		},
+		Annotations: map[string]string{"multiContext": ""},
 	}
 
 	flags := cmd.Flags()
@@ -58,7 +59,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runImages(dockerCli command.Cli, options imagesOptions) error {
-	ctx := context.Background()
+	ctx := command.WithOfflineCache(context.Background())
 
 	filters := options.filter.Value()
 	if options.matchName != "" {
@@ -70,11 +71,6 @@ func runImages(dockerCli command.Cli, options imagesOptions) error {
 		Filters: filters,
 	}
 
-	images, err := dockerCli.Client().ImageList(ctx, listOptions)
-	if err != nil {
-		return err
-	}
-
 	format := options.format
 	if len(format) == 0 {
 		if len(dockerCli.ConfigFile().ImagesFormat) > 0 && !options.quiet {
@@ -84,13 +80,41 @@ func runImages(dockerCli command.Cli, options imagesOptions) error {
 		}
 	}
 
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	imageCtx := formatter.ImageContext{
 		Context: formatter.Context{
 			Output: dockerCli.Out(),
 			Format: formatter.NewImageFormat(format, options.quiet, options.showDigests),
-			Trunc:  !options.noTrunc,
+			Trunc:  truncation.Trunc(options.noTrunc),
 		},
 		Digest: options.showDigests,
 	}
+
+	if clients := dockerCli.SelectedContextClients(); len(clients) > 0 {
+		return runImagesContexts(ctx, clients, imageCtx, listOptions)
+	}
+
+	images, err := dockerCli.Client().ImageList(ctx, listOptions)
+	if err != nil {
+		return err
+	}
 	return formatter.ImageWrite(imageCtx, images)
 }
+
+// runImagesContexts lists the images of the daemons of several contexts
+// concurrently. The images of the contexts whose daemon can be reached are
+// written before the errors of the others are returned.
+func runImagesContexts(ctx context.Context, clients []command.ContextAPIClient, imageCtx formatter.ImageContext, listOptions types.ImageListOptions) error {
+	results := make([]formatter.ContextImages, len(clients))
+	listErr := command.RunInContexts(ctx, clients, func(ctx context.Context, i int, c command.ContextAPIClient) error {
+		images, err := c.Client.ImageList(ctx, listOptions)
+		results[i] = formatter.ContextImages{Context: c.Name, Images: images}
+		return err
+	})
+
+	imageCtx.Format = formatter.WithContextColumn(imageCtx.Format)
+	if err := formatter.ImageWriteContexts(imageCtx, results); err != nil {
+		return err
+	}
+	return listErr
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
@@ -96,3 +97,24 @@ func TestNewListCommandAlias(t *testing.T) {
 	assert.Check(t, cmd.HasAlias("list"))
 	assert.Check(t, !cmd.HasAlias("other"))
 }
+
+func TestNewImagesCommandContexts(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetSelectedContextClients([]command.ContextAPIClient{
+		{Name: "default", Current: true, Client: &fakeClient{
+			imageListFunc: func(options types.ImageListOptions) ([]types.ImageSummary, error) {
+				return []types.ImageSummary{{ID: "sha256:abcdef0123456789", RepoTags: []string{"busybox:latest"}}}, nil
+			},
+		}},
+		{Name: "prod", Client: &fakeClient{
+			imageListFunc: func(options types.ImageListOptions) ([]types.ImageSummary, error) {
+				return []types.ImageSummary{{ID: "sha256:0123456789abcdef", RepoTags: []string{"nginx:1.17", "nginx:latest"}}}, nil
+			},
+		}},
+	})
+	cmd := NewImagesCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--format", "table {{.Repository}}:{{.Tag}}\t{{.ID}}"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "list-command-contexts.golden")
+}
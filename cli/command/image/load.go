@@ -3,6 +3,7 @@ package image
 import (
 	"context"
 	"io"
+	"os"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -32,7 +33,7 @@ func NewLoadCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 
-	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, instead of STDIN")
+	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, or OCI layout directory, instead of STDIN")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the load output")
 
 	return cmd
@@ -42,14 +43,26 @@ func runLoad(dockerCli command.Cli, opts loadOptions) error {
 
 	var input io.Reader = dockerCli.In()
 	if opts.input != "" {
-		// We use system.OpenSequential to use sequential file access on Windows, avoiding
-		// depleting the standby list un-necessarily. On Linux, this equates to a regular os.Open.
-		file, err := system.OpenSequential(opts.input)
-		if err != nil {
-			return err
+		if info, err := os.Stat(opts.input); err == nil && info.IsDir() {
+			if !isOCILayout(opts.input) {
+				return errors.Errorf("%s is a directory, but not an OCI layout", opts.input)
+			}
+			pr, pw := io.Pipe()
+			defer pr.Close()
+			go func() {
+				pw.CloseWithError(writeOCILayoutArchive(pw, opts.input))
+			}()
+			input = pr
+		} else {
+			// We use system.OpenSequential to use sequential file access on Windows, avoiding
+			// depleting the standby list un-necessarily. On Linux, this equates to a regular os.Open.
+			file, err := system.OpenSequential(opts.input)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			input = file
 		}
-		defer file.Close()
-		input = file
 	}
 
 	// To avoid getting stuck, verify that a tar file is given either in
@@ -0,0 +1,356 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Formats of the images saved by "docker save"
+const (
+	formatDockerArchive = "docker-archive"
+	formatOCILayout     = "oci-layout"
+)
+
+const (
+	// archiveManifestFile is the file of an archive of images, as written by
+	// "docker save", listing its images
+	archiveManifestFile = "manifest.json"
+	// ociIndexFile is the file of an OCI layout listing its images
+	ociIndexFile = "index.json"
+	// annotationImageName is the annotation of the descriptors of the index of
+	// an OCI layout with the full name of the image, as set by containerd
+	annotationImageName = "io.containerd.image.name"
+)
+
+// archiveManifest is an image of the manifest.json file of an archive of
+// images, as written by "docker save"
+type archiveManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// writeOCILayout writes the images of an archive of images, as written by
+// "docker save", to an OCI layout in dir. The images are referenced by the
+// index of the layout with their tags, and their layers are not compressed.
+func writeOCILayout(dir string, archive io.Reader) error {
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+
+	// The files of the archive are written as blobs, as its manifest.json
+	// file, which refers to them, is usually its last file
+	var manifests []archiveManifest
+	blobs := map[string]ocispec.Descriptor{}
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if path.Clean(hdr.Name) == archiveManifestFile {
+			if err := json.NewDecoder(tr).Decode(&manifests); err != nil {
+				return errors.Wrapf(err, "invalid %s file", archiveManifestFile)
+			}
+			continue
+		}
+		desc, err := writeBlob(dir, tr)
+		if err != nil {
+			return err
+		}
+		blobs[path.Clean(hdr.Name)] = desc
+	}
+
+	index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, Manifests: []ocispec.Descriptor{}}
+	used := map[digest.Digest]bool{}
+	for _, m := range manifests {
+		config, ok := blobs[path.Clean(m.Config)]
+		if !ok {
+			return errors.Errorf("invalid archive: missing file %s", m.Config)
+		}
+		config.MediaType = ocispec.MediaTypeImageConfig
+		manifest := ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			Config:    config,
+			Layers:    []ocispec.Descriptor{},
+		}
+		used[config.Digest] = true
+		for _, l := range m.Layers {
+			layer, ok := blobs[path.Clean(l)]
+			if !ok {
+				return errors.Errorf("invalid archive: missing file %s", l)
+			}
+			layer.MediaType = ocispec.MediaTypeImageLayer
+			manifest.Layers = append(manifest.Layers, layer)
+			used[layer.Digest] = true
+		}
+
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		desc, err := writeBlob(dir, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		desc.MediaType = ocispec.MediaTypeImageManifest
+		used[desc.Digest] = true
+		if len(m.RepoTags) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, repoTag := range m.RepoTags {
+			annotations, err := imageAnnotations(repoTag)
+			if err != nil {
+				return err
+			}
+			tagged := desc
+			tagged.Annotations = annotations
+			index.Manifests = append(index.Manifests, tagged)
+		}
+	}
+
+	// Remove the blobs of the other files of the archive, such as the legacy
+	// metadata of the layers
+	for _, desc := range blobs {
+		if !used[desc.Digest] {
+			if err := os.Remove(blobPath(dir, desc.Digest)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(dir, ociIndexFile), index); err != nil {
+		return err
+	}
+	return writeJSONFile(filepath.Join(dir, ocispec.ImageLayoutFile), ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+}
+
+// imageAnnotations returns the annotations of the descriptor of an image in
+// the index of an OCI layout, from its tag in an archive, such as
+// "alpine:latest"
+func imageAnnotations(repoTag string) (map[string]string, error) {
+	ref, err := reference.ParseNormalizedNamed(repoTag)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid archive: invalid tag %s", repoTag)
+	}
+	ref = reference.TagNameOnly(ref)
+	annotations := map[string]string{annotationImageName: ref.String()}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		annotations[ocispec.AnnotationRefName] = tagged.Tag()
+	}
+	return annotations, nil
+}
+
+// isOCILayout returns whether dir is an OCI layout
+func isOCILayout(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ocispec.ImageLayoutFile))
+	return err == nil
+}
+
+// writeOCILayoutArchive writes the images of the OCI layout in dir to an
+// archive of images, as read by "docker load". The images are tagged with
+// their full name in the index of the layout, if any, or with their
+// reference name if it is a full name, such as "alpine:latest".
+func writeOCILayoutArchive(w io.Writer, dir string) error {
+	var layout ocispec.ImageLayout
+	if err := readJSONFile(filepath.Join(dir, ocispec.ImageLayoutFile), &layout); err != nil {
+		return errors.Wrap(err, "invalid OCI layout")
+	}
+	if layout.Version != ocispec.ImageLayoutVersion {
+		return errors.Errorf("unsupported OCI layout version %q", layout.Version)
+	}
+	var index ocispec.Index
+	if err := readJSONFile(filepath.Join(dir, ociIndexFile), &index); err != nil {
+		return errors.Wrap(err, "invalid OCI layout")
+	}
+
+	var manifests []*archiveManifest
+	images := map[digest.Digest]*archiveManifest{}
+	// files are the descriptors of the blobs of the files of the archive
+	files := map[string]ocispec.Descriptor{}
+	for _, desc := range index.Manifests {
+		switch desc.MediaType {
+		case ocispec.MediaTypeImageManifest, schema2.MediaTypeManifest:
+		case ocispec.MediaTypeImageIndex, manifestlist.MediaTypeManifestList:
+			return errors.Errorf("image indexes are not supported: %s is the index of the images of several platforms", desc.Digest)
+		default:
+			return errors.Errorf("unsupported media type %s of %s", desc.MediaType, desc.Digest)
+		}
+
+		image, ok := images[desc.Digest]
+		if !ok {
+			var manifest ocispec.Manifest
+			if err := readBlob(dir, desc.Digest, &manifest); err != nil {
+				return err
+			}
+			image = &archiveManifest{Config: manifest.Config.Digest.Hex() + ".json"}
+			for _, layer := range manifest.Layers {
+				switch layer.MediaType {
+				case ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerGzip, schema2.MediaTypeLayer:
+				default:
+					return errors.Errorf("unsupported media type %s of layer %s", layer.MediaType, layer.Digest)
+				}
+				name := path.Join(layer.Digest.Hex(), "layer.tar")
+				image.Layers = append(image.Layers, name)
+				files[name] = layer
+			}
+			files[image.Config] = manifest.Config
+			images[desc.Digest] = image
+			manifests = append(manifests, image)
+		}
+		if name := imageName(desc.Annotations); name != "" {
+			image.RepoTags = append(image.RepoTags, name)
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	written := map[string]bool{}
+	for _, image := range manifests {
+		for _, name := range append([]string{image.Config}, image.Layers...) {
+			if written[name] {
+				continue
+			}
+			if err := copyBlobToArchive(tw, dir, name, files[name].Digest); err != nil {
+				return err
+			}
+			written[name] = true
+		}
+	}
+	data, err := json.Marshal(manifests)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: archiveManifestFile, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// imageName returns the full name of an image from the annotations of its
+// descriptor in the index of an OCI layout, or an empty string if it has
+// none. Reference names which are only a tag, such as "latest", are not a
+// full name.
+func imageName(annotations map[string]string) string {
+	for _, name := range []string{annotations[annotationImageName], annotations[ocispec.AnnotationRefName]} {
+		if !strings.ContainsAny(name, ":/") {
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			continue
+		}
+		if _, ok := ref.(reference.Canonical); ok {
+			continue
+		}
+		return reference.FamiliarString(reference.TagNameOnly(ref))
+	}
+	return ""
+}
+
+// blobPath returns the path of a blob of the OCI layout in dir
+func blobPath(dir string, dgst digest.Digest) string {
+	return filepath.Join(dir, "blobs", string(dgst.Algorithm()), dgst.Hex())
+}
+
+// writeBlob writes a blob to the OCI layout in dir, and returns its
+// descriptor, without media type
+func writeBlob(dir string, r io.Reader) (ocispec.Descriptor, error) {
+	f, err := ioutil.TempFile(filepath.Join(dir, "blobs", string(digest.Canonical)), ".tmp-")
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer os.Remove(f.Name())
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(io.MultiWriter(f, digester.Hash()), r)
+	if err != nil {
+		f.Close()
+		return ocispec.Descriptor{}, err
+	}
+	if err := f.Close(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{Digest: digester.Digest(), Size: size}
+	if err := os.Rename(f.Name(), blobPath(dir, desc.Digest)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
+
+// readBlob decodes a JSON blob of the OCI layout in dir, after verifying its
+// digest
+func readBlob(dir string, dgst digest.Digest, v interface{}) error {
+	if err := dgst.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid digest %s", dgst)
+	}
+	data, err := ioutil.ReadFile(blobPath(dir, dgst))
+	if err != nil {
+		return err
+	}
+	verifier := dgst.Verifier()
+	if _, err := verifier.Write(data); err != nil || !verifier.Verified() {
+		return errors.Errorf("invalid OCI layout: the digest of blob %s does not match its content", dgst)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// copyBlobToArchive copies a blob of the OCI layout in dir to the file with
+// the given name in an archive
+func copyBlobToArchive(tw *tar.Writer, dir, name string, dgst digest.Digest) error {
+	if err := dgst.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid digest %s", dgst)
+	}
+	f, err := os.Open(blobPath(dir, dgst))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func readJSONFile(filename string, v interface{}) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func writeJSONFile(filename string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
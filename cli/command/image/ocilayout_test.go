@@ -0,0 +1,226 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+const (
+	testImageConfig = `{"architecture":"amd64","os":"linux","rootfs":{"type":"layers","diff_ids":["sha256:0"]}}`
+	testImageLayer  = "layer content"
+)
+
+// newTestArchive returns an archive of images, as written by "docker save",
+// with an image with the given tags
+func newTestArchive(t *testing.T, repoTags ...string) []byte {
+	t.Helper()
+	configName := digest.FromString(testImageConfig).Hex() + ".json"
+	manifest, err := json.Marshal([]archiveManifest{{Config: configName, RepoTags: repoTags, Layers: []string{"0123/layer.tar"}}})
+	assert.NilError(t, err)
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, f := range []struct{ name, content string }{
+		{"0123/VERSION", "1.0"},
+		{"0123/json", `{"id":"0123"}`},
+		{"0123/layer.tar", testImageLayer},
+		{configName, testImageConfig},
+		{"manifest.json", string(manifest)},
+		{"repositories", `{}`},
+	} {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(f.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+// readArchive returns the content of the files of an archive, by name
+func readArchive(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NilError(t, err)
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = string(content)
+	}
+}
+
+func TestSaveLoadOCILayout(t *testing.T) {
+	dir := fs.NewDir(t, "oci-layout")
+	defer dir.Remove()
+	output := filepath.Join(dir.Path(), "alpine")
+
+	cli := test.NewFakeCli(&fakeClient{
+		imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(newTestArchive(t, "alpine:latest", "registry.example.com/alpine:3.10"))), nil
+		},
+	})
+	cmd := NewSaveCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--format", "oci-layout", "-o", output, "alpine:latest", "registry.example.com/alpine:3.10"})
+	assert.NilError(t, cmd.Execute())
+
+	var layout ocispec.ImageLayout
+	assert.NilError(t, readJSONFile(filepath.Join(output, "oci-layout"), &layout))
+	assert.Check(t, is.Equal(layout.Version, "1.0.0"))
+	var index ocispec.Index
+	assert.NilError(t, readJSONFile(filepath.Join(output, "index.json"), &index))
+	assert.Assert(t, is.Len(index.Manifests, 2))
+	assert.Check(t, is.DeepEqual(index.Manifests[0].Annotations, map[string]string{
+		"io.containerd.image.name":          "docker.io/library/alpine:latest",
+		"org.opencontainers.image.ref.name": "latest",
+	}))
+	assert.Check(t, is.Equal(index.Manifests[1].Annotations["org.opencontainers.image.ref.name"], "3.10"))
+	assert.Check(t, is.Equal(index.Manifests[0].Digest, index.Manifests[1].Digest))
+
+	var manifest ocispec.Manifest
+	assert.NilError(t, readBlob(output, index.Manifests[0].Digest, &manifest))
+	assert.Check(t, is.Equal(manifest.Config.Digest, digest.FromString(testImageConfig)))
+	assert.Check(t, is.Equal(manifest.Config.MediaType, ocispec.MediaTypeImageConfig))
+	assert.Assert(t, is.Len(manifest.Layers, 1))
+	assert.Check(t, is.Equal(manifest.Layers[0].Digest, digest.FromString(testImageLayer)))
+	assert.Check(t, is.Equal(manifest.Layers[0].MediaType, ocispec.MediaTypeImageLayer))
+	assert.Check(t, is.Equal(manifest.Layers[0].Size, int64(len(testImageLayer))))
+
+	// Only the blobs of the images are kept
+	blobs, err := ioutil.ReadDir(filepath.Join(output, "blobs", "sha256"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(blobs, 3))
+
+	var loaded map[string]string
+	cli = test.NewFakeCli(&fakeClient{
+		imageLoadFunc: func(input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+			loaded = readArchive(t, input)
+			return types.ImageLoadResponse{Body: ioutil.NopCloser(strings.NewReader("Success"))}, nil
+		},
+	})
+	cmd = NewLoadCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--input", output})
+	assert.NilError(t, cmd.Execute())
+
+	configName := digest.FromString(testImageConfig).Hex() + ".json"
+	layerName := digest.FromString(testImageLayer).Hex() + "/layer.tar"
+	assert.Check(t, is.Equal(loaded[configName], testImageConfig))
+	assert.Check(t, is.Equal(loaded[layerName], testImageLayer))
+	var manifests []archiveManifest
+	assert.NilError(t, json.Unmarshal([]byte(loaded["manifest.json"]), &manifests))
+	assert.Check(t, is.DeepEqual(manifests, []archiveManifest{{
+		Config:   configName,
+		RepoTags: []string{"alpine:latest", "registry.example.com/alpine:3.10"},
+		Layers:   []string{layerName},
+	}}))
+}
+
+func TestSaveOCILayoutErrors(t *testing.T) {
+	dir := fs.NewDir(t, "oci-layout", fs.WithFile("file", ""), fs.WithDir("full", fs.WithFile("file", "")))
+	defer dir.Remove()
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--format", "oci-layout", "alpine"},
+			expectedError: "the -o flag is required to save the images to an OCI layout directory",
+		},
+		{
+			args:          []string{"--format", "oci-layout", "--include-signatures", "-o", dir.Join("out"), "alpine"},
+			expectedError: "the --include-signatures flag is not supported with the oci-layout format",
+		},
+		{
+			args:          []string{"--format", "oci-layout", "-o", dir.Join("full"), "alpine"},
+			expectedError: "is not empty",
+		},
+		{
+			args:          []string{"--format", "oci-layout", "-o", dir.Join("file"), "alpine"},
+			expectedError: "must be a directory",
+		},
+		{
+			args:          []string{"--format", "tar", "alpine"},
+			expectedError: `invalid format "tar": must be docker-archive or oci-layout`,
+		},
+	}
+	for _, tc := range testCases {
+		cmd := NewSaveCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs(tc.args)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+	}
+}
+
+func TestLoadOCILayoutErrors(t *testing.T) {
+	index := `{"schemaVersion":2,"manifests":[{"mediaType":"application/vnd.oci.image.index.v1+json","digest":"sha256:` + strings.Repeat("0", 64) + `","size":2}]}`
+	dir := fs.NewDir(t, "oci-layout",
+		fs.WithDir("empty"),
+		fs.WithDir("index",
+			fs.WithFile("oci-layout", `{"imageLayoutVersion":"1.0.0"}`),
+			fs.WithFile("index.json", index),
+		),
+	)
+	defer dir.Remove()
+
+	testCases := []struct {
+		input         string
+		expectedError string
+	}{
+		{
+			input:         dir.Join("empty"),
+			expectedError: "is a directory, but not an OCI layout",
+		},
+		{
+			input:         dir.Join("index"),
+			expectedError: "image indexes are not supported",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{
+			imageLoadFunc: func(input io.Reader, quiet bool) (types.ImageLoadResponse, error) {
+				_, err := ioutil.ReadAll(input)
+				return types.ImageLoadResponse{}, err
+			},
+		})
+		cmd := NewLoadCommand(cli)
+		cmd.SetOutput(ioutil.Discard)
+		cmd.SetArgs([]string{"--input", tc.input})
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+	}
+}
+
+func TestImageName(t *testing.T) {
+	testCases := []struct {
+		annotations map[string]string
+		expected    string
+	}{
+		{annotations: nil, expected: ""},
+		{annotations: map[string]string{"org.opencontainers.image.ref.name": "latest"}, expected: ""},
+		{annotations: map[string]string{"org.opencontainers.image.ref.name": "alpine:3.10"}, expected: "alpine:3.10"},
+		{annotations: map[string]string{"org.opencontainers.image.ref.name": "docker.io/library/alpine"}, expected: "alpine:latest"},
+		{annotations: map[string]string{"org.opencontainers.image.ref.name": "latest", "io.containerd.image.name": "registry.example.com/app:1.0"}, expected: "registry.example.com/app:1.0"},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Equal(imageName(tc.annotations), tc.expected), tc.annotations)
+	}
+}
@@ -13,9 +13,10 @@ import (
 )
 
 type pruneOptions struct {
-	force  bool
-	all    bool
-	filter opts.FilterOpt
+	force          bool
+	forceProtected bool
+	all            bool
+	filter         opts.FilterOpt
 }
 
 // NewPruneCommand returns a new cobra prune command for images
@@ -44,6 +45,7 @@ func NewPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images, not just dangling ones")
 	flags.Var(&options.filter, "filter", "Provide filter values (e.g. 'until=<timestamp>')")
+	command.AddForceProtectedFlag(flags, &options.forceProtected)
 
 	return cmd
 }
@@ -59,12 +61,16 @@ func runPrune(dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint6
 	pruneFilters := options.filter.Value().Clone()
 	pruneFilters.Add("dangling", fmt.Sprintf("%v", !options.all))
 	pruneFilters = command.PruneFilters(dockerCli, pruneFilters)
+	pruneFilters, err = command.ExcludeProtected(pruneFilters, options.forceProtected, command.ProtectedImages(context.Background(), dockerCli.Client(), options.all))
+	if err != nil {
+		return 0, "", err
+	}
 
 	warning := danglingWarning
 	if options.all {
 		warning = allImageWarning
 	}
-	if !options.force && !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), warning) {
+	if !command.ConfirmPrune(dockerCli, options.force, warning, "image") {
 		return 0, "", nil
 	}
 
@@ -99,3 +105,10 @@ func runPrune(dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint6
 func RunPrune(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error) {
 	return runPrune(dockerCli, pruneOptions{force: true, all: all, filter: filter})
 }
+
+// RunPruneForceProtected calls the Image Prune API, which also removes the
+// images protected by the protection label
+// This returns the amount of space reclaimed and a detailed output string
+func RunPruneForceProtected(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error) {
+	return runPrune(dockerCli, pruneOptions{force: true, forceProtected: true, all: all, filter: filter})
+}
@@ -1,7 +1,6 @@
 package image
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
@@ -62,7 +61,7 @@ func RunPull(cli command.Cli, opts PullOptions) error {
 		}
 	}
 
-	ctx := context.Background()
+	ctx := cli.Context()
 	imgRefAndAuth, err := trust.GetImageReferencesAndAuth(ctx, nil, AuthResolver(cli), distributionRef.String())
 	if err != nil {
 		return err
@@ -1,8 +1,6 @@
 package image
 
 import (
-	"context"
-
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/distribution/reference"
@@ -50,7 +48,7 @@ func RunPush(dockerCli command.Cli, opts pushOptions) error {
 		return err
 	}
 
-	ctx := context.Background()
+	ctx := dockerCli.Context()
 
 	// Resolve the Auth config relevant for this server
 	authConfig := command.ResolveAuthConfig(ctx, dockerCli, repoInfo.Index)
@@ -14,8 +14,9 @@ import (
 )
 
 type removeOptions struct {
-	force   bool
-	noPrune bool
+	force          bool
+	forceProtected bool
+	noPrune        bool
 }
 
 // NewRemoveCommand creates a new `docker remove` command
@@ -35,6 +36,7 @@ func NewRemoveCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force removal of the image")
 	flags.BoolVar(&opts.noPrune, "no-prune", false, "Do not delete untagged parents")
+	command.AddForceProtectedFlag(flags, &opts.forceProtected)
 
 	return cmd
 }
@@ -58,6 +60,15 @@ func runRemove(dockerCli command.Cli, opts removeOptions, images []string) error
 	var errs []string
 	var fatalErr = false
 	for _, img := range images {
+		if !opts.forceProtected {
+			if inspect, _, err := client.ImageInspectWithRaw(ctx, img); err == nil && inspect.Config != nil {
+				if err := command.CheckProtected("image", img, inspect.Config.Labels, false); err != nil {
+					fatalErr = true
+					errs = append(errs, err.Error())
+					continue
+				}
+			}
+		}
 		dels, err := client.ImageRemove(ctx, img, options)
 		if err != nil {
 			if !apiclient.IsErrNotFound(err) {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -75,6 +76,29 @@ func TestNewRemoveCommandErrors(t *testing.T) {
 	}
 }
 
+func TestNewRemoveCommandProtected(t *testing.T) {
+	var removed []string
+	client := &fakeClient{
+		imageInspectFunc: func(image string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{Config: &container.Config{Labels: map[string]string{"cli.docker.io/protected": "true"}}}, nil, nil
+		},
+		imageRemoveFunc: func(image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+			removed = append(removed, image)
+			return []types.ImageDeleteResponseItem{{Deleted: image}}, nil
+		},
+	}
+	cmd := NewRemoveCommand(test.NewFakeCli(client))
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"-f", "image1"})
+	assert.Check(t, is.Error(cmd.Execute(), "image image1 is protected by the cli.docker.io/protected=true label: use --force-protected to remove it"))
+	assert.Check(t, is.Len(removed, 0))
+
+	cmd = NewRemoveCommand(test.NewFakeCli(client))
+	cmd.SetArgs([]string{"--force-protected", "image1"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(removed, []string{"image1"}))
+}
+
 func TestNewRemoveCommandSuccess(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -0,0 +1,145 @@
+package image
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type retagOptions struct {
+	from    string
+	to      string
+	dryRun  bool
+	noTrunc bool
+	format  string
+}
+
+// newRetagCommand creates a new `docker image retag` command
+func newRetagCommand(dockerCli command.Cli) *cobra.Command {
+	var opts retagOptions
+
+	cmd := &cobra.Command{
+		Use:   "retag [OPTIONS] --from PATTERN --to REPLACEMENT",
+		Short: "Tag the local images matching a pattern with a replacement",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRetag(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.from, "from", "", "Regular expression matching the whole tags of the images")
+	flags.StringVar(&opts.to, "to", "", "Replacement of the matching tags, which can reference the groups of the pattern ($1, ${name})")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Print the tags without creating them")
+	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print the tags using a Go template")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func runRetag(dockerCli command.Cli, opts retagOptions) error {
+	ctx := dockerCli.Context()
+
+	// The pattern matches the whole tags, so that "registry.old/(.*)" does
+	// not match "myregistry.old/app"
+	pattern, err := regexp.Compile("^(?:" + opts.from + ")$")
+	if err != nil {
+		return errors.Wrapf(err, "invalid pattern %q", opts.from)
+	}
+
+	images, err := dockerCli.Client().ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return err
+	}
+	entries, err := retagEntries(images, pattern, opts.to)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	if !opts.dryRun {
+		tagged := entries[:0]
+		for _, entry := range entries {
+			if err := dockerCli.Client().ImageTag(ctx, entry.ID, entry.Target); err != nil {
+				errs = append(errs, fmt.Sprintf("failed to tag %s as %s: %v", entry.Source, entry.Target, err))
+				continue
+			}
+			tagged = append(tagged, entry)
+		}
+		entries = tagged
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
+	retagCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewRetagFormat(format),
+		Trunc:  truncation.Trunc(opts.noTrunc),
+		Widths: truncation.Widths("retag"),
+	}
+	if err := RetagWrite(retagCtx, entries); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// retagEntries returns the tags of the images matching the pattern, and the
+// tags they are mapped to, sorted by tag. The tags which are mapped to
+// themselves, or to a tag of the same image, are skipped, and two tags cannot
+// be mapped to the same tag.
+func retagEntries(images []types.ImageSummary, pattern *regexp.Regexp, replacement string) ([]retagEntry, error) {
+	var entries []retagEntry
+	for _, img := range images {
+		existing := make(map[string]bool, len(img.RepoTags))
+		for _, tag := range img.RepoTags {
+			existing[tag] = true
+		}
+		for _, tag := range img.RepoTags {
+			if tag == "<none>:<none>" || !pattern.MatchString(tag) {
+				continue
+			}
+			target := pattern.ReplaceAllString(tag, replacement)
+			ref, err := reference.ParseNormalizedNamed(target)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid tag %q for %s", target, tag)
+			}
+			if _, isCanonical := ref.(reference.Canonical); isCanonical {
+				return nil, errors.Errorf("invalid tag %q for %s: refusing to create a tag with a digest reference", target, tag)
+			}
+			target = reference.FamiliarString(reference.TagNameOnly(ref))
+			if existing[target] {
+				continue
+			}
+			entries = append(entries, retagEntry{Source: tag, Target: target, ID: img.ID})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Source < entries[j].Source
+	})
+	sources := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if source, ok := sources[entry.Target]; ok {
+			return nil, errors.Errorf("%s and %s are both mapped to %s", source, entry.Source, entry.Target)
+		}
+		sources[entry.Target] = entry.Source
+	}
+	return entries, nil
+}
@@ -0,0 +1,83 @@
+package image
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func newRetagClient(tagged map[string]string) *fakeClient {
+	return &fakeClient{
+		imageListFunc: func(types.ImageListOptions) ([]types.ImageSummary, error) {
+			return []types.ImageSummary{
+				{ID: "sha256:1111111111111111111111111111111111111111111111111111111111111111", RepoTags: []string{"registry.old/app:1", "registry.old/app:latest", "app:latest"}},
+				{ID: "sha256:2222222222222222222222222222222222222222222222222222222222222222", RepoTags: []string{"registry.old/tools/db:5", "registry.new/tools/db:5"}},
+				{ID: "sha256:3333333333333333333333333333333333333333333333333333333333333333", RepoTags: []string{"myregistry.old/app:1", "<none>:<none>"}},
+			}, nil
+		},
+		imageTagFunc: func(image, ref string) error {
+			if tagged == nil {
+				panic("the images are tagged with --dry-run")
+			}
+			tagged[ref] = image
+			return nil
+		},
+	}
+}
+
+func TestNewRetagCommandDryRun(t *testing.T) {
+	cli := test.NewFakeCli(newRetagClient(nil))
+	cmd := newRetagCommand(cli)
+	cmd.SetArgs([]string{"--dry-run", "--from", "registry.old/(.*)", "--to", "registry.new/$1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "retag-command-dry-run.golden")
+}
+
+func TestNewRetagCommand(t *testing.T) {
+	tagged := map[string]string{}
+	cli := test.NewFakeCli(newRetagClient(tagged))
+	cmd := newRetagCommand(cli)
+	cmd.SetArgs([]string{"--from", `registry\.old/(?P<name>[^:]*):.*`, "--to", "registry.new/${name}:migrated", "--format", "{{.Source}} {{.Target}}"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "registry.old/app:1 and registry.old/app:latest are both mapped to registry.new/app:migrated")
+
+	cmd = newRetagCommand(cli)
+	cmd.SetArgs([]string{"--from", `registry\.old/(.*):1`, "--to", "registry.new/$1", "--format", "{{.Source}} {{.Target}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "registry.old/app:1 registry.new/app:latest\n"))
+	assert.Check(t, is.DeepEqual(tagged, map[string]string{
+		"registry.new/app:latest": "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+	}))
+}
+
+func TestNewRetagCommandErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--to", "registry.new/$1"},
+			expectedError: `required flag(s) "from" not set`,
+		},
+		{
+			args:          []string{"--from", "registry.old/(.*", "--to", "registry.new/$1"},
+			expectedError: `invalid pattern "registry.old/(.*"`,
+		},
+		{
+			args:          []string{"--from", "registry.old/(.*)", "--to", "registry.new/App/$1"},
+			expectedError: `invalid tag "registry.new/App/app:1" for registry.old/app:1`,
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(newRetagClient(nil))
+		cmd := newRetagCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
@@ -1,18 +1,28 @@
 package image
 
 import (
+	"archive/tar"
 	"context"
+	"encoding/json"
 	"io"
+	"io/ioutil"
+	"os"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/trust/cosign"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type saveOptions struct {
-	images []string
-	output string
+	images            []string
+	output            string
+	format            string
+	includeSignatures bool
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -32,12 +42,30 @@ func NewSaveCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVar(&opts.format, "format", formatDockerArchive, `Format of the saved images ("docker-archive"|"oci-layout")`)
+	flags.BoolVar(&opts.includeSignatures, "include-signatures", false, "Include the cosign signatures of the images, to verify them offline")
 
 	return cmd
 }
 
 // RunSave performs a save against the engine based on the specified options
 func RunSave(dockerCli command.Cli, opts saveOptions) error {
+	switch opts.format {
+	case formatDockerArchive:
+	case formatOCILayout:
+		if opts.output == "" {
+			return errors.New("the -o flag is required to save the images to an OCI layout directory")
+		}
+		if opts.includeSignatures {
+			return errors.New("the --include-signatures flag is not supported with the oci-layout format")
+		}
+		if err := validateOCILayoutOutput(opts.output); err != nil {
+			return errors.Wrap(err, "failed to save image")
+		}
+	default:
+		return errors.Errorf("invalid format %q: must be docker-archive or oci-layout", opts.format)
+	}
+
 	if opts.output == "" && dockerCli.Out().IsTerminal() {
 		return errors.New("cowardly refusing to save to a terminal. Use the -o flag or redirect")
 	}
@@ -46,16 +74,144 @@ func RunSave(dockerCli command.Cli, opts saveOptions) error {
 		return errors.Wrap(err, "failed to save image")
 	}
 
-	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images)
+	ctx := context.Background()
+	var signatures []byte
+	if opts.includeSignatures {
+		archive, err := fetchArchiveSignatures(ctx, dockerCli, opts.images)
+		if err != nil {
+			return err
+		}
+		if signatures, err = json.Marshal(archive); err != nil {
+			return err
+		}
+	}
+
+	responseBody, err := dockerCli.Client().ImageSave(ctx, opts.images)
 	if err != nil {
 		return err
 	}
 	defer responseBody.Close()
 
+	if opts.format == formatOCILayout {
+		return writeOCILayout(opts.output, responseBody)
+	}
+
+	var archive io.Reader = responseBody
+	if signatures != nil {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		go func() {
+			pw.CloseWithError(appendToArchive(pw, responseBody, cosign.ArchiveFile, signatures))
+		}()
+		archive = pr
+	}
+
 	if opts.output == "" {
-		_, err := io.Copy(dockerCli.Out(), responseBody)
+		_, err := io.Copy(dockerCli.Out(), archive)
 		return err
 	}
 
-	return command.CopyToFile(opts.output, responseBody)
+	return command.CopyToFile(opts.output, archive)
+}
+
+// validateOCILayoutOutput validates that the images can be saved to an OCI
+// layout in the output directory, which must not exist, or be empty
+func validateOCILayoutOutput(output string) error {
+	info, err := os.Stat(output)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.Errorf("invalid output path: %q must be a directory", output)
+	}
+	entries, err := ioutil.ReadDir(output)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return errors.Errorf("invalid output path: directory %q is not empty", output)
+	}
+	return nil
+}
+
+// fetchArchiveSignatures fetches the cosign signatures of the images from
+// their registries. The images must be referenced by name, and have been
+// pulled from, or pushed to, their repositories.
+func fetchArchiveSignatures(ctx context.Context, dockerCli command.Cli, images []string) (*cosign.Archive, error) {
+	archive := cosign.NewArchive()
+	for _, image := range images {
+		ref, err := reference.ParseNormalizedNamed(image)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to include the signatures of %s", image)
+		}
+		ref = reference.TagNameOnly(ref)
+		inspect, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, image)
+		if err != nil {
+			return nil, err
+		}
+		dgst, err := repoDigest(ref, inspect.RepoDigests)
+		if err != nil {
+			return nil, err
+		}
+		if err := archive.Add(ctx, dockerCli.RegistryClient(false), ref, dgst, digest.Digest(inspect.ID)); err != nil {
+			return nil, errors.Wrapf(err, "unable to include the signatures of %s", image)
+		}
+	}
+	return archive, nil
+}
+
+// repoDigest returns the digest of the manifest of the image in the
+// repository of the reference.
+func repoDigest(ref reference.Named, repoDigests []string) (digest.Digest, error) {
+	if canonical, ok := ref.(reference.Canonical); ok {
+		return canonical.Digest(), nil
+	}
+	for _, rd := range repoDigests {
+		named, err := reference.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		if canonical, ok := named.(reference.Canonical); ok && named.Name() == ref.Name() {
+			return canonical.Digest(), nil
+		}
+	}
+	return "", errors.Errorf("unable to include the signatures of %s: the image has no digest in its repository, pull or push it first", reference.FamiliarString(ref))
+}
+
+// appendToArchive copies the tar archive from src to dst, adding the file
+// with the given name and content.
+func appendToArchive(dst io.Writer, src io.Reader, name string, content []byte) error {
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return err
+	}
+	return tw.Close()
 }
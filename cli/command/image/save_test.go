@@ -1,6 +1,8 @@
 package image
 
 import (
+	"archive/tar"
+	"bytes"
 	"io"
 	"io/ioutil"
 	"os"
@@ -8,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -15,11 +18,12 @@ import (
 
 func TestNewSaveCommandErrors(t *testing.T) {
 	testCases := []struct {
-		name          string
-		args          []string
-		isTerminal    bool
-		expectedError string
-		imageSaveFunc func(images []string) (io.ReadCloser, error)
+		name             string
+		args             []string
+		isTerminal       bool
+		expectedError    string
+		imageSaveFunc    func(images []string) (io.ReadCloser, error)
+		imageInspectFunc func(image string) (types.ImageInspect, []byte, error)
 	}{
 		{
 			name:          "wrong args",
@@ -51,9 +55,20 @@ func TestNewSaveCommandErrors(t *testing.T) {
 			args:          []string{"-o", "/dev/null", "arg1"},
 			expectedError: "failed to save image: invalid output path: \"/dev/null\" must be a directory or a regular file",
 		},
+		{
+			name:          "include signatures of image without digest",
+			args:          []string{"--include-signatures", "-o", "save_tmp_file", "registry.example.com/app:1.0"},
+			expectedError: "unable to include the signatures of registry.example.com/app:1.0: the image has no digest in its repository, pull or push it first",
+			imageInspectFunc: func(image string) (types.ImageInspect, []byte, error) {
+				return types.ImageInspect{
+					ID:          "sha256:e1b5d3b24c0d091ad5e4ad0e3eb60d8d55aaa6c5d5cd05da1d157c8b3d1196a6",
+					RepoDigests: []string{"registry.example.com/other@sha256:6d4c741d3a3cd5c8a71e3ce0d2a24fb9bd9bb5bb42cf2df05f36379e7fe2dda1"},
+				}, nil, nil
+			},
+		},
 	}
 	for _, tc := range testCases {
-		cli := test.NewFakeCli(&fakeClient{imageSaveFunc: tc.imageSaveFunc})
+		cli := test.NewFakeCli(&fakeClient{imageSaveFunc: tc.imageSaveFunc, imageInspectFunc: tc.imageInspectFunc})
 		cli.Out().SetIsTerminal(tc.isTerminal)
 		cmd := NewSaveCommand(cli)
 		cmd.SetOutput(ioutil.Discard)
@@ -106,3 +121,29 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendToArchive(t *testing.T) {
+	src := new(bytes.Buffer)
+	tw := tar.NewWriter(src)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0644, Size: 2}))
+	_, err := tw.Write([]byte("[]"))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+
+	dst := new(bytes.Buffer)
+	assert.NilError(t, appendToArchive(dst, src, "signatures.json", []byte("{}")))
+
+	var files []string
+	tr := tar.NewReader(dst)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		content, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files = append(files, hdr.Name+": "+string(content))
+	}
+	assert.Check(t, is.DeepEqual([]string{"manifest.json: []", "signatures.json: {}"}, files))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/cli/trust"
+	"github.com/docker/cli/cli/trust/cosign"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
@@ -183,7 +184,16 @@ func imagePushPrivileged(ctx context.Context, cli command.Cli, authConfig types.
 
 // trustedPull handles content trust pulling of an image
 func trustedPull(ctx context.Context, cli command.Cli, imgRefAndAuth trust.ImageRefAndAuth, opts PullOptions) error {
-	refs, err := getTrustedPullTargets(cli, imgRefAndAuth)
+	verifier, err := cosign.VerifierForReference(cli.ConfigFile(), imgRefAndAuth.Reference())
+	if err != nil {
+		return err
+	}
+	var refs []target
+	if verifier != nil {
+		refs, err = getCosignPullTargets(ctx, cli, verifier, imgRefAndAuth.Reference())
+	} else {
+		refs, err = getTrustedPullTargets(cli, imgRefAndAuth)
+	}
 	if err != nil {
 		return err
 	}
@@ -274,6 +284,36 @@ func getTrustedPullTargets(cli command.Cli, imgRefAndAuth trust.ImageRefAndAuth)
 	return []target{r}, err
 }
 
+// getCosignPullTargets resolves the tag of the reference to the digest of its
+// manifest, and verifies the cosign signatures of the manifest.
+func getCosignPullTargets(ctx context.Context, cli command.Cli, verifier *cosign.Verifier, ref reference.Named) ([]target, error) {
+	tagged, isTagged := ref.(reference.NamedTagged)
+	if !isTagged {
+		return nil, errors.Errorf("pulling all tags of %s is not supported with cosign signature verification", reference.FamiliarName(ref))
+	}
+	t, err := cosignTarget(ctx, cli, verifier, tagged)
+	if err != nil {
+		return nil, err
+	}
+	return []target{t}, nil
+}
+
+func cosignTarget(ctx context.Context, cli command.Cli, verifier *cosign.Verifier, ref reference.NamedTagged) (target, error) {
+	registryClient := cli.RegistryClient(false)
+	content, err := registryClient.GetManifestContent(ctx, ref)
+	if err != nil {
+		return target{}, errors.Wrapf(err, "unable to resolve %s", reference.FamiliarString(ref))
+	}
+	verified, err := verifier.Verify(ctx, registryClient, ref, content.Digest)
+	if err != nil {
+		return target{}, err
+	}
+	if verified.Identity != "" {
+		logrus.Debugf("verified cosign signature of %s by %s", reference.FamiliarString(ref), verified.Identity)
+	}
+	return target{name: ref.Tag(), digest: content.Digest, size: int64(len(content.Payload))}, nil
+}
+
 // imagePullPrivileged pulls the image and displays it to the output
 func imagePullPrivileged(ctx context.Context, cli command.Cli, imgRefAndAuth trust.ImageRefAndAuth, opts PullOptions) error {
 	ref := reference.FamiliarString(imgRefAndAuth.Reference())
@@ -309,6 +349,18 @@ func TrustedReference(ctx context.Context, cli command.Cli, ref reference.NamedT
 		return nil, err
 	}
 
+	verifier, err := cosign.VerifierForReference(cli.ConfigFile(), imgRefAndAuth.Reference())
+	if err != nil {
+		return nil, err
+	}
+	if verifier != nil {
+		t, err := cosignTarget(ctx, cli, verifier, ref)
+		if err != nil {
+			return nil, err
+		}
+		return reference.WithDigest(reference.TrimNamed(ref), t.digest)
+	}
+
 	notaryRepo, err := cli.NotaryClient(imgRefAndAuth, []string{"pull"})
 	if err != nil {
 		return nil, errors.Wrap(err, "error establishing connection to trust repository")
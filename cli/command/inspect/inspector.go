@@ -8,6 +8,8 @@ import (
 	"text/template"
 
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/templates"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -36,16 +38,24 @@ func NewTemplateInspector(outputStream io.Writer, tmpl *template.Template) Inspe
 }
 
 // NewTemplateInspectorFromString creates a new TemplateInspector from a string
-// which is compiled into a template.
+// which is compiled into a template, or a TableInspector if the string is a
+// table-type format.
 func NewTemplateInspectorFromString(out io.Writer, tmplStr string) (Inspector, error) {
 	if tmplStr == "" {
 		return NewIndentedInspector(out), nil
 	}
 
+	isTable := formatter.Format(tmplStr).IsTable()
+	if isTable {
+		tmplStr = tableFormat(tmplStr)
+	}
 	tmpl, err := templates.Parse(tmplStr)
 	if err != nil {
 		return nil, errors.Errorf("Template parsing error: %s", err)
 	}
+	if isTable {
+		return NewTableInspector(out, tmpl), nil
+	}
 	return NewTemplateInspector(out, tmpl), nil
 }
 
@@ -61,16 +71,16 @@ func Inspect(out io.Writer, references []string, tmplStr string, getRef GetRefFu
 		return cli.StatusError{StatusCode: 64, Status: err.Error()}
 	}
 
-	var inspectErrs []string
+	var inspectErrs []error
 	for _, ref := range references {
 		element, raw, err := getRef(ref)
 		if err != nil {
-			inspectErrs = append(inspectErrs, err.Error())
+			inspectErrs = append(inspectErrs, err)
 			continue
 		}
 
 		if err := inspector.Inspect(element, raw); err != nil {
-			inspectErrs = append(inspectErrs, err.Error())
+			inspectErrs = append(inspectErrs, err)
 		}
 	}
 
@@ -79,14 +89,29 @@ func Inspect(out io.Writer, references []string, tmplStr string, getRef GetRefFu
 	}
 
 	if len(inspectErrs) != 0 {
-		return cli.StatusError{
-			StatusCode: 1,
-			Status:     strings.Join(inspectErrs, "\n"),
-		}
+		return inspectError(inspectErrs)
 	}
 	return nil
 }
 
+// inspectError returns the StatusError of the errors of the references. Its
+// exit code is the exit code of the kind of the errors if they all have the
+// same kind, such as when none of the references exist.
+func inspectError(errs []error) cli.StatusError {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	sterr := cli.NewStatusError(errs[0])
+	for _, err := range errs[1:] {
+		if code, _ := command.ClassifyError(err); code != sterr.Code {
+			sterr.Code, sterr.StatusCode = "", command.ExitCodeError
+		}
+	}
+	sterr.Status = strings.Join(msgs, "\n")
+	return sterr
+}
+
 // Inspect executes the inspect template.
 // It decodes the raw element into a map if the initial execution fails.
 // This allows docker cli to parse inspect structs injected with Swarm fields.
@@ -5,7 +5,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/templates"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -257,3 +261,75 @@ func TestTemplateInspectorRawFallbackNumber(t *testing.T) {
 		b.Reset()
 	}
 }
+
+type testContainer struct {
+	Name  string
+	State struct {
+		Status string
+	}
+	NetworkSettings struct {
+		IPAddress string
+	}
+}
+
+func TestTableInspector(t *testing.T) {
+	b := new(bytes.Buffer)
+	i, err := NewTemplateInspectorFromString(b, `table {{.Name}}\t{{.State.Status}}\t{{.NetworkSettings.IPAddress}}`)
+	assert.NilError(t, err)
+
+	c1, c2 := testContainer{Name: "/web"}, testContainer{Name: "/database-primary"}
+	c1.State.Status, c2.State.Status = "running", "exited"
+	c1.NetworkSettings.IPAddress = "172.17.0.2"
+	assert.NilError(t, i.Inspect(c1, nil))
+	assert.NilError(t, i.Inspect(c2, nil))
+	assert.NilError(t, i.Flush())
+
+	expected := `NAME                STATUS              IP ADDRESS
+/web                running             172.17.0.2
+/database-primary   exited              
+`
+	assert.Check(t, is.Equal(expected, b.String()))
+}
+
+func TestTableHeader(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{format: `{{.ID}}`, expected: "ID"},
+		{format: "ID: {{.ID}}\t{{.Config.Image}}", expected: "ID: ID\tIMAGE"},
+		{format: "{{json .Config.Labels}}\t{{.State.Health.Status | upper}}", expected: "LABELS\tSTATUS"},
+		{format: "{{(index .Mounts 0).Source}}", expected: "SOURCE"},
+		{format: "{{.RestartCount}}\t{{.HostConfig.CPUShares}}", expected: "RESTART COUNT\tCPU SHARES"},
+		{format: `{{"static"}}`, expected: ""},
+	}
+	for _, tc := range testCases {
+		tmpl, err := templates.Parse(tc.format)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(tc.expected, tableHeader(tmpl)), tc.format)
+	}
+}
+
+func TestInspectErrors(t *testing.T) {
+	notFound := func(ref string) (interface{}, []byte, error) {
+		return nil, nil, errdefs.NotFound(errors.Errorf("No such object: %s", ref))
+	}
+	err := Inspect(new(bytes.Buffer), []string{"foo", "bar"}, "", notFound)
+	sterr, ok := err.(cli.StatusError)
+	assert.Assert(t, ok, err)
+	assert.Check(t, is.Equal(sterr.Status, "No such object: foo\nNo such object: bar"))
+	assert.Check(t, is.Equal(sterr.StatusCode, command.ExitCodeNotFound))
+	assert.Check(t, is.Equal(sterr.Code, command.ErrorCodeNotFound))
+
+	mixed := func(ref string) (interface{}, []byte, error) {
+		if ref == "foo" {
+			return notFound(ref)
+		}
+		return nil, nil, errors.New("something went wrong")
+	}
+	err = Inspect(new(bytes.Buffer), []string{"foo", "bar"}, "", mixed)
+	sterr, ok = err.(cli.StatusError)
+	assert.Assert(t, ok, err)
+	assert.Check(t, is.Equal(sterr.StatusCode, command.ExitCodeError))
+	assert.Check(t, is.Equal(sterr.Code, ""))
+}
@@ -0,0 +1,114 @@
+package inspect
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode"
+
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+// TableInspector uses a table-type template to inspect elements, printing a
+// header and aligning the columns, like the table formats of the ls commands.
+type TableInspector struct {
+	TemplateInspector
+	header string
+}
+
+// NewTableInspector creates a new inspector with a table-type template, whose
+// "table" prefix is already removed.
+func NewTableInspector(outputStream io.Writer, tmpl *template.Template) Inspector {
+	return &TableInspector{
+		TemplateInspector: TemplateInspector{
+			outputStream: outputStream,
+			buffer:       new(bytes.Buffer),
+			tmpl:         tmpl,
+		},
+		header: tableHeader(tmpl),
+	}
+}
+
+// Flush writes the header and the result of inspecting all elements into the
+// output stream, with aligned columns.
+func (i *TableInspector) Flush() error {
+	t := formatter.NewTableWriter(i.outputStream)
+	if _, err := io.WriteString(t, i.header+"\n"); err != nil {
+		return err
+	}
+	if _, err := i.buffer.WriteTo(t); err != nil {
+		return err
+	}
+	return t.Flush()
+}
+
+// tableFormat returns the template of a table-type format, without the
+// "table" prefix, and with the escaped tabs and newlines replaced.
+func tableFormat(format string) string {
+	format = strings.Trim(strings.TrimPrefix(format, formatter.TableFormatKey), " ")
+	return strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+}
+
+// tableHeader returns the header of a table-type template: the text of the
+// template is kept, and each action is replaced with the name of the last
+// field it uses, such as "STATUS" for "{{.State.Status}}".
+func tableHeader(tmpl *template.Template) string {
+	if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return ""
+	}
+	var header strings.Builder
+	for _, node := range tmpl.Tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			header.Write(n.Text)
+		case *parse.ActionNode:
+			header.WriteString(columnName(lastField(n.Pipe)))
+		}
+	}
+	return strings.TrimRight(header.String(), "\n")
+}
+
+// lastField returns the name of the last field used by the pipeline, if any.
+func lastField(pipe *parse.PipeNode) string {
+	var name string
+	if pipe == nil {
+		return name
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.FieldNode:
+				name = a.Ident[len(a.Ident)-1]
+			case *parse.ChainNode:
+				if len(a.Field) > 0 {
+					name = a.Field[len(a.Field)-1]
+				}
+			case *parse.PipeNode:
+				if f := lastField(a); f != "" {
+					name = f
+				}
+			}
+		}
+	}
+	return name
+}
+
+// columnName returns the column name of a field, in upper case with its
+// words separated by spaces, such as "IP ADDRESS" for "IPAddress".
+func columnName(field string) string {
+	runes := []rune(field)
+	var name []rune
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				name = append(name, ' ')
+			}
+		}
+		name = append(name, unicode.ToUpper(r))
+	}
+	return string(name)
+}
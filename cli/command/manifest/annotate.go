@@ -6,6 +6,7 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/opts"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -18,11 +19,15 @@ type annotateOptions struct {
 	os         string
 	arch       string
 	osFeatures []string
+	osVersion  string
+	// annotations and artifactType are only supported by OCI image indexes
+	annotations  opts.ListOpts
+	artifactType string
 }
 
 // NewAnnotateCommand creates a new `docker manifest annotate` command
 func newAnnotateCommand(dockerCli command.Cli) *cobra.Command {
-	var opts annotateOptions
+	opts := annotateOptions{annotations: opts.NewListOpts(opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
@@ -41,6 +46,9 @@ func newAnnotateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
 	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
 	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.StringVar(&opts.osVersion, "os-version", "", "Set operating system version")
+	flags.Var(&opts.annotations, "annotation", "Add an annotation to the manifest in an OCI image index")
+	flags.StringVar(&opts.artifactType, "artifact-type", "", "Set the artifact type of the manifest in an OCI image index")
 
 	return cmd
 }
@@ -64,6 +72,28 @@ func runManifestAnnotate(dockerCli command.Cli, opts annotateOptions) error {
 		return err
 	}
 
+	if opts.annotations.Len() > 0 || opts.artifactType != "" {
+		indexOptions, err := manifestStore.GetIndexOptions(targetRef)
+		if err != nil {
+			return err
+		}
+		if !indexOptions.OCI {
+			return errors.Errorf("--annotation and --artifact-type require an OCI image index: %s is a Docker manifest list", opts.target)
+		}
+	}
+	imageManifest.Descriptor.Annotations = mergeAnnotations(imageManifest.Descriptor.Annotations, opts.annotations.GetAll())
+	if opts.artifactType != "" {
+		imageManifest.ArtifactType = opts.artifactType
+	}
+
+	// Artifacts other than images have no platform
+	if imageManifest.Descriptor.Platform == nil && opts.os == "" && opts.arch == "" {
+		if opts.variant != "" || opts.osVersion != "" || len(opts.osFeatures) > 0 {
+			return errors.Errorf("manifest for image %s has no platform: use --os and --arch", opts.image)
+		}
+		return manifestStore.Save(targetRef, imgRef, imageManifest)
+	}
+
 	// Update the mf
 	if imageManifest.Descriptor.Platform == nil {
 		imageManifest.Descriptor.Platform = new(ocispec.Platform)
@@ -80,6 +110,9 @@ func runManifestAnnotate(dockerCli command.Cli, opts annotateOptions) error {
 	if opts.variant != "" {
 		imageManifest.Descriptor.Platform.Variant = opts.variant
 	}
+	if opts.osVersion != "" {
+		imageManifest.Descriptor.Platform.OSVersion = opts.osVersion
+	}
 
 	if !isValidOSArch(imageManifest.Descriptor.Platform.OS, imageManifest.Descriptor.Platform.Architecture) {
 		return errors.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", opts.os, opts.arch)
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -75,3 +76,37 @@ func TestManifestAnnotate(t *testing.T) {
 	expected := golden.Get(t, "inspect-annotate.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestManifestAnnotateOCI(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	namedRef := ref(t, "alpine:3.0")
+	imageManifest := fullImageManifest(t, namedRef)
+	err := store.Save(ref(t, "list:v1"), namedRef, imageManifest)
+	assert.NilError(t, err)
+
+	cmd := newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "org.opencontainers.image.title=test", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	expectedError := "--annotation and --artifact-type require an OCI image index: example.com/list:v1 is a Docker manifest list"
+	assert.ErrorContains(t, cmd.Execute(), expectedError)
+
+	assert.NilError(t, store.SaveIndexOptions(ref(t, "list:v1"), types.IndexOptions{OCI: true}))
+	cmd = newAnnotateCommand(cli)
+	cmd.SetArgs([]string{
+		"--annotation", "org.opencontainers.image.title=test",
+		"--artifact-type", "application/vnd.example.image.v1",
+		"--os-version", "10.0.17763.1",
+		"example.com/list:v1", "example.com/alpine:3.0",
+	})
+	assert.NilError(t, cmd.Execute())
+
+	imageManifest, err = store.Get(ref(t, "list:v1"), namedRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]string{"org.opencontainers.image.title": "test"}, imageManifest.Descriptor.Annotations))
+	assert.Check(t, is.Equal("application/vnd.example.image.v1", imageManifest.ArtifactType))
+	assert.Check(t, is.Equal("10.0.17763.1", imageManifest.Descriptor.Platform.OSVersion))
+}
@@ -7,15 +7,20 @@ import (
 	"github.com/docker/cli/cli/registry/client"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/reference"
+	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/opencontainers/go-digest"
 )
 
 type fakeRegistryClient struct {
-	getManifestFunc     func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
-	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
-	mountBlobFunc       func(ctx context.Context, source reference.Canonical, target reference.Named) error
-	putManifestFunc     func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
-	getTagsFunc         func(ctx context.Context, ref reference.Named) ([]string, error)
+	getManifestFunc        func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
+	getManifestListFunc    func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	mountBlobFunc          func(ctx context.Context, source reference.Canonical, target reference.Named) error
+	putManifestFunc        func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
+	getTagsFunc            func(ctx context.Context, ref reference.Named) ([]string, error)
+	getManifestContentFunc func(ctx context.Context, ref reference.Named) (client.ManifestContent, error)
+	getBlobFunc            func(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error)
+	putManifestContentFunc func(ctx context.Context, ref reference.Named, content client.ManifestContent) (bool, error)
+	deleteManifestFunc     func(ctx context.Context, ref reference.Named) error
 }
 
 func (c *fakeRegistryClient) GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
@@ -53,4 +58,36 @@ func (c *fakeRegistryClient) GetTags(ctx context.Context, ref reference.Named) (
 	return nil, nil
 }
 
+func (c *fakeRegistryClient) GetManifestContent(ctx context.Context, ref reference.Named) (client.ManifestContent, error) {
+	if c.getManifestContentFunc != nil {
+		return c.getManifestContentFunc(ctx, ref)
+	}
+	return client.ManifestContent{}, nil
+}
+
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	if c.getBlobFunc != nil {
+		return c.getBlobFunc(ctx, ref, dgst)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) PutManifestContent(ctx context.Context, ref reference.Named, content client.ManifestContent) (bool, error) {
+	if c.putManifestContentFunc != nil {
+		return c.putManifestContentFunc(ctx, ref, content)
+	}
+	return false, nil
+}
+
+func (c *fakeRegistryClient) DeleteManifest(ctx context.Context, ref reference.Named) error {
+	if c.deleteManifestFunc != nil {
+		return c.deleteManifestFunc(ctx, ref)
+	}
+	return nil
+}
+
+func (c *fakeRegistryClient) SearchRepositories(ctx context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (client.SearchPage, error) {
+	return client.SearchPage{}, nil
+}
+
 var _ client.RegistryClient = &fakeRegistryClient{}
@@ -7,18 +7,26 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/opts"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type createOpts struct {
-	amend    bool
-	insecure bool
+	amend        bool
+	insecure     bool
+	oci          bool
+	artifactType string
+	annotations  opts.ListOpts
+	subject      string
 }
 
 func newCreateListCommand(dockerCli command.Cli) *cobra.Command {
-	opts := createOpts{}
+	opts := createOpts{annotations: opts.NewListOpts(opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "create MANIFEST_LIST MANIFEST [MANIFEST...]",
@@ -32,6 +40,10 @@ func newCreateListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
 	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.oci, "oci", false, "Create an OCI image index instead of a Docker manifest list")
+	flags.StringVar(&opts.artifactType, "artifact-type", "", "Set the artifact type of the OCI image index")
+	flags.Var(&opts.annotations, "annotation", "Add an annotation to the OCI image index")
+	flags.StringVar(&opts.subject, "subject", "", "Set the manifest the OCI image index refers to, to attach it as a referrer")
 	return cmd
 }
 
@@ -59,6 +71,15 @@ func createManifestList(dockerCli command.Cli, args []string, opts createOpts) e
 	}
 
 	ctx := context.Background()
+	indexOptions, err := buildIndexOptions(ctx, dockerCli, targetRef, opts)
+	if err != nil {
+		return err
+	}
+	if indexOptions.OCI {
+		if err := manifestStore.SaveIndexOptions(targetRef, indexOptions); err != nil {
+			return err
+		}
+	}
 	// Now create the local manifest list transaction by looking up the manifest schemas
 	// for the constituent images:
 	manifests := args[1:]
@@ -69,7 +90,11 @@ func createManifestList(dockerCli command.Cli, args []string, opts createOpts) e
 			return err
 		}
 
-		manifest, err := getManifest(ctx, dockerCli, targetRef, namedRef, opts.insecure)
+		getManifestFunc := getManifest
+		if indexOptions.OCI {
+			getManifestFunc = getOCIManifest
+		}
+		manifest, err := getManifestFunc(ctx, dockerCli, targetRef, namedRef, opts.insecure)
 		if err != nil {
 			return err
 		}
@@ -80,3 +105,45 @@ func createManifestList(dockerCli command.Cli, args []string, opts createOpts) e
 	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", targetRef.String())
 	return nil
 }
+
+// buildIndexOptions returns the options of the manifest list, amending the
+// options of the existing manifest list.
+func buildIndexOptions(ctx context.Context, dockerCli command.Cli, targetRef reference.Named, options createOpts) (types.IndexOptions, error) {
+	indexOptions, err := dockerCli.ManifestStore().GetIndexOptions(targetRef)
+	if err != nil {
+		return indexOptions, err
+	}
+	if options.oci {
+		indexOptions.OCI = true
+	}
+	if !indexOptions.OCI {
+		if options.artifactType != "" || options.annotations.Len() > 0 || options.subject != "" {
+			return indexOptions, errors.New("--annotation, --artifact-type, and --subject require an OCI image index: use --oci")
+		}
+		return indexOptions, nil
+	}
+
+	if options.artifactType != "" {
+		indexOptions.ArtifactType = options.artifactType
+	}
+	indexOptions.Annotations = mergeAnnotations(indexOptions.Annotations, options.annotations.GetAll())
+	if options.subject != "" {
+		subjectRef, err := normalizeReference(options.subject)
+		if err != nil {
+			return indexOptions, errors.Wrapf(err, "error parsing name for subject %s", options.subject)
+		}
+		if subjectRef.Name() != targetRef.Name() {
+			return indexOptions, errors.Errorf("the subject %s must be in the repository of %s", options.subject, reference.FamiliarName(targetRef))
+		}
+		content, err := dockerCli.RegistryClient(options.insecure).GetManifestContent(ctx, subjectRef)
+		if err != nil {
+			return indexOptions, err
+		}
+		indexOptions.Subject = &ocispec.Descriptor{
+			MediaType: content.MediaType,
+			Digest:    content.Digest,
+			Size:      int64(len(content.Payload)),
+		}
+	}
+	return indexOptions, nil
+}
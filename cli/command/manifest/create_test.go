@@ -2,12 +2,16 @@ package manifest
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"testing"
 
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/cli/registry/client"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -27,10 +31,21 @@ func TestManifestCreateErrors(t *testing.T) {
 			args:          []string{"th!si'sa/fa!ke/li$t/name", "example.com/alpine:3.0"},
 			expectedError: "error parsing name for manifest list",
 		},
+		{
+			args:          []string{"--annotation", "org.opencontainers.image.title=test", "example.com/list:v1", "example.com/alpine:3.0"},
+			expectedError: "--annotation, --artifact-type, and --subject require an OCI image index: use --oci",
+		},
+		{
+			args:          []string{"--oci", "--subject", "example.com/other:v0", "example.com/list:v1", "example.com/alpine:3.0"},
+			expectedError: "the subject example.com/other:v0 must be in the repository of example.com/list",
+		},
 	}
 
 	for _, tc := range testCases {
+		store, cleanup := newTempManifestStore(t)
+		defer cleanup()
 		cli := test.NewFakeCli(nil)
+		cli.SetManifestStore(store)
 		cmd := newCreateListCommand(cli)
 		cmd.SetArgs(tc.args)
 		cmd.SetOutput(ioutil.Discard)
@@ -114,3 +129,58 @@ func TestManifestCreateNoManifest(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err, "No such image: example.com/alpine:3.0")
 }
+
+var ociImageConfig = []byte(`{"architecture":"arm64","os":"linux","variant":"v8"}`)
+
+// newOCIRegistryClient returns a registry client with the OCI image
+// "example.com/alpine:3.0", its SBOM "example.com/alpine:sbom", and the image
+// "example.com/list:v0".
+func newOCIRegistryClient() *fakeRegistryClient {
+	manifests := map[string]string{
+		"example.com/alpine:3.0":  fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":%q,"digest":%q,"size":%d},"layers":[]}`, ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageConfig, digest.FromBytes(ociImageConfig), len(ociImageConfig)),
+		"example.com/alpine:sbom": fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":"application/vnd.example.sbom.v1+json","digest":%q,"size":2},"layers":[]}`, ocispec.MediaTypeImageManifest, digest.FromBytes([]byte("{}"))),
+		"example.com/list:v0":     fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":%q,"digest":%q,"size":%d},"layers":[]}`, ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageConfig, digest.FromBytes(ociImageConfig), len(ociImageConfig)),
+	}
+	return &fakeRegistryClient{
+		getManifestContentFunc: func(_ context.Context, ref reference.Named) (client.ManifestContent, error) {
+			payload, ok := manifests[ref.String()]
+			if !ok {
+				return client.ManifestContent{}, errors.Errorf("No such manifest: %s", ref)
+			}
+			return client.ManifestContent{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromString(payload), Payload: []byte(payload)}, nil
+		},
+		getBlobFunc: func(_ context.Context, _ reference.Named, dgst digest.Digest) ([]byte, error) {
+			if dgst != digest.FromBytes(ociImageConfig) {
+				return nil, errors.Errorf("No such blob: %s", dgst)
+			}
+			return ociImageConfig, nil
+		},
+	}
+}
+
+func TestManifestCreateOCI(t *testing.T) {
+	store, cleanup := newTempManifestStore(t)
+	defer cleanup()
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(newOCIRegistryClient())
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{
+		"--oci",
+		"--artifact-type", "application/vnd.example.bundle.v1",
+		"--annotation", "org.opencontainers.image.title=test",
+		"--subject", "example.com/list:v0",
+		"example.com/list:v1", "example.com/alpine:3.0", "example.com/alpine:sbom",
+	})
+	assert.NilError(t, cmd.Execute())
+
+	// make a new cli to clear the buffers
+	cli = test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	inspectCmd := newInspectCommand(cli)
+	inspectCmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, inspectCmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-oci-index.golden")
+}
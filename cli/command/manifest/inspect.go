@@ -72,6 +72,13 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	// Try a local manifest list first
 	localManifestList, err := dockerCli.ManifestStore().GetList(namedRef)
 	if err == nil {
+		indexOptions, err := dockerCli.ManifestStore().GetIndexOptions(namedRef)
+		if err != nil {
+			return err
+		}
+		if indexOptions.OCI && !opts.verbose {
+			return printOCIIndex(dockerCli, namedRef, localManifestList, indexOptions)
+		}
 		return printManifestList(dockerCli, namedRef, localManifestList, opts)
 	}
 
@@ -146,3 +153,18 @@ func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []t
 	dockerCli.Out().Write(append(jsonBytes, '\n'))
 	return nil
 }
+
+// printOCIIndex prints the OCI image index which is pushed for a local manifest
+// list created with --oci
+func printOCIIndex(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, indexOptions types.IndexOptions) error {
+	index, err := buildOCIIndex(list, namedRef, indexOptions)
+	if err != nil {
+		return errors.Wrap(err, "failed to assemble OCI image index")
+	}
+	_, payload, err := index.Payload()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), string(payload))
+	return nil
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/registry"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -38,6 +40,7 @@ type manifestBlob struct {
 type pushRequest struct {
 	targetRef     reference.Named
 	list          *manifestlist.DeserializedManifestList
+	index         *types.OCIIndex
 	mountRequests []mountRequest
 	manifestBlobs []manifestBlob
 	insecure      bool
@@ -77,7 +80,12 @@ func runPush(dockerCli command.Cli, opts pushOpts) error {
 		return errors.Errorf("%s not found", targetRef)
 	}
 
-	pushRequest, err := buildPushRequest(manifests, targetRef, opts.insecure)
+	indexOptions, err := dockerCli.ManifestStore().GetIndexOptions(targetRef)
+	if err != nil {
+		return err
+	}
+
+	pushRequest, err := buildPushRequest(manifests, targetRef, indexOptions, opts.insecure)
 	if err != nil {
 		return err
 	}
@@ -92,11 +100,15 @@ func runPush(dockerCli command.Cli, opts pushOpts) error {
 	return nil
 }
 
-func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named, insecure bool) (pushRequest, error) {
+func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named, indexOptions types.IndexOptions, insecure bool) (pushRequest, error) {
 	req := pushRequest{targetRef: targetRef, insecure: insecure}
 
 	var err error
-	req.list, err = buildManifestList(manifests, targetRef)
+	if indexOptions.OCI {
+		req.index, err = buildOCIIndex(manifests, targetRef, indexOptions)
+	} else {
+		req.list, err = buildManifestList(manifests, targetRef)
+	}
 	if err != nil {
 		return req, err
 	}
@@ -158,6 +170,41 @@ func buildManifestList(manifests []types.ImageManifest, targetRef reference.Name
 	return manifestlist.FromDescriptors(descriptors)
 }
 
+// buildOCIIndex returns the OCI image index of the manifests. Unlike in Docker
+// manifest lists, manifests without platform, such as the manifests of
+// attestations, are allowed.
+func buildOCIIndex(manifests []types.ImageManifest, targetRef reference.Named, indexOptions types.IndexOptions) (*types.OCIIndex, error) {
+	targetRepoInfo, err := registry.ParseRepositoryInfo(targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptors := []types.OCIDescriptor{}
+	for _, imageManifest := range manifests {
+		platform := imageManifest.Descriptor.Platform
+		if platform != nil && (platform.Architecture == "" || platform.OS == "") {
+			return nil, errors.Errorf(
+				"manifest %s must have an OS and Architecture, or no platform, to be pushed to a registry", imageManifest.Ref)
+		}
+		descriptor, err := buildManifestDescriptor(targetRepoInfo, imageManifest)
+		if err != nil {
+			return nil, err
+		}
+		descriptors = append(descriptors, types.OCIDescriptor{
+			Descriptor: ocispec.Descriptor{
+				MediaType:   descriptor.MediaType,
+				Digest:      descriptor.Digest,
+				Size:        descriptor.Size,
+				Annotations: imageManifest.Descriptor.Annotations,
+				Platform:    platform,
+			},
+			ArtifactType: imageManifest.ArtifactType,
+		})
+	}
+
+	return types.NewOCIIndex(descriptors, indexOptions), nil
+}
+
 func buildManifestDescriptor(targetRepo *registry.RepositoryInfo, imageManifest types.ImageManifest) (manifestlist.ManifestDescriptor, error) {
 	repoInfo, err := registry.ParseRepositoryInfo(imageManifest.Ref)
 	if err != nil {
@@ -218,6 +265,10 @@ func buildPutManifestRequest(imageManifest types.ImageManifest, targetRef refere
 	if err != nil {
 		return mountRequest{}, err
 	}
+	// The content of OCI image manifests is pushed as is
+	if imageManifest.SchemaV2Manifest == nil {
+		return mountRequest{ref: mountRef, manifest: imageManifest}, nil
+	}
 
 	// This indentation has to be added to ensure sha parity with the registry
 	v2ManifestBytes, err := json.MarshalIndent(imageManifest.SchemaV2Manifest, "", "   ")
@@ -244,6 +295,9 @@ func pushList(ctx context.Context, dockerCli command.Cli, req pushRequest) error
 	if err := pushReferences(ctx, dockerCli.Out(), rclient, req.mountRequests); err != nil {
 		return err
 	}
+	if req.index != nil {
+		return pushIndex(ctx, dockerCli.Out(), rclient, req.targetRef, req.index)
+	}
 	dgst, err := rclient.PutManifest(ctx, req.targetRef, req.list)
 	if err != nil {
 		return err
@@ -255,7 +309,18 @@ func pushList(ctx context.Context, dockerCli command.Cli, req pushRequest) error
 
 func pushReferences(ctx context.Context, out io.Writer, client registryclient.RegistryClient, mounts []mountRequest) error {
 	for _, mount := range mounts {
-		newDigest, err := client.PutManifest(ctx, mount.ref, mount.manifest)
+		var newDigest digest.Digest
+		var err error
+		if mount.manifest.OCIManifest != nil {
+			newDigest = mount.manifest.Descriptor.Digest
+			_, err = client.PutManifestContent(ctx, mount.ref, registryclient.ManifestContent{
+				MediaType: mount.manifest.Descriptor.MediaType,
+				Digest:    newDigest,
+				Payload:   mount.manifest.OCIManifest,
+			})
+		} else {
+			newDigest, err = client.PutManifest(ctx, mount.ref, mount.manifest)
+		}
 		if err != nil {
 			return err
 		}
@@ -264,6 +329,67 @@ func pushReferences(ctx context.Context, out io.Writer, client registryclient.Re
 	return nil
 }
 
+// pushIndex pushes the OCI image index. If the index has a subject, and the
+// registry does not support the referrers API, the index is added to the
+// referrers of the subject with the referrers tag schema.
+func pushIndex(ctx context.Context, out io.Writer, client registryclient.RegistryClient, targetRef reference.Named, index *types.OCIIndex) error {
+	mediaType, payload, err := index.Payload()
+	if err != nil {
+		return err
+	}
+	dgst := digest.FromBytes(payload)
+	referrersAPI, err := client.PutManifestContent(ctx, targetRef, registryclient.ManifestContent{MediaType: mediaType, Digest: dgst, Payload: payload})
+	if err != nil {
+		return err
+	}
+	if index.Subject != nil && !referrersAPI {
+		descriptor, err := index.Descriptor()
+		if err != nil {
+			return err
+		}
+		if err := addReferrer(ctx, client, targetRef, index.Subject.Digest, descriptor); err != nil {
+			return errors.Wrapf(err, "failed to add %s to the referrers of %s", dgst, index.Subject.Digest)
+		}
+	}
+
+	fmt.Fprintln(out, dgst.String())
+	return nil
+}
+
+// addReferrer adds the descriptor to the index of the referrers tag of the
+// subject, which is the "<algorithm>-<encoded>" tag of its digest.
+func addReferrer(ctx context.Context, client registryclient.RegistryClient, ref reference.Named, subject digest.Digest, descriptor types.OCIDescriptor) error {
+	tagRef, err := reference.WithTag(reference.TrimNamed(ref), subject.Algorithm().String()+"-"+subject.Hex())
+	if err != nil {
+		return err
+	}
+
+	referrers := types.NewOCIIndex([]types.OCIDescriptor{}, types.IndexOptions{})
+	content, err := client.GetManifestContent(ctx, tagRef)
+	switch {
+	case registryclient.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(content.Payload, referrers); err != nil {
+			return errors.Wrapf(err, "invalid referrers index %s", tagRef)
+		}
+	}
+	for _, referrer := range referrers.Manifests {
+		if referrer.Digest == descriptor.Digest {
+			return nil
+		}
+	}
+	referrers.Manifests = append(referrers.Manifests, descriptor)
+
+	mediaType, payload, err := referrers.Payload()
+	if err != nil {
+		return err
+	}
+	_, err = client.PutManifestContent(ctx, tagRef, registryclient.ManifestContent{MediaType: mediaType, Digest: digest.FromBytes(payload), Payload: payload})
+	return err
+}
+
 func mountBlobs(ctx context.Context, client registryclient.RegistryClient, ref reference.Named, blobs []manifestBlob) error {
 	for _, blob := range blobs {
 		err := client.MountBlob(ctx, blob.canonical, ref)
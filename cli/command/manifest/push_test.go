@@ -2,14 +2,18 @@ package manifest
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"testing"
 
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/cli/registry/client"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func newFakeRegistryClient() *fakeRegistryClient {
@@ -67,3 +71,60 @@ func TestManifestPush(t *testing.T) {
 	err = cmd.Execute()
 	assert.NilError(t, err)
 }
+
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) + " not found" }
+func (notFoundError) NotFound()       {}
+
+func TestManifestPushOCI(t *testing.T) {
+	store, sCleanup := newTempManifestStore(t)
+	defer sCleanup()
+
+	registry := newOCIRegistryClient()
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(registry)
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--oci", "--subject", "example.com/list:v0", "example.com/list:v1", "example.com/alpine:3.0", "example.com/alpine:sbom"})
+	assert.NilError(t, cmd.Execute())
+	indexOptions, err := store.GetIndexOptions(ref(t, "list:v1"))
+	assert.NilError(t, err)
+
+	// The registry does not support the referrers API, and the subject has
+	// no referrers yet.
+	getManifestContent := registry.getManifestContentFunc
+	registry.getManifestContentFunc = func(ctx context.Context, ref reference.Named) (client.ManifestContent, error) {
+		if ref.String() == "example.com/list:sha256-"+indexOptions.Subject.Digest.Hex() {
+			return client.ManifestContent{}, notFoundError(ref.String())
+		}
+		return getManifestContent(ctx, ref)
+	}
+	pushed := map[string]client.ManifestContent{}
+	registry.putManifestContentFunc = func(_ context.Context, ref reference.Named, content client.ManifestContent) (bool, error) {
+		pushed[ref.String()] = content
+		return false, nil
+	}
+
+	cli = test.NewFakeCli(nil)
+	cli.SetManifestStore(store)
+	cli.SetRegistryClient(registry)
+	cmd = newPushListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+
+	index, ok := pushed["example.com/list:v1"]
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(ocispec.MediaTypeImageIndex, index.MediaType))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "\n"+index.Digest.String()+"\n"))
+	// The manifests of the other repository are pushed to the repository of the index
+	assert.Check(t, is.Len(pushed, 4))
+
+	referrers, ok := pushed["example.com/list:sha256-"+indexOptions.Subject.Digest.Hex()]
+	assert.Assert(t, ok)
+	var referrersIndex manifesttypes.OCIIndex
+	assert.NilError(t, json.Unmarshal(referrers.Payload, &referrersIndex))
+	assert.Assert(t, is.Len(referrersIndex.Manifests, 1))
+	assert.Check(t, is.Equal(index.Digest, referrersIndex.Manifests[0].Digest))
+}
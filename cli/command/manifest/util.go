@@ -2,11 +2,17 @@ package manifest
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/store"
 	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/opts"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 )
 
 type osArch struct {
@@ -79,3 +85,73 @@ func getManifest(ctx context.Context, dockerCli command.Cli, listRef, namedRef r
 		return data, nil
 	}
 }
+
+// getOCIManifest is getManifest for OCI image indexes, which can include OCI
+// image manifests, such as the manifests of attestations or other artifacts.
+func getOCIManifest(ctx context.Context, dockerCli command.Cli, listRef, namedRef reference.Named, insecure bool) (types.ImageManifest, error) {
+	data, err := dockerCli.ManifestStore().Get(listRef, namedRef)
+	switch {
+	case store.IsNotFound(err):
+	case err != nil:
+		return types.ImageManifest{}, err
+	default:
+		return data, nil
+	}
+
+	registryClient := dockerCli.RegistryClient(insecure)
+	content, err := registryClient.GetManifestContent(ctx, namedRef)
+	if err != nil {
+		return types.ImageManifest{}, err
+	}
+	switch content.MediaType {
+	case schema2.MediaTypeManifest:
+		return registryClient.GetManifest(ctx, namedRef)
+	case manifestlist.MediaTypeManifestList, ocispec.MediaTypeImageIndex:
+		return types.ImageManifest{}, errors.Errorf("%s is a manifest list", namedRef)
+	}
+
+	var manifest struct {
+		ocispec.Manifest
+		ArtifactType string `json:"artifactType,omitempty"`
+	}
+	if err := json.Unmarshal(content.Payload, &manifest); err != nil {
+		return types.ImageManifest{}, errors.Wrapf(err, "invalid manifest %s", namedRef)
+	}
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    content.Digest,
+		Size:      int64(len(content.Payload)),
+	}
+	imageManifest := types.NewOCIImageManifest(namedRef, desc, content.Payload)
+	imageManifest.ArtifactType = manifest.ArtifactType
+
+	// The platform of images is the one of their configuration. Other
+	// artifacts have no platform, and their artifact type defaults to the
+	// media type of their configuration.
+	if manifest.Config.MediaType != ocispec.MediaTypeImageConfig {
+		if imageManifest.ArtifactType == "" {
+			imageManifest.ArtifactType = manifest.Config.MediaType
+		}
+		return imageManifest, nil
+	}
+	config, err := registryClient.GetBlob(ctx, namedRef, manifest.Config.Digest)
+	if err != nil {
+		return types.ImageManifest{}, errors.Wrapf(err, "failed to fetch the configuration of %s", namedRef)
+	}
+	imageManifest.Descriptor.Platform = &ocispec.Platform{}
+	if err := json.Unmarshal(config, imageManifest.Descriptor.Platform); err != nil {
+		return types.ImageManifest{}, errors.Wrapf(err, "invalid configuration of %s", namedRef)
+	}
+	return imageManifest, nil
+}
+
+// mergeAnnotations adds the "key=value" annotations to the annotations.
+func mergeAnnotations(annotations map[string]string, kvs []string) map[string]string {
+	for k, v := range opts.ConvertKVStringsToMap(kvs) {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[k] = v
+	}
+	return annotations
+}
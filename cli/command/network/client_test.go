@@ -14,6 +14,8 @@ type fakeClient struct {
 	networkConnectFunc    func(ctx context.Context, networkID, container string, config *network.EndpointSettings) error
 	networkDisconnectFunc func(ctx context.Context, networkID, container string, force bool) error
 	networkListFunc       func(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
+	networkInspectFunc    func(ctx context.Context, networkID string, options types.NetworkInspectOptions) (types.NetworkResource, error)
+	containerInspectFunc  func(ctx context.Context, container string) (types.ContainerJSON, error)
 }
 
 func (c *fakeClient) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
@@ -43,3 +45,17 @@ func (c *fakeClient) NetworkList(ctx context.Context, options types.NetworkListO
 	}
 	return []types.NetworkResource{}, nil
 }
+
+func (c *fakeClient) NetworkInspect(ctx context.Context, networkID string, options types.NetworkInspectOptions) (types.NetworkResource, error) {
+	if c.networkInspectFunc != nil {
+		return c.networkInspectFunc(ctx, networkID, options)
+	}
+	return types.NetworkResource{}, nil
+}
+
+func (c *fakeClient) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	if c.containerInspectFunc != nil {
+		return c.containerInspectFunc(ctx, container)
+	}
+	return types.ContainerJSON{}, nil
+}
@@ -0,0 +1,74 @@
+package network
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// networkConnection is the connection of a container to a network, combining
+// the endpoint of the network with the settings of the container.
+type networkConnection struct {
+	Container    string
+	Name         string
+	EndpointID   string
+	MacAddress   string
+	IPv4Address  string
+	IPv6Address  string
+	Aliases      []string `json:",omitempty"`
+	Links        []string `json:",omitempty"`
+	LinkLocalIPs []string `json:",omitempty"`
+}
+
+// networkWithConnections is a network with the details of the connections of
+// its containers.
+type networkWithConnections struct {
+	types.NetworkResource
+	Connections []networkConnection
+}
+
+// inspectNetworkConnections inspects the network, and the containers connected
+// to all its endpoints. Endpoints which are not containers of this host, such
+// as the endpoints of other nodes of an overlay network, only have the details
+// known by the network.
+func inspectNetworkConnections(ctx context.Context, apiClient client.APIClient, name string, options types.NetworkInspectOptions) (networkWithConnections, error) {
+	nw, err := apiClient.NetworkInspect(ctx, name, options)
+	if err != nil {
+		return networkWithConnections{}, err
+	}
+	result := networkWithConnections{NetworkResource: nw, Connections: []networkConnection{}}
+	for id, endpoint := range nw.Containers {
+		conn := networkConnection{
+			Container:   id,
+			Name:        endpoint.Name,
+			EndpointID:  endpoint.EndpointID,
+			MacAddress:  endpoint.MacAddress,
+			IPv4Address: endpoint.IPv4Address,
+			IPv6Address: endpoint.IPv6Address,
+		}
+		c, err := apiClient.ContainerInspect(ctx, id)
+		switch {
+		case client.IsErrNotFound(err):
+		case err != nil:
+			return networkWithConnections{}, err
+		case c.NetworkSettings != nil:
+			if settings, ok := c.NetworkSettings.Networks[nw.Name]; ok && settings != nil {
+				conn.Aliases = settings.Aliases
+				conn.Links = settings.Links
+				if settings.IPAMConfig != nil {
+					conn.LinkLocalIPs = settings.IPAMConfig.LinkLocalIPs
+				}
+			}
+		}
+		result.Connections = append(result.Connections, conn)
+	}
+	sort.Slice(result.Connections, func(i, j int) bool {
+		if result.Connections[i].Name != result.Connections[j].Name {
+			return result.Connections[i].Name < result.Connections[j].Name
+		}
+		return result.Connections[i].Container < result.Connections[j].Container
+	})
+	return result, nil
+}
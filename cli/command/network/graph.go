@@ -0,0 +1,48 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// graphFormatDot is the Graphviz format of the graph of the networks
+const graphFormatDot = "dot"
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns s as a quoted string of the DOT language.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+// writeDotGraph writes the topology of the networks and of their containers
+// as an undirected graph of the DOT language. Containers connected to several
+// of the networks have a single node.
+func writeDotGraph(out io.Writer, networks []networkWithConnections) error {
+	var b strings.Builder
+	b.WriteString("graph networks {\n")
+	containers := map[string]bool{}
+	for _, nw := range networks {
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=box];\n", dotQuote("network:"+nw.ID), dotQuote(nw.Name+"\n("+nw.Driver+")"))
+		for _, conn := range nw.Connections {
+			if containers[conn.Container] {
+				continue
+			}
+			containers[conn.Container] = true
+			fmt.Fprintf(&b, "\t%s [label=%s];\n", dotQuote("container:"+conn.Container), dotQuote(strings.TrimPrefix(conn.Name, "/")))
+		}
+	}
+	for _, nw := range networks {
+		for _, conn := range nw.Connections {
+			label := conn.IPv4Address
+			if conn.IPv6Address != "" {
+				label = strings.TrimPrefix(label+"\n"+conn.IPv6Address, "\n")
+			}
+			fmt.Fprintf(&b, "\t%s -- %s [label=%s];\n", dotQuote("network:"+nw.ID), dotQuote("container:"+conn.Container), dotQuote(label))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(out, b.String())
+	return err
+}
@@ -7,13 +7,16 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/inspect"
 	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type inspectOptions struct {
-	format  string
-	names   []string
-	verbose bool
+	format      string
+	names       []string
+	verbose     bool
+	connections bool
+	graph       string
 }
 
 func newInspectCommand(dockerCli command.Cli) *cobra.Command {
@@ -31,6 +34,8 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 
 	cmd.Flags().StringVarP(&opts.format, "format", "f", "", "Format the output using the given Go template")
 	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "Verbose output for diagnostics")
+	cmd.Flags().BoolVar(&opts.connections, "connections", false, "Include the details of the connections of the containers")
+	cmd.Flags().StringVar(&opts.graph, "graph", "", `Print the topology of the networks and their containers as a graph ("dot")`)
 
 	return cmd
 }
@@ -40,9 +45,40 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 
 	ctx := context.Background()
 
+	if opts.graph != "" {
+		return runInspectGraph(ctx, dockerCli, opts)
+	}
+
+	if opts.connections {
+		getNetFunc := func(name string) (interface{}, []byte, error) {
+			nw, err := inspectNetworkConnections(ctx, client, name, types.NetworkInspectOptions{Verbose: opts.verbose})
+			return nw, nil, err
+		}
+		return inspect.Inspect(dockerCli.Out(), opts.names, opts.format, getNetFunc)
+	}
+
 	getNetFunc := func(name string) (interface{}, []byte, error) {
 		return client.NetworkInspectWithRaw(ctx, name, types.NetworkInspectOptions{Verbose: opts.verbose})
 	}
 
 	return inspect.Inspect(dockerCli.Out(), opts.names, opts.format, getNetFunc)
 }
+
+func runInspectGraph(ctx context.Context, dockerCli command.Cli, opts inspectOptions) error {
+	if opts.graph != graphFormatDot {
+		return errors.Errorf("invalid graph format %q: only %q is supported", opts.graph, graphFormatDot)
+	}
+	if opts.format != "" {
+		return errors.New("Conflicting options: --graph and --format")
+	}
+
+	var networks []networkWithConnections
+	for _, name := range opts.names {
+		nw, err := inspectNetworkConnections(ctx, dockerCli.Client(), name, types.NetworkInspectOptions{Verbose: opts.verbose})
+		if err != nil {
+			return err
+		}
+		networks = append(networks, nw)
+	}
+	return writeDotGraph(dockerCli.Out(), networks)
+}
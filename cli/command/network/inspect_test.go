@@ -0,0 +1,124 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+type notFound struct {
+	object string
+	id     string
+}
+
+func (n notFound) Error() string {
+	return fmt.Sprintf("Error: No such %s: %s", n.object, n.id)
+}
+
+func (n notFound) NotFound() bool {
+	return true
+}
+
+func fakeNetworkInspect(ctx context.Context, networkID string, options types.NetworkInspectOptions) (types.NetworkResource, error) {
+	switch networkID {
+	case "frontend":
+		return types.NetworkResource{
+			ID:     "frontend-id",
+			Name:   "frontend",
+			Driver: "bridge",
+			Containers: map[string]types.EndpointResource{
+				"web-id": {Name: "web", EndpointID: "ep-1", MacAddress: "02:42:ac:12:00:02", IPv4Address: "172.18.0.2/16"},
+			},
+		}, nil
+	case "backend":
+		return types.NetworkResource{
+			ID:     "backend-id",
+			Name:   "backend",
+			Driver: "overlay",
+			Containers: map[string]types.EndpointResource{
+				"web-id":  {Name: "web", EndpointID: "ep-2", IPv4Address: "10.0.0.3/24", IPv6Address: "fd00::3/64"},
+				"lb-back": {Name: "backend-endpoint", EndpointID: "ep-3", IPv4Address: "10.0.0.2/24"},
+				"db-id":   {Name: "db", EndpointID: "ep-4", IPv4Address: "10.0.0.4/24"},
+			},
+		}, nil
+	}
+	return types.NetworkResource{}, notFound{object: "network", id: networkID}
+}
+
+func fakeContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
+	switch container {
+	case "web-id":
+		return types.ContainerJSON{NetworkSettings: &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"frontend": {Aliases: []string{"www"}, IPAMConfig: &network.EndpointIPAMConfig{LinkLocalIPs: []string{"169.254.0.2"}}},
+			"backend":  {Aliases: []string{"web-backend"}, Links: []string{"/db:/web/db"}},
+		}}}, nil
+	case "db-id":
+		return types.ContainerJSON{NetworkSettings: &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+			"backend": {Aliases: []string{"database"}},
+		}}}, nil
+	}
+	return types.ContainerJSON{}, notFound{object: "container", id: container}
+}
+
+func TestNetworkInspectConnections(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		networkInspectFunc:   fakeNetworkInspect,
+		containerInspectFunc: fakeContainerInspect,
+	})
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"--connections", "--format", "{{range .Connections}}{{.Name}} {{.IPv4Address}} {{.Aliases}} {{.Links}} {{.LinkLocalIPs}}\n{{end}}", "frontend", "backend"})
+	assert.NilError(t, cmd.Execute())
+	expected := `web 172.18.0.2/16 [www] [] [169.254.0.2]
+
+backend-endpoint 10.0.0.2/24 [] [] []
+db 10.0.0.4/24 [database] [] []
+web 10.0.0.3/24 [web-backend] [/db:/web/db] []
+
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestNetworkInspectGraph(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		networkInspectFunc:   fakeNetworkInspect,
+		containerInspectFunc: fakeContainerInspect,
+	})
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"--graph", "dot", "frontend", "backend"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "network-inspect-graph.golden")
+}
+
+func TestNetworkInspectGraphErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--graph", "svg", "frontend"},
+			expectedError: `invalid graph format "svg"`,
+		},
+		{
+			args:          []string{"--graph", "dot", "--format", "{{.Name}}", "frontend"},
+			expectedError: "Conflicting options: --graph and --format",
+		},
+		{
+			args:          []string{"--graph", "dot", "unknown"},
+			expectedError: "Error: No such network: unknown",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newInspectCommand(test.NewFakeCli(&fakeClient{networkInspectFunc: fakeNetworkInspect}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
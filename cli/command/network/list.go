@@ -63,10 +63,11 @@ func runList(dockerCli command.Cli, options listOptions) error {
 		return sortorder.NaturalLess(networkResources[i].Name, networkResources[j].Name)
 	})
 
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	networksCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
-		Trunc:  !options.noTrunc,
+		Trunc:  truncation.Trunc(options.noTrunc),
 	}
 	return FormatWrite(networksCtx, networkResources)
 }
@@ -11,8 +11,9 @@ import (
 )
 
 type pruneOptions struct {
-	force  bool
-	filter opts.FilterOpt
+	force          bool
+	forceProtected bool
+	filter         opts.FilterOpt
 }
 
 // NewPruneCommand returns a new cobra prune command for networks
@@ -39,6 +40,7 @@ func NewPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.Var(&options.filter, "filter", "Provide filter values (e.g. 'until=<timestamp>')")
+	command.AddForceProtectedFlag(flags, &options.forceProtected)
 
 	return cmd
 }
@@ -47,9 +49,13 @@ const warning = `WARNING! This will remove all networks not used by at least one
 Are you sure you want to continue?`
 
 func runPrune(dockerCli command.Cli, options pruneOptions) (output string, err error) {
-	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value())
+	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value().Clone())
+	pruneFilters, err = command.ExcludeProtected(pruneFilters, options.forceProtected, command.ProtectedNetworks(context.Background(), dockerCli.Client()))
+	if err != nil {
+		return "", err
+	}
 
-	if !options.force && !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), warning) {
+	if !command.ConfirmPrune(dockerCli, options.force, warning, "network") {
 		return "", nil
 	}
 
@@ -74,3 +80,11 @@ func RunPrune(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, s
 	output, err := runPrune(dockerCli, pruneOptions{force: true, filter: filter})
 	return 0, output, err
 }
+
+// RunPruneForceProtected calls the Network Prune API, which also removes the
+// networks protected by the protection label
+// This returns the amount of space reclaimed and a detailed output string
+func RunPruneForceProtected(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error) {
+	output, err := runPrune(dockerCli, pruneOptions{force: true, forceProtected: true, filter: filter})
+	return 0, output, err
+}
@@ -10,16 +10,26 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type removeOptions struct {
+	forceProtected bool
+}
+
 func newRemoveCommand(dockerCli command.Cli) *cobra.Command {
-	return &cobra.Command{
-		Use:     "rm NETWORK [NETWORK...]",
+	var opts removeOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm [OPTIONS] NETWORK [NETWORK...]",
 		Aliases: []string{"remove"},
 		Short:   "Remove one or more networks",
 		Args:    cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRemove(dockerCli, args)
+			return runRemove(dockerCli, opts, args)
 		},
 	}
+
+	flags := cmd.Flags()
+	command.AddForceProtectedFlag(flags, &opts.forceProtected)
+	return cmd
 }
 
 const ingressWarning = "WARNING! Before removing the routing-mesh network, " +
@@ -27,16 +37,21 @@ const ingressWarning = "WARNING! Before removing the routing-mesh network, " +
 	"Otherwise, removal may not be effective and functionality of newly create " +
 	"ingress networks will be impaired.\nAre you sure you want to continue?"
 
-func runRemove(dockerCli command.Cli, networks []string) error {
+func runRemove(dockerCli command.Cli, opts removeOptions, networks []string) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 	status := 0
 
 	for _, name := range networks {
-		if nw, _, err := client.NetworkInspectWithRaw(ctx, name, types.NetworkInspectOptions{}); err == nil &&
-			nw.Ingress &&
-			!command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), ingressWarning) {
-			continue
+		if nw, _, err := client.NetworkInspectWithRaw(ctx, name, types.NetworkInspectOptions{}); err == nil {
+			if err := command.CheckProtected("network", name, nw.Labels, opts.forceProtected); err != nil {
+				fmt.Fprintf(dockerCli.Err(), "%s\n", err)
+				status = 1
+				continue
+			}
+			if nw.Ingress && !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), ingressWarning) {
+				continue
+			}
 		}
 		if err := client.NetworkRemove(ctx, name); err != nil {
 			fmt.Fprintf(dockerCli.Err(), "%s\n", err)
@@ -66,7 +66,7 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	}
 
 	if err := InspectFormatWrite(nodeCtx, opts.nodeIds, getRef); err != nil {
-		return cli.StatusError{StatusCode: 1, Status: err.Error()}
+		return cli.NewStatusError(err)
 	}
 	return nil
 }
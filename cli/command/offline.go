@@ -0,0 +1,292 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/redact"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/ioutils"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of the responses of the daemon stored in the offline cache
+const (
+	offlineContainers = "containers"
+	offlineImages     = "images"
+	offlineContainer  = "container"
+	offlineImage      = "image"
+)
+
+// The offline cache keeps at most offlineCacheMaxEntries responses, and
+// removes the responses older than offlineCacheMaxAge
+const (
+	offlineCacheMaxEntries = 200
+	offlineCacheMaxAge     = 7 * 24 * time.Hour
+)
+
+type offlineCacheKey struct{}
+
+// WithOfflineCache returns a context whose requests listing and inspecting the
+// containers and the images are stored in the offline cache, if it is enabled,
+// such as those of "docker images", "docker ps -a", and "docker inspect". The
+// requests made by the other commands are not stored.
+func WithOfflineCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, offlineCacheKey{}, true)
+}
+
+func isOfflineCached(ctx context.Context) bool {
+	cached, _ := ctx.Value(offlineCacheKey{}).(bool)
+	return cached
+}
+
+// offlineCache stores the responses of the daemon to the requests listing and
+// inspecting the containers and the images, so that they can be served when
+// the daemon is unreachable. The responses are stored by daemon host.
+type offlineCache struct {
+	dir  string
+	host string
+}
+
+// offlineEntry is a response stored in the offline cache
+type offlineEntry struct {
+	Time time.Time       `json:"time"`
+	Host string          `json:"host"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (c *offlineCache) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(c.host + "\x00" + kind + "\x00" + key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// store stores the response of a request, with its secrets, such as the
+// environment variables of the containers, redacted. The responses are only
+// readable by the user.
+func (c *offlineCache) store(kind, key string, data []byte) error {
+	data, err := redact.JSON(data)
+	if err != nil {
+		return err
+	}
+	entry, err := json.Marshal(offlineEntry{Time: time.Now().UTC(), Host: c.host, Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(c.path(kind, key), entry, 0600); err != nil {
+		return err
+	}
+	return c.prune()
+}
+
+// prune removes the responses older than offlineCacheMaxAge, and the oldest
+// responses beyond offlineCacheMaxEntries
+func (c *offlineCache) prune() error {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+	var kept int
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		if kept < offlineCacheMaxEntries && time.Since(f.ModTime()) < offlineCacheMaxAge {
+			kept++
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, f.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// load returns the stored response of a request
+func (c *offlineCache) load(kind, key string) (offlineEntry, error) {
+	var entry offlineEntry
+	data, err := ioutil.ReadFile(c.path(kind, key))
+	if err != nil {
+		return entry, err
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, errors.Wrap(err, "invalid offline cache entry")
+	}
+	return entry, nil
+}
+
+// offlineAPIClient is an API client which stores the responses of the daemon
+// listing and inspecting the containers and the images in the offline cache,
+// if it is enabled, and serves them from the cache, with a warning, if the
+// daemon is unreachable in offline mode.
+type offlineAPIClient struct {
+	*client.Client
+	dockerCli Cli
+	cache     *offlineCache
+	enabled   bool
+	offline   bool
+	warnOnce  sync.Once
+}
+
+// newOfflineAPIClient returns the API client of the CLI wrapped by the
+// offline cache if the cache is enabled in the configuration file, or in
+// offline mode
+func newOfflineAPIClient(dockerCli Cli, apiClient client.APIClient, offline bool, dir string) client.APIClient {
+	c, ok := apiClient.(*client.Client)
+	enabled := dockerCli.ConfigFile().OfflineCache == "enabled"
+	if !ok || (!enabled && !offline) {
+		return apiClient
+	}
+	return &offlineAPIClient{
+		Client:    c,
+		dockerCli: dockerCli,
+		cache:     &offlineCache{dir: dir, host: c.DaemonHost()},
+		enabled:   enabled,
+		offline:   offline,
+	}
+}
+
+// isDaemonUnreachable returns whether the error of a request is a failure to
+// reach the daemon, rather than an error returned by the daemon
+func isDaemonUnreachable(err error) bool {
+	if client.IsErrConnectionFailed(err) {
+		return true
+	}
+	_, ok := errors.Cause(err).(*url.Error)
+	return ok
+}
+
+// save stores a successful response of a request whose context is marked by
+// WithOfflineCache, if the cache is enabled. Failures to store the responses
+// are only logged.
+func (c *offlineAPIClient) save(ctx context.Context, kind, key string, data []byte) {
+	if !c.enabled || !isOfflineCached(ctx) {
+		return
+	}
+	if err := c.cache.store(kind, key, data); err != nil {
+		logrus.Debugf("failed to store the %s in the offline cache: %v", kind, err)
+	}
+}
+
+func (c *offlineAPIClient) saveJSON(ctx context.Context, kind, key string, v interface{}) {
+	if !c.enabled || !isOfflineCached(ctx) {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		logrus.Debugf("failed to store the %s in the offline cache: %v", kind, err)
+		return
+	}
+	c.save(ctx, kind, key, data)
+}
+
+// fallback returns the stored response of a request which failed because the
+// daemon is unreachable, in offline mode. It warns once that the output is
+// stale.
+func (c *offlineAPIClient) fallback(ctx context.Context, kind, key string, err error) ([]byte, bool) {
+	if !c.offline || !isOfflineCached(ctx) || !isDaemonUnreachable(err) {
+		return nil, false
+	}
+	entry, loadErr := c.cache.load(kind, key)
+	if loadErr != nil {
+		logrus.Debugf("no %s in the offline cache: %v", kind, loadErr)
+		return nil, false
+	}
+	c.warnOnce.Do(func() {
+		PrintWarning(c.dockerCli, "The Docker daemon at %s is unreachable: showing the cached output of %s (%s ago), which may be stale",
+			c.cache.host, entry.Time.Local().Format(time.RFC3339), units.HumanDuration(time.Since(entry.Time)))
+	})
+	return entry.Data, true
+}
+
+// filtersKey returns the key of the filters of a list request
+func filtersKey(args filters.Args) string {
+	key, _ := filters.ToJSON(args)
+	return key
+}
+
+// ContainerList lists the containers, and stores them in the offline cache
+func (c *offlineAPIClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	containers, err := c.Client.ContainerList(ctx, options)
+	key := fmt.Sprintf("all=%t size=%t latest=%t since=%s before=%s limit=%d filters=%s", options.All, options.Size, options.Latest, options.Since, options.Before, options.Limit, filtersKey(options.Filters))
+	if err == nil {
+		c.saveJSON(ctx, offlineContainers, key, containers)
+		return containers, nil
+	}
+	if data, ok := c.fallback(ctx, offlineContainers, key, err); ok {
+		var cached []types.Container
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+	return containers, err
+}
+
+// ImageList lists the images, and stores them in the offline cache
+func (c *offlineAPIClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	images, err := c.Client.ImageList(ctx, options)
+	key := fmt.Sprintf("all=%t filters=%s", options.All, filtersKey(options.Filters))
+	if err == nil {
+		c.saveJSON(ctx, offlineImages, key, images)
+		return images, nil
+	}
+	if data, ok := c.fallback(ctx, offlineImages, key, err); ok {
+		var cached []types.ImageSummary
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+	return images, err
+}
+
+// ContainerInspectWithRaw inspects a container, and stores it in the offline
+// cache
+func (c *offlineAPIClient) ContainerInspectWithRaw(ctx context.Context, container string, getSize bool) (types.ContainerJSON, []byte, error) {
+	info, raw, err := c.Client.ContainerInspectWithRaw(ctx, container, getSize)
+	if err == nil {
+		c.save(ctx, offlineContainer, container, raw)
+		return info, raw, nil
+	}
+	if data, ok := c.fallback(ctx, offlineContainer, container, err); ok {
+		var cached types.ContainerJSON
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, data, nil
+		}
+	}
+	return info, raw, err
+}
+
+// ImageInspectWithRaw inspects an image, and stores it in the offline cache
+func (c *offlineAPIClient) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	info, raw, err := c.Client.ImageInspectWithRaw(ctx, image)
+	if err == nil {
+		c.save(ctx, offlineImage, image, raw)
+		return info, raw, nil
+	}
+	if data, ok := c.fallback(ctx, offlineImage, image, err); ok {
+		var cached types.ImageInspect
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, data, nil
+		}
+	}
+	return info, raw, err
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestOfflineAPIClient(t *testing.T) {
+	dir := fs.NewDir(t, "offline-cache")
+	defer dir.Remove()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.40/containers/json":
+			json.NewEncoder(w).Encode([]types.Container{{ID: "abc", Names: []string{"/web"}}})
+		case "/v1.40/images/alpine/json":
+			w.Write([]byte(`{"Id":"sha256:123","RepoTags":["alpine:latest"]}`))
+		case "/v1.40/containers/web/json":
+			w.Write([]byte(`{"Id":"abc","Config":{"Env":["DB_PASSWORD=secret","PATH=/bin"]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.40"))
+	assert.NilError(t, err)
+
+	errBuf := new(bytes.Buffer)
+	cli := &DockerCli{err: errBuf, configFile: &configfile.ConfigFile{OfflineCache: "enabled"}}
+	online := newOfflineAPIClient(cli, apiClient, false, dir.Path())
+	offline := newOfflineAPIClient(cli, apiClient, true, dir.Path())
+
+	ctx := WithOfflineCache(context.Background())
+	options := types.ContainerListOptions{All: true}
+	_, err = online.ContainerList(ctx, options)
+	assert.NilError(t, err)
+	_, _, err = online.ImageInspectWithRaw(ctx, "alpine")
+	assert.NilError(t, err)
+	_, _, err = online.ContainerInspectWithRaw(ctx, "web", false)
+	assert.NilError(t, err)
+	// The requests of the other commands are not stored
+	_, _, err = online.ImageInspectWithRaw(context.Background(), "alpine:latest")
+	assert.Check(t, is.ErrorContains(err, ""))
+	_, err = online.ContainerList(context.Background(), types.ContainerListOptions{})
+	assert.NilError(t, err)
+	server.Close()
+
+	// The responses are only served from the cache in offline mode
+	_, err = online.ContainerList(ctx, options)
+	assert.Check(t, is.ErrorContains(err, ""))
+
+	containers, err := offline.ContainerList(ctx, options)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(containers, []types.Container{{ID: "abc", Names: []string{"/web"}}}))
+	image, raw, err := offline.ImageInspectWithRaw(ctx, "alpine")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(image.ID, "sha256:123"))
+	assert.Check(t, is.Equal(string(raw), `{"Id":"sha256:123","RepoTags":["alpine:latest"]}`))
+	container, _, err := offline.ContainerInspectWithRaw(ctx, "web", false)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(container.Config.Env, []string{"DB_PASSWORD=<redacted>", "PATH=/bin"}))
+	assert.Check(t, is.Contains(errBuf.String(), "is unreachable: showing the cached output of"))
+	assert.Check(t, is.Equal(bytes.Count(errBuf.Bytes(), []byte("WARNING")), 1))
+
+	// The requests which were not cached fail
+	_, err = offline.ContainerList(ctx, types.ContainerListOptions{})
+	assert.Check(t, isDaemonUnreachable(err), err)
+	_, _, err = offline.ImageInspectWithRaw(context.Background(), "alpine")
+	assert.Check(t, isDaemonUnreachable(err), err)
+}
+
+func TestOfflineAPIClientDisabled(t *testing.T) {
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:2375"))
+	assert.NilError(t, err)
+	cli := &DockerCli{configFile: &configfile.ConfigFile{}}
+	assert.Check(t, is.Equal(newOfflineAPIClient(cli, apiClient, false, ""), client.APIClient(apiClient)))
+}
+
+func TestOfflineCachePrune(t *testing.T) {
+	dir := fs.NewDir(t, "offline-cache")
+	defer dir.Remove()
+
+	cache := &offlineCache{dir: dir.Path(), host: "unix:///var/run/docker.sock"}
+	assert.NilError(t, cache.store(offlineImage, "old", []byte(`{}`)))
+	old := time.Now().Add(-offlineCacheMaxAge - time.Hour)
+	assert.NilError(t, os.Chtimes(cache.path(offlineImage, "old"), old, old))
+	for i := 0; i < offlineCacheMaxEntries+1; i++ {
+		assert.NilError(t, cache.store(offlineImage, strconv.Itoa(i), []byte(`{}`)))
+	}
+
+	files, err := ioutil.ReadDir(dir.Path())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(files, offlineCacheMaxEntries))
+	_, err = cache.load(offlineImage, "old")
+	assert.Check(t, os.IsNotExist(err), err)
+}
@@ -61,6 +61,14 @@ func NormalizeOrchestrator(value string) (Orchestrator, error) {
 // GetStackOrchestrator checks DOCKER_STACK_ORCHESTRATOR environment variable and configuration file
 // orchestrator value and returns user defined Orchestrator.
 func GetStackOrchestrator(flagValue, contextValue, globalDefault string, stderr io.Writer) (Orchestrator, error) {
+	return getStackOrchestrator(flagValue, contextValue, globalDefault, func(format string, args ...interface{}) {
+		fmt.Fprintf(stderr, "WARNING: "+format+"\n", args...)
+	})
+}
+
+// getStackOrchestrator returns the user defined Orchestrator, as
+// GetStackOrchestrator does, and prints its warnings with warn
+func getStackOrchestrator(flagValue, contextValue, globalDefault string, warn func(format string, args ...interface{})) (Orchestrator, error) {
 	// Check flag
 	if o, err := normalize(flagValue); o != orchestratorUnset {
 		return o, err
@@ -68,7 +76,7 @@ func GetStackOrchestrator(flagValue, contextValue, globalDefault string, stderr
 	// Check environment variable
 	env := os.Getenv(envVarDockerStackOrchestrator)
 	if env == "" && os.Getenv(envVarDockerOrchestrator) != "" {
-		fmt.Fprintf(stderr, "WARNING: experimental environment variable %s is set. Please use %s instead\n", envVarDockerOrchestrator, envVarDockerStackOrchestrator)
+		warn("experimental environment variable %s is set. Please use %s instead", envVarDockerOrchestrator, envVarDockerStackOrchestrator)
 	}
 	if o, err := normalize(env); o != orchestratorUnset {
 		return o, err
@@ -36,7 +36,7 @@ func NewFormat(source string, quiet bool) formatter.Format {
 func FormatWrite(ctx formatter.Context, plugins []*types.Plugin) error {
 	render := func(format func(subContext formatter.SubContext) error) error {
 		for _, plugin := range plugins {
-			pluginCtx := &pluginContext{trunc: ctx.Trunc, p: *plugin}
+			pluginCtx := &pluginContext{trunc: ctx.Trunc, widths: ctx.Widths, p: *plugin}
 			if err := format(pluginCtx); err != nil {
 				return err
 			}
@@ -56,8 +56,9 @@ func FormatWrite(ctx formatter.Context, plugins []*types.Plugin) error {
 
 type pluginContext struct {
 	formatter.HeaderContext
-	trunc bool
-	p     types.Plugin
+	trunc  bool
+	widths map[string]int
+	p      types.Plugin
 }
 
 func (c *pluginContext) MarshalJSON() ([]byte, error) {
@@ -79,7 +80,7 @@ func (c *pluginContext) Description() string {
 	desc := strings.Replace(c.p.Config.Description, "\n", "", -1)
 	desc = strings.Replace(desc, "\r", "", -1)
 	if c.trunc {
-		desc = formatter.Ellipsis(desc, 45)
+		desc = formatter.TruncateColumn(desc, c.widths, "Description", 45)
 	}
 
 	return desc
@@ -61,10 +61,12 @@ func runList(dockerCli command.Cli, options listOptions) error {
 		}
 	}
 
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	pluginsCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewFormat(format, options.quiet),
-		Trunc:  !options.noTrunc,
+		Trunc:  truncation.Trunc(options.noTrunc),
+		Widths: truncation.Widths("plugins"),
 	}
 	return FormatWrite(pluginsCtx, plugins)
 }
@@ -0,0 +1,62 @@
+package preset
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type addOptions struct {
+	name  string
+	flags string
+	force bool
+}
+
+func newAddCommand(dockerCli command.Cli) *cobra.Command {
+	var options addOptions
+	cmd := &cobra.Command{
+		Use:   "add [OPTIONS] NAME FLAGS",
+		Short: "Add a preset of flags",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.name = args[0]
+			options.flags = args[1]
+			return runAdd(dockerCli, cmd.Root(), options)
+		},
+	}
+	flags := cmd.Flags()
+	// The flags of the preset are not flags of the command
+	flags.SetInterspersed(false)
+	flags.BoolVarP(&options.force, "force", "f", false, "Replace the preset if it already exists")
+	return cmd
+}
+
+func runAdd(dockerCli command.Cli, root *cobra.Command, options addOptions) error {
+	if err := opts.ValidatePresetName(options.name); err != nil {
+		return err
+	}
+	flags, err := runFlags(root)
+	if err != nil {
+		return err
+	}
+	if _, err := opts.ParsePreset(options.flags, flags); err != nil {
+		return errors.Wrapf(err, "invalid preset %q", options.name)
+	}
+	cfg := dockerCli.ConfigFile()
+	if _, exists := cfg.RunPresets[options.name]; exists && !options.force {
+		return errors.Errorf("preset %q already exists, use --force to replace it", options.name)
+	}
+	if cfg.RunPresets == nil {
+		cfg.RunPresets = map[string]string{}
+	}
+	cfg.RunPresets[options.name] = options.flags
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), options.name)
+	return nil
+}
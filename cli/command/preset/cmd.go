@@ -0,0 +1,37 @@
+package preset
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewPresetCommand returns the preset cli subcommand
+func NewPresetCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Manage the presets of flags of docker run and docker create",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newAddCommand(dockerCli),
+		newListCommand(dockerCli),
+		newShowCommand(dockerCli),
+		newRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+// runFlags returns the flags of "docker run", which the flags of the presets
+// are validated against
+func runFlags(root *cobra.Command) (*pflag.FlagSet, error) {
+	run, _, err := root.Find([]string{"run"})
+	if err != nil || run.Flags().Lookup(opts.PresetFlag) == nil {
+		return nil, errors.New("the presets are only supported by the docker command")
+	}
+	return run.Flags(), nil
+}
@@ -0,0 +1,52 @@
+package preset
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet bool
+}
+
+func newListCommand(dockerCli command.Cli) *cobra.Command {
+	var options listOptions
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the presets of flags",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, options)
+		},
+	}
+	cmd.Flags().BoolVarP(&options.quiet, "quiet", "q", false, "Only show preset names")
+	return cmd
+}
+
+func runList(dockerCli command.Cli, options listOptions) error {
+	presets := dockerCli.ConfigFile().RunPresets
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if options.quiet {
+		for _, name := range names {
+			fmt.Fprintln(dockerCli.Out(), name)
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFLAGS")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, presets[name])
+	}
+	return w.Flush()
+}
@@ -0,0 +1,112 @@
+package preset
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func newTestCli(t *testing.T, dir *fs.Dir) *test.FakeCli {
+	t.Helper()
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(configfile.New(filepath.Join(dir.Path(), "config.json")))
+	return cli
+}
+
+func newTestRoot(cli *test.FakeCli) *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	run := &cobra.Command{Use: "run", Run: func(*cobra.Command, []string) {}}
+	run.Flags().StringArrayP("volume", "v", nil, "")
+	run.Flags().StringArrayP("env", "e", nil, "")
+	run.Flags().StringArray("security-opt", nil, "")
+	run.Flags().StringArray(opts.PresetFlag, nil, "")
+	run.Flags().BoolP("interactive", "i", false, "")
+	run.Flags().BoolP("tty", "t", false, "")
+	root.AddCommand(run, NewPresetCommand(cli))
+	return root
+}
+
+func TestAddListShowRemove(t *testing.T) {
+	dir := fs.NewDir(t, "preset")
+	defer dir.Remove()
+	cli := newTestCli(t, dir)
+	root := newTestRoot(cli)
+
+	for _, args := range [][]string{
+		{"preset", "add", "dev", "-v /src:/src -e DEBUG=1"},
+		{"preset", "add", "safe", "--security-opt no-new-privileges"},
+		{"preset", "add", "--force", "dev", `-it -v "/my src:/src" -e DEBUG=1`},
+	} {
+		root.SetArgs(args)
+		assert.NilError(t, root.Execute())
+	}
+	assert.Check(t, is.DeepEqual(map[string]string{
+		"dev":  `-it -v "/my src:/src" -e DEBUG=1`,
+		"safe": "--security-opt no-new-privileges",
+	}, cli.ConfigFile().RunPresets))
+
+	cli.OutBuffer().Reset()
+	root.SetArgs([]string{"preset", "ls"})
+	assert.NilError(t, root.Execute())
+	assert.Check(t, is.Equal(`NAME   FLAGS
+dev    -it -v "/my src:/src" -e DEBUG=1
+safe   --security-opt no-new-privileges
+`, cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	root.SetArgs([]string{"preset", "ls", "-q"})
+	assert.NilError(t, root.Execute())
+	assert.Check(t, is.Equal("dev\nsafe\n", cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	root.SetArgs([]string{"preset", "show", "dev"})
+	assert.NilError(t, root.Execute())
+	assert.Check(t, is.Equal("-it\n-v '/my src:/src'\n-e DEBUG=1\n", cli.OutBuffer().String()))
+
+	cli.OutBuffer().Reset()
+	root.SetArgs([]string{"preset", "rm", "safe", "unknown"})
+	assert.Check(t, is.Error(root.Execute(), "unknown: no such preset"))
+	assert.Check(t, is.Equal("safe\n", cli.OutBuffer().String()))
+
+	// The presets are saved in the configuration file
+	b, err := ioutil.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	saved := configfile.New(cli.ConfigFile().Filename)
+	assert.NilError(t, saved.LoadFromReader(bytes.NewReader(b)))
+	assert.Check(t, is.DeepEqual(map[string]string{"dev": `-it -v "/my src:/src" -e DEBUG=1`}, saved.RunPresets))
+}
+
+func TestAddErrors(t *testing.T) {
+	dir := fs.NewDir(t, "preset")
+	defer dir.Remove()
+	cli := newTestCli(t, dir)
+	cli.ConfigFile().RunPresets = map[string]string{"dev": "-it"}
+
+	testCases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{args: []string{"add", "my dev", "-it"}, expectedErr: `preset name "my dev" is invalid`},
+		{args: []string{"add", "dev", "-it"}, expectedErr: `preset "dev" already exists, use --force to replace it`},
+		{args: []string{"add", "img", "-it busybox"}, expectedErr: `invalid preset "img": unexpected argument "busybox": a preset only contains flags`},
+		{args: []string{"add", "nested", "--preset dev"}, expectedErr: `invalid preset "nested": a preset cannot apply other presets`},
+		{args: []string{"add", "bad", "--unknown"}, expectedErr: `invalid preset "bad": unknown flag: --unknown`},
+		{args: []string{"show", "unknown"}, expectedErr: "no such preset: unknown"},
+	}
+	for _, tc := range testCases {
+		root := newTestRoot(cli)
+		root.SetArgs(append([]string{"preset"}, tc.args...))
+		root.SetOutput(ioutil.Discard)
+		assert.Check(t, is.ErrorContains(root.Execute(), tc.expectedErr), tc.args)
+	}
+	assert.Check(t, is.DeepEqual(map[string]string{"dev": "-it"}, cli.ConfigFile().RunPresets))
+}
@@ -0,0 +1,79 @@
+package preset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newShowCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show NAME",
+		Short: "Show the flags of a preset, one per line",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShow(dockerCli, cmd.Root(), args[0])
+		},
+	}
+}
+
+func runShow(dockerCli command.Cli, root *cobra.Command, name string) error {
+	value, ok := dockerCli.ConfigFile().RunPresets[name]
+	if !ok {
+		return errors.Errorf("no such preset: %s", name)
+	}
+	flags, err := runFlags(root)
+	if err != nil {
+		return err
+	}
+	words, err := opts.ParsePreset(value, flags)
+	if err != nil {
+		return errors.Wrapf(err, "invalid preset %q", name)
+	}
+	for _, line := range flagLines(words, flags) {
+		fmt.Fprintln(dockerCli.Out(), line)
+	}
+	return nil
+}
+
+// flagLines returns the flags of a preset, with their values, such as
+// "--volume /data:/data"
+func flagLines(words []string, flags *pflag.FlagSet) []string {
+	var lines []string
+	for i := 0; i < len(words); i++ {
+		line := words[i]
+		if takesValue(words[i], flags) && i+1 < len(words) {
+			line += " " + quote(words[i+1])
+			i++
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// takesValue returns whether the flag of a valid preset, such as "-v", takes
+// its value in the next word
+func takesValue(word string, flags *pflag.FlagSet) bool {
+	if strings.HasPrefix(word, "--") {
+		f := flags.Lookup(strings.TrimPrefix(word, "--"))
+		return f != nil && f.NoOptDefVal == ""
+	}
+	if len(word) < 2 {
+		return false
+	}
+	f := flags.ShorthandLookup(word[len(word)-1:])
+	return f != nil && f.NoOptDefVal == "" && !strings.ContainsAny(word[1:len(word)-1], "=")
+}
+
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\"'\\$") {
+		return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+	}
+	return value
+}
@@ -0,0 +1,189 @@
+package command
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// ProtectedLabel is the label of the containers, images, networks, and
+// volumes which the rm and prune commands refuse to remove, if its value is
+// "true", unless the --force-protected flag is set.
+const ProtectedLabel = "cli.docker.io/protected"
+
+// AddForceProtectedFlag adds the --force-protected flag of the rm and prune
+// commands to a set of flags.
+func AddForceProtectedFlag(flags *pflag.FlagSet, target *bool) {
+	flags.BoolVar(target, "force-protected", false, "Also remove the objects protected by the "+ProtectedLabel+"=true label")
+}
+
+// IsProtected returns whether the labels of an object have the protection
+// label.
+func IsProtected(labels map[string]string) bool {
+	return labels[ProtectedLabel] == "true"
+}
+
+// CheckProtected returns an error if the labels of an object have the
+// protection label, unless forceProtected is set. The kind of the object is
+// the kind of its error, such as "container".
+func CheckProtected(kind, name string, labels map[string]string, forceProtected bool) error {
+	if forceProtected || !IsProtected(labels) {
+		return nil
+	}
+	return errdefs.Conflict(errors.Errorf("%s %s is protected by the %s=true label: use --force-protected to remove it", kind, name, ProtectedLabel))
+}
+
+// ProtectedLister returns the labels of the objects with the protection
+// label which a prune command could remove, by object, such as
+// "container db".
+type ProtectedLister func() (map[string]map[string]string, error)
+
+// ExcludeProtected adds a filter excluding the objects with the protection
+// label to prune filters, unless forceProtected is set. The daemon only
+// excludes the objects which match all the "label!" filters, so the filter
+// cannot be added if the prune filters already have one: the protected
+// objects are listed instead, and an error is returned if the prune filters
+// do not exclude all of them.
+func ExcludeProtected(pruneFilters filters.Args, forceProtected bool, listProtected ProtectedLister) (filters.Args, error) {
+	if forceProtected {
+		return pruneFilters, nil
+	}
+	if !pruneFilters.Contains("label!") {
+		pruneFilters.Add("label!", ProtectedLabel+"=true")
+		return pruneFilters, nil
+	}
+	protected, err := listProtected()
+	if err != nil {
+		return pruneFilters, err
+	}
+	var pruned []string
+	for object, labels := range protected {
+		if pruneFilters.MatchKVList("label", labels) && !pruneFilters.MatchKVList("label!", labels) {
+			pruned = append(pruned, object)
+		}
+	}
+	if len(pruned) > 0 {
+		sort.Strings(pruned)
+		return pruneFilters, errors.Errorf(`the "label!" filter cannot be combined with the protection of the objects with the %s=true label, and would prune %s: use --force-protected to also prune the protected objects`, ProtectedLabel, strings.Join(pruned, ", "))
+	}
+	return pruneFilters, nil
+}
+
+// protectedFilters returns the filters of the objects with the protection
+// label
+func protectedFilters(args ...filters.KeyValuePair) filters.Args {
+	return filters.NewArgs(append(args, filters.Arg("label", ProtectedLabel+"=true"))...)
+}
+
+// ProtectedContainers returns the lister of the protected containers which
+// "docker container prune" could remove, which are the stopped containers.
+func ProtectedContainers(ctx context.Context, apiClient client.APIClient) ProtectedLister {
+	return func() (map[string]map[string]string, error) {
+		containers, err := apiClient.ContainerList(ctx, types.ContainerListOptions{
+			All:     true,
+			Filters: protectedFilters(filters.Arg("status", "created"), filters.Arg("status", "exited"), filters.Arg("status", "dead")),
+		})
+		if err != nil {
+			return nil, err
+		}
+		protected := make(map[string]map[string]string, len(containers))
+		for _, c := range containers {
+			name := c.ID
+			if len(c.Names) > 0 {
+				name = strings.TrimPrefix(c.Names[0], "/")
+			}
+			protected["container "+name] = c.Labels
+		}
+		return protected, nil
+	}
+}
+
+// ProtectedImages returns the lister of the protected images which "docker
+// image prune" could remove, which are the dangling images unless all is set.
+func ProtectedImages(ctx context.Context, apiClient client.APIClient, all bool) ProtectedLister {
+	return func() (map[string]map[string]string, error) {
+		imageFilters := protectedFilters()
+		if !all {
+			imageFilters.Add("dangling", "true")
+		}
+		images, err := apiClient.ImageList(ctx, types.ImageListOptions{Filters: imageFilters})
+		if err != nil {
+			return nil, err
+		}
+		protected := make(map[string]map[string]string, len(images))
+		for _, img := range images {
+			name := img.ID
+			if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+				name = img.RepoTags[0]
+			}
+			protected["image "+name] = img.Labels
+		}
+		return protected, nil
+	}
+}
+
+// ProtectedNetworks returns the lister of the protected networks which
+// "docker network prune" could remove.
+func ProtectedNetworks(ctx context.Context, apiClient client.APIClient) ProtectedLister {
+	return func() (map[string]map[string]string, error) {
+		networks, err := apiClient.NetworkList(ctx, types.NetworkListOptions{Filters: protectedFilters()})
+		if err != nil {
+			return nil, err
+		}
+		protected := make(map[string]map[string]string, len(networks))
+		for _, n := range networks {
+			protected["network "+n.Name] = n.Labels
+		}
+		return protected, nil
+	}
+}
+
+// ProtectedVolumes returns the lister of the protected volumes which "docker
+// volume prune" could remove, which are the unused volumes.
+func ProtectedVolumes(ctx context.Context, apiClient client.APIClient) ProtectedLister {
+	return func() (map[string]map[string]string, error) {
+		volumes, err := apiClient.VolumeList(ctx, protectedFilters(filters.Arg("dangling", "true")))
+		if err != nil {
+			return nil, err
+		}
+		protected := make(map[string]map[string]string, len(volumes.Volumes))
+		for _, v := range volumes.Volumes {
+			protected["volume "+v.Name] = v.Labels
+		}
+		return protected, nil
+	}
+}
+
+// ConfirmPrune prompts for the confirmation of a prune command, unless force
+// is set and the configuration file does not require the confirmation of the
+// command, such as "volume" for "docker volume prune". It returns whether to
+// prune.
+func ConfirmPrune(dockerCli Cli, force bool, message string, commands ...string) bool {
+	if force && !isPruneConfirmationRequired(dockerCli, commands) {
+		return true
+	}
+	return PromptForConfirmation(dockerCli.In(), dockerCli.Out(), message)
+}
+
+// isPruneConfirmationRequired returns whether the configuration file requires
+// the confirmation of one of the prune commands.
+func isPruneConfirmationRequired(dockerCli Cli, commands []string) bool {
+	if dockerCli.ConfigFile() == nil {
+		return false
+	}
+	for _, required := range dockerCli.ConfigFile().PruneConfirmation {
+		for _, c := range commands {
+			if c == required {
+				return true
+			}
+		}
+	}
+	return false
+}
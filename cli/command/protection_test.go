@@ -0,0 +1,108 @@
+package command
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestCheckProtected(t *testing.T) {
+	protected := map[string]string{ProtectedLabel: "true"}
+	assert.Check(t, IsProtected(protected))
+	assert.Check(t, !IsProtected(map[string]string{ProtectedLabel: "false"}))
+	assert.Check(t, !IsProtected(nil))
+
+	err := CheckProtected("container", "web", protected, false)
+	assert.Check(t, is.Error(err, "container web is protected by the cli.docker.io/protected=true label: use --force-protected to remove it"))
+	assert.Check(t, errdefs.IsConflict(err))
+	assert.Check(t, CheckProtected("container", "web", protected, true))
+	assert.Check(t, CheckProtected("container", "web", nil, false))
+}
+
+func TestExcludeProtected(t *testing.T) {
+	listProtected := func(protected map[string]map[string]string) ProtectedLister {
+		return func() (map[string]map[string]string, error) {
+			return protected, nil
+		}
+	}
+	noProtected := func() (map[string]map[string]string, error) {
+		t.Fatal("the protected objects are listed")
+		return nil, nil
+	}
+	pruneFilters, err := ExcludeProtected(filters.NewArgs(filters.Arg("until", "24h")), false, noProtected)
+	assert.NilError(t, err)
+	assert.Check(t, pruneFilters.ExactMatch("label!", "cli.docker.io/protected=true"))
+	assert.Check(t, pruneFilters.ExactMatch("until", "24h"))
+
+	pruneFilters, err = ExcludeProtected(filters.NewArgs(), true, noProtected)
+	assert.NilError(t, err)
+	assert.Check(t, !pruneFilters.Contains("label!"))
+
+	// Nothing is protected, so the "label!" filter is kept as it is
+	pruneFilters, err = ExcludeProtected(filters.NewArgs(filters.Arg("label!", "keep")), false, listProtected(nil))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pruneFilters.Get("label!"), []string{"keep"}))
+
+	// The protected objects are excluded by the "label!" filter, or are not
+	// matched by the "label" filter
+	pruneFilters, err = ExcludeProtected(filters.NewArgs(filters.Arg("label!", "keep"), filters.Arg("label", "env=test")), false, listProtected(map[string]map[string]string{
+		"container db":  {ProtectedLabel: "true", "keep": ""},
+		"volume data":   {ProtectedLabel: "true", "env": "prod"},
+		"network other": {ProtectedLabel: "true"},
+	}))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pruneFilters.Get("label!"), []string{"keep"}))
+
+	_, err = ExcludeProtected(filters.NewArgs(filters.Arg("label!", "keep")), false, listProtected(map[string]map[string]string{
+		"container web": {ProtectedLabel: "true"},
+		"container db":  {ProtectedLabel: "true", "keep": ""},
+		"image app":     {ProtectedLabel: "true"},
+	}))
+	assert.Check(t, is.ErrorContains(err, `the "label!" filter cannot be combined with the protection of the objects with the cli.docker.io/protected=true label, and would prune container web, image app`))
+
+	_, err = ExcludeProtected(filters.NewArgs(filters.Arg("label!", "keep")), false, func() (map[string]map[string]string, error) {
+		return nil, errors.New("error listing")
+	})
+	assert.Check(t, is.Error(err, "error listing"))
+
+	pruneFilters, err = ExcludeProtected(filters.NewArgs(filters.Arg("label!", "keep")), true, noProtected)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pruneFilters.Get("label!"), []string{"keep"}))
+}
+
+func TestConfirmPrune(t *testing.T) {
+	testCases := []struct {
+		name              string
+		force             bool
+		pruneConfirmation []string
+		input             string
+		expected          bool
+		prompted          bool
+	}{
+		{name: "force", force: true, expected: true},
+		{name: "force-other-command-required", force: true, pruneConfirmation: []string{"image"}, expected: true},
+		{name: "force-required-yes", force: true, pruneConfirmation: []string{"volume"}, input: "y\n", expected: true, prompted: true},
+		{name: "force-required-no", force: true, pruneConfirmation: []string{"system"}, input: "n\n", prompted: true},
+		{name: "prompt-yes", input: "y\n", expected: true, prompted: true},
+		{name: "prompt-no", input: "n\n", prompted: true},
+	}
+	for _, tc := range testCases {
+		out := new(bytes.Buffer)
+		cli := &DockerCli{
+			in:         streams.NewIn(ioutil.NopCloser(strings.NewReader(tc.input))),
+			out:        streams.NewOut(out),
+			configFile: &configfile.ConfigFile{PruneConfirmation: tc.pruneConfirmation},
+		}
+		assert.Check(t, is.Equal(ConfirmPrune(cli, tc.force, "Are you sure?", "system", "volume"), tc.expected), tc.name)
+		assert.Check(t, is.Equal(strings.Contains(out.String(), "Are you sure? [y/N]"), tc.prompted), tc.name)
+	}
+}
@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"context"
+
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/reference"
+	registrytypes "github.com/docker/docker/api/types/registry"
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	getTagsFunc            func(ctx context.Context, ref reference.Named) ([]string, error)
+	getManifestContentFunc func(ctx context.Context, ref reference.Named) (registryclient.ManifestContent, error)
+	deleteManifestFunc     func(ctx context.Context, ref reference.Named) error
+	searchFunc             func(ctx context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (registryclient.SearchPage, error)
+}
+
+func (c *fakeRegistryClient) GetTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	if c.getTagsFunc != nil {
+		return c.getTagsFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) GetManifestContent(ctx context.Context, ref reference.Named) (registryclient.ManifestContent, error) {
+	if c.getManifestContentFunc != nil {
+		return c.getManifestContentFunc(ctx, ref)
+	}
+	return registryclient.ManifestContent{}, nil
+}
+
+func (c *fakeRegistryClient) DeleteManifest(ctx context.Context, ref reference.Named) error {
+	if c.deleteManifestFunc != nil {
+		return c.deleteManifestFunc(ctx, ref)
+	}
+	return nil
+}
+
+func (c *fakeRegistryClient) SearchRepositories(ctx context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (registryclient.SearchPage, error) {
+	if c.searchFunc != nil {
+		return c.searchFunc(ctx, index, term, page, pageSize)
+	}
+	return registryclient.SearchPage{}, nil
+}
@@ -0,0 +1,24 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// NewRegistryCommand returns a cobra command for `registry` subcommands
+func NewRegistryCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Inspect and manage the repositories of registries",
+		Long:  "Inspect and manage the repositories of registries. These commands contact the registries directly, with the credentials of \"docker login\", and do not require the daemon.",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newListTagsCommand(dockerCli),
+		newInspectCommand(dockerCli),
+		newRemoveTagCommand(dockerCli),
+	)
+	return cmd
+}
@@ -9,6 +9,10 @@ import (
 )
 
 const (
+	// jsonFormatKey is the format of "docker search" which prints the
+	// results as a JSON array, with all their fields
+	jsonFormatKey = "json"
+
 	defaultSearchTableFormat = "table {{.Name}}\t{{.Description}}\t{{.StarCount}}\t{{.IsOfficial}}\t{{.IsAutomated}}"
 
 	starsHeader     = "STARS"
@@ -35,7 +39,7 @@ func SearchWrite(ctx formatter.Context, results []registry.SearchResult, auto bo
 			if (auto && !result.IsAutomated) || (stars > result.StarCount) {
 				continue
 			}
-			searchCtx := &searchContext{trunc: ctx.Trunc, s: result}
+			searchCtx := &searchContext{trunc: ctx.Trunc, widths: ctx.Widths, s: result}
 			if err := format(searchCtx); err != nil {
 				return err
 			}
@@ -55,9 +59,10 @@ func SearchWrite(ctx formatter.Context, results []registry.SearchResult, auto bo
 
 type searchContext struct {
 	formatter.HeaderContext
-	trunc bool
-	json  bool
-	s     registry.SearchResult
+	trunc  bool
+	widths map[string]int
+	json   bool
+	s      registry.SearchResult
 }
 
 func (c *searchContext) MarshalJSON() ([]byte, error) {
@@ -73,7 +78,7 @@ func (c *searchContext) Description() string {
 	desc := strings.Replace(c.s.Description, "\n", " ", -1)
 	desc = strings.Replace(desc, "\r", " ", -1)
 	if c.trunc {
-		desc = formatter.Ellipsis(desc, 45)
+		desc = formatter.TruncateColumn(desc, c.widths, "Description", 45)
 	}
 	return desc
 }
@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/inspect"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	refs     []string
+	format   string
+	insecure bool
+}
+
+// manifestInfo is the manifest of a reference in its registry
+type manifestInfo struct {
+	Name      string
+	Digest    digest.Digest
+	MediaType string
+	Size      int
+	Manifest  json.RawMessage
+}
+
+func newInspectCommand(dockerCli command.Cli) *cobra.Command {
+	options := inspectOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] IMAGE[:TAG|@DIGEST] [IMAGE[:TAG|@DIGEST]...]",
+		Short: "Display the manifest of one or more images in their registry",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.refs = args
+			return runInspect(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&options.format, "format", "f", "", "Format the output using the given Go template")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runInspect(dockerCli command.Cli, options inspectOptions) error {
+	ctx := context.Background()
+	registryClient := dockerCli.RegistryClient(options.insecure)
+
+	getRefFunc := func(name string) (interface{}, []byte, error) {
+		ref, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		ref = reference.TagNameOnly(ref)
+		content, err := registryClient.GetManifestContent(ctx, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		info := manifestInfo{
+			Name:      reference.FamiliarString(ref),
+			Digest:    content.Digest,
+			MediaType: content.MediaType,
+			Size:      len(content.Payload),
+			Manifest:  content.Payload,
+		}
+		// The raw element allows templates to access the fields of the
+		// manifest
+		raw, err := json.Marshal(info)
+		return info, raw, err
+	}
+	return inspect.Inspect(dockerCli.Out(), options.refs, options.format, getRefFunc)
+}
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func TestInspect(t *testing.T) {
+	payload := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.image.config.v1+json","digest":"sha256:7328f6f8b41890597575cbaadc884e7386ae0acc53b747401ebce5cf0d624560","size":1520},"layers":[]}`)
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestContentFunc: func(_ context.Context, ref reference.Named) (registryclient.ManifestContent, error) {
+			assert.Check(t, is.Equal("example.com/app:latest", ref.String()))
+			return registryclient.ManifestContent{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(payload), Payload: payload}, nil
+		},
+	})
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"example.com/app"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "registry-inspect.golden")
+
+	cli.OutBuffer().Reset()
+	cmd = newInspectCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Digest}} {{.Manifest.config.size}}", "example.com/app"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(digest.FromBytes(payload).String()+" 1520\n", cli.OutBuffer().String()))
+}
@@ -17,7 +17,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const unencryptedWarning = `WARNING! Your password will be stored unencrypted in %s.
+const unencryptedWarning = `Your password will be stored unencrypted in %s.
 Configure a credential helper to remove this warning. See
 https://docs.docker.com/engine/reference/commandline/login/#credentials-store
 `
@@ -60,10 +60,8 @@ func NewLoginCommand(dockerCli command.Cli) *cobra.Command {
 // Otherwise, we'll assume they want it (sadly), because people may have been scripting
 // insecure logins and we don't want to break them. Maybe they'll see the warning in their
 // logs and fix things.
-func displayUnencryptedWarning(dockerCli command.Streams, filename string) error {
-	_, err := fmt.Fprintln(dockerCli.Err(), fmt.Sprintf(unencryptedWarning, filename))
-
-	return err
+func displayUnencryptedWarning(dockerCli command.Cli, filename string) {
+	command.PrintWarning(dockerCli, unencryptedWarning, filename)
 }
 
 type isFileStore interface {
@@ -73,7 +71,7 @@ type isFileStore interface {
 
 func verifyloginOptions(dockerCli command.Cli, opts *loginOptions) error {
 	if opts.password != "" {
-		fmt.Fprintln(dockerCli.Err(), "WARNING! Using --password via the CLI is insecure. Use --password-stdin.")
+		command.PrintWarning(dockerCli, "Using --password via the CLI is insecure. Use --password-stdin.")
 		if opts.passwordStdin {
 			return errors.New("--password and --password-stdin are mutually exclusive")
 		}
@@ -144,10 +142,7 @@ func runLogin(dockerCli command.Cli, opts loginOptions) error { //nolint: gocycl
 
 	store, isDefault := creds.(isFileStore)
 	if isDefault {
-		err = displayUnencryptedWarning(dockerCli, store.GetFilename())
-		if err != nil {
-			return err
-		}
+		displayUnencryptedWarning(dockerCli, store.GetFilename())
 	}
 
 	if err := creds.Store(configtypes.AuthConfig(*authConfig)); err != nil {
@@ -27,6 +27,7 @@ var expiredPassword = "I_M_EXPIRED"
 
 type fakeClient struct {
 	client.Client
+	imageSearchFunc func(term string, options types.ImageSearchOptions) ([]registrytypes.SearchResult, error)
 }
 
 func (c fakeClient) Info(ctx context.Context) (types.Info, error) {
@@ -41,6 +42,13 @@ func (c fakeClient) RegistryLogin(ctx context.Context, auth types.AuthConfig) (r
 	return registrytypes.AuthenticateOKBody{}, err
 }
 
+func (c fakeClient) ImageSearch(ctx context.Context, term string, options types.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+	if c.imageSearchFunc != nil {
+		return c.imageSearchFunc(term, options)
+	}
+	return nil, nil
+}
+
 func TestLoginWithCredStoreCreds(t *testing.T) {
 	testCases := []struct {
 		inputAuthConfig types.AuthConfig
@@ -68,7 +68,7 @@ func runLogout(dockerCli command.Cli, serverAddress string) error {
 	fmt.Fprintf(dockerCli.Out(), "Removing login credentials for %s\n", hostnameAddress)
 	for _, r := range regsToLogout {
 		if err := dockerCli.ConfigFile().GetCredentialsStore(r).Erase(r); err != nil {
-			fmt.Fprintf(dockerCli.Err(), "WARNING: could not erase credentials: %v\n", err)
+			command.PrintWarning(dockerCli, "could not erase credentials: %v", err)
 		}
 	}
 
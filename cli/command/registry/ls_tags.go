@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type listTagsOptions struct {
+	repository string
+	insecure   bool
+}
+
+func newListTagsCommand(dockerCli command.Cli) *cobra.Command {
+	options := listTagsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "ls-tags [OPTIONS] REPOSITORY",
+		Short: "List the tags of a repository",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.repository = args[0]
+			return runListTags(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runListTags(dockerCli command.Cli, options listTagsOptions) error {
+	ref, err := reference.ParseNormalizedNamed(options.repository)
+	if err != nil {
+		return err
+	}
+	if !reference.IsNameOnly(ref) {
+		return errors.Errorf("%s is not a repository name: remove the tag or digest", options.repository)
+	}
+
+	tags, err := dockerCli.RegistryClient(options.insecure).GetTags(context.Background(), ref)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the tags of %s", reference.FamiliarName(ref))
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		fmt.Fprintln(dockerCli.Out(), tag)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestListTagsErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		getTagsFunc   func(ctx context.Context, ref reference.Named) ([]string, error)
+		expectedError string
+	}{
+		{
+			expectedError: "requires exactly 1 argument",
+		},
+		{
+			args:          []string{"example.com/app:1.0"},
+			expectedError: "example.com/app:1.0 is not a repository name: remove the tag or digest",
+		},
+		{
+			args: []string{"example.com/app"},
+			getTagsFunc: func(_ context.Context, _ reference.Named) ([]string, error) {
+				return nil, errors.New("unauthorized")
+			},
+			expectedError: "failed to list the tags of example.com/app: unauthorized",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetRegistryClient(&fakeRegistryClient{getTagsFunc: tc.getTagsFunc})
+		cmd := newListTagsCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getTagsFunc: func(_ context.Context, ref reference.Named) ([]string, error) {
+			assert.Check(t, is.Equal("docker.io/library/alpine", ref.String()))
+			return []string{"latest", "3.9", "3.10"}, nil
+		},
+	})
+	cmd := newListTagsCommand(cli)
+	cmd.SetArgs([]string{"alpine"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("3.10\n3.9\nlatest\n", cli.OutBuffer().String()))
+}
@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type removeTagOptions struct {
+	refs     []string
+	insecure bool
+}
+
+func newRemoveTagCommand(dockerCli command.Cli) *cobra.Command {
+	options := removeTagOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rm-tag [OPTIONS] IMAGE:TAG [IMAGE:TAG...]",
+		Short: "Remove one or more tags from their registry",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.refs = args
+			return runRemoveTag(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runRemoveTag(dockerCli command.Cli, options removeTagOptions) error {
+	ctx := context.Background()
+	registryClient := dockerCli.RegistryClient(options.insecure)
+
+	var errs []string
+	for _, name := range options.refs {
+		ref, err := parseTag(name)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := registryClient.DeleteManifest(ctx, ref); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		fmt.Fprintf(dockerCli.Out(), "Deleted: %s\n", reference.FamiliarString(ref))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// parseTag parses a reference with a tag. Unlike other commands, the tag is
+// not "latest" by default, to avoid deleting tags by mistake.
+func parseTag(name string) (reference.NamedTagged, error) {
+	ref, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	tagged, ok := ref.(reference.NamedTagged)
+	if !ok {
+		return nil, errors.Errorf("%s has no tag: use IMAGE:TAG", name)
+	}
+	if _, ok := ref.(reference.Canonical); ok {
+		return nil, errors.Errorf("%s has a digest: use IMAGE:TAG", name)
+	}
+	return tagged, nil
+}
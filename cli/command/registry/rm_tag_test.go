@@ -0,0 +1,33 @@
+package registry
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRemoveTag(t *testing.T) {
+	var deleted []string
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		deleteManifestFunc: func(_ context.Context, ref reference.Named) error {
+			if reference.FamiliarString(ref) == "example.com/app:2.0" {
+				return errors.New("failed to delete example.com/app:2.0: the registry does not allow deletes")
+			}
+			deleted = append(deleted, ref.String())
+			return nil
+		},
+	})
+	cmd := newRemoveTagCommand(cli)
+	cmd.SetArgs([]string{"example.com/app:1.0", "example.com/app", "example.com/app:2.0", "example.com/app:3.0"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Error(t, cmd.Execute(), "example.com/app has no tag: use IMAGE:TAG\nfailed to delete example.com/app:2.0: the registry does not allow deletes")
+	assert.Check(t, is.DeepEqual([]string{"example.com/app:1.0", "example.com/app:3.0"}, deleted))
+	assert.Check(t, is.Equal("Deleted: example.com/app:1.0\nDeleted: example.com/app:3.0\n", cli.OutBuffer().String()))
+}
@@ -2,14 +2,21 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	registryclient "github.com/docker/cli/cli/registry/client"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/registry"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -44,7 +51,7 @@ func NewSearchCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.IntVar(&options.limit, "limit", registry.DefaultSearchLimit, "Max number of search results")
-	flags.StringVar(&options.format, "format", "", "Pretty-print search using a Go template")
+	flags.StringVar(&options.format, "format", "", "Pretty-print search using a Go template, or print the results as JSON with \"json\"")
 
 	flags.BoolVar(&options.automated, "automated", false, "Only show automated builds")
 	flags.UintVarP(&options.stars, "stars", "s", 0, "Only displays with at least x stars")
@@ -56,6 +63,22 @@ func NewSearchCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runSearch(dockerCli command.Cli, options searchOptions) error {
+	if options.limit < 1 {
+		return errors.Errorf("invalid limit %d: the limit must be at least 1", options.limit)
+	}
+	filter, err := newSearchFilter(options.filter.Value())
+	if err != nil {
+		return err
+	}
+	// --automated and -s, --stars are deprecated since Docker 1.12
+	if options.automated {
+		automated := true
+		filter.automated = &automated
+	}
+	if int(options.stars) > filter.stars {
+		filter.stars = int(options.stars)
+	}
+
 	indexInfo, err := registry.ParseSearchIndexInfo(options.term)
 	if err != nil {
 		return err
@@ -63,12 +86,50 @@ func runSearch(dockerCli command.Cli, options searchOptions) error {
 
 	ctx := context.Background()
 
+	var results []registrytypes.SearchResult
+	if options.limit <= registryclient.MaxSearchPageSize {
+		results, err = searchDaemon(ctx, dockerCli, indexInfo, options)
+	} else {
+		// The daemon only returns the first 100 results, so the pages of the
+		// results are fetched from the registry
+		results, err = searchRegistry(ctx, dockerCli, indexInfo, options.term, options.limit, filter)
+	}
+	if err != nil {
+		return err
+	}
+	results = filter.apply(results)
+	if len(results) > options.limit {
+		results = results[:options.limit]
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[j].StarCount < results[i].StarCount
+	})
+
+	if options.format == jsonFormatKey {
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(results)
+	}
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
+	searchCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewSearchFormat(options.format),
+		Trunc:  truncation.Trunc(options.noTrunc),
+		Widths: truncation.Widths("search"),
+	}
+	return SearchWrite(searchCtx, results, false, 0)
+}
+
+// searchDaemon returns the results of the search of the daemon, which are
+// also filtered by the daemon
+func searchDaemon(ctx context.Context, dockerCli command.Cli, indexInfo *registrytypes.IndexInfo, options searchOptions) ([]registrytypes.SearchResult, error) {
 	authConfig := command.ResolveAuthConfig(ctx, dockerCli, indexInfo)
 	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(dockerCli, indexInfo, "search")
 
 	encodedAuth, err := command.EncodeAuthToBase64(authConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	searchOptions := types.ImageSearchOptions{
@@ -77,21 +138,110 @@ func runSearch(dockerCli command.Cli, options searchOptions) error {
 		Filters:       options.filter.Value(),
 		Limit:         options.limit,
 	}
+	return dockerCli.Client().ImageSearch(ctx, options.term, searchOptions)
+}
+
+// searchRegistry returns the filtered results of the search of the registry
+// of the index, page by page, until there are enough results, or no more
+// pages
+func searchRegistry(ctx context.Context, dockerCli command.Cli, indexInfo *registrytypes.IndexInfo, term string, limit int, filter searchFilter) ([]registrytypes.SearchResult, error) {
+	registryClient := dockerCli.RegistryClient(false)
+	term = remoteSearchTerm(indexInfo, term)
+
+	var results []registrytypes.SearchResult
+	for page := 1; len(results) < limit; page++ {
+		p, err := registryClient.SearchRepositories(ctx, indexInfo, term, page, registryclient.MaxSearchPageSize)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, filter.apply(p.Results)...)
+		// Registries which do not paginate the results return all of them
+		if len(p.Results) == 0 || p.NumPages == 0 || page >= p.NumPages {
+			break
+		}
+	}
+	return results, nil
+}
 
-	clnt := dockerCli.Client()
+// remoteSearchTerm returns the term of the search of the registry, without
+// the name of the index, as the daemon does
+func remoteSearchTerm(indexInfo *registrytypes.IndexInfo, term string) string {
+	parts := strings.SplitN(term, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		term = parts[1]
+	}
+	if indexInfo.Official {
+		term = strings.TrimPrefix(term, "library/")
+	}
+	return term
+}
 
-	results, err := clnt.ImageSearch(ctx, options.term, searchOptions)
-	if err != nil {
-		return err
+// searchFilter is the filter of the results of a search. The daemon filters
+// the results itself, but the registries do not.
+type searchFilter struct {
+	official  *bool
+	automated *bool
+	stars     int
+}
+
+var acceptedSearchFilterTags = map[string]bool{
+	"is-automated": true,
+	"is-official":  true,
+	"stars":        true,
+}
+
+func newSearchFilter(args filters.Args) (searchFilter, error) {
+	var filter searchFilter
+	if err := args.Validate(acceptedSearchFilterTags); err != nil {
+		return filter, err
+	}
+	var err error
+	if filter.official, err = searchBoolFilter(args, "is-official"); err != nil {
+		return filter, err
 	}
+	if filter.automated, err = searchBoolFilter(args, "is-automated"); err != nil {
+		return filter, err
+	}
+	for _, value := range args.Get("stars") {
+		stars, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, errors.Errorf("invalid filter 'stars=%s': the number of stars must be an integer", value)
+		}
+		if stars > filter.stars {
+			filter.stars = stars
+		}
+	}
+	return filter, nil
+}
 
-	sort.Slice(results, func(i, j int) bool {
-		return results[j].StarCount < results[i].StarCount
-	})
-	searchCtx := formatter.Context{
-		Output: dockerCli.Out(),
-		Format: NewSearchFormat(options.format),
-		Trunc:  !options.noTrunc,
+func searchBoolFilter(args filters.Args, key string) (*bool, error) {
+	if !args.Contains(key) {
+		return nil, nil
+	}
+	var value bool
+	switch {
+	case args.UniqueExactMatch(key, "true"):
+		value = true
+	case args.UniqueExactMatch(key, "false"):
+	default:
+		return nil, errors.Errorf("invalid filter '%s=%s': the value must be true or false", key, strings.Join(args.Get(key), ","))
+	}
+	return &value, nil
+}
+
+func (f searchFilter) apply(results []registrytypes.SearchResult) []registrytypes.SearchResult {
+	filtered := make([]registrytypes.SearchResult, 0, len(results))
+	for _, result := range results {
+		if f.official != nil && result.IsOfficial != *f.official {
+			continue
+		}
+		if f.automated != nil && result.IsAutomated != *f.automated {
+			continue
+		}
+		if result.StarCount < f.stars {
+			continue
+		}
+		filtered = append(filtered, result)
 	}
-	return SearchWrite(searchCtx, results, options.automated, int(options.stars))
+	return filtered
 }
@@ -0,0 +1,137 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestSearchErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--limit", "0", "nginx"},
+			expectedError: "invalid limit 0: the limit must be at least 1",
+		},
+		{
+			args:          []string{"--filter", "size=1", "nginx"},
+			expectedError: "Invalid filter 'size'",
+		},
+		{
+			args:          []string{"--filter", "is-official=yes", "nginx"},
+			expectedError: "invalid filter 'is-official=yes': the value must be true or false",
+		},
+		{
+			args:          []string{"--filter", "stars=many", "nginx"},
+			expectedError: "invalid filter 'stars=many': the number of stars must be an integer",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := NewSearchCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestSearchDaemon(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageSearchFunc: func(term string, options types.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+			assert.Check(t, is.Equal("nginx", term))
+			assert.Check(t, is.Equal(50, options.Limit))
+			assert.Check(t, options.Filters.ExactMatch("is-official", "true"))
+			// The filters are applied even if the daemon ignores them
+			return []registrytypes.SearchResult{
+				{Name: "bitnami/nginx", StarCount: 100},
+				{Name: "nginx", StarCount: 15000, IsOfficial: true},
+			}, nil
+		},
+	})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		searchFunc: func(context.Context, *registrytypes.IndexInfo, string, int, int) (registryclient.SearchPage, error) {
+			return registryclient.SearchPage{}, fmt.Errorf("unexpected search of the registry")
+		},
+	})
+	cmd := NewSearchCommand(cli)
+	cmd.SetArgs([]string{"--limit", "50", "--filter", "is-official=true", "--format", "{{.Name}} {{.StarCount}}", "nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("nginx 15000\n", cli.OutBuffer().String()))
+}
+
+func TestSearchRegistryPages(t *testing.T) {
+	var pages []int
+	cli := test.NewFakeCli(&fakeClient{
+		imageSearchFunc: func(string, types.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+			return nil, fmt.Errorf("unexpected search of the daemon")
+		},
+	})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		searchFunc: func(_ context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (registryclient.SearchPage, error) {
+			assert.Check(t, is.Equal("registry.example.com", index.Name))
+			assert.Check(t, is.Equal("app", term))
+			assert.Check(t, is.Equal(registryclient.MaxSearchPageSize, pageSize))
+			pages = append(pages, page)
+			var results []registrytypes.SearchResult
+			for i := 0; i < pageSize; i++ {
+				results = append(results, registrytypes.SearchResult{
+					Name:      fmt.Sprintf("app-%d-%d", page, i),
+					StarCount: i,
+				})
+			}
+			return registryclient.SearchPage{
+				SearchResults: registrytypes.SearchResults{Results: results},
+				Page:          page,
+				NumPages:      3,
+			}, nil
+		},
+	})
+	cmd := NewSearchCommand(cli)
+	cmd.SetArgs([]string{"--limit", "150", "--filter", "stars=50", "--format", "{{.Name}}", "registry.example.com/app"})
+	assert.NilError(t, cmd.Execute())
+	// Each page has 50 results with at least 50 stars
+	assert.Check(t, is.DeepEqual([]int{1, 2, 3}, pages))
+	assert.Check(t, is.Len(strings.Split(strings.TrimSpace(cli.OutBuffer().String()), "\n"), 150))
+}
+
+func TestSearchJSON(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageSearchFunc: func(string, types.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+			return []registrytypes.SearchResult{
+				{Name: "nginx", Description: "Official build of Nginx.", StarCount: 15000, IsOfficial: true},
+			}, nil
+		},
+	})
+	cmd := NewSearchCommand(cli)
+	cmd.SetArgs([]string{"--format", "json", "nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`[
+    {
+        "star_count": 15000,
+        "is_official": true,
+        "name": "nginx",
+        "is_automated": false,
+        "description": "Official build of Nginx."
+    }
+]
+`, cli.OutBuffer().String()))
+}
+
+func TestRemoteSearchTerm(t *testing.T) {
+	official := &registrytypes.IndexInfo{Name: "docker.io", Official: true}
+	assert.Check(t, is.Equal("nginx", remoteSearchTerm(official, "nginx")))
+	assert.Check(t, is.Equal("nginx", remoteSearchTerm(official, "library/nginx")))
+	assert.Check(t, is.Equal("bitnami/nginx", remoteSearchTerm(official, "bitnami/nginx")))
+	index := &registrytypes.IndexInfo{Name: "localhost:5000"}
+	assert.Check(t, is.Equal("team/app", remoteSearchTerm(index, "localhost:5000/team/app")))
+}
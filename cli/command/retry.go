@@ -0,0 +1,219 @@
+package command
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultRetryDelay    = 500 * time.Millisecond
+	defaultRetryMaxDelay = 10 * time.Second
+)
+
+// retryPolicy is the policy for retrying failed requests to the daemon
+type retryPolicy struct {
+	// attempts is the number of retries after the first attempt
+	attempts int
+	delay    time.Duration
+	maxDelay time.Duration
+}
+
+// newRetryPolicy returns the retry policy set in the configuration file. The
+// number of retries set with the --retries flag, if not nil, overrides the
+// configuration file, so that --retries 0 disables the retries.
+func newRetryPolicy(config *configfile.RetryConfig, retries *int) (retryPolicy, error) {
+	p := retryPolicy{delay: defaultRetryDelay, maxDelay: defaultRetryMaxDelay}
+	if config != nil {
+		p.attempts = config.Attempts
+		if config.Delay != "" {
+			d, err := time.ParseDuration(config.Delay)
+			if err != nil {
+				return p, errors.Wrap(err, "invalid retry delay in configuration file")
+			}
+			p.delay = d
+		}
+		if config.MaxDelay != "" {
+			d, err := time.ParseDuration(config.MaxDelay)
+			if err != nil {
+				return p, errors.Wrap(err, "invalid maximum retry delay in configuration file")
+			}
+			p.maxDelay = d
+		}
+	}
+	if retries != nil {
+		p.attempts = *retries
+	}
+	if p.attempts < 0 {
+		return p, errors.Errorf("invalid number of retries: %d", p.attempts)
+	}
+	return p, nil
+}
+
+// backoff returns the delay before the given retry, starting at 1. The delay
+// doubles with each retry, up to the maximum delay.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	d := p.delay
+	for i := 1; i < retry && d < p.maxDelay; i++ {
+		d *= 2
+	}
+	if d > p.maxDelay {
+		d = p.maxDelay
+	}
+	return d
+}
+
+// wait waits for the delay before the given retry, and returns an error if
+// the context is done first.
+func (p retryPolicy) wait(ctx context.Context, retry int) error {
+	t := time.NewTimer(p.backoff(retry))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retryAPIClient retries the failed requests made by the API client, with
+// exponential backoff. Failures to connect to the daemon are retried for all
+// the connections, including the hijacked connections of attach and exec.
+// Failed requests are only retried if they are idempotent (GET and HEAD), and
+// for plain connections to the daemon, as the hijacked connections cannot be
+// established over TLS or SSH once the transport of the client is wrapped.
+func retryAPIClient(apiClient client.APIClient, p retryPolicy) {
+	c, ok := apiClient.(*client.Client)
+	if p.attempts == 0 || !ok {
+		return
+	}
+	httpClient := c.HTTPClient()
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+	dial := transport.DialContext
+	if dial == nil && transport.Dial != nil {
+		legacyDial := transport.Dial
+		dial = func(_ context.Context, network, addr string) (net.Conn, error) {
+			return legacyDial(network, addr)
+		}
+		transport.Dial = nil
+	}
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	transport.DialContext = retryDial(p, dial)
+
+	if canWrapTransport(c) {
+		httpClient.Transport = &retryTransport{policy: p, base: httpClient.Transport}
+	}
+}
+
+// canWrapTransport returns whether the transport of the client can be wrapped
+// without breaking its hijacked connections.
+func canWrapTransport(c *client.Client) bool {
+	rt := c.HTTPClient().Transport
+	if cancel, ok := rt.(*cancelTransport); ok {
+		rt = cancel.base
+	}
+	if retry, ok := rt.(*retryTransport); ok {
+		rt = retry.base
+	}
+	if transport, ok := rt.(*http.Transport); !ok || transport.TLSClientConfig != nil {
+		return false
+	}
+	hostURL, err := client.ParseHostURL(c.DaemonHost())
+	return err == nil && (hostURL.Scheme == "unix" || hostURL.Scheme == "tcp")
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// retryDial returns a dial function retrying the failed connections made
+// with dial.
+func retryDial(p retryPolicy, dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		for retry := 1; ; retry++ {
+			conn, err := dial(ctx, network, addr)
+			if err == nil || retry > p.attempts || ctx.Err() != nil {
+				return conn, err
+			}
+			logrus.Warnf("Failed to connect to the daemon: %v; retrying in %s (retry %d of %d)", err, p.backoff(retry), retry, p.attempts)
+			if err := p.wait(ctx, retry); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryTransport is a RoundTripper retrying the idempotent requests that
+// failed, or that a proxy in front of the daemon could not serve.
+type retryTransport struct {
+	policy retryPolicy
+	base   http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isRetryable(req) {
+		return t.base.RoundTrip(req)
+	}
+	ctx := req.Context()
+	for retry := 1; ; retry++ {
+		r := req
+		if req.Body != nil && req.Body != http.NoBody && retry > 1 {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = new(http.Request)
+			*r = *req
+			r.Body = body
+		}
+		resp, err := t.base.RoundTrip(r)
+		if retry > t.policy.attempts || ctx.Err() != nil {
+			return resp, err
+		}
+		var reason string
+		switch {
+		case err != nil:
+			reason = err.Error()
+		case isRetryableStatus(resp.StatusCode):
+			reason = resp.Status
+		default:
+			return resp, nil
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		logrus.Warnf("Request %s %s failed: %s; retrying in %s (retry %d of %d)", req.Method, req.URL.Path, reason, t.policy.backoff(retry), retry, t.policy.attempts)
+		if err := t.policy.wait(ctx, retry); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isRetryable returns whether the request can be sent again without side
+// effects on the daemon.
+func isRetryable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isRetryableStatus returns whether the status code is returned by a proxy
+// that could not reach the daemon. Other errors of the daemon are not
+// transient.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	p, err := newRetryPolicy(nil, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(retryPolicy{delay: defaultRetryDelay, maxDelay: defaultRetryMaxDelay}, p))
+
+	config := &configfile.RetryConfig{Attempts: 3, Delay: "1s", MaxDelay: "4s"}
+	p, err = newRetryPolicy(config, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(retryPolicy{attempts: 3, delay: time.Second, maxDelay: 4 * time.Second}, p))
+
+	retries := 5
+	p, err = newRetryPolicy(config, &retries)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(5, p.attempts))
+
+	// --retries 0 disables the retries set in the configuration file
+	retries = 0
+	p, err = newRetryPolicy(config, &retries)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(0, p.attempts))
+
+	_, err = newRetryPolicy(&configfile.RetryConfig{Delay: "soon"}, nil)
+	assert.Check(t, is.ErrorContains(err, "invalid retry delay"))
+	retries = -1
+	_, err = newRetryPolicy(nil, &retries)
+	assert.Check(t, is.Error(err, "invalid number of retries: -1"))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := retryPolicy{attempts: 5, delay: time.Second, maxDelay: 5 * time.Second}
+	var delays []time.Duration
+	for retry := 1; retry <= 5; retry++ {
+		delays = append(delays, p.backoff(retry))
+	}
+	assert.Check(t, is.DeepEqual([]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays))
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newResponse(code int) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	var attempts int
+	responses := []func() (*http.Response, error){
+		func() (*http.Response, error) { return nil, errors.New("connection reset by peer") },
+		func() (*http.Response, error) { return newResponse(http.StatusBadGateway), nil },
+		func() (*http.Response, error) { return newResponse(http.StatusOK), nil },
+	}
+	rt := &retryTransport{
+		policy: retryPolicy{attempts: 3, delay: time.Millisecond, maxDelay: time.Millisecond},
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return responses[attempts-1]()
+		}),
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://docker/v1.40/info", nil)
+	assert.NilError(t, err)
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(http.StatusOK, resp.StatusCode))
+	assert.Check(t, is.Equal(3, attempts))
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	var attempts int
+	rt := &retryTransport{
+		policy: retryPolicy{attempts: 2, delay: time.Millisecond, maxDelay: time.Millisecond},
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return newResponse(http.StatusServiceUnavailable), nil
+		}),
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://docker/v1.40/info", nil)
+	assert.NilError(t, err)
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(http.StatusServiceUnavailable, resp.StatusCode))
+	assert.Check(t, is.Equal(3, attempts))
+}
+
+func TestRetryTransportNotRetried(t *testing.T) {
+	testCases := []struct {
+		method string
+		status int
+	}{
+		// Requests with side effects are never retried
+		{method: http.MethodPost, status: http.StatusBadGateway},
+		// Errors of the daemon are not transient
+		{method: http.MethodGet, status: http.StatusInternalServerError},
+		{method: http.MethodGet, status: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		var attempts int
+		rt := &retryTransport{
+			policy: retryPolicy{attempts: 3, delay: time.Millisecond, maxDelay: time.Millisecond},
+			base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return newResponse(tc.status), nil
+			}),
+		}
+		req, err := http.NewRequest(tc.method, "http://docker/v1.40/containers/create", bytes.NewReader([]byte("{}")))
+		assert.NilError(t, err)
+		resp, err := rt.RoundTrip(req)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(tc.status, resp.StatusCode))
+		assert.Check(t, is.Equal(1, attempts), "%s %d", tc.method, tc.status)
+	}
+}
+
+func TestRetryDial(t *testing.T) {
+	var attempts int
+	dial := retryDial(retryPolicy{attempts: 3, delay: time.Millisecond, maxDelay: time.Millisecond}, func(ctx context.Context, network, addr string) (net.Conn, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("connection refused")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	})
+	conn, err := dial(context.Background(), "unix", "/var/run/docker.sock")
+	assert.NilError(t, err)
+	conn.Close()
+	assert.Check(t, is.Equal(3, attempts))
+}
+
+func TestRetryDialCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts int
+	dial := retryDial(retryPolicy{attempts: 3, delay: time.Hour, maxDelay: time.Hour}, func(ctx context.Context, network, addr string) (net.Conn, error) {
+		attempts++
+		cancel()
+		return nil, errors.New("connection refused")
+	})
+	_, err := dial(ctx, "unix", "/var/run/docker.sock")
+	assert.Check(t, is.ErrorContains(err, "connection refused"))
+	assert.Check(t, is.Equal(1, attempts))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/secretprovider"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/pkg/system"
@@ -20,6 +21,7 @@ type createOptions struct {
 	driver         string
 	templateDriver string
 	file           string
+	source         secretprovider.Source
 	labels         opts.ListOpts
 }
 
@@ -30,7 +32,7 @@ func newSecretCreateCommand(dockerCli command.Cli) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] SECRET [file|-]",
-		Short: "Create a secret from a file or STDIN as content",
+		Short: "Create a secret from a file, STDIN, or a secret provider as content",
 		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.name = args[0]
@@ -46,6 +48,7 @@ func newSecretCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("driver", "version", []string{"1.31"})
 	flags.StringVar(&options.templateDriver, "template-driver", "", "Template driver")
 	flags.SetAnnotation("driver", "version", []string{"1.37"})
+	secretprovider.AddFlags(flags, &options.source, "secret")
 
 	return cmd
 }
@@ -58,9 +61,20 @@ func runSecretCreate(dockerCli command.Cli, options createOptions) error {
 		return errors.Errorf("When using secret driver secret data must be empty")
 	}
 
-	secretData, err := readSecretData(dockerCli.In(), options.file)
-	if err != nil {
-		return errors.Errorf("Error reading content from %q: %v", options.file, err)
+	var secretData []byte
+	if options.source.IsSet() {
+		if options.driver != "" || options.file != "" {
+			return errors.Errorf("When using a secret provider, secret data must be empty and no secret driver can be used")
+		}
+		var err error
+		if secretData, err = options.source.Get(); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if secretData, err = readSecretData(dockerCli.In(), options.file); err != nil {
+			return errors.Errorf("Error reading content from %q: %v", options.file, err)
+		}
 	}
 	spec := swarm.SecretSpec{
 		Annotations: swarm.Annotations{
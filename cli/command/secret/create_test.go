@@ -2,8 +2,10 @@ package secret
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -13,6 +15,9 @@ import (
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+	"gotest.tools/fs"
+	"gotest.tools/skip"
 )
 
 const secretDataFile = "secret-create-with-name.golden"
@@ -29,6 +34,12 @@ func TestSecretCreateErrors(t *testing.T) {
 		{args: []string{"create", "--driver", "driver", "-"},
 			expectedError: "secret data must be empty",
 		},
+		{args: []string{"create", "--from-vault", "secret/data/db", "-"},
+			expectedError: "When using a secret provider, secret data must be empty",
+		},
+		{args: []string{"create", "--from-vault", "secret/data/db", "--from-aws-sm", "prod/db"},
+			expectedError: "only one of --from-vault, --from-aws-sm, and --from-provider can be set",
+		},
 		{
 			args: []string{"name", filepath.Join("testdata", secretDataFile)},
 			secretCreateFunc: func(secretSpec swarm.SecretSpec) (types.SecretCreateResponse, error) {
@@ -167,3 +178,29 @@ func TestSecretCreateWithLabels(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 	assert.Check(t, is.Equal("ID-"+name, strings.TrimSpace(cli.OutBuffer().String())))
 }
+
+func TestSecretCreateFromProvider(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "the helper of the secret provider is a shell script")
+	dir := fs.NewDir(t, "secret-provider", fs.WithFile("docker-secret-vault", "#!/bin/sh\nprintf 'content of '\ncat\n", fs.WithMode(0755)))
+	defer dir.Remove()
+	defer env.Patch(t, "PATH", dir.Path()+string(os.PathListSeparator)+os.Getenv("PATH"))()
+
+	var actual []byte
+	cli := test.NewFakeCli(&fakeClient{
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			actual = spec.Data
+			return types.SecretCreateResponse{ID: "ID-" + spec.Name}, nil
+		},
+	})
+
+	cmd := newSecretCreateCommand(cli)
+	cmd.SetArgs([]string{"--from-vault", "secret/data/db", "db_password"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(string(actual), "content of secret/data/db"))
+	assert.Check(t, is.Equal("ID-db_password", strings.TrimSpace(cli.OutBuffer().String())))
+
+	cmd = newSecretCreateCommand(cli)
+	cmd.SetArgs([]string{"--from-aws-sm", "prod/db", "db_password"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "error getting prod/db from the aws-sm secret provider"))
+}
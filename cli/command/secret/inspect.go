@@ -59,7 +59,7 @@ func runSecretInspect(dockerCli command.Cli, opts inspectOptions) error {
 	}
 
 	if err := InspectFormatWrite(secretCtx, opts.names, getRef); err != nil {
-		return cli.StatusError{StatusCode: 1, Status: err.Error()}
+		return cli.NewStatusError(err)
 	}
 	return nil
 }
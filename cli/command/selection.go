@@ -0,0 +1,113 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli/streams"
+)
+
+// SelectionItem is an item presented by PromptForSelection
+type SelectionItem struct {
+	// Columns are the values displayed for the item, aligned in a table
+	Columns []string
+	// Selected indicates whether the item is selected
+	Selected bool
+}
+
+const selectionHelp = `Toggle items by number or range (e.g. "1 3-5"), "a" to select all, "n" to select none,
+press enter to continue, or "q" to cancel: `
+
+// PromptForSelection displays a numbered list of items, and lets the user
+// toggle which items are selected until they press enter on an empty line.
+// The initial selection is taken from the Selected field of each item, and
+// is updated in place. It returns false if the user cancelled the selection,
+// or if the input was closed before the selection was confirmed.
+func PromptForSelection(ins io.Reader, outs io.Writer, title string, items []SelectionItem) bool {
+	// On Windows, force the use of the regular OS stdin stream.
+	if runtime.GOOS == "windows" {
+		ins = streams.NewIn(os.Stdin)
+	}
+	reader := bufio.NewReader(ins)
+
+	for {
+		printSelection(outs, title, items)
+		fmt.Fprint(outs, selectionHelp)
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(outs)
+			return false
+		}
+		answer := strings.ToLower(strings.TrimSpace(line))
+		switch answer {
+		case "":
+			return true
+		case "q":
+			return false
+		case "a", "n":
+			for i := range items {
+				items[i].Selected = answer == "a"
+			}
+			continue
+		}
+		indices, err := parseSelection(answer, len(items))
+		if err != nil {
+			fmt.Fprintf(outs, "%s\n", err)
+			continue
+		}
+		for _, i := range indices {
+			items[i].Selected = !items[i].Selected
+		}
+	}
+}
+
+func printSelection(outs io.Writer, title string, items []SelectionItem) {
+	fmt.Fprintf(outs, "\n%s\n\n", title)
+	w := tabwriter.NewWriter(outs, 0, 4, 2, ' ', 0)
+	for i, item := range items {
+		check := " "
+		if item.Selected {
+			check = "x"
+		}
+		fmt.Fprintf(w, "  [%s] %d\t%s\n", check, i+1, strings.Join(item.Columns, "\t"))
+	}
+	w.Flush()
+	fmt.Fprintln(outs)
+}
+
+// parseSelection parses a whitespace or comma separated list of item
+// numbers and ranges (such as "1 3-5") into zero-based indices.
+func parseSelection(answer string, count int) ([]int, error) {
+	var indices []int
+	fields := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	for _, field := range fields {
+		first, last := field, field
+		if i := strings.Index(field, "-"); i > 0 {
+			first, last = field[:i], field[i+1:]
+		}
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		end, err := strconv.Atoi(last)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		if start < 1 || end > count || start > end {
+			return nil, fmt.Errorf("invalid selection %q: must be between 1 and %d", field, count)
+		}
+		for n := start; n <= end; n++ {
+			indices = append(indices, n-1)
+		}
+	}
+	return indices, nil
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newSelectionItems() []SelectionItem {
+	return []SelectionItem{
+		{Columns: []string{"container", "one"}, Selected: true},
+		{Columns: []string{"container", "two"}, Selected: true},
+		{Columns: []string{"image", "three"}, Selected: true},
+		{Columns: []string{"image", "four"}, Selected: true},
+	}
+}
+
+func selected(items []SelectionItem) []bool {
+	var s []bool
+	for _, item := range items {
+		s = append(s, item.Selected)
+	}
+	return s
+}
+
+func TestPromptForSelection(t *testing.T) {
+	testCases := []struct {
+		input     string
+		confirmed bool
+		expected  []bool
+	}{
+		{input: "\n", confirmed: true, expected: []bool{true, true, true, true}},
+		{input: "2\n\n", confirmed: true, expected: []bool{true, false, true, true}},
+		{input: "1-3\n3\n\n", confirmed: true, expected: []bool{false, false, true, true}},
+		{input: "n\n1,4\n\n", confirmed: true, expected: []bool{true, false, false, true}},
+		{input: "n\na\n\n", confirmed: true, expected: []bool{true, true, true, true}},
+		{input: "5\n0\nfoo\n2\n\n", confirmed: true, expected: []bool{true, false, true, true}},
+		{input: "1\nq\n", confirmed: false, expected: []bool{false, true, true, true}},
+		{input: "1", confirmed: false, expected: []bool{false, true, true, true}},
+		{input: "", confirmed: false, expected: []bool{true, true, true, true}},
+	}
+	for _, tc := range testCases {
+		items := newSelectionItems()
+		out := new(bytes.Buffer)
+		confirmed := PromptForSelection(strings.NewReader(tc.input), out, "Select:", items)
+		assert.Check(t, is.Equal(tc.confirmed, confirmed), tc.input)
+		assert.Check(t, is.DeepEqual(tc.expected, selected(items)), tc.input)
+	}
+}
+
+func TestPromptForSelectionOutput(t *testing.T) {
+	items := newSelectionItems()
+	out := new(bytes.Buffer)
+	PromptForSelection(strings.NewReader("2\n7\n\n"), out, "Select:", items)
+	expected := `
+Select:
+
+  [x] 1  container  one
+  [x] 2  container  two
+  [x] 3  image      three
+  [x] 4  image      four
+
+` + selectionHelp + `
+Select:
+
+  [x] 1  container  one
+  [ ] 2  container  two
+  [x] 3  image      three
+  [x] 4  image      four
+
+` + selectionHelp + `invalid selection "7": must be between 1 and 4
+
+Select:
+
+  [x] 1  container  one
+  [ ] 2  container  two
+  [x] 3  image      three
+  [x] 4  image      four
+
+` + selectionHelp
+	assert.Check(t, is.Equal(expected, out.String()))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
 	// Import builders to get the builder function as package function
@@ -18,6 +19,7 @@ type fakeClient struct {
 	taskListFunc              func(context.Context, types.TaskListOptions) ([]swarm.Task, error)
 	infoFunc                  func(ctx context.Context) (types.Info, error)
 	networkInspectFunc        func(ctx context.Context, networkID string, options types.NetworkInspectOptions) (types.NetworkResource, error)
+	eventsFunc                func(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
 }
 
 func (f *fakeClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
@@ -69,6 +71,13 @@ func (f *fakeClient) NetworkInspect(ctx context.Context, networkID string, optio
 	return types.NetworkResource{}, nil
 }
 
+func (f *fakeClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	if f.eventsFunc != nil {
+		return f.eventsFunc(ctx, options)
+	}
+	return nil, nil
+}
+
 func newService(id string, name string) swarm.Service {
 	return swarm.Service{
 		ID:   id,
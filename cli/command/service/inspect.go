@@ -87,7 +87,7 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	}
 
 	if err := InspectFormatWrite(serviceCtx, opts.refs, getRef, getNetwork); err != nil {
-		return cli.StatusError{StatusCode: 1, Status: err.Error()}
+		return cli.NewStatusError(err)
 	}
 	return nil
 }
@@ -8,9 +8,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/command/idresolver"
 	"github.com/docker/cli/service/logs"
 	"github.com/docker/docker/api/types"
@@ -28,6 +31,7 @@ type logsOptions struct {
 	noTaskIDs  bool
 	follow     bool
 	since      string
+	until      string
 	timestamps bool
 	tail       string
 	details    bool
@@ -60,6 +64,8 @@ func newLogsCommand(dockerCli command.Cli) *cobra.Command {
 	// options identical to container logs
 	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
 	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.StringVar(&opts.until, "until", "", "Show logs before a timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
+	flags.SetAnnotation("until", "version", []string{"1.35"})
 	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
 	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
 	flags.SetAnnotation("details", "version", []string{"1.30"})
@@ -68,7 +74,10 @@ func newLogsCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runLogs(dockerCli command.Cli, opts *logsOptions) error {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts.noTrunc = !formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation).Trunc(opts.noTrunc)
 
 	options := types.ContainerLogsOptions{
 		ShowStdout: true,
@@ -82,6 +91,28 @@ func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 		Details: opts.details || !opts.raw,
 	}
 
+	// --until is applied by the client, as the logs of swarm services cannot
+	// be filtered by the daemon. The timestamps of the log lines are used to
+	// drop the lines written after the given time.
+	var until time.Time
+	if opts.until != "" {
+		var err error
+		until, err = parseUntil(opts.until, time.Now())
+		if err != nil {
+			return err
+		}
+		options.Timestamps = true
+		if opts.follow {
+			if until.Before(time.Now()) {
+				options.Follow = false
+			} else {
+				var cancelUntil context.CancelFunc
+				ctx, cancelUntil = context.WithDeadline(ctx, until)
+				defer cancelUntil()
+			}
+		}
+	}
+
 	cli := dockerCli.Client()
 
 	var (
@@ -91,6 +122,9 @@ func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 		// logfunc is used to delay the call to logs so that we can do some
 		// processing before we actually get the logs
 		logfunc func(context.Context, string, types.ContainerLogsOptions) (io.ReadCloser, error)
+		// serviceID is set when following the logs of a service, to watch
+		// for the tasks that are created while following
+		serviceID string
 	)
 
 	service, _, err := cli.ServiceInspectWithRaw(ctx, opts.target, types.ServiceInspectOptions{})
@@ -123,6 +157,9 @@ func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 			replicas := *service.Spec.Mode.Replicated.Replicas
 			maxLength = getMaxLength(int(replicas))
 		}
+		if options.Follow {
+			serviceID = service.ID
+		}
 	}
 
 	// we can't prettify tty logs. tell the user that this is the case.
@@ -133,19 +170,6 @@ func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 		return errors.New("tty service logs only supported with --raw")
 	}
 
-	// now get the logs
-	responseBody, err = logfunc(ctx, opts.target, options)
-	if err != nil {
-		return err
-	}
-	defer responseBody.Close()
-
-	// tty logs get straight copied. they're not muxed with stdcopy
-	if tty {
-		_, err = io.Copy(dockerCli.Out(), responseBody)
-		return err
-	}
-
 	// otherwise, logs are multiplexed. if we're doing pretty printing, also
 	// create a task formatter.
 	var stdout, stderr io.Writer
@@ -157,8 +181,79 @@ func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout}
 		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr}
 	}
+	// the logs of the tasks created while following are copied concurrently
+	// with the logs of the service, so the writers are shared under a lock.
+	mu := &sync.Mutex{}
+	stdout = &lockedWriter{mu: mu, w: stdout}
+	stderr = &lockedWriter{mu: mu, w: stderr}
+
+	copyLogs := func(body io.Reader) error {
+		if opts.until == "" {
+			return copyLogStream(body, stdout, stderr, tty)
+		}
+		untilStdout := &untilWriter{until: until, timestamps: opts.timestamps, w: stdout}
+		untilStderr := &untilWriter{until: until, timestamps: opts.timestamps, w: stderr}
+		err := copyLogStream(body, untilStdout, untilStderr, tty)
+		if err := untilStdout.flush(); err != nil {
+			return err
+		}
+		if err := untilStderr.flush(); err != nil {
+			return err
+		}
+		return err
+	}
 
-	_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
+	var wg sync.WaitGroup
+	if serviceID != "" {
+		known, err := listTaskIDs(ctx, cli, serviceID)
+		if err != nil {
+			return err
+		}
+		taskOptions := options
+		// the tasks are new, so all of their logs are shown
+		taskOptions.Tail = "all"
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watchNewTasks(ctx, cli, serviceID, known, taskPollInterval, func(task swarm.Task) {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					body, err := cli.TaskLogs(ctx, task.ID, taskOptions)
+					if err == nil {
+						err = copyLogs(body)
+						body.Close()
+					}
+					if err != nil && ctx.Err() == nil {
+						fmt.Fprintf(dockerCli.Err(), "failed to follow the logs of task %s: %v\n", task.ID, err)
+					}
+				}()
+			})
+		}()
+	}
+
+	// now get the logs
+	responseBody, err = logfunc(ctx, opts.target, options)
+	if err == nil {
+		err = copyLogs(responseBody)
+		responseBody.Close()
+	}
+	cancel()
+	wg.Wait()
+	if ctx.Err() == context.DeadlineExceeded {
+		// the logs were followed until the time given by --until
+		return nil
+	}
+	return err
+}
+
+func copyLogStream(body io.Reader, stdout, stderr io.Writer, tty bool) error {
+	// tty logs get straight copied. they're not muxed with stdcopy
+	if tty {
+		_, err := io.Copy(stdout, body)
+		return err
+	}
+	_, err := stdcopy.StdCopy(stdout, stderr, body)
 	return err
 }
 
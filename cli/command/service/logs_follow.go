@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/docker/client"
+)
+
+// taskPollInterval is the interval at which the tasks of a service are listed
+// when following its logs. Tasks are also listed when the service is updated.
+const taskPollInterval = 5 * time.Second
+
+// listTaskIDs returns the IDs of the tasks of a service
+func listTaskIDs(ctx context.Context, apiClient client.APIClient, serviceID string) (map[string]bool, error) {
+	tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("service", serviceID)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		ids[task.ID] = true
+	}
+	return ids, nil
+}
+
+// watchNewTasks calls onNewTask for each task of the service that is not in
+// known once it has started, such as the tasks that replace the tasks of the
+// service after an update, until the context is cancelled.
+func watchNewTasks(ctx context.Context, apiClient client.APIClient, serviceID string, known map[string]bool, interval time.Duration, onNewTask func(swarm.Task)) {
+	messages, errs := apiClient.Events(ctx, types.EventsOptions{
+		Filters: filters.NewArgs(filters.Arg("type", "service"), filters.Arg("service", serviceID)),
+	})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-messages:
+		case <-errs:
+			// the daemon does not stream the events; the tasks are still
+			// listed at every interval.
+			messages, errs = nil, nil
+			continue
+		case <-ticker.C:
+		}
+
+		tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{
+			Filters: filters.NewArgs(filters.Arg("service", serviceID)),
+		})
+		if err != nil {
+			continue
+		}
+		for _, task := range tasks {
+			if known[task.ID] || !taskStarted(task) {
+				continue
+			}
+			known[task.ID] = true
+			onNewTask(task)
+		}
+	}
+}
+
+// taskStarted returns whether the container of a task has been started, and
+// may have produced logs.
+func taskStarted(task swarm.Task) bool {
+	switch task.Status.State {
+	case swarm.TaskStateNew, swarm.TaskStatePending, swarm.TaskStateAssigned,
+		swarm.TaskStateAccepted, swarm.TaskStatePreparing, swarm.TaskStateReady,
+		swarm.TaskStateStarting, swarm.TaskStateRejected:
+		return false
+	}
+	return true
+}
+
+// parseUntil parses the value of --until, which is either a timestamp or a
+// duration relative to now.
+func parseUntil(value string, now time.Time) (time.Time, error) {
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// lockedWriter serializes the writes to a writer shared by several streams
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(buf []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(buf)
+}
+
+// untilWriter drops the log lines that were written after a given time. Log
+// lines must be prefixed with their timestamp, which is removed unless
+// timestamps is set.
+type untilWriter struct {
+	until      time.Time
+	timestamps bool
+	w          io.Writer
+	// buf holds the last incomplete line
+	buf []byte
+}
+
+func (uw *untilWriter) Write(p []byte) (int, error) {
+	uw.buf = append(uw.buf, p...)
+	for {
+		i := bytes.IndexByte(uw.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := uw.writeLine(uw.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		uw.buf = uw.buf[i+1:]
+	}
+	if len(uw.buf) == 0 {
+		uw.buf = nil
+	}
+	return len(p), nil
+}
+
+// flush writes the last line, if it is incomplete
+func (uw *untilWriter) flush() error {
+	if len(uw.buf) == 0 {
+		return nil
+	}
+	line := uw.buf
+	uw.buf = nil
+	return uw.writeLine(line)
+}
+
+func (uw *untilWriter) writeLine(line []byte) error {
+	parts := bytes.SplitN(line, []byte(" "), 2)
+	ts, err := time.Parse(time.RFC3339Nano, string(parts[0]))
+	if err != nil || len(parts) != 2 {
+		// not a timestamped line, which is written as is
+		_, err := uw.w.Write(line)
+		return err
+	}
+	if ts.After(uw.until) {
+		return nil
+	}
+	if !uw.timestamps {
+		line = parts[1]
+	}
+	_, err = uw.w.Write(line)
+	return err
+}
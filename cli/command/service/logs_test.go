@@ -0,0 +1,96 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestUntilWriter(t *testing.T) {
+	until := time.Date(2019, 1, 2, 13, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		timestamps bool
+		expected   string
+	}{
+		{
+			timestamps: false,
+			expected:   "before\nat\nnot timestamped\n",
+		},
+		{
+			timestamps: true,
+			expected:   "2019-01-02T12:59:59.999999999Z before\n2019-01-02T13:00:00Z at\nnot timestamped\n",
+		},
+	}
+	for _, tc := range testCases {
+		out := new(bytes.Buffer)
+		w := &untilWriter{until: until, timestamps: tc.timestamps, w: out}
+		for _, chunk := range []string{
+			"2019-01-02T12:59:59.999999999Z bef", "ore\n2019-01-02T13:00:00Z at\n",
+			"2019-01-02T13:00:00.000000001Z after\n",
+			"not timestamped\n",
+		} {
+			_, err := w.Write([]byte(chunk))
+			assert.NilError(t, err)
+		}
+		assert.NilError(t, w.flush())
+		assert.Check(t, is.Equal(tc.expected, out.String()))
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2019, 1, 2, 13, 0, 0, 0, time.UTC)
+	until, err := parseUntil("42m", now)
+	assert.NilError(t, err)
+	assert.Check(t, until.Equal(now.Add(-42*time.Minute)))
+
+	until, err = parseUntil("2019-01-02T13:23:37Z", now)
+	assert.NilError(t, err)
+	assert.Check(t, until.Equal(time.Date(2019, 1, 2, 13, 23, 37, 0, time.UTC)))
+}
+
+func TestWatchNewTasks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tasks := make(chan []swarm.Task, 1)
+	messages := make(chan events.Message)
+	apiClient := &fakeClient{
+		taskListFunc: func(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+			assert.Check(t, options.Filters.ExactMatch("service", "service-id"))
+			return <-tasks, nil
+		},
+		eventsFunc: func(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+			assert.Check(t, options.Filters.ExactMatch("service", "service-id"))
+			return messages, nil
+		},
+	}
+
+	started := make(chan string)
+	known := map[string]bool{"old": true}
+	go watchNewTasks(ctx, apiClient, "service-id", known, time.Hour, func(task swarm.Task) {
+		started <- task.ID
+	})
+
+	tasks <- []swarm.Task{
+		{ID: "old", Status: swarm.TaskStatus{State: swarm.TaskStateShutdown}},
+		{ID: "new", Status: swarm.TaskStatus{State: swarm.TaskStateRunning}},
+		{ID: "pending", Status: swarm.TaskStatus{State: swarm.TaskStatePending}},
+	}
+	messages <- events.Message{Type: events.ServiceEventType, Action: "update"}
+	assert.Check(t, is.Equal("new", <-started))
+
+	tasks <- []swarm.Task{
+		{ID: "old", Status: swarm.TaskStatus{State: swarm.TaskStateShutdown}},
+		{ID: "new", Status: swarm.TaskStatus{State: swarm.TaskStateRunning}},
+		{ID: "pending", Status: swarm.TaskStatus{State: swarm.TaskStateRunning}},
+	}
+	messages <- events.Message{Type: events.ServiceEventType, Action: "update"}
+	assert.Check(t, is.Equal("pending", <-started))
+}
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// cancelSignals are the signals cancelling the root context of the CLI
+var cancelSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ignoredCancelSignals is the number of callers of IgnoreCancelSignals which
+// have not restored the handling of the signals yet
+var ignoredCancelSignals int32
+
+type signalContextKey struct{}
+
+// signalState records the signal which cancelled a root context
+type signalState struct {
+	mu  sync.Mutex
+	sig os.Signal
+}
+
+// NotifyContext returns a copy of ctx which is cancelled on the first SIGINT
+// or SIGTERM received by the process, such as by pressing CTRL-C, so that the
+// requests in progress are cancelled. The process exits with
+// ExitCodeCancelled on the next signal, in case the command does not return
+// once cancelled. The signals are no longer handled once the returned cancel
+// function is called.
+func NotifyContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	state := &signalState{}
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, signalContextKey{}, state))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, cancelSignals...)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if atomic.LoadInt32(&ignoredCancelSignals) > 0 {
+					continue
+				}
+				state.mu.Lock()
+				cancelled := state.sig != nil
+				if !cancelled {
+					state.sig = sig
+				}
+				state.mu.Unlock()
+				if cancelled {
+					os.Exit(ExitCodeCancelled)
+				}
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			cancel()
+		})
+	}
+}
+
+// CancelSignal returns the signal which cancelled the root context ctx, or
+// one of its children, or nil if it was not cancelled by a signal.
+func CancelSignal(ctx context.Context) os.Signal {
+	state, ok := ctx.Value(signalContextKey{}).(*signalState)
+	if !ok {
+		return nil
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.sig
+}
+
+// IgnoreCancelSignals makes the signals not cancel the root context of the
+// CLI, such as while they are forwarded to a container, or handled by a
+// plugin, and returns a function restoring their handling.
+func IgnoreCancelSignals() (restore func()) {
+	atomic.AddInt32(&ignoredCancelSignals, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt32(&ignoredCancelSignals, -1)
+		})
+	}
+}
+
+// cancelTransport is a RoundTripper cancelling the requests in progress when
+// the root context of the CLI is cancelled, including the requests made with
+// another context by the commands.
+type cancelTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *cancelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The requests made once the command is cancelled, such as to clean up
+	// on the daemon, are not cancelled
+	if t.ctx.Done() == nil || t.ctx.Err() != nil {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	body := &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	if rwc, ok := resp.Body.(io.ReadWriteCloser); ok && resp.StatusCode == http.StatusSwitchingProtocols {
+		// The upgraded connections, such as the WebSocket connections of
+		// the attached streams, are written to
+		resp.Body = &cancelReadWriteBody{cancelBody: body, Writer: rwc}
+		return resp, nil
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// cancelBody releases the context of the request of a response once its body
+// is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// cancelReadWriteBody is a cancelBody of an upgraded connection, which is
+// written to
+type cancelReadWriteBody struct {
+	*cancelBody
+	io.Writer
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+// interrupt sends SIGINT to the process of the test
+func interrupt(t *testing.T) {
+	t.Helper()
+	p, err := os.FindProcess(os.Getpid())
+	assert.NilError(t, err)
+	assert.NilError(t, p.Signal(os.Interrupt))
+}
+
+func TestNotifyContext(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "signals cannot be sent to the process on Windows")
+	ctx, cancel := NotifyContext(context.Background())
+	defer cancel()
+	assert.Check(t, is.Nil(CancelSignal(ctx)))
+
+	interrupt(t)
+	select {
+	case <-ctx.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("the context was not cancelled by the signal")
+	}
+	assert.Check(t, is.Equal(CancelSignal(ctx), os.Interrupt))
+	assert.Check(t, is.Nil(CancelSignal(context.Background())))
+}
+
+func TestIgnoreCancelSignals(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "signals cannot be sent to the process on Windows")
+	ctx, cancel := NotifyContext(context.Background())
+	defer cancel()
+
+	restore := IgnoreCancelSignals()
+	interrupt(t)
+	select {
+	case <-ctx.Done():
+		t.Fatal("the context was cancelled by an ignored signal")
+	case <-time.After(100 * time.Millisecond):
+	}
+	restore()
+	restore()
+	assert.Check(t, is.Equal(ignoredCancelSignals, int32(0)))
+	assert.Check(t, is.Nil(CancelSignal(ctx)))
+}
+
+func TestCancelTransport(t *testing.T) {
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan struct{})
+	rt := &cancelTransport{
+		ctx: root,
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/v1.40/images/create" {
+				close(started)
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+			return newResponse(http.StatusOK), nil
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://docker/v1.40/info", nil)
+	assert.NilError(t, err)
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	assert.Check(t, resp.Body.Close())
+
+	// The requests in progress are cancelled, even if they are made with
+	// another context
+	req, err = http.NewRequest(http.MethodPost, "http://docker/v1.40/images/create", nil)
+	assert.NilError(t, err)
+	errC := make(chan error)
+	go func() {
+		_, err := rt.RoundTrip(req)
+		errC <- err
+	}()
+	<-started
+	cancel()
+	select {
+	case err := <-errC:
+		assert.Check(t, is.Equal(err, context.Canceled))
+	case <-time.After(10 * time.Second):
+		t.Fatal("the request was not cancelled")
+	}
+
+	// The requests made once cancelled are not, such as to clean up
+	req, err = http.NewRequest(http.MethodGet, "http://docker/v1.40/info", nil)
+	assert.NilError(t, err)
+	_, err = rt.RoundTrip(req)
+	assert.NilError(t, err)
+}
+
+type readWriteCloser struct {
+	io.Reader
+	io.Writer
+}
+
+func (readWriteCloser) Close() error { return nil }
+
+func TestCancelTransportUpgrade(t *testing.T) {
+	written := new(bytes.Buffer)
+	rt := &cancelTransport{
+		ctx: context.Background(),
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			resp := newResponse(http.StatusSwitchingProtocols)
+			resp.Body = readWriteCloser{Reader: strings.NewReader(""), Writer: written}
+			return resp, nil
+		}),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://docker/v1.40/containers/web/attach/ws", nil)
+	assert.NilError(t, err)
+	resp, err := rt.RoundTrip(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	// The upgraded connections stay writable
+	w, ok := resp.Body.(io.Writer)
+	assert.Assert(t, ok)
+	_, err = w.Write([]byte("stdin"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(written.String(), "stdin"))
+}
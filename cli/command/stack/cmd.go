@@ -57,9 +57,11 @@ func NewStackCommand(dockerCli command.Cli) *cobra.Command {
 	})
 	cmd.AddCommand(
 		newDeployCommand(dockerCli, &opts),
+		newHistoryCommand(dockerCli, &opts),
 		newListCommand(dockerCli, &opts),
 		newPsCommand(dockerCli, &opts),
 		newRemoveCommand(dockerCli, &opts),
+		newRollbackCommand(dockerCli, &opts),
 		newServicesCommand(dockerCli, &opts),
 	)
 	flags := cmd.PersistentFlags()
@@ -0,0 +1,39 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/kubernetes"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newHistoryCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.History
+
+	cmd := &cobra.Command{
+		Use:   "history [OPTIONS] STACK",
+		Short: "Show the deploy history of a stack",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			return RunHistory(dockerCli, cmd.Flags(), common.Orchestrator(), opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Only display revision numbers")
+	return cmd
+}
+
+// RunHistory performs a stack history against the specified orchestrator
+func RunHistory(dockerCli command.Cli, flags *pflag.FlagSet, commonOrchestrator command.Orchestrator, opts options.History) error {
+	return runOrchestratedCommand(dockerCli, flags, commonOrchestrator,
+		func() error { return swarm.RunHistory(dockerCli, opts) },
+		func(kli *kubernetes.KubeCli) error { return errors.New("stack history is not supported on Kubernetes") })
+}
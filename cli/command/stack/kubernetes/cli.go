@@ -1,7 +1,6 @@
 package kubernetes
 
 import (
-	"fmt"
 	"net"
 	"net/url"
 	"os"
@@ -130,8 +129,8 @@ func (c *KubeCli) checkHostsMatch() error {
 			}
 		}
 	}
-	fmt.Fprintf(c.Err(), "WARNING: Swarm and Kubernetes hosts do not match (docker host=%s, kubernetes host=%s).\n"+
-		"         Update $DOCKER_HOST (or pass -H), or use 'kubectl config use-context' to match.\n", daemonEndpoint.Hostname(), kubeEndpoint.Hostname())
+	command.PrintWarning(c, "Swarm and Kubernetes hosts do not match (docker host=%s, kubernetes host=%s).\n"+
+		"         Update $DOCKER_HOST (or pass -H), or use 'kubectl config use-context' to match.", daemonEndpoint.Hostname(), kubeEndpoint.Hostname())
 	return nil
 }
 
@@ -8,11 +8,15 @@ import (
 	composetypes "github.com/docker/cli/cli/compose/types"
 	"github.com/docker/cli/cli/streams"
 	"github.com/morikuni/aec"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 // RunDeploy is the kubernetes implementation of docker stack deploy
 func RunDeploy(dockerCli *KubeCli, opts options.Deploy, cfg *composetypes.Config) error {
+	if opts.Plan {
+		return errors.New("--plan is not supported on Kubernetes")
+	}
 	cmdOut := dockerCli.Out()
 
 	// Initialize clients
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/docker/cli/cli/command"
+	cliformatter "github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/command/stack/formatter"
 	"github.com/docker/cli/cli/command/stack/options"
 	"github.com/docker/cli/cli/command/task"
@@ -81,10 +82,12 @@ func printTasks(dockerCli command.Cli, options options.PS, namespace string, cli
 		nodes[task.ID] = nodeValue
 	}
 
+	truncation := cliformatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	tasksCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: task.NewTaskFormat(format, options.Quiet),
-		Trunc:  !options.NoTrunc,
+		Trunc:  truncation.Trunc(options.NoTrunc),
+		Widths: truncation.Widths("tasks"),
 	}
 
 	return task.FormatWrite(tasksCtx, tasks, names, nodes)
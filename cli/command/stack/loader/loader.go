@@ -24,8 +24,17 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 		return nil, err
 	}
 
+	profiles := opts.Profiles
+	if len(profiles) == 0 {
+		if env := os.Getenv("COMPOSE_PROFILES"); env != "" {
+			profiles = strings.Split(env, ",")
+		}
+	}
+
 	dicts := getDictsFrom(configDetails.ConfigFiles)
-	config, err := loader.Load(configDetails)
+	config, err := loader.Load(configDetails, func(options *loader.Options) {
+		options.Profiles = profiles
+	})
 	if err != nil {
 		if fpe, ok := err.(*loader.ForbiddenPropertiesError); ok {
 			return nil, errors.Errorf("Compose file contains unsupported options:\n\n%s\n",
@@ -6,10 +6,12 @@ import "github.com/docker/cli/opts"
 type Deploy struct {
 	Bundlefile       string
 	Composefiles     []string
+	Profiles         []string
 	Namespace        string
 	ResolveImage     string
 	SendRegistryAuth bool
 	Prune            bool
+	Plan             bool
 }
 
 // List holds docker stack ls options
@@ -41,3 +43,16 @@ type Services struct {
 	Filter    opts.FilterOpt
 	Namespace string
 }
+
+// History holds docker stack history options
+type History struct {
+	Namespace string
+	Quiet     bool
+}
+
+// Rollback holds docker stack rollback options
+type Rollback struct {
+	Namespace        string
+	Revision         int
+	SendRegistryAuth bool
+}
@@ -0,0 +1,42 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/kubernetes"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newRollbackCommand(dockerCli command.Cli, common *commonOptions) *cobra.Command {
+	var opts options.Rollback
+
+	cmd := &cobra.Command{
+		Use:   "rollback [OPTIONS] STACK",
+		Short: "Revert a stack to a previous revision",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			return RunRollback(dockerCli, cmd.Flags(), common.Orchestrator(), opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.IntVar(&opts.Revision, "revision", 0, "Revision to roll back to (default: the previous revision)")
+	flags.BoolVar(&opts.SendRegistryAuth, "with-registry-auth", false, "Send registry authentication details to Swarm agents")
+	return cmd
+}
+
+// RunRollback performs a stack rollback against the specified orchestrator
+func RunRollback(dockerCli command.Cli, flags *pflag.FlagSet, commonOrchestrator command.Orchestrator, opts options.Rollback) error {
+	return runOrchestratedCommand(dockerCli, flags, commonOrchestrator,
+		func() error { return swarm.RunRollback(dockerCli, opts) },
+		func(kli *kubernetes.KubeCli) error {
+			return errors.New("stack rollback is not supported on Kubernetes")
+		})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 )
 
 type fakeClient struct {
@@ -50,6 +51,10 @@ func (cli *fakeClient) ServerVersion(ctx context.Context) (types.Version, error)
 	}, nil
 }
 
+func (cli *fakeClient) Info(ctx context.Context) (types.Info, error) {
+	return types.Info{Swarm: swarm.Info{ControlAvailable: true}}, nil
+}
+
 func (cli *fakeClient) ClientVersion() string {
 	return cli.version
 }
@@ -114,6 +119,24 @@ func (cli *fakeClient) ConfigList(ctx context.Context, options types.ConfigListO
 	return configsList, nil
 }
 
+func (cli *fakeClient) SecretInspectWithRaw(ctx context.Context, name string) (swarm.Secret, []byte, error) {
+	for _, secret := range cli.secrets {
+		if secret == name {
+			return secretFromName(name), nil, nil
+		}
+	}
+	return swarm.Secret{}, nil, notFound{errors.Errorf("no such secret: %s", name)}
+}
+
+func (cli *fakeClient) ConfigInspectWithRaw(ctx context.Context, name string) (swarm.Config, []byte, error) {
+	for _, config := range cli.configs {
+		if config == name {
+			return configFromName(name), nil, nil
+		}
+	}
+	return swarm.Config{}, nil, notFound{errors.Errorf("no such config: %s", name)}
+}
+
 func (cli *fakeClient) TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
 	if cli.taskListFunc != nil {
 		return cli.taskListFunc(options)
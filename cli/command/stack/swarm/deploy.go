@@ -29,6 +29,9 @@ func RunDeploy(dockerCli command.Cli, opts options.Deploy, cfg *composetypes.Con
 		return err
 	}
 
+	if opts.Plan {
+		return planCompose(ctx, dockerCli, opts, cfg)
+	}
 	return deployCompose(ctx, dockerCli, opts, cfg)
 }
 
@@ -60,7 +60,13 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts options.Depl
 	if err != nil {
 		return err
 	}
-	return deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
+	if err := deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage); err != nil {
+		return err
+	}
+	if err := recordStackRevision(ctx, dockerCli, namespace, networks, 0); err != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to record the stack history: %s\n", err)
+	}
+	return nil
 }
 
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
@@ -0,0 +1,221 @@
+package swarm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/ioutils"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// maxStackRevisions is the number of revisions that are kept in the history
+// of a stack
+const maxStackRevisions = 10
+
+// stackRevision is the state of a stack after a deploy or a rollback
+type stackRevision struct {
+	Revision int       `json:"revision"`
+	Created  time.Time `json:"created"`
+	// RollbackOf is the revision that was restored by a rollback
+	RollbackOf int `json:"rollbackOf,omitempty"`
+	// Services are the specs of the services of the stack, as stored by the
+	// daemon, indexed by their name in the Compose file
+	Services map[string]swarm.ServiceSpec   `json:"services"`
+	Networks map[string]types.NetworkCreate `json:"networks,omitempty"`
+}
+
+// stackHistory is the history of a stack. It is stored on the client, in the
+// configuration directory, per context.
+type stackHistory struct {
+	Revisions []stackRevision `json:"revisions"`
+}
+
+// stackHistoryPath returns the path of the file holding the history of a
+// stack. Histories are stored per context, as stacks of different swarms may
+// have the same name.
+func stackHistoryPath(dockerCli command.Cli, namespace string) string {
+	contextName := dockerCli.CurrentContext()
+	if contextName == "" {
+		contextName = "default"
+	}
+	return filepath.Join(config.Dir(), "stacks", contextName, namespace+".json")
+}
+
+func loadStackHistory(path string) (stackHistory, error) {
+	var history stackHistory
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return history, nil
+	case err != nil:
+		return history, err
+	}
+	if err := json.Unmarshal(b, &history); err != nil {
+		return history, errors.Wrapf(err, "invalid stack history %s", path)
+	}
+	return history, nil
+}
+
+func saveStackHistory(path string, history stackHistory) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(path, b, 0600)
+}
+
+// recordStackRevision adds the current state of the stack's services to the
+// history of the stack.
+func recordStackRevision(ctx context.Context, dockerCli command.Cli, namespace convert.Namespace, networks map[string]types.NetworkCreate, rollbackOf int) error {
+	services, err := getStackServices(ctx, dockerCli.Client(), namespace.Name())
+	if err != nil {
+		return err
+	}
+	existingNetworks, err := getStackNetworks(ctx, dockerCli.Client(), namespace.Name())
+	if err != nil {
+		return err
+	}
+	networkNames := map[string]string{}
+	for _, nw := range existingNetworks {
+		networkNames[nw.ID] = nw.Name
+	}
+
+	revision := stackRevision{
+		Created:    time.Now().UTC(),
+		RollbackOf: rollbackOf,
+		Services:   map[string]swarm.ServiceSpec{},
+		Networks:   networks,
+	}
+	for _, service := range services {
+		// Network IDs are replaced with names, so that the networks can be
+		// re-created if they were removed.
+		revision.Services[namespace.Descope(service.Spec.Name)] = normalizeServiceSpec(service.Spec, networkNames)
+	}
+
+	path := stackHistoryPath(dockerCli, namespace.Name())
+	history, err := loadStackHistory(path)
+	if err != nil {
+		return err
+	}
+	revision.Revision = 1
+	if n := len(history.Revisions); n > 0 {
+		revision.Revision = history.Revisions[n-1].Revision + 1
+	}
+	history.Revisions = append(history.Revisions, revision)
+	if n := len(history.Revisions); n > maxStackRevisions {
+		history.Revisions = history.Revisions[n-maxStackRevisions:]
+	}
+	return saveStackHistory(path, history)
+}
+
+// RunHistory is the swarm implementation of docker stack history
+func RunHistory(dockerCli command.Cli, opts options.History) error {
+	history, err := loadStackHistory(stackHistoryPath(dockerCli, opts.Namespace))
+	if err != nil {
+		return err
+	}
+	if len(history.Revisions) == 0 {
+		return errors.Errorf("no history found for stack %s", opts.Namespace)
+	}
+	if opts.Quiet {
+		for _, r := range history.Revisions {
+			fmt.Fprintln(dockerCli.Out(), r.Revision)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tCREATED\tSERVICES\tDESCRIPTION")
+	for _, r := range history.Revisions {
+		description := "deploy"
+		if r.RollbackOf != 0 {
+			description = fmt.Sprintf("rollback to revision %d", r.RollbackOf)
+		}
+		created := units.HumanDuration(time.Now().UTC().Sub(r.Created)) + " ago"
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Revision, created, revisionServices(r), description)
+	}
+	return w.Flush()
+}
+
+// revisionServices returns the names and images of the services of a
+// revision, such as "db=postgres:11, web=nginx:1.17"
+func revisionServices(r stackRevision) string {
+	var services []string
+	for name, spec := range r.Services {
+		image := spec.Labels[convert.LabelImage]
+		if image == "" && spec.TaskTemplate.ContainerSpec != nil {
+			image = spec.TaskTemplate.ContainerSpec.Image
+		}
+		services = append(services, name+"="+image)
+	}
+	sort.Strings(services)
+	return strings.Join(services, ", ")
+}
+
+// RunRollback is the swarm implementation of docker stack rollback
+func RunRollback(dockerCli command.Cli, opts options.Rollback) error {
+	ctx := context.Background()
+	if err := checkDaemonIsSwarmManager(ctx, dockerCli); err != nil {
+		return err
+	}
+
+	namespace := convert.NewNamespace(opts.Namespace)
+	history, err := loadStackHistory(stackHistoryPath(dockerCli, opts.Namespace))
+	if err != nil {
+		return err
+	}
+	var target *stackRevision
+	switch {
+	case opts.Revision != 0:
+		for i, r := range history.Revisions {
+			if r.Revision == opts.Revision {
+				target = &history.Revisions[i]
+			}
+		}
+		if target == nil {
+			return errors.Errorf("revision %d of stack %s not found", opts.Revision, opts.Namespace)
+		}
+	case len(history.Revisions) < 2:
+		return errors.Errorf("no previous revision of stack %s found", opts.Namespace)
+	default:
+		target = &history.Revisions[len(history.Revisions)-2]
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Rolling back stack %s to revision %d\n", opts.Namespace, target.Revision)
+	if err := createNetworks(ctx, dockerCli, namespace, target.Networks); err != nil {
+		return err
+	}
+	// The images of the services are pinned to the digests resolved by the
+	// deploy of the revision.
+	if err := deployServices(ctx, dockerCli, target.Services, namespace, opts.SendRegistryAuth, ResolveImageNever); err != nil {
+		return err
+	}
+	services := map[string]struct{}{}
+	for name := range target.Services {
+		services[name] = struct{}{}
+	}
+	pruneServices(ctx, dockerCli, namespace, services)
+
+	if err := recordStackRevision(ctx, dockerCli, namespace, target.Networks, target.Revision); err != nil {
+		fmt.Fprintf(dockerCli.Err(), "Failed to record the stack history: %s\n", err)
+	}
+	return nil
+}
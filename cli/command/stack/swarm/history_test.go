@@ -0,0 +1,95 @@
+package swarm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func stackService(namespace convert.Namespace, name, image string) swarm.Service {
+	return swarm.Service{
+		ID: "ID-" + name,
+		Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{
+				Name:   namespace.Scope(name),
+				Labels: map[string]string{convert.LabelNamespace: namespace.Name(), convert.LabelImage: image},
+			},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{Image: image + "@sha256:" + image},
+			},
+		},
+	}
+}
+
+func TestStackHistoryAndRollback(t *testing.T) {
+	dir := fs.NewDir(t, "stack-history")
+	defer dir.Remove()
+	defer config.SetDir(config.Dir())
+	config.SetDir(dir.Path())
+
+	ctx := context.Background()
+	namespace := convert.NewNamespace("mystack")
+	services := []swarm.Service{stackService(namespace, "web", "nginx:1.16")}
+	updated := map[string]swarm.ServiceSpec{}
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated[serviceID] = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+
+	err := RunRollback(fakeCli, options.Rollback{Namespace: "mystack"})
+	assert.Check(t, is.Error(err, "no previous revision of stack mystack found"))
+
+	assert.NilError(t, recordStackRevision(ctx, fakeCli, namespace, nil, 0))
+	services = []swarm.Service{stackService(namespace, "web", "nginx:1.17")}
+	assert.NilError(t, recordStackRevision(ctx, fakeCli, namespace, nil, 0))
+
+	err = RunRollback(fakeCli, options.Rollback{Namespace: "mystack", Revision: 5})
+	assert.Check(t, is.Error(err, "revision 5 of stack mystack not found"))
+
+	assert.NilError(t, RunRollback(fakeCli, options.Rollback{Namespace: "mystack"}))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), "Rolling back stack mystack to revision 1"))
+	// The image is pinned to the digest of the first deploy
+	assert.Check(t, is.Equal("nginx:1.16@sha256:nginx:1.16", updated["ID-web"].TaskTemplate.ContainerSpec.Image))
+
+	fakeCli.OutBuffer().Reset()
+	assert.NilError(t, RunHistory(fakeCli, options.History{Namespace: "mystack"}))
+	lines := strings.Split(strings.TrimSpace(fakeCli.OutBuffer().String()), "\n")
+	assert.Assert(t, is.Len(lines, 4))
+	assert.Check(t, is.Equal("REVISION   CREATED                  SERVICES         DESCRIPTION", lines[0]))
+	assert.Check(t, strings.HasPrefix(lines[1], "1 "))
+	assert.Check(t, strings.HasSuffix(lines[2], "web=nginx:1.17   deploy"))
+	assert.Check(t, strings.HasSuffix(lines[3], "rollback to revision 1"))
+}
+
+func TestRecordStackRevisionKeepsLastRevisions(t *testing.T) {
+	dir := fs.NewDir(t, "stack-history")
+	defer dir.Remove()
+	defer config.SetDir(config.Dir())
+	config.SetDir(dir.Path())
+
+	namespace := convert.NewNamespace("mystack")
+	fakeCli := test.NewFakeCli(&fakeClient{services: []string{objectName("mystack", "web")}})
+	for i := 0; i < maxStackRevisions+2; i++ {
+		assert.NilError(t, recordStackRevision(context.Background(), fakeCli, namespace, nil, 0))
+	}
+	history, err := loadStackHistory(stackHistoryPath(fakeCli, "mystack"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(history.Revisions, maxStackRevisions))
+	assert.Check(t, is.Equal(3, history.Revisions[0].Revision))
+	assert.Check(t, is.Equal(maxStackRevisions+2, history.Revisions[maxStackRevisions-1].Revision))
+}
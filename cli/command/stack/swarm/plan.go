@@ -0,0 +1,362 @@
+package swarm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+)
+
+// pendingID is the ID used for objects that would be created by the deploy
+const pendingID = "(new)"
+
+type planAction string
+
+const (
+	planCreate planAction = "+"
+	planUpdate planAction = "~"
+	planRemove planAction = "-"
+)
+
+// planChange is a change to a field of a service spec
+type planChange struct {
+	action   planAction
+	path     string
+	oldValue string
+	newValue string
+}
+
+// planEntry is an object that would be created, updated or removed
+type planEntry struct {
+	action  planAction
+	kind    string
+	name    string
+	changes []planChange
+}
+
+// stackPlan is the list of changes that `docker stack deploy` would apply
+type stackPlan struct {
+	entries   []planEntry
+	unchanged int
+}
+
+// planCompose prints the changes that deploying the Compose file would
+// apply to the stack, without applying them.
+func planCompose(ctx context.Context, dockerCli command.Cli, opts options.Deploy, config *composetypes.Config) error {
+	if err := checkDaemonIsSwarmManager(ctx, dockerCli); err != nil {
+		return err
+	}
+	plan, err := computeStackPlan(ctx, dockerCli.Client(), opts, config)
+	if err != nil {
+		return err
+	}
+	printStackPlan(dockerCli.Out(), opts.Namespace, plan, dockerCli.ColorPolicy())
+	return nil
+}
+
+func computeStackPlan(ctx context.Context, apiClient client.APIClient, opts options.Deploy, config *composetypes.Config) (stackPlan, error) {
+	var plan stackPlan
+	namespace := convert.NewNamespace(opts.Namespace)
+
+	serviceNetworks := getServicesDeclaredNetworks(config.Services)
+	networks, externalNetworks := convert.Networks(namespace, config.Networks, serviceNetworks)
+	if err := validateExternalNetworks(ctx, apiClient, externalNetworks); err != nil {
+		return plan, err
+	}
+	existingNetworks, err := getStackNetworks(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return plan, err
+	}
+	networkNames := map[string]string{}
+	for _, nw := range existingNetworks {
+		networkNames[nw.ID] = nw.Name
+	}
+	var networkKeys []string
+	for name := range networks {
+		networkKeys = append(networkKeys, name)
+	}
+	sort.Strings(networkKeys)
+	for _, name := range networkKeys {
+		if !containsValue(networkNames, name) {
+			plan.entries = append(plan.entries, planEntry{action: planCreate, kind: "network", name: name})
+		}
+	}
+
+	secrets, err := convert.Secrets(namespace, config.Secrets)
+	if err != nil {
+		return plan, err
+	}
+	pc := &planClient{APIClient: apiClient, secrets: map[string]bool{}, configs: map[string]bool{}}
+	for _, spec := range secrets {
+		if _, _, err := apiClient.SecretInspectWithRaw(ctx, spec.Name); client.IsErrNotFound(err) {
+			plan.entries = append(plan.entries, planEntry{action: planCreate, kind: "secret", name: spec.Name})
+			pc.secrets[spec.Name] = true
+		} else if err != nil {
+			return plan, err
+		}
+	}
+	configs, err := convert.Configs(namespace, config.Configs)
+	if err != nil {
+		return plan, err
+	}
+	for _, spec := range configs {
+		if _, _, err := apiClient.ConfigInspectWithRaw(ctx, spec.Name); client.IsErrNotFound(err) {
+			plan.entries = append(plan.entries, planEntry{action: planCreate, kind: "config", name: spec.Name})
+			pc.configs[spec.Name] = true
+		} else if err != nil {
+			return plan, err
+		}
+	}
+
+	services, err := convert.Services(namespace, config, pc)
+	if err != nil {
+		return plan, err
+	}
+	existingServices, err := getStackServices(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return plan, err
+	}
+	existingServiceMap := make(map[string]swarm.Service)
+	for _, service := range existingServices {
+		existingServiceMap[service.Spec.Name] = service
+	}
+	var serviceKeys []string
+	for name := range services {
+		serviceKeys = append(serviceKeys, name)
+	}
+	sort.Strings(serviceKeys)
+	for _, internalName := range serviceKeys {
+		spec := services[internalName]
+		name := namespace.Scope(internalName)
+		service, exists := existingServiceMap[name]
+		if !exists {
+			plan.entries = append(plan.entries, planEntry{action: planCreate, kind: "service", name: name})
+			continue
+		}
+		// Apply the same changes to the spec as deployServices
+		if spec.TaskTemplate.ContainerSpec != nil && service.Spec.TaskTemplate.ContainerSpec != nil &&
+			spec.TaskTemplate.ContainerSpec.Image == service.Spec.Labels[convert.LabelImage] {
+			spec.TaskTemplate.ContainerSpec.Image = service.Spec.TaskTemplate.ContainerSpec.Image
+			if spec.TaskTemplate.Placement == nil || len(spec.TaskTemplate.Placement.Platforms) == 0 {
+				// Platforms are set by the daemon when resolving the image
+				if p := service.Spec.TaskTemplate.Placement; p != nil && len(p.Platforms) > 0 {
+					if spec.TaskTemplate.Placement == nil {
+						spec.TaskTemplate.Placement = &swarm.Placement{}
+					}
+					spec.TaskTemplate.Placement.Platforms = p.Platforms
+				}
+			}
+		}
+		spec.TaskTemplate.ForceUpdate = service.Spec.TaskTemplate.ForceUpdate
+
+		changes, err := diffServiceSpecs(normalizeServiceSpec(service.Spec, networkNames), spec)
+		if err != nil {
+			return plan, err
+		}
+		if len(changes) == 0 {
+			plan.unchanged++
+			continue
+		}
+		plan.entries = append(plan.entries, planEntry{action: planUpdate, kind: "service", name: name, changes: changes})
+	}
+
+	if opts.Prune {
+		for _, service := range existingServices {
+			if _, exists := services[namespace.Descope(service.Spec.Name)]; !exists {
+				plan.entries = append(plan.entries, planEntry{action: planRemove, kind: "service", name: service.Spec.Name})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// normalizeServiceSpec replaces the network IDs in a spec returned by the
+// daemon with the names of the networks, as used by the Compose converter.
+func normalizeServiceSpec(spec swarm.ServiceSpec, networkNames map[string]string) swarm.ServiceSpec {
+	normalize := func(networks []swarm.NetworkAttachmentConfig) []swarm.NetworkAttachmentConfig {
+		var result []swarm.NetworkAttachmentConfig
+		for _, nw := range networks {
+			if name, ok := networkNames[nw.Target]; ok {
+				nw.Target = name
+			}
+			result = append(result, nw)
+		}
+		return result
+	}
+	spec.TaskTemplate.Networks = normalize(spec.TaskTemplate.Networks)
+	spec.Networks = normalize(spec.Networks)
+	return spec
+}
+
+// diffServiceSpecs returns the changes between the fields of two specs.
+// Fields that are empty, or set to their default value, are ignored.
+func diffServiceSpecs(oldSpec, newSpec swarm.ServiceSpec) ([]planChange, error) {
+	oldFields, err := flattenSpec(oldSpec)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenSpec(newSpec)
+	if err != nil {
+		return nil, err
+	}
+	var changes []planChange
+	for path, oldValue := range oldFields {
+		newValue, ok := newFields[path]
+		switch {
+		case !ok:
+			changes = append(changes, planChange{action: planRemove, path: path, oldValue: oldValue})
+		case newValue != oldValue:
+			changes = append(changes, planChange{action: planUpdate, path: path, oldValue: oldValue, newValue: newValue})
+		}
+	}
+	for path, newValue := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			changes = append(changes, planChange{action: planCreate, path: path, newValue: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+	return changes, nil
+}
+
+// flattenSpec returns the JSON-encoded values of the non-empty fields of a
+// spec, indexed by their path (such as "TaskTemplate.ContainerSpec.Image").
+func flattenSpec(spec swarm.ServiceSpec) (map[string]string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{}
+	var flatten func(path string, v interface{})
+	flatten = func(path string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if path != "" {
+					key = path + "." + key
+				}
+				flatten(key, value)
+			}
+		case []interface{}:
+			for i, value := range v {
+				flatten(fmt.Sprintf("%s[%d]", path, i), value)
+			}
+		case nil:
+		case string:
+			// Isolation is set to "default" by the daemon
+			if v != "" && !(v == "default" && strings.HasSuffix(path, ".Isolation")) {
+				fields[path] = fmt.Sprintf("%q", v)
+			}
+		case bool:
+			if v {
+				fields[path] = "true"
+			}
+		case float64:
+			if v != 0 {
+				fields[path] = fmt.Sprintf("%v", v)
+			}
+		}
+	}
+	flatten("", v)
+	return fields, nil
+}
+
+func printStackPlan(out io.Writer, stack string, plan stackPlan, colors streams.ColorPolicy) {
+	colorize := func(action planAction, s string) string {
+		switch action {
+		case planCreate:
+			return colors.Colorize(out, streams.ColorAdded, s)
+		case planRemove:
+			return colors.Colorize(out, streams.ColorRemoved, s)
+		default:
+			return colors.Colorize(out, streams.ColorChanged, s)
+		}
+	}
+
+	var created, updated, removed int
+	for _, entry := range plan.entries {
+		switch entry.action {
+		case planCreate:
+			created++
+		case planUpdate:
+			updated++
+		case planRemove:
+			removed++
+		}
+		fmt.Fprintln(out, colorize(entry.action, fmt.Sprintf("%s %s %s", entry.action, entry.kind, entry.name)))
+		for _, c := range entry.changes {
+			var line string
+			switch c.action {
+			case planCreate:
+				line = fmt.Sprintf("    + %s: %s", c.path, c.newValue)
+			case planRemove:
+				line = fmt.Sprintf("    - %s: %s", c.path, c.oldValue)
+			default:
+				line = fmt.Sprintf("    ~ %s: %s => %s", c.path, c.oldValue, c.newValue)
+			}
+			fmt.Fprintln(out, colorize(c.action, line))
+		}
+	}
+	if len(plan.entries) > 0 {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "Plan for stack %s: %d to create, %d to update, %d to remove, %d unchanged\n", stack, created, updated, removed, plan.unchanged)
+}
+
+// planClient returns the secrets and configs that would be created by the
+// deploy when listing secrets and configs, so that services referencing them
+// can be converted.
+type planClient struct {
+	client.APIClient
+	secrets map[string]bool
+	configs map[string]bool
+}
+
+func (c *planClient) SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error) {
+	secrets, err := c.APIClient.SecretList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range options.Filters.Get("name") {
+		if c.secrets[name] {
+			secrets = append(secrets, swarm.Secret{ID: pendingID, Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: name}}})
+		}
+	}
+	return secrets, nil
+}
+
+func (c *planClient) ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error) {
+	configs, err := c.APIClient.ConfigList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range options.Filters.Get("name") {
+		if c.configs[name] {
+			configs = append(configs, swarm.Config{ID: pendingID, Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: name}}})
+		}
+	}
+	return configs, nil
+}
+
+func containsValue(m map[string]string, value string) bool {
+	for _, v := range m {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestComputeStackPlan(t *testing.T) {
+	namespace := convert.NewNamespace("mystack")
+	oldConfig := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{Name: "web", Image: "nginx:1.16", Environment: composetypes.MappingWithEquals{}},
+			{Name: "cache", Image: "redis", Environment: composetypes.MappingWithEquals{}},
+			{Name: "old", Image: "busybox", Environment: composetypes.MappingWithEquals{}},
+		},
+	}
+	secretFile := fs.NewFile(t, "plan-secret", fs.WithContent("secret"))
+	defer secretFile.Remove()
+	password := "password"
+	newConfig := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name:        "web",
+				Image:       "nginx:1.17",
+				Environment: composetypes.MappingWithEquals{"MODE": &password},
+			},
+			{Name: "cache", Image: "redis", Environment: composetypes.MappingWithEquals{}},
+			{
+				Name:        "db",
+				Image:       "postgres",
+				Environment: composetypes.MappingWithEquals{},
+				Secrets:     []composetypes.ServiceSecretConfig{{Source: "password"}},
+			},
+		},
+		Secrets: map[string]composetypes.SecretConfig{
+			"password": {File: secretFile.Path()},
+		},
+	}
+
+	fakeCli := &fakeClient{
+		networks: []string{"mystack_default"},
+		secretListFunc: func(options types.SecretListOptions) ([]swarm.Secret, error) {
+			return nil, nil
+		},
+	}
+	oldSpecs, err := convert.Services(namespace, oldConfig, fakeCli)
+	assert.NilError(t, err)
+	fakeCli.serviceListFunc = func(options types.ServiceListOptions) ([]swarm.Service, error) {
+		var services []swarm.Service
+		for _, name := range []string{"web", "cache", "old"} {
+			spec := oldSpecs[name]
+			// The daemon stores the IDs of the networks
+			for i := range spec.TaskTemplate.Networks {
+				spec.TaskTemplate.Networks[i].Target = objectID(spec.TaskTemplate.Networks[i].Target)
+			}
+			services = append(services, swarm.Service{ID: "ID-" + name, Spec: spec})
+		}
+		return services, nil
+	}
+
+	plan, err := computeStackPlan(context.Background(), fakeCli, options.Deploy{Namespace: "mystack", Prune: true}, newConfig)
+	assert.NilError(t, err)
+
+	out := new(bytes.Buffer)
+	printStackPlan(out, "mystack", plan, streams.ColorPolicy{Mode: streams.ColorNever})
+	expected := `+ secret mystack_password
++ service mystack_db
+~ service mystack_web
+    ~ Labels.com.docker.stack.image: "nginx:1.16" => "nginx:1.17"
+    + TaskTemplate.ContainerSpec.Env[0]: "MODE=password"
+    ~ TaskTemplate.ContainerSpec.Image: "nginx:1.16" => "nginx:1.17"
+- service mystack_old
+
+Plan for stack mystack: 2 to create, 1 to update, 1 to remove, 1 unchanged
+`
+	assert.Check(t, is.Equal(expected, out.String()))
+}
+
+func TestComputeStackPlanNoChanges(t *testing.T) {
+	out := new(bytes.Buffer)
+	printStackPlan(out, "mystack", stackPlan{unchanged: 2}, streams.ColorPolicy{Mode: streams.ColorNever})
+	assert.Check(t, is.Equal("Plan for stack mystack: 0 to create, 0 to update, 0 to remove, 2 unchanged\n", out.String()))
+}
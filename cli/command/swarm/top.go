@@ -0,0 +1,211 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/node"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/cli/command/task"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"vbom.ml/util/sortorder"
+)
+
+type topOptions struct {
+	noStream bool
+	interval time.Duration
+}
+
+func newTopCommand(dockerCli command.Cli) *cobra.Command {
+	var options topOptions
+
+	cmd := &cobra.Command{
+		Use:   "top [OPTIONS]",
+		Short: "Display a live view of the nodes, services, and tasks of the swarm",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTop(dockerCli, options)
+		},
+		Annotations: map[string]string{
+			"version": "1.30",
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.noStream, "no-stream", false, "Disable streaming the view and only pull the first result")
+	flags.DurationVar(&options.interval, "interval", 2*time.Second, "Interval between the refreshes of the view")
+	return cmd
+}
+
+func runTop(dockerCli command.Cli, options topOptions) error {
+	if options.interval <= 0 {
+		return errors.Errorf("invalid interval %s: must be positive", options.interval)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The view is only refreshed on a terminal
+	if options.noStream || !dockerCli.Out().IsTerminal() {
+		return writeTop(ctx, dockerCli, dockerCli.Out())
+	}
+
+	// The view is refreshed on the events of the nodes and services, and
+	// periodically, as the changes of the states of the tasks have no events
+	eventFilters := filters.NewArgs(
+		filters.Arg("type", events.NodeEventType),
+		filters.Arg("type", events.ServiceEventType),
+	)
+	eventq, errq := dockerCli.Client().Events(ctx, types.EventsOptions{Filters: eventFilters})
+	ticker := time.NewTicker(options.interval)
+	defer ticker.Stop()
+
+	for {
+		// The view is written at once, to not flicker
+		var buf bytes.Buffer
+		if err := writeTop(ctx, dockerCli, &buf); err != nil {
+			return err
+		}
+		fmt.Fprint(dockerCli.Out(), "\033[2J")
+		fmt.Fprint(dockerCli.Out(), "\033[H")
+		if _, err := io.Copy(dockerCli.Out(), &buf); err != nil {
+			return err
+		}
+
+		select {
+		case <-eventq:
+		case <-ticker.C:
+		case err := <-errq:
+			return err
+		}
+	}
+}
+
+// writeTop writes the view of the nodes, services, and running tasks of the
+// swarm, with the formats of "docker node ls", "docker service ls", and
+// "docker service ps"
+func writeTop(ctx context.Context, dockerCli command.Cli, out io.Writer) error {
+	client := dockerCli.Client()
+	info, err := client.Info(ctx)
+	if err != nil {
+		return err
+	}
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+	tasks, err := client.TaskList(ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("desired-state", string(swarm.TaskStateRunning))),
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return sortorder.NaturalLess(nodes[i].Description.Hostname, nodes[j].Description.Hostname)
+	})
+	sort.Slice(services, func(i, j int) bool {
+		return sortorder.NaturalLess(services[i].Spec.Name, services[j].Spec.Name)
+	})
+	fmt.Fprintln(out, topSummary(nodes, services, tasks))
+	fmt.Fprintln(out)
+
+	configFile := dockerCli.ConfigFile()
+	nodesFormat := configFile.NodesFormat
+	if nodesFormat == "" {
+		nodesFormat = formatter.TableFormatKey
+	}
+	if err := node.FormatWrite(formatter.Context{Output: out, Format: node.NewFormat(nodesFormat, false)}, nodes, info); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+
+	servicesFormat := configFile.ServicesFormat
+	if servicesFormat == "" {
+		servicesFormat = formatter.TableFormatKey
+	}
+	servicesCtx := formatter.Context{Output: out, Format: service.NewListFormat(servicesFormat, false)}
+	if err := service.ListFormatWrite(servicesCtx, services, service.GetServicesStatus(services, nodes, tasks)); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+
+	names, hostnames := taskPlacement(nodes, services, tasks)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return sortorder.NaturalLess(names[tasks[i].ID], names[tasks[j].ID])
+	})
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), configFile.Truncation)
+	tasksCtx := formatter.Context{
+		Output: out,
+		Format: task.NewTaskFormat(task.DefaultFormat(configFile, false), false),
+		Trunc:  truncation.Trunc(false),
+		Widths: truncation.Widths("tasks"),
+	}
+	return task.FormatWrite(tasksCtx, tasks, names, hostnames)
+}
+
+// topSummary returns the summary of the view of the swarm, with the number of
+// nodes, services, and tasks, by state
+func topSummary(nodes []swarm.Node, services []swarm.Service, tasks []swarm.Task) string {
+	ready := 0
+	for _, n := range nodes {
+		if n.Status.State == swarm.NodeStateReady {
+			ready++
+		}
+	}
+	running := 0
+	for _, t := range tasks {
+		if t.Status.State == swarm.TaskStateRunning {
+			running++
+		}
+	}
+	return fmt.Sprintf("Nodes: %d (%d ready), Services: %d, Tasks: %d (%d running)", len(nodes), ready, len(services), len(tasks), running)
+}
+
+// taskPlacement returns the names of the tasks, such as "web.1", and the
+// hostnames of their nodes, by task ID
+func taskPlacement(nodes []swarm.Node, services []swarm.Service, tasks []swarm.Task) (map[string]string, map[string]string) {
+	serviceNames := map[string]string{}
+	for _, s := range services {
+		serviceNames[s.ID] = s.Spec.Name
+	}
+	hostnamesByNode := map[string]string{}
+	for _, n := range nodes {
+		hostnamesByNode[n.ID] = n.Description.Hostname
+	}
+
+	names := map[string]string{}
+	hostnames := map[string]string{}
+	for _, t := range tasks {
+		serviceName, ok := serviceNames[t.ServiceID]
+		if !ok {
+			serviceName = t.ServiceID
+		}
+		if t.Slot != 0 {
+			names[t.ID] = fmt.Sprintf("%v.%v", serviceName, t.Slot)
+		} else {
+			names[t.ID] = fmt.Sprintf("%v.%v", serviceName, t.NodeID)
+		}
+		if hostname, ok := hostnamesByNode[t.NodeID]; ok {
+			hostnames[t.ID] = hostname
+		} else {
+			hostnames[t.ID] = t.NodeID
+		}
+	}
+	return names, hostnames
+}
@@ -0,0 +1,94 @@
+package swarm
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/golden"
+)
+
+func TestSwarmTopErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		flags         map[string]string
+		nodeListFunc  func() ([]swarm.Node, error)
+		expectedError string
+	}{
+		{
+			name:          "too-many-args",
+			args:          []string{"foo"},
+			expectedError: "accepts no arguments",
+		},
+		{
+			name: "invalid-interval",
+			flags: map[string]string{
+				"interval": "0s",
+			},
+			expectedError: "invalid interval 0s: must be positive",
+		},
+		{
+			name: "node-list-failed",
+			nodeListFunc: func() ([]swarm.Node, error) {
+				return nil, errors.Errorf("error listing the nodes")
+			},
+			expectedError: "error listing the nodes",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newTopCommand(
+			test.NewFakeCli(&fakeClient{
+				nodeListFunc: tc.nodeListFunc,
+			}))
+		cmd.SetArgs(tc.args)
+		for key, value := range tc.flags {
+			cmd.Flags().Set(key, value)
+		}
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError, tc.name)
+	}
+}
+
+func TestSwarmTopNoStream(t *testing.T) {
+	timestamp := time.Now().Add(-2 * time.Hour)
+	var taskListOptions types.TaskListOptions
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				*Node(NodeID("node-2-id"), Hostname("node-2")),
+				*Node(NodeID("node-1-id"), Hostname("node-1"), Manager(Leader())),
+			}, nil
+		},
+		serviceListFunc: func() ([]swarm.Service, error) {
+			return []swarm.Service{
+				*Service(ServiceID("web-id"), ServiceName("web"), ReplicatedService(2)),
+				*Service(ServiceID("db-id"), ServiceName("db"), ReplicatedService(1)),
+			}, nil
+		},
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			taskListOptions = options
+			return []swarm.Task{
+				*Task(TaskID("web-2-id"), TaskServiceID("web-id"), TaskNodeID("node-2-id"), TaskSlot(2),
+					TaskDesiredState(swarm.TaskStateRunning), WithStatus(TaskState(swarm.TaskStateStarting), Timestamp(timestamp))),
+				*Task(TaskID("web-1-id"), TaskServiceID("web-id"), TaskNodeID("node-1-id"), TaskSlot(1),
+					TaskDesiredState(swarm.TaskStateRunning), WithStatus(TaskState(swarm.TaskStateRunning), Timestamp(timestamp))),
+				*Task(TaskID("db-1-id"), TaskServiceID("db-id"), TaskNodeID("node-2-id"), TaskSlot(1),
+					TaskDesiredState(swarm.TaskStateRunning), WithStatus(TaskState(swarm.TaskStateRunning), Timestamp(timestamp))),
+			}, nil
+		},
+	})
+	cmd := newTopCommand(cli)
+	cmd.Flags().Set("no-stream", "true")
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(taskListOptions.Filters.Get("desired-state"), []string{"running"}))
+	golden.Assert(t, cli.OutBuffer().String(), "top-no-stream.golden")
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 )
 
@@ -11,7 +15,29 @@ type fakeClient struct {
 	client.Client
 
 	version       string
+	pingFunc      func(ctx context.Context) (types.Ping, error)
 	serverVersion func(ctx context.Context) (types.Version, error)
+	eventsFunc    func(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+	diskUsageFunc func(ctx context.Context) (types.DiskUsage, error)
+	infoFunc      func(ctx context.Context) (types.Info, error)
+	imageHistory  func(ctx context.Context, img string) ([]image.HistoryResponseItem, error)
+
+	containerListFunc   func(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	imageListFunc       func(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
+	volumeListFunc      func(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error)
+	networkListFunc     func(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
+	networkInspectFunc  func(ctx context.Context, networkID string) (types.NetworkResource, error)
+	containerRemoveFunc func(ctx context.Context, container string) error
+	imageRemoveFunc     func(ctx context.Context, image string, options types.ImageRemoveOptions) error
+	networkRemoveFunc   func(ctx context.Context, networkID string) error
+	volumeRemoveFunc    func(ctx context.Context, volumeID string) error
+}
+
+func (cli *fakeClient) Ping(ctx context.Context) (types.Ping, error) {
+	if cli.pingFunc != nil {
+		return cli.pingFunc(ctx)
+	}
+	return types.Ping{}, nil
 }
 
 func (cli *fakeClient) ServerVersion(ctx context.Context) (types.Version, error) {
@@ -21,3 +47,69 @@ func (cli *fakeClient) ServerVersion(ctx context.Context) (types.Version, error)
 func (cli *fakeClient) ClientVersion() string {
 	return cli.version
 }
+
+func (cli *fakeClient) NegotiateAPIVersion(ctx context.Context) {}
+
+func (cli *fakeClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	return cli.eventsFunc(ctx, options)
+}
+
+func (cli *fakeClient) DiskUsage(ctx context.Context) (types.DiskUsage, error) {
+	return cli.diskUsageFunc(ctx)
+}
+
+func (cli *fakeClient) Info(ctx context.Context) (types.Info, error) {
+	return cli.infoFunc(ctx)
+}
+
+func (cli *fakeClient) ImageHistory(ctx context.Context, img string) ([]image.HistoryResponseItem, error) {
+	return cli.imageHistory(ctx, img)
+}
+
+func (cli *fakeClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	if cli.containerListFunc != nil {
+		return cli.containerListFunc(ctx, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	if cli.imageListFunc != nil {
+		return cli.imageListFunc(ctx, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error) {
+	if cli.volumeListFunc != nil {
+		return cli.volumeListFunc(ctx, filter)
+	}
+	return volumetypes.VolumeListOKBody{}, nil
+}
+
+func (cli *fakeClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	if cli.networkListFunc != nil {
+		return cli.networkListFunc(ctx, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) NetworkInspect(ctx context.Context, networkID string, _ types.NetworkInspectOptions) (types.NetworkResource, error) {
+	return cli.networkInspectFunc(ctx, networkID)
+}
+
+func (cli *fakeClient) ContainerRemove(ctx context.Context, container string, _ types.ContainerRemoveOptions) error {
+	return cli.containerRemoveFunc(ctx, container)
+}
+
+func (cli *fakeClient) ImageRemove(ctx context.Context, image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	return nil, cli.imageRemoveFunc(ctx, image, options)
+}
+
+func (cli *fakeClient) NetworkRemove(ctx context.Context, networkID string) error {
+	return cli.networkRemoveFunc(ctx, networkID)
+}
+
+func (cli *fakeClient) VolumeRemove(ctx context.Context, volumeID string, _ bool) error {
+	return cli.volumeRemoveFunc(ctx, volumeID)
+}
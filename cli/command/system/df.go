@@ -2,21 +2,34 @@ package system
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type diskUsageOptions struct {
 	verbose bool
 	format  string
+	types   []string
+	sortBy  string
+	filter  opts.FilterOpt
+	layers  bool
 }
 
 // newDiskUsageCommand creates a new cobra.Command for `docker df`
 func newDiskUsageCommand(dockerCli command.Cli) *cobra.Command {
-	var opts diskUsageOptions
+	opts := diskUsageOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "df [OPTIONS]",
@@ -32,15 +45,27 @@ func newDiskUsageCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Show detailed information on space usage")
 	flags.StringVar(&opts.format, "format", "", "Pretty-print images using a Go template")
+	flags.StringSliceVar(&opts.types, "type", nil, "Only show the given object types (images, containers, volumes, build-cache)")
+	flags.StringVar(&opts.sortBy, "sort", "", "Sort detailed output by \"size\", \"name\", or \"created\"")
+	flags.Var(&opts.filter, "filter", "Filter output based on conditions provided")
+	flags.BoolVar(&opts.layers, "layers", false, "Break the usage of the images down by layer of their layer chain")
 
 	return cmd
 }
 
 func runDiskUsage(dockerCli command.Cli, opts diskUsageOptions) error {
-	du, err := dockerCli.Client().DiskUsage(context.Background())
+	if err := validateDiskUsageOptions(opts); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	du, err := dockerCli.Client().DiskUsage(ctx)
 	if err != nil {
 		return err
 	}
+	if err := filterDiskUsage(&du, opts.filter.Value()); err != nil {
+		return err
+	}
 
 	format := opts.format
 	if len(format) == 0 {
@@ -66,7 +91,166 @@ func runDiskUsage(dockerCli command.Cli, opts diskUsageOptions) error {
 		Containers:  du.Containers,
 		Volumes:     du.Volumes,
 		Verbose:     opts.verbose,
+		Types:       opts.types,
+		SortBy:      opts.sortBy,
+	}
+	if opts.layers && duCtx.Includes(formatter.DiskUsageTypeImages) {
+		if duCtx.ImageLayers, err = diskUsageImageLayers(ctx, dockerCli.Client(), du.Images); err != nil {
+			return err
+		}
 	}
 
 	return duCtx.Write()
 }
+
+func validateDiskUsageOptions(opts diskUsageOptions) error {
+	for _, t := range opts.types {
+		switch t {
+		case formatter.DiskUsageTypeImages, formatter.DiskUsageTypeContainers, formatter.DiskUsageTypeVolumes, formatter.DiskUsageTypeBuildCache:
+		default:
+			return errors.Errorf("invalid type %q: must be one of images, containers, volumes, or build-cache", t)
+		}
+	}
+	switch opts.sortBy {
+	case "", formatter.DiskUsageSortBySize, formatter.DiskUsageSortByName, formatter.DiskUsageSortByCreated:
+	default:
+		return errors.Errorf("invalid sort order %q: must be one of size, name, or created", opts.sortBy)
+	}
+	if !opts.verbose {
+		// The summary includes the size of layers shared between images,
+		// which cannot be attributed to a subset of the objects.
+		if opts.sortBy != "" {
+			return errors.New("--sort can only be used in combination with --verbose")
+		}
+		if opts.filter.Value().Len() > 0 {
+			return errors.New("--filter can only be used in combination with --verbose")
+		}
+		if opts.layers {
+			return errors.New("--layers can only be used in combination with --verbose")
+		}
+	}
+	return opts.filter.Value().Validate(map[string]bool{"name": true, "reclaimable": true})
+}
+
+// filterDiskUsage removes the objects that do not match the given filters.
+// The "name" filter matches objects whose name (or, for images, one of their
+// tags) contains the given value. The "reclaimable" filter matches objects
+// that are not in use, and would be removed by the corresponding prune
+// command.
+func filterDiskUsage(du *types.DiskUsage, filter filters.Args) error {
+	if filter.Len() == 0 {
+		return nil
+	}
+	matchName := func(names ...string) bool {
+		if !filter.Contains("name") {
+			return true
+		}
+		for _, name := range names {
+			for _, value := range filter.Get("name") {
+				if strings.Contains(strings.TrimPrefix(name, "/"), value) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	var wantReclaimable []bool
+	for _, value := range filter.Get("reclaimable") {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Errorf("invalid filter 'reclaimable=%s'", value)
+		}
+		wantReclaimable = append(wantReclaimable, b)
+	}
+	matchReclaimable := func(reclaimable bool) bool {
+		if len(wantReclaimable) == 0 {
+			return true
+		}
+		for _, want := range wantReclaimable {
+			if want == reclaimable {
+				return true
+			}
+		}
+		return false
+	}
+
+	images := du.Images[:0]
+	for _, i := range du.Images {
+		if matchName(i.RepoTags...) && matchReclaimable(i.Containers == 0) {
+			images = append(images, i)
+		}
+	}
+	du.Images = images
+
+	containers := du.Containers[:0]
+	for _, c := range du.Containers {
+		active := c.State == "running" || c.State == "paused" || c.State == "restarting"
+		if matchName(c.Names...) && matchReclaimable(!active) {
+			containers = append(containers, c)
+		}
+	}
+	du.Containers = containers
+
+	volumes := du.Volumes[:0]
+	for _, v := range du.Volumes {
+		if matchName(v.Name) && matchReclaimable(v.UsageData == nil || v.UsageData.RefCount == 0) {
+			volumes = append(volumes, v)
+		}
+	}
+	du.Volumes = volumes
+
+	buildCache := du.BuildCache[:0]
+	for _, bc := range du.BuildCache {
+		if matchName(bc.ID, bc.Description) && matchReclaimable(!bc.InUse) {
+			buildCache = append(buildCache, bc)
+		}
+	}
+	du.BuildCache = buildCache
+	return nil
+}
+
+// diskUsageImageLayers returns the layer chains of the images, from their
+// base layer, by image ID. The layers which do not use any space, such as the
+// layers of the ENV instructions, are omitted. A layer is shared by the
+// images whose history is the same up to the layer, so that the layers of a
+// base image are attributed to all the images built from it.
+func diskUsageImageLayers(ctx context.Context, apiClient client.APIClient, images []*types.ImageSummary) (map[string][]formatter.ImageLayer, error) {
+	type layer struct {
+		chain string
+		item  image.HistoryResponseItem
+	}
+	chains := make(map[string][]layer, len(images))
+	sharedBy := map[string]int{}
+	for _, img := range images {
+		history, err := apiClient.ImageHistory(ctx, img.ID)
+		if err != nil {
+			return nil, err
+		}
+		var chain string
+		var layers []layer
+		// The history starts with the most recent layer
+		for i := len(history) - 1; i >= 0; i-- {
+			h := history[i]
+			chain = fmt.Sprintf("%s\x00%d\x00%s\x00%d", chain, h.Created, h.CreatedBy, h.Size)
+			if h.Size == 0 {
+				continue
+			}
+			layers = append(layers, layer{chain: chain, item: h})
+			sharedBy[chain]++
+		}
+		chains[img.ID] = layers
+	}
+
+	imageLayers := make(map[string][]formatter.ImageLayer, len(chains))
+	for id, layers := range chains {
+		for _, l := range layers {
+			imageLayers[id] = append(imageLayers[id], formatter.ImageLayer{
+				CreatedBy: l.item.CreatedBy,
+				Created:   l.item.Created,
+				Size:      l.item.Size,
+				SharedBy:  sharedBy[l.chain],
+			})
+		}
+	}
+	return imageLayers, nil
+}
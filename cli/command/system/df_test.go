@@ -0,0 +1,107 @@
+package system
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestDiskUsageInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"--type", "networks"}, expectedError: `invalid type "networks"`},
+		{args: []string{"-v", "--sort", "links"}, expectedError: `invalid sort order "links"`},
+		{args: []string{"--sort", "size"}, expectedError: "--sort can only be used in combination with --verbose"},
+		{args: []string{"--filter", "name=foo"}, expectedError: "--filter can only be used in combination with --verbose"},
+		{args: []string{"--layers"}, expectedError: "--layers can only be used in combination with --verbose"},
+		{args: []string{"-v", "--filter", "driver=local"}, expectedError: "Invalid filter 'driver'"},
+		{args: []string{"-v", "--filter", "reclaimable=maybe"}, expectedError: "invalid filter 'reclaimable=maybe'"},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{
+			diskUsageFunc: func(context.Context) (types.DiskUsage, error) {
+				return types.DiskUsage{}, nil
+			},
+		})
+		cmd := newDiskUsageCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestDiskUsageFilter(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		diskUsageFunc: func(context.Context) (types.DiskUsage, error) {
+			return types.DiskUsage{
+				Images: []*types.ImageSummary{
+					{ID: "sha256:1", RepoTags: []string{"web:latest"}, Containers: 1},
+					{ID: "sha256:2", RepoTags: []string{"web:old"}, Containers: 0},
+					{ID: "sha256:3", RepoTags: []string{"db:latest"}, Containers: 0},
+				},
+				Containers: []*types.Container{
+					{ID: "c1", Names: []string{"/web-1"}, State: "running"},
+					{ID: "c2", Names: []string{"/web-2"}, State: "exited"},
+				},
+				Volumes: []*types.Volume{
+					{Name: "web-data", UsageData: &types.VolumeUsageData{RefCount: 0}},
+					{Name: "db-data", UsageData: &types.VolumeUsageData{RefCount: 0}},
+				},
+				BuildCache: []*types.BuildCache{
+					{ID: "cache1", Description: "web build", InUse: true},
+				},
+			}, nil
+		},
+	})
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{
+		"-v", "--filter", "name=web", "--filter", "reclaimable=true",
+		"--format", "{{range .Images}}{{.Tag}} {{end}}|{{range .Containers}}{{.Names}} {{end}}|{{range .Volumes}}{{.Name}} {{end}}|{{len .BuildCache}}",
+	})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("old |web-2 |web-data |0", cli.OutBuffer().String()))
+}
+
+func TestDiskUsageLayers(t *testing.T) {
+	history := map[string][]image.HistoryResponseItem{
+		"sha256:1": {
+			{ID: "sha256:1", Created: 3, CreatedBy: "COPY app /app", Size: 2048},
+			{ID: "<missing>", Created: 2, CreatedBy: "ENV PATH=/app", Size: 0},
+			{ID: "<missing>", Created: 1, CreatedBy: "ADD rootfs.tar /", Size: 5000000},
+		},
+		"sha256:2": {
+			{ID: "sha256:2", Created: 4, CreatedBy: "COPY worker /worker", Size: 4096},
+			{ID: "<missing>", Created: 2, CreatedBy: "ENV PATH=/app", Size: 0},
+			{ID: "<missing>", Created: 1, CreatedBy: "ADD rootfs.tar /", Size: 5000000},
+		},
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		diskUsageFunc: func(context.Context) (types.DiskUsage, error) {
+			return types.DiskUsage{
+				Images: []*types.ImageSummary{
+					{ID: "sha256:1", RepoTags: []string{"web:latest"}},
+					{ID: "sha256:2", RepoTags: []string{"worker:latest"}},
+				},
+			}, nil
+		},
+		imageHistory: func(_ context.Context, img string) ([]image.HistoryResponseItem, error) {
+			return history[img], nil
+		},
+	})
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"-v", "--layers", "--type", "images", "--format", "{{range .Layers}}{{.Image}}|{{.CreatedBy}}|{{.Size}}|{{.SharedBy}}\n{{end}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(`web:latest|ADD rootfs.tar /|5MB|2
+web:latest|COPY app /app|2.05kB|1
+worker:latest|ADD rootfs.tar /|5MB|2
+worker:latest|COPY worker /worker|4.1kB|1
+`, cli.OutBuffer().String()))
+}
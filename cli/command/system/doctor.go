@@ -0,0 +1,333 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/debug"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	"github.com/spf13/cobra"
+)
+
+// pingTimeout is the maximum time spent checking that the daemon of a context
+// is reachable
+const pingTimeout = 5 * time.Second
+
+// Statuses of the checks of docker doctor
+const (
+	checkOK      = "ok"
+	checkWarning = "warning"
+	checkError   = "error"
+)
+
+type doctorOptions struct {
+	format string
+}
+
+// doctorCheck is the result of a check of docker doctor
+type doctorCheck struct {
+	Category string `json:"category"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Remedy   string `json:"remedy,omitempty"`
+}
+
+// doctorReport is the report of docker doctor
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks"`
+}
+
+// Failed returns whether any check of the report failed
+func (r doctorReport) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == checkError {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDoctorCommand creates a new cobra.Command for `docker doctor`
+func NewDoctorCommand(dockerCli command.Cli) *cobra.Command {
+	var opts doctorOptions
+
+	cmd := &cobra.Command{
+		Use:   "doctor [OPTIONS]",
+		Short: "Check the configuration of the Docker client",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(dockerCli, cmd.Root(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", `Format the output using the given Go template, or "json"`)
+
+	return cmd
+}
+
+func runDoctor(dockerCli command.Cli, rootCmd *cobra.Command, opts doctorOptions) error {
+	report := doctorChecks(dockerCli, rootCmd)
+	if err := printDoctorReport(dockerCli, report, opts.format); err != nil {
+		return err
+	}
+	if report.Failed() {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// doctorChecks runs the checks of docker doctor
+func doctorChecks(dockerCli command.Cli, rootCmd *cobra.Command) doctorReport {
+	var report doctorReport
+	report.Checks = append(report.Checks, checkConfigFile(dockerCli.ConfigFile())...)
+	report.Checks = append(report.Checks, checkCredentialHelpers(dockerCli.ConfigFile())...)
+	report.Checks = append(report.Checks, checkPlugins(dockerCli, rootCmd)...)
+	report.Checks = append(report.Checks, checkContexts(dockerCli)...)
+	report.Checks = append(report.Checks, checkVersionSkew(dockerCli)...)
+	return report
+}
+
+func printDoctorReport(dockerCli command.Cli, report doctorReport, format string) error {
+	switch format {
+	case "":
+		var warnings, failures int
+		for _, c := range report.Checks {
+			switch c.Status {
+			case checkWarning:
+				warnings++
+			case checkError:
+				failures++
+			}
+			fmt.Fprintf(dockerCli.Out(), "%-9s %s\n", "["+c.Status+"]", c.Message)
+			if c.Remedy != "" {
+				fmt.Fprintf(dockerCli.Out(), "%-9s %s\n", "", c.Remedy)
+			}
+		}
+		fmt.Fprintf(dockerCli.Out(), "\n%d checks, %d warnings, %d errors\n", len(report.Checks), warnings, failures)
+		return nil
+	case "json":
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(report)
+	default:
+		tmpl, err := templates.Parse(format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "Template parsing error: " + err.Error()}
+		}
+		if err := tmpl.Execute(dockerCli.Out(), report); err != nil {
+			return err
+		}
+		fmt.Fprintln(dockerCli.Out())
+		return nil
+	}
+}
+
+func checkConfigFile(configFile *configfile.ConfigFile) []doctorCheck {
+	b, err := ioutil.ReadFile(configFile.Filename)
+	switch {
+	case os.IsNotExist(err):
+		return []doctorCheck{{
+			Category: "config",
+			Status:   checkOK,
+			Message:  fmt.Sprintf("Configuration file %s does not exist; the default configuration is used", configFile.Filename),
+		}}
+	case err != nil:
+		return []doctorCheck{{
+			Category: "config",
+			Status:   checkError,
+			Message:  fmt.Sprintf("Configuration file %s cannot be read: %v", configFile.Filename, err),
+		}}
+	}
+	if err := json.Unmarshal(b, &configfile.ConfigFile{}); err != nil {
+		return []doctorCheck{{
+			Category: "config",
+			Status:   checkError,
+			Message:  fmt.Sprintf("Configuration file %s is not valid: %v", configFile.Filename, err),
+			Remedy:   "Fix the JSON syntax of the file, or remove it to use the default configuration.",
+		}}
+	}
+	checks := []doctorCheck{{
+		Category: "config",
+		Status:   checkOK,
+		Message:  fmt.Sprintf("Configuration file %s is valid", configFile.Filename),
+	}}
+	if configFile.CredentialsStore == "" {
+		var registries []string
+		for registry := range configFile.AuthConfigs {
+			registries = append(registries, registry)
+		}
+		sort.Strings(registries)
+		for _, registry := range registries {
+			if auth := configFile.AuthConfigs[registry]; auth.Auth != "" || auth.Password != "" || auth.IdentityToken != "" {
+				checks = append(checks, doctorCheck{
+					Category: "credentials",
+					Status:   checkWarning,
+					Message:  fmt.Sprintf("The credentials of %s are stored unencrypted in the configuration file", registry),
+					Remedy:   "Configure a credential helper with the credsStore property of the configuration file.",
+				})
+			}
+		}
+	}
+	return checks
+}
+
+func checkCredentialHelpers(configFile *configfile.ConfigFile) []doctorCheck {
+	helpers := map[string]struct{}{}
+	if configFile.CredentialsStore != "" {
+		helpers[configFile.CredentialsStore] = struct{}{}
+	}
+	for _, helper := range configFile.CredentialHelpers {
+		helpers[helper] = struct{}{}
+	}
+	var names []string
+	for name := range helpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var checks []doctorCheck
+	for _, name := range names {
+		binary := "docker-credential-" + name
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Category: "credentials",
+				Status:   checkError,
+				Message:  fmt.Sprintf("Credential helper %s is not found: %v", name, err),
+				Remedy:   fmt.Sprintf("Install %s in the PATH, or remove it from the configuration file.", binary),
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{
+			Category: "credentials",
+			Status:   checkOK,
+			Message:  fmt.Sprintf("Credential helper %s is installed at %s", name, path),
+		})
+	}
+	return checks
+}
+
+func checkPlugins(dockerCli command.Cli, rootCmd *cobra.Command) []doctorCheck {
+	plugins, err := pluginmanager.ListPlugins(dockerCli, rootCmd)
+	if err != nil {
+		return []doctorCheck{{
+			Category: "plugins",
+			Status:   checkError,
+			Message:  fmt.Sprintf("CLI plugins cannot be listed: %v", err),
+		}}
+	}
+	var checks []doctorCheck
+	for _, p := range plugins {
+		if p.Err != nil {
+			checks = append(checks, doctorCheck{
+				Category: "plugins",
+				Status:   checkError,
+				Message:  fmt.Sprintf("Plugin %s (%s) is not valid: %v", p.Name, p.Path, p.Err),
+				Remedy:   "Reinstall the plugin, or remove it from the plugin directory.",
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{
+			Category: "plugins",
+			Status:   checkOK,
+			Message:  fmt.Sprintf("Plugin %s %s (%s) is valid", p.Name, p.Version, p.Path),
+		})
+	}
+	return checks
+}
+
+func checkContexts(dockerCli command.Cli) []doctorCheck {
+	clients, err := command.NewContextAPIClients(dockerCli)
+	if err != nil {
+		return []doctorCheck{{
+			Category: "contexts",
+			Status:   checkError,
+			Message:  fmt.Sprintf("Contexts cannot be listed: %v", err),
+		}}
+	}
+	defer command.CloseContextAPIClients(clients)
+	var current, others []doctorCheck
+	for _, c := range clients {
+		if c.Current {
+			current = append(current, checkDaemon(c.Name, c.Client))
+			if current[0].Status != checkOK {
+				for _, f := range debug.DiagnoseConnection(dockerCli) {
+					current = append(current, doctorCheck{Category: "contexts", Status: checkWarning, Message: f.Problem, Remedy: f.Remedy})
+				}
+			}
+			continue
+		}
+		if c.Err != nil {
+			others = append(others, doctorCheck{
+				Category: "contexts",
+				Status:   checkWarning,
+				Message:  fmt.Sprintf("Context %s is not valid: %v", c.Name, c.Err),
+			})
+			continue
+		}
+		// Only the current context is needed to run the commands
+		check := checkDaemon(c.Name, c.Client)
+		if check.Status == checkError {
+			check.Status = checkWarning
+		}
+		others = append(others, check)
+	}
+	return append(current, others...)
+}
+
+func checkDaemon(contextName string, apiClient client.APIClient) doctorCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if _, err := apiClient.Ping(ctx); err != nil {
+		return doctorCheck{
+			Category: "contexts",
+			Status:   checkError,
+			Message:  fmt.Sprintf("The daemon of context %s cannot be reached: %v", contextName, err),
+		}
+	}
+	return doctorCheck{
+		Category: "contexts",
+		Status:   checkOK,
+		Message:  fmt.Sprintf("The daemon of context %s is reachable at %s", contextName, apiClient.DaemonHost()),
+	}
+}
+
+func checkVersionSkew(dockerCli command.Cli) []doctorCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	sv, err := dockerCli.Client().ServerVersion(ctx)
+	if err != nil {
+		// The failure to reach the daemon is reported by the checks of the
+		// contexts
+		return nil
+	}
+	check := doctorCheck{Category: "version"}
+	switch {
+	case versions.LessThan(sv.APIVersion, api.DefaultVersion):
+		check.Status = checkWarning
+		check.Message = fmt.Sprintf("The daemon (API version %s) is older than the client (API version %s); the features requiring a newer API version are not available", sv.APIVersion, api.DefaultVersion)
+		check.Remedy = "Upgrade the daemon."
+	case versions.GreaterThan(sv.APIVersion, api.DefaultVersion):
+		check.Status = checkWarning
+		check.Message = fmt.Sprintf("The client (API version %s) is older than the daemon (API version %s); the features of the newer API version are not available", api.DefaultVersion, sv.APIVersion)
+		check.Remedy = "Upgrade the client."
+	default:
+		check.Status = checkOK
+		check.Message = fmt.Sprintf("The client and the daemon (version %s) use API version %s", sv.Version, sv.APIVersion)
+	}
+	return []doctorCheck{check}
+}
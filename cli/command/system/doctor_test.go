@@ -0,0 +1,122 @@
+package system
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestCheckConfigFile(t *testing.T) {
+	dir := fs.NewDir(t, "doctor",
+		fs.WithFile("valid.json", `{"auths": {"registry.example.com": {"auth": "dXNlcjpwYXNz"}}}`),
+		fs.WithFile("invalid.json", `{"auths": `),
+	)
+	defer dir.Remove()
+
+	checks := checkConfigFile(configfile.New(dir.Join("missing.json")))
+	assert.Assert(t, is.Len(checks, 1))
+	assert.Check(t, is.Equal(checkOK, checks[0].Status))
+
+	checks = checkConfigFile(configfile.New(dir.Join("invalid.json")))
+	assert.Assert(t, is.Len(checks, 1))
+	assert.Check(t, is.Equal(checkError, checks[0].Status))
+	assert.Check(t, is.Contains(checks[0].Message, "is not valid: unexpected end of JSON input"))
+
+	configFile := configfile.New(dir.Join("valid.json"))
+	configFile.AuthConfigs["registry.example.com"] = types.AuthConfig{Auth: "dXNlcjpwYXNz"}
+	checks = checkConfigFile(configFile)
+	assert.Assert(t, is.Len(checks, 2))
+	assert.Check(t, is.Equal(checkOK, checks[0].Status))
+	assert.Check(t, is.Equal(checkWarning, checks[1].Status))
+	assert.Check(t, is.Equal("The credentials of registry.example.com are stored unencrypted in the configuration file", checks[1].Message))
+
+	configFile.CredentialsStore = "pass"
+	assert.Check(t, is.Len(checkConfigFile(configFile), 1))
+}
+
+func TestCheckCredentialHelpers(t *testing.T) {
+	configFile := configfile.New("config.json")
+	configFile.CredentialsStore = "doesnotexist"
+	configFile.CredentialHelpers = map[string]string{
+		"a.example.com": "doesnotexist",
+		"b.example.com": "doesnotexist",
+	}
+	checks := checkCredentialHelpers(configFile)
+	assert.Assert(t, is.Len(checks, 1))
+	assert.Check(t, is.Equal(checkError, checks[0].Status))
+	assert.Check(t, is.Contains(checks[0].Message, "Credential helper doesnotexist is not found"))
+}
+
+func TestCheckVersionSkew(t *testing.T) {
+	testCases := []struct {
+		apiVersion string
+		status     string
+		remedy     string
+	}{
+		{apiVersion: api.DefaultVersion, status: checkOK},
+		{apiVersion: "1.24", status: checkWarning, remedy: "Upgrade the daemon."},
+		{apiVersion: "99.0", status: checkWarning, remedy: "Upgrade the client."},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{
+			serverVersion: func(ctx context.Context) (dockertypes.Version, error) {
+				return dockertypes.Version{Version: "19.03.0", APIVersion: tc.apiVersion}, nil
+			},
+		})
+		checks := checkVersionSkew(cli)
+		assert.Assert(t, is.Len(checks, 1))
+		assert.Check(t, is.Equal(tc.status, checks[0].Status), tc.apiVersion)
+		assert.Check(t, is.Equal(tc.remedy, checks[0].Remedy), tc.apiVersion)
+	}
+}
+
+func TestCheckContextsUnreachable(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		pingFunc: func(ctx context.Context) (dockertypes.Ping, error) {
+			return dockertypes.Ping{}, errors.New("connection refused")
+		},
+	})
+	checks := checkContexts(cli)
+	assert.Assert(t, len(checks) > 0)
+	assert.Check(t, is.Equal(checkError, checks[0].Status))
+	assert.Check(t, is.Equal("The daemon of context default cannot be reached: connection refused", checks[0].Message))
+}
+
+func TestPrintDoctorReport(t *testing.T) {
+	report := doctorReport{Checks: []doctorCheck{
+		{Category: "config", Status: checkOK, Message: "Configuration file config.json is valid"},
+		{Category: "credentials", Status: checkError, Message: "Credential helper pass is not found", Remedy: "Install docker-credential-pass."},
+	}}
+
+	cli := test.NewFakeCli(&fakeClient{})
+	assert.NilError(t, printDoctorReport(cli, report, ""))
+	expected := `[ok]      Configuration file config.json is valid
+[error]   Credential helper pass is not found
+          Install docker-credential-pass.
+
+2 checks, 0 warnings, 1 errors
+`
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+
+	cli = test.NewFakeCli(&fakeClient{})
+	assert.NilError(t, printDoctorReport(cli, report, "{{range .Checks}}{{.Status}} {{end}}"))
+	assert.Check(t, is.Equal("ok error \n", cli.OutBuffer().String()))
+
+	cli = test.NewFakeCli(&fakeClient{})
+	assert.NilError(t, printDoctorReport(cli, report, "json"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), `"status": "error"`))
+}
+
+func TestDoctorFailedReport(t *testing.T) {
+	assert.Check(t, !doctorReport{Checks: []doctorCheck{{Status: checkOK}, {Status: checkWarning}}}.Failed())
+	assert.Check(t, doctorReport{Checks: []doctorCheck{{Status: checkOK}, {Status: checkError}}}.Failed())
+}
@@ -2,6 +2,7 @@ package system
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,12 +21,17 @@ import (
 )
 
 type eventsOptions struct {
-	since  string
-	until  string
-	filter opts.FilterOpt
-	format string
+	since      string
+	until      string
+	filter     opts.FilterOpt
+	filterExpr string
+	format     string
 }
 
+// jsonLinesFormat is the special --format value that prints each event as a
+// single JSON object per line.
+const jsonLinesFormat = "jsonl"
+
 // NewEventsCommand creates a new cobra.Command for `docker events`
 func NewEventsCommand(dockerCli command.Cli) *cobra.Command {
 	options := eventsOptions{filter: opts.NewFilterOpt()}
@@ -43,17 +49,33 @@ func NewEventsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.StringVar(&options.since, "since", "", "Show all events created since timestamp")
 	flags.StringVar(&options.until, "until", "", "Stream events until this timestamp")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
-	flags.StringVar(&options.format, "format", "", "Format the output using the given Go template")
+	flags.StringVar(&options.filterExpr, "filter-expr", "", "Filter output client-side using an expression")
+	flags.StringVar(&options.format, "format", "", "Format the output using the given Go template, or 'jsonl' for JSON Lines")
 
 	return cmd
 }
 
 func runEvents(dockerCli command.Cli, options *eventsOptions) error {
-	tmpl, err := makeTemplate(options.format)
-	if err != nil {
-		return cli.StatusError{
-			StatusCode: 64,
-			Status:     "Error parsing format: " + err.Error()}
+	var (
+		tmpl *template.Template
+		err  error
+	)
+	if options.format != jsonLinesFormat {
+		tmpl, err = makeTemplate(options.format)
+		if err != nil {
+			return cli.StatusError{
+				StatusCode: 64,
+				Status:     "Error parsing format: " + err.Error()}
+		}
+	}
+	var matcher eventMatcher
+	if options.filterExpr != "" {
+		matcher, err = parseEventFilter(options.filterExpr)
+		if err != nil {
+			return cli.StatusError{
+				StatusCode: 64,
+				Status:     err.Error()}
+		}
 	}
 	eventOptions := types.EventsOptions{
 		Since:   options.since,
@@ -70,7 +92,15 @@ func runEvents(dockerCli command.Cli, options *eventsOptions) error {
 	for {
 		select {
 		case event := <-events:
-			if err := handleEvent(out, event, tmpl); err != nil {
+			if matcher != nil && !matcher.Match(event) {
+				continue
+			}
+			if options.format == jsonLinesFormat {
+				err = formatEventJSONLine(out, event)
+			} else {
+				err = handleEvent(out, event, tmpl)
+			}
+			if err != nil {
 				return err
 			}
 		case err := <-errs:
@@ -140,3 +170,12 @@ func formatEvent(out io.Writer, event eventtypes.Message, tmpl *template.Templat
 	defer out.Write([]byte{'\n'})
 	return tmpl.Execute(out, event)
 }
+
+// formatEventJSONLine prints the event as a single line of JSON. Unlike the
+// "{{json .}}" template, the output is guaranteed to be one object per line
+// with a stable key order, and HTML characters are not escaped.
+func formatEventJSONLine(out io.Writer, event eventtypes.Message) error {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(event)
+}
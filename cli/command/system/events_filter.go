@@ -0,0 +1,318 @@
+package system
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+)
+
+// eventMatcher reports whether an event matches a client-side filter
+// expression.
+type eventMatcher interface {
+	Match(event eventtypes.Message) bool
+}
+
+type andMatcher []eventMatcher
+
+func (m andMatcher) Match(event eventtypes.Message) bool {
+	for _, sub := range m {
+		if !sub.Match(event) {
+			return false
+		}
+	}
+	return true
+}
+
+type orMatcher []eventMatcher
+
+func (m orMatcher) Match(event eventtypes.Message) bool {
+	for _, sub := range m {
+		if sub.Match(event) {
+			return true
+		}
+	}
+	return false
+}
+
+type notMatcher struct {
+	matcher eventMatcher
+}
+
+func (m notMatcher) Match(event eventtypes.Message) bool {
+	return !m.matcher.Match(event)
+}
+
+type fieldMatcher struct {
+	field  string
+	negate bool
+	value  string
+	regexp *regexp.Regexp
+}
+
+func (m fieldMatcher) Match(event eventtypes.Message) bool {
+	value, ok := eventField(event, m.field)
+	var matched bool
+	if m.regexp != nil {
+		matched = ok && m.regexp.MatchString(value)
+	} else {
+		matched = ok && value == m.value
+	}
+	return matched != m.negate
+}
+
+// eventField returns the value of the given field for an event. The "type",
+// "action" (or "status"), "id" and "scope" fields refer to the event itself;
+// any other field is looked up in the actor's attributes. The "attr." prefix
+// can be used to explicitly select an attribute that shadows one of the
+// event fields (for example, the "type" attribute of network events).
+func eventField(event eventtypes.Message, field string) (string, bool) {
+	if strings.HasPrefix(field, "attr.") {
+		value, ok := event.Actor.Attributes[strings.TrimPrefix(field, "attr.")]
+		return value, ok
+	}
+	switch strings.ToLower(field) {
+	case "type":
+		return event.Type, true
+	case "action", "status":
+		return event.Action, true
+	case "id":
+		return event.Actor.ID, true
+	case "scope":
+		return event.Scope, true
+	}
+	value, ok := event.Actor.Attributes[field]
+	return value, ok
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// tokenizeFilter splits a filter expression into tokens. Field names and
+// operators are recognized in word position, and the value following an
+// operator extends to the next whitespace or closing parenthesis unless it
+// is quoted. Unquoted values cannot contain parentheses, so that a regular
+// expression such as "(foo|bar)" is not cut at its closing parenthesis.
+func tokenizeFilter(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	afterOperator := false
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' && !afterOperator:
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == r || runes[i+1] == '\\') {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errors.Errorf("unterminated string starting at offset %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+			afterOperator = false
+		case afterOperator:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != ')' {
+				if runes[i] == '(' {
+					return nil, errors.Errorf("unquoted value at offset %d contains a parenthesis: values with parentheses must be quoted", start)
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, value: string(runes[start:i]), pos: start})
+			afterOperator = false
+		default:
+			if op := matchOperator(runes[i:]); op != "" {
+				tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+				i += len(op)
+				afterOperator = op != "!" && op != "&&" && op != "||"
+				continue
+			}
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()=!~&|\"'", runes[i]) {
+				i++
+			}
+			if i == start {
+				return nil, errors.Errorf("unexpected character %q at offset %d", r, start)
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i]), pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func matchOperator(runes []rune) string {
+	for _, op := range []string{"=~", "!~", "!=", "&&", "||", "=", "!"} {
+		if strings.HasPrefix(string(runes), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseEventFilter parses a client-side filter expression such as
+//
+//	type=container AND (name=~^web- OR image=nginx) AND NOT action=exec_create
+//
+// Comparisons use "=" and "!=" for exact matches and "=~" and "!~" for
+// regular expressions. Comparisons are combined with AND (or &&), OR (or ||)
+// and NOT (or !), and can be grouped using parentheses. AND binds tighter
+// than OR.
+func parseEventFilter(expr string) (eventMatcher, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid filter expression")
+	}
+	p := &filterParser{tokens: tokens}
+	m, err := p.parseOr()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid filter expression")
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errors.Errorf("invalid filter expression: unexpected %q at offset %d", tok.value, tok.pos)
+	}
+	return m, nil
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) isKeyword(tok token, keyword, symbol string) bool {
+	return (tok.kind == tokenWord && strings.EqualFold(tok.value, keyword)) ||
+		(tok.kind == tokenOperator && tok.value == symbol)
+}
+
+func (p *filterParser) parseOr() (eventMatcher, error) {
+	var matchers orMatcher
+	for {
+		m, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+		if !p.isKeyword(p.peek(), "or", "||") {
+			break
+		}
+		p.next()
+	}
+	if len(matchers) == 1 {
+		return matchers[0], nil
+	}
+	return matchers, nil
+}
+
+func (p *filterParser) parseAnd() (eventMatcher, error) {
+	var matchers andMatcher
+	for {
+		m, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+		if !p.isKeyword(p.peek(), "and", "&&") {
+			break
+		}
+		p.next()
+	}
+	if len(matchers) == 1 {
+		return matchers[0], nil
+	}
+	return matchers, nil
+}
+
+func (p *filterParser) parseUnary() (eventMatcher, error) {
+	tok := p.peek()
+	switch {
+	case p.isKeyword(tok, "not", "!"):
+		p.next()
+		m, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notMatcher{matcher: m}, nil
+	case tok.kind == tokenLParen:
+		p.next()
+		m, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, errors.Errorf("expected \")\" at offset %d", closing.pos)
+		}
+		return m, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (eventMatcher, error) {
+	field := p.next()
+	if field.kind != tokenWord || field.value == "" {
+		if field.kind == tokenEOF {
+			return nil, errors.New("unexpected end of expression")
+		}
+		return nil, errors.Errorf("expected a field name at offset %d, got %q", field.pos, field.value)
+	}
+	op := p.next()
+	if op.kind != tokenOperator || (op.value != "=" && op.value != "!=" && op.value != "=~" && op.value != "!~") {
+		return nil, errors.Errorf("expected a comparison operator after %q at offset %d", field.value, op.pos)
+	}
+	value := p.next()
+	if value.kind != tokenString {
+		return nil, errors.Errorf("expected a value after %q at offset %d", field.value+op.value, value.pos)
+	}
+	m := fieldMatcher{
+		field:  field.value,
+		negate: op.value[0] == '!',
+		value:  value.value,
+	}
+	if strings.HasSuffix(op.value, "~") {
+		re, err := regexp.Compile(value.value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regular expression for %q", field.value)
+		}
+		m.regexp = re
+	}
+	return m, nil
+}
@@ -0,0 +1,79 @@
+package system
+
+import (
+	"testing"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParseEventFilter(t *testing.T) {
+	event := eventtypes.Message{
+		Type:   "container",
+		Action: "start",
+		Scope:  "local",
+		Actor: eventtypes.Actor{
+			ID: "abc123",
+			Attributes: map[string]string{
+				"name":  "web-1",
+				"image": "nginx:alpine",
+				"type":  "custom",
+			},
+		},
+	}
+
+	testCases := []struct {
+		expr     string
+		expected bool
+	}{
+		{expr: "type=container", expected: true},
+		{expr: "type!=container", expected: false},
+		{expr: "TYPE=container", expected: true},
+		{expr: "attr.type=custom", expected: true},
+		{expr: "status=start", expected: true},
+		{expr: "id=abc123 and scope=local", expected: true},
+		{expr: "name=~^web-", expected: true},
+		{expr: "name!~^web-", expected: false},
+		{expr: "name=~'^(web|api)-[0-9]+$'", expected: true},
+		{expr: `(type=network OR name=~"(web|api)-1")`, expected: true},
+		{expr: "image=redis OR name=web-1", expected: true},
+		{expr: "image=redis || name=web-2", expected: false},
+		{expr: "type=container && (image=redis || name=~web)", expected: true},
+		{expr: "type=network OR type=container AND action=start", expected: true},
+		{expr: "(type=network OR type=container) AND action=die", expected: false},
+		{expr: "NOT action=die", expected: true},
+		{expr: "!(action=start)", expected: false},
+		{expr: `image="nginx:alpine"`, expected: true},
+		{expr: "missing=foo", expected: false},
+		{expr: "missing!=foo", expected: true},
+	}
+	for _, tc := range testCases {
+		m, err := parseEventFilter(tc.expr)
+		assert.NilError(t, err, tc.expr)
+		assert.Check(t, is.Equal(tc.expected, m.Match(event)), tc.expr)
+	}
+}
+
+func TestParseEventFilterErrors(t *testing.T) {
+	testCases := []struct {
+		expr          string
+		expectedError string
+	}{
+		{expr: "", expectedError: "unexpected end of expression"},
+		{expr: "type", expectedError: `expected a comparison operator after "type"`},
+		{expr: "type=", expectedError: `expected a value after "type="`},
+		{expr: "(type=container", expectedError: `expected ")"`},
+		{expr: "type=container)", expectedError: `unexpected ")"`},
+		{expr: "type=container AND", expectedError: "unexpected end of expression"},
+		{expr: "name=~'['", expectedError: `invalid regular expression for "name"`},
+		{expr: "name='web", expectedError: "unterminated string"},
+		{expr: "type=container & name=web", expectedError: "unexpected character '&'"},
+		{expr: "image=~(foo|bar)", expectedError: "unquoted value at offset 7 contains a parenthesis"},
+		{expr: "(type=container AND name=~^(web|api))", expectedError: "values with parentheses must be quoted"},
+	}
+	for _, tc := range testCases {
+		_, err := parseEventFilter(tc.expr)
+		assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.expr)
+	}
+}
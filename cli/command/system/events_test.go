@@ -0,0 +1,64 @@
+package system
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	eventtypes "github.com/docker/docker/api/types/events"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func fakeEvents(messages ...eventtypes.Message) func(context.Context, types.EventsOptions) (<-chan eventtypes.Message, <-chan error) {
+	return func(context.Context, types.EventsOptions) (<-chan eventtypes.Message, <-chan error) {
+		events := make(chan eventtypes.Message)
+		errs := make(chan error, 1)
+		go func() {
+			for _, m := range messages {
+				events <- m
+			}
+			errs <- io.EOF
+		}()
+		return events, errs
+	}
+}
+
+func TestEventsFilterExprAndJSONLines(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		eventsFunc: fakeEvents(
+			eventtypes.Message{
+				Type:     "container",
+				Action:   "start",
+				Actor:    eventtypes.Actor{ID: "c1", Attributes: map[string]string{"name": "web-1", "image": "<none>"}},
+				TimeNano: 1500000000000000000,
+			},
+			eventtypes.Message{
+				Type:   "container",
+				Action: "start",
+				Actor:  eventtypes.Actor{ID: "c2", Attributes: map[string]string{"name": "db"}},
+			},
+			eventtypes.Message{
+				Type:   "network",
+				Action: "connect",
+				Actor:  eventtypes.Actor{ID: "n1", Attributes: map[string]string{"name": "web-net"}},
+			},
+		),
+	})
+	cmd := NewEventsCommand(cli)
+	cmd.SetArgs([]string{"--format", "jsonl", "--filter-expr", "type=container AND name=~^web"})
+	assert.NilError(t, cmd.Execute())
+	expected := `{"Type":"container","Action":"start","Actor":{"ID":"c1","Attributes":{"image":"<none>","name":"web-1"}},"timeNano":1500000000000000000}` + "\n"
+	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
+}
+
+func TestEventsInvalidFilterExpr(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := NewEventsCommand(cli)
+	cmd.SetArgs([]string{"--filter-expr", "type="})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "invalid filter expression")
+}
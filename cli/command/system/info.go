@@ -2,6 +2,7 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
@@ -11,6 +12,7 @@ import (
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/debug"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/templates"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
@@ -38,6 +40,10 @@ type info struct {
 
 	ClientInfo   *clientInfo `json:",omitempty"`
 	ClientErrors []string    `json:",omitempty"`
+
+	// Context is the name of the context of the server, if several contexts
+	// are selected with the --context flag
+	Context string `json:",omitempty"`
 }
 
 // NewInfoCommand creates a new cobra.Command for `docker info`
@@ -51,6 +57,7 @@ func NewInfoCommand(dockerCli command.Cli) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runInfo(cmd, dockerCli, &opts)
 		},
+		Annotations: map[string]string{"multiContext": ""},
 	}
 
 	flags := cmd.Flags()
@@ -63,13 +70,6 @@ func NewInfoCommand(dockerCli command.Cli) *cobra.Command {
 func runInfo(cmd *cobra.Command, dockerCli command.Cli, opts *infoOptions) error {
 	var info info
 
-	ctx := context.Background()
-	if dinfo, err := dockerCli.Client().Info(ctx); err == nil {
-		info.Info = &dinfo
-	} else {
-		info.ServerErrors = append(info.ServerErrors, err.Error())
-	}
-
 	info.ClientInfo = &clientInfo{
 		Debug: debug.IsEnabled(),
 	}
@@ -79,35 +79,98 @@ func runInfo(cmd *cobra.Command, dockerCli command.Cli, opts *infoOptions) error
 		info.ClientErrors = append(info.ClientErrors, err.Error())
 	}
 
+	ctx := context.Background()
+	if clients := dockerCli.SelectedContextClients(); len(clients) > 0 {
+		return runInfoContexts(ctx, dockerCli, clients, info, opts)
+	}
+
+	if dinfo, err := dockerCli.Client().Info(ctx); err == nil {
+		info.Info = &dinfo
+	} else {
+		info.ServerErrors = append(info.ServerErrors, err.Error())
+	}
+
 	if opts.format == "" {
 		return prettyPrintInfo(dockerCli, info)
 	}
 	return formatInfo(dockerCli, info, opts.format)
 }
 
-func prettyPrintInfo(dockerCli command.Cli, info info) error {
-	fmt.Fprintln(dockerCli.Out(), "Client:")
-	if info.ClientInfo != nil {
-		if err := prettyPrintClientInfo(dockerCli, *info.ClientInfo); err != nil {
-			info.ClientErrors = append(info.ClientErrors, err.Error())
+// runInfoContexts displays the information of the daemons of several
+// contexts, fetched concurrently, along with the information of the client,
+// which is the same for every context.
+func runInfoContexts(ctx context.Context, dockerCli command.Cli, clients []command.ContextAPIClient, client info, opts *infoOptions) error {
+	infos := make([]info, len(clients))
+	for i, c := range clients {
+		infos[i] = client
+		infos[i].Context = c.Name
+		if c.Err != nil {
+			infos[i].ServerErrors = []string{c.Err.Error()}
 		}
 	}
-	for _, err := range info.ClientErrors {
-		fmt.Fprintln(dockerCli.Out(), "ERROR:", err)
+	// The errors are reported with the information of each context
+	_ = command.RunInContexts(ctx, clients, func(ctx context.Context, i int, c command.ContextAPIClient) error {
+		dinfo, err := c.Client.Info(ctx)
+		if err != nil {
+			infos[i].ServerErrors = append(infos[i].ServerErrors, err.Error())
+			return nil
+		}
+		infos[i].Info = &dinfo
+		return nil
+	})
+
+	if opts.format == "" {
+		return prettyPrintInfo(dockerCli, infos...)
 	}
+	var errs []string
+	for _, info := range infos {
+		if err := formatInfo(dockerCli, info, opts.format); err != nil {
+			if _, ok := err.(cli.StatusError); ok {
+				return err
+			}
+			errs = append(errs, fmt.Sprintf("context %s: %s", info.Context, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
 
-	fmt.Fprintln(dockerCli.Out())
-	fmt.Fprintln(dockerCli.Out(), "Server:")
-	if info.Info != nil {
-		for _, err := range prettyPrintServerInfo(dockerCli, *info.Info) {
-			info.ServerErrors = append(info.ServerErrors, err.Error())
+// prettyPrintInfo prints the information of the client, and of the server of
+// each info, titled with its context if it has one
+func prettyPrintInfo(dockerCli command.Cli, infos ...info) error {
+	client := infos[0]
+	fmt.Fprintln(dockerCli.Out(), "Client:")
+	if client.ClientInfo != nil {
+		if err := prettyPrintClientInfo(dockerCli, *client.ClientInfo); err != nil {
+			client.ClientErrors = append(client.ClientErrors, err.Error())
 		}
 	}
-	for _, err := range info.ServerErrors {
+	for _, err := range client.ClientErrors {
 		fmt.Fprintln(dockerCli.Out(), "ERROR:", err)
 	}
+	hasErrors := len(client.ClientErrors) > 0
 
-	if len(info.ServerErrors) > 0 || len(info.ClientErrors) > 0 {
+	for _, info := range infos {
+		fmt.Fprintln(dockerCli.Out())
+		if info.Context == "" {
+			fmt.Fprintln(dockerCli.Out(), "Server:")
+		} else {
+			fmt.Fprintf(dockerCli.Out(), "Server (context %s):\n", info.Context)
+		}
+		if info.Info != nil {
+			for _, err := range prettyPrintServerInfo(dockerCli, *info.Info) {
+				info.ServerErrors = append(info.ServerErrors, err.Error())
+			}
+		}
+		for _, err := range info.ServerErrors {
+			fmt.Fprintln(dockerCli.Out(), "ERROR:", err)
+		}
+		hasErrors = hasErrors || len(info.ServerErrors) > 0
+	}
+
+	if hasErrors {
 		return fmt.Errorf("errors pretty printing info")
 	}
 	return nil
@@ -371,7 +434,7 @@ func printSwarmInfo(dockerCli command.Cli, info types.Info) {
 
 func printServerWarnings(dockerCli command.Cli, info types.Info) {
 	if len(info.Warnings) > 0 {
-		fmt.Fprintln(dockerCli.Err(), strings.Join(info.Warnings, "\n"))
+		fmt.Fprintln(dockerCli.Err(), dockerCli.ColorPolicy().Colorize(dockerCli.Err(), streams.ColorWarning, strings.Join(info.Warnings, "\n")))
 		return
 	}
 	// daemon didn't return warnings. Fallback to old behavior
@@ -389,37 +452,37 @@ func printServerWarningsLegacy(dockerCli command.Cli, info types.Info) {
 		return
 	}
 	if !info.MemoryLimit {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No memory limit support")
+		command.PrintWarning(dockerCli, "No memory limit support")
 	}
 	if !info.SwapLimit {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No swap limit support")
+		command.PrintWarning(dockerCli, "No swap limit support")
 	}
 	if !info.KernelMemory {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No kernel memory limit support")
+		command.PrintWarning(dockerCli, "No kernel memory limit support")
 	}
 	if !info.OomKillDisable {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No oom kill disable support")
+		command.PrintWarning(dockerCli, "No oom kill disable support")
 	}
 	if !info.CPUCfsQuota {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No cpu cfs quota support")
+		command.PrintWarning(dockerCli, "No cpu cfs quota support")
 	}
 	if !info.CPUCfsPeriod {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No cpu cfs period support")
+		command.PrintWarning(dockerCli, "No cpu cfs period support")
 	}
 	if !info.CPUShares {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No cpu shares support")
+		command.PrintWarning(dockerCli, "No cpu shares support")
 	}
 	if !info.CPUSet {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: No cpuset support")
+		command.PrintWarning(dockerCli, "No cpuset support")
 	}
 	if !info.IPv4Forwarding {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: IPv4 forwarding is disabled")
+		command.PrintWarning(dockerCli, "IPv4 forwarding is disabled")
 	}
 	if !info.BridgeNfIptables {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: bridge-nf-call-iptables is disabled")
+		command.PrintWarning(dockerCli, "bridge-nf-call-iptables is disabled")
 	}
 	if !info.BridgeNfIP6tables {
-		fmt.Fprintln(dockerCli.Err(), "WARNING: bridge-nf-call-ip6tables is disabled")
+		command.PrintWarning(dockerCli, "bridge-nf-call-ip6tables is disabled")
 	}
 }
 
@@ -438,19 +501,19 @@ func printStorageDriverWarnings(dockerCli command.Cli, info types.Info) {
 	}
 	for _, pair := range info.DriverStatus {
 		if pair[0] == "Data loop file" {
-			fmt.Fprintf(dockerCli.Err(), "WARNING: %s: usage of loopback devices is "+
+			command.PrintWarning(dockerCli, "%s: usage of loopback devices is "+
 				"strongly discouraged for production use.\n         "+
-				"Use `--storage-opt dm.thinpooldev` to specify a custom block storage device.\n", info.Driver)
+				"Use `--storage-opt dm.thinpooldev` to specify a custom block storage device.", info.Driver)
 		}
 		if pair[0] == "Supports d_type" && pair[1] == "false" {
 			backingFs := getBackingFs(info)
 
-			msg := fmt.Sprintf("WARNING: %s: the backing %s filesystem is formatted without d_type support, which leads to incorrect behavior.\n", info.Driver, backingFs)
+			msg := "%s: the backing %s filesystem is formatted without d_type support, which leads to incorrect behavior.\n"
 			if backingFs == "xfs" {
 				msg += "         Reformat the filesystem with ftype=1 to enable d_type support.\n"
 			}
 			msg += "         Running without d_type support will not be supported in future releases."
-			fmt.Fprintln(dockerCli.Err(), msg)
+			command.PrintWarning(dockerCli, msg, info.Driver, backingFs)
 		}
 	}
 }
@@ -1,12 +1,16 @@
 package system
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
+	"io/ioutil"
 	"net"
 	"testing"
 	"time"
 
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/registry"
@@ -396,3 +400,25 @@ func TestFormatInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestInfoContexts(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetSelectedContextClients([]command.ContextAPIClient{
+		{Name: "default", Current: true, Client: &fakeClient{
+			infoFunc: func(ctx context.Context) (types.Info, error) {
+				return types.Info{Name: "laptop", ServerVersion: "19.03.1"}, nil
+			},
+		}},
+		{Name: "prod", Err: errors.New("unable to resolve docker endpoint")},
+		{Name: "staging", Client: &fakeClient{
+			infoFunc: func(ctx context.Context) (types.Info, error) {
+				return types.Info{Name: "staging-1", ServerVersion: "18.09.7"}, nil
+			},
+		}},
+	})
+	cmd := NewInfoCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	cmd.SetArgs([]string{"--format", "{{.Context}}: {{.Name}} {{.ServerVersion}}"})
+	assert.Error(t, cmd.Execute(), `context prod: template: :1:16: executing "" at <.Name>: reflect: indirection through nil pointer to embedded struct field Info`)
+	golden.Assert(t, cli.OutBuffer().String(), "docker-info-contexts.golden")
+}
@@ -47,7 +47,7 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	var elementSearcher inspect.GetRefFunc
 	switch opts.inspectType {
 	case "", "container", "image", "node", "network", "service", "volume", "task", "plugin", "secret":
-		elementSearcher = inspectAll(context.Background(), dockerCli, opts.size, opts.inspectType)
+		elementSearcher = inspectAll(command.WithOfflineCache(context.Background()), dockerCli, opts.size, opts.inspectType)
 	default:
 		return errors.Errorf("%q is not a valid value for --type", opts.inspectType)
 	}
@@ -203,7 +203,7 @@ func inspectAll(ctx context.Context, dockerCli command.Cli, getSize bool, typeCo
 				return v, raw, err
 			}
 			if getSize && !inspectData.isSizeSupported {
-				fmt.Fprintf(dockerCli.Err(), "WARNING: --size ignored for %s\n", inspectData.objectType)
+				command.PrintWarning(dockerCli, "--size ignored for %s", inspectData.objectType)
 			}
 			return v, raw, err
 		}
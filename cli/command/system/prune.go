@@ -2,6 +2,7 @@ package system
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"text/template"
 
@@ -20,9 +21,11 @@ import (
 
 type pruneOptions struct {
 	force           bool
+	forceProtected  bool
 	all             bool
 	pruneVolumes    bool
 	pruneBuildCache bool
+	interactive     bool
 	filter          opts.FilterOpt
 }
 
@@ -45,6 +48,8 @@ func newPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images not just dangling ones")
 	flags.BoolVar(&options.pruneVolumes, "volumes", false, "Prune volumes")
+	flags.BoolVarP(&options.interactive, "interactive", "i", false, "Select the objects to remove")
+	command.AddForceProtectedFlag(flags, &options.forceProtected)
 	flags.Var(&options.filter, "filter", "Provide filter values (e.g. 'label=<key>=<value>')")
 	// "filter" flag is available in 1.28 (docker 17.04) and up
 	flags.SetAnnotation("filter", "version", []string{"1.28"})
@@ -63,17 +68,31 @@ func runPrune(dockerCli command.Cli, options pruneOptions) error {
 	if options.pruneVolumes && options.filter.Value().Contains("until") {
 		return fmt.Errorf(`ERROR: The "until" filter is not supported with "--volumes"`)
 	}
-	if !options.force && !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), confirmationMessage(options)) {
+	if options.interactive {
+		if options.force {
+			return fmt.Errorf(`ERROR: "--interactive" and "--force" cannot be used together`)
+		}
+		return runInteractivePrune(dockerCli, options)
+	}
+	// The protection of the objects is checked before the confirmation
+	if err := checkProtected(dockerCli, options); err != nil {
+		return err
+	}
+	if !command.ConfirmPrune(dockerCli, options.force, confirmationMessage(options), prunedCommands(options)...) {
 		return nil
 	}
+	containerPrune, networkPrune, volumePrune, imagePrune := container.RunPrune, network.RunPrune, volume.RunPrune, image.RunPrune
+	if options.forceProtected {
+		containerPrune, networkPrune, volumePrune, imagePrune = container.RunPruneForceProtected, network.RunPruneForceProtected, volume.RunPruneForceProtected, image.RunPruneForceProtected
+	}
 	pruneFuncs := []func(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error){
-		container.RunPrune,
-		network.RunPrune,
+		containerPrune,
+		networkPrune,
 	}
 	if options.pruneVolumes {
-		pruneFuncs = append(pruneFuncs, volume.RunPrune)
+		pruneFuncs = append(pruneFuncs, volumePrune)
 	}
-	pruneFuncs = append(pruneFuncs, image.RunPrune)
+	pruneFuncs = append(pruneFuncs, imagePrune)
 	if options.pruneBuildCache {
 		pruneFuncs = append(pruneFuncs, builder.CachePrune)
 	}
@@ -95,6 +114,50 @@ func runPrune(dockerCli command.Cli, options pruneOptions) error {
 	return nil
 }
 
+// checkProtected returns an error if the "label!" prune filters would prune
+// objects protected by the protection label, before any object is pruned.
+// The build cache has no labels, so it is never protected.
+func checkProtected(dockerCli command.Cli, options pruneOptions) error {
+	ctx := context.Background()
+	listers := []command.ProtectedLister{
+		command.ProtectedContainers(ctx, dockerCli.Client()),
+		command.ProtectedNetworks(ctx, dockerCli.Client()),
+	}
+	if options.pruneVolumes {
+		listers = append(listers, command.ProtectedVolumes(ctx, dockerCli.Client()))
+	}
+	listers = append(listers, command.ProtectedImages(ctx, dockerCli.Client(), options.all))
+	listProtected := func() (map[string]map[string]string, error) {
+		protected := map[string]map[string]string{}
+		for _, list := range listers {
+			objects, err := list()
+			if err != nil {
+				return nil, err
+			}
+			for object, labels := range objects {
+				protected[object] = labels
+			}
+		}
+		return protected, nil
+	}
+	_, err := command.ExcludeProtected(command.PruneFilters(dockerCli, options.filter.Value().Clone()), options.forceProtected, listProtected)
+	return err
+}
+
+// prunedCommands returns the prune commands run by the prune, whose
+// confirmation can be required by the configuration file.
+func prunedCommands(options pruneOptions) []string {
+	commands := []string{"system", "container", "network"}
+	if options.pruneVolumes {
+		commands = append(commands, "volume")
+	}
+	commands = append(commands, "image")
+	if options.pruneBuildCache {
+		commands = append(commands, "builder")
+	}
+	return commands
+}
+
 // confirmationMessage constructs a confirmation message that depends on the cli options.
 func confirmationMessage(options pruneOptions) string {
 	t := template.Must(template.New("confirmation message").Parse(confirmationTemplate))
@@ -118,6 +181,9 @@ func confirmationMessage(options pruneOptions) string {
 			warnings = append(warnings, "all dangling build cache")
 		}
 	}
+	if options.forceProtected {
+		warnings = append(warnings, "including the objects protected by the "+command.ProtectedLabel+"=true label")
+	}
 	if len(options.filter.String()) > 0 {
 		warnings = append(warnings, "Elements to be pruned will be filtered with:")
 		warnings = append(warnings, "label="+options.filter.String())
@@ -0,0 +1,312 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/builder"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// pruneCandidate is an object that would be removed by `docker system prune`
+type pruneCandidate struct {
+	kind string
+	id   string
+	name string
+	// ref is the full ID or the name with which the object is removed
+	ref string
+	// size is the space that is expected to be reclaimed when removing the
+	// object, or -1 if unknown.
+	size int64
+	// containers are the refs of the stopped containers which use an image,
+	// and which must be removed before the image
+	containers []string
+}
+
+const (
+	candidateContainer  = "container"
+	candidateNetwork    = "network"
+	candidateVolume     = "volume"
+	candidateImage      = "image"
+	candidateBuildCache = "build cache"
+)
+
+// pruneMatcher applies the "label", "label!" and "until" prune filters on
+// the client, in the same way as the daemon does for the prune endpoints.
+type pruneMatcher struct {
+	filter filters.Args
+	until  time.Time
+	// protect excludes the objects with the protection label
+	protect bool
+}
+
+func newPruneMatcher(filter filters.Args) (*pruneMatcher, error) {
+	if err := filter.Validate(map[string]bool{"label": true, "label!": true, "until": true}); err != nil {
+		return nil, err
+	}
+	m := &pruneMatcher{filter: filter}
+	untilValues := filter.Get("until")
+	switch len(untilValues) {
+	case 0:
+	case 1:
+		ts, err := timetypes.GetTimestamp(untilValues[0], time.Now())
+		if err != nil {
+			return nil, err
+		}
+		seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+		if err != nil {
+			return nil, err
+		}
+		m.until = time.Unix(seconds, nanoseconds)
+	default:
+		return nil, errors.New("more than one until filter specified")
+	}
+	return m, nil
+}
+
+func (m *pruneMatcher) match(labels map[string]string, created time.Time) bool {
+	if m.protect && command.IsProtected(labels) {
+		return false
+	}
+	if !m.filter.MatchKVList("label", labels) {
+		return false
+	}
+	if m.filter.Contains("label!") && m.filter.MatchKVList("label!", labels) {
+		return false
+	}
+	if !m.until.IsZero() && !created.IsZero() && !created.Before(m.until) {
+		return false
+	}
+	return true
+}
+
+// runInteractivePrune lists the objects that would be pruned, and removes
+// the objects selected by the user one by one.
+func runInteractivePrune(dockerCli command.Cli, options pruneOptions) error {
+	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value().Clone())
+	matcher, err := newPruneMatcher(pruneFilters)
+	if err != nil {
+		return err
+	}
+	matcher.protect = !options.forceProtected
+	candidates, err := listPruneCandidates(context.Background(), dockerCli, options, matcher)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		fmt.Fprintln(dockerCli.Out(), "Nothing to remove")
+		return nil
+	}
+
+	items := make([]command.SelectionItem, 0, len(candidates))
+	for _, c := range candidates {
+		size := "-"
+		if c.size >= 0 {
+			size = units.HumanSize(float64(c.size))
+		}
+		items = append(items, command.SelectionItem{
+			Columns:  []string{c.kind, c.id, c.name, size},
+			Selected: true,
+		})
+	}
+	if !command.PromptForSelection(dockerCli.In(), dockerCli.Out(), "The following objects will be removed:", items) {
+		return nil
+	}
+
+	var (
+		spaceReclaimed uint64
+		failed         int
+		deleted        = map[string][]string{}
+		removed        = map[string]bool{}
+		ctx            = context.Background()
+	)
+	for i, c := range candidates {
+		if !items[i].Selected {
+			continue
+		}
+		if container := firstNotRemoved(c.containers, removed); container != "" {
+			fmt.Fprintf(dockerCli.Err(), "Skipping %s %s: it is used by container %s, which was not removed\n", c.kind, c.id, stringid.TruncateID(container))
+			continue
+		}
+		if c.kind == candidateBuildCache {
+			spc, output, err := builder.CachePrune(dockerCli, options.all, options.filter)
+			if err != nil {
+				fmt.Fprintf(dockerCli.Err(), "Error removing build cache: %s\n", err)
+				failed++
+				continue
+			}
+			spaceReclaimed += spc
+			if output != "" {
+				fmt.Fprintln(dockerCli.Out(), output)
+			}
+			continue
+		}
+		if err := removePruneCandidate(ctx, dockerCli, c); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "Error removing %s %s: %s\n", c.kind, c.id, err)
+			failed++
+			continue
+		}
+		removed[c.ref] = true
+		deleted[c.kind] = append(deleted[c.kind], c.id)
+		if c.size > 0 {
+			spaceReclaimed += uint64(c.size)
+		}
+	}
+
+	for _, section := range []struct{ kind, title string }{
+		{kind: candidateContainer, title: "Deleted Containers:"},
+		{kind: candidateNetwork, title: "Deleted Networks:"},
+		{kind: candidateVolume, title: "Deleted Volumes:"},
+		{kind: candidateImage, title: "Deleted Images:"},
+	} {
+		if ids := deleted[section.kind]; len(ids) > 0 {
+			fmt.Fprintf(dockerCli.Out(), "%s\n%s\n\n", section.title, strings.Join(ids, "\n"))
+		}
+	}
+	fmt.Fprintln(dockerCli.Out(), "Total reclaimed space:", units.HumanSize(float64(spaceReclaimed)))
+	if failed > 0 {
+		return errors.Errorf("failed to remove %d object(s)", failed)
+	}
+	return nil
+}
+
+// firstNotRemoved returns the first of the refs which was not removed, or
+// an empty string if all were
+func firstNotRemoved(refs []string, removed map[string]bool) string {
+	for _, ref := range refs {
+		if !removed[ref] {
+			return ref
+		}
+	}
+	return ""
+}
+
+// listPruneCandidates returns the objects that match the criteria used by
+// the daemon's prune endpoints, in the order in which they are pruned by
+// `docker system prune`.
+func listPruneCandidates(ctx context.Context, dockerCli command.Cli, options pruneOptions, matcher *pruneMatcher) ([]pruneCandidate, error) {
+	du, err := dockerCli.Client().DiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []pruneCandidate
+
+	// The containers which are kept, and the candidates, by image, so that
+	// the images only used by the candidates are candidates too
+	keptContainers := map[string]bool{}
+	imageContainers := map[string][]string{}
+	for _, c := range du.Containers {
+		if c.State == "running" || c.State == "paused" || c.State == "restarting" || !matcher.match(c.Labels, time.Unix(c.Created, 0)) {
+			keptContainers[c.ImageID] = true
+			continue
+		}
+		var name string
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		candidates = append(candidates, pruneCandidate{kind: candidateContainer, id: stringid.TruncateID(c.ID), name: name, ref: c.ID, size: c.SizeRw})
+		imageContainers[c.ImageID] = append(imageContainers[c.ImageID], c.ID)
+	}
+
+	networks, err := dockerCli.Client().NetworkList(ctx, types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("type", "custom")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, nw := range networks {
+		if nw.Scope != "local" || !matcher.match(nw.Labels, nw.Created) {
+			continue
+		}
+		// The list endpoint does not return the network's endpoints
+		inspect, err := dockerCli.Client().NetworkInspect(ctx, nw.ID, types.NetworkInspectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(inspect.Containers) > 0 {
+			continue
+		}
+		candidates = append(candidates, pruneCandidate{kind: candidateNetwork, id: stringid.TruncateID(nw.ID), name: nw.Name, ref: nw.ID, size: -1})
+	}
+
+	if options.pruneVolumes {
+		for _, v := range du.Volumes {
+			if v.UsageData != nil && v.UsageData.RefCount > 0 {
+				continue
+			}
+			if !matcher.match(v.Labels, time.Time{}) {
+				continue
+			}
+			size := int64(-1)
+			if v.UsageData != nil {
+				size = v.UsageData.Size
+			}
+			candidates = append(candidates, pruneCandidate{kind: candidateVolume, id: v.Name, ref: v.Name, size: size})
+		}
+	}
+
+	for _, i := range du.Images {
+		dangling := len(i.RepoTags) == 0 || (len(i.RepoTags) == 1 && i.RepoTags[0] == "<none>:<none>")
+		// The images used by the containers which are removed are pruned
+		// once the containers are removed
+		if keptContainers[i.ID] || (i.Containers > 0 && len(imageContainers[i.ID]) == 0) || (!options.all && !dangling) {
+			continue
+		}
+		if !matcher.match(i.Labels, time.Unix(i.Created, 0)) {
+			continue
+		}
+		name := "<none>"
+		if !dangling {
+			name = strings.Join(i.RepoTags, ", ")
+		}
+		// Shared layers are not reclaimed when removing a single image
+		size := int64(-1)
+		if i.SharedSize != -1 {
+			size = i.Size - i.SharedSize
+		}
+		candidates = append(candidates, pruneCandidate{kind: candidateImage, id: stringid.TruncateID(i.ID), name: name, ref: i.ID, size: size, containers: imageContainers[i.ID]})
+	}
+
+	if options.pruneBuildCache {
+		var size int64
+		for _, bc := range du.BuildCache {
+			if !bc.InUse && !bc.Shared {
+				size += bc.Size
+			}
+		}
+		name := "all dangling build cache"
+		if options.all {
+			name = "all build cache"
+		}
+		if size > 0 {
+			candidates = append(candidates, pruneCandidate{kind: candidateBuildCache, id: "-", name: name, size: size})
+		}
+	}
+	return candidates, nil
+}
+
+func removePruneCandidate(ctx context.Context, dockerCli command.Cli, c pruneCandidate) error {
+	client := dockerCli.Client()
+	switch c.kind {
+	case candidateContainer:
+		return client.ContainerRemove(ctx, c.ref, types.ContainerRemoveOptions{})
+	case candidateNetwork:
+		return client.NetworkRemove(ctx, c.ref)
+	case candidateVolume:
+		return client.VolumeRemove(ctx, c.ref, false)
+	case candidateImage:
+		// The image is removed by ID with all its tags, as the prune
+		// endpoint does, rather than failing for the images with several tags
+		_, err := client.ImageRemove(ctx, c.ref, types.ImageRemoveOptions{Force: true, PruneChildren: true})
+		return err
+	}
+	return errors.Errorf("unknown object type %q", c.kind)
+}
@@ -1,9 +1,16 @@
 package system
 
 import (
+	"context"
+	"io/ioutil"
+	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -20,3 +27,165 @@ Are you sure you want to continue? [y/N] `
 	assert.Check(t, is.Equal(expected, cli.OutBuffer().String()))
 
 }
+
+func TestPruneInteractive(t *testing.T) {
+	var removed []string
+	remove := func(kind string) func(context.Context, string) error {
+		return func(_ context.Context, id string) error {
+			removed = append(removed, kind+" "+id)
+			return nil
+		}
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		diskUsageFunc: func(context.Context) (types.DiskUsage, error) {
+			return types.DiskUsage{
+				Containers: []*types.Container{
+					{ID: "running", Names: []string{"/web"}, State: "running"},
+					{ID: "exited1", Names: []string{"/old-web"}, State: "exited", SizeRw: 1000},
+					{ID: "exited2", Names: []string{"/keep-me"}, State: "exited", SizeRw: 2000, Labels: map[string]string{"keep": "true"}},
+					{ID: "exited3", Names: []string{"/new"}, State: "created", SizeRw: 3000},
+					{ID: "exited4", Names: []string{"/protected-db"}, State: "exited", SizeRw: 4000, Labels: map[string]string{"cli.docker.io/protected": "true"}},
+				},
+				Images: []*types.ImageSummary{
+					{ID: "sha256:dangling", RepoTags: []string{"<none>:<none>"}, Size: 500, SharedSize: 0},
+					{ID: "sha256:tagged", RepoTags: []string{"alpine:latest"}, Size: 600, SharedSize: 0},
+				},
+				Volumes: []*types.Volume{
+					{Name: "unused", UsageData: &types.VolumeUsageData{RefCount: 0, Size: 100}},
+				},
+			}, nil
+		},
+		networkListFunc: func(context.Context, types.NetworkListOptions) ([]types.NetworkResource, error) {
+			return []types.NetworkResource{
+				{ID: "net1", Name: "unused-net", Scope: "local"},
+				{ID: "net2", Name: "used-net", Scope: "local"},
+			}, nil
+		},
+		networkInspectFunc: func(_ context.Context, id string) (types.NetworkResource, error) {
+			if id == "net2" {
+				return types.NetworkResource{Containers: map[string]types.EndpointResource{"c": {}}}, nil
+			}
+			return types.NetworkResource{}, nil
+		},
+		containerRemoveFunc: remove("container"),
+		imageRemoveFunc: func(_ context.Context, id string, options types.ImageRemoveOptions) error {
+			assert.Check(t, options.Force)
+			removed = append(removed, "image "+id)
+			return nil
+		},
+		networkRemoveFunc: remove("network"),
+		volumeRemoveFunc:  remove("volume"),
+	})
+	// Deselect the third candidate (container "new") before confirming
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader("2\n\n"))))
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--interactive", "--filter", "label!=keep"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual([]string{"container exited1", "network net1", "image sha256:dangling"}, removed))
+	output := cli.OutBuffer().String()
+	assert.Check(t, is.Contains(output, "[ ] 2  container  exited3   new         3kB"))
+	assert.Check(t, !strings.Contains(output, "keep-me"))
+	assert.Check(t, !strings.Contains(output, "protected-db"))
+	assert.Check(t, !strings.Contains(output, "alpine"))
+	assert.Check(t, is.Contains(output, "Deleted Containers:\nexited1\n\nDeleted Networks:\nnet1\n\nDeleted Images:\ndangling\n\n"))
+	assert.Check(t, is.Contains(output, "Total reclaimed space: 1.5kB"))
+}
+
+func TestPruneInteractiveWithForce(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{version: "1.30"})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--interactive", "--force"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), `"--interactive" and "--force" cannot be used together`)
+}
+
+func TestPruneProtectedWithLabelFilter(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		containerListFunc: func(_ context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+			assert.Check(t, options.Filters.ExactMatch("label", command.ProtectedLabel+"=true"))
+			assert.Check(t, !options.Filters.ExactMatch("status", "running"))
+			return []types.Container{
+				{ID: "c1", Names: []string{"/db"}, Labels: map[string]string{command.ProtectedLabel: "true"}},
+				{ID: "c2", Names: []string{"/kept"}, Labels: map[string]string{command.ProtectedLabel: "true", "keep": "1"}},
+			}, nil
+		},
+		imageListFunc: func(_ context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+			assert.Check(t, options.Filters.ExactMatch("dangling", "true"))
+			return []types.ImageSummary{{ID: "sha256:app", RepoTags: []string{"app:1"}, Labels: map[string]string{command.ProtectedLabel: "true"}}}, nil
+		},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--force", "--filter", "label!=keep"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.ErrorContains(t, cmd.Execute(), `the "label!" filter cannot be combined with the protection of the objects with the cli.docker.io/protected=true label, and would prune container db, image app:1`)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+}
+
+func TestPruneLabelFilterWithoutProtected(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{version: "1.30"})
+	cli.SetConfigFile(&configfile.ConfigFile{PruneFilters: []string{"label!=keep"}})
+	cmd := newPruneCommand(cli)
+	cmd.SetOutput(ioutil.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Are you sure you want to continue? [y/N] "))
+}
+
+func TestPruneInteractiveImagesOfRemovedContainers(t *testing.T) {
+	testCases := []struct {
+		input           string
+		expectedRemoved []string
+		expectedErr     string
+	}{
+		{
+			input:           "\n",
+			expectedRemoved: []string{"container sha256:c1", "image sha256:app"},
+		},
+		{
+			// Deselect the container, which keeps using the image
+			input:           "1\n\n",
+			expectedRemoved: nil,
+			expectedErr:     "Skipping image app: it is used by container c1, which was not removed",
+		},
+	}
+	for _, tc := range testCases {
+		var removed []string
+		cli := test.NewFakeCli(&fakeClient{
+			version: "1.30",
+			diskUsageFunc: func(context.Context) (types.DiskUsage, error) {
+				return types.DiskUsage{
+					Containers: []*types.Container{
+						{ID: "sha256:c1", Names: []string{"/app-1"}, ImageID: "sha256:app", State: "exited"},
+						{ID: "sha256:c2", Names: []string{"/db-1"}, ImageID: "sha256:db", State: "running"},
+					},
+					Images: []*types.ImageSummary{
+						{ID: "sha256:app", RepoTags: []string{"app:1", "app:latest"}, Containers: 1, SharedSize: -1},
+						{ID: "sha256:db", RepoTags: []string{"db:latest"}, Containers: 1, SharedSize: -1},
+					},
+				}, nil
+			},
+			networkListFunc: func(context.Context, types.NetworkListOptions) ([]types.NetworkResource, error) {
+				return nil, nil
+			},
+			containerRemoveFunc: func(_ context.Context, id string) error {
+				removed = append(removed, "container "+id)
+				return nil
+			},
+			imageRemoveFunc: func(_ context.Context, id string, options types.ImageRemoveOptions) error {
+				assert.Check(t, options.Force)
+				removed = append(removed, "image "+id)
+				return nil
+			},
+		})
+		cli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader(tc.input))))
+		cmd := newPruneCommand(cli)
+		cmd.SetArgs([]string{"--interactive", "--all"})
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.DeepEqual(tc.expectedRemoved, removed))
+		assert.Check(t, is.Contains(cli.OutBuffer().String(), "[x] 2  image      app  app:1, app:latest"))
+		assert.Check(t, !strings.Contains(cli.OutBuffer().String(), "db:latest"))
+		assert.Check(t, is.Contains(cli.ErrBuffer().String(), tc.expectedErr))
+	}
+}
@@ -0,0 +1,277 @@
+package system
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/redact"
+	"github.com/docker/cli/cli/version"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// supportBundleManifest is the manifest of a support bundle, stored in its
+// bundle.json file
+type supportBundleManifest struct {
+	Created time.Time `json:"created"`
+	Files   []string  `json:"files"`
+	// Errors are the failures to collect the diagnostics, by file, such as
+	// the failure to reach the daemon.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+type supportBundleOptions struct {
+	output       string
+	auditEntries int
+}
+
+// supportBundleFile is a file of a support bundle
+type supportBundleFile struct {
+	name string
+	data []byte
+}
+
+func newSupportBundleCommand(dockerCli command.Cli) *cobra.Command {
+	var opts supportBundleOptions
+
+	cmd := &cobra.Command{
+		Use:   "support-bundle [OPTIONS]",
+		Short: "Gather the diagnostics of the client into an archive for bug reports",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSupportBundle(dockerCli, cmd.Root(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", `Write the archive to a file, or to STDOUT with "-" (default "docker-support-bundle-<timestamp>.tar.gz")`)
+	flags.IntVar(&opts.auditEntries, "audit-entries", 100, "Number of the most recent entries of the audit log to include")
+
+	return cmd
+}
+
+func runSupportBundle(dockerCli command.Cli, rootCmd *cobra.Command, opts supportBundleOptions) error {
+	now := time.Now().UTC()
+	output := opts.output
+	switch output {
+	case "":
+		output = "docker-support-bundle-" + now.Format("20060102-150405") + ".tar.gz"
+	case "-":
+		if dockerCli.Out().IsTerminal() {
+			return errors.New("cowardly refusing to write the support bundle to a terminal. Use the -o flag with a file")
+		}
+	}
+	if output != "-" {
+		if err := command.ValidateOutputPath(output); err != nil {
+			return errors.Wrap(err, "failed to write the support bundle")
+		}
+	}
+
+	files, manifest := collectSupportBundle(dockerCli, rootCmd, opts.auditEntries)
+	manifest.Created = now
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+	files = append([]supportBundleFile{{name: "bundle.json", data: append(data, '\n')}}, files...)
+
+	var buf bytes.Buffer
+	if err := writeSupportBundle(&buf, files, now); err != nil {
+		return errors.Wrap(err, "failed to write the support bundle")
+	}
+	if output == "-" {
+		_, err := io.Copy(dockerCli.Out(), &buf)
+		return err
+	}
+	if err := command.CopyToFile(output, &buf); err != nil {
+		return errors.Wrap(err, "failed to write the support bundle")
+	}
+	// The bundle contains the details of the configuration, so it is only
+	// readable by the user until it is attached to a bug report
+	if err := os.Chmod(output, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Err(), "Support bundle written to %s\n", output)
+	if len(manifest.Errors) > 0 {
+		fmt.Fprintf(dockerCli.Err(), "%d diagnostics could not be collected, see bundle.json\n", len(manifest.Errors))
+	}
+	return nil
+}
+
+// collectSupportBundle collects the files of a support bundle. The failures to
+// collect a file are recorded in the manifest, rather than failing the bundle.
+func collectSupportBundle(dockerCli command.Cli, rootCmd *cobra.Command, auditEntries int) ([]supportBundleFile, supportBundleManifest) {
+	manifest := supportBundleManifest{Files: []string{"bundle.json"}, Errors: map[string]string{}}
+	var files []supportBundleFile
+	add := func(name string, data []byte, err error) {
+		if err != nil {
+			manifest.Errors[name] = err.Error()
+			return
+		}
+		files = append(files, supportBundleFile{name: name, data: data})
+		manifest.Files = append(manifest.Files, name)
+	}
+	addJSON := func(name string, v interface{}, err error) {
+		if err != nil {
+			add(name, nil, err)
+			return
+		}
+		data, err := redact.MarshalIndent(v)
+		add(name, data, err)
+	}
+
+	data, err := supportBundleConfigFile(dockerCli)
+	add("config.json", data, err)
+
+	var contexts interface{} = []interface{}{}
+	if s := dockerCli.ContextStore(); s != nil {
+		contexts, err = s.ListContexts()
+	}
+	addJSON("contexts.json", contexts, err)
+
+	plugins, err := pluginmanager.ListPlugins(dockerCli, rootCmd)
+	addJSON("plugins.json", plugins, err)
+
+	ctx, cancel := context.WithTimeout(dockerCli.Context(), pingTimeout)
+	defer cancel()
+	vd := versionInfo{
+		Client: clientVersion{
+			Platform:          struct{ Name string }{version.PlatformName},
+			Version:           version.Version,
+			APIVersion:        dockerCli.Client().ClientVersion(),
+			DefaultAPIVersion: dockerCli.DefaultVersion(),
+			GoVersion:         runtime.Version(),
+			GitCommit:         version.GitCommit,
+			BuildTime:         version.BuildTime,
+			Os:                runtime.GOOS,
+			Arch:              runtime.GOARCH,
+			Experimental:      dockerCli.ClientInfo().HasExperimental,
+		},
+	}
+	sv, err := dockerCli.Client().ServerVersion(ctx)
+	if err == nil {
+		vd.Server = &sv
+	} else {
+		manifest.Errors["version.json"] = err.Error()
+	}
+	addJSON("version.json", vd, nil)
+
+	var info types.Info
+	if vd.Server != nil {
+		info, err = dockerCli.Client().Info(ctx)
+	}
+	addJSON("info.json", info, err)
+
+	addJSON("doctor.json", doctorChecks(dockerCli, rootCmd), nil)
+
+	if auditLog := dockerCli.ConfigFile().AuditLog; auditLog != nil && auditLog.File != "" && auditEntries > 0 {
+		data, err := readAuditEntries(auditLog.File, auditEntries)
+		add("audit.log", data, err)
+	}
+
+	if len(manifest.Errors) == 0 {
+		manifest.Errors = nil
+	}
+	return files, manifest
+}
+
+// supportBundleConfigFile returns the configuration file, with the values of
+// its credentials and secrets redacted
+func supportBundleConfigFile(dockerCli command.Cli) ([]byte, error) {
+	configFile := dockerCli.ConfigFile()
+	if configFile.Filename != "" {
+		data, err := ioutil.ReadFile(configFile.Filename)
+		switch {
+		case err == nil:
+			return redact.JSON(data)
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+	}
+	// There is no configuration file, the defaults are used
+	return redact.MarshalIndent(configFile)
+}
+
+// readAuditEntries returns the last entries of the audit log. The arguments of
+// the entries are redacted again, in case the entries were recorded by an
+// older CLI.
+func readAuditEntries(filename string, n int) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			// The entry is not included, as it cannot be redacted
+			continue
+		}
+		if args, ok := record["args"].([]interface{}); ok {
+			strArgs := make([]string, len(args))
+			for i, arg := range args {
+				strArgs[i] = fmt.Sprint(arg)
+			}
+			record["args"] = redact.Args(strArgs)
+		}
+		if err := enc.Encode(redact.Value(record)); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSupportBundle writes the files to a gzip-compressed tar archive
+func writeSupportBundle(w io.Writer, files []supportBundleFile, modTime time.Time) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(f.data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
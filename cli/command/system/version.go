@@ -58,8 +58,9 @@ Server:{{if ne .Platform.Name ""}} {{.Platform.Name}}{{end}}
  {{- end}}{{- end}}`
 
 type versionOptions struct {
-	format     string
-	kubeConfig string
+	format      string
+	kubeConfig  string
+	allContexts bool
 }
 
 // versionInfo contains version information of both the Client, and Server
@@ -102,6 +103,9 @@ func NewVersionCommand(dockerCli command.Cli) *cobra.Command {
 		Short: "Show the Docker version information",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.allContexts {
+				return runVersionAllContexts(dockerCli, &opts)
+			}
 			return runVersion(dockerCli, &opts)
 		},
 	}
@@ -109,6 +113,7 @@ func NewVersionCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.format, "format", "f", "", "Format the output using the given Go template")
 	flags.StringVar(&opts.kubeConfig, "kubeconfig", "", "Kubernetes config file")
+	flags.BoolVar(&opts.allContexts, "all-contexts", false, "Show the versions of the daemons of all the contexts")
 	flags.SetAnnotation("kubeconfig", "kubernetes", nil)
 
 	return cmd
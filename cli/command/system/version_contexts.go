@@ -0,0 +1,105 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/version"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types/versions"
+)
+
+// contextVersionTimeout is the maximum time spent querying the versions of the
+// daemons of the contexts, which are queried concurrently
+const contextVersionTimeout = 10 * time.Second
+
+// contextVersion contains the version information of the daemon of a context
+type contextVersion struct {
+	Context              string
+	Current              bool
+	Version              string `json:",omitempty"`
+	APIVersion           string `json:"ApiVersion,omitempty"`
+	MinAPIVersion        string `json:"MinAPIVersion,omitempty"`
+	NegotiatedAPIVersion string `json:",omitempty"`
+	Compatibility        string `json:",omitempty"`
+	Error                string `json:",omitempty"`
+}
+
+func runVersionAllContexts(dockerCli command.Cli, opts *versionOptions) error {
+	clients, err := command.NewContextAPIClients(dockerCli)
+	if err != nil {
+		return err
+	}
+	defer command.CloseContextAPIClients(clients)
+
+	results := make([]contextVersion, len(clients))
+	for i, c := range clients {
+		results[i] = contextVersion{Context: c.Name, Current: c.Current}
+		if c.Err != nil {
+			results[i].Error = c.Err.Error()
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), contextVersionTimeout)
+	defer cancel()
+	// The errors are reported with the version of each context
+	_ = command.RunInContexts(ctx, clients, func(ctx context.Context, i int, c command.ContextAPIClient) error {
+		sv, err := c.Client.ServerVersion(ctx)
+		if err != nil {
+			results[i].Error = err.Error()
+			return nil
+		}
+		results[i].Version = sv.Version
+		results[i].APIVersion = sv.APIVersion
+		results[i].MinAPIVersion = sv.MinAPIVersion
+		results[i].NegotiatedAPIVersion = c.Client.ClientVersion()
+		results[i].Compatibility = apiCompatibility(sv.APIVersion, sv.MinAPIVersion)
+		return nil
+	})
+
+	if opts.format != "" {
+		tmpl, err := templates.Parse(opts.format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: "Template parsing error: " + err.Error()}
+		}
+		for _, r := range results {
+			if err := tmpl.Execute(dockerCli.Out(), r); err != nil {
+				return err
+			}
+			fmt.Fprintln(dockerCli.Out())
+		}
+		return nil
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "Client: %s (API version %s)\n\n", version.Version, api.DefaultVersion)
+	w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tSERVER VERSION\tAPI VERSION\tMIN API VERSION\tCOMPATIBILITY")
+	for _, r := range results {
+		name := r.Context
+		if r.Current {
+			name += " *"
+		}
+		if r.Error != "" {
+			fmt.Fprintf(w, "%s\t\t\t\terror: %s\n", name, r.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, r.Version, r.APIVersion, r.MinAPIVersion, r.Compatibility)
+	}
+	return w.Flush()
+}
+
+// apiCompatibility describes whether the client can use the API of a daemon
+func apiCompatibility(apiVersion, minAPIVersion string) string {
+	switch {
+	case minAPIVersion != "" && versions.LessThan(api.DefaultVersion, minAPIVersion):
+		return "incompatible (client too old)"
+	case versions.LessThan(apiVersion, api.DefaultVersion):
+		return "downgraded to API version " + apiVersion
+	default:
+		return "compatible"
+	}
+}
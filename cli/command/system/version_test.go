@@ -8,9 +8,14 @@ import (
 
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
 	"gotest.tools/golden"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/types"
 )
 
@@ -111,3 +116,37 @@ func TestVersionAlign(t *testing.T) {
 	assert.Check(t, golden.String(cli.OutBuffer().String(), "docker-client-version.golden"))
 	assert.Check(t, is.Equal("", cli.ErrBuffer().String()))
 }
+
+func TestVersionAllContexts(t *testing.T) {
+	dir := fs.NewDir(t, "version-contexts")
+	defer dir.Remove()
+	contextStore := store.New(dir.Path(), store.NewConfig(
+		func() interface{} { return &command.DockerContext{} },
+		store.EndpointTypeGetter(docker.DockerEndpoint, func() interface{} { return &docker.EndpointMeta{} }),
+	))
+	assert.NilError(t, contextStore.CreateOrUpdateContext(store.ContextMetadata{
+		Name:     "remote",
+		Metadata: command.DockerContext{},
+		Endpoints: map[string]interface{}{
+			docker.DockerEndpoint: docker.EndpointMeta{Host: "unix://" + dir.Join("missing.sock")},
+		},
+	}))
+
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		serverVersion: func(ctx context.Context) (types.Version, error) {
+			return types.Version{Version: "17.06.2-ee-15", APIVersion: "1.30", MinAPIVersion: "1.12"}, nil
+		},
+	})
+	cli.SetContextStore(contextStore)
+	cmd := NewVersionCommand(cli)
+	cmd.SetArgs([]string{"--all-contexts", "--format", "{{.Context}} {{.Current}} {{.NegotiatedAPIVersion}} {{.Compatibility}} {{if .Error}}error{{end}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("default true 1.30 downgraded to API version 1.30 \nremote false   error\n", cli.OutBuffer().String()))
+}
+
+func TestAPICompatibility(t *testing.T) {
+	assert.Check(t, is.Equal("compatible", apiCompatibility(api.DefaultVersion, "1.12")))
+	assert.Check(t, is.Equal("downgraded to API version 1.30", apiCompatibility("1.30", "1.12")))
+	assert.Check(t, is.Equal("incompatible (client too old)", apiCompatibility("99.0", "98.0")))
+}
@@ -46,7 +46,7 @@ func NewTaskFormat(source string, quiet bool) formatter.Format {
 func FormatWrite(ctx formatter.Context, tasks []swarm.Task, names map[string]string, nodes map[string]string) error {
 	render := func(format func(subContext formatter.SubContext) error) error {
 		for _, task := range tasks {
-			taskCtx := &taskContext{trunc: ctx.Trunc, task: task, name: names[task.ID], node: nodes[task.ID]}
+			taskCtx := &taskContext{trunc: ctx.Trunc, widths: ctx.Widths, task: task, name: names[task.ID], node: nodes[task.ID]}
 			if err := format(taskCtx); err != nil {
 				return err
 			}
@@ -69,10 +69,11 @@ func FormatWrite(ctx formatter.Context, tasks []swarm.Task, names map[string]str
 
 type taskContext struct {
 	formatter.HeaderContext
-	trunc bool
-	task  swarm.Task
-	name  string
-	node  string
+	trunc  bool
+	widths map[string]int
+	task   swarm.Task
+	name   string
+	node   string
 }
 
 func (c *taskContext) MarshalJSON() ([]byte, error) {
@@ -124,8 +125,8 @@ func (c *taskContext) CurrentState() string {
 func (c *taskContext) Error() string {
 	// Trim and quote the error message.
 	taskErr := c.task.Status.Err
-	if c.trunc && len(taskErr) > maxErrLength {
-		taskErr = fmt.Sprintf("%s…", taskErr[:maxErrLength-1])
+	if c.trunc {
+		taskErr = formatter.TruncateColumn(taskErr, c.widths, "Error", maxErrLength)
 	}
 	if len(taskErr) > 0 {
 		taskErr = fmt.Sprintf("\"%s\"", taskErr)
@@ -41,10 +41,12 @@ func Print(ctx context.Context, dockerCli command.Cli, tasks []swarm.Task, resol
 	names := map[string]string{}
 	nodes := map[string]string{}
 
+	truncation := formatter.NewTruncation(dockerCli.NoTrunc(), dockerCli.ConfigFile().Truncation)
 	tasksCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewTaskFormat(format, quiet),
-		Trunc:  trunc,
+		Trunc:  truncation.Trunc(!trunc),
+		Widths: truncation.Widths("tasks"),
 	}
 
 	prevName := ""
@@ -0,0 +1,117 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/trust/cosign"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type verifyOptions struct {
+	remote string
+	verifierOptions
+}
+
+// verifierOptions override the signature verification configuration of a
+// registry
+type verifierOptions struct {
+	key                   string
+	certificateIdentity   string
+	certificateOIDCIssuer string
+	fulcioRoots           string
+	rekorPublicKey        string
+}
+
+func newVerifyCommand(dockerCli command.Cli) *cobra.Command {
+	options := verifyOptions{}
+	cmd := &cobra.Command{
+		Use:   "verify [OPTIONS] IMAGE[:TAG|@DIGEST]",
+		Short: "Verify the cosign signatures of an image",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.remote = args[0]
+			return runVerify(dockerCli, options)
+		},
+	}
+	addVerifierFlags(cmd.Flags(), &options.verifierOptions)
+	return cmd
+}
+
+func addVerifierFlags(flags *pflag.FlagSet, options *verifierOptions) {
+	flags.StringVar(&options.key, "key", "", "Path to the public key of the signatures")
+	flags.StringVar(&options.certificateIdentity, "certificate-identity", "", "Identity of the signer in the certificates of the signatures")
+	flags.StringVar(&options.certificateOIDCIssuer, "certificate-oidc-issuer", "", "OIDC issuer of the identity of the signer")
+	flags.StringVar(&options.fulcioRoots, "fulcio-roots", "", "Path to the certificates of the Fulcio certificate authority")
+	flags.StringVar(&options.rekorPublicKey, "rekor-public-key", "", "Path to the public key of the Rekor transparency log")
+}
+
+func runVerify(dockerCli command.Cli, options verifyOptions) error {
+	ctx := context.Background()
+	ref, err := reference.ParseNormalizedNamed(options.remote)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	verifier, err := verifierForOptions(dockerCli, ref, options.verifierOptions)
+	if err != nil {
+		return err
+	}
+
+	registryClient := dockerCli.RegistryClient(false)
+	var dgst digest.Digest
+	if canonical, ok := ref.(reference.Canonical); ok {
+		dgst = canonical.Digest()
+	} else {
+		content, err := registryClient.GetManifestContent(ctx, ref)
+		if err != nil {
+			return errors.Wrapf(err, "unable to resolve %s", reference.FamiliarString(ref))
+		}
+		dgst = content.Digest
+	}
+
+	verified, err := verifier.Verify(ctx, registryClient, ref, dgst)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "Verified cosign signature of %s@%s\n", reference.FamiliarName(ref), dgst)
+	if verified.Identity != "" {
+		fmt.Fprintf(dockerCli.Out(), "Signed by %s (%s)\n", verified.Identity, verified.Issuer)
+	}
+	if !verified.IntegratedTime.IsZero() {
+		fmt.Fprintf(dockerCli.Out(), "Recorded in the Rekor transparency log at index %d on %s\n", verified.LogIndex, verified.IntegratedTime.UTC().Format("2006-01-02 15:04:05 MST"))
+	}
+	return nil
+}
+
+// verifierForOptions returns the verifier of the signature verification
+// configuration of the registry of the reference, overridden by the options.
+func verifierForOptions(dockerCli command.Cli, ref reference.Named, options verifierOptions) (*cosign.Verifier, error) {
+	config, _ := cosign.ConfigForReference(dockerCli.ConfigFile(), ref)
+	if options.key != "" {
+		config.PublicKey = options.key
+	}
+	if options.certificateIdentity != "" {
+		config.CertificateIdentity = options.certificateIdentity
+	}
+	if options.certificateOIDCIssuer != "" {
+		config.CertificateOIDCIssuer = options.certificateOIDCIssuer
+	}
+	if options.fulcioRoots != "" {
+		config.FulcioRoots = options.fulcioRoots
+	}
+	if options.rekorPublicKey != "" {
+		config.RekorPublicKey = options.rekorPublicKey
+	}
+	if config.PublicKey == "" && config.FulcioRoots == "" {
+		return nil, errors.Errorf("no cosign signature verification configured for %s: use --key, or --fulcio-roots", reference.Domain(ref))
+	}
+	return cosign.NewVerifier(config)
+}
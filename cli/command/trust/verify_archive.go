@@ -0,0 +1,211 @@
+package trust
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/trust/cosign"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// maxArchiveFileSize is the maximum size of the JSON files of an image
+// archive which are read
+const maxArchiveFileSize = 16 * 1024 * 1024
+
+type verifyArchiveOptions struct {
+	input string
+	verifierOptions
+}
+
+func newVerifyArchiveCommand(dockerCli command.Cli) *cobra.Command {
+	options := verifyArchiveOptions{}
+	cmd := &cobra.Command{
+		Use:   "verify-archive [OPTIONS] FILE",
+		Short: "Verify the cosign signatures of the images of an archive, without contacting the registries",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.input = args[0]
+			return runVerifyArchive(dockerCli, options)
+		},
+	}
+	addVerifierFlags(cmd.Flags(), &options.verifierOptions)
+	return cmd
+}
+
+// archiveManifestItem is an image of the "manifest.json" file of an archive of
+// "docker save"
+type archiveManifestItem struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// imageArchive is the content of an archive of "docker save" which is needed
+// to verify its images
+type imageArchive struct {
+	manifest   []archiveManifestItem
+	signatures *cosign.Archive
+	// digests are the digests of the files of the archive, by name
+	digests map[string]digest.Digest
+	// files are the contents of the JSON files of the archive, by name
+	files map[string][]byte
+}
+
+func runVerifyArchive(dockerCli command.Cli, options verifyArchiveOptions) error {
+	f, err := os.Open(options.input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	archive, err := readImageArchive(f)
+	if err != nil {
+		return errors.Wrapf(err, "invalid image archive %s", options.input)
+	}
+
+	ctx := context.Background()
+	for _, item := range archive.manifest {
+		imageID, err := archive.verifyImage(item)
+		if err != nil {
+			return err
+		}
+		if len(item.RepoTags) == 0 {
+			return errors.Errorf("image %s of the archive has no tag to verify its signatures", imageID)
+		}
+		for _, tag := range item.RepoTags {
+			ref, dgst, err := archive.signedImage(tag)
+			if err != nil {
+				return err
+			}
+			verifier, err := verifierForOptions(dockerCli, ref, options.verifierOptions)
+			if err != nil {
+				return err
+			}
+			if _, err := verifier.Verify(ctx, archive.signatures, ref, dgst); err != nil {
+				return err
+			}
+			if _, err := cosign.ResolveManifest(ctx, archive.signatures, ref, dgst, imageID); err != nil {
+				return err
+			}
+			fmt.Fprintf(dockerCli.Out(), "Verified %s@%s\n", tag, dgst)
+		}
+	}
+	return nil
+}
+
+// verifyImage verifies that the layers of the image are the ones of its
+// configuration, and returns its ID.
+func (a *imageArchive) verifyImage(item archiveManifestItem) (digest.Digest, error) {
+	imageID, ok := a.digests[item.Config]
+	if !ok {
+		return "", errors.Errorf("invalid image archive: missing %s", item.Config)
+	}
+	var config ocispec.Image
+	if err := json.Unmarshal(a.files[item.Config], &config); err != nil {
+		return "", errors.Wrapf(err, "invalid configuration of image %s", imageID)
+	}
+	if len(config.RootFS.DiffIDs) != len(item.Layers) {
+		return "", errors.Errorf("the layers of image %s do not match its configuration", imageID)
+	}
+	for i, layer := range item.Layers {
+		if a.digests[layer] != config.RootFS.DiffIDs[i] {
+			return "", errors.Errorf("layer %s of image %s does not match its configuration", layer, imageID)
+		}
+	}
+	return imageID, nil
+}
+
+// signedImage returns the reference of the image with the given tag in the
+// signature material of the archive, and the digest of its manifest.
+func (a *imageArchive) signedImage(tag string) (reference.Named, digest.Digest, error) {
+	ref, err := reference.ParseNormalizedNamed(tag)
+	if err != nil {
+		return nil, "", err
+	}
+	if a.signatures != nil {
+		for _, img := range a.signatures.Images {
+			if img.Reference == ref.String() {
+				return ref, img.Digest, nil
+			}
+		}
+	}
+	return nil, "", errors.Errorf("the archive has no signatures of %s: use \"docker save --include-signatures\"", tag)
+}
+
+func readImageArchive(r io.Reader) (*imageArchive, error) {
+	archive := &imageArchive{
+		digests: map[string]digest.Digest{},
+		files:   map[string][]byte{},
+	}
+	links := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean(hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeSymlink:
+			links[name] = path.Join(path.Dir(name), hdr.Linkname)
+			continue
+		case tar.TypeReg, tar.TypeRegA:
+		default:
+			continue
+		}
+
+		digester := digest.Canonical.Digester()
+		var content io.Reader = tr
+		if strings.HasSuffix(name, ".json") {
+			if hdr.Size > maxArchiveFileSize {
+				return nil, errors.Errorf("%s is larger than %d bytes", name, maxArchiveFileSize)
+			}
+			b, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			archive.files[name] = b
+			content = bytes.NewReader(b)
+		}
+		if _, err := io.Copy(digester.Hash(), content); err != nil {
+			return nil, err
+		}
+		archive.digests[name] = digester.Digest()
+	}
+	for name, target := range links {
+		if dgst, ok := archive.digests[target]; ok {
+			archive.digests[name] = dgst
+		}
+	}
+
+	manifest, ok := archive.files["manifest.json"]
+	if !ok {
+		return nil, errors.New("missing manifest.json")
+	}
+	if err := json.Unmarshal(manifest, &archive.manifest); err != nil {
+		return nil, errors.Wrap(err, "invalid manifest.json")
+	}
+	if signatures, ok := archive.files[cosign.ArchiveFile]; ok {
+		archive.signatures = cosign.NewArchive()
+		if err := json.Unmarshal(signatures, archive.signatures); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", cosign.ArchiveFile)
+		}
+	}
+	return archive, nil
+}
@@ -0,0 +1,128 @@
+package trust
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/cli/trust/cosign"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+var (
+	archiveLayer  = []byte("layer")
+	archiveConfig = []byte(fmt.Sprintf(`{"architecture":"amd64","os":"linux","rootfs":{"type":"layers","diff_ids":[%q]}}`, digest.FromBytes(archiveLayer)))
+)
+
+// newImageArchive returns an archive of "docker save" of the image
+// "registry.example.com/app:1.0" with the given layer, including the
+// signatures of the image in the registry client, if not nil.
+func newImageArchive(t *testing.T, registryClient *fakeRegistryClient, dgst digest.Digest, layer []byte) *fs.File {
+	imageID := digest.FromBytes(archiveConfig)
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "0123/layer.tar", content: layer},
+		{name: imageID.Hex() + ".json", content: archiveConfig},
+		{name: "manifest.json", content: []byte(fmt.Sprintf(`[{"Config":%q,"RepoTags":["registry.example.com/app:1.0"],"Layers":["0123/layer.tar"]}]`, imageID.Hex()+".json"))},
+	}
+	if registryClient != nil {
+		ref, err := reference.ParseNormalizedNamed("registry.example.com/app:1.0")
+		assert.NilError(t, err)
+		signatures := cosign.NewArchive()
+		assert.NilError(t, signatures.Add(context.Background(), registryClient, ref, dgst, imageID))
+		b, err := json.Marshal(signatures)
+		assert.NilError(t, err)
+		files = append(files, struct {
+			name    string
+			content []byte
+		}{name: cosign.ArchiveFile, content: b})
+	}
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, file := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0644, Size: int64(len(file.content))}))
+		_, err := tw.Write(file.content)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return fs.NewFile(t, "image.tar", fs.WithBytes(buf.Bytes()))
+}
+
+func newSignedImage(t *testing.T, key *ecdsa.PrivateKey) (*fakeRegistryClient, digest.Digest) {
+	imageManifest := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","config":{"digest":%q}}`, digest.FromBytes(archiveConfig)))
+	return newSignedRegistryClientWithManifest(t, key, imageManifest)
+}
+
+func TestTrustVerifyArchive(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	publicKey := writePublicKey(t, key)
+	defer publicKey.Remove()
+	registryClient, dgst := newSignedImage(t, key)
+	archive := newImageArchive(t, registryClient, dgst, archiveLayer)
+	defer archive.Remove()
+
+	// The registry is not contacted
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newVerifyArchiveCommand(cli)
+	cmd.SetArgs([]string{"--key", publicKey.Path(), archive.Path()})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("Verified registry.example.com/app:1.0@"+dgst.String()+"\n", cli.OutBuffer().String()))
+}
+
+func TestTrustVerifyArchiveErrors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	publicKey := writePublicKey(t, key)
+	defer publicKey.Remove()
+	registryClient, dgst := newSignedImage(t, key)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	otherRegistryClient, otherDigest := newSignedImage(t, otherKey)
+
+	testCases := []struct {
+		name          string
+		archive       *fs.File
+		expectedError string
+	}{
+		{
+			name:          "unsigned",
+			archive:       newImageArchive(t, nil, "", archiveLayer),
+			expectedError: `the archive has no signatures of registry.example.com/app:1.0: use "docker save --include-signatures"`,
+		},
+		{
+			name:          "tampered-layer",
+			archive:       newImageArchive(t, registryClient, dgst, []byte("tampered")),
+			expectedError: "layer 0123/layer.tar of image " + digest.FromBytes(archiveConfig).String() + " does not match its configuration",
+		},
+		{
+			name:          "other-key",
+			archive:       newImageArchive(t, otherRegistryClient, otherDigest, archiveLayer),
+			expectedError: "no valid cosign signature for registry.example.com/app@" + otherDigest.String(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer tc.archive.Remove()
+			cmd := newVerifyArchiveCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetArgs([]string{"--key", publicKey.Path(), tc.archive.Path()})
+			cmd.SetOutput(ioutil.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
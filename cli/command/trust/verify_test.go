@@ -0,0 +1,150 @@
+package trust
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	registryclient "github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/cli/trust/cosign"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	manifests map[string][]byte
+	blobs     map[digest.Digest][]byte
+}
+
+func (c *fakeRegistryClient) GetManifestContent(ctx context.Context, ref reference.Named) (registryclient.ManifestContent, error) {
+	payload, ok := c.manifests[reference.FamiliarString(ref)]
+	if !ok {
+		return registryclient.ManifestContent{}, fmt.Errorf("manifest unknown: %s", ref)
+	}
+	return registryclient.ManifestContent{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(payload), Payload: payload}, nil
+}
+
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	return c.blobs[dgst], nil
+}
+
+// newSignedRegistryClient returns a registry client with the image
+// "registry.example.com/app:1.0", signed with the key.
+func newSignedRegistryClient(t *testing.T, key *ecdsa.PrivateKey) (*fakeRegistryClient, digest.Digest) {
+	return newSignedRegistryClientWithManifest(t, key, []byte(`{"schemaVersion":2}`))
+}
+
+func newSignedRegistryClientWithManifest(t *testing.T, key *ecdsa.PrivateKey, imageManifest []byte) (*fakeRegistryClient, digest.Digest) {
+	dgst := digest.FromBytes(imageManifest)
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"}}`, dgst))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	assert.NilError(t, err)
+	sigManifest, err := json.Marshal(ocispec.Manifest{Layers: []ocispec.Descriptor{{
+		Digest:      digest.FromBytes(payload),
+		Annotations: map[string]string{"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(sig)},
+	}}})
+	assert.NilError(t, err)
+
+	return &fakeRegistryClient{
+		manifests: map[string][]byte{
+			"registry.example.com/app:1.0":                          imageManifest,
+			"registry.example.com/app@" + dgst.String():             imageManifest,
+			"registry.example.com/app:" + cosign.SignatureTag(dgst): sigManifest,
+		},
+		blobs: map[digest.Digest][]byte{digest.FromBytes(payload): payload},
+	}, dgst
+}
+
+func writePublicKey(t *testing.T, key *ecdsa.PrivateKey) *fs.File {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NilError(t, err)
+	return fs.NewFile(t, "cosign.pub", fs.WithBytes(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+}
+
+func TestTrustVerifyCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "not-enough-args",
+			expectedError: "requires exactly 1 argument",
+		},
+		{
+			name:          "invalid-img-reference",
+			args:          []string{"ALPINE"},
+			expectedError: "invalid reference format",
+		},
+		{
+			name:          "not-configured",
+			args:          []string{"registry.example.com/app:1.0"},
+			expectedError: "no cosign signature verification configured for registry.example.com",
+		},
+		{
+			name:          "missing-fulcio-roots",
+			args:          []string{"--fulcio-roots", "testdata/none.pem", "registry.example.com/app:1.0"},
+			expectedError: "unable to read Fulcio roots",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newVerifyCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestTrustVerifyCommand(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	publicKey := writePublicKey(t, key)
+	defer publicKey.Remove()
+
+	registryClient, dgst := newSignedRegistryClient(t, key)
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(registryClient)
+	cmd := newVerifyCommand(cli)
+	cmd.SetArgs([]string{"--key", publicKey.Path(), "registry.example.com/app:1.0"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("Verified cosign signature of registry.example.com/app@"+dgst.String()+"\n", cli.OutBuffer().String()))
+}
+
+func TestTrustVerifyCommandWithConfig(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	publicKey := writePublicKey(t, key)
+	defer publicKey.Remove()
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	registryClient, dgst := newSignedRegistryClient(t, otherKey)
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(registryClient)
+	cli.SetConfigFile(&configfile.ConfigFile{
+		SignatureVerification: map[string]configfile.SignatureVerificationConfig{
+			"registry.example.com": {Backend: "cosign", PublicKey: publicKey.Path()},
+		},
+	})
+	cmd := newVerifyCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com/app@" + dgst.String()})
+	assert.ErrorContains(t, cmd.Execute(), "no valid cosign signature for registry.example.com/app@"+dgst.String()+": invalid signature")
+}
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/docker/cli/cli/i18n"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/pkg/system"
@@ -71,10 +72,12 @@ func PrettyPrint(i interface{}) string {
 // This will display the provided message followed by ' [y/N] '. If
 // the user input 'y' or 'Y' it returns true other false.  If no
 // message is provided "Are you sure you want to proceed? [y/N] "
-// will be used instead.
+// will be used instead. The message is translated by the message catalog.
 func PromptForConfirmation(ins io.Reader, outs io.Writer, message string) bool {
 	if message == "" {
-		message = "Are you sure you want to proceed?"
+		message = i18n.T("Are you sure you want to proceed?")
+	} else {
+		message = i18n.T(message)
 	}
 	message += " [y/N] "
 
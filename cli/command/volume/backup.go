@@ -0,0 +1,106 @@
+package volume
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compression"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type backupOptions struct {
+	volume      string
+	output      string
+	compression string
+	image       string
+	quiet       bool
+}
+
+func newBackupCommand(dockerCli command.Cli) *cobra.Command {
+	var opts backupOptions
+
+	cmd := &cobra.Command{
+		Use:   "backup [OPTIONS] VOLUME",
+		Short: "Back up the contents of a volume to a tar archive (streamed to STDOUT by default)",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			return runBackup(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.25"},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVar(&opts.compression, "compression", "", `Compression of the archive ("none", "gzip", or "zstd"), instead of the one matching the extension of the output file`)
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output")
+	addHelperImageFlag(flags, &opts.image)
+
+	return cmd
+}
+
+func runBackup(dockerCli command.Cli, opts backupOptions) error {
+	if opts.output == "" && dockerCli.Out().IsTerminal() {
+		return errors.New("cowardly refusing to write the backup to a terminal. Use the -o flag or redirect")
+	}
+	if err := command.ValidateOutputPath(opts.output); err != nil {
+		return errors.Wrap(err, "failed to back up volume")
+	}
+	c := compression.FromPath(opts.output)
+	if opts.compression != "" {
+		var err error
+		if c, err = compression.Parse(opts.compression); err != nil {
+			return err
+		}
+	}
+	if err := compression.Supported(c); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	// Inspect the volume first, as the helper container would create a
+	// missing volume.
+	if _, err := dockerCli.Client().VolumeInspect(ctx, opts.volume); err != nil {
+		return err
+	}
+	id, err := createHelperContainer(ctx, dockerCli, opts.volume, opts.image, true)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the helper container")
+	}
+	defer removeHelperContainer(ctx, dockerCli, id)
+
+	content, _, err := dockerCli.Client().CopyFromContainer(ctx, id, helperMountPath+"/.")
+	if err != nil {
+		return err
+	}
+	content = newProgressReader(dockerCli, content, opts.quiet, "Backing up volume "+opts.volume)
+	defer content.Close()
+
+	archive := compressStream(content, c)
+	defer archive.Close()
+	if opts.output == "" {
+		_, err := io.Copy(dockerCli.Out(), archive)
+		return err
+	}
+	return command.CopyToFile(opts.output, archive)
+}
+
+// compressStream returns a reader of the compressed contents of the reader.
+func compressStream(r io.Reader, c compression.Compression) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		w, err := compression.CompressStream(pw, c)
+		if err == nil {
+			_, err = io.Copy(w, r)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
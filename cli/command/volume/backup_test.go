@@ -0,0 +1,101 @@
+package volume
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+type notFound struct{}
+
+func (notFound) Error() string  { return "Error: No such image: busybox:latest" }
+func (notFound) NotFound() bool { return true }
+
+func TestVolumeBackupErrors(t *testing.T) {
+	testCases := []struct {
+		args              []string
+		volumeInspectFunc func(volumeID string) (types.Volume, error)
+		expectedError     string
+	}{
+		{
+			args:          []string{"data", "-o", "data.tar", "--compression", "lz4"},
+			expectedError: `invalid compression "lz4"`,
+		},
+		{
+			args: []string{"missing", "-o", "missing.tar"},
+			volumeInspectFunc: func(volumeID string) (types.Volume, error) {
+				return types.Volume{}, errors.Errorf("no such volume: %s", volumeID)
+			},
+			expectedError: "no such volume: missing",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newBackupCommand(test.NewFakeCli(&fakeClient{volumeInspectFunc: tc.volumeInspectFunc}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestVolumeBackup(t *testing.T) {
+	dir := fs.NewDir(t, "volume-backup")
+	defer dir.Remove()
+
+	var (
+		mounts  []mount.Mount
+		removed string
+		pulled  string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		containerCreateFunc: func(config *container.Config, hostConfig *container.HostConfig) (container.ContainerCreateCreatedBody, error) {
+			if pulled == "" {
+				return container.ContainerCreateCreatedBody{}, notFound{}
+			}
+			assert.Check(t, is.Equal("busybox:latest", config.Image))
+			mounts = hostConfig.Mounts
+			return container.ContainerCreateCreatedBody{ID: "helper"}, nil
+		},
+		imageCreateFunc: func(parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error) {
+			pulled = parentReference
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		},
+		copyFromContainerFunc: func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+			assert.Check(t, is.Equal("helper", container))
+			assert.Check(t, is.Equal("/volume/.", srcPath))
+			return ioutil.NopCloser(strings.NewReader("volume contents")), types.ContainerPathStat{}, nil
+		},
+		containerRemoveFunc: func(container string, options types.ContainerRemoveOptions) error {
+			removed = container
+			return nil
+		},
+	})
+	output := filepath.Join(dir.Path(), "data.tar.gz")
+	cmd := newBackupCommand(cli)
+	cmd.SetArgs([]string{"data", "-o", output})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal("busybox:latest", pulled))
+	assert.Check(t, is.DeepEqual([]mount.Mount{{Type: mount.TypeVolume, Source: "data", Target: "/volume", ReadOnly: true}}, mounts))
+	assert.Check(t, is.Equal("helper", removed))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Backing up volume data"))
+
+	f, err := ioutil.ReadFile(output)
+	assert.NilError(t, err)
+	r, err := gzip.NewReader(strings.NewReader(string(f)))
+	assert.NilError(t, err)
+	b, err := ioutil.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("volume contents", string(b)))
+}
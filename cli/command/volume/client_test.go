@@ -2,20 +2,28 @@ package volume
 
 import (
 	"context"
+	"io"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 )
 
 type fakeClient struct {
 	client.Client
-	volumeCreateFunc  func(volumetypes.VolumeCreateBody) (types.Volume, error)
-	volumeInspectFunc func(volumeID string) (types.Volume, error)
-	volumeListFunc    func(filter filters.Args) (volumetypes.VolumeListOKBody, error)
-	volumeRemoveFunc  func(volumeID string, force bool) error
-	volumePruneFunc   func(filter filters.Args) (types.VolumesPruneReport, error)
+	volumeCreateFunc      func(volumetypes.VolumeCreateBody) (types.Volume, error)
+	volumeInspectFunc     func(volumeID string) (types.Volume, error)
+	volumeListFunc        func(filter filters.Args) (volumetypes.VolumeListOKBody, error)
+	volumeRemoveFunc      func(volumeID string, force bool) error
+	volumePruneFunc       func(filter filters.Args) (types.VolumesPruneReport, error)
+	containerCreateFunc   func(config *container.Config, hostConfig *container.HostConfig) (container.ContainerCreateCreatedBody, error)
+	containerRemoveFunc   func(container string, options types.ContainerRemoveOptions) error
+	copyFromContainerFunc func(container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
+	copyToContainerFunc   func(container, path string, content io.Reader) error
+	imageCreateFunc       func(parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error)
 }
 
 func (c *fakeClient) VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error) {
@@ -52,3 +60,42 @@ func (c *fakeClient) VolumeRemove(ctx context.Context, volumeID string, force bo
 	}
 	return nil
 }
+
+func (c *fakeClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error) {
+	if c.containerCreateFunc != nil {
+		return c.containerCreateFunc(config, hostConfig)
+	}
+	return container.ContainerCreateCreatedBody{}, nil
+}
+
+func (c *fakeClient) ContainerRemove(ctx context.Context, container string, options types.ContainerRemoveOptions) error {
+	if c.containerRemoveFunc != nil {
+		return c.containerRemoveFunc(container, options)
+	}
+	return nil
+}
+
+func (c *fakeClient) CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, types.ContainerPathStat, error) {
+	if c.copyFromContainerFunc != nil {
+		return c.copyFromContainerFunc(container, srcPath)
+	}
+	return nil, types.ContainerPathStat{}, nil
+}
+
+func (c *fakeClient) CopyToContainer(ctx context.Context, container, path string, content io.Reader, options types.CopyToContainerOptions) error {
+	if c.copyToContainerFunc != nil {
+		return c.copyToContainerFunc(container, path, content)
+	}
+	return nil
+}
+
+func (c *fakeClient) ImageCreate(ctx context.Context, parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error) {
+	if c.imageCreateFunc != nil {
+		return c.imageCreateFunc(parentReference, options)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) Info(ctx context.Context) (types.Info, error) {
+	return types.Info{}, nil
+}
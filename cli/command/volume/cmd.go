@@ -16,10 +16,12 @@ func NewVolumeCommand(dockerCli command.Cli) *cobra.Command {
 		Annotations: map[string]string{"version": "1.21"},
 	}
 	cmd.AddCommand(
+		newBackupCommand(dockerCli),
 		newCreateCommand(dockerCli),
 		newInspectCommand(dockerCli),
 		newListCommand(dockerCli),
 		newRemoveCommand(dockerCli),
+		newRestoreCommand(dockerCli),
 		NewPruneCommand(dockerCli),
 	)
 	return cmd
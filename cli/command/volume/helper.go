@@ -0,0 +1,115 @@
+package volume
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/docker/docker/registry"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// defaultHelperImage is the default image of the helper containers
+	// mounting the volumes to back up and restore
+	defaultHelperImage = "busybox:latest"
+
+	// helperMountPath is where the volume is mounted in helper containers
+	helperMountPath = "/volume"
+)
+
+func addHelperImageFlag(flags *pflag.FlagSet, image *string) {
+	flags.StringVar(image, "helper-image", defaultHelperImage, "Image of the helper container mounting the volume")
+}
+
+// createHelperContainer creates a container mounting the volume, so that its
+// contents can be copied with the archive endpoints of the API. The container
+// is never started. The image of the container is pulled if it is missing.
+func createHelperContainer(ctx context.Context, dockerCli command.Cli, volume, image string, readOnly bool) (string, error) {
+	config := &container.Config{
+		Image:  image,
+		Cmd:    []string{"true"},
+		Labels: map[string]string{"com.docker.volume.helper": volume},
+	}
+	hostConfig := &container.HostConfig{
+		Mounts: []mount.Mount{{
+			Type:     mount.TypeVolume,
+			Source:   volume,
+			Target:   helperMountPath,
+			ReadOnly: readOnly,
+		}},
+	}
+	response, err := dockerCli.Client().ContainerCreate(ctx, config, hostConfig, nil, "")
+	if client.IsErrNotFound(err) {
+		if err := pullHelperImage(ctx, dockerCli, image); err != nil {
+			return "", err
+		}
+		response, err = dockerCli.Client().ContainerCreate(ctx, config, hostConfig, nil, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+func removeHelperContainer(ctx context.Context, dockerCli command.Cli, id string) error {
+	return dockerCli.Client().ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true})
+}
+
+func pullHelperImage(ctx context.Context, dockerCli command.Cli, image string) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return err
+	}
+	authConfig := command.ResolveAuthConfig(ctx, dockerCli, repoInfo.Index)
+	encodedAuth, err := command.EncodeAuthToBase64(authConfig)
+	if err != nil {
+		return err
+	}
+	responseBody, err := dockerCli.Client().ImageCreate(ctx, image, types.ImageCreateOptions{RegistryAuth: encodedAuth})
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+	errOut := streams.NewOut(dockerCli.Err())
+	return jsonmessage.DisplayJSONMessagesStream(responseBody, errOut, errOut.FD(), errOut.IsTerminal(), nil)
+}
+
+// newProgressReader returns a reader printing the progress of the transfer of
+// the contents of the volume to the standard error, or only printing the
+// total when the standard error is not a terminal.
+func newProgressReader(dockerCli command.Cli, in io.ReadCloser, quiet bool, action string) io.ReadCloser {
+	if quiet {
+		return in
+	}
+	output := streamformatter.NewProgressOutput(dockerCli.Err())
+	if !streams.NewOut(dockerCli.Err()).IsTerminal() {
+		output = &lastProgressOutput{output: output}
+	}
+	return progress.NewProgressReader(in, output, 0, "", action)
+}
+
+// lastProgressOutput is a progress.Output only writing the last update.
+type lastProgressOutput struct {
+	output progress.Output
+}
+
+func (out *lastProgressOutput) WriteProgress(prog progress.Progress) error {
+	if !prog.LastUpdate {
+		return nil
+	}
+	return out.output.WriteProgress(prog)
+}
@@ -12,8 +12,9 @@ import (
 )
 
 type pruneOptions struct {
-	force  bool
-	filter opts.FilterOpt
+	force          bool
+	forceProtected bool
+	filter         opts.FilterOpt
 }
 
 // NewPruneCommand returns a new cobra prune command for volumes
@@ -41,6 +42,7 @@ func NewPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.Var(&options.filter, "filter", "Provide filter values (e.g. 'label=<label>')")
+	command.AddForceProtectedFlag(flags, &options.forceProtected)
 
 	return cmd
 }
@@ -49,9 +51,13 @@ const warning = `WARNING! This will remove all local volumes not used by at leas
 Are you sure you want to continue?`
 
 func runPrune(dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint64, output string, err error) {
-	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value())
+	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value().Clone())
+	pruneFilters, err = command.ExcludeProtected(pruneFilters, options.forceProtected, command.ProtectedVolumes(context.Background(), dockerCli.Client()))
+	if err != nil {
+		return 0, "", err
+	}
 
-	if !options.force && !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), warning) {
+	if !command.ConfirmPrune(dockerCli, options.force, warning, "volume") {
 		return 0, "", nil
 	}
 
@@ -76,3 +82,10 @@ func runPrune(dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint6
 func RunPrune(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error) {
 	return runPrune(dockerCli, pruneOptions{force: true, filter: filter})
 }
+
+// RunPruneForceProtected calls the Volume Prune API, which also removes the
+// volumes protected by the protection label
+// This returns the amount of space reclaimed and a detailed output string
+func RunPruneForceProtected(dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error) {
+	return runPrune(dockerCli, pruneOptions{force: true, forceProtected: true, filter: filter})
+}
@@ -7,12 +7,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/golden"
 	"gotest.tools/skip"
 )
@@ -117,3 +119,36 @@ func simplePruneFunc(args filters.Args) (types.VolumesPruneReport, error) {
 		SpaceReclaimed: 2000,
 	}, nil
 }
+
+func TestVolumePruneProtected(t *testing.T) {
+	for forceProtected, expected := range map[bool][]string{false: {"cli.docker.io/protected=true"}, true: {}} {
+		var pruneFilters filters.Args
+		cli := test.NewFakeCli(&fakeClient{
+			volumePruneFunc: func(args filters.Args) (types.VolumesPruneReport, error) {
+				pruneFilters = args
+				return types.VolumesPruneReport{}, nil
+			},
+		})
+		cmd := NewPruneCommand(cli)
+		cmd.Flags().Set("force", "true")
+		cmd.Flags().Set("force-protected", fmt.Sprint(forceProtected))
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.DeepEqual(pruneFilters.Get("label!"), expected))
+	}
+}
+
+func TestVolumePruneConfirmationRequired(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "TODO: fix test on windows")
+
+	cli := test.NewFakeCli(&fakeClient{
+		volumePruneFunc: func(args filters.Args) (types.VolumesPruneReport, error) {
+			return types.VolumesPruneReport{}, errors.New("volumes pruned without confirmation")
+		},
+	})
+	cli.SetConfigFile(&configfile.ConfigFile{PruneConfirmation: []string{"volume"}})
+	cli.SetIn(streams.NewIn(ioutil.NopCloser(strings.NewReader("n"))))
+	cmd := NewPruneCommand(cli)
+	cmd.Flags().Set("force", "true")
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Are you sure you want to continue? [y/N]"))
+}
@@ -12,7 +12,8 @@ import (
 )
 
 type removeOptions struct {
-	force bool
+	force          bool
+	forceProtected bool
 
 	volumes []string
 }
@@ -36,6 +37,7 @@ func newRemoveCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force the removal of one or more volumes")
 	flags.SetAnnotation("force", "version", []string{"1.25"})
+	command.AddForceProtectedFlag(flags, &opts.forceProtected)
 	return cmd
 }
 
@@ -46,6 +48,14 @@ func runRemove(dockerCli command.Cli, opts *removeOptions) error {
 	var errs []string
 
 	for _, name := range opts.volumes {
+		if !opts.forceProtected {
+			if vol, err := client.VolumeInspect(ctx, name); err == nil {
+				if err := command.CheckProtected("volume", name, vol.Labels, false); err != nil {
+					errs = append(errs, err.Error())
+					continue
+				}
+			}
+		}
 		if err := client.VolumeRemove(ctx, name, opts.force); err != nil {
 			errs = append(errs, err.Error())
 			continue
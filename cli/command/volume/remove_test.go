@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func TestVolumeRemoveErrors(t *testing.T) {
@@ -42,3 +44,31 @@ func TestNodeRemoveMultiple(t *testing.T) {
 	cmd.SetArgs([]string{"volume1", "volume2"})
 	assert.NilError(t, cmd.Execute())
 }
+
+func TestVolumeRemoveProtected(t *testing.T) {
+	var removed []string
+	client := &fakeClient{
+		volumeInspectFunc: func(volumeID string) (types.Volume, error) {
+			if volumeID == "protected" {
+				return types.Volume{Name: volumeID, Labels: map[string]string{"cli.docker.io/protected": "true"}}, nil
+			}
+			return types.Volume{Name: volumeID}, nil
+		},
+		volumeRemoveFunc: func(volumeID string, force bool) error {
+			removed = append(removed, volumeID)
+			return nil
+		},
+	}
+	cmd := newRemoveCommand(test.NewFakeCli(client))
+	cmd.SetArgs([]string{"protected", "other"})
+	cmd.SetOutput(ioutil.Discard)
+	err := cmd.Execute()
+	assert.Check(t, is.Error(err, "volume protected is protected by the cli.docker.io/protected=true label: use --force-protected to remove it"))
+	assert.Check(t, is.DeepEqual(removed, []string{"other"}))
+
+	removed = nil
+	cmd = newRemoveCommand(test.NewFakeCli(client))
+	cmd.SetArgs([]string{"--force-protected", "protected"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(removed, []string{"protected"}))
+}
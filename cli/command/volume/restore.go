@@ -0,0 +1,74 @@
+package volume
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compression"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type restoreOptions struct {
+	volume string
+	input  string
+	image  string
+	quiet  bool
+}
+
+func newRestoreCommand(dockerCli command.Cli) *cobra.Command {
+	var opts restoreOptions
+
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] VOLUME",
+		Short: "Restore the contents of a volume from a tar archive (read from STDIN by default)",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.volume = args[0]
+			return runRestore(dockerCli, opts)
+		},
+		Annotations: map[string]string{"version": "1.25"},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, instead of STDIN")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output")
+	addHelperImageFlag(flags, &opts.image)
+
+	return cmd
+}
+
+func runRestore(dockerCli command.Cli, opts restoreOptions) error {
+	var input io.ReadCloser = dockerCli.In()
+	if opts.input != "" {
+		file, err := os.Open(opts.input)
+		if err != nil {
+			return err
+		}
+		input = file
+	} else if dockerCli.In().IsTerminal() {
+		return errors.New("cowardly refusing to read the backup from a terminal. Use the -i flag or redirect")
+	}
+	defer input.Close()
+
+	archive, err := compression.DecompressStream(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the backup")
+	}
+	defer archive.Close()
+
+	ctx := context.Background()
+
+	id, err := createHelperContainer(ctx, dockerCli, opts.volume, opts.image, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the helper container")
+	}
+	defer removeHelperContainer(ctx, dockerCli, id)
+
+	content := newProgressReader(dockerCli, archive, opts.quiet, "Restoring volume "+opts.volume)
+	return dockerCli.Client().CopyToContainer(ctx, id, helperMountPath, content, types.CopyToContainerOptions{})
+}
@@ -0,0 +1,51 @@
+package volume
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func TestVolumeRestore(t *testing.T) {
+	compressed := new(bytes.Buffer)
+	w := gzip.NewWriter(compressed)
+	_, err := w.Write([]byte("volume contents"))
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+	file := fs.NewFile(t, "volume-restore", fs.WithBytes(compressed.Bytes()))
+	defer file.Remove()
+
+	var (
+		mounts   []mount.Mount
+		restored string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		containerCreateFunc: func(config *container.Config, hostConfig *container.HostConfig) (container.ContainerCreateCreatedBody, error) {
+			mounts = hostConfig.Mounts
+			return container.ContainerCreateCreatedBody{ID: "helper"}, nil
+		},
+		copyToContainerFunc: func(container, path string, content io.Reader) error {
+			assert.Check(t, is.Equal("helper", container))
+			assert.Check(t, is.Equal("/volume", path))
+			b, err := ioutil.ReadAll(content)
+			restored = string(b)
+			return err
+		},
+	})
+	cmd := newRestoreCommand(cli)
+	cmd.SetArgs([]string{"data", "-i", file.Path(), "--quiet"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual([]mount.Mount{{Type: mount.TypeVolume, Source: "data", Target: "/volume"}}, mounts))
+	assert.Check(t, is.Equal("volume contents", restored))
+	assert.Check(t, is.Equal("", cli.ErrBuffer().String()))
+}
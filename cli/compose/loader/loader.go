@@ -32,13 +32,17 @@ type Options struct {
 	SkipInterpolation bool
 	// Interpolation options
 	Interpolate *interp.Options
+	// Profiles to enable. Services that have profiles are only included if
+	// one of their profiles is enabled; the "*" profile enables all profiles.
+	Profiles []string
 }
 
 // ParseYAML reads the bytes from a file, parses the bytes into a mapping
-// structure, and returns it.
+// structure, and returns it. The keys of values tagged with !reset or
+// !override are suffixed with the tag, which is used by Load.
 func ParseYAML(source []byte) (map[string]interface{}, error) {
 	var cfg interface{}
-	if err := yaml.Unmarshal(source, &cfg); err != nil {
+	if err := yaml.Unmarshal(rewriteMergeTags(source), &cfg); err != nil {
 		return nil, err
 	}
 	cfgMap, ok := cfg.(map[interface{}]interface{})
@@ -74,7 +78,7 @@ func Load(configDetails types.ConfigDetails, options ...func(*Options)) (*types.
 	var err error
 
 	for _, file := range configDetails.ConfigFiles {
-		configDict := file.Config
+		configDict, tags := extractMergeTags(file.Config)
 		version := schema.Version(configDict)
 		if configDetails.Version == "" {
 			configDetails.Version = version
@@ -106,10 +110,39 @@ func Load(configDetails types.ConfigDetails, options ...func(*Options)) (*types.
 		}
 		cfg.Filename = file.Filename
 
+		applyMergeTags(configs, tags)
 		configs = append(configs, cfg)
 	}
 
-	return merge(configs)
+	config, err := merge(configs)
+	if err != nil {
+		return config, err
+	}
+	config.Services = filterByProfiles(config.Services, opts.Profiles)
+	return config, nil
+}
+
+// filterByProfiles returns the services that have no profiles, or that have
+// one of the given profiles.
+func filterByProfiles(services types.Services, profiles []string) types.Services {
+	enabled := map[string]bool{}
+	for _, p := range profiles {
+		enabled[p] = true
+	}
+	result := services[:0:0]
+	for _, service := range services {
+		if len(service.Profiles) == 0 || enabled["*"] {
+			result = append(result, service)
+			continue
+		}
+		for _, p := range service.Profiles {
+			if enabled[p] {
+				result = append(result, service)
+				break
+			}
+		}
+	}
+	return result
 }
 
 func validateForbidden(configDict map[string]interface{}) error {
@@ -235,8 +268,9 @@ func getProperties(services map[string]interface{}, propertyMap map[string]strin
 
 	for _, service := range services {
 		if serviceDict, ok := service.(map[string]interface{}); ok {
-			for property, description := range propertyMap {
-				if _, isSet := serviceDict[property]; isSet {
+			for key := range serviceDict {
+				property, _ := splitMergeTag(key)
+				if description, ok := propertyMap[property]; ok {
 					output[property] = description
 				}
 			}
@@ -1484,3 +1484,44 @@ func TestTransform(t *testing.T) {
 
 	assert.Check(t, is.DeepEqual(samplePortsConfig, ports))
 }
+
+func TestLoadProfiles(t *testing.T) {
+	dict, err := ParseYAML([]byte(`
+version: "3.8"
+services:
+  web:
+    image: web
+  debug:
+    image: debug
+    profiles: [debug]
+  test:
+    image: test
+    profiles: [test, ci]
+`))
+	assert.NilError(t, err)
+
+	serviceNames := func(config *types.Config) []string {
+		var names []string
+		for _, s := range config.Services {
+			names = append(names, s.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	testcases := []struct {
+		profiles []string
+		expected []string
+	}{
+		{expected: []string{"web"}},
+		{profiles: []string{"ci"}, expected: []string{"test", "web"}},
+		{profiles: []string{"debug", "test"}, expected: []string{"debug", "test", "web"}},
+		{profiles: []string{"*"}, expected: []string{"debug", "test", "web"}},
+	}
+	for _, tc := range testcases {
+		config, err := Load(buildConfigDetails(dict, nil), func(options *Options) {
+			options.Profiles = tc.profiles
+		})
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(tc.expected, serviceNames(config)), "profiles: %v", tc.profiles)
+	}
+}
@@ -0,0 +1,249 @@
+package loader
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+)
+
+// The !reset and !override tags change how a value of a Compose file is
+// merged with the values of the files that precede it:
+//
+//	!reset     removes the value set by the preceding files
+//	!override  replaces the value set by the preceding files, instead of
+//	           merging it
+//
+// gopkg.in/yaml.v2 does not expose the tags of the nodes it decodes, so the
+// tags are moved to the key of the tagged value before the source is parsed,
+// and extracted by Load.
+const (
+	mergeTagReset    = "reset"
+	mergeTagOverride = "override"
+
+	// mergeTagSeparator separates the key from the tag. It cannot be part of
+	// a key in a Compose file.
+	mergeTagSeparator = "\x00"
+)
+
+// taggedKeyPattern matches a key of a block mapping whose value is tagged
+// with one of the merge tags.
+var taggedKeyPattern = regexp.MustCompile(`^(\s*(?:-\s+)*)("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s"'#?|>!&*{}\[\],-][^:#]*?)\s*:\s+!(reset|override)(\s.*)?$`)
+
+// blockScalarPattern matches a line that starts a literal or folded block
+// scalar, whose content must not be rewritten.
+var blockScalarPattern = regexp.MustCompile(`(?:^|\s)[|>][-+0-9]*\s*(?:#.*)?$`)
+
+// rewriteMergeTags moves the merge tags in the source of a Compose file to
+// the keys of the tagged values, for example:
+//
+//	ports: !reset []   =>   "ports\0reset": []
+//
+// Only values of block mappings can be tagged.
+func rewriteMergeTags(source []byte) []byte {
+	if !bytes.Contains(source, []byte("!"+mergeTagReset)) && !bytes.Contains(source, []byte("!"+mergeTagOverride)) {
+		return source
+	}
+	lines := strings.Split(string(source), "\n")
+	blockIndent := -1
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" || indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		if m := taggedKeyPattern.FindStringSubmatch(line); m != nil {
+			line = m[1] + quoteTaggedKey(m[2], m[3]) + ":" + m[4]
+			lines[i] = line
+		}
+		if blockScalarPattern.MatchString(line) {
+			blockIndent = indent
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// quoteTaggedKey returns a double-quoted YAML scalar for the given key, which
+// may be a plain or quoted scalar, followed by the tag.
+func quoteTaggedKey(key, tag string) string {
+	switch key[0] {
+	case '"':
+		return key[:len(key)-1] + `\0` + tag + `"`
+	case '\'':
+		key = strings.Replace(key[1:len(key)-1], "''", "'", -1)
+	}
+	key = strings.Replace(key, `\`, `\\`, -1)
+	key = strings.Replace(key, `"`, `\"`, -1)
+	return `"` + key + `\0` + tag + `"`
+}
+
+// splitMergeTag returns the key and the merge tag (if any) of a key of the
+// mapping returned by ParseYAML.
+func splitMergeTag(key string) (string, string) {
+	if i := strings.Index(key, mergeTagSeparator); i >= 0 {
+		return key[:i], key[i+len(mergeTagSeparator):]
+	}
+	return key, ""
+}
+
+// mergeTag is a merge tag found in a Compose file
+type mergeTag struct {
+	path []string
+	tag  string
+}
+
+// extractMergeTags returns a copy of the config without merge tags, and the
+// tags that were found in the config. Values tagged with !reset are removed.
+// Tags found in lists are removed, but not returned, as the elements of a
+// list are not merged individually.
+func extractMergeTags(config map[string]interface{}) (map[string]interface{}, []mergeTag) {
+	var tags []mergeTag
+	var extract func(value interface{}, path []string, record bool) interface{}
+	extract = func(value interface{}, path []string, record bool) interface{} {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			dict := make(map[string]interface{}, len(value))
+			for key, entry := range value {
+				name, tag := splitMergeTag(key)
+				entryPath := append(append([]string{}, path...), name)
+				if tag != "" && record {
+					tags = append(tags, mergeTag{path: entryPath, tag: tag})
+				}
+				if tag == mergeTagReset {
+					continue
+				}
+				dict[name] = extract(entry, entryPath, record)
+			}
+			return dict
+		case []interface{}:
+			list := make([]interface{}, len(value))
+			for i, entry := range value {
+				list[i] = extract(entry, path, false)
+			}
+			return list
+		}
+		return value
+	}
+	return extract(config, nil, true).(map[string]interface{}), tags
+}
+
+// applyMergeTags removes the values tagged with !reset or !override from
+// the configs that precede a config in which they are tagged, so that the
+// tagged values are respectively removed from, or replace the values of,
+// the merged config.
+func applyMergeTags(configs []*types.Config, tags []mergeTag) {
+	for _, t := range tags {
+		for _, cfg := range configs {
+			resetConfigPath(cfg, t.path)
+		}
+	}
+}
+
+func resetConfigPath(cfg *types.Config, path []string) {
+	switch path[0] {
+	case "services":
+		if len(path) == 1 {
+			cfg.Services = nil
+			return
+		}
+		for i, service := range cfg.Services {
+			if service.Name != path[1] {
+				continue
+			}
+			if len(path) == 2 {
+				cfg.Services = append(cfg.Services[:i], cfg.Services[i+1:]...)
+				return
+			}
+			resetValuePath(reflect.ValueOf(&cfg.Services[i]).Elem(), path[2:])
+			return
+		}
+	case "networks":
+		resetValuePath(reflect.ValueOf(&cfg.Networks).Elem(), path[1:])
+	case "volumes":
+		resetValuePath(reflect.ValueOf(&cfg.Volumes).Elem(), path[1:])
+	case "secrets":
+		resetValuePath(reflect.ValueOf(&cfg.Secrets).Elem(), path[1:])
+	case "configs":
+		resetValuePath(reflect.ValueOf(&cfg.Configs).Elem(), path[1:])
+	default:
+		resetValuePath(reflect.ValueOf(&cfg.Extras).Elem(), path)
+	}
+}
+
+// resetValuePath sets the value at the given path, relative to value, to its
+// zero value. Struct fields are looked up by their name in the Compose file.
+func resetValuePath(value reflect.Value, path []string) {
+	if len(path) == 0 {
+		value.Set(reflect.Zero(value.Type()))
+		return
+	}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			resetValuePath(value.Elem(), path)
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return
+		}
+		key := reflect.ValueOf(path[0]).Convert(value.Type().Key())
+		entry := value.MapIndex(key)
+		if !entry.IsValid() {
+			return
+		}
+		if len(path) == 1 {
+			value.SetMapIndex(key, reflect.Value{})
+			return
+		}
+		// Map entries are not addressable, so the entry is updated on a copy
+		copied := reflect.New(entry.Type()).Elem()
+		copied.Set(entry)
+		resetValuePath(copied, path[1:])
+		value.SetMapIndex(key, copied)
+	case reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+		copied := reflect.New(value.Elem().Type()).Elem()
+		copied.Set(value.Elem())
+		resetValuePath(copied, path)
+		value.Set(copied)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name, inline := composeFieldName(field)
+			if inline {
+				resetValuePath(value.Field(i), path)
+				continue
+			}
+			if name == path[0] {
+				resetValuePath(value.Field(i), path[1:])
+				return
+			}
+		}
+	}
+}
+
+// composeFieldName returns the name of a struct field in a Compose file,
+// and whether the field holds the inlined extension fields.
+func composeFieldName(field reflect.StructField) (string, bool) {
+	if tag := field.Tag.Get("yaml"); tag != "" {
+		parts := strings.Split(tag, ",")
+		for _, option := range parts[1:] {
+			if option == "inline" {
+				return "", true
+			}
+		}
+		if parts[0] != "" {
+			return parts[0], false
+		}
+	}
+	if name := field.Tag.Get("mapstructure"); name != "" {
+		return name, false
+	}
+	return strings.ToLower(field.Name), false
+}
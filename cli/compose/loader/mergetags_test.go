@@ -0,0 +1,138 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func loadMultipleYAML(t *testing.T, sources ...string) *types.Config {
+	t.Helper()
+	details := types.ConfigDetails{WorkingDir: "/work"}
+	for _, source := range sources {
+		dict, err := ParseYAML([]byte(source))
+		assert.NilError(t, err)
+		details.ConfigFiles = append(details.ConfigFiles, types.ConfigFile{Filename: "file.yml", Config: dict})
+	}
+	config, err := Load(details)
+	assert.NilError(t, err)
+	return config
+}
+
+func TestRewriteMergeTags(t *testing.T) {
+	source := `version: "3.8"
+services:
+  web:
+    ports: !reset []
+    "environment": !override
+      FOO: bar
+    'it''s': !reset null  # comment
+    command: |
+      ports: !reset []
+    - item: !override x
+    image: "!reset"
+`
+	expected := `version: "3.8"
+services:
+  web:
+    "ports\0reset": []
+    "environment\0override":
+      FOO: bar
+    "it's\0reset": null  # comment
+    command: |
+      ports: !reset []
+    - "item\0override": x
+    image: "!reset"
+`
+	assert.Check(t, is.Equal(expected, string(rewriteMergeTags([]byte(source)))))
+}
+
+func TestLoadMergeTagReset(t *testing.T) {
+	config := loadMultipleYAML(t, `
+version: "3.8"
+services:
+  web:
+    image: web
+    ports:
+      - 8080:80
+    environment:
+      FOO: foo
+  db:
+    image: db
+volumes:
+  data: {}
+`, `
+version: "3.8"
+services:
+  web:
+    ports: !reset []
+    environment: !reset {}
+  db: !reset
+volumes: !reset {}
+`)
+	assert.Check(t, is.Len(config.Services, 1))
+	web := config.Services[0]
+	assert.Check(t, is.Equal("web", web.Name))
+	assert.Check(t, is.Equal("web", web.Image))
+	assert.Check(t, is.Len(web.Ports, 0))
+	assert.Check(t, is.Len(web.Environment, 0))
+	assert.Check(t, is.Len(config.Volumes, 0))
+}
+
+func TestLoadMergeTagOverride(t *testing.T) {
+	config := loadMultipleYAML(t, `
+version: "3.8"
+services:
+  web:
+    image: web
+    ports:
+      - 8080:80
+    environment:
+      FOO: foo
+      BAR: bar
+    deploy:
+      resources:
+        limits:
+          memory: 50M
+`, `
+version: "3.8"
+services:
+  web:
+    ports: !override
+      - 9090:90
+    environment: !override
+      BAZ: baz
+    deploy:
+      resources: !override
+        reservations:
+          memory: 20M
+`)
+	assert.Assert(t, is.Len(config.Services, 1))
+	web := config.Services[0]
+	assert.Check(t, is.DeepEqual([]types.ServicePortConfig{
+		{Mode: "ingress", Target: 90, Published: 9090, Protocol: "tcp"},
+	}, web.Ports))
+	baz := "baz"
+	assert.Check(t, is.DeepEqual(types.MappingWithEquals{"BAZ": &baz}, web.Environment))
+	assert.Check(t, is.Nil(web.Deploy.Resources.Limits))
+	assert.Check(t, is.DeepEqual(&types.Resource{MemoryBytes: 20 * 1024 * 1024}, web.Deploy.Resources.Reservations))
+}
+
+func TestLoadMergeTagInFirstFile(t *testing.T) {
+	config := loadMultipleYAML(t, `
+version: "3.8"
+services:
+  web:
+    image: web
+    ports: !reset
+      - 8080:80
+    environment: !override
+      FOO: foo
+`)
+	assert.Assert(t, is.Len(config.Services, 1))
+	web := config.Services[0]
+	assert.Check(t, is.Len(web.Ports, 0))
+	assert.Check(t, is.Len(web.Environment, 1))
+}
@@ -510,45 +510,45 @@ bnBpPlHfjORjkTRf1wyAwiYqMXd9/G6313QfoXs6/sbZ66r6e179PwAA//8ZL3SpvkUAAA==
 
 	"/data/config_schema_v3.8.json": {
 		local:   "data/config_schema_v3.8.json",
-		size:    17835,
+		size:    17898,
 		modtime: 1518458244,
 		compressed: `
-H4sIAAAAAAAC/+xcS4/bOBK++1cYmrlNPwLsYLGb2x73tHvehiPQVNnmNEVyipTTTuD/vtCzJYoUKVtO
-dzAdIEi3VHzUg8WvHsr31Xqd/KrpAXKSfF4nB2PU58fHP7QU9/XTB4n7xwzJztx/+v2xfvZLcleOY1k5
-hEqxY/u0fpMe//bwj4dyeE1iTgpKIrn9A6ipnyH8WTCEcvBTcgTUTIpkc7cq3ymUCtAw0Mnndbm59boj
-aR/0ptUGmdgn1eNzNcN6nWjAI6O9Gbqt/vL4Ov9jR3Znz9rbbPVcEWMAxX/He6tef3ki99/+df+/T/f/
-fEjvN7/9OnhdyhdhVy+fwY4JZpgU3fpJR3lufjp3C5Msq4gJH6y9I1zDkGcB5qvE5xDPHdkb8dys7+B5
-yM5R8iIParCleiNm6uWX0Z8GimDCJltTvZnFlssvw3DtNUIMt1RvxHC9/HUMr1qm3XtMvrzcl/+eqzkn
-56tn6e2vYmLg81zidPkcvzw7gXokmYHi8lTt3C2zmiAHYZJOTOt1si0Yz2ypSwH/Kad46j1cr7/b7r03
-T/V+8JvfKLr3Hl6691QKAy+mYmp66VoEkj4D7hiH2BEEa0v3iIwzbVKJacaocY7nZAv8qhkooQdIdyjz
-4Cy7tOZEOydqPXgk54bgHqIlqw95qtm3gVyfEiYM7AGTu27s5myNHU0WPpj2mS7/bFaOCRNKVEqybMAE
-QSSnckfMQK7d/K2TQrA/C/h3Q2KwAHveDKVafuI9ykKlimB5Cqdln1CZ50QsdTTn8BEh+dElMTjvzRr9
-V91qg215uFlHWKXDXQTcTdjhlJYuC6Sx/mPuOVqvk4Jl8cT7OcS5zIb7FkW+BUzOI+LRIR38vlm53lja
-N4QJwFSQHIJ2jJCBMIzwVCugA/JWUxOaSaL8eYKwZ9rgyUn5ykV/YxkoEJlO6whmvutNMujCmUXdRCam
-rpR6mvJSKfeWWANTDQTp4cLxMidMxCgVhMGTkqx2Y+/OP4E4pp3dzBYDiCNDKfLWScdd7b3xL0pquN45
-dhdtw/hdd6Y3Q+klO4k5KTfbrr3yXMEOy+sLsM9DCYkJTzkTz8ubOLwYJOlBanMJekoOQLg50APQ54nh
-farBaKlNjJGznOzDRIIN3f9WSg5EDIkUDc6jJSemSadMEV6MOZNFVdmbVu73JanPfkcxTCT6z5AdAWMh
-qlSvoZfrng5hg2CsOiD98lCHqhNntPqJ8zEmdl3B9hOLwzjUPNBKTmgJjhG0DllUEzqkIwTxSjsi1rF+
-/6KIZn4kGaW6YLohiEt92DPeyuJwaKt2zogGfV1o2PNCx98jbcI19u+TYz1DvXPGB4KBqfqAl3PnRjZh
-CHzLOFUNYfzQV1Qeon/AlETzQyKrVz/1Ch/qxcfBlq3uqEG3idAmvFRcfNamLdwDVLHlTB8gmzMGpZFU
-8riD4UxExR+GiWjtIqSnkB0Zh73FsQvGIJAslYKfIii1IRjMcWigBTJzSqUyi2NMd9Lq1eq7nNVwQ1a6
-/yOx8ddJbOiTpuYybK1NxkQqFYjg2dBGqnSPhEKqAJl0imLgYLMC69BgNI1me0F46JiZXO0uTCkYEz7s
-BWc58x8ah9VG4LUaq7kh2gQ8i3LZExHCdIAQERkcCM64OqqDufPcT6tIDDQs3Ffz3TUb2TjpZ0Evexsb
-L/pxH6pCB4O4ikboNOJqd1Sgfw4PPdBRRb65yI83K0X6zlt7/WhEMKzqaaYNCHqKX2jLRqWQuXFXXNRV
-UZG9PxXjjk2iz2rTnPBDWBGSSuVRzZVsdFfK7bloMZw/OLU950QcmzPB8iJPPq8/+SLWeMncGNpbOaAJ
-QO/zvV8lPpc3e8ZwypbP0+0aw1aImf0kVqp2qgmiTxpsLJluyAg1SzBNtlZZyZm3FQbw6AZYYYSGYJBZ
-9aEWu/YhFuj3WUUxLAdZmEvhKUEzH+DabWe93pa2HjNlQj1K24KeetXGOu0SNJMYPAIiq+pgUeAFQXFG
-iQ4BxCuS/Cg53xL6nDY9UnNA+QQaVwQJ58CZzmPQbZIBJ6eLLKcuaBHGC4SU0IiSSKMrwYzEy5fMyUva
-LluRBM5tfU4xA9+aIKp7xsaX9cm43zHUpk5DSNX8NnT/Z29qJ7Ya8Hp1qIwY+DCJD5PoZ+iq2EAvZQ7O
-JMAybYCqiK1XJDnkMtQFcn3K31I5gi5hgq8A+V4E4KDegwBkNB1Yg+fKGdPeqIpyvWXX2ENyVoeYS5g3
-laLeR4znudLVlX6nBOK5MjrKtX5lIpNf58OsBaStOKFgQbNrBa0NEibM7F4FWywKYQcIgsLksRznjCby
-Rssl5BUCyd6gZOSythaYloA9FTaSdWUkLzGbKz5LcDqqqUhgPGAUUg717tC3X88T/YoZ0xTBQLdy1/a4
-irehaftJnptsWNDFJ0fCi4jqyUX9Jr6sQ8Tgs/MrqZBOW7IFQruY/q+oBqSGKpVq+QpIuMloE86/M0Xy
-pXxzdEtW4gw13oPXLbbCk+C+sddd7sptezM9Wn3qUll3naw20Sr2Hozl9l9l1eyypSv9Rowh9BCVqZuZ
-MPkBic9Rot/p0hqqD482w6P97Pb//my1+YA0+JFiRRX+5vMKC4342uMd6P8nUevoEnaqtaH6UOvPolar
-Xaen3nHZaEri0T3Fq36VqNuGTeb4byB8EZZ3U74ip7VoI+xpzhe8tx5+m0CyU73/N4KACzRKunVqJV9W
-XVuk/RW735e040fftJd8itOorPl92BpTf4++GcjHIqm/y+kBhU1UYO760t1uzGm/OPf0Cg6j11X597z6
-fwAAAP//uQ4+qatFAAA=
+H4sIAAAAAAAC/+xcS2/jOBK++1cQnLmNkzSwg8Vu3/a4p93zBm6BlsoyJxTJISl33A3/94Wsh/XgS7bc
+SWMSYDCJVHzUk18VS/19hRD+Vad7KAj+jPDeGPn56ekPLfhD/fRRqPwpU2RnHj79/lQ/+wWvVwhhmlVD
+UsF3NE/qN8nhb4//eKyG1yTmKKEiEts/IDX1MwV/llRBNfgZH0BpKjjerFfVO6mEBGUoaPwZfV8hhFBH
+0j7oTauNojzHK4QQOp1nQAhrUAea9mbotvrL02X+p45sPZ61t1mEEMKSGAOK/3e6N4QQwl+eycO3fz38
+79PDPx+Th81vvw5eV/JVsKuXz2BHOTVU8G593FGemt9O3cIky87EhA3W3hGmYcgzB/NVqJcQzx3ZG/Hc
+rG/hecjOQbCyCGqwpXojZurll9GfhlSBCZtsTfVmFlstvwzDddQIMdxSvRHD9fK3MbxqmbbvEX95faj+
+fzrP6Z2vnqW3vzMTg5hnE6ct5rjl2QnUIckMJBPH887tMqsJCuAGd2JCCG9LyrKx1AWH/1RTPPceIvR9
+HN5P6+H7wV9uo0DIz0v7U9migVdzZsq/dC0Ckb6A2lEGsSOIyrVHZIxqkwiVZDQ11vGMbIHdNENK0j0k
+OyWK4Cy7pOZEWydqI3gk54aoHKIlq/dFoum3gVyfMeUGclB43Y3dnEZjJ5OFHXPs0wghtFlZJsQpkQnJ
+sgETRClyrHZEDRTazh/CJad/lvDvhsSoEsbzZkrI5SfOlShlIomqvNAve5yKoiB8Kdecw0eE5CeHxMDf
+mzX6r7rVeg+d3IT5QbZwEQg34YCDENaiVGls/JjrRwjhkmbxxPkc4kJkw33zstiCwqcJ8Wnl+3uzsr0Z
+ad8QykElnBQQtGMFGXBDCUu0hHRA3mrKoxkcFc+xgpxqo45WygsX/Y1lIIFnOhH8utCLM+jSmUXDRMZ9
+R0o9TXWoVHvDo4GJBqLS/ZXjRUEoj1EqcKOOUtA6jL27+AT8kHR2M1sMwA9UCV60QTruaO+Nf5VCw+3B
+sTtoG8bXnU9v1kPKnVAFqTbbrr1yHMEWy+sLsM9DBYkJSxjlL8ubOLwaRZK90OYa9IT3QJjZp3tIXzzD
++1SD0UKbGCOnBcnDRJwOw/9WCAaED4lkGpxHC0ZMU07xEV6NOfGiquxNK/K8InXZ7ySHiUT/maIHULEQ
+VchL6mU7p0PYIJir9n/wl8c6VfX46Pk3xqaY2HYEj5+MOIxDzQOtFCStwLECrUMW1aQOyQRBXGgnxDo2
+7l+V0czPJKNUFyw3BLhxbW+OlcWY/kXtjBIN+rbUsBeFDr9H2oRt7N+9Yx1DnXPGJ4KBqfqAlzHrRjZh
+CHzPPFXSzB0rzhGi72BSKPNDMqtLnLrAh3rx09o56LL18KD7ZGieKBWXn7VlC/sAWW4Z1XvI5oxRwohU
+sDjHsBai4p3Bk61dhfSkogfKIIcsCGOkEhWqvjIeYQUkSwRnx+BCCrQhKlgi0ZCWippjIqRZHKLaa14X
+p+lKXsMNjW4L0Edd5C9TF9FHnZrroLk2GeWJkMCDvqGNkEmuSAqJBEWFVRSD+JyVqs4sJtNomnPCQm5m
+Crm7siJhTNjZS0YL6nYai9VGwL0a6tkRntO7UFzE9yQY/vwiIrHYEzXj5Dk75s5xvK0iIdTw3v8837rZ
+yMZKPwu5jbexcYInu1OVOpgDnmm4TiKQgeUC++eI0AMdnck3V8XxZqXI2HnvqB+NCBDqV5811QZ4eoxf
+aEsnNylzxB/rvg0Vyd2VHOu4eF+t7ffHsMJFKqRDNTey0R0p9+eixXCO9whNIqcnDS4op0VZ4M/ok4No
+hmTunBkMJ/PlA67YWxV6qpM9o8pnyyd/t8ewkwLNa0cZVXp9PRR90mBfir+fw29gOKOabBnYPWNQGzag
+DoRdh9AUGEVBW7Frj8yAfp+XMIYWIEpzLTwlyswHuOOuNXRpjWmvc3wm1KMcW9Bz77KyrtoEzSQGjwDP
+ztdoUeBFgWQ0JToEEG+4I1CCsS1JX5KmxWoOKPegcUkUYQwY1UVo843KGDleZTkIIYR3hLJSQULSiBuV
+RlecGqGuX7Igr0m77Jkk4LcIIYSFysC1JvCymLhw6xkPO6q0qcsQQjZ/DcP/yVkZir1MuBwdMiMGPkzi
+wyR6oajODfRS5mAtAqBFughlGXvdgQsoRKiJxIHVZtwYjFSuoGr/JK77y/ciAAt1DhwUTZOBNTiOnCnt
+nS5hbrfsGnsIRusUcwnzTgWv9xETeW4MdVXcqYB4IY2OCq1fKc/E1/kwawFpS0ZSGEGzWwWtjSKUm9mt
+DmOxSAU7UMBT8LrltGaE3HUjtFhBXlbFkze4cbJZWwtMK8Ce8DGStVUkrzGbG75qsAYqXyYwHbBeefVu
+0bdbz279VrlldYcE3cpd1+Qq3ob89oNfmmpYMMTjA2FlxO3JVe0qrqpDxOCT9SOrkE5bsgVSu5j2saj+
+pYaqusFc/AYk3KO0CdffqSTFUrE5SiLo0mD+DqNuueWOAvedo+5yR27b2unQ6nNXylp3stpEq9jpGMvt
+n/LL/r3lN2IMSfdRlbqZBZMfUPicFPqtIa2h+ohoMyLaz27/789Wm+9Pg984nqnCn4zeYKERH4u8A/3/
+JGqdHMJWtTZUH2r9WdQ6atfpqXd6beSTeHQL4Aoh1N0SddsYk1n+FQlXhuXclOuSc7RoI2w/5wueW4+/
+eZCs79OBO0HABRol7TodFV9a6U4/gnfHknb85JN4hDDhx5GWEPo+bI2pP2ffnNZukvqznh5Q2EQl5rYP
+5ceNOe0H645ewWH2uqr+O63+PwAGtRkm6kUAAA==
 `,
 	},
 
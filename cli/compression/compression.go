@@ -0,0 +1,189 @@
+// Package compression compresses and decompresses the archives streamed by
+// the CLI. Gzip is handled natively; zstd, like xz in the archive package of
+// the daemon, uses the zstd command of the host.
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/pkg/errors"
+)
+
+// Compression is a compression algorithm
+type Compression string
+
+// Compression algorithms supported by CompressStream
+const (
+	None Compression = "none"
+	Gzip Compression = "gzip"
+	Zstd Compression = "zstd"
+)
+
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// Parse returns the compression algorithm with the given name.
+func Parse(name string) (Compression, error) {
+	switch c := Compression(strings.ToLower(name)); c {
+	case None, Gzip, Zstd:
+		return c, nil
+	}
+	return "", errors.Errorf("invalid compression %q: must be one of %q, %q, or %q", name, None, Gzip, Zstd)
+}
+
+// FromPath returns the compression algorithm matching the extension of the
+// path, such as Zstd for "backup.tar.zst", or None for other extensions.
+func FromPath(path string) Compression {
+	switch {
+	case strings.HasSuffix(path, ".gz"), strings.HasSuffix(path, ".tgz"):
+		return Gzip
+	case strings.HasSuffix(path, ".zst"), strings.HasSuffix(path, ".tzst"):
+		return Zstd
+	}
+	return None
+}
+
+// Supported returns an error if the compression algorithm is not available on
+// this host.
+func Supported(c Compression) error {
+	if c != Zstd {
+		return nil
+	}
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return errors.Wrap(err, "zstd compression requires the zstd command")
+	}
+	return nil
+}
+
+// CompressStream returns a writer compressing what is written to it into
+// dest. The writer must be closed to flush the compressed stream.
+func CompressStream(dest io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case None:
+		return archive.CompressStream(dest, archive.Uncompressed)
+	case Gzip:
+		return archive.CompressStream(dest, archive.Gzip)
+	case Zstd:
+		return zstdCompress(dest)
+	}
+	return nil, errors.Errorf("unsupported compression %q", c)
+}
+
+// DecompressStream returns a reader decompressing the archive, whose
+// compression is detected from its first bytes. Besides the compressions of
+// CompressStream, bzip2 and xz archives are supported.
+func DecompressStream(src io.Reader) (io.ReadCloser, error) {
+	buf := bufio.NewReader(src)
+	header, err := buf.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if bytes.Equal(header, zstdMagic) {
+		return zstdDecompress(buf)
+	}
+	return archive.DecompressStream(buf)
+}
+
+func zstdCompress(dest io.Writer) (io.WriteCloser, error) {
+	if err := Supported(Zstd); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("zstd", "--quiet", "--stdout", "--threads=0")
+	cmd.Stdout = dest
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := startCommand(cmd); err != nil {
+		return nil, err
+	}
+	return &commandWriter{WriteCloser: stdin, cmd: cmd}, nil
+}
+
+func zstdDecompress(src io.Reader) (io.ReadCloser, error) {
+	if err := Supported(Zstd); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("zstd", "--decompress", "--quiet", "--stdout")
+	cmd.Stdin = src
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := startCommand(cmd); err != nil {
+		return nil, err
+	}
+	return &commandReader{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+func startCommand(cmd *exec.Cmd) error {
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	return errors.Wrap(cmd.Start(), "unable to start zstd")
+}
+
+func commandError(cmd *exec.Cmd, err error) error {
+	if err == nil {
+		return nil
+	}
+	if stderr, ok := cmd.Stderr.(*bytes.Buffer); ok && stderr.Len() > 0 {
+		return errors.Errorf("zstd: %s", strings.TrimSpace(stderr.String()))
+	}
+	return errors.Wrap(err, "zstd")
+}
+
+// commandWriter writes to the standard input of a command, and waits for the
+// command to exit when closed.
+type commandWriter struct {
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+func (w *commandWriter) Close() error {
+	err := w.WriteCloser.Close()
+	if waitErr := w.cmd.Wait(); waitErr != nil {
+		err = waitErr
+	}
+	return commandError(w.cmd, err)
+}
+
+// commandReader reads the standard output of a command. Reading the end of
+// the output returns the error of the command, if any.
+type commandReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	waited bool
+	err    error
+}
+
+func (r *commandReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		if waitErr := r.wait(); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *commandReader) wait() error {
+	if !r.waited {
+		r.waited = true
+		r.err = commandError(r.cmd, r.cmd.Wait())
+	}
+	return r.err
+}
+
+func (r *commandReader) Close() error {
+	if !r.waited {
+		// The output is not read until its end: stop the command
+		r.cmd.Process.Kill()
+		r.wait()
+		return nil
+	}
+	return r.err
+}
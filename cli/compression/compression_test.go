@@ -0,0 +1,70 @@
+package compression
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+)
+
+func TestParse(t *testing.T) {
+	c, err := Parse("ZSTD")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(Zstd, c))
+
+	_, err = Parse("lz4")
+	assert.Check(t, is.Error(err, `invalid compression "lz4": must be one of "none", "gzip", or "zstd"`))
+}
+
+func TestFromPath(t *testing.T) {
+	testCases := map[string]Compression{
+		"backup.tar":     None,
+		"backup.tar.gz":  Gzip,
+		"backup.tgz":     Gzip,
+		"backup.tar.zst": Zstd,
+		"backup":         None,
+	}
+	for path, expected := range testCases {
+		assert.Check(t, is.Equal(expected, FromPath(path)), path)
+	}
+}
+
+func TestCompressStream(t *testing.T) {
+	for _, c := range []Compression{None, Gzip, Zstd} {
+		t.Run(string(c), func(t *testing.T) {
+			if c == Zstd {
+				if _, err := exec.LookPath("zstd"); err != nil {
+					t.Skip("zstd is not installed")
+				}
+			}
+			compressed := new(bytes.Buffer)
+			w, err := CompressStream(compressed, c)
+			assert.NilError(t, err)
+			_, err = w.Write([]byte("volume contents"))
+			assert.NilError(t, err)
+			assert.NilError(t, w.Close())
+
+			r, err := DecompressStream(compressed)
+			assert.NilError(t, err)
+			defer r.Close()
+			b, err := ioutil.ReadAll(r)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal("volume contents", string(b)))
+		})
+	}
+}
+
+func TestZstdUnavailable(t *testing.T) {
+	defer env.Patch(t, "PATH", os.TempDir())()
+
+	assert.Check(t, is.ErrorContains(Supported(Zstd), "zstd compression requires the zstd command"))
+	assert.Check(t, Supported(Gzip))
+
+	_, err := DecompressStream(bytes.NewReader(append(zstdMagic, 0)))
+	assert.Check(t, is.ErrorContains(err, "zstd compression requires the zstd command"))
+}
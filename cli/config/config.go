@@ -89,12 +89,11 @@ func Load(configDir string) (*configfile.ConfigFile, error) {
 
 	// Try happy path first - latest config file
 	if _, err := os.Stat(filename); err == nil {
-		file, err := os.Open(filename)
+		content, resolved, err := ResolveFile(filename)
 		if err != nil {
-			return configFile, errors.Wrap(err, filename)
+			return configFile, err
 		}
-		defer file.Close()
-		err = configFile.LoadFromReader(file)
+		err = configFile.LoadResolved(content, resolved)
 		if err != nil {
 			err = errors.Wrap(err, filename)
 		}
@@ -1,6 +1,7 @@
 package configfile
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/docker/cli/cli/config/credentials"
@@ -50,6 +52,55 @@ type ConfigFile struct {
 	CurrentContext       string                       `json:"currentContext,omitempty"`
 	CLIPluginsExtraDirs  []string                     `json:"cliPluginsExtraDirs,omitempty"`
 	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
+	Tracing              *TracingConfig               `json:"tracing,omitempty"`
+	Aliases              map[string]string            `json:"aliases,omitempty"`
+	Retries              *RetryConfig                 `json:"retries,omitempty"`
+	CLIPluginsPolicy     *CLIPluginsPolicy            `json:"cliPluginsPolicy,omitempty"`
+	// CLIPluginsProjectDirs records whether the per-project plugin
+	// directories, such as "/src/app/.docker/cli-plugins", are trusted, by
+	// path. The plugins of the directories which are not trusted are ignored.
+	CLIPluginsProjectDirs map[string]bool `json:"cliPluginsProjectDirs,omitempty"`
+	// Theme is the color of each role of colored output, such as "error" or
+	// "warning", as a space-separated list of attributes, such as "bold red".
+	Theme map[string]string `json:"theme,omitempty"`
+	// Language is the language of the messages, such as "pt_BR", which
+	// overrides the LC_ALL, LC_MESSAGES, and LANG environment variables.
+	Language string `json:"language,omitempty"`
+	// RunPresets are the flags of "docker run" and "docker create" applied
+	// by the --preset flag, by name, such as "-v /data:/data --init".
+	RunPresets map[string]string `json:"runPresets,omitempty"`
+	// SignatureVerification is the verification of the signatures of the
+	// images with content trust, by registry hostname, such as "docker.io".
+	SignatureVerification map[string]SignatureVerificationConfig `json:"signatureVerification,omitempty"`
+	// Truncation is the truncation of the output of the commands, such as
+	// "docker ps".
+	Truncation *TruncationConfig `json:"truncation,omitempty"`
+	// AuditLog is the audit log of the commands executed by the CLI.
+	AuditLog *AuditLogConfig `json:"auditLog,omitempty"`
+	// PruneConfirmation are the prune commands which always prompt for
+	// confirmation, even with --force, such as "volume" for "docker volume
+	// prune", or "system" for "docker system prune".
+	PruneConfirmation []string `json:"pruneConfirmation,omitempty"`
+	// OfflineCache is "enabled" to enable the cache of the responses of the
+	// daemon listing and inspecting the containers and the images, which
+	// are shown with --offline when the daemon is unreachable.
+	OfflineCache string `json:"offlineCache,omitempty"`
+	// AttachTransport is the transport of the attached streams of the
+	// containers: "auto", "hijack", or "websocket".
+	AttachTransport string `json:"attachTransport,omitempty"`
+	// Templates are the named templates of the --format flags, by name,
+	// referenced as "template:<name>".
+	Templates map[string]string `json:"templates,omitempty"`
+	// Source is the content of the file when it includes other files. Note:
+	// for internal use only
+	Source *Source `json:"-"`
+}
+
+// Source is the content of a configuration file, and its resolved content,
+// with the content of the files it includes
+type Source struct {
+	Content  map[string]interface{}
+	Resolved map[string]interface{}
 }
 
 // ProxyConfig contains proxy configuration settings
@@ -65,6 +116,87 @@ type KubernetesConfig struct {
 	AllNamespaces string `json:"allNamespaces,omitempty"`
 }
 
+// TracingConfig contains the settings of the export of the traces of the
+// CLI to an OpenTelemetry collector
+type TracingConfig struct {
+	// Endpoint is the URL of the OTLP/HTTP endpoint of the collector, such
+	// as "http://localhost:4318". Traces are not recorded if it is empty.
+	Endpoint string            `json:"endpoint,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+// RetryConfig contains the settings of the retries of the requests to the
+// daemon that failed
+type RetryConfig struct {
+	// Attempts is the number of times a failed request is retried. Requests
+	// are not retried if it is zero.
+	Attempts int `json:"attempts,omitempty"`
+	// Delay is the delay before the first retry, such as "500ms". The delay
+	// doubles with each retry, up to MaxDelay.
+	Delay    string `json:"delay,omitempty"`
+	MaxDelay string `json:"maxDelay,omitempty"`
+}
+
+// AuditLogConfig contains the settings of the audit log, which records each
+// command executed by the CLI, including the commands of the CLI plugins
+type AuditLogConfig struct {
+	// File is the path of the file the records are appended to, one JSON
+	// object per line.
+	File string `json:"file,omitempty"`
+	// Syslog sends the records to the local syslog daemon.
+	Syslog bool `json:"syslog,omitempty"`
+}
+
+// CLIPluginsPolicy restricts the CLI plugins which can be listed and run.
+// The plugins which do not conform to the policy are ignored.
+type CLIPluginsPolicy struct {
+	// Allowed are the names of the plugins which can be run. All the plugins
+	// which are not denied can be run if it is empty.
+	Allowed []string `json:"allowed,omitempty"`
+	Denied  []string `json:"denied,omitempty"`
+	// Digests are the digests the binaries of the plugins must have, by
+	// plugin name, such as "sha256:...".
+	Digests map[string]string `json:"digests,omitempty"`
+	// Dirs are the directories plugins are looked up in. The plugins of the
+	// other directories are ignored if it is not empty.
+	Dirs []string `json:"dirs,omitempty"`
+}
+
+// TruncationConfig contains the settings of the truncation of the output of
+// the commands
+type TruncationConfig struct {
+	// NoTrunc disables the truncation of the output of all the commands, as
+	// the --no-trunc flag of the commands does.
+	NoTrunc bool `json:"noTrunc,omitempty"`
+	// ColumnWidths are the maximum widths of the truncated columns, by
+	// command, such as "ps", and by column, such as "Command". A width of 0
+	// disables the truncation of a column.
+	ColumnWidths map[string]map[string]int `json:"columnWidths,omitempty"`
+}
+
+// SignatureVerificationConfig contains the settings of the verification of
+// the signatures of the images of a registry with content trust
+type SignatureVerificationConfig struct {
+	// Backend is "notary", the default, to verify the signatures of Notary
+	// v1, or "cosign" to verify cosign signatures.
+	Backend string `json:"backend,omitempty"`
+	// PublicKey is the path of the PEM public key of the signatures. Without
+	// it, the signatures must have certificates issued by the Fulcio roots.
+	PublicKey string `json:"publicKey,omitempty"`
+	// CertificateIdentity and CertificateOIDCIssuer are the identity, such
+	// as an email address or a URI, and the OIDC issuer of the certificates
+	// of the signatures.
+	CertificateIdentity   string `json:"certificateIdentity,omitempty"`
+	CertificateOIDCIssuer string `json:"certificateOidcIssuer,omitempty"`
+	// FulcioRoots is the path of the PEM certificates of the Fulcio roots
+	// and intermediates.
+	FulcioRoots string `json:"fulcioRoots,omitempty"`
+	// RekorPublicKey is the path of the PEM public key of the Rekor
+	// transparency log. If set, the signatures must have been recorded in
+	// the log.
+	RekorPublicKey string `json:"rekorPublicKey,omitempty"`
+}
+
 // New initializes an empty configuration file for the given filename 'fn'
 func New(fn string) *ConfigFile {
 	return &ConfigFile{
@@ -132,6 +264,24 @@ func (configFile *ConfigFile) LoadFromReader(configData io.Reader) error {
 	return checkKubernetesConfiguration(configFile.Kubernetes)
 }
 
+// LoadResolved loads the resolved content of the configuration file, with
+// the content of the files it includes. When the configuration file is saved,
+// the values of the resolved content which are unchanged are saved as they are
+// in content, so that the included values are not written to the file.
+func (configFile *ConfigFile) LoadResolved(content, resolved map[string]interface{}) error {
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return err
+	}
+	if err := configFile.LoadFromReader(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(content, resolved) {
+		configFile.Source = &Source{Content: content, Resolved: resolved}
+	}
+	return nil
+}
+
 // ContainsAuth returns whether there is authentication configured
 // in this file or not.
 func (configFile *ConfigFile) ContainsAuth() bool {
@@ -168,10 +318,57 @@ func (configFile *ConfigFile) SaveToWriter(writer io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if configFile.Source != nil {
+		if data, err = configFile.unresolve(data); err != nil {
+			return err
+		}
+	}
 	_, err = writer.Write(data)
 	return err
 }
 
+// unresolve returns the content to save of the marshaled configuration file,
+// in which the values which are unchanged since it was loaded are the ones of
+// the content of the file
+func (configFile *ConfigFile) unresolve(data []byte) ([]byte, error) {
+	var current map[string]interface{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil, err
+	}
+	value, _ := unresolveValue(current, configFile.Source.Resolved, configFile.Source.Content, true)
+	content := value.(map[string]interface{})
+	if include, ok := configFile.Source.Content["include"]; ok {
+		content["include"] = include
+	}
+	return json.MarshalIndent(content, "", "\t")
+}
+
+// unresolveValue returns the value to save of the current value of a
+// property, and whether it is saved, given its resolved value, and its value
+// in the content of the file, if it is in the file
+func unresolveValue(current, resolved, content interface{}, inContent bool) (interface{}, bool) {
+	if reflect.DeepEqual(current, resolved) {
+		return content, inContent
+	}
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return current, true
+	}
+	resolvedMap, ok := resolved.(map[string]interface{})
+	if !ok {
+		return current, true
+	}
+	contentMap, _ := content.(map[string]interface{})
+	value := make(map[string]interface{}, len(currentMap))
+	for key, v := range currentMap {
+		c, inContent := contentMap[key]
+		if v, ok := unresolveValue(v, resolvedMap[key], c, inContent); ok {
+			value[key] = v
+		}
+	}
+	return value, true
+}
+
 // Save encodes and writes out all the authorization information
 func (configFile *ConfigFile) Save() error {
 	if configFile.Filename == "" {
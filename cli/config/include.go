@@ -0,0 +1,137 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// includeKey is the property of a configuration file with the paths of the
+// configuration files it includes, such as a machine-wide base configuration
+const includeKey = "include"
+
+// envVarPattern matches the references to environment variables of the paths
+// of the included files, such as "${HOME}"
+var envVarPattern = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+// ResolveFile returns the content of a configuration file, and its resolved
+// content. The resolved content is the content of the files it includes,
+// which is overridden by its own content. The references to environment
+// variables of the paths of the included files, such as "${HOME}", are
+// replaced with their values; the other values are left as they are.
+func ResolveFile(filename string) (map[string]interface{}, map[string]interface{}, error) {
+	return resolveFile(filename, nil)
+}
+
+// resolveFile resolves a configuration file, which is included by the files
+// of the chain, if any
+func resolveFile(filename string, chain []string) (map[string]interface{}, map[string]interface{}, error) {
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range chain {
+		if f == filename {
+			return nil, nil, errors.Errorf("include cycle: %s", strings.Join(append(chain, filename), " -> "))
+		}
+	}
+	chain = append(chain, filename)
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	var content map[string]interface{}
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&content); err != nil {
+		return nil, nil, errors.Wrap(err, filename)
+	}
+
+	includes, err := includePaths(content[includeKey])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, filename)
+	}
+	resolved := map[string]interface{}{}
+	for _, include := range includes {
+		if include, err = expandEnv(include); err != nil {
+			return nil, nil, errors.Wrap(err, filename)
+		}
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(filename), include)
+		}
+		_, included, err := resolveFile(include, chain)
+		if err != nil {
+			return nil, nil, err
+		}
+		mergeConfig(resolved, included)
+	}
+	own := make(map[string]interface{}, len(content))
+	for key, value := range content {
+		if key != includeKey {
+			own[key] = value
+		}
+	}
+	mergeConfig(resolved, own)
+	return content, resolved, nil
+}
+
+// expandEnv replaces the references to environment variables of a path, such
+// as "${HOME}", with their values. The variables must be set.
+func expandEnv(path string) (string, error) {
+	var err error
+	expanded := envVarPattern.ReplaceAllStringFunc(path, func(ref string) string {
+		name := envVarPattern.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = errors.Errorf("invalid %q property: environment variable %s of %s is not set", includeKey, name, path)
+		}
+		return value
+	})
+	return expanded, err
+}
+
+// includePaths returns the paths of the "include" property, which is either
+// a path or a list of paths
+func includePaths(value interface{}) ([]string, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		paths := make([]string, 0, len(value))
+		for _, v := range value {
+			path, ok := v.(string)
+			if !ok {
+				return nil, errors.Errorf("invalid %q property: %v is not a path", includeKey, v)
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, errors.Errorf("invalid %q property: must be a path or a list of paths", includeKey)
+	}
+}
+
+// mergeConfig merges the properties of a configuration into dst. The
+// properties which are objects in both configurations are merged, while the
+// other properties of dst are overridden.
+func mergeConfig(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			merged := make(map[string]interface{}, len(dstMap))
+			mergeConfig(merged, dstMap)
+			mergeConfig(merged, srcMap)
+			dst[key] = merged
+			continue
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+	"gotest.tools/fs"
+)
+
+func TestResolveFile(t *testing.T) {
+	base := fs.NewDir(t, "config-base",
+		fs.WithFile("base.json", `{
+			"psFormat": "{{.ID}}",
+			"detachKeys": "ctrl-e,e",
+			"proxies": {"default": {"httpProxy": "http://proxy:3128", "noProxy": "localhost"}}
+		}`),
+	)
+	defer base.Remove()
+	defer env.Patch(t, "DOCKER_TEST_BASE", base.Path())()
+	dir := fs.NewDir(t, "config-test",
+		fs.WithFile("config.json", `{
+			"include": "${DOCKER_TEST_BASE}/base.json",
+			"psFormat": "{{.Names}}",
+			"proxies": {"default": {"noProxy": "*.example.com"}}
+		}`),
+	)
+	defer dir.Remove()
+
+	content, resolved, err := ResolveFile(dir.Join("config.json"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("${DOCKER_TEST_BASE}/base.json", content["include"]))
+	assert.Check(t, is.DeepEqual(map[string]interface{}{
+		"psFormat":   "{{.Names}}",
+		"detachKeys": "ctrl-e,e",
+		"proxies": map[string]interface{}{
+			"default": map[string]interface{}{"httpProxy": "http://proxy:3128", "noProxy": "*.example.com"},
+		},
+	}, resolved))
+}
+
+func TestResolveFileRelativeIncludes(t *testing.T) {
+	dir := fs.NewDir(t, "config-test",
+		fs.WithFile("config.json", `{"include": ["base/first.json", "base/second.json"]}`),
+		fs.WithDir("base",
+			fs.WithFile("first.json", `{"psFormat": "first", "imagesFormat": "first"}`),
+			fs.WithFile("second.json", `{"psFormat": "second"}`),
+		),
+	)
+	defer dir.Remove()
+
+	_, resolved, err := ResolveFile(dir.Join("config.json"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]interface{}{"psFormat": "second", "imagesFormat": "first"}, resolved))
+}
+
+func TestResolveFileErrors(t *testing.T) {
+	dir := fs.NewDir(t, "config-test",
+		fs.WithFile("a.json", `{"include": "b.json"}`),
+		fs.WithFile("b.json", `{"include": "a.json"}`),
+		fs.WithFile("invalid.json", `{"include": 1}`),
+		fs.WithFile("unset.json", `{"include": "${DOCKER_TEST_UNSET}/base.json"}`),
+	)
+	defer dir.Remove()
+
+	_, _, err := ResolveFile(dir.Join("a.json"))
+	assert.Check(t, is.Error(err, "include cycle: "+dir.Join("a.json")+" -> "+dir.Join("b.json")+" -> "+dir.Join("a.json")))
+	_, _, err = ResolveFile(dir.Join("invalid.json"))
+	assert.Check(t, is.ErrorContains(err, `invalid "include" property: must be a path or a list of paths`))
+	_, _, err = ResolveFile(dir.Join("unset.json"))
+	assert.Check(t, is.ErrorContains(err, `invalid "include" property: environment variable DOCKER_TEST_UNSET of ${DOCKER_TEST_UNSET}/base.json is not set`))
+}
+
+func TestResolveFileKeepsDollarSigns(t *testing.T) {
+	defer env.Patch(t, "k", "value")()
+	defer env.Patch(t, "HOME", "/home/user")()
+	dir := fs.NewDir(t, "config-test",
+		fs.WithFile("base.json", `{"imagesFormat": "{{.ID}}"}`),
+		fs.WithFile("config.json", `{
+			"include": "base.json",
+			"psFormat": "{{range $k, $v := .Labels}}{{$k}}{{end}}",
+			"detachKeys": "$$",
+			"HttpHeaders": {"X-Home": "${HOME}"}
+		}`),
+	)
+	defer dir.Remove()
+
+	_, resolved, err := ResolveFile(dir.Join("config.json"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]interface{}{
+		"imagesFormat": "{{.ID}}",
+		"psFormat":     "{{range $k, $v := .Labels}}{{$k}}{{end}}",
+		"detachKeys":   "$$",
+		"HttpHeaders":  map[string]interface{}{"X-Home": "${HOME}"},
+	}, resolved))
+}
+
+func TestLoadSaveResolvedFile(t *testing.T) {
+	dir := fs.NewDir(t, "config-test",
+		fs.WithDir("base", fs.WithFile("base.json", `{"imagesFormat": "{{.ID}}", "aliases": {"ll": "container ls"}}`)),
+		fs.WithFile(ConfigFileName, `{"include": "${DOCKER_TEST_BASE}/base.json", "psFormat": "{{.Names}}"}`),
+	)
+	defer dir.Remove()
+	defer env.Patch(t, "DOCKER_TEST_BASE", dir.Join("base"))()
+
+	configFile, err := Load(dir.Path())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("{{.Names}}", configFile.PsFormat))
+	assert.Check(t, is.Equal("{{.ID}}", configFile.ImagesFormat))
+
+	// Only the changes are saved, and the included values are not written to
+	// the file
+	configFile.Aliases["it"] = "run -it"
+	assert.NilError(t, configFile.Save())
+	data, err := ioutil.ReadFile(filepath.Join(dir.Path(), ConfigFileName))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(`{
+	"aliases": {
+		"it": "run -it"
+	},
+	"auths": {},
+	"include": "${DOCKER_TEST_BASE}/base.json",
+	"psFormat": "{{.Names}}"
+}`, string(data)))
+
+	configFile, err = Load(dir.Path())
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(map[string]string{"ll": "container ls", "it": "run -it"}, configFile.Aliases))
+}
@@ -3,6 +3,8 @@ package cli
 import (
 	"fmt"
 	"strings"
+
+	"github.com/docker/cli/cli/command"
 )
 
 // Errors is a list of errors.
@@ -26,8 +28,29 @@ func (errList Errors) Error() string {
 type StatusError struct {
 	Status     string
 	StatusCode int
+	// Code is the machine-readable code of the kind of error, such as
+	// command.ErrorCodeNotFound, if any.
+	Code string
+	// WrappedErr is the error which caused the unsuccessful exit, if any.
+	WrappedErr error
+}
+
+// NewStatusError returns the StatusError of an error, whose status is the
+// message of the error, and whose exit code and Code are those of its kind,
+// as classified by command.ClassifyError. A StatusError is returned as is.
+func NewStatusError(err error) StatusError {
+	if sterr, ok := err.(StatusError); ok {
+		return sterr
+	}
+	code, exitCode := command.ClassifyError(err)
+	return StatusError{Status: err.Error(), StatusCode: exitCode, Code: code, WrappedErr: err}
 }
 
 func (e StatusError) Error() string {
 	return fmt.Sprintf("Status: %s, Code: %d", e.Status, e.StatusCode)
 }
+
+// Unwrap returns the error which caused the unsuccessful exit, if any.
+func (e StatusError) Unwrap() error {
+	return e.WrappedErr
+}
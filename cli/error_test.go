@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestNewStatusError(t *testing.T) {
+	err := errors.Wrap(errdefs.NotFound(errors.New("No such container: foo")), "Error response from daemon")
+	sterr := NewStatusError(err)
+	assert.Check(t, is.Equal(sterr.Status, "Error response from daemon: No such container: foo"))
+	assert.Check(t, is.Equal(sterr.StatusCode, command.ExitCodeNotFound))
+	assert.Check(t, is.Equal(sterr.Code, command.ErrorCodeNotFound))
+	assert.Check(t, sterr.Unwrap() == err)
+
+	sterr = NewStatusError(errors.New("something went wrong"))
+	assert.Check(t, is.Equal(sterr.StatusCode, command.ExitCodeError))
+	assert.Check(t, is.Equal(sterr.Code, ""))
+
+	// A StatusError, such as the exit status of a container, is unchanged
+	original := StatusError{StatusCode: 3}
+	assert.Check(t, is.Equal(NewStatusError(original), original))
+}
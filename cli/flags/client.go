@@ -4,6 +4,9 @@ package flags
 type ClientOptions struct {
 	Common    *CommonOptions
 	ConfigDir string
+	// IgnoreCLIPluginsPolicy overrides the CLI plugins policy of the
+	// configuration file
+	IgnoreCLIPluginsPolicy bool
 }
 
 // NewClientOptions returns a new ClientOptions
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/opts"
 	"github.com/docker/go-connections/tlsconfig"
 	"github.com/sirupsen/logrus"
@@ -38,6 +39,13 @@ type CommonOptions struct {
 	TLSVerify  bool
 	TLSOptions *tlsconfig.Options
 	Context    string
+	Retries    int
+	// RetriesSet is set if the number of retries is set with the --retries
+	// flag, which then overrides the configuration file, even if it is zero.
+	RetriesSet bool
+	Color      string
+	NoTrunc    bool
+	Offline    bool
 }
 
 // NewCommonOptions returns a new CommonOptions
@@ -73,6 +81,10 @@ func (commonOpts *CommonOptions) InstallFlags(flags *pflag.FlagSet) {
 	flags.VarP(hostOpt, "host", "H", "Daemon socket(s) to connect to")
 	flags.StringVarP(&commonOpts.Context, "context", "c", "",
 		`Name of the context to use to connect to the daemon (overrides DOCKER_HOST env var and default context set with "docker context use")`)
+	flags.IntVar(&commonOpts.Retries, "retries", 0, "Number of times to retry failed requests to the daemon (overrides the configuration file)")
+	flags.StringVar(&commonOpts.Color, "color", string(streams.ColorAuto), `Colorize the output ("auto"|"always"|"never")`)
+	flags.BoolVar(&commonOpts.NoTrunc, "no-trunc", false, "Do not truncate the output of the commands")
+	flags.BoolVar(&commonOpts.Offline, "offline", false, "Show the cached output of the listing and inspect commands if the daemon is unreachable")
 }
 
 // SetDefaultOptions sets default values for options after flag parsing is
@@ -86,6 +98,8 @@ func (commonOpts *CommonOptions) SetDefaultOptions(flags *pflag.FlagSet) {
 		commonOpts.TLS = true
 	}
 
+	commonOpts.RetriesSet = flags.Changed("retries")
+
 	if !commonOpts.TLS {
 		commonOpts.TLSOptions = nil
 	} else {
@@ -41,3 +41,20 @@ func TestCommonOptionsInstallFlagsWithDefaults(t *testing.T) {
 	assert.Check(t, is.Equal(defaultPath("cert.pem"), opts.TLSOptions.CertFile))
 	assert.Check(t, is.Equal(defaultPath("key.pem"), opts.TLSOptions.KeyFile))
 }
+
+func TestCommonOptionsSetDefaultOptionsRetries(t *testing.T) {
+	flags := pflag.NewFlagSet("testing", pflag.ContinueOnError)
+	opts := NewCommonOptions()
+	opts.InstallFlags(flags)
+	assert.NilError(t, flags.Parse([]string{}))
+	opts.SetDefaultOptions(flags)
+	assert.Check(t, !opts.RetriesSet)
+
+	flags = pflag.NewFlagSet("testing", pflag.ContinueOnError)
+	opts = NewCommonOptions()
+	opts.InstallFlags(flags)
+	assert.NilError(t, flags.Parse([]string{"--retries", "0"}))
+	opts.SetDefaultOptions(flags)
+	assert.Check(t, opts.RetriesSet)
+	assert.Check(t, is.Equal(0, opts.Retries))
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// visitAll calls fn for the command and all its subcommands
+func visitAll(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, c := range cmd.Commands() {
+		visitAll(c, fn)
+	}
+}
+
+func visitFlags(cmd *cobra.Command, fn func(*pflag.Flag)) {
+	cmd.LocalNonPersistentFlags().VisitAll(fn)
+	cmd.PersistentFlags().VisitAll(fn)
+}
+
+// TranslateCommand translates the descriptions of the command, of its flags,
+// and of all its subcommands with the catalog
+func TranslateCommand(cmd *cobra.Command, c *Catalog) {
+	if c == nil {
+		return
+	}
+	visitAll(cmd, func(cmd *cobra.Command) {
+		cmd.Short = c.T(cmd.Short)
+		cmd.Long = c.T(cmd.Long)
+		visitFlags(cmd, func(f *pflag.Flag) {
+			f.Usage = c.T(f.Usage)
+		})
+	})
+}
+
+// CommandMessages returns the sorted descriptions of the command, of its
+// flags, and of all its subcommands, which are translated by
+// TranslateCommand
+func CommandMessages(cmd *cobra.Command) []string {
+	seen := map[string]bool{}
+	add := func(msg string) {
+		if msg != "" {
+			seen[msg] = true
+		}
+	}
+	visitAll(cmd, func(cmd *cobra.Command) {
+		add(cmd.Short)
+		add(cmd.Long)
+		visitFlags(cmd, func(f *pflag.Flag) {
+			add(f.Usage)
+		})
+	})
+	messages := make([]string, 0, len(seen))
+	for msg := range seen {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	return messages
+}
@@ -0,0 +1,81 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+)
+
+func newTestCommand() (*cobra.Command, *cobra.Command) {
+	root := &cobra.Command{Use: "docker", Short: "A self-sufficient runtime for containers"}
+	root.PersistentFlags().String("context", "", "Name of the context to use")
+	sub := &cobra.Command{Use: "ps", Short: "List containers", Run: func(*cobra.Command, []string) {}}
+	sub.Flags().Bool("all", false, "Show all containers")
+	root.AddCommand(sub)
+	return root, sub
+}
+
+func TestTranslateCommand(t *testing.T) {
+	root, sub := newTestCommand()
+	TranslateCommand(root, NewCatalog("fr", map[string]string{
+		"List containers":            "Lister les conteneurs",
+		"Show all containers":        "Afficher tous les conteneurs",
+		"Name of the context to use": "Nom du contexte à utiliser",
+	}))
+
+	assert.Check(t, is.Equal("A self-sufficient runtime for containers", root.Short))
+	assert.Check(t, is.Equal("Nom du contexte à utiliser", root.PersistentFlags().Lookup("context").Usage))
+	assert.Check(t, is.Equal("Lister les conteneurs", sub.Short))
+	assert.Check(t, is.Equal("Afficher tous les conteneurs", sub.Flags().Lookup("all").Usage))
+}
+
+func TestCommandMessages(t *testing.T) {
+	root, _ := newTestCommand()
+	assert.Check(t, is.DeepEqual([]string{
+		"A self-sufficient runtime for containers",
+		"List containers",
+		"Name of the context to use",
+		"Show all containers",
+	}, CommandMessages(root)))
+}
+
+func TestSourceMessages(t *testing.T) {
+	dir := fs.NewDir(t, "source",
+		fs.WithFile("cmd.go", `package cmd
+
+const usage = `+"`"+`{{t "Usage:"}}
+{{printf (t "Run '%s COMMAND --help'") .CommandPath}}`+"`"+`
+
+func run(dockerCli command.Cli) error {
+	command.PrintWarning(dockerCli, "No swap limit support")
+	if !command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), "Do you grant the above permissions?") {
+		return errors.New(i18n.T("Cancelled"))
+	}
+	return errors.New(i18n.Sprintf("%d containers", 3) + fmt.Sprintf("not translated"))
+}
+`),
+		fs.WithFile("cmd_test.go", `package cmd
+
+var _ = i18n.T("test")
+`),
+		fs.WithDir("vendor", fs.WithFile("vendored.go", `package vendored
+
+var _ = i18n.T("vendored")
+`)),
+	)
+	defer dir.Remove()
+
+	messages, err := SourceMessages(dir.Path())
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{
+		"%d containers",
+		"Cancelled",
+		"Do you grant the above permissions?",
+		"No swap limit support",
+		"Run '%s COMMAND --help'",
+		"Usage:",
+	}, messages))
+}
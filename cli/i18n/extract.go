@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// templateMessage matches the messages translated in templates, such as
+// `{{t "Usage:"}}`, or `{{printf (t "Run '%s'") .CommandPath}}`
+var templateMessage = regexp.MustCompile(`(?:{{-?|\()\s*t\s+("(?:[^"\\]|\\.)*")`)
+
+// SourceMessages returns the sorted messages of the Go source files of the
+// directory and its subdirectories which are translated, such as by T or
+// Sprintf, or by the "t" function of templates. Vendored and test files are skipped.
+func SourceMessages(dir string) ([]string, error) {
+	seen := map[string]bool{}
+	fset := token.NewFileSet()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "vendor" || info.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.CallExpr:
+				if arg, ok := translatedArg(n); ok {
+					if msg, ok := stringLiteral(arg); ok && msg != "" {
+						seen[msg] = true
+					}
+				}
+			case *ast.BasicLit:
+				if s, ok := stringLiteral(n); ok {
+					for _, m := range templateMessage.FindAllStringSubmatch(s, -1) {
+						if msg, err := strconv.Unquote(m[1]); err == nil && msg != "" {
+							seen[msg] = true
+						}
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]string, 0, len(seen))
+	for msg := range seen {
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
+
+// translatedArg returns the argument of the call which is a translated
+// message, for the calls of i18n.T, i18n.Sprintf, and of the PrintWarning and
+// PromptForConfirmation functions of the command package, which translate
+// their message.
+func translatedArg(call *ast.CallExpr) (ast.Expr, bool) {
+	var pkg, name string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			pkg = x.Name
+		}
+		name = fun.Sel.Name
+	}
+	i := -1
+	switch {
+	case pkg == "i18n" && (name == "T" || name == "Sprintf"):
+		i = 0
+	case (pkg == "" || pkg == "command") && name == "PrintWarning":
+		i = 1
+	case (pkg == "" || pkg == "command") && name == "PromptForConfirmation":
+		i = 2
+	}
+	if i < 0 || len(call.Args) <= i {
+		return nil, false
+	}
+	return call.Args[i], true
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
@@ -0,0 +1,200 @@
+// Package i18n translates the messages of the CLI, such as usage text,
+// errors, and prompts.
+//
+// Messages are identified by their English text, and are translated by a
+// catalog of the language of the user. Catalogs are loaded by loaders, such as
+// the "locales" directory of the configuration directory, which has a JSON
+// file per language, such as "pt_BR.json", of the translations by message.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Catalog is the translations of the messages of the CLI into a language. A
+// nil catalog does not translate messages.
+type Catalog struct {
+	language string
+	messages map[string]string
+}
+
+// NewCatalog returns a catalog of the translations of the messages into the
+// language
+func NewCatalog(language string, messages map[string]string) *Catalog {
+	return &Catalog{language: language, messages: messages}
+}
+
+// Language returns the language of the catalog, such as "pt_BR"
+func (c *Catalog) Language() string {
+	if c == nil {
+		return ""
+	}
+	return c.language
+}
+
+// T returns the translation of the message, or the message if it has no
+// translation
+func (c *Catalog) T(msg string) string {
+	if c == nil {
+		return msg
+	}
+	if translation := c.messages[msg]; translation != "" {
+		return translation
+	}
+	return msg
+}
+
+// Sprintf formats the translation of the format according to the arguments
+func (c *Catalog) Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(c.T(format), args...)
+}
+
+// Loader loads the translations of the messages into a language, such as "pt"
+// or "pt_BR". It returns no translations if it has no catalog of the language.
+type Loader interface {
+	Load(language string) (map[string]string, error)
+}
+
+// LoaderFunc is a function which is a Loader
+type LoaderFunc func(language string) (map[string]string, error)
+
+// Load calls f(language)
+func (f LoaderFunc) Load(language string) (map[string]string, error) {
+	return f(language)
+}
+
+// DirLoader loads the catalogs of a directory, which has a JSON file per
+// language, such as "pt_BR.json", of the translations by message
+type DirLoader string
+
+// Load loads the catalog of the language in the directory, if any
+func (d DirLoader) Load(language string) (map[string]string, error) {
+	filename := filepath.Join(string(d), language+".json")
+	content, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	messages := map[string]string{}
+	if err := json.Unmarshal(content, &messages); err != nil {
+		return nil, errors.Wrapf(err, "invalid message catalog %s", filename)
+	}
+	return messages, nil
+}
+
+// SystemDir is the directory of the catalogs installed with the CLI, which is
+// set at build time by distributions which ship catalogs, such as
+// "/usr/share/docker/locales"
+var SystemDir = ""
+
+var (
+	mu             sync.RWMutex
+	loaders        []Loader
+	defaultCatalog *Catalog
+)
+
+// RegisterLoader registers a loader of catalogs, such as catalogs embedded in
+// the binary by a distribution. Its translations are overridden by the ones
+// of SystemDir, and of the loaders passed to Load.
+func RegisterLoader(loader Loader) {
+	mu.Lock()
+	defer mu.Unlock()
+	loaders = append(loaders, loader)
+}
+
+// Load returns the catalog of the language, which merges the catalogs of the
+// registered loaders, of SystemDir, and of the given loaders, in that order.
+// The catalogs of a language without its territory, such as "pt", are
+// overridden by the ones of the language, such as "pt_BR".
+func Load(language string, extraLoaders ...Loader) (*Catalog, error) {
+	if language == "" {
+		return nil, nil
+	}
+	mu.RLock()
+	all := append([]Loader{}, loaders...)
+	mu.RUnlock()
+	if SystemDir != "" {
+		all = append(all, DirLoader(SystemDir))
+	}
+	all = append(all, extraLoaders...)
+
+	candidates := []string{language}
+	if i := strings.Index(language, "_"); i > 0 {
+		candidates = []string{language[:i], language}
+	}
+	messages := map[string]string{}
+	for _, candidate := range candidates {
+		for _, loader := range all {
+			m, err := loader.Load(candidate)
+			if err != nil {
+				return nil, err
+			}
+			for msg, translation := range m {
+				messages[msg] = translation
+			}
+		}
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return NewCatalog(language, messages), nil
+}
+
+// Language returns the language of the messages, which is the configured
+// language if not empty, or else the language of the LC_ALL, LC_MESSAGES, or
+// LANG environment variables. The encoding and modifier of a locale are
+// stripped, such as "pt_BR" for "pt_BR.UTF-8". It returns an empty language if
+// the messages are not translated, such as for the "C" and "POSIX" locales.
+func Language(configured string) string {
+	locale := configured
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale = os.Getenv(env)
+	}
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.Replace(locale, "-", "_", -1)
+	switch locale {
+	case "C", "POSIX":
+		return ""
+	}
+	return locale
+}
+
+// SetCatalog sets the catalog of the messages translated by T and Sprintf
+func SetCatalog(c *Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultCatalog = c
+}
+
+// Default returns the catalog of the messages translated by T and Sprintf
+func Default() *Catalog {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultCatalog
+}
+
+// T returns the translation of the message by the catalog set by SetCatalog
+func T(msg string) string {
+	return Default().T(msg)
+}
+
+// Sprintf formats the translation of the format by the catalog set by
+// SetCatalog according to the arguments
+func Sprintf(format string, args ...interface{}) string {
+	return Default().Sprintf(format, args...)
+}
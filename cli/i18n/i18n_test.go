@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+	"gotest.tools/fs"
+)
+
+func TestLanguage(t *testing.T) {
+	testCases := []struct {
+		configured  string
+		lcAll       string
+		lcMessages  string
+		lang        string
+		expectedLng string
+	}{
+		{expectedLng: ""},
+		{lang: "fr_FR.UTF-8", expectedLng: "fr_FR"},
+		{lcMessages: "de_DE@euro", lang: "fr_FR.UTF-8", expectedLng: "de_DE"},
+		{lcAll: "es", lcMessages: "de_DE", lang: "fr_FR", expectedLng: "es"},
+		{configured: "pt-BR", lang: "fr_FR", expectedLng: "pt_BR"},
+		{lang: "C", expectedLng: ""},
+		{lang: "POSIX", expectedLng: ""},
+		{lang: "C.UTF-8", expectedLng: ""},
+	}
+	for _, tc := range testCases {
+		defer env.PatchAll(t, map[string]string{
+			"LC_ALL":      tc.lcAll,
+			"LC_MESSAGES": tc.lcMessages,
+			"LANG":        tc.lang,
+		})()
+		assert.Check(t, is.Equal(tc.expectedLng, Language(tc.configured)), "%+v", tc)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := fs.NewDir(t, "locales",
+		fs.WithFile("pt.json", `{"Options:": "Opções:", "Commands:": "Comandos:"}`),
+		fs.WithFile("pt_BR.json", `{"Commands:": "Comandos do Brasil:"}`),
+	)
+	defer dir.Remove()
+
+	catalog, err := Load("pt_BR", DirLoader(dir.Path()))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("pt_BR", catalog.Language()))
+	assert.Check(t, is.Equal("Opções:", catalog.T("Options:")))
+	assert.Check(t, is.Equal("Comandos do Brasil:", catalog.T("Commands:")))
+	assert.Check(t, is.Equal("Usage:", catalog.T("Usage:")))
+
+	catalog, err = Load("pt", DirLoader(dir.Path()))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("Comandos:", catalog.T("Commands:")))
+
+	catalog, err = Load("fr", DirLoader(dir.Path()))
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(catalog))
+	assert.Check(t, is.Equal("Commands:", catalog.T("Commands:")))
+}
+
+func TestLoadInvalidCatalog(t *testing.T) {
+	dir := fs.NewDir(t, "locales", fs.WithFile("fr.json", `not json`))
+	defer dir.Remove()
+
+	_, err := Load("fr", DirLoader(dir.Path()))
+	assert.ErrorContains(t, err, "invalid message catalog")
+}
+
+func TestRegisterLoader(t *testing.T) {
+	defer func(l []Loader) { loaders = l }(loaders)
+	RegisterLoader(LoaderFunc(func(language string) (map[string]string, error) {
+		if language != "fr" {
+			return nil, nil
+		}
+		return map[string]string{"Options:": "Options :", "Commands:": "Commandes :"}, nil
+	}))
+	dir := fs.NewDir(t, "locales", fs.WithFile("fr.json", `{"Commands:": "Les commandes :"}`))
+	defer dir.Remove()
+
+	catalog, err := Load("fr_CA", DirLoader(dir.Path()))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("Options :", catalog.T("Options:")))
+	assert.Check(t, is.Equal("Les commandes :", catalog.T("Commands:")))
+}
+
+func TestSprintf(t *testing.T) {
+	defer SetCatalog(Default())
+	SetCatalog(NewCatalog("fr", map[string]string{
+		"%d containers": "%d conteneurs",
+		"untranslated":  "",
+	}))
+	assert.Check(t, is.Equal("3 conteneurs", Sprintf("%d containers", 3)))
+	assert.Check(t, is.Equal("untranslated", T("untranslated")))
+
+	SetCatalog(nil)
+	assert.Check(t, is.Equal("3 containers", Sprintf("%d containers", 3)))
+}
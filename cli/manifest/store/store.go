@@ -22,8 +22,15 @@ type Store interface {
 	Get(listRef reference.Reference, manifest reference.Reference) (types.ImageManifest, error)
 	GetList(listRef reference.Reference) ([]types.ImageManifest, error)
 	Save(listRef reference.Reference, manifest reference.Reference, image types.ImageManifest) error
+	GetIndexOptions(listRef reference.Reference) (types.IndexOptions, error)
+	SaveIndexOptions(listRef reference.Reference, options types.IndexOptions) error
 }
 
+// indexOptionsFilename is the name of the file of the options of a manifest
+// list, in the directory of its manifests. Manifest filenames never start
+// with a dot.
+const indexOptionsFilename = ".index-options.json"
+
 // fsStore manages manifest files stored on the local filesystem
 type fsStore struct {
 	root string
@@ -122,6 +129,9 @@ func (s *fsStore) listManifests(transaction string) ([]string, error) {
 
 	filenames := []string{}
 	for _, info := range fileInfos {
+		if info.Name() == indexOptionsFilename {
+			continue
+		}
 		filenames = append(filenames, info.Name())
 	}
 	return filenames, nil
@@ -140,6 +150,33 @@ func (s *fsStore) Save(listRef reference.Reference, manifest reference.Reference
 	return ioutil.WriteFile(filename, bytes, 0644)
 }
 
+// GetIndexOptions returns the options of a local manifest list, which are
+// empty if they were never saved
+func (s *fsStore) GetIndexOptions(listRef reference.Reference) (types.IndexOptions, error) {
+	var options types.IndexOptions
+	bytes, err := ioutil.ReadFile(filepath.Join(s.root, makeFilesafeName(listRef.String()), indexOptionsFilename))
+	switch {
+	case os.IsNotExist(err):
+		return options, nil
+	case err != nil:
+		return options, err
+	}
+	err = json.Unmarshal(bytes, &options)
+	return options, err
+}
+
+// SaveIndexOptions saves the options of a local manifest list
+func (s *fsStore) SaveIndexOptions(listRef reference.Reference, options types.IndexOptions) error {
+	if err := s.createManifestListDirectory(listRef.String()); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.root, makeFilesafeName(listRef.String()), indexOptionsFilename), bytes, 0644)
+}
+
 func (s *fsStore) createManifestListDirectory(transaction string) error {
 	path := filepath.Join(s.root, makeFilesafeName(transaction))
 	return os.MkdirAll(path, 0755)
@@ -134,3 +134,26 @@ func TestStoreGetListDoesNotExist(t *testing.T) {
 	assert.Error(t, err, "No such manifest: list")
 	assert.Check(t, IsNotFound(err))
 }
+
+func TestStoreIndexOptions(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	listRef := ref("list")
+	options, err := store.GetIndexOptions(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(types.IndexOptions{}, options))
+
+	options = types.IndexOptions{OCI: true, ArtifactType: "application/vnd.example.sbom", Annotations: map[string]string{"a": "b"}}
+	assert.NilError(t, store.SaveIndexOptions(listRef, options))
+	assert.NilError(t, store.Save(listRef, ref("manifest"), types.ImageManifest{Ref: sref(t, "abcdef")}))
+
+	actual, err := store.GetIndexOptions(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(options, actual))
+
+	// The options are not a manifest of the list
+	manifests, err := store.GetList(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(manifests, 1))
+}
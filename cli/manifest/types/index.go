@@ -0,0 +1,88 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/docker/distribution"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// IndexOptions are the options of a local manifest list which are set when
+// it is created.
+type IndexOptions struct {
+	// OCI is whether the manifest list is pushed as an OCI image index,
+	// instead of a Docker manifest list.
+	OCI          bool                `json:",omitempty"`
+	ArtifactType string              `json:",omitempty"`
+	Annotations  map[string]string   `json:",omitempty"`
+	Subject      *ocispec.Descriptor `json:",omitempty"`
+}
+
+// OCIDescriptor is an OCI descriptor, with the artifact type of the
+// descriptors of the OCI image spec v1.1
+type OCIDescriptor struct {
+	ocispec.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// OCIIndex is an OCI image index, with the artifact type and subject of the
+// OCI image spec v1.1. It implements distribution.Manifest.
+type OCIIndex struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	MediaType     string              `json:"mediaType"`
+	ArtifactType  string              `json:"artifactType,omitempty"`
+	Manifests     []OCIDescriptor     `json:"manifests"`
+	Subject       *ocispec.Descriptor `json:"subject,omitempty"`
+	Annotations   map[string]string   `json:"annotations,omitempty"`
+}
+
+// NewOCIIndex returns an OCI image index of the given manifests.
+func NewOCIIndex(manifests []OCIDescriptor, options IndexOptions) *OCIIndex {
+	return &OCIIndex{
+		SchemaVersion: 2,
+		MediaType:     ocispec.MediaTypeImageIndex,
+		ArtifactType:  options.ArtifactType,
+		Manifests:     manifests,
+		Subject:       options.Subject,
+		Annotations:   options.Annotations,
+	}
+}
+
+// References returns the descriptors of the manifests of the index.
+func (i *OCIIndex) References() []distribution.Descriptor {
+	references := make([]distribution.Descriptor, 0, len(i.Manifests))
+	for _, m := range i.Manifests {
+		references = append(references, distribution.Descriptor{
+			MediaType:   m.MediaType,
+			Digest:      m.Digest,
+			Size:        m.Size,
+			Annotations: m.Annotations,
+			Platform:    m.Platform,
+		})
+	}
+	return references
+}
+
+// Payload returns the media type and the JSON of the index.
+func (i *OCIIndex) Payload() (string, []byte, error) {
+	payload, err := json.MarshalIndent(i, "", "   ")
+	return ocispec.MediaTypeImageIndex, payload, err
+}
+
+// Descriptor returns the descriptor of the index.
+func (i *OCIIndex) Descriptor() (OCIDescriptor, error) {
+	mediaType, payload, err := i.Payload()
+	if err != nil {
+		return OCIDescriptor{}, err
+	}
+	return OCIDescriptor{
+		Descriptor: ocispec.Descriptor{
+			MediaType:   mediaType,
+			Digest:      digest.FromBytes(payload),
+			Size:        int64(len(payload)),
+			Annotations: i.Annotations,
+		},
+		ArtifactType: i.ArtifactType,
+	}, nil
+}
@@ -20,6 +20,14 @@ type ImageManifest struct {
 	// SchemaV2Manifest is used for inspection
 	// TODO: Deprecate this and store manifest blobs
 	SchemaV2Manifest *schema2.DeserializedManifest `json:",omitempty"`
+
+	// OCIManifest is the content of OCI image manifests, whose media type
+	// is the media type of the descriptor
+	OCIManifest []byte `json:",omitempty"`
+
+	// ArtifactType is the artifact type of the manifest in an OCI image
+	// index
+	ArtifactType string `json:",omitempty"`
 }
 
 // OCIPlatform creates an OCI platform from a manifest list platform spec
@@ -53,7 +61,7 @@ func PlatformSpecFromOCI(p *ocispec.Platform) *manifestlist.PlatformSpec {
 // Blobs returns the digests for all the blobs referenced by this manifest
 func (i ImageManifest) Blobs() []digest.Digest {
 	digests := []digest.Digest{}
-	for _, descriptor := range i.SchemaV2Manifest.References() {
+	for _, descriptor := range i.References() {
 		digests = append(digests, descriptor.Digest)
 	}
 	return digests
@@ -65,6 +73,8 @@ func (i ImageManifest) Payload() (string, []byte, error) {
 	switch {
 	case i.SchemaV2Manifest != nil:
 		return i.SchemaV2Manifest.Payload()
+	case i.OCIManifest != nil:
+		return i.Descriptor.MediaType, i.OCIManifest, nil
 	default:
 		return "", nil, errors.Errorf("%s has no payload", i.Ref)
 	}
@@ -76,6 +86,16 @@ func (i ImageManifest) References() []distribution.Descriptor {
 	switch {
 	case i.SchemaV2Manifest != nil:
 		return i.SchemaV2Manifest.References()
+	case i.OCIManifest != nil:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(i.OCIManifest, &manifest); err != nil {
+			return nil
+		}
+		references := []distribution.Descriptor{{MediaType: manifest.Config.MediaType, Digest: manifest.Config.Digest, Size: manifest.Config.Size}}
+		for _, layer := range manifest.Layers {
+			references = append(references, distribution.Descriptor{MediaType: layer.MediaType, Digest: layer.Digest, Size: layer.Size, URLs: layer.URLs})
+		}
+		return references
 	default:
 		return nil
 	}
@@ -91,6 +111,16 @@ func NewImageManifest(ref reference.Named, desc ocispec.Descriptor, manifest *sc
 	}
 }
 
+// NewOCIImageManifest returns a new ImageManifest object of an OCI image
+// manifest, with its content.
+func NewOCIImageManifest(ref reference.Named, desc ocispec.Descriptor, content []byte) ImageManifest {
+	return ImageManifest{
+		Ref:         &SerializableNamed{Named: ref},
+		Descriptor:  desc,
+		OCIManifest: content,
+	}
+}
+
 // SerializableNamed is a reference.Named that can be serialized and deserialized
 // from JSON
 type SerializableNamed struct {
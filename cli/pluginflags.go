@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+// TolerateCLIPluginsGlobalFlags makes HandleGlobalFlags accept the global
+// options added by the CLI plugins, before the name of the plugin which adds
+// them. The plugins are only listed if the global options have an unknown
+// flag.
+func (tcmd *TopLevelCommand) TolerateCLIPluginsGlobalFlags() {
+	tcmd.tolerateCLIPluginsGlobalFlags = true
+}
+
+// pluginGlobalFlags returns the global options added by the CLI plugins, by
+// plugin name, if the args have global options which are unknown to flags.
+func (tcmd *TopLevelCommand) pluginGlobalFlags(flags *pflag.FlagSet) map[string]*pflag.FlagSet {
+	configDir, unknown := hasUnknownFlags(flags, tcmd.args)
+	if !unknown {
+		return nil
+	}
+	// The CLI is not initialized yet, as the global options are not parsed,
+	// so the plugins are listed with the configuration file of the --config
+	// option
+	configFile, err := cliconfig.Load(configDir)
+	if err != nil {
+		logrus.Debugf("failed to load the configuration file to list the global options of the CLI plugins: %v", err)
+		return nil
+	}
+	pluginFlags, err := pluginmanager.ListPluginGlobalFlags(uninitializedCli{Cli: tcmd.dockerCli, configFile: configFile}, tcmd.cmd)
+	if err != nil {
+		logrus.Debugf("failed to list the global options of the CLI plugins: %v", err)
+		return nil
+	}
+	return pluginFlags
+}
+
+// addPluginGlobalFlags adds the global options of the plugins to flags. If
+// several plugins add the same option, the plugin whose name sorts first
+// defines it.
+func addPluginGlobalFlags(flags *pflag.FlagSet, pluginFlags map[string]*pflag.FlagSet) {
+	var names []string
+	for name := range pluginFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flags.AddFlagSet(pluginFlags[name])
+	}
+}
+
+// checkPluginGlobalFlags returns an error if a global option added by a
+// plugin is set, but the command is not the plugin which adds it.
+func checkPluginGlobalFlags(flags *pflag.FlagSet, pluginFlags map[string]*pflag.FlagSet) error {
+	name := flags.Arg(0)
+	var err error
+	for _, pf := range pluginFlags {
+		// The flags are parsed by flags, so pf.Visit does not visit them
+		pf.VisitAll(func(f *pflag.Flag) {
+			if err != nil || !f.Changed {
+				return
+			}
+			if own, ok := pluginFlags[name]; ok && own.Lookup(f.Name) != nil {
+				return
+			}
+			err = errors.Errorf("unknown flag: --%s", f.Name)
+		})
+	}
+	return err
+}
+
+// hasUnknownFlags returns whether the global options of args have a flag
+// which is unknown to flags, and the value of the --config option. The args
+// are parsed with discarded values, so that the values of flags are not set.
+func hasUnknownFlags(flags *pflag.FlagSet, args []string) (configDir string, unknown bool) {
+	configDir = cliconfig.Dir()
+	dry := pflag.NewFlagSet("", pflag.ContinueOnError)
+	dry.SetInterspersed(false)
+	dry.SetOutput(ioutil.Discard)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "config" {
+			dry.StringVar(&configDir, f.Name, configDir, "")
+			return
+		}
+		dry.AddFlag(&pflag.Flag{Name: f.Name, Shorthand: f.Shorthand, NoOptDefVal: f.NoOptDefVal, Value: discardValue{}})
+	})
+	err := dry.Parse(args)
+	return configDir, err != nil && strings.HasPrefix(err.Error(), "unknown flag: ")
+}
+
+// discardValue is the value of a flag which discards its values
+type discardValue struct{}
+
+func (discardValue) Set(string) error { return nil }
+
+func (discardValue) String() string { return "" }
+
+func (discardValue) Type() string { return "string" }
+
+// uninitializedCli is a CLI which is not initialized yet, with the
+// configuration file loaded before its initialization
+type uninitializedCli struct {
+	command.Cli
+	configFile *configfile.ConfigFile
+}
+
+func (c uninitializedCli) ConfigFile() *configfile.ConfigFile {
+	return c.configFile
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newTestGlobalFlags() (*pflag.FlagSet, *[]string) {
+	flags := pflag.NewFlagSet("docker", pflag.ContinueOnError)
+	flags.String("config", "/default", "")
+	flags.BoolP("debug", "D", false, "")
+	hosts := &[]string{}
+	flags.StringSliceVarP(hosts, "host", "H", nil, "")
+	return flags, hosts
+}
+
+func TestHasUnknownFlags(t *testing.T) {
+	testCases := []struct {
+		args              []string
+		expectedConfigDir string
+		expectedUnknown   bool
+	}{
+		{args: []string{"ps"}},
+		{args: []string{"-D", "-H", "tcp://host", "ps", "--unknown"}},
+		{args: []string{"--config", "/config", "--greeting", "Howdy", "helloworld"}, expectedConfigDir: "/config", expectedUnknown: true},
+		{args: []string{"--greeting=Howdy", "--config=/config", "helloworld"}, expectedUnknown: true},
+		{args: []string{"-x", "helloworld"}},
+	}
+	for _, tc := range testCases {
+		flags, hosts := newTestGlobalFlags()
+		configDir, unknown := hasUnknownFlags(flags, tc.args)
+		assert.Check(t, is.Equal(unknown, tc.expectedUnknown), tc.args)
+		if tc.expectedConfigDir != "" {
+			assert.Check(t, is.Equal(configDir, tc.expectedConfigDir), tc.args)
+		}
+		// The values of the flags are not set
+		assert.Check(t, is.Len(*hosts, 0), tc.args)
+	}
+}
+
+func TestCheckPluginGlobalFlags(t *testing.T) {
+	newPluginFlags := func() map[string]*pflag.FlagSet {
+		hello := pflag.NewFlagSet("helloworld", pflag.ContinueOnError)
+		hello.String("greeting", "", "")
+		other := pflag.NewFlagSet("other", pflag.ContinueOnError)
+		other.Bool("verbose", false, "")
+		other.String("greeting", "", "")
+		return map[string]*pflag.FlagSet{"helloworld": hello, "other": other}
+	}
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"-D", "helloworld"}},
+		{args: []string{"--greeting", "Howdy", "helloworld"}},
+		{args: []string{"--greeting", "Howdy", "other"}},
+		{args: []string{"--verbose", "other"}},
+		{args: []string{"--verbose", "helloworld"}, expectedError: "unknown flag: --verbose"},
+		{args: []string{"--greeting=Howdy", "ps"}, expectedError: "unknown flag: --greeting"},
+	}
+	for _, tc := range testCases {
+		flags, _ := newTestGlobalFlags()
+		flags.SetInterspersed(false)
+		pluginFlags := newPluginFlags()
+		addPluginGlobalFlags(flags, pluginFlags)
+		assert.NilError(t, flags.Parse(tc.args))
+		err := checkPluginGlobalFlags(flags, pluginFlags)
+		if tc.expectedError == "" {
+			assert.Check(t, err, tc.args)
+		} else {
+			assert.Check(t, is.Error(err, tc.expectedError), tc.args)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package redact removes the secrets, such as the passwords and the tokens,
+// from the data written by the CLI outside of the configuration directory,
+// such as the audit log, or the support bundles attached to bug reports.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the redacted values
+const Redacted = "<redacted>"
+
+// sensitiveFlagSuffixes are the suffixes of the names of the flags whose
+// values are redacted, such as the --password flag of "docker login", or the
+// --token flag of "docker swarm join"
+var sensitiveFlagSuffixes = []string{"-password", "-token"}
+
+// sensitiveShorthands are the shorthands of the flags whose values are
+// redacted, by command, as a shorthand is a different flag of the other
+// commands, such as -p, which is the --publish flag of "docker run"
+var sensitiveShorthands = map[string][]string{
+	"login": {"-p"},
+}
+
+// envFlags are the flags whose values are environment variables, which are
+// redacted as Env does
+var envFlags = []string{"--env", "-e"}
+
+// sensitiveKeys are the keys whose values are redacted, such as the "auth"
+// key of the registries of the configuration file
+var sensitiveKeys = map[string]bool{
+	"auth":          true,
+	"authorization": true,
+	"identitytoken": true,
+	"registrytoken": true,
+}
+
+// sensitiveKeyWords are the words which the keys whose values are redacted
+// contain
+var sensitiveKeyWords = []string{"password", "passwd", "secret", "token", "credential", "apikey", "api_key", "privatekey", "private_key"}
+
+// IsSensitiveKey returns whether the value of a key, such as the key of a
+// JSON object, or the name of an environment variable, is a secret. The keys
+// are matched case-insensitively.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, word := range sensitiveKeyWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Args returns the arguments of a command line, in which the values of the
+// sensitive flags, such as "--password", are replaced with Redacted, and the
+// values of the --env flags are redacted as Env does. The first argument
+// which is not a flag is the command, such as "login".
+func Args(args []string) []string {
+	redactedArgs := make([]string, len(args))
+	var command string
+	var redactNext func(string) string
+	for i, arg := range args {
+		switch {
+		case redactNext != nil:
+			redactedArgs[i] = redactNext(arg)
+			redactNext = nil
+		case arg == "--":
+			copy(redactedArgs[i:], args[i:])
+			return redactedArgs
+		case len(arg) < 2 || arg[0] != '-':
+			redactedArgs[i] = arg
+			if command == "" {
+				command = arg
+			}
+		default:
+			redactedArgs[i], redactNext = redactFlag(command, arg)
+		}
+	}
+	return redactedArgs
+}
+
+// redactFlag returns the argument of a flag of the command, with its value
+// redacted if it is in the argument, such as "--password=secret", or the
+// redaction of the next argument if its value is the next argument
+func redactFlag(command, arg string) (string, func(string) string) {
+	if strings.HasPrefix(arg, "--") {
+		parts := strings.SplitN(arg, "=", 2)
+		redact := flagRedaction(command, parts[0])
+		switch {
+		case redact == nil:
+			return arg, nil
+		case len(parts) == 2:
+			return parts[0] + "=" + redact(parts[1]), nil
+		default:
+			return arg, redact
+		}
+	}
+	// The value of a shorthand follows it, such as in "-psecret" or
+	// "-p=secret", or is the next argument, including when the shorthand ends
+	// a group of shorthands, such as in "-ite"
+	if redact := flagRedaction(command, arg[:2]); redact != nil {
+		if len(arg) == 2 {
+			return arg, redact
+		}
+		value := strings.TrimPrefix(arg[2:], "=")
+		return arg[:len(arg)-len(value)] + redact(value), nil
+	}
+	return arg, flagRedaction(command, "-"+arg[len(arg)-1:])
+}
+
+// flagRedaction returns the redaction of the values of a flag of the command,
+// or nil if they are not redacted
+func flagRedaction(command, flag string) func(string) string {
+	for _, f := range envFlags {
+		if flag == f {
+			return func(value string) string { return Env([]string{value})[0] }
+		}
+	}
+	redacted := func(string) string { return Redacted }
+	if strings.HasPrefix(flag, "--") {
+		for _, suffix := range sensitiveFlagSuffixes {
+			if strings.HasSuffix(flag, suffix) {
+				return redacted
+			}
+		}
+	}
+	for _, f := range sensitiveShorthands[command] {
+		if flag == f {
+			return redacted
+		}
+	}
+	return nil
+}
+
+// Env returns the environment variables, as KEY=VALUE, in which the values of
+// the sensitive variables, such as "DB_PASSWORD", are replaced with Redacted,
+// and the passwords of the URLs are removed
+func Env(env []string) []string {
+	redacted := make([]string, len(env))
+	for i, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		switch {
+		case len(parts) != 2:
+			redacted[i] = kv
+		case IsSensitiveKey(parts[0]):
+			redacted[i] = parts[0] + "=" + Redacted
+		default:
+			redacted[i] = parts[0] + "=" + URL(parts[1])
+		}
+	}
+	return redacted
+}
+
+// URL returns the string with its password replaced with Redacted, if it is
+// a URL with a password, such as the URL of an HTTP proxy, and the string
+// otherwise
+func URL(s string) string {
+	if !strings.Contains(s, "://") || !strings.Contains(s, "@") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), "REDACTED")
+	return strings.Replace(u.String(), ":REDACTED@", ":"+Redacted+"@", 1)
+}
+
+// Value redacts a value decoded from JSON: the values of the sensitive keys of
+// its objects, such as "password", are replaced with Redacted, the
+// environment variables of the "Env" arrays are redacted, and the passwords
+// of the URLs are removed.
+func Value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			switch {
+			case IsSensitiveKey(key):
+				if value != nil && value != "" {
+					v[key] = Redacted
+				}
+			case strings.EqualFold(key, "env"):
+				v[key] = redactEnvValue(value)
+			default:
+				v[key] = Value(value)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = Value(value)
+		}
+		return v
+	case string:
+		return URL(v)
+	}
+	return v
+}
+
+func redactEnvValue(value interface{}) interface{} {
+	values, ok := value.([]interface{})
+	if !ok {
+		return Value(value)
+	}
+	for i, kv := range values {
+		if s, ok := kv.(string); ok {
+			values[i] = Env([]string{s})[0]
+		}
+	}
+	return values
+}
+
+// JSON redacts a JSON document, as Value does, and returns it indented
+func JSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(Value(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalIndent encodes a value as JSON, and redacts it as JSON does
+func MarshalIndent(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return JSON(data)
+}
@@ -26,6 +26,11 @@ type RegistryClient interface {
 	MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
 	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
 	GetTags(ctx context.Context, ref reference.Named) ([]string, error)
+	GetManifestContent(ctx context.Context, ref reference.Named) (ManifestContent, error)
+	GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error)
+	PutManifestContent(ctx context.Context, ref reference.Named, content ManifestContent) (bool, error)
+	DeleteManifest(ctx context.Context, ref reference.Named) error
+	SearchRepositories(ctx context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (SearchPage, error)
 }
 
 // NewRegistryClient returns a new RegistryClient with a resolver
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// maxManifestSize is the maximum size of the manifests fetched by
+// GetManifestContent
+const maxManifestSize = 4 * 1024 * 1024
+
+// maxBlobSize is the maximum size of the blobs fetched by GetBlob, such as the
+// configuration of an image, or the payload of a signature
+const maxBlobSize = 4 * 1024 * 1024
+
+// ManifestContent is the raw content of a manifest, whatever its media type.
+type ManifestContent struct {
+	MediaType string
+	Digest    digest.Digest
+	Payload   []byte
+}
+
+// manifestMediaTypes are the media types of the manifests accepted by
+// GetManifestContent
+var manifestMediaTypes = []string{
+	ocispec.MediaTypeImageManifest,
+	ocispec.MediaTypeImageIndex,
+	schema2.MediaTypeManifest,
+	manifestlist.MediaTypeManifestList,
+}
+
+// GetManifestContent returns the raw content of the manifest of the reference.
+// Unlike GetManifest, manifests of any media type are returned, such as the
+// OCI manifests of signatures. The digest of the content is verified if the
+// reference is canonical.
+func (c *client) GetManifestContent(ctx context.Context, ref reference.Named) (ManifestContent, error) {
+	tagOrDigest, err := tagOrDigest(ref)
+	if err != nil {
+		return ManifestContent{}, err
+	}
+
+	resp, err := c.getContent(ctx, ref, "manifests/"+tagOrDigest, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return ManifestContent{}, err
+	}
+	defer resp.Body.Close()
+	payload, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return ManifestContent{}, errors.Wrapf(err, "failed to read the manifest of %s", ref)
+	}
+	if len(payload) > maxManifestSize {
+		return ManifestContent{}, errors.Errorf("the manifest of %s is larger than %d bytes", ref, maxManifestSize)
+	}
+
+	content := ManifestContent{
+		MediaType: resp.Header.Get("Content-Type"),
+		Digest:    digest.FromBytes(payload),
+		Payload:   payload,
+	}
+	if canonical, ok := ref.(reference.Canonical); ok && canonical.Digest() != content.Digest {
+		return ManifestContent{}, errors.Errorf("the manifest of %s has digest %s", ref, content.Digest)
+	}
+	return content, nil
+}
+
+// GetBlob returns the content of the blob of the repository of the reference
+// with the given digest. The digest of the content is verified, and blobs
+// larger than maxBlobSize are rejected.
+func (c *client) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid blob digest %q", dgst)
+	}
+	resp, err := c.getContent(ctx, ref, "blobs/"+dgst.String(), "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	verifier := dgst.Verifier()
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBlobSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read blob %s", dgst)
+	}
+	if len(b) > maxBlobSize {
+		return nil, errors.Errorf("blob %s is larger than %d bytes", dgst, maxBlobSize)
+	}
+	verifier.Write(b)
+	if !verifier.Verified() {
+		return nil, errors.Errorf("blob %s does not match its digest", dgst)
+	}
+	return b, nil
+}
+
+// PutManifestContent pushes the raw content of a manifest, whatever its media
+// type, with the tag or digest of the reference. It returns whether the
+// registry supports the referrers API, if the manifest has a subject.
+func (c *client) PutManifestContent(ctx context.Context, ref reference.Named, content ManifestContent) (bool, error) {
+	tagOrDigest := content.Digest.String()
+	if tagged, ok := ref.(reference.NamedTagged); ok {
+		tagOrDigest = tagged.Tag()
+	}
+	req, err := c.newRequest(ctx, ref, http.MethodPut, "manifests/"+tagOrDigest, bytes.NewReader(content.Payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", content.MediaType)
+	resp, err := req.client.Do(req.Request)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to put manifest %s", ref)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return false, errors.Errorf("failed to put manifest %s: %s: %s", ref, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if dgst := resp.Header.Get("Docker-Content-Digest"); dgst != "" && dgst != content.Digest.String() {
+		return false, errors.Errorf("failed to put manifest %s: the registry computed digest %s instead of %s", ref, dgst, content.Digest)
+	}
+	return resp.Header.Get("OCI-Subject") != "", nil
+}
+
+// DeleteManifest deletes the tag of the reference, or the manifest with its
+// digest, and all its tags, if the reference is canonical. Registries may not
+// allow deleting tags, or manifests.
+func (c *client) DeleteManifest(ctx context.Context, ref reference.Named) error {
+	tagOrDigest, err := tagOrDigest(ref)
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, ref, http.MethodDelete, "manifests/"+tagOrDigest, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := req.client.Do(req.Request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete %s", ref)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return newNotFoundError(ref.String())
+	case http.StatusMethodNotAllowed:
+		return errors.Errorf("failed to delete %s: the registry does not allow deletes", ref)
+	default:
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return errors.Errorf("failed to delete %s: %s: %s", ref, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// tagOrDigest returns the digest of canonical references, and the tag of
+// tagged references.
+func tagOrDigest(ref reference.Named) (string, error) {
+	switch r := ref.(type) {
+	case reference.Canonical:
+		return r.Digest().String(), nil
+	case reference.NamedTagged:
+		return r.Tag(), nil
+	default:
+		return "", errors.Errorf("%s no tag or digest", ref)
+	}
+}
+
+type request struct {
+	*http.Request
+	client *http.Client
+}
+
+func (c *client) newRequest(ctx context.Context, ref reference.Named, method, path string, body io.Reader) (request, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return request{}, err
+	}
+	httpTransport, err := c.getHTTPTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return request{}, err
+	}
+
+	url := fmt.Sprintf("%s/v2/%s/%s", strings.TrimSuffix(repoEndpoint.BaseURL(), "/"), repoEndpoint.Name(), path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return request{}, err
+	}
+	return request{Request: req.WithContext(ctx), client: &http.Client{Transport: httpTransport}}, nil
+}
+
+func (c *client) getContent(ctx context.Context, ref reference.Named, path, accept string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, ref, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := req.client.Do(req.Request)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, newNotFoundError(ref.Name() + "/" + path)
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return nil, errors.Errorf("failed to fetch %s of %s: %s", path, ref.Name(), resp.Status)
+	}
+	return resp, nil
+}
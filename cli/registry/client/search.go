@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/docker/distribution/registry/client/transport"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/registry"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/pkg/errors"
+)
+
+// MaxSearchPageSize is the maximum number of results of a page of the
+// results of a search
+const MaxSearchPageSize = 100
+
+// SearchPage is a page of the results of a search of the repositories of a
+// registry
+type SearchPage struct {
+	registrytypes.SearchResults
+	// Page is the number of the page, starting at 1
+	Page int `json:"page"`
+	// NumPages is the number of pages of the results
+	NumPages int `json:"num_pages"`
+}
+
+// SearchRepositories returns a page of the repositories of the index which
+// match the term, with the search API of the index, as the daemon does.
+// Unlike the search of the daemon, which is limited to the first 100 results,
+// any page of the results can be returned.
+func (c *client) SearchRepositories(ctx context.Context, index *registrytypes.IndexInfo, term string, page, pageSize int) (SearchPage, error) {
+	if pageSize < 1 || pageSize > MaxSearchPageSize {
+		return SearchPage{}, errors.Errorf("page size %d is outside the range of [1, %d]", pageSize, MaxSearchPageSize)
+	}
+	endpoint, err := registry.NewV1Endpoint(index, c.userAgent, nil)
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	tlsConfig := tlsconfig.ServerDefault()
+	if !endpoint.IsSecure || c.insecureRegistry {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true} // nolint: gosec
+	}
+	authConfig := c.authConfigResolver(ctx, index)
+	httpClient := &http.Client{
+		Transport: transport.NewTransport(
+			registry.AuthTransport(registry.NewTransport(tlsConfig), &authConfig, false),
+			registry.Headers(c.userAgent, http.Header{})...,
+		),
+	}
+
+	query := url.Values{}
+	query.Set("q", term)
+	query.Set("n", fmt.Sprintf("%d", pageSize))
+	query.Set("page", fmt.Sprintf("%d", page))
+	req, err := http.NewRequest(http.MethodGet, endpoint.String()+"search?"+query.Encode(), nil)
+	if err != nil {
+		return SearchPage{}, err
+	}
+	// Have the AuthTransport send the credentials, when logged in
+	req.Header.Set("X-Docker-Token", "true")
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return SearchPage{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return SearchPage{}, errors.Errorf("failed to search %s: %s", index.Name, resp.Status)
+	}
+	var result SearchPage
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return SearchPage{}, errors.Wrapf(err, "failed to decode the search results of %s", index.Name)
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"strings"
 
+	"github.com/docker/cli/cli/i18n"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -18,7 +19,7 @@ func NoArgs(cmd *cobra.Command, args []string) error {
 	}
 
 	return errors.Errorf(
-		"%q accepts no arguments.\nSee '%s --help'.\n\nUsage:  %s\n\n%s",
+		i18n.T("%q accepts no arguments.\nSee '%s --help'.\n\nUsage:  %s\n\n%s"),
 		cmd.CommandPath(),
 		cmd.CommandPath(),
 		cmd.UseLine(),
@@ -33,10 +34,10 @@ func RequiresMinArgs(min int) cobra.PositionalArgs {
 			return nil
 		}
 		return errors.Errorf(
-			"%q requires at least %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s",
+			i18n.T("%q requires at least %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s"),
 			cmd.CommandPath(),
 			min,
-			pluralize("argument", min),
+			pluralizeArguments(min),
 			cmd.CommandPath(),
 			cmd.UseLine(),
 			cmd.Short,
@@ -51,10 +52,10 @@ func RequiresMaxArgs(max int) cobra.PositionalArgs {
 			return nil
 		}
 		return errors.Errorf(
-			"%q requires at most %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s",
+			i18n.T("%q requires at most %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s"),
 			cmd.CommandPath(),
 			max,
-			pluralize("argument", max),
+			pluralizeArguments(max),
 			cmd.CommandPath(),
 			cmd.UseLine(),
 			cmd.Short,
@@ -69,11 +70,11 @@ func RequiresRangeArgs(min int, max int) cobra.PositionalArgs {
 			return nil
 		}
 		return errors.Errorf(
-			"%q requires at least %d and at most %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s",
+			i18n.T("%q requires at least %d and at most %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s"),
 			cmd.CommandPath(),
 			min,
 			max,
-			pluralize("argument", max),
+			pluralizeArguments(max),
 			cmd.CommandPath(),
 			cmd.UseLine(),
 			cmd.Short,
@@ -88,10 +89,10 @@ func ExactArgs(number int) cobra.PositionalArgs {
 			return nil
 		}
 		return errors.Errorf(
-			"%q requires exactly %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s",
+			i18n.T("%q requires exactly %d %s.\nSee '%s --help'.\n\nUsage:  %s\n\n%s"),
 			cmd.CommandPath(),
 			number,
-			pluralize("argument", number),
+			pluralizeArguments(number),
 			cmd.CommandPath(),
 			cmd.UseLine(),
 			cmd.Short,
@@ -99,9 +100,9 @@ func ExactArgs(number int) cobra.PositionalArgs {
 	}
 }
 
-func pluralize(word string, number int) string {
+func pluralizeArguments(number int) string {
 	if number == 1 {
-		return word
+		return i18n.T("argument")
 	}
-	return word + "s"
+	return i18n.T("arguments")
 }
@@ -0,0 +1,148 @@
+// Package secretprovider fetches the content of the secrets and configs of a
+// swarm from external secret managers, such as HashiCorp Vault.
+//
+// The secret managers are accessed by helper programs, analogous to the
+// credential helpers, named "docker-secret-<provider>", such as
+// "docker-secret-vault". A helper is invoked with the "get" argument, reads the
+// reference of the secret, such as its path in the secret manager, on its
+// standard input, and writes the content of the secret on its standard
+// output. On failure, it exits with a non-zero status, and writes the error on
+// its standard output. The content of the secrets is only kept in memory, and
+// never written to disk.
+package secretprovider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// helperPrefix is the prefix of the names of the helper programs
+const helperPrefix = "docker-secret-"
+
+// Well-known providers, which have their own flags
+const (
+	Vault             = "vault"
+	AWSSecretsManager = "aws-sm"
+)
+
+// Provider fetches the content of the secrets of an external secret manager
+type Provider interface {
+	// Get returns the content of the secret with the given reference, such as
+	// its path in the secret manager.
+	Get(ref string) ([]byte, error)
+}
+
+// helperProvider is a provider using the helper program of a secret manager
+type helperProvider struct {
+	name        string
+	programFunc client.ProgramFunc
+}
+
+// NewHelperProvider returns the provider using the helper program of the
+// given provider, such as "docker-secret-vault" for "vault"
+func NewHelperProvider(name string) Provider {
+	return &helperProvider{name: name, programFunc: client.NewShellProgramFunc(helperPrefix + name)}
+}
+
+func (p *helperProvider) Get(ref string) ([]byte, error) {
+	cmd := p.programFunc("get")
+	cmd.Input(strings.NewReader(ref))
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, errors.Errorf("error getting %s from the %s secret provider: %s", ref, p.name, msg)
+	}
+	return out, nil
+}
+
+// Source is the secret of an external secret manager providing the content of
+// a secret or config
+type Source struct {
+	// Provider is the name of the provider of the secret, such as "vault".
+	Provider string
+	// Reference is the reference of the secret in the secret manager, such
+	// as its path.
+	Reference string
+}
+
+// IsSet returns whether the source is set
+func (s Source) IsSet() bool {
+	return s.Provider != ""
+}
+
+// Get returns the content of the secret of the source, using the helper
+// program of its provider
+func (s Source) Get() ([]byte, error) {
+	return NewHelperProvider(s.Provider).Get(s.Reference)
+}
+
+// ParseSource parses the source of a secret, as PROVIDER:REFERENCE, such as
+// "vault:secret/data/db"
+func ParseSource(value string) (Source, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Source{}, errors.Errorf("invalid secret source %q: must be PROVIDER:REFERENCE", value)
+	}
+	if strings.ContainsAny(parts[0], `/\`) {
+		return Source{}, errors.Errorf("invalid secret provider %q", parts[0])
+	}
+	return Source{Provider: parts[0], Reference: parts[1]}, nil
+}
+
+// AddFlags adds the flags setting the source of the content of a secret or
+// config, such as --from-vault, to the flags of a command. The object is the
+// kind of object created by the command, such as "secret".
+func AddFlags(flags *pflag.FlagSet, source *Source, object string) {
+	flags.Var(&sourceValue{source: source, provider: Vault}, "from-vault", fmt.Sprintf("Read the content of the %s from a path of HashiCorp Vault", object))
+	flags.Var(&sourceValue{source: source, provider: AWSSecretsManager}, "from-aws-sm", fmt.Sprintf("Read the content of the %s from a secret of AWS Secrets Manager", object))
+	flags.Var(&sourceValue{source: source}, "from-provider", fmt.Sprintf("Read the content of the %s from a secret provider, as PROVIDER:REFERENCE", object))
+}
+
+// sourceValue is the value of a flag setting the source of a secret. The
+// provider of the flags of the well-known providers is set.
+type sourceValue struct {
+	source   *Source
+	provider string
+}
+
+func (v *sourceValue) Set(value string) error {
+	if v.source.IsSet() {
+		return errors.New("only one of --from-vault, --from-aws-sm, and --from-provider can be set")
+	}
+	if v.provider == "" {
+		source, err := ParseSource(value)
+		if err != nil {
+			return err
+		}
+		*v.source = source
+		return nil
+	}
+	if value == "" {
+		return errors.New("the reference of the secret must not be empty")
+	}
+	*v.source = Source{Provider: v.provider, Reference: value}
+	return nil
+}
+
+// String returns an empty string, as the flags have no default value, and
+// share the source they set
+func (v *sourceValue) String() string {
+	return ""
+}
+
+func (v *sourceValue) Type() string {
+	if v.provider == Vault {
+		return "path"
+	}
+	if v.provider == AWSSecretsManager {
+		return "name"
+	}
+	return "source"
+}
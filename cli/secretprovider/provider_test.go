@@ -0,0 +1,114 @@
+package secretprovider
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// mockCommand simulates the helper program of a secret provider
+type mockCommand struct {
+	arg   string
+	input io.Reader
+}
+
+func (m *mockCommand) Output() ([]byte, error) {
+	in, err := ioutil.ReadAll(m.input)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case m.arg != "get":
+		return []byte("unknown command"), errors.New("exit status 1")
+	case string(in) == "secret/data/db":
+		return []byte("s3cr3t"), nil
+	case string(in) == "secret/data/silent":
+		return nil, errors.New("exit status 2")
+	}
+	return []byte("secret not found\n"), errors.New("exit status 1")
+}
+
+func (m *mockCommand) Input(in io.Reader) {
+	m.input = in
+}
+
+func mockProgramFunc(args ...string) client.Program {
+	return &mockCommand{arg: args[0]}
+}
+
+func TestHelperProviderGet(t *testing.T) {
+	p := &helperProvider{name: "vault", programFunc: mockProgramFunc}
+
+	data, err := p.Get("secret/data/db")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "s3cr3t"))
+
+	_, err = p.Get("secret/data/missing")
+	assert.Check(t, is.Error(err, "error getting secret/data/missing from the vault secret provider: secret not found"))
+
+	_, err = p.Get("secret/data/silent")
+	assert.Check(t, is.Error(err, "error getting secret/data/silent from the vault secret provider: exit status 2"))
+}
+
+func TestParseSource(t *testing.T) {
+	source, err := ParseSource("gcp-sm:projects/p/secrets/db:latest")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(source, Source{Provider: "gcp-sm", Reference: "projects/p/secrets/db:latest"}))
+
+	for _, value := range []string{"", "vault", "vault:", ":path", "../bin/sh:path"} {
+		_, err := ParseSource(value)
+		assert.Check(t, is.ErrorContains(err, "invalid secret"), value)
+	}
+}
+
+func TestAddFlags(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expected      Source
+		expectedError string
+	}{
+		{
+			args:     []string{"--from-vault", "secret/data/db"},
+			expected: Source{Provider: "vault", Reference: "secret/data/db"},
+		},
+		{
+			args:     []string{"--from-aws-sm", "prod/db"},
+			expected: Source{Provider: "aws-sm", Reference: "prod/db"},
+		},
+		{
+			args:     []string{"--from-provider", "1password:op://vault/db/password"},
+			expected: Source{Provider: "1password", Reference: "op://vault/db/password"},
+		},
+		{
+			args: []string{},
+		},
+		{
+			args:          []string{"--from-vault", "secret/data/db", "--from-aws-sm", "prod/db"},
+			expectedError: "only one of --from-vault, --from-aws-sm, and --from-provider can be set",
+		},
+		{
+			args:          []string{"--from-vault="},
+			expectedError: "the reference of the secret must not be empty",
+		},
+	}
+	for _, tc := range testCases {
+		var source Source
+		flags := pflag.NewFlagSet("create", pflag.ContinueOnError)
+		flags.SetOutput(ioutil.Discard)
+		AddFlags(flags, &source, "secret")
+		err := flags.Parse(tc.args)
+		if tc.expectedError != "" {
+			assert.Check(t, is.ErrorContains(err, tc.expectedError))
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(source, tc.expected))
+		assert.Check(t, is.Equal(source.IsSet(), tc.expected.Provider != ""))
+	}
+}
@@ -0,0 +1,152 @@
+package streams
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/pkg/term"
+	"github.com/pkg/errors"
+)
+
+// ColorMode is the mode of the --color flag
+type ColorMode string
+
+// Modes of the --color flag
+const (
+	// ColorAuto colors the output written to a terminal, unless the NO_COLOR
+	// environment variable is set, or the terminal is "dumb"
+	ColorAuto ColorMode = "auto"
+	// ColorAlways colors the output, even if it is not written to a terminal
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors the output
+	ColorNever ColorMode = "never"
+)
+
+// Roles of colored output, whose colors are set by the theme
+const (
+	ColorError   = "error"
+	ColorWarning = "warning"
+	ColorSuccess = "success"
+	ColorAdded   = "added"
+	ColorRemoved = "removed"
+	ColorChanged = "changed"
+	ColorHeader  = "header"
+)
+
+// ParseColorMode parses the value of the --color flag. An empty value is
+// ColorAuto.
+func ParseColorMode(value string) (ColorMode, error) {
+	switch mode := ColorMode(value); mode {
+	case "":
+		return ColorAuto, nil
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	default:
+		return "", errors.Errorf("invalid --color value %q: must be one of \"auto\", \"always\", or \"never\"", value)
+	}
+}
+
+// Theme is the SGR (Select Graphic Rendition) parameters of the color of each
+// role, such as "1;31" for bold red. A role without parameters is not colored.
+type Theme map[string]string
+
+// DefaultTheme is the theme of the roles which are not set in the theme of the
+// configuration file
+var DefaultTheme = Theme{
+	ColorError:   "31",
+	ColorWarning: "33",
+	ColorSuccess: "32",
+	ColorAdded:   "32",
+	ColorRemoved: "31",
+	ColorChanged: "33",
+	ColorHeader:  "1",
+}
+
+var colorAttributes = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+}
+
+// ParseTheme returns the default theme, overridden by the colors of the
+// theme of the configuration file. A color is a space-separated list of
+// attributes, such as "bold red", or "bright-blue"; "none" disables the color
+// of a role.
+func ParseTheme(colors map[string]string) (Theme, error) {
+	theme := Theme{}
+	for role, params := range DefaultTheme {
+		theme[role] = params
+	}
+	for role, color := range colors {
+		var params []string
+		for _, attr := range strings.Fields(strings.ToLower(color)) {
+			if attr == "none" {
+				continue
+			}
+			bright := strings.HasPrefix(attr, "bright-")
+			p, ok := colorAttributes[strings.TrimPrefix(attr, "bright-")]
+			if !ok || (bright && len(p) != 2) {
+				return nil, errors.Errorf("invalid color %q of %q in the theme of the configuration file", color, role)
+			}
+			if bright {
+				p = "9" + p[1:]
+			}
+			params = append(params, p)
+		}
+		theme[role] = strings.Join(params, ";")
+	}
+	return theme, nil
+}
+
+// ColorPolicy decides whether the output written to a stream is colored, and
+// with which colors. The zero value is the ColorAuto mode, with the default
+// theme.
+type ColorPolicy struct {
+	Mode  ColorMode
+	Theme Theme
+}
+
+// Enabled returns whether the output written to w is colored
+func (p ColorPolicy) Enabled(w io.Writer) bool {
+	switch p.Mode {
+	case ColorNever:
+		return false
+	case ColorAlways:
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if s, ok := w.(interface{ IsTerminal() bool }); ok {
+		return s.IsTerminal()
+	}
+	_, isTerminal := term.GetFdInfo(w)
+	return isTerminal
+}
+
+// Colorize returns s with the color of the role, if the output written to w is
+// colored
+func (p ColorPolicy) Colorize(w io.Writer, role, s string) string {
+	if s == "" || !p.Enabled(w) {
+		return s
+	}
+	theme := p.Theme
+	if theme == nil {
+		theme = DefaultTheme
+	}
+	params := theme[role]
+	if params == "" {
+		return s
+	}
+	return "\x1b[" + params + "m" + s + "\x1b[0m"
+}
@@ -0,0 +1,53 @@
+package streams
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+)
+
+func TestParseColorMode(t *testing.T) {
+	for value, expected := range map[string]ColorMode{"": ColorAuto, "auto": ColorAuto, "always": ColorAlways, "never": ColorNever} {
+		mode, err := ParseColorMode(value)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(expected, mode))
+	}
+	_, err := ParseColorMode("yes")
+	assert.Check(t, is.Error(err, `invalid --color value "yes": must be one of "auto", "always", or "never"`))
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme(map[string]string{
+		ColorError:   "bold bright-red",
+		ColorWarning: "none",
+		"custom":     "Underline cyan",
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("1;91", theme[ColorError]))
+	assert.Check(t, is.Equal("", theme[ColorWarning]))
+	assert.Check(t, is.Equal("4;36", theme["custom"]))
+	assert.Check(t, is.Equal(DefaultTheme[ColorSuccess], theme[ColorSuccess]))
+
+	_, err = ParseTheme(map[string]string{ColorError: "bright-bold"})
+	assert.Check(t, is.Error(err, `invalid color "bright-bold" of "error" in the theme of the configuration file`))
+}
+
+func TestColorPolicy(t *testing.T) {
+	defer env.Patch(t, "NO_COLOR", "")()
+	defer env.Patch(t, "TERM", "xterm")()
+	out := NewOut(new(bytes.Buffer))
+
+	assert.Check(t, is.Equal("text", ColorPolicy{}.Colorize(out, ColorError, "text")))
+	assert.Check(t, is.Equal("text", ColorPolicy{Mode: ColorNever}.Colorize(out, ColorError, "text")))
+	assert.Check(t, is.Equal("\x1b[31mtext\x1b[0m", ColorPolicy{Mode: ColorAlways}.Colorize(out, ColorError, "text")))
+	assert.Check(t, is.Equal("text", ColorPolicy{Mode: ColorAlways, Theme: Theme{ColorError: ""}}.Colorize(out, ColorError, "text")))
+
+	out.SetIsTerminal(true)
+	assert.Check(t, ColorPolicy{}.Enabled(out))
+	defer env.Patch(t, "NO_COLOR", "1")()
+	assert.Check(t, !ColorPolicy{}.Enabled(out))
+	assert.Check(t, ColorPolicy{Mode: ColorAlways}.Enabled(out))
+}
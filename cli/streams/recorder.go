@@ -0,0 +1,139 @@
+package streams
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Types of the events of an asciicast recording
+const (
+	recordOutput = "o"
+	recordInput  = "i"
+)
+
+// RecordHeader is the header of an asciicast v2 recording, see
+// https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md
+type RecordHeader struct {
+	Version   int               `json:"version"`
+	Width     uint              `json:"width"`
+	Height    uint              `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder records the input and output of a terminal session, with their
+// timing, in the asciicast v2 format.
+type Recorder struct {
+	mu      sync.Mutex
+	w       io.Writer
+	start   time.Time
+	now     func() time.Time
+	pending map[string][]byte
+	err     error
+}
+
+// NewRecorder returns a Recorder writing the recording to w, after writing
+// the given header. The version and timestamp of the header are set by
+// NewRecorder.
+func NewRecorder(w io.Writer, header RecordHeader) (*Recorder, error) {
+	return newRecorder(w, header, time.Now)
+}
+
+func newRecorder(w io.Writer, header RecordHeader, now func() time.Time) (*Recorder, error) {
+	r := &Recorder{w: w, start: now(), now: now, pending: map[string][]byte{}}
+	header.Version = 2
+	header.Timestamp = r.start.Unix()
+	if err := r.writeJSON(header); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Err returns the first error writing the recording, if any. The session is
+// not interrupted by errors writing the recording.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// RecordOutput returns a writer recording what is written to w as output of
+// the session.
+func (r *Recorder) RecordOutput(w io.Writer) io.Writer {
+	return &recordWriter{w: w, r: r}
+}
+
+// RecordInput returns a reader recording what is read from in as input of the
+// session.
+func (r *Recorder) RecordInput(in io.ReadCloser) io.ReadCloser {
+	return &recordReader{ReadCloser: in, r: r}
+}
+
+// record records an event with the given data. Incomplete UTF-8 sequences at
+// the end of the data are kept until the next event of the same type, as the
+// data of the events are strings.
+func (r *Recorder) record(eventType string, p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	data := append(r.pending[eventType], p...)
+	n := completeUTF8(data)
+	r.pending[eventType] = append([]byte{}, data[n:]...)
+	if n == 0 {
+		return
+	}
+	elapsed := r.now().Sub(r.start).Seconds()
+	r.err = r.writeJSON([]interface{}{elapsed, eventType, string(data[:n])})
+}
+
+func (r *Recorder) writeJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(b, '\n'))
+	return err
+}
+
+// completeUTF8 returns the length of the data without the incomplete UTF-8
+// sequence at its end, if any.
+func completeUTF8(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
+
+type recordWriter struct {
+	w io.Writer
+	r *Recorder
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.r.record(recordOutput, p[:n])
+	return n, err
+}
+
+type recordReader struct {
+	io.ReadCloser
+	r *Recorder
+}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.r.record(recordInput, p[:n])
+	return n, err
+}
@@ -0,0 +1,52 @@
+package streams
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	now := start
+	buf := new(bytes.Buffer)
+	r, err := newRecorder(buf, RecordHeader{Width: 80, Height: 24, Env: map[string]string{"TERM": "xterm"}}, func() time.Time { return now })
+	assert.NilError(t, err)
+
+	in := r.RecordInput(ioutil.NopCloser(strings.NewReader("ls\r")))
+	out := new(bytes.Buffer)
+	w := r.RecordOutput(out)
+
+	now = start.Add(500 * time.Millisecond)
+	_, err = ioutil.ReadAll(in)
+	assert.NilError(t, err)
+
+	now = start.Add(1500 * time.Millisecond)
+	_, err = w.Write([]byte("caf\xc3"))
+	assert.NilError(t, err)
+	_, err = w.Write([]byte("\xa9\r\n"))
+	assert.NilError(t, err)
+	assert.NilError(t, r.Err())
+
+	assert.Check(t, is.Equal("café\r\n", out.String()))
+	expected := `{"version":2,"width":80,"height":24,"timestamp":1500000000,"env":{"TERM":"xterm"}}
+[0.5,"i","ls\r"]
+[1.5,"o","caf"]
+[1.5,"o","é\r\n"]
+`
+	assert.Check(t, is.Equal(expected, buf.String()))
+}
+
+func TestCompleteUTF8(t *testing.T) {
+	assert.Check(t, is.Equal(0, completeUTF8(nil)))
+	assert.Check(t, is.Equal(3, completeUTF8([]byte("abc"))))
+	assert.Check(t, is.Equal(1, completeUTF8([]byte("a\xe2\x82"))))
+	assert.Check(t, is.Equal(4, completeUTF8([]byte("a\xe2\x82\xac"))))
+	// Invalid sequences are not kept
+	assert.Check(t, is.Equal(2, completeUTF8([]byte("a\x82"))))
+}
@@ -0,0 +1,227 @@
+// Package tracing records OpenTelemetry spans for the execution of the CLI,
+// and exports them to an OTLP/HTTP endpoint.
+//
+// Tracing is disabled unless an endpoint is configured. All the methods of a
+// nil *Tracer and a nil *Span are no-ops, so that callers do not have to check
+// whether tracing is enabled.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TraceparentEnvVar is the environment variable holding the W3C trace context
+// of the parent span. It is set for CLI plugins, so that their spans are part
+// of the trace of the CLI command that invoked them.
+const TraceparentEnvVar = "TRACEPARENT"
+
+// flushTimeout is the maximum time spent exporting the spans at the end of
+// the command
+const flushTimeout = 2 * time.Second
+
+// SpanKind is the kind of a span, as defined by OTLP
+type SpanKind int
+
+// Kinds of spans
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindClient   SpanKind = 3
+)
+
+var traceparentRe = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// Tracer records the spans of a trace
+type Tracer struct {
+	endpoint string
+	headers  map[string]string
+	attrs    map[string]string
+
+	mu       sync.Mutex
+	traceID  string
+	parentID string
+	active   *Span
+	spans    []*Span
+}
+
+// New returns a tracer exporting its spans to the given OTLP/HTTP endpoint,
+// such as "http://localhost:4318", or nil if the endpoint is empty. If the
+// TRACEPARENT environment variable is set, the spans are part of the trace
+// it refers to.
+func New(endpoint string, headers map[string]string) *Tracer {
+	if endpoint == "" {
+		return nil
+	}
+	t := &Tracer{
+		endpoint: endpoint,
+		headers:  headers,
+		attrs:    map[string]string{"service.name": "docker-cli"},
+		traceID:  newID(16),
+	}
+	if m := traceparentRe.FindStringSubmatch(os.Getenv(TraceparentEnvVar)); m != nil {
+		t.traceID, t.parentID = m[1], m[2]
+	}
+	return t
+}
+
+// SetResourceAttribute sets an attribute of the entity producing the spans,
+// such as "service.version"
+func (t *Tracer) SetResourceAttribute(key, value string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attrs[key] = value
+}
+
+// StartSpan starts an internal span, child of the active span. The span is
+// the active span until it ends.
+func (t *Tracer) StartSpan(name string) *Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.newSpan(name, SpanKindInternal)
+	s.restore = t.active
+	t.active = s
+	return s
+}
+
+// StartClientSpan starts a span for a request to a remote service, child of
+// the active span.
+func (t *Tracer) StartClientSpan(name string) *Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.newSpan(name, SpanKindClient)
+}
+
+func (t *Tracer) newSpan(name string, kind SpanKind) *Span {
+	s := &Span{
+		tracer:   t,
+		name:     name,
+		kind:     kind,
+		traceID:  t.traceID,
+		spanID:   newID(8),
+		parentID: t.parentID,
+		start:    time.Now(),
+		attrs:    map[string]string{},
+	}
+	if t.active != nil {
+		s.parentID = t.active.spanID
+	}
+	return s
+}
+
+// Traceparent returns the W3C trace context of the active span, to propagate
+// the trace to other processes.
+func (t *Tracer) Traceparent() string {
+	if t == nil {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parentID := t.parentID
+	if t.active != nil {
+		parentID = t.active.spanID
+	}
+	if parentID == "" {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", t.traceID, parentID)
+}
+
+// Span is an operation of a trace
+type Span struct {
+	tracer   *Tracer
+	name     string
+	kind     SpanKind
+	traceID  string
+	spanID   string
+	parentID string
+	start    time.Time
+	end      time.Time
+	attrs    map[string]string
+	err      error
+	// restore is the span that was active when this span started
+	restore *Span
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.attrs[key] = value
+}
+
+// End ends the span. If err is not nil, the span is marked as failed.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	t := s.tracer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !s.end.IsZero() {
+		return
+	}
+	s.end = time.Now()
+	s.err = err
+	if t.active == s {
+		t.active = s.restore
+	}
+	t.spans = append(t.spans, s)
+}
+
+// Shutdown exports the spans that ended. It must be called once all the
+// spans ended.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	return t.export(ctx, spans)
+}
+
+// Flush exports the spans that ended, and logs the failures to export them.
+// Export failures are not returned, so that the export never fails the
+// command being traced.
+func (t *Tracer) Flush() {
+	if t == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := t.Shutdown(ctx); err != nil {
+		logrus.Debug(err)
+	}
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/env"
+)
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	assert.Check(t, is.Nil(New("", nil)))
+	span := tracer.StartSpan("docker")
+	span.SetAttribute("key", "value")
+	span.End(nil)
+	assert.Check(t, is.Equal("", tracer.Traceparent()))
+	assert.Check(t, tracer.Shutdown(context.Background()))
+}
+
+func TestExport(t *testing.T) {
+	var (
+		received otlpRequest
+		header   http.Header
+		path     string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, header = r.URL.Path, r.Header
+		b, err := ioutil.ReadAll(r.Body)
+		assert.Check(t, err)
+		assert.Check(t, json.Unmarshal(b, &received))
+	}))
+	defer server.Close()
+
+	// The requests to the daemon are made with the same transport
+	var daemonHeader http.Header
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		daemonHeader = r.Header
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer daemon.Close()
+
+	tracer := New(server.URL, map[string]string{"Authorization": "token"})
+	root := tracer.StartSpan("docker container ls")
+	root.SetAttribute("docker.context", "default")
+
+	httpClient := &http.Client{Transport: NewTransport(tracer, http.DefaultTransport)}
+	resp, err := httpClient.Get(daemon.URL + "/v1.40/containers/json")
+	assert.NilError(t, err)
+	resp.Body.Close()
+
+	plugin := tracer.StartSpan("plugin app")
+	traceparent := tracer.Traceparent()
+	plugin.End(errors.New("exit status 1"))
+	root.End(nil)
+	assert.NilError(t, tracer.Shutdown(context.Background()))
+
+	assert.Check(t, is.Equal("/v1/traces", path))
+	assert.Check(t, is.Equal("token", header.Get("Authorization")))
+	assert.Assert(t, is.Len(received.ResourceSpans, 1))
+	assert.Check(t, is.DeepEqual(otlpAttribute{Key: "service.name", Value: otlpValue{StringValue: "docker-cli"}}, received.ResourceSpans[0].Resource.Attributes[0]))
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Assert(t, is.Len(spans, 3))
+
+	api, plug, cmd := spans[0], spans[1], spans[2]
+	assert.Check(t, is.Equal("docker container ls", cmd.Name))
+	assert.Check(t, is.Equal("", cmd.ParentSpanID))
+	assert.Check(t, is.Equal(statusCodeOK, cmd.Status.Code))
+
+	assert.Check(t, is.Equal("GET /containers/json", api.Name))
+	assert.Check(t, is.Equal(SpanKindClient, api.Kind))
+	assert.Check(t, is.Equal(cmd.SpanID, api.ParentSpanID))
+	assert.Check(t, is.Equal(cmd.TraceID, api.TraceID))
+	assert.Check(t, is.Contains(api.Attributes, otlpAttribute{Key: "http.status_code", Value: otlpValue{StringValue: "404"}}))
+	assert.Check(t, is.Equal("00-"+api.TraceID+"-"+api.SpanID+"-01", daemonHeader.Get("traceparent")))
+
+	assert.Check(t, is.Equal("plugin app", plug.Name))
+	assert.Check(t, is.Equal(cmd.SpanID, plug.ParentSpanID))
+	assert.Check(t, is.Equal(statusCodeError, plug.Status.Code))
+	assert.Check(t, is.Equal("exit status 1", plug.Status.Message))
+	assert.Check(t, is.Equal("00-"+plug.TraceID+"-"+plug.SpanID+"-01", traceparent))
+}
+
+func TestTraceparentFromEnv(t *testing.T) {
+	defer env.Patch(t, TraceparentEnvVar, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")()
+	assert.Check(t, is.Equal("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", os.Getenv(TraceparentEnvVar)))
+
+	tracer := New("http://localhost:4318", nil)
+	span := tracer.StartSpan("docker app")
+	assert.Check(t, is.Equal("0af7651916cd43dd8448eb211c80319c", span.traceID))
+	assert.Check(t, is.Equal("b7ad6b7169203331", span.parentID))
+}
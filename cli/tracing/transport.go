@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// versionPrefixRe matches the API version prefix of the path of a request to
+// the daemon, such as "/v1.40"
+var versionPrefixRe = regexp.MustCompile(`^/v[0-9.]+/`)
+
+type transport struct {
+	tracer *Tracer
+	base   http.RoundTripper
+}
+
+// NewTransport returns a RoundTripper recording a span for each request made
+// with base. It returns base if the tracer is nil.
+func NewTransport(t *Tracer, base http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return base
+	}
+	return &transport{tracer: t, base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := versionPrefixRe.ReplaceAllString(req.URL.Path, "/")
+	span := t.tracer.StartClientSpan(req.Method + " " + path)
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.target", req.URL.Path)
+
+	// The trace context is sent to the daemon, which ignores it unless it
+	// supports tracing.
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set("traceparent", "00-"+span.traceID+"-"+span.spanID+"-01")
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.End(errors.New(resp.Status))
+	} else {
+		span.End(nil)
+	}
+	return resp, nil
+}
@@ -0,0 +1,175 @@
+package cosign
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// ArchiveFile is the name of the file with the signature material of the
+// images of an image archive, such as the archives of "docker save".
+const ArchiveFile = "cosign-signatures.json"
+
+// Archive is the signature material of images, which is embedded in image
+// archives to verify their signatures without contacting the registries.
+// It is a Fetcher of the content it records.
+type Archive struct {
+	Images []ArchiveImage `json:"images"`
+	// Manifests are the manifests of the images and of their signatures,
+	// by reference.
+	Manifests map[string][]byte `json:"manifests"`
+	// Blobs are the payloads of the signatures, by digest.
+	Blobs map[digest.Digest][]byte `json:"blobs"`
+}
+
+// ArchiveImage is an image of an archive, and the digest of its manifest in
+// its repository.
+type ArchiveImage struct {
+	Reference string        `json:"reference"`
+	Digest    digest.Digest `json:"digest"`
+}
+
+// NewArchive returns an empty archive.
+func NewArchive() *Archive {
+	return &Archive{
+		Manifests: map[string][]byte{},
+		Blobs:     map[digest.Digest][]byte{},
+	}
+}
+
+// Add adds the signature material of the image of the reference, whose
+// manifest has the given digest, and whose image ID is imageID, fetching it
+// with the fetcher. If the manifest is a manifest list, the manifest of the
+// image is added as well.
+func (a *Archive) Add(ctx context.Context, fetcher Fetcher, ref reference.Named, dgst, imageID digest.Digest) error {
+	recorder := &recordingFetcher{fetcher: fetcher, archive: a}
+	if _, err := ResolveManifest(ctx, recorder, ref, dgst, imageID); err != nil {
+		return err
+	}
+	if _, err := FetchSignatures(ctx, recorder, ref, dgst); err != nil {
+		return err
+	}
+	a.Images = append(a.Images, ArchiveImage{Reference: ref.String(), Digest: dgst})
+	return nil
+}
+
+// GetManifestContent returns the recorded manifest of the reference.
+func (a *Archive) GetManifestContent(ctx context.Context, ref reference.Named) (client.ManifestContent, error) {
+	payload, ok := a.Manifests[ref.String()]
+	if !ok {
+		return client.ManifestContent{}, notFoundError("manifest " + ref.String())
+	}
+	var mediaType struct {
+		MediaType string `json:"mediaType"`
+	}
+	if err := json.Unmarshal(payload, &mediaType); err != nil {
+		return client.ManifestContent{}, errors.Wrapf(err, "invalid manifest %s", ref)
+	}
+	content := client.ManifestContent{MediaType: mediaType.MediaType, Digest: digest.FromBytes(payload), Payload: payload}
+	if canonical, ok := ref.(reference.Canonical); ok && canonical.Digest() != content.Digest {
+		return client.ManifestContent{}, errors.Errorf("the manifest of %s has digest %s", ref, content.Digest)
+	}
+	return content, nil
+}
+
+// GetBlob returns the recorded blob with the given digest.
+func (a *Archive) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	b, ok := a.Blobs[dgst]
+	if !ok {
+		return nil, notFoundError("blob " + dgst.String())
+	}
+	if digest.FromBytes(b) != dgst {
+		return nil, errors.Errorf("blob %s does not match its digest", dgst)
+	}
+	return b, nil
+}
+
+// ResolveManifest returns the manifest of the image with the given image ID,
+// from the manifest of the repository of the reference with the given digest,
+// which is either the manifest of the image, or a manifest list including it.
+func ResolveManifest(ctx context.Context, fetcher Fetcher, ref reference.Named, dgst, imageID digest.Digest) (ocispec.Manifest, error) {
+	manifest, index, err := fetchManifest(ctx, fetcher, ref, dgst)
+	if err != nil {
+		return ocispec.Manifest{}, err
+	}
+	if index == nil {
+		if manifest.Config.Digest != imageID {
+			return ocispec.Manifest{}, errors.Errorf("the manifest %s of %s is not the one of image %s", dgst, reference.FamiliarName(ref), imageID)
+		}
+		return manifest, nil
+	}
+	for _, desc := range index.Manifests {
+		manifest, _, err := fetchManifest(ctx, fetcher, ref, desc.Digest)
+		if err != nil {
+			return ocispec.Manifest{}, err
+		}
+		if manifest.Config.Digest == imageID {
+			return manifest, nil
+		}
+	}
+	return ocispec.Manifest{}, errors.Errorf("the manifest list %s of %s does not include image %s", dgst, reference.FamiliarName(ref), imageID)
+}
+
+// fetchManifest returns the manifest, or the manifest list, with the given
+// digest.
+func fetchManifest(ctx context.Context, fetcher Fetcher, ref reference.Named, dgst digest.Digest) (ocispec.Manifest, *ocispec.Index, error) {
+	canonical, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return ocispec.Manifest{}, nil, err
+	}
+	content, err := fetcher.GetManifestContent(ctx, canonical)
+	if err != nil {
+		return ocispec.Manifest{}, nil, errors.Wrapf(err, "unable to fetch the manifest %s of %s", dgst, reference.FamiliarName(ref))
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(content.Payload, &index); err != nil {
+		return ocispec.Manifest{}, nil, errors.Wrapf(err, "invalid manifest %s", dgst)
+	}
+	// The media type of OCI manifests is optional
+	switch {
+	case content.MediaType == ocispec.MediaTypeImageIndex, content.MediaType == manifestlist.MediaTypeManifestList, len(index.Manifests) > 0:
+		return ocispec.Manifest{}, &index, nil
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(content.Payload, &manifest); err != nil {
+		return ocispec.Manifest{}, nil, errors.Wrapf(err, "invalid manifest %s", dgst)
+	}
+	return manifest, nil, nil
+}
+
+// recordingFetcher records the content it fetches in an archive
+type recordingFetcher struct {
+	fetcher Fetcher
+	archive *Archive
+}
+
+func (f *recordingFetcher) GetManifestContent(ctx context.Context, ref reference.Named) (client.ManifestContent, error) {
+	content, err := f.fetcher.GetManifestContent(ctx, ref)
+	if err == nil {
+		f.archive.Manifests[ref.String()] = content.Payload
+	}
+	return content, err
+}
+
+func (f *recordingFetcher) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	b, err := f.fetcher.GetBlob(ctx, ref, dgst)
+	if err == nil {
+		f.archive.Blobs[dgst] = b
+	}
+	return b, err
+}
+
+type notFoundError string
+
+func (e notFoundError) Error() string {
+	return string(e) + " not found"
+}
+
+// NotFound satisfies the interface of client.IsNotFound
+func (notFoundError) NotFound() {}
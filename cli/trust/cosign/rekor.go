@@ -0,0 +1,83 @@
+package cosign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Bundle is the proof of the inclusion of a signature in the Rekor
+// transparency log: the entry of the signature, and the timestamp of the
+// entry signed by the log.
+type Bundle struct {
+	SignedEntryTimestamp []byte
+	Payload              BundlePayload
+}
+
+// BundlePayload is the entry of a signature in the Rekor transparency log.
+// The order of its fields is the one of its canonical JSON encoding, which
+// is signed by the log.
+type BundlePayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// hashedRekord is the body of a "hashedrekord" entry of the log
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content string `json:"content"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verify verifies that the entry is signed by the log, and that it is the
+// entry of the signature.
+func (b *Bundle) verify(rekorPublicKey crypto.PublicKey, sig Signature) error {
+	canonical, err := json.Marshal(b.Payload)
+	if err != nil {
+		return err
+	}
+	if err := verifyWithKey(rekorPublicKey, canonical, b.SignedEntryTimestamp); err != nil {
+		return errors.Wrap(err, "invalid Rekor signed entry timestamp")
+	}
+	if b.Payload.IntegratedTime > time.Now().Add(time.Minute).Unix() {
+		return errors.New("invalid Rekor entry: integrated in the future")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return errors.Wrap(err, "invalid Rekor entry")
+	}
+	var entry hashedRekord
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return errors.Wrap(err, "invalid Rekor entry")
+	}
+	if entry.Kind != "hashedrekord" {
+		return errors.Errorf("unsupported Rekor entry kind %q", entry.Kind)
+	}
+	hash := sha256.Sum256(sig.Payload)
+	if entry.Spec.Data.Hash.Algorithm != "sha256" || entry.Spec.Data.Hash.Value != hex.EncodeToString(hash[:]) {
+		return errors.New("the Rekor entry is not the one of the signature payload")
+	}
+	entrySig, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.Content)
+	if err != nil || !bytes.Equal(entrySig, sig.Signature) {
+		return errors.New("the Rekor entry is not the one of the signature")
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Annotations of the layers of the signatures
+const (
+	signatureAnnotation   = "dev.cosignproject.cosign/signature"
+	certificateAnnotation = "dev.sigstore.cosign/certificate"
+	chainAnnotation       = "dev.sigstore.cosign/chain"
+	bundleAnnotation      = "dev.sigstore.cosign/bundle"
+)
+
+// simpleSigningType is the type of the payloads of the signatures
+const simpleSigningType = "cosign container image signature"
+
+// OIDs of the extensions of the Fulcio certificates with the OIDC issuer of
+// the identity of the signer
+var (
+	oidIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Fetcher fetches the manifests and blobs of a registry, such as the
+// registry client of the CLI.
+type Fetcher interface {
+	GetManifestContent(ctx context.Context, ref reference.Named) (client.ManifestContent, error)
+	GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error)
+}
+
+// Signature is a cosign signature of a manifest.
+type Signature struct {
+	Payload     []byte
+	Signature   []byte
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate
+	Bundle      *Bundle
+}
+
+// VerifiedSignature is the result of the verification of a signature.
+type VerifiedSignature struct {
+	Digest digest.Digest
+	// Identity and Issuer are the identity of the signer, and its OIDC
+	// issuer, if the signature has a certificate.
+	Identity string
+	Issuer   string
+	// LogIndex and IntegratedTime are the index and time of the entry of the
+	// signature in the Rekor transparency log, if it was verified.
+	LogIndex       int64
+	IntegratedTime time.Time
+}
+
+// simpleSigning is the payload of a signature
+type simpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// SignatureTag returns the tag of the signatures of the manifest with the
+// given digest.
+func SignatureTag(dgst digest.Digest) string {
+	return dgst.Algorithm().String() + "-" + dgst.Hex() + ".sig"
+}
+
+// FetchSignatures returns the signatures of the manifest of the repository
+// of the reference with the given digest.
+func FetchSignatures(ctx context.Context, fetcher Fetcher, ref reference.Named, dgst digest.Digest) ([]Signature, error) {
+	sigRef, err := reference.WithTag(reference.TrimNamed(ref), SignatureTag(dgst))
+	if err != nil {
+		return nil, err
+	}
+	content, err := fetcher.GetManifestContent(ctx, sigRef)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return nil, errors.Errorf("no cosign signatures found for %s@%s", reference.FamiliarName(ref), dgst)
+		}
+		return nil, errors.Wrapf(err, "unable to fetch the cosign signatures of %s@%s", reference.FamiliarName(ref), dgst)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(content.Payload, &manifest); err != nil {
+		return nil, errors.Wrap(err, "invalid cosign signatures manifest")
+	}
+
+	var signatures []Signature
+	for _, layer := range manifest.Layers {
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations[signatureAnnotation])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+		payload, err := fetcher.GetBlob(ctx, sigRef, layer.Digest)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to fetch the payload of a cosign signature")
+		}
+		signature := Signature{Payload: payload, Signature: sig}
+		if cert := layer.Annotations[certificateAnnotation]; cert != "" {
+			certs, err := parseCertificates([]byte(cert))
+			if err != nil || len(certs) == 0 {
+				continue
+			}
+			signature.Certificate = certs[0]
+			signature.Chain, _ = parseCertificates([]byte(layer.Annotations[chainAnnotation]))
+		}
+		if bundle := layer.Annotations[bundleAnnotation]; bundle != "" {
+			signature.Bundle = new(Bundle)
+			if err := json.Unmarshal([]byte(bundle), signature.Bundle); err != nil {
+				continue
+			}
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures, nil
+}
+
+// Verify verifies the cosign signatures of the manifest of the repository of
+// the reference with the given digest. It returns the first valid signature,
+// or an error if none of the signatures is valid.
+func (v *Verifier) Verify(ctx context.Context, fetcher Fetcher, ref reference.Named, dgst digest.Digest) (VerifiedSignature, error) {
+	signatures, err := FetchSignatures(ctx, fetcher, ref, dgst)
+	if err != nil {
+		return VerifiedSignature{}, err
+	}
+	var errs []string
+	for _, sig := range signatures {
+		verified, err := v.VerifySignature(sig, dgst)
+		if err == nil {
+			return verified, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return VerifiedSignature{}, errors.Errorf("no cosign signatures found for %s@%s", reference.FamiliarName(ref), dgst)
+	}
+	return VerifiedSignature{}, errors.Errorf("no valid cosign signature for %s@%s: %s", reference.FamiliarName(ref), dgst, strings.Join(errs, "; "))
+}
+
+// VerifySignature verifies a signature of the manifest with the given digest.
+func (v *Verifier) VerifySignature(sig Signature, dgst digest.Digest) (VerifiedSignature, error) {
+	var payload simpleSigning
+	if err := json.Unmarshal(sig.Payload, &payload); err != nil {
+		return VerifiedSignature{}, errors.Wrap(err, "invalid signature payload")
+	}
+	if payload.Critical.Type != simpleSigningType {
+		return VerifiedSignature{}, errors.Errorf("invalid signature payload type %q", payload.Critical.Type)
+	}
+	if payload.Critical.Image.DockerManifestDigest != dgst.String() {
+		return VerifiedSignature{}, errors.Errorf("the signature is for manifest %s", payload.Critical.Image.DockerManifestDigest)
+	}
+
+	verified := VerifiedSignature{Digest: dgst}
+	if v.RekorPublicKey != nil {
+		if sig.Bundle == nil {
+			return VerifiedSignature{}, errors.New("the signature is not recorded in the Rekor transparency log")
+		}
+		if err := sig.Bundle.verify(v.RekorPublicKey, sig); err != nil {
+			return VerifiedSignature{}, err
+		}
+		verified.LogIndex = sig.Bundle.Payload.LogIndex
+		verified.IntegratedTime = time.Unix(sig.Bundle.Payload.IntegratedTime, 0)
+	}
+
+	key := v.PublicKey
+	if key == nil {
+		if sig.Certificate == nil {
+			return VerifiedSignature{}, errors.New("the signature has no certificate")
+		}
+		identity, issuer, err := v.verifyCertificate(sig, verified.IntegratedTime)
+		if err != nil {
+			return VerifiedSignature{}, err
+		}
+		verified.Identity, verified.Issuer = identity, issuer
+		key = sig.Certificate.PublicKey
+	}
+	if err := verifyWithKey(key, sig.Payload, sig.Signature); err != nil {
+		return VerifiedSignature{}, err
+	}
+	return verified, nil
+}
+
+// verifyCertificate verifies that the certificate of the signature was issued
+// by the roots for the identity and issuer of the verifier, and was valid
+// when the signature was recorded in the transparency log.
+func (v *Verifier) verifyCertificate(sig Signature, signedAt time.Time) (identity, issuer string, err error) {
+	intermediates := x509.NewCertPool()
+	if v.Intermediates != nil {
+		intermediates = v.Intermediates
+	}
+	for _, cert := range sig.Chain {
+		intermediates.AddCert(cert)
+	}
+	if _, err := sig.Certificate.Verify(x509.VerifyOptions{
+		Roots:         v.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return "", "", errors.Wrap(err, "invalid signature certificate")
+	}
+
+	identities := append([]string{}, sig.Certificate.EmailAddresses...)
+	for _, uri := range sig.Certificate.URIs {
+		identities = append(identities, uri.String())
+	}
+	for _, id := range identities {
+		if id == v.Identity {
+			identity = id
+		}
+	}
+	if identity == "" {
+		return "", "", errors.Errorf("the signature certificate is for %s, not %s", strings.Join(identities, ", "), v.Identity)
+	}
+	issuer = certificateIssuer(sig.Certificate)
+	if v.Issuer != "" && issuer != v.Issuer {
+		return "", "", errors.Errorf("the signature certificate is issued by %q, not %q", issuer, v.Issuer)
+	}
+	return identity, issuer, nil
+}
+
+// certificateIssuer returns the OIDC issuer of the identity of a Fulcio
+// certificate.
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidIssuer):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+// verifyWithKey verifies the signature of the SHA-256 digest of the payload.
+func verifyWithKey(key crypto.PublicKey, payload, sig []byte) error {
+	hash := sha256.Sum256(payload)
+	valid := false
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, hash[:], sig)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, payload, sig)
+	default:
+		return errors.Errorf("unsupported public key type %T", key)
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package cosign
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+const manifestDigest = digest.Digest("sha256:dd3b2c71b0eb8a3a6dfb4f5e2e10c8d4cd22b3c1d6af1b8c9f3a3e4a5b6c7d8e")
+
+type fakeFetcher struct {
+	manifests map[string][]byte
+	blobs     map[digest.Digest][]byte
+}
+
+func (f *fakeFetcher) GetManifestContent(ctx context.Context, ref reference.Named) (client.ManifestContent, error) {
+	payload, ok := f.manifests[ref.String()]
+	if !ok {
+		return client.ManifestContent{}, notFound{}
+	}
+	return client.ManifestContent{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(payload), Payload: payload}, nil
+}
+
+func (f *fakeFetcher) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	return f.blobs[dgst], nil
+}
+
+type notFound struct{}
+
+func (notFound) Error() string { return "not found" }
+func (notFound) NotFound()     {}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	return key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) []byte {
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	assert.NilError(t, err)
+	return sig
+}
+
+func signaturePayload(dgst digest.Digest) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.example.com/app"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, dgst))
+}
+
+// newFetcher returns a fetcher of the signature of the payload, with the
+// given annotations.
+func newFetcher(t *testing.T, payload, sig []byte, annotations map[string]string) *fakeFetcher {
+	layerDigest := digest.FromBytes(payload)
+	layer := ocispec.Descriptor{
+		MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+		Digest:      layerDigest,
+		Size:        int64(len(payload)),
+		Annotations: map[string]string{signatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	}
+	for k, v := range annotations {
+		layer.Annotations[k] = v
+	}
+	manifest, err := json.Marshal(ocispec.Manifest{Layers: []ocispec.Descriptor{layer}})
+	assert.NilError(t, err)
+	return &fakeFetcher{
+		manifests: map[string][]byte{"registry.example.com/app:" + SignatureTag(manifestDigest): manifest},
+		blobs:     map[digest.Digest][]byte{layerDigest: payload},
+	}
+}
+
+func newBundle(t *testing.T, rekorKey *ecdsa.PrivateKey, payload, sig []byte, integratedTime time.Time) string {
+	hash := sha256.Sum256(payload)
+	body := fmt.Sprintf(`{"apiVersion":"0.0.1","kind":"hashedrekord","spec":{"data":{"hash":{"algorithm":"sha256","value":%q}},"signature":{"content":%q}}}`,
+		hex.EncodeToString(hash[:]), base64.StdEncoding.EncodeToString(sig))
+	bundlePayload := BundlePayload{
+		Body:           base64.StdEncoding.EncodeToString([]byte(body)),
+		IntegratedTime: integratedTime.Unix(),
+		LogID:          "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d",
+		LogIndex:       42,
+	}
+	canonical, err := json.Marshal(bundlePayload)
+	assert.NilError(t, err)
+	b, err := json.Marshal(Bundle{SignedEntryTimestamp: sign(t, rekorKey, canonical), Payload: bundlePayload})
+	assert.NilError(t, err)
+	return string(b)
+}
+
+func TestVerifyWithPublicKey(t *testing.T) {
+	key := newKey(t)
+	payload := signaturePayload(manifestDigest)
+	fetcher := newFetcher(t, payload, sign(t, key, payload), nil)
+	ref, err := reference.ParseNormalizedNamed("registry.example.com/app:1.0")
+	assert.NilError(t, err)
+
+	v := &Verifier{PublicKey: &key.PublicKey}
+	verified, err := v.Verify(context.Background(), fetcher, ref, manifestDigest)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(manifestDigest, verified.Digest))
+
+	v = &Verifier{PublicKey: &newKey(t).PublicKey}
+	_, err = v.Verify(context.Background(), fetcher, ref, manifestDigest)
+	assert.Check(t, is.ErrorContains(err, "no valid cosign signature for registry.example.com/app@"+manifestDigest.String()+": invalid signature"))
+
+	_, err = v.Verify(context.Background(), fetcher, ref, digest.FromString("other"))
+	assert.Check(t, is.ErrorContains(err, "no cosign signatures found"))
+}
+
+func TestVerifySignatureOfOtherManifest(t *testing.T) {
+	key := newKey(t)
+	payload := signaturePayload(digest.FromString("other"))
+	v := &Verifier{PublicKey: &key.PublicKey}
+	_, err := v.VerifySignature(Signature{Payload: payload, Signature: sign(t, key, payload)}, manifestDigest)
+	assert.Check(t, is.Error(err, "the signature is for manifest "+digest.FromString("other").String()))
+}
+
+func TestVerifyWithCertificate(t *testing.T) {
+	signedAt := time.Now().Add(-time.Hour)
+
+	rootKey := newKey(t)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             signedAt.Add(-24 * time.Hour),
+		NotAfter:              signedAt.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	assert.NilError(t, err)
+	root, err := x509.ParseCertificate(rootDER)
+	assert.NilError(t, err)
+
+	issuer, err := asn1.Marshal("https://accounts.example.com")
+	assert.NilError(t, err)
+	signerKey := newKey(t)
+	signerTemplate := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       signedAt.Add(-5 * time.Minute),
+		NotAfter:        signedAt.Add(5 * time.Minute),
+		EmailAddresses:  []string{"release@example.com"},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuer}},
+	}
+	signerDER, err := x509.CreateCertificate(rand.Reader, signerTemplate, root, &signerKey.PublicKey, rootKey)
+	assert.NilError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: signerDER})
+
+	rekorKey := newKey(t)
+	payload := signaturePayload(manifestDigest)
+	sig := sign(t, signerKey, payload)
+	fetcher := newFetcher(t, payload, sig, map[string]string{
+		certificateAnnotation: string(certPEM),
+		bundleAnnotation:      newBundle(t, rekorKey, payload, sig, signedAt),
+	})
+	ref, err := reference.ParseNormalizedNamed("registry.example.com/app:1.0")
+	assert.NilError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	v := &Verifier{
+		Roots:          roots,
+		Identity:       "release@example.com",
+		Issuer:         "https://accounts.example.com",
+		RekorPublicKey: &rekorKey.PublicKey,
+	}
+	verified, err := v.Verify(context.Background(), fetcher, ref, manifestDigest)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(VerifiedSignature{
+		Digest:         manifestDigest,
+		Identity:       "release@example.com",
+		Issuer:         "https://accounts.example.com",
+		LogIndex:       42,
+		IntegratedTime: time.Unix(signedAt.Unix(), 0),
+	}, verified))
+
+	v.Identity = "attacker@example.com"
+	_, err = v.Verify(context.Background(), fetcher, ref, manifestDigest)
+	assert.Check(t, is.ErrorContains(err, "the signature certificate is for release@example.com, not attacker@example.com"))
+
+	v.Identity = "release@example.com"
+	v.RekorPublicKey = &newKey(t).PublicKey
+	_, err = v.Verify(context.Background(), fetcher, ref, manifestDigest)
+	assert.Check(t, is.ErrorContains(err, "invalid Rekor signed entry timestamp"))
+}
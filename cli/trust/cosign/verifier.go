@@ -0,0 +1,163 @@
+// Package cosign verifies the cosign signatures of images, as an alternative
+// to the Notary v1 signatures of content trust.
+//
+// The signatures of a manifest are the layers of the manifest tagged with the
+// digest of the signed manifest, such as "sha256-<hex>.sig", in the same
+// repository. They are verified with a public key, or with the certificates
+// of their signers issued by Fulcio, and their entries in the Rekor
+// transparency log.
+package cosign
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+// Backends of the verification of the signatures of a registry
+const (
+	BackendNotary = "notary"
+	BackendCosign = "cosign"
+)
+
+// Verifier verifies the cosign signatures of images.
+type Verifier struct {
+	// PublicKey is the public key of the signatures. If nil, the signatures
+	// are verified with their certificates, which must be issued by Roots
+	// for Identity and Issuer.
+	PublicKey     crypto.PublicKey
+	Roots         *x509.CertPool
+	Intermediates *x509.CertPool
+	Identity      string
+	Issuer        string
+	// RekorPublicKey is the public key of the Rekor transparency log. If set,
+	// the signatures must have been recorded in the log. It is required to
+	// verify signatures with certificates.
+	RekorPublicKey crypto.PublicKey
+}
+
+// NewVerifier returns the verifier of the given configuration, loading its
+// keys and certificates.
+func NewVerifier(config configfile.SignatureVerificationConfig) (*Verifier, error) {
+	v := &Verifier{
+		Identity: config.CertificateIdentity,
+		Issuer:   config.CertificateOIDCIssuer,
+	}
+	var err error
+	if config.PublicKey != "" {
+		if v.PublicKey, err = loadPublicKey(config.PublicKey); err != nil {
+			return nil, err
+		}
+	}
+	if config.RekorPublicKey != "" {
+		if v.RekorPublicKey, err = loadPublicKey(config.RekorPublicKey); err != nil {
+			return nil, err
+		}
+	}
+	if config.FulcioRoots != "" {
+		if v.Roots, v.Intermediates, err = loadCertificates(config.FulcioRoots); err != nil {
+			return nil, err
+		}
+	}
+	if v.PublicKey == nil {
+		switch {
+		case v.Roots == nil:
+			return nil, errors.New("cosign signature verification requires a public key, or Fulcio roots")
+		case v.Identity == "":
+			return nil, errors.New("cosign signature verification with Fulcio certificates requires a certificate identity")
+		case v.RekorPublicKey == nil:
+			return nil, errors.New("cosign signature verification with Fulcio certificates requires the Rekor public key")
+		}
+	}
+	return v, nil
+}
+
+// ConfigForReference returns the signature verification configuration of the
+// registry of the reference, and whether it uses the cosign backend.
+func ConfigForReference(configFile *configfile.ConfigFile, ref reference.Named) (configfile.SignatureVerificationConfig, bool) {
+	config, ok := configFile.SignatureVerification[reference.Domain(ref)]
+	if !ok {
+		return configfile.SignatureVerificationConfig{}, false
+	}
+	return config, strings.ToLower(config.Backend) == BackendCosign
+}
+
+// VerifierForReference returns the verifier of the signatures of the images
+// of the registry of the reference, or nil if the registry does not use the
+// cosign backend.
+func VerifierForReference(configFile *configfile.ConfigFile, ref reference.Named) (*Verifier, error) {
+	config, isCosign := ConfigForReference(configFile, ref)
+	if !isCosign {
+		return nil, nil
+	}
+	v, err := NewVerifier(config)
+	return v, errors.Wrapf(err, "invalid signature verification configuration for %s", reference.Domain(ref))
+}
+
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read public key")
+	}
+	return ParsePublicKey(b)
+}
+
+// ParsePublicKey parses a PEM public key, such as the "cosign.pub" file of
+// "cosign generate-key-pair".
+func ParsePublicKey(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("invalid public key: no PEM data found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	return key, errors.Wrap(err, "invalid public key")
+}
+
+func loadCertificates(path string) (roots, intermediates *x509.CertPool, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to read Fulcio roots")
+	}
+	certs, err := parseCertificates(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(certs) == 0 {
+		return nil, nil, errors.Errorf("no certificates found in %s", path)
+	}
+	roots, intermediates = x509.NewCertPool(), x509.NewCertPool()
+	for _, cert := range certs {
+		// Self-signed certificates are roots
+		if cert.CheckSignatureFrom(cert) == nil {
+			roots.AddCert(cert)
+		} else {
+			intermediates.AddCert(cert)
+		}
+	}
+	return roots, intermediates, nil
+}
+
+func parseCertificates(b []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid certificate")
+		}
+		certs = append(certs, cert)
+	}
+}
@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"reflect"
 	"strings"
 	"syscall"
 
 	"github.com/docker/cli/cli"
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/audit"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/alias"
 	"github.com/docker/cli/cli/command/commands"
+	"github.com/docker/cli/cli/command/debug"
 	cliflags "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/i18n"
+	"github.com/docker/cli/cli/tracing"
 	"github.com/docker/cli/cli/version"
+	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
 	"github.com/sirupsen/logrus"
@@ -38,8 +47,7 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 			if len(args) == 0 {
 				return command.ShowHelp(dockerCli.Err())(cmd, args)
 			}
-			return fmt.Errorf("docker: '%s' is not a docker command.\nSee 'docker --help'", args[0])
-
+			return unknownCommandError(dockerCli, cmd, args[0])
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			return isSupported(cmd, dockerCli)
@@ -62,7 +70,9 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 	setValidateArgs(dockerCli, cmd)
 
 	// flags must be the top-level command flags, not cmd.Flags()
-	return cli.NewTopLevelCommand(cmd, dockerCli, opts, flags)
+	tcmd := cli.NewTopLevelCommand(cmd, dockerCli, opts, flags)
+	tcmd.TolerateCLIPluginsGlobalFlags()
+	return tcmd
 }
 
 func setFlagErrorFunc(dockerCli *command.DockerCli, cmd *cobra.Command) {
@@ -182,13 +192,19 @@ func setValidateArgs(dockerCli *command.DockerCli, cmd *cobra.Command) {
 	})
 }
 
-func tryPluginRun(dockerCli command.Cli, cmd *cobra.Command, subcommand string) error {
+func tryPluginRun(dockerCli *command.DockerCli, cmd *cobra.Command, subcommand string) error {
 	plugincmd, err := pluginmanager.PluginRunCommand(dockerCli, subcommand, cmd)
 	if err != nil {
 		return err
 	}
 
-	if err := plugincmd.Run(); err != nil {
+	span := dockerCli.Tracer().StartSpan("plugin " + subcommand)
+	if traceparent := dockerCli.Tracer().Traceparent(); traceparent != "" {
+		plugincmd.Env = append(plugincmd.Env, tracing.TraceparentEnvVar+"="+traceparent)
+	}
+	err = runPlugin(plugincmd)
+	span.End(err)
+	if err != nil {
 		statusCode := 1
 		exitErr, ok := err.(*exec.ExitError)
 		if !ok {
@@ -199,12 +215,59 @@ func tryPluginRun(dockerCli command.Cli, cmd *cobra.Command, subcommand string)
 		}
 		return cli.StatusError{
 			StatusCode: statusCode,
+			Code:       command.ErrorCodeForExitCode(statusCode),
 		}
 	}
 	return nil
 }
 
-func runDocker(dockerCli *command.DockerCli) error {
+// runPlugin runs the plugin command. The plugin, which is in the same process
+// group, handles the signals of the terminal such as SIGINT itself, so that
+// they do not cancel the CLI while it waits for the plugin, and the SIGTERM
+// received by the CLI is forwarded to the plugin.
+func runPlugin(plugincmd *exec.Cmd) error {
+	defer command.IgnoreCancelSignals()()
+	if err := plugincmd.Start(); err != nil {
+		return err
+	}
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case s := <-sigc:
+				_ = plugincmd.Process.Signal(s)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return plugincmd.Wait()
+}
+
+// cancellationError returns the error of a command which was cancelled by a
+// signal, summarizing the cancellation, or err if the command was not
+// cancelled.
+func cancellationError(dockerCli command.Cli, name string, err error) error {
+	sig := command.CancelSignal(dockerCli.Context())
+	if err == nil || sig == nil {
+		return err
+	}
+	if sterr, ok := err.(cli.StatusError); ok && sterr.Code != command.ErrorCodeCancelled && sterr.Code != "" {
+		return err
+	}
+	return cli.StatusError{
+		Status:     fmt.Sprintf("%s was cancelled (%s): the requests in progress to the daemon were cancelled", name, sig),
+		StatusCode: command.ExitCodeCancelled,
+		Code:       command.ErrorCodeCancelled,
+		WrappedErr: err,
+	}
+}
+
+func runDocker(dockerCli *command.DockerCli) (err error) {
 	tcmd := newDockerCommand(dockerCli)
 
 	cmd, args, err := tcmd.HandleGlobalFlags()
@@ -212,12 +275,47 @@ func runDocker(dockerCli *command.DockerCli) error {
 		return err
 	}
 
+	// The failures to initialize the CLI are also audited, once the
+	// configuration file with the settings of the audit log is loaded
+	defer func() {
+		if configFile := dockerCli.ConfigFile(); configFile != nil {
+			auditCommand(dockerCli, audit.New(configFile.AuditLog), cmd, args, err)
+		}
+	}()
+
 	if err := tcmd.Initialize(); err != nil {
+		return debug.InitializationError(err)
+	}
+	i18n.TranslateCommand(cmd, i18n.Default())
+
+	args, err = expandAliases(dockerCli, tcmd, cmd, args)
+	if err != nil {
 		return err
 	}
 
+	args, err = expandRunPresets(dockerCli, tcmd, cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if err := areContextsSupported(dockerCli, cmd, args); err != nil {
+		return err
+	}
+
+	if err := setPluginInvocationContext(pluginmanager.NewInvocationContext(dockerCli, tcmd.GlobalArgs())); err != nil {
+		return err
+	}
+
+	span := dockerCli.Tracer().StartSpan(commandSpanName(cmd, args))
+	span.SetAttribute("docker.context", dockerCli.CurrentContext())
+	defer func() {
+		err = cancellationError(dockerCli, commandSpanName(cmd, args), err)
+		span.End(err)
+		dockerCli.Tracer().Flush()
+	}()
+
 	if len(args) > 0 {
-		if _, _, err := cmd.Find(args); err != nil {
+		if group, groupArgs, err := cmd.Find(args); err != nil {
 			err := tryPluginRun(dockerCli, cmd, args[0])
 			if !pluginmanager.IsNotFound(err) {
 				return err
@@ -225,14 +323,184 @@ func runDocker(dockerCli *command.DockerCli) error {
 			// For plugin not found we fall through to
 			// cmd.Execute() which deals with reporting
 			// "command not found" in a consistent way.
+		} else if group != cmd && group.HasSubCommands() && len(groupArgs) > 0 && !strings.HasPrefix(groupArgs[0], "-") {
+			err := tryPluginSubcommandRun(dockerCli, tcmd, group, groupArgs)
+			if !pluginmanager.IsNotFound(err) {
+				return err
+			}
 		}
 	}
 
 	return cmd.Execute()
 }
 
+// unknownCommandError returns the error of an unknown command, which suggests
+// the builtin commands and the installed plugins with a close name, or how to
+// install the plugin of the command, if it is a well-known plugin.
+func unknownCommandError(dockerCli command.Cli, cmd *cobra.Command, name string) error {
+	msg := fmt.Sprintf(i18n.T("docker: '%s' is not a docker command."), name)
+	if url, ok := pluginmanager.WellKnownPlugin(name); ok {
+		msg += "\n" + fmt.Sprintf(i18n.T("The %s CLI plugin is not installed: see %s to install it."), name, url)
+	}
+	msg += "\n" + i18n.T("See 'docker --help'")
+	if suggestions := pluginmanager.SuggestCommands(dockerCli, cmd.Root(), name); len(suggestions) > 0 {
+		msg += "\n\n" + i18n.T("Did you mean this?")
+		for _, s := range suggestions {
+			msg += "\n\t" + s
+		}
+	}
+	return errors.New(msg)
+}
+
+// areContextsSupported checks that the command supports running against several
+// contexts, if several contexts are selected with the --context flag. Such
+// commands, or one of their ancestors, have the "multiContext" annotation.
+func areContextsSupported(dockerCli command.Cli, cmd *cobra.Command, args []string) error {
+	if len(dockerCli.SelectedContextClients()) == 0 || len(args) == 0 {
+		return nil
+	}
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" {
+			return nil
+		}
+	}
+	name := args[0]
+	if target, _, err := cmd.Find(args); err == nil {
+		if target.Name() == "help" {
+			return nil
+		}
+		for curr := target; curr != nil; curr = curr.Parent() {
+			if _, ok := curr.Annotations["multiContext"]; ok {
+				return nil
+			}
+		}
+		name = strings.TrimPrefix(target.CommandPath(), cmd.Root().Name()+" ")
+	}
+	return fmt.Errorf("\"docker %s\" does not support several contexts: use a single context with --context, or one of \"docker ps\", \"docker images\", and \"docker info\"", name)
+}
+
+// tryPluginSubcommandRun runs the plugin providing the subcommand of a builtin
+// command group, such as "docker image sign", if any. The plugin is invoked
+// as if its own command was used, such as "docker imagesign", with the path
+// of the subcommand in its invocation context.
+func tryPluginSubcommandRun(dockerCli *command.DockerCli, tcmd *cli.TopLevelCommand, group *cobra.Command, args []string) error {
+	path := append(strings.Fields(group.CommandPath())[1:], args[0])
+	subcommand, err := pluginmanager.FindPluginSubcommand(dockerCli, group.Root(), path)
+	if err != nil {
+		return err
+	}
+	if err := setPluginInvocationContext(subcommand.InvocationContext(dockerCli, tcmd.GlobalArgs())); err != nil {
+		return err
+	}
+	fullArgs := tcmd.SetCommandArgs(append([]string{subcommand.Plugin.Name}, args[1:]...))
+	// Plugins are invoked with os.Args
+	os.Args = append(os.Args[:1], fullArgs...)
+	return tryPluginRun(dockerCli, group.Root(), subcommand.Plugin.Name)
+}
+
+// setPluginInvocationContext sets the environment variable passing the
+// invocation context of the CLI to the plugins, which inherit the environment
+// of the CLI.
+func setPluginInvocationContext(invocation pluginmanager.InvocationContext) error {
+	value, err := invocation.Encode()
+	if err != nil {
+		return err
+	}
+	return os.Setenv(pluginmanager.InvocationContextEnvvar, value)
+}
+
+// expandAliases expands the alias used as command, if any, before the
+// command or plugin is looked up.
+func expandAliases(dockerCli *command.DockerCli, tcmd *cli.TopLevelCommand, cmd *cobra.Command, args []string) ([]string, error) {
+	aliases := dockerCli.ConfigFile().Aliases
+	if len(aliases) == 0 || len(args) == 0 {
+		return args, nil
+	}
+	expanded, err := alias.Expand(aliases, args, func(name string) bool {
+		_, _, err := cmd.Find([]string{name})
+		return err == nil
+	})
+	if err != nil || expanded[0] == args[0] {
+		return args, err
+	}
+	fullArgs := tcmd.SetCommandArgs(expanded)
+	// Plugins are invoked with os.Args, which must include the expanded alias
+	os.Args = append(os.Args[:1], fullArgs...)
+	return expanded, nil
+}
+
+// expandRunPresets replaces the --preset flags of the commands which have
+// them, such as "docker run", with the flags of their presets in the
+// configuration file.
+func expandRunPresets(dockerCli command.Cli, tcmd *cli.TopLevelCommand, cmd *cobra.Command, args []string) ([]string, error) {
+	target, targetArgs, err := cmd.Find(args)
+	if err != nil || target.Flags().Lookup(opts.PresetFlag) == nil {
+		return args, nil
+	}
+	// The arguments of the command follow its name, unless flags of its
+	// parents are in between, which are not supported
+	n := len(args) - len(targetArgs)
+	if n < 0 || !reflect.DeepEqual(args[n:], targetArgs) {
+		return args, nil
+	}
+	expanded, err := opts.ExpandPresets(dockerCli.ConfigFile().RunPresets, targetArgs, target.Flags())
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(expanded, targetArgs) {
+		return args, nil
+	}
+	expanded = append(append([]string{}, args[:n]...), expanded...)
+	tcmd.SetCommandArgs(expanded)
+	return expanded, nil
+}
+
+// commandSpanName returns the name of the span of the execution of the
+// command, such as "docker container ls"
+func commandSpanName(cmd *cobra.Command, args []string) string {
+	if c, _, err := cmd.Find(args); err == nil {
+		return c.CommandPath()
+	}
+	if len(args) > 0 {
+		return cmd.CommandPath() + " " + args[0]
+	}
+	return cmd.CommandPath()
+}
+
+// auditCommand appends the record of the execution of the command, or of
+// the plugin, to the audit log. Failures to write the audit log are reported
+// as warnings.
+func auditCommand(dockerCli command.Cli, auditLog *audit.Logger, cmd *cobra.Command, args []string, err error) {
+	context := dockerCli.CurrentContext()
+	if context == "" {
+		context = command.DefaultContextName
+	}
+	record := audit.Record{
+		Context:  context,
+		Command:  commandSpanName(cmd, args),
+		Args:     args,
+		ExitCode: exitCode(err),
+	}
+	if err := auditLog.Log(record); err != nil {
+		command.PrintWarning(dockerCli, "%v", err)
+	}
+}
+
+// exitCode returns the exit code of the CLI for the error of a command
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if sterr := cli.NewStatusError(err); sterr.StatusCode != 0 {
+		return sterr.StatusCode
+	}
+	return 1
+}
+
 func main() {
-	dockerCli, err := command.NewDockerCli()
+	ctx, cancel := command.NotifyContext(context.Background())
+	defer cancel()
+	dockerCli, err := command.NewDockerCli(command.WithRootContext(ctx))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -242,8 +510,9 @@ func main() {
 	if err := runDocker(dockerCli); err != nil {
 		if sterr, ok := err.(cli.StatusError); ok {
 			if sterr.Status != "" {
-				fmt.Fprintln(dockerCli.Err(), sterr.Status)
+				command.PrintError(dockerCli, errors.New(sterr.Status))
 			}
+			printDiagnostics(dockerCli, err)
 			// StatusError should only be used for errors, and all errors should
 			// have a non-zero exit status, so never exit with 0
 			if sterr.StatusCode == 0 {
@@ -251,8 +520,17 @@ func main() {
 			}
 			os.Exit(sterr.StatusCode)
 		}
-		fmt.Fprintln(dockerCli.Err(), err)
-		os.Exit(1)
+		command.PrintError(dockerCli, err)
+		printDiagnostics(dockerCli, err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// printDiagnostics prints how to fix the connection to the daemon if err is a
+// failure to connect to it.
+func printDiagnostics(dockerCli command.Cli, err error) {
+	if debug.IsConnectionFailure(err) {
+		debug.PrintFindings(dockerCli.Err(), debug.DiagnoseConnection(dockerCli))
 	}
 }
 
@@ -2,16 +2,27 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/audit"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/debug"
+	"github.com/docker/cli/internal/test"
 	"github.com/sirupsen/logrus"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/fs"
+	"gotest.tools/skip"
 )
 
 func TestClientDebugEnabled(t *testing.T) {
@@ -58,9 +69,150 @@ func TestExitStatusForInvalidSubcommand(t *testing.T) {
 	assert.Check(t, is.ErrorContains(err, "docker: 'invalid' is not a docker command."))
 }
 
+func TestInvalidSubcommandSuggestions(t *testing.T) {
+	err := runCliCommand(t, nil, nil, "contaner")
+	assert.Check(t, is.ErrorContains(err, "docker: 'contaner' is not a docker command.\nSee 'docker --help'\n\nDid you mean this?\n\tcontainer"))
+
+	err = runCliCommand(t, nil, nil, "buildx")
+	assert.Check(t, is.ErrorContains(err, "docker: 'buildx' is not a docker command.\nThe buildx CLI plugin is not installed: see https://github.com/docker/buildx#installing to install it."))
+}
+
 func TestVersion(t *testing.T) {
 	var b bytes.Buffer
 	err := runCliCommand(t, nil, &b, "--version")
 	assert.NilError(t, err)
 	assert.Check(t, is.Contains(b.String(), "Docker version"))
 }
+
+func TestExpandAliases(t *testing.T) {
+	defer func(args []string) { os.Args = args }(os.Args)
+	defer config.SetDir(config.Dir())
+	dir := fs.NewDir(t, "config", fs.WithFile("config.json", `{"aliases": {"hi": "help invalid"}}`))
+	defer dir.Remove()
+
+	cli, err := command.NewDockerCli(command.WithInputStream(discard), command.WithCombinedStreams(ioutil.Discard))
+	assert.NilError(t, err)
+	tcmd := newDockerCommand(cli)
+	tcmd.SetArgs([]string{"--config", dir.Path(), "hi"})
+	cmd, args, err := tcmd.HandleGlobalFlags()
+	assert.NilError(t, err)
+	assert.NilError(t, tcmd.Initialize())
+
+	args, err = expandAliases(cli, tcmd, cmd, args)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"help", "invalid"}, args))
+	assert.Check(t, is.DeepEqual([]string{"--config", dir.Path(), "help", "invalid"}, os.Args[1:]))
+	assert.Check(t, is.Error(cmd.Execute(), "unknown help topic: invalid"))
+}
+
+func TestExpandRunPresets(t *testing.T) {
+	defer config.SetDir(config.Dir())
+	dir := fs.NewDir(t, "config", fs.WithFile("config.json", `{"runPresets": {"dev": "-v /src:/src --security-opt no-new-privileges", "nested": "--preset dev"}}`))
+	defer dir.Remove()
+
+	cli, err := command.NewDockerCli(command.WithInputStream(discard), command.WithCombinedStreams(ioutil.Discard))
+	assert.NilError(t, err)
+	tcmd := newDockerCommand(cli)
+	tcmd.SetArgs([]string{"--config", dir.Path(), "container", "run", "--preset", "dev", "-e", "A=1", "busybox", "--preset", "dev"})
+	cmd, args, err := tcmd.HandleGlobalFlags()
+	assert.NilError(t, err)
+	assert.NilError(t, tcmd.Initialize())
+
+	args, err = expandRunPresets(cli, tcmd, cmd, args)
+	assert.NilError(t, err)
+	expected := []string{"container", "run", "-v", "/src:/src", "--security-opt", "no-new-privileges", "-e", "A=1", "busybox", "--preset", "dev"}
+	assert.Check(t, is.DeepEqual(expected, args))
+
+	// The commands without the --preset flag are not expanded
+	args, err = expandRunPresets(cli, tcmd, cmd, []string{"ps", "--preset", "dev"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"ps", "--preset", "dev"}, args))
+
+	_, err = expandRunPresets(cli, tcmd, cmd, []string{"create", "--preset=unknown", "busybox"})
+	assert.Check(t, is.Error(err, "no such preset: unknown"))
+	_, err = expandRunPresets(cli, tcmd, cmd, []string{"run", "--preset", "nested", "busybox"})
+	assert.Check(t, is.Error(err, `invalid preset "nested": a preset cannot apply other presets`))
+}
+
+func TestAreContextsSupported(t *testing.T) {
+	tcmd := newDockerCommand(&command.DockerCli{})
+	cmd, _, err := tcmd.HandleGlobalFlags()
+	assert.NilError(t, err)
+
+	cli := test.NewFakeCli(nil)
+	assert.NilError(t, areContextsSupported(cli, cmd, []string{"run", "busybox"}))
+
+	cli.SetSelectedContextClients([]command.ContextAPIClient{{Name: "default"}, {Name: "prod"}})
+	for _, args := range [][]string{{"ps", "-a"}, {"container", "ls"}, {"images"}, {"system", "info"}, {"run", "--help"}} {
+		assert.Check(t, areContextsSupported(cli, cmd, args), args)
+	}
+	assert.Check(t, is.ErrorContains(areContextsSupported(cli, cmd, []string{"container", "run", "busybox"}), `"docker container run" does not support several contexts`))
+	assert.Check(t, is.ErrorContains(areContextsSupported(cli, cmd, []string{"someplugin"}), `"docker someplugin" does not support several contexts`))
+}
+
+func TestAuditLog(t *testing.T) {
+	defer func(args []string) { os.Args = args }(os.Args)
+	defer config.SetDir(config.Dir())
+	dir := fs.NewDir(t, "config")
+	defer dir.Remove()
+	auditFile := filepath.Join(dir.Path(), "audit.log")
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir.Path(), "config.json"), []byte(`{"auditLog": {"file": "`+filepath.ToSlash(auditFile)+`"}, "aliases": {"hi": "help invalid"}}`), 0600))
+
+	cli, err := command.NewDockerCli(command.WithInputStream(discard), command.WithCombinedStreams(ioutil.Discard))
+	assert.NilError(t, err)
+	os.Args = []string{"docker", "--config", dir.Path(), "hi"}
+	assert.Check(t, is.ErrorContains(runDocker(cli), "unknown help topic: invalid"))
+
+	data, err := ioutil.ReadFile(auditFile)
+	assert.NilError(t, err)
+	var record audit.Record
+	assert.NilError(t, json.Unmarshal(data, &record))
+	assert.Check(t, is.Equal(record.Command, "docker help"))
+	assert.Check(t, is.DeepEqual(record.Args, []string{"help", "invalid"}))
+	assert.Check(t, is.Equal(record.Context, "default"))
+	assert.Check(t, is.Equal(record.ExitCode, 1))
+}
+
+func TestAuditLogInitializationError(t *testing.T) {
+	defer func(args []string) { os.Args = args }(os.Args)
+	defer config.SetDir(config.Dir())
+	dir := fs.NewDir(t, "config")
+	defer dir.Remove()
+	auditFile := filepath.Join(dir.Path(), "audit.log")
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir.Path(), "config.json"), []byte(`{"auditLog": {"file": "`+filepath.ToSlash(auditFile)+`"}, "theme": {"error": "invalid"}}`), 0600))
+
+	cli, err := command.NewDockerCli(command.WithInputStream(discard), command.WithCombinedStreams(ioutil.Discard))
+	assert.NilError(t, err)
+	os.Args = []string{"docker", "--config", dir.Path(), "login", "-p", "secret"}
+	assert.Check(t, is.ErrorContains(runDocker(cli), "invalid color"))
+
+	data, err := ioutil.ReadFile(auditFile)
+	assert.NilError(t, err)
+	var record audit.Record
+	assert.NilError(t, json.Unmarshal(data, &record))
+	assert.Check(t, is.DeepEqual(record.Args, []string{"login", "-p", "<redacted>"}))
+	assert.Check(t, is.Equal(record.ExitCode, 1))
+}
+
+func TestCancellationError(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "signals cannot be sent to the process on Windows")
+	ctx, cancel := command.NotifyContext(context.Background())
+	defer cancel()
+	dockerCli, err := command.NewDockerCli(command.WithRootContext(ctx), command.WithCombinedStreams(ioutil.Discard))
+	assert.NilError(t, err)
+	assert.Check(t, is.Error(cancellationError(dockerCli, "docker pull", errors.New("boom")), "boom"))
+
+	p, err := os.FindProcess(os.Getpid())
+	assert.NilError(t, err)
+	assert.NilError(t, p.Signal(os.Interrupt))
+	<-ctx.Done()
+
+	assert.NilError(t, cancellationError(dockerCli, "docker pull", nil))
+	err = cancellationError(dockerCli, "docker pull", context.Canceled)
+	sterr, ok := err.(cli.StatusError)
+	assert.Assert(t, ok, "%T", err)
+	assert.Check(t, is.Equal(sterr.Status, "docker pull was cancelled (interrupt): the requests in progress to the daemon were cancelled"))
+	assert.Check(t, is.Equal(sterr.StatusCode, command.ExitCodeCancelled))
+	assert.Check(t, is.Equal(sterr.Code, command.ErrorCodeCancelled))
+	assert.Check(t, is.Equal(exitCode(err), command.ExitCodeCancelled))
+}
@@ -24,3 +24,16 @@ func TestDialStdio(t *testing.T) {
 	assert.Assert(t, is.Contains(res.Stderr(), `msg="commandconn: starting /bin/true with [--config=blah --tls --log-level debug system dial-stdio]"`))
 	assert.Assert(t, is.Equal(res.Stdout(), "Hello foo!\n"))
 }
+
+func TestDialStdioInvocationContext(t *testing.T) {
+	// The global arguments are taken from the invocation context passed
+	// by the CLI, if any, rather than from the arguments of the plugin.
+	helloworld := filepath.Join(os.Getenv("DOCKER_CLI_E2E_PLUGINS_EXTRA_DIRS"), "docker-helloworld")
+	cmd := icmd.Command(helloworld, "--log-level", "debug", "helloworld", "--who=foo")
+	res := icmd.RunCmd(cmd, icmd.WithEnv(
+		manager.ReexecEnvvar+"=/bin/true",
+		manager.InvocationContextEnvvar+`={"GlobalArgs":["--context","remote","--log-level","debug"]}`,
+	))
+	res.Assert(t, icmd.Success)
+	assert.Assert(t, is.Contains(res.Stderr(), `msg="commandconn: starting /bin/true with [--context remote --log-level debug system dial-stdio]"`))
+}
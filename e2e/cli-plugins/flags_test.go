@@ -87,6 +87,58 @@ func TestUnknownGlobal(t *testing.T) {
 	}
 }
 
+// TestPluginGlobalFlags checks that the global options added by a plugin
+// are accepted before the name of the plugin, and only for that plugin
+func TestPluginGlobalFlags(t *testing.T) {
+	run, _, cleanup := prepare(t)
+	defer cleanup()
+
+	for _, tc := range []struct {
+		name           string
+		args           []string
+		expCode        int
+		expOut, expErr string
+	}{
+		{
+			name:    "separate-val",
+			args:    []string{"--greeting", "Howdy", "helloworld"},
+			expCode: 0,
+			expOut:  "Howdy World!",
+			expErr:  icmd.None,
+		},
+		{
+			name:    "joined-val",
+			args:    []string{"--greeting=Howdy", "helloworld", "--who", "Cleveland"},
+			expCode: 0,
+			expOut:  "Howdy Cleveland!",
+			expErr:  icmd.None,
+		},
+		{
+			name:    "with-cli-global",
+			args:    []string{"--log-level", "error", "--greeting", "Howdy", "helloworld"},
+			expCode: 0,
+			expOut:  "Howdy World!",
+			expErr:  icmd.None,
+		},
+		{
+			name:    "builtin-command",
+			args:    []string{"--greeting", "Howdy", "version"},
+			expCode: 125,
+			expOut:  icmd.None,
+			expErr:  "unknown flag: --greeting",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := icmd.RunCmd(run(tc.args...))
+			res.Assert(t, icmd.Expected{
+				ExitCode: tc.expCode,
+				Out:      tc.expOut,
+				Err:      tc.expErr,
+			})
+		})
+	}
+}
+
 // TestCliPluginsVersion checks that `-v` and friends DTRT
 func TestCliPluginsVersion(t *testing.T) {
 	run, _, cleanup := prepare(t)
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/commands"
+	"github.com/docker/cli/cli/i18n"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// generateCatalog writes the messages of the CLI to the catalog of the target,
+// keeping the translations of the messages which are already in the catalog.
+func generateCatalog(opts *options) error {
+	dockerCli, err := command.NewDockerCli()
+	if err != nil {
+		return err
+	}
+	cmd := &cobra.Command{Use: "docker"}
+	cli.SetupRootCommand(cmd)
+	commands.AddCommands(cmd, dockerCli)
+	cmd.InitDefaultHelpFlag()
+
+	messages := i18n.CommandMessages(cmd)
+	for _, dir := range []string{"cli", "cmd"} {
+		m, err := i18n.SourceMessages(filepath.Join(opts.source, dir))
+		if err != nil {
+			return err
+		}
+		messages = append(messages, m...)
+	}
+
+	catalog := map[string]string{}
+	if content, err := ioutil.ReadFile(opts.target); err == nil {
+		if err := json.Unmarshal(content, &catalog); err != nil {
+			return fmt.Errorf("invalid message catalog %s: %s", opts.target, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	translations := map[string]string{}
+	for _, msg := range messages {
+		translations[msg] = catalog[msg]
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(translations); err != nil {
+		return err
+	}
+	fmt.Printf("Writing %d messages to %s\n", len(translations), opts.target)
+	return ioutil.WriteFile(opts.target, buf.Bytes(), 0644)
+}
+
+type options struct {
+	source string
+	target string
+}
+
+func parseArgs() (*options, error) {
+	opts := &options{}
+	cwd, _ := os.Getwd()
+	flags := pflag.NewFlagSet(os.Args[0], pflag.ContinueOnError)
+	flags.StringVar(&opts.source, "root", cwd, "Path to project root")
+	flags.StringVar(&opts.target, "target", "i18n/messages.json", "Path to the message catalog to generate or update")
+	err := flags.Parse(os.Args[1:])
+	return opts, err
+}
+
+func main() {
+	opts, err := parseArgs()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if err := generateCatalog(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate the message catalog: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
@@ -44,6 +44,9 @@ type FakeCli struct {
 	contextStore                  store.Store
 	currentContext                string
 	dockerEndpoint                docker.Endpoint
+	selectedContexts              []command.ContextAPIClient
+	colorPolicy                   streams.ColorPolicy
+	noTrunc                       bool
 }
 
 // NewFakeCli returns a fake for the command.Cli interface
@@ -101,6 +104,32 @@ func (c *FakeCli) SetDockerEndpoint(ep docker.Endpoint) {
 	c.dockerEndpoint = ep
 }
 
+// SetSelectedContextClients sets the "fake" clients of the contexts selected
+// with the --context flag
+func (c *FakeCli) SetSelectedContextClients(clients []command.ContextAPIClient) {
+	c.selectedContexts = clients
+}
+
+// SetColorPolicy sets the "fake" color policy
+func (c *FakeCli) SetColorPolicy(policy streams.ColorPolicy) {
+	c.colorPolicy = policy
+}
+
+// ColorPolicy returns the color policy of the cli
+func (c *FakeCli) ColorPolicy() streams.ColorPolicy {
+	return c.colorPolicy
+}
+
+// SetNoTrunc sets the "fake" global --no-trunc flag
+func (c *FakeCli) SetNoTrunc(noTrunc bool) {
+	c.noTrunc = noTrunc
+}
+
+// NoTrunc returns whether the global --no-trunc flag of the cli is set
+func (c *FakeCli) NoTrunc() bool {
+	return c.noTrunc
+}
+
 // Client returns a docker API client
 func (c *FakeCli) Client() client.APIClient {
 	return c.client
@@ -141,11 +170,22 @@ func (c *FakeCli) DockerEndpoint() docker.Endpoint {
 	return c.dockerEndpoint
 }
 
+// SelectedContextClients returns the clients of the contexts selected with the
+// --context flag
+func (c *FakeCli) SelectedContextClients() []command.ContextAPIClient {
+	return c.selectedContexts
+}
+
 // ServerInfo returns API server information for the server used by this client
 func (c *FakeCli) ServerInfo() command.ServerInfo {
 	return c.server
 }
 
+// SetServerInfo sets the API server information
+func (c *FakeCli) SetServerInfo(server command.ServerInfo) {
+	c.server = server
+}
+
 // ClientInfo returns client information
 func (c *FakeCli) ClientInfo() command.ClientInfo {
 	if c.clientInfoFunc != nil {
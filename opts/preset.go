@@ -0,0 +1,142 @@
+package opts
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	shellwords "github.com/mattn/go-shellwords"
+	"github.com/spf13/pflag"
+)
+
+// PresetFlag is the flag of "docker run" and "docker create" which applies the
+// flags of a preset
+const PresetFlag = "preset"
+
+const presetNamePattern = "^[a-zA-Z0-9][a-zA-Z0-9_.+-]*$"
+
+var presetNameRegEx = regexp.MustCompile(presetNamePattern)
+
+// ValidatePresetName validates the name of a preset
+func ValidatePresetName(name string) error {
+	if !presetNameRegEx.MatchString(name) {
+		return fmt.Errorf("preset name %q is invalid, names are validated against regexp %q", name, presetNamePattern)
+	}
+	return nil
+}
+
+// ParsePreset returns the flags of a preset, such as
+// "--volume /data:/data --security-opt no-new-privileges", as separate
+// arguments. The preset must only contain flags of the flag set, with their
+// values, and cannot apply other presets.
+func ParsePreset(value string, flags *pflag.FlagSet) ([]string, error) {
+	words, err := shellwords.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" || !strings.HasPrefix(word, "-") || word == "-" {
+			return nil, fmt.Errorf("unexpected argument %q: a preset only contains flags", word)
+		}
+		if isPresetFlag(word) {
+			return nil, fmt.Errorf("a preset cannot apply other presets")
+		}
+		takesValue, err := flagTakesValue(word, flags)
+		if err != nil {
+			return nil, err
+		}
+		if takesValue {
+			if i+1 == len(words) {
+				return nil, fmt.Errorf("flag needs an argument: %s", word)
+			}
+			i++
+		}
+	}
+	return words, nil
+}
+
+// ExpandPresets replaces the --preset flags of the arguments of a command,
+// such as "docker run", with the flags of their presets. Only the flags
+// before the first argument which is not a flag, such as the image of
+// "docker run", are expanded. The flags of a preset are overridden by the
+// flags after it on the command line, except for the flags which can be
+// repeated, such as --volume.
+func ExpandPresets(presets map[string]string, args []string, flags *pflag.FlagSet) ([]string, error) {
+	var expanded []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") || arg == "-" {
+			return append(expanded, args[i:]...), nil
+		}
+		if !isPresetFlag(arg) {
+			expanded = append(expanded, arg)
+			takesValue, err := flagTakesValue(arg, flags)
+			if err == nil && takesValue && i+1 < len(args) {
+				expanded = append(expanded, args[i+1])
+				i++
+			}
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, "--"+PresetFlag+"=")
+		if arg == "--"+PresetFlag {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("flag needs an argument: --%s", PresetFlag)
+			}
+			name = args[i+1]
+			i++
+		}
+		value, ok := presets[name]
+		if !ok {
+			return nil, fmt.Errorf("no such preset: %s", name)
+		}
+		words, err := ParsePreset(value, flags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preset %q: %v", name, err)
+		}
+		expanded = append(expanded, words...)
+	}
+	return expanded, nil
+}
+
+func isPresetFlag(arg string) bool {
+	return arg == "--"+PresetFlag || strings.HasPrefix(arg, "--"+PresetFlag+"=")
+}
+
+// flagTakesValue returns whether the flag of the argument, such as "--volume"
+// or "-v", is followed by its value in the next argument
+func flagTakesValue(arg string, flags *pflag.FlagSet) (bool, error) {
+	if strings.HasPrefix(arg, "--") {
+		name := strings.TrimPrefix(arg, "--")
+		if strings.Contains(name, "=") {
+			name = name[:strings.Index(name, "=")]
+			if flags.Lookup(name) == nil {
+				return false, fmt.Errorf("unknown flag: --%s", name)
+			}
+			return false, nil
+		}
+		f := flags.Lookup(name)
+		if f == nil {
+			return false, fmt.Errorf("unknown flag: --%s", name)
+		}
+		return f.NoOptDefVal == "", nil
+	}
+	// Shorthands can be combined, such as "-it", and the last one may take
+	// the value in the next argument, or in the rest of the argument, such
+	// as "-p80:80"
+	shorthands := strings.TrimPrefix(arg, "-")
+	for i := 0; i < len(shorthands); i++ {
+		if shorthands[i] == '=' {
+			return false, nil
+		}
+		f := flags.ShorthandLookup(shorthands[i : i+1])
+		if f == nil {
+			return false, fmt.Errorf("unknown shorthand flag: %q in %s", shorthands[i], arg)
+		}
+		if f.NoOptDefVal == "" {
+			return i == len(shorthands)-1, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,101 @@
+package opts
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newPresetTestFlags() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("run", pflag.ContinueOnError)
+	flags.StringArrayP("volume", "v", nil, "")
+	flags.StringArrayP("env", "e", nil, "")
+	flags.StringArray("security-opt", nil, "")
+	flags.StringArray(PresetFlag, nil, "")
+	flags.BoolP("interactive", "i", false, "")
+	flags.BoolP("tty", "t", false, "")
+	flags.Bool("rm", false, "")
+	return flags
+}
+
+func TestValidatePresetName(t *testing.T) {
+	for _, name := range []string{"dev", "Dev-2", "go1.12", "a_b+c"} {
+		assert.Check(t, ValidatePresetName(name), name)
+	}
+	for _, name := range []string{"", "-dev", ".dev", "a b", "a/b"} {
+		assert.Check(t, is.ErrorContains(ValidatePresetName(name), "is invalid"), name)
+	}
+}
+
+func TestParsePreset(t *testing.T) {
+	flags := newPresetTestFlags()
+	words, err := ParsePreset(`-it --rm -v "/my data:/data" -e=A=1 --security-opt=no-new-privileges -eB=2`, flags)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual([]string{"-it", "--rm", "-v", "/my data:/data", "-e=A=1", "--security-opt=no-new-privileges", "-eB=2"}, words))
+
+	testCases := []struct {
+		value       string
+		expectedErr string
+	}{
+		{value: "-v /data:/data busybox", expectedErr: `unexpected argument "busybox": a preset only contains flags`},
+		{value: "--rm -- busybox", expectedErr: `unexpected argument "--": a preset only contains flags`},
+		{value: "--preset=dev", expectedErr: "a preset cannot apply other presets"},
+		{value: "--unknown", expectedErr: "unknown flag: --unknown"},
+		{value: "-x", expectedErr: `unknown shorthand flag: 'x' in -x`},
+		{value: "--rm -v", expectedErr: "flag needs an argument: -v"},
+		{value: `-e "A=1`, expectedErr: "invalid command line string"},
+	}
+	for _, tc := range testCases {
+		_, err := ParsePreset(tc.value, flags)
+		assert.Check(t, is.Error(err, tc.expectedErr), tc.value)
+	}
+}
+
+func TestExpandPresets(t *testing.T) {
+	flags := newPresetTestFlags()
+	presets := map[string]string{
+		"dev":  "-v /src:/src -e DEBUG=1",
+		"safe": "--security-opt no-new-privileges",
+	}
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"busybox"},
+			expected: []string{"busybox"},
+		},
+		{
+			args:     []string{"--preset", "dev", "-e", "DEBUG=0", "--preset=safe", "busybox", "sh"},
+			expected: []string{"-v", "/src:/src", "-e", "DEBUG=1", "-e", "DEBUG=0", "--security-opt", "no-new-privileges", "busybox", "sh"},
+		},
+		{
+			// The value of a flag is not a preset flag
+			args:     []string{"-e", "--preset", "--preset", "dev", "busybox"},
+			expected: []string{"-e", "--preset", "-v", "/src:/src", "-e", "DEBUG=1", "busybox"},
+		},
+		{
+			// The flags after the image are arguments of the command
+			args:     []string{"-it", "busybox", "--preset", "dev"},
+			expected: []string{"-it", "busybox", "--preset", "dev"},
+		},
+		{
+			args:     []string{"--rm", "--", "--preset", "dev"},
+			expected: []string{"--rm", "--", "--preset", "dev"},
+		},
+	}
+	for _, tc := range testCases {
+		expanded, err := ExpandPresets(presets, tc.args, flags)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(tc.expected, expanded), tc.args)
+	}
+
+	_, err := ExpandPresets(presets, []string{"--preset", "unknown", "busybox"}, flags)
+	assert.Check(t, is.Error(err, "no such preset: unknown"))
+	_, err = ExpandPresets(presets, []string{"--preset"}, flags)
+	assert.Check(t, is.Error(err, "flag needs an argument: --preset"))
+	_, err = ExpandPresets(map[string]string{"bad": "busybox"}, []string{"--preset=bad"}, flags)
+	assert.Check(t, is.Error(err, `invalid preset "bad": unexpected argument "busybox": a preset only contains flags`))
+}
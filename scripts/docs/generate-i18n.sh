@@ -0,0 +1,10 @@
+#!/usr/bin/env bash
+# Generate or update a message catalog of the CLI, with the messages to
+# translate, such as "make TARGET=~/.docker/locales/fr.json i18n-catalog"
+set -eu -o pipefail
+
+target=${TARGET:-"$(pwd)/i18n/messages.json"}
+mkdir -p "$(dirname "$target")"
+
+go build -o build/i18n-catalog-generator github.com/docker/cli/i18n
+build/i18n-catalog-generator --root "$(pwd)" --target "$target"
@@ -3,10 +3,23 @@ package templates
 import (
 	"bytes"
 	"encoding/json"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
 )
 
+// NamedTemplatePrefix is the prefix of the formats which reference a named
+// template, such as "template:images" for the template named "images".
+const NamedTemplatePrefix = "template:"
+
+// namedTemplates are the named templates, by name
+var namedTemplates map[string]string
+
 // basicFunctions are the set of initial
 // functions provided to every template.
 var basicFunctions = template.FuncMap{
@@ -25,6 +38,11 @@ var basicFunctions = template.FuncMap{
 	"upper":    strings.ToUpper,
 	"pad":      padWithSpace,
 	"truncate": truncateWithLength,
+
+	"path":          pathValue,
+	"humanizeBytes": humanizeBytes,
+	"formatDate":    formatDate,
+	"regexReplace":  regexReplace,
 }
 
 // HeaderFunctions are used to created headers of a table.
@@ -47,6 +65,38 @@ var HeaderFunctions = template.FuncMap{
 	"truncate": func(v string, _ int) string {
 		return v
 	},
+	"humanizeBytes": func(v interface{}) interface{} {
+		return v
+	},
+	"formatDate": func(v interface{}, _ string) interface{} {
+		return v
+	},
+	"regexReplace": func(v string, _, _ string) string {
+		return v
+	},
+}
+
+// SetNamedTemplates sets the named templates, such as the templates of the
+// configuration file. The formats referencing a named template with the
+// NamedTemplatePrefix are replaced by the template, and the templates can be
+// executed by the other templates with the "template" action.
+func SetNamedTemplates(templates map[string]string) {
+	namedTemplates = templates
+}
+
+// Expand returns the named template referenced by a format with the
+// NamedTemplatePrefix, or the format if it does not reference a named
+// template. It returns an error if the named template does not exist.
+func Expand(format string) (string, error) {
+	if !strings.HasPrefix(format, NamedTemplatePrefix) {
+		return format, nil
+	}
+	name := strings.TrimPrefix(format, NamedTemplatePrefix)
+	tmpl, ok := namedTemplates[name]
+	if !ok {
+		return "", errors.Errorf("template %q is not defined", name)
+	}
+	return tmpl, nil
 }
 
 // Parse creates a new anonymous template with the basic functions
@@ -62,9 +112,20 @@ func New(tag string) *template.Template {
 }
 
 // NewParse creates a new tagged template with the basic functions
-// and parses the given format.
+// and parses the given format. The format can reference a named template, and
+// the named templates are associated with the template.
 func NewParse(tag, format string) (*template.Template, error) {
-	return New(tag).Parse(format)
+	format, err := Expand(format)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := New(tag)
+	for name, text := range namedTemplates {
+		if _, err := tmpl.New(name).Parse(text); err != nil {
+			return nil, errors.Wrapf(err, "invalid template %q", name)
+		}
+	}
+	return tmpl.Parse(format)
 }
 
 // padWithSpace adds whitespace to the input if the input is non-empty
@@ -82,3 +143,150 @@ func truncateWithLength(source string, length int) string {
 	}
 	return source[:length]
 }
+
+// pathValue returns the value at a path of the JSON encoding of v, such as
+// ".Config.Labels" or ".Mounts[0].Source". The keys with special characters
+// are quoted, such as .Labels["com.example.version"]. It returns nil if the
+// value does not exist.
+func pathValue(v interface{}, path string) (interface{}, error) {
+	keys, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		switch x := value.(type) {
+		case map[string]interface{}:
+			value = x[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, nil
+			}
+			value = x[i]
+		default:
+			return nil, nil
+		}
+	}
+	return value, nil
+}
+
+// parsePath returns the keys of a path of pathValue
+func parsePath(path string) ([]string, error) {
+	var keys []string
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+			continue
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if strings.HasPrefix(path[i+1:], `"`) {
+				// The quoted keys can have a "]"
+				end = strings.Index(path[i:], `"]`) + 1
+			}
+			if end <= 0 {
+				return nil, errors.Errorf("invalid path %q: unterminated [", path)
+			}
+			key := path[i+1 : i+end]
+			if strings.HasPrefix(key, `"`) {
+				unquoted, err := strconv.Unquote(key)
+				if err != nil {
+					return nil, errors.Errorf("invalid path %q: invalid key %s", path, key)
+				}
+				key = unquoted
+			}
+			keys = append(keys, key)
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			keys = append(keys, path[i:i+end])
+			i += end
+		}
+	}
+	return keys, nil
+}
+
+// humanizeBytes returns a human-readable size of a number of bytes, such as
+// "1.5MB"
+func humanizeBytes(v interface{}) (string, error) {
+	size, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	return units.HumanSizeWithPrecision(size, 3), nil
+}
+
+// formatDate formats a date with a layout of the time package, such as
+// "2006-01-02 15:04". The date is a time, a string in the RFC 3339 format, as
+// in the JSON output of the commands, or a number of seconds since the Unix
+// epoch.
+func formatDate(v interface{}, layout string) (string, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(layout), nil
+	case *time.Time:
+		return t.Format(layout), nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return "", errors.Errorf("invalid date %q", t)
+		}
+		return parsed.Format(layout), nil
+	}
+	seconds, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	return time.Unix(int64(seconds), 0).Format(layout), nil
+}
+
+// regexReplace replaces the matches of a regular expression in source with
+// the replacement, which can reference the submatches, such as "$1"
+func regexReplace(source, pattern, replacement string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(source, replacement), nil
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint16:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float32:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		return strconv.ParseFloat(n, 64)
+	}
+	return 0, errors.Errorf("invalid number %v of type %T", v, v)
+}
@@ -3,6 +3,7 @@ package templates
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -87,3 +88,106 @@ func TestParseTruncateFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePathFunction(t *testing.T) {
+	source := map[string]interface{}{
+		"Config": map[string]interface{}{
+			"Labels": map[string]string{"com.example.version": "1.0"},
+			"Env":    []string{"PATH=/bin", "DEBUG=1"},
+		},
+		"Size": 7410000,
+	}
+
+	testCases := []struct {
+		template string
+		expected string
+	}{
+		{template: `{{path . ".Config.Env[1]"}}`, expected: "DEBUG=1"},
+		{template: `{{path . "Config.Labels[\"com.example.version\"]"}}`, expected: "1.0"},
+		{template: `{{path . ".Size"}}`, expected: "7410000"},
+		{template: `{{json (path . ".Config.Env")}}`, expected: `["PATH=/bin","DEBUG=1"]`},
+		{template: `{{if path . ".Config.Missing"}}yes{{else}}no{{end}}`, expected: "no"},
+		{template: `{{if path . ".Config.Env[5]"}}yes{{else}}no{{end}}`, expected: "no"},
+	}
+
+	for _, tc := range testCases {
+		tm, err := Parse(tc.template)
+		assert.NilError(t, err)
+
+		var b bytes.Buffer
+		assert.NilError(t, tm.Execute(&b, source))
+		assert.Check(t, is.Equal(tc.expected, b.String()), tc.template)
+	}
+
+	tm, err := Parse(`{{path . ".Config[0"}}`)
+	assert.NilError(t, err)
+	assert.Check(t, is.ErrorContains(tm.Execute(new(bytes.Buffer), source), "unterminated ["))
+}
+
+func TestParseHumanizeBytesFunction(t *testing.T) {
+	tm, err := Parse(`{{humanizeBytes .Size}} {{humanizeBytes (path . ".Size")}}`)
+	assert.NilError(t, err)
+
+	var b bytes.Buffer
+	assert.NilError(t, tm.Execute(&b, map[string]int64{"Size": 1500000}))
+	assert.Check(t, is.Equal("1.5MB 1.5MB", b.String()))
+
+	tm, err = Parse(`{{humanizeBytes .}}`)
+	assert.NilError(t, err)
+	assert.Check(t, is.ErrorContains(tm.Execute(new(bytes.Buffer), true), "invalid number true of type bool"))
+}
+
+func TestParseFormatDateFunction(t *testing.T) {
+	created := time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		source   interface{}
+		expected string
+	}{
+		{source: created, expected: "2019-07-01 12:30"},
+		{source: "2019-07-01T12:30:00.123456Z", expected: "2019-07-01 12:30"},
+		{source: created.Unix(), expected: time.Unix(created.Unix(), 0).Format("2006-01-02 15:04")},
+	}
+
+	for _, tc := range testCases {
+		tm, err := Parse(`{{formatDate . "2006-01-02 15:04"}}`)
+		assert.NilError(t, err)
+
+		var b bytes.Buffer
+		assert.NilError(t, tm.Execute(&b, tc.source))
+		assert.Check(t, is.Equal(tc.expected, b.String()))
+	}
+}
+
+func TestParseRegexReplaceFunction(t *testing.T) {
+	tm, err := Parse(`{{regexReplace . "^/(\\w+)_\\d+$" "$1"}}`)
+	assert.NilError(t, err)
+
+	var b bytes.Buffer
+	assert.NilError(t, tm.Execute(&b, "/web_1"))
+	assert.Check(t, is.Equal("web", b.String()))
+
+	tm, err = Parse(`{{regexReplace . "(" ""}}`)
+	assert.NilError(t, err)
+	assert.Check(t, is.ErrorContains(tm.Execute(new(bytes.Buffer), "/web_1"), "missing closing )"))
+}
+
+func TestParseNamedTemplates(t *testing.T) {
+	SetNamedTemplates(map[string]string{
+		"short": `{{truncate .ID 4}}`,
+		"line":  `{{template "short" .}}: {{.Name}}`,
+	})
+	defer SetNamedTemplates(nil)
+
+	tm, err := Parse("template:line")
+	assert.NilError(t, err)
+	var b bytes.Buffer
+	assert.NilError(t, tm.Execute(&b, map[string]string{"ID": "abcdef", "Name": "web"}))
+	assert.Check(t, is.Equal("abcd: web", b.String()))
+
+	_, err = Parse("template:unknown")
+	assert.Check(t, is.Error(err, `template "unknown" is not defined`))
+
+	expanded, err := Expand("{{.ID}}")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("{{.ID}}", expanded))
+}